@@ -0,0 +1,250 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Agent Checkpoint Uploader
+ *
+ * Uploads the engine session directory as the agent-checkpoint artifact at a fixed interval
+ * while the engine runs (resume: true), so that a run whose runner is lost or killed before the
+ * final upload step still leaves a checkpoint to resume from.
+ *
+ * main() runs in a github-script step before the engine step and starts this file as a
+ * detached process, which inherits the Actions runtime token of the step. The process copies
+ * the session to a staging directory, redacts the copy, zips it and replaces the artifact
+ * through the Actions results service. The final upload step stops it (see pid file) and
+ * uploads the complete checkpoint with overwrite: true.
+ */
+
+const fs = require("fs");
+const os = require("os");
+const path = require("path");
+const crypto = require("crypto");
+const { spawn, execFileSync } = require("child_process");
+
+const PID_FILE = "/tmp/gh-aw/agent-checkpoint-uploader.pid";
+const LOG_FILE = "/tmp/gh-aw/agent-checkpoint-uploader.log";
+const ARTIFACT_SERVICE = "twirp/github.actions.results.api.v1.ArtifactService";
+const DEFAULT_INTERVAL_SECONDS = 300;
+
+// Same file types as the redaction step of the job
+const REDACTED_EXTENSIONS = [".txt", ".json", ".log", ".md", ".mdx", ".yml", ".jsonl"];
+
+/**
+ * Returns the workflow run and job backend IDs from the scope of the Actions runtime token
+ * @param {string} token - ACTIONS_RUNTIME_TOKEN
+ * @returns {{workflowRunBackendId: string, workflowJobRunBackendId: string}}
+ */
+function getBackendIds(token) {
+  const payload = token.split(".")[1];
+  if (!payload) {
+    throw new Error("invalid Actions runtime token");
+  }
+  const claims = JSON.parse(Buffer.from(payload, "base64url").toString("utf8"));
+  const scopes = typeof claims.scp === "string" ? claims.scp.split(" ") : [];
+  for (const scope of scopes) {
+    const parts = scope.split(":");
+    if (parts[0] === "Actions.Results" && parts.length === 3) {
+      return { workflowRunBackendId: parts[1], workflowJobRunBackendId: parts[2] };
+    }
+  }
+  throw new Error("Actions runtime token has no Actions.Results scope");
+}
+
+/**
+ * Calls a method of the artifact service of the Actions results service
+ * @param {string} method - Twirp method name
+ * @param {object} body - Request body
+ * @param {{resultsUrl: string, token: string}} runtime
+ * @returns {Promise<any>}
+ */
+async function callArtifactService(method, body, runtime) {
+  const url = new URL(`/${ARTIFACT_SERVICE}/${method}`, runtime.resultsUrl);
+  const response = await fetch(url, {
+    method: "POST",
+    headers: {
+      "Content-Type": "application/json",
+      Authorization: `Bearer ${runtime.token}`,
+      "User-Agent": "gh-aw-agent-checkpoint",
+    },
+    body: JSON.stringify(body),
+  });
+  if (!response.ok) {
+    const error = new Error(`${method} failed with status ${response.status}: ${await response.text()}`);
+    /** @type {any} */ (error).status = response.status;
+    throw error;
+  }
+  return response.json();
+}
+
+/**
+ * Returns a fingerprint of the files of a directory (path, size and modification time), or an
+ * empty string when the directory has no files
+ * @param {string} dir
+ * @returns {string}
+ */
+function fingerprintDirectory(dir) {
+  /** @type {string[]} */
+  const entries = [];
+  /** @param {string} current */
+  const walk = current => {
+    let items;
+    try {
+      items = fs.readdirSync(current, { withFileTypes: true });
+    } catch {
+      return;
+    }
+    for (const item of items) {
+      const fullPath = path.join(current, item.name);
+      if (item.isDirectory()) {
+        walk(fullPath);
+      } else if (item.isFile()) {
+        const stat = fs.statSync(fullPath);
+        entries.push(`${path.relative(dir, fullPath)}:${stat.size}:${stat.mtimeMs}`);
+      }
+    }
+  };
+  walk(dir);
+  if (entries.length === 0) {
+    return "";
+  }
+  return crypto.createHash("sha256").update(entries.sort().join("\n")).digest("hex");
+}
+
+/**
+ * Copies the session directory to a staging directory and redacts the copy, so that the
+ * uploaded checkpoint never contains secrets even before the redaction step of the job runs
+ * @param {string} sessionDir
+ * @param {string} stagingDir
+ * @param {string[]} secretValues
+ */
+function stageSession(sessionDir, stagingDir, secretValues) {
+  const { redactSecrets, redactBuiltInPatterns } = require("./redact_secrets.cjs");
+  fs.rmSync(stagingDir, { recursive: true, force: true });
+  fs.cpSync(sessionDir, stagingDir, { recursive: true });
+
+  /** @param {string} current */
+  const walk = current => {
+    for (const item of fs.readdirSync(current, { withFileTypes: true })) {
+      const fullPath = path.join(current, item.name);
+      if (item.isDirectory()) {
+        walk(fullPath);
+      } else if (item.isFile() && REDACTED_EXTENSIONS.includes(path.extname(item.name).toLowerCase())) {
+        const content = fs.readFileSync(fullPath, "utf8");
+        const redacted = redactSecrets(redactBuiltInPatterns(content).content, secretValues).content;
+        if (redacted !== content) {
+          fs.writeFileSync(fullPath, redacted, "utf8");
+        }
+      }
+    }
+  };
+  walk(stagingDir);
+}
+
+/**
+ * Replaces the checkpoint artifact of the job with a zip of the staging directory
+ * @param {string} name - Artifact name
+ * @param {string} stagingDir
+ * @param {{resultsUrl: string, token: string}} runtime
+ */
+async function uploadCheckpoint(name, stagingDir, runtime) {
+  const zipPath = `${stagingDir}.zip`;
+  fs.rmSync(zipPath, { force: true });
+  execFileSync("zip", ["-qr", zipPath, "."], { cwd: stagingDir });
+  const data = fs.readFileSync(zipPath);
+  const hash = crypto.createHash("sha256").update(data).digest("hex");
+
+  const ids = getBackendIds(runtime.token);
+  try {
+    await callArtifactService("DeleteArtifact", { ...ids, name }, runtime);
+  } catch (error) {
+    // The first upload of the run has no artifact to replace
+    if (/** @type {any} */ (error).status !== 404) {
+      throw error;
+    }
+  }
+
+  const created = await callArtifactService("CreateArtifact", { ...ids, name, version: 4 }, runtime);
+  if (!created.ok || !created.signedUploadUrl) {
+    throw new Error("CreateArtifact did not return an upload URL");
+  }
+  const upload = await fetch(created.signedUploadUrl, {
+    method: "PUT",
+    headers: { "x-ms-blob-type": "BlockBlob", "Content-Type": "application/zip" },
+    body: data,
+  });
+  if (!upload.ok) {
+    throw new Error(`checkpoint upload failed with status ${upload.status}`);
+  }
+  const finalized = await callArtifactService("FinalizeArtifact", { ...ids, name, size: String(data.length), hash: `sha256:${hash}` }, runtime);
+  if (!finalized.ok) {
+    throw new Error("FinalizeArtifact failed");
+  }
+  return data.length;
+}
+
+/**
+ * Uploads the session at the configured interval while it changes. Runs in the detached process
+ * until the final upload step stops it or the job ends.
+ */
+async function runUploadLoop() {
+  const sessionDir = process.env.GH_AW_SESSION_DIR || "";
+  const name = process.env.GH_AW_CHECKPOINT_ARTIFACT || "agent-checkpoint";
+  const intervalSeconds = parseInt(process.env.GH_AW_CHECKPOINT_INTERVAL || "", 10) || DEFAULT_INTERVAL_SECONDS;
+  const runtime = { resultsUrl: process.env.ACTIONS_RESULTS_URL || "", token: process.env.ACTIONS_RUNTIME_TOKEN || "" };
+  const secretValues = Object.keys(process.env)
+    .filter(key => key.startsWith("SECRET_"))
+    .map(key => (process.env[key] || "").trim())
+    .filter(value => value !== "");
+  const stagingDir = path.join(os.tmpdir(), "gh-aw-agent-checkpoint");
+
+  // redact_secrets.cjs logs through the github-script core object
+  /** @type {any} */ (global).core = { info: console.log, warning: console.log };
+
+  let lastFingerprint = "";
+  for (;;) {
+    await new Promise(resolve => setTimeout(resolve, intervalSeconds * 1000));
+    const fingerprint = fingerprintDirectory(sessionDir);
+    if (fingerprint === "" || fingerprint === lastFingerprint) {
+      continue;
+    }
+    try {
+      stageSession(sessionDir, stagingDir, secretValues);
+      const size = await uploadCheckpoint(name, stagingDir, runtime);
+      lastFingerprint = fingerprint;
+      console.log(`${new Date().toISOString()} uploaded checkpoint ${name} (${size} bytes)`);
+    } catch (error) {
+      console.log(`${new Date().toISOString()} checkpoint upload failed: ${error instanceof Error ? error.message : String(error)}`);
+    }
+  }
+}
+
+/**
+ * Starts the upload loop as a detached process that outlives the step
+ */
+async function main() {
+  if (!process.env.ACTIONS_RUNTIME_TOKEN || !process.env.ACTIONS_RESULTS_URL) {
+    core.warning("Actions runtime token not available, the agent checkpoint is only uploaded after the engine stops");
+    return;
+  }
+  if (!process.env.GH_AW_SESSION_DIR) {
+    core.setFailed("Configuration error: GH_AW_SESSION_DIR not specified.");
+    return;
+  }
+
+  fs.mkdirSync(path.dirname(LOG_FILE), { recursive: true });
+  const log = fs.openSync(LOG_FILE, "a");
+  const child = spawn(process.execPath, [__filename], {
+    detached: true,
+    stdio: ["ignore", log, log],
+    env: { ...process.env, GH_AW_CHECKPOINT_UPLOADER: "loop" },
+  });
+  child.unref();
+  fs.writeFileSync(PID_FILE, String(child.pid));
+  core.info(`Started agent checkpoint uploader (pid ${child.pid}, every ${process.env.GH_AW_CHECKPOINT_INTERVAL || DEFAULT_INTERVAL_SECONDS}s)`);
+}
+
+if (require.main === module && process.env.GH_AW_CHECKPOINT_UPLOADER === "loop") {
+  runUploadLoop();
+}
+
+module.exports = { main, getBackendIds, fingerprintDirectory, stageSession, uploadCheckpoint };
@@ -0,0 +1,315 @@
+// @ts-check
+/// <reference types="@actions/github-script" />
+
+/**
+ * Egress Report Module
+ *
+ * Summarizes the network egress of the agent from the firewall logs: allowed and
+ * blocked domains and request counts. The report is written to the step
+ * summary and to /tmp/gh-aw/egress-report.json, which is uploaded as the egress-report
+ * artifact so safe outputs can add the blocked requests to the footer of pull requests,
+ * issues and comments.
+ *
+ * Blocked requests to domains of the alert list (GH_AW_EGRESS_ALERT) are reported
+ * as warnings, or fail the step when GH_AW_EGRESS_ON_ALERT is "fail".
+ */
+
+const fs = require("fs");
+const path = require("path");
+const { parseFirewallLogLine, isRequestBlocked, extractAndSanitizeDomain } = require("./firewall_blocked_domains.cjs");
+
+const FIREWALL_LOGS_DIR = "/tmp/gh-aw/sandbox/firewall/logs/";
+const EGRESS_REPORT_PATH = "/tmp/gh-aw/egress-report.json";
+
+/** Maximum number of blocked domains listed in the footer of pull requests and comments */
+const MAX_FOOTER_DOMAINS = 20;
+
+/**
+ * @typedef {Object} DomainEgress
+ * @property {number} allowed - Allowed requests
+ * @property {number} blocked - Blocked requests
+ */
+
+/**
+ * @typedef {Object} EgressAlert
+ * @property {string} domain - Blocked domain
+ * @property {string} pattern - Alert pattern matched by the domain
+ * @property {number} requests - Blocked requests to the domain
+ */
+
+/**
+ * @typedef {Object} EgressReport
+ * @property {number} total_requests
+ * @property {number} allowed_requests
+ * @property {number} blocked_requests
+ * @property {string[]} allowed_domains
+ * @property {string[]} blocked_domains
+ * @property {Record<string, DomainEgress>} requests_by_domain
+ * @property {EgressAlert[]} alerts
+ */
+
+/**
+ * Normalizes an alert pattern to the domain it matches
+ * @param {string} pattern - Domain, wildcard domain or URL
+ * @returns {string} Lower case base domain
+ */
+function normalizeAlertPattern(pattern) {
+  return pattern
+    .trim()
+    .toLowerCase()
+    .replace(/^https?:\/\//, "")
+    .replace(/^\*\./, "")
+    .replace(/[/:].*$/, "");
+}
+
+/**
+ * Returns the alert pattern matched by a domain. Patterns match the domain and its subdomains.
+ * @param {string} domain - Domain of a request
+ * @param {string[]} patterns - Alert patterns
+ * @returns {string|undefined} Matched pattern
+ */
+function matchAlertPattern(domain, patterns) {
+  const lower = domain.toLowerCase();
+  return patterns.find(pattern => {
+    const base = normalizeAlertPattern(pattern);
+    return base !== "" && (lower === base || lower.endsWith("." + base));
+  });
+}
+
+/**
+ * Builds the egress report from firewall log contents
+ * @param {string[]} contents - Contents of the firewall log files
+ * @param {string[]} alertPatterns - Domains whose blocked requests raise an alert
+ * @returns {EgressReport} Egress report
+ */
+function buildEgressReport(contents, alertPatterns) {
+  /** @type {Record<string, DomainEgress>} */
+  const requestsByDomain = {};
+  let totalRequests = 0;
+  let allowedRequests = 0;
+  let blockedRequests = 0;
+
+  for (const content of contents) {
+    for (const line of content.split("\n")) {
+      const entry = parseFirewallLogLine(line);
+      if (!entry) {
+        continue;
+      }
+      totalRequests++;
+      const blocked = isRequestBlocked(entry.decision, entry.status);
+      if (blocked) {
+        blockedRequests++;
+      } else {
+        allowedRequests++;
+      }
+
+      // Skip placeholder domains and proxy error codes
+      const domain = extractAndSanitizeDomain(entry.domain);
+      if (!domain || domain === "-" || domain.startsWith("error:")) {
+        continue;
+      }
+      const stats = requestsByDomain[domain] || (requestsByDomain[domain] = { allowed: 0, blocked: 0 });
+      if (blocked) {
+        stats.blocked++;
+      } else {
+        stats.allowed++;
+      }
+    }
+  }
+
+  const domains = Object.keys(requestsByDomain).sort();
+  /** @type {EgressAlert[]} */
+  const alerts = [];
+  for (const domain of domains) {
+    const stats = requestsByDomain[domain];
+    const pattern = stats.blocked > 0 ? matchAlertPattern(domain, alertPatterns) : undefined;
+    if (pattern) {
+      alerts.push({ domain, pattern, requests: stats.blocked });
+    }
+  }
+
+  return {
+    total_requests: totalRequests,
+    allowed_requests: allowedRequests,
+    blocked_requests: blockedRequests,
+    allowed_domains: domains.filter(domain => requestsByDomain[domain].allowed > 0),
+    blocked_domains: domains.filter(domain => requestsByDomain[domain].blocked > 0),
+    requests_by_domain: requestsByDomain,
+    alerts,
+  };
+}
+
+/**
+ * Returns the one-line summary of the report
+ * @param {EgressReport} report - Egress report
+ * @returns {string} Summary line
+ */
+function formatEgressHeadline(report) {
+  const domainCount = Object.keys(report.requests_by_domain).length;
+  let headline = `${report.total_requests} request${report.total_requests !== 1 ? "s" : ""} | `;
+  headline += `${report.allowed_requests} allowed | ${report.blocked_requests} blocked | `;
+  headline += `${domainCount} domain${domainCount !== 1 ? "s" : ""}`;
+  return headline;
+}
+
+/**
+ * Returns the markdown table of the domains of the report
+ * @param {EgressReport} report - Egress report
+ * @param {number} [limit] - Maximum number of domains, blocked domains first
+ * @param {boolean} [blockedOnly] - Whether to list only the domains with blocked requests
+ * @returns {string} Markdown table
+ */
+function formatEgressTable(report, limit, blockedOnly) {
+  const domains = Object.keys(report.requests_by_domain)
+    .filter(domain => !blockedOnly || report.requests_by_domain[domain].blocked > 0)
+    .sort((a, b) => {
+      const statsA = report.requests_by_domain[a];
+      const statsB = report.requests_by_domain[b];
+      return statsB.blocked - statsA.blocked || a.localeCompare(b);
+    });
+  const listed = limit !== undefined ? domains.slice(0, limit) : domains;
+
+  let table = "| Domain | Allowed | Blocked |\n";
+  table += "|--------|---------|---------|\n";
+  for (const domain of listed) {
+    const stats = report.requests_by_domain[domain];
+    table += `| ${domain} | ${stats.allowed} | ${stats.blocked} |\n`;
+  }
+  if (listed.length < domains.length) {
+    table += `\n${domains.length - listed.length} more ${blockedOnly ? "blocked " : ""}domain${domains.length - listed.length !== 1 ? "s" : ""} in the step summary of the run.\n`;
+  }
+  return table;
+}
+
+/**
+ * Returns the alert lines of the report
+ * @param {EgressReport} report - Egress report
+ * @param {string} prefix - Prefix of each line
+ * @returns {string} Markdown alert lines
+ */
+function formatEgressAlerts(report, prefix) {
+  let lines = `${prefix}Blocked requests matched the egress alert list:\n`;
+  for (const alert of report.alerts) {
+    lines += `${prefix}- \`${alert.domain}\` (${alert.requests} request${alert.requests !== 1 ? "s" : ""}, matches \`${alert.pattern}\`)\n`;
+  }
+  return lines;
+}
+
+/**
+ * Generates the step summary of the report
+ * @param {EgressReport} report - Egress report
+ * @returns {string} Markdown summary
+ */
+function generateEgressSummary(report) {
+  let summary = "";
+  if (report.alerts.length > 0) {
+    summary += "> [!CAUTION]\n";
+    summary += formatEgressAlerts(report, "> ");
+    summary += "\n";
+  }
+
+  summary += "<details>\n";
+  summary += `<summary>Egress report: ${formatEgressHeadline(report)}</summary>\n\n`;
+  if (Object.keys(report.requests_by_domain).length > 0) {
+    summary += formatEgressTable(report);
+  } else {
+    summary += "No network activity detected.\n";
+  }
+  summary += "\n</details>\n\n";
+  return summary;
+}
+
+/**
+ * Generates the footer section of pull requests and comments for the report. Only runs with
+ * blocked requests get the section, listing the blocked domains and the alerts; the full table
+ * stays in the step summary.
+ * @param {EgressReport|null} report - Egress report
+ * @returns {string} Footer section, or an empty string when no request was blocked
+ */
+function generateEgressReportSection(report) {
+  if (!report || report.blocked_requests === 0) {
+    return "";
+  }
+
+  let section = "\n\n> [!WARNING]\n";
+  const prefix = "> ";
+  section += `${prefix}<details>\n`;
+  section += `${prefix}<summary>🌐 Network egress: ${formatEgressHeadline(report)}</summary>\n`;
+  section += `${prefix.trimEnd()}\n`;
+  if (report.alerts.length > 0) {
+    section += formatEgressAlerts(report, prefix);
+    section += `${prefix.trimEnd()}\n`;
+  }
+  for (const line of formatEgressTable(report, MAX_FOOTER_DOMAINS, true).trimEnd().split("\n")) {
+    section += `${prefix}${line}\n`;
+  }
+  section += `${prefix.trimEnd()}\n`;
+  section += `${prefix}The allowed domains are listed in the step summary of the run.\n`;
+  section += `${prefix.trimEnd()}\n`;
+  section += `${prefix}</details>\n`;
+  return section;
+}
+
+/**
+ * Reads the egress report written by the agent job
+ * @param {string} [reportPath] - Path of the report. Defaults to /tmp/gh-aw/egress-report.json
+ * @returns {EgressReport|null} Egress report, or null when it is missing or invalid
+ */
+function readEgressReport(reportPath) {
+  const file = reportPath || EGRESS_REPORT_PATH;
+  if (!fs.existsSync(file)) {
+    return null;
+  }
+  try {
+    return JSON.parse(fs.readFileSync(file, "utf8"));
+  } catch (error) {
+    return null;
+  }
+}
+
+async function main() {
+  if (!fs.existsSync(FIREWALL_LOGS_DIR)) {
+    core.info(`No firewall logs directory found at: ${FIREWALL_LOGS_DIR}`);
+    return;
+  }
+
+  const files = fs.readdirSync(FIREWALL_LOGS_DIR).filter(file => file.endsWith(".log"));
+  const contents = [];
+  for (const file of files) {
+    try {
+      contents.push(fs.readFileSync(path.join(FIREWALL_LOGS_DIR, file), "utf8"));
+    } catch (error) {
+      core.warning(`Failed to read firewall log ${file}: ${error instanceof Error ? error.message : String(error)}`);
+    }
+  }
+
+  const alertPatterns = (process.env.GH_AW_EGRESS_ALERT || "")
+    .split("\n")
+    .map(pattern => pattern.trim())
+    .filter(Boolean);
+  const report = buildEgressReport(contents, alertPatterns);
+  core.info(`Egress report: ${formatEgressHeadline(report)}`);
+
+  fs.writeFileSync(EGRESS_REPORT_PATH, JSON.stringify(report, null, 2));
+  await core.summary.addRaw(generateEgressSummary(report)).write();
+
+  if (report.alerts.length === 0) {
+    return;
+  }
+  const message = `Blocked requests matched the egress alert list: ${report.alerts.map(alert => alert.domain).join(", ")}`;
+  if (process.env.GH_AW_EGRESS_ON_ALERT === "fail") {
+    core.setFailed(message);
+  } else {
+    core.warning(message);
+  }
+}
+
+module.exports = {
+  EGRESS_REPORT_PATH,
+  matchAlertPattern,
+  buildEgressReport,
+  generateEgressSummary,
+  generateEgressReportSection,
+  readEgressReport,
+  main,
+};
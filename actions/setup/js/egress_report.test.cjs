@@ -0,0 +1,98 @@
+// @ts-check
+
+import { describe, it, expect } from "vitest";
+import { matchAlertPattern, buildEgressReport, generateEgressSummary, generateEgressReportSection } from "./egress_report.cjs";
+
+const ALLOWED_LINE = '1761332530.474 172.30.0.20:35288 api.github.com:443 140.82.112.22:443 1.1 CONNECT 200 TCP_TUNNEL:HIER_DIRECT api.github.com:443 "-"';
+const SECOND_ALLOWED_LINE = '1761332530.475 172.30.0.20:35289 api.github.com:443 140.82.112.22:443 1.1 CONNECT 200 TCP_TUNNEL:HIER_DIRECT api.github.com:443 "-"';
+const BLOCKED_LINE = '1761332531.123 172.30.0.20:35290 paste.pastebin.com:443 1.2.3.4:443 1.1 CONNECT 403 NONE_NONE:HIER_NONE paste.pastebin.com:443 "curl/8.5.0"';
+const PLACEHOLDER_LINE = '1761332531.124 - - - - - 0 NONE_NONE:HIER_NONE - "-"';
+
+describe("matchAlertPattern", () => {
+  it("should match domains and their subdomains", () => {
+    expect(matchAlertPattern("pastebin.com", ["pastebin.com"])).toBe("pastebin.com");
+    expect(matchAlertPattern("paste.pastebin.com", ["pastebin.com"])).toBe("pastebin.com");
+    expect(matchAlertPattern("paste.pastebin.com", ["*.pastebin.com"])).toBe("*.pastebin.com");
+    expect(matchAlertPattern("Tunnel.NGROK.io", ["https://*.ngrok.io"])).toBe("https://*.ngrok.io");
+  });
+
+  it("should not match other domains", () => {
+    expect(matchAlertPattern("notpastebin.com", ["pastebin.com"])).toBeUndefined();
+    expect(matchAlertPattern("api.github.com", [])).toBeUndefined();
+  });
+});
+
+describe("buildEgressReport", () => {
+  it("should count requests by domain", () => {
+    const report = buildEgressReport([[ALLOWED_LINE, SECOND_ALLOWED_LINE].join("\n"), [BLOCKED_LINE, PLACEHOLDER_LINE, "not a log line"].join("\n")], []);
+
+    expect(report.total_requests).toBe(4);
+    expect(report.allowed_requests).toBe(2);
+    expect(report.blocked_requests).toBe(2);
+    expect(report.allowed_domains).toEqual(["api.github.com"]);
+    expect(report.blocked_domains).toEqual(["paste.pastebin.com"]);
+    expect(report.requests_by_domain).toEqual({
+      "api.github.com": { allowed: 2, blocked: 0 },
+      "paste.pastebin.com": { allowed: 0, blocked: 1 },
+    });
+    expect(report.alerts).toEqual([]);
+  });
+
+  it("should raise alerts for blocked requests to alert domains only", () => {
+    const report = buildEgressReport([[ALLOWED_LINE, BLOCKED_LINE].join("\n")], ["pastebin.com", "github.com"]);
+
+    expect(report.alerts).toEqual([{ domain: "paste.pastebin.com", pattern: "pastebin.com", requests: 1 }]);
+  });
+});
+
+describe("generateEgressSummary", () => {
+  it("should list domains with blocked domains first", () => {
+    const summary = generateEgressSummary(buildEgressReport([[ALLOWED_LINE, BLOCKED_LINE].join("\n")], []));
+
+    expect(summary).toContain("<summary>Egress report: 2 requests | 1 allowed | 1 blocked | 2 domains</summary>");
+    expect(summary.indexOf("| paste.pastebin.com | 0 | 1 |")).toBeLessThan(summary.indexOf("| api.github.com | 1 | 0 |"));
+    expect(summary).not.toContain("[!CAUTION]");
+  });
+
+  it("should highlight alerts", () => {
+    const summary = generateEgressSummary(buildEgressReport([BLOCKED_LINE], ["*.pastebin.com"]));
+
+    expect(summary).toContain("> [!CAUTION]");
+    expect(summary).toContain("> - `paste.pastebin.com` (1 request, matches `*.pastebin.com`)");
+  });
+
+  it("should report the absence of network activity", () => {
+    expect(generateEgressSummary(buildEgressReport([], []))).toContain("No network activity detected.");
+  });
+});
+
+describe("generateEgressReportSection", () => {
+  it("should return an empty section without network activity", () => {
+    expect(generateEgressReportSection(null)).toBe("");
+    expect(generateEgressReportSection(buildEgressReport([], []))).toBe("");
+  });
+
+  it("should return an empty section when no request was blocked", () => {
+    expect(generateEgressReportSection(buildEgressReport([ALLOWED_LINE], []))).toBe("");
+  });
+
+  it("should list the blocked domains and alerts in a warning when requests were blocked", () => {
+    const section = generateEgressReportSection(buildEgressReport([[ALLOWED_LINE, BLOCKED_LINE].join("\n")], ["pastebin.com"]));
+
+    expect(section).toContain("> [!WARNING]");
+    expect(section).toContain("<summary>🌐 Network egress: 2 requests | 1 allowed | 1 blocked | 2 domains</summary>");
+    expect(section).toContain("> - `paste.pastebin.com` (1 request, matches `pastebin.com`)");
+    expect(section).toContain("> | paste.pastebin.com | 0 | 1 |");
+    expect(section).not.toContain("| api.github.com |");
+    expect(section).toContain("> The allowed domains are listed in the step summary of the run.");
+    expect(section).toContain("> </details>");
+  });
+
+  it("should limit the blocked domains listed in the footer", () => {
+    const lines = Array.from({ length: 25 }, (_, i) => BLOCKED_LINE.replace(/paste\.pastebin\.com/g, `host${i}.example.com`));
+    const section = generateEgressReportSection(buildEgressReport([lines.join("\n")], []));
+
+    expect(section).toContain("5 more blocked domains in the step summary of the run.");
+    expect(section).not.toContain("host9.example.com");
+  });
+});
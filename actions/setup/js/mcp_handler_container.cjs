@@ -0,0 +1,155 @@
+// @ts-check
+
+/**
+ * Container Handler for Safe-Inputs
+ *
+ * This module provides a handler for running safe-inputs tools backed by a container image.
+ * Each call runs in a fresh container that is removed afterwards, with no network access
+ * unless the tool allows it.
+ */
+
+const path = require("path");
+const crypto = require("crypto");
+const { execFile } = require("child_process");
+
+/**
+ * @typedef {Object} ContainerMount
+ * @property {string} source - Host path (relative paths are resolved against the workspace)
+ * @property {string} target - Absolute path inside the container
+ * @property {boolean} [readOnly] - Whether the mount is read-only
+ */
+
+/**
+ * @typedef {Object} ContainerConfig
+ * @property {string} image - Image pinned by digest (image@sha256:...)
+ * @property {string} [entrypoint] - Entrypoint override
+ * @property {string[]} [args] - Arguments passed to the container
+ * @property {boolean} [network] - Whether the container has network access
+ * @property {ContainerMount[]} [mounts] - Host paths mounted into the container
+ */
+
+/**
+ * Build the docker run arguments for a single tool call.
+ * These match the arguments built by the compiler (BuildSafeInputContainerRunArgs).
+ *
+ * @param {ContainerConfig} container - Container configuration from tools.json
+ * @param {string[]} envNames - Names of environment variables forwarded to the container
+ * @param {string} workspace - Directory used to resolve relative mount sources
+ * @param {string} name - Name of the container
+ * @returns {string[]} Arguments for the docker command
+ */
+function buildDockerRunArgs(container, envNames, workspace, name) {
+  const args = ["run", "--rm", "-i", "--name", name];
+  if (!container.network) {
+    args.push("--network", "none");
+  }
+  args.push("--cap-drop", "ALL", "--security-opt", "no-new-privileges");
+
+  for (const mount of container.mounts || []) {
+    const source = path.isAbsolute(mount.source) ? mount.source : path.join(workspace, mount.source);
+    args.push("-v", `${source}:${mount.target}${mount.readOnly ? ":ro" : ""}`);
+  }
+
+  for (const envName of [...envNames].sort()) {
+    args.push("-e", envName);
+  }
+
+  if (container.entrypoint) {
+    args.push("--entrypoint", container.entrypoint);
+  }
+  args.push(container.image, ...(container.args || []));
+  return args;
+}
+
+/**
+ * Create a container handler function that runs the tool image once per call.
+ * - Inputs are passed as JSON via stdin
+ * - Outputs are read from stdout (JSON format expected)
+ * - The container is force-removed when the call times out
+ *
+ * @param {Object} server - The MCP server instance for logging
+ * @param {string} toolName - Name of the tool for logging purposes
+ * @param {ContainerConfig} container - Container configuration from tools.json
+ * @param {string[]} envNames - Names of environment variables forwarded to the container
+ * @param {number} [timeoutSeconds=60] - Timeout in seconds for the container run
+ * @returns {Function} Async handler function that runs the container
+ */
+function createContainerHandler(server, toolName, container, envNames, timeoutSeconds = 60) {
+  return async args => {
+    const name = `gh-aw-safe-input-${toolName.replace(/[^a-zA-Z0-9_.-]/g, "-")}-${crypto.randomBytes(4).toString("hex")}`;
+    const workspace = process.env.GITHUB_WORKSPACE || process.cwd();
+    const dockerArgs = buildDockerRunArgs(container, envNames, workspace, name);
+
+    server.debug(`  [${toolName}] Invoking container handler: ${container.image}`);
+    server.debug(`  [${toolName}] Container handler args: ${JSON.stringify(args)}`);
+    server.debug(`  [${toolName}] Timeout: ${timeoutSeconds}s`);
+
+    const inputJson = JSON.stringify(args || {});
+
+    return new Promise((resolve, reject) => {
+      server.debug(`  [${toolName}] Running container ${name}...`);
+
+      const child = execFile(
+        "docker",
+        dockerArgs,
+        {
+          env: process.env,
+          cwd: workspace,
+          timeout: timeoutSeconds * 1000, // Convert to milliseconds
+          maxBuffer: 10 * 1024 * 1024, // 10MB buffer
+        },
+        (error, stdout, stderr) => {
+          if (stdout) {
+            server.debug(`  [${toolName}] stdout: ${stdout.substring(0, 500)}${stdout.length > 500 ? "..." : ""}`);
+          }
+          if (stderr) {
+            server.debug(`  [${toolName}] stderr: ${stderr.substring(0, 500)}${stderr.length > 500 ? "..." : ""}`);
+          }
+
+          if (error) {
+            server.debugError(`  [${toolName}] Container error: `, error);
+            // Killing the docker client does not stop the container, so remove it explicitly
+            if (error.killed) {
+              execFile("docker", ["rm", "-f", name], () => {});
+            }
+            reject(error);
+            return;
+          }
+
+          let result;
+          try {
+            if (stdout && stdout.trim()) {
+              result = JSON.parse(stdout.trim());
+            } else {
+              result = { stdout: stdout || "", stderr: stderr || "" };
+            }
+          } catch {
+            server.debug(`  [${toolName}] Output is not JSON, returning as text`);
+            result = { stdout: stdout || "", stderr: stderr || "" };
+          }
+
+          server.debug(`  [${toolName}] Container handler completed successfully`);
+
+          resolve({
+            content: [
+              {
+                type: "text",
+                text: JSON.stringify(result),
+              },
+            ],
+          });
+        }
+      );
+
+      if (child.stdin) {
+        child.stdin.write(inputJson);
+        child.stdin.end();
+      }
+    });
+  };
+}
+
+module.exports = {
+  buildDockerRunArgs,
+  createContainerHandler,
+};
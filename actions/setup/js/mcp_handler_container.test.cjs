@@ -0,0 +1,53 @@
+// @ts-check
+
+import { describe, it, expect } from "vitest";
+import { buildDockerRunArgs } from "./mcp_handler_container.cjs";
+
+describe("buildDockerRunArgs", () => {
+  const image = "ghcr.io/example/tool@sha256:" + "a".repeat(64);
+
+  it("should isolate the container by default", () => {
+    const args = buildDockerRunArgs({ image }, [], "/workspace", "tool-1");
+
+    expect(args).toEqual(["run", "--rm", "-i", "--name", "tool-1", "--network", "none", "--cap-drop", "ALL", "--security-opt", "no-new-privileges", image]);
+  });
+
+  it("should add mounts, environment, entrypoint and args", () => {
+    const container = {
+      image,
+      entrypoint: "/bin/tool",
+      args: ["--json"],
+      network: true,
+      mounts: [
+        { source: "data", target: "/data", readOnly: true },
+        { source: "/tmp/out", target: "/out", readOnly: false },
+      ],
+    };
+
+    const args = buildDockerRunArgs(container, ["TOKEN", "API_URL"], "/workspace", "tool-2");
+
+    expect(args).toEqual([
+      "run",
+      "--rm",
+      "-i",
+      "--name",
+      "tool-2",
+      "--cap-drop",
+      "ALL",
+      "--security-opt",
+      "no-new-privileges",
+      "-v",
+      "/workspace/data:/data:ro",
+      "-v",
+      "/tmp/out:/out",
+      "-e",
+      "API_URL",
+      "-e",
+      "TOKEN",
+      "--entrypoint",
+      "/bin/tool",
+      image,
+      "--json",
+    ]);
+  });
+});
@@ -58,13 +58,17 @@ class MCPServer {
    * @param {string} description - Tool description
    * @param {Object} inputSchema - JSON Schema for tool input
    * @param {Function} handler - Async function that handles tool calls
+   * @param {Object} [options] - Optional tool settings
+   * @param {Object} [options.outputSchema] - JSON Schema for the structured tool result
    */
-  tool(name, description, inputSchema, handler) {
+  tool(name, description, inputSchema, handler, options = {}) {
+    const outputSchema = options.outputSchema ? { outputSchema: options.outputSchema } : {};
     this.tools.set(name, {
       name,
       description,
       inputSchema,
       handler,
+      ...outputSchema,
     });
     // Also register with the core server
     registerTool(this._coreServer, {
@@ -72,6 +76,7 @@ class MCPServer {
       description,
       inputSchema,
       handler,
+      ...outputSchema,
     });
   }
 
@@ -30,7 +30,7 @@ const fs = require("fs");
 const path = require("path");
 
 const { ReadBuffer } = require("./read_buffer.cjs");
-const { validateRequiredFields } = require("./safe_inputs_validation.cjs");
+const { validateRequiredFields, validateInputSchema } = require("./safe_inputs_validation.cjs");
 const { getErrorMessage } = require("./error_helpers.cjs");
 const { generateEnhancedErrorMessage } = require("./mcp_enhanced_errors.cjs");
 
@@ -47,6 +47,7 @@ const encoder = new TextEncoder();
  * @property {string} name - Tool name
  * @property {string} description - Tool description
  * @property {Object} inputSchema - JSON Schema for tool inputs
+ * @property {Object} [outputSchema] - JSON Schema for the structured tool result
  * @property {Function} [handler] - Tool handler function
  * @property {string} [handlerPath] - Optional file path to handler module (original path from config)
  * @property {number} [timeout] - Timeout in seconds for tool execution (default: 60)
@@ -294,6 +295,12 @@ function createWrappedHandler(server, toolName, handlerFn) {
  *   - Outputs are read from stdout (JSON format expected)
  *   - Executed using 'go run' command
  *
+ * For container tools (tool.container instead of a handler path):
+ *   - Each call runs the image in a fresh container removed afterwards
+ *   - Inputs are passed as JSON via stdin
+ *   - Outputs are read from stdout (JSON format expected)
+ *   - No network access unless the tool enables it
+ *
  * SECURITY NOTE: Handler paths are loaded from tools.json configuration file,
  * which should be controlled by the server administrator. When basePath is provided,
  * relative paths are resolved within it, preventing directory traversal outside
@@ -318,6 +325,17 @@ function loadToolHandlers(server, tools, basePath) {
   for (const tool of tools) {
     const toolName = tool.name || "(unnamed)";
 
+    // Container tools run their image directly and have no handler file
+    if (tool.container && tool.container.image) {
+      const { createContainerHandler } = require("./mcp_handler_container.cjs");
+      const timeout = tool.timeout || 60; // Default to 60 seconds if not specified
+      tool.handler = createContainerHandler(server, toolName, tool.container, Object.keys(tool.env || {}), timeout);
+
+      loadedCount++;
+      server.debug(`  [${toolName}] Container handler created successfully for ${tool.container.image} with timeout: ${timeout}s`);
+      continue;
+    }
+
     // Check if tool has a handler path specified
     if (!tool.handler) {
       server.debug(`  [${toolName}] No handler path specified, skipping handler load`);
@@ -452,6 +470,29 @@ function loadToolHandlers(server, tools, basePath) {
   return tools;
 }
 
+/**
+ * Build the tools/call result for a tool.
+ * Tools declaring an output schema also return their JSON result as structured content.
+ * @param {Tool} tool - The tool that was called
+ * @param {Array<any>} content - The content returned by the handler
+ * @returns {Object} The tools/call result
+ */
+function buildToolCallResult(tool, content) {
+  /** @type {{content: Array<any>, isError: boolean, structuredContent?: any}} */
+  const result = { content, isError: false };
+  if (tool.outputSchema && content.length > 0 && content[0].type === "text") {
+    try {
+      const parsed = JSON.parse(content[0].text);
+      if (parsed && typeof parsed === "object" && !Array.isArray(parsed)) {
+        result.structuredContent = parsed;
+      }
+    } catch {
+      // Not JSON; only the text content is returned
+    }
+  }
+  return result;
+}
+
 /**
  * Register a tool with the server
  * @param {MCPServer} server - The MCP server instance
@@ -579,6 +620,9 @@ async function handleRequest(server, request, defaultHandler) {
           description: tool.description,
           inputSchema: tool.inputSchema,
         };
+        if (tool.outputSchema) {
+          toolDef.outputSchema = tool.outputSchema;
+        }
         list.push(toolDef);
       });
       result = { tools: list };
@@ -628,10 +672,18 @@ async function handleRequest(server, request, defaultHandler) {
         };
       }
 
+      const schemaErrors = validateInputSchema(args, tool.inputSchema);
+      if (schemaErrors.length) {
+        throw {
+          code: -32602,
+          message: `Invalid arguments for tool '${name}': ${schemaErrors.join("; ")}`,
+        };
+      }
+
       // Call handler and await the result (supports both sync and async handlers)
       const handlerResult = await Promise.resolve(handler(args));
       const content = handlerResult && handlerResult.content ? handlerResult.content : [];
-      result = { content, isError: false };
+      result = buildToolCallResult(tool, content);
     } else if (/^notifications\//.test(method)) {
       // Notifications don't need a response
       return null;
@@ -709,6 +761,9 @@ async function handleMessage(server, req, defaultHandler) {
           description: tool.description,
           inputSchema: tool.inputSchema,
         };
+        if (tool.outputSchema) {
+          toolDef.outputSchema = tool.outputSchema;
+        }
         list.push(toolDef);
       });
       server.replyResult(id, { tools: list });
@@ -750,12 +805,18 @@ async function handleMessage(server, req, defaultHandler) {
         return;
       }
 
+      const schemaErrors = validateInputSchema(args, tool.inputSchema);
+      if (schemaErrors.length) {
+        server.replyError(id, -32602, `Invalid arguments for tool '${name}': ${schemaErrors.join("; ")}`);
+        return;
+      }
+
       // Call handler and await the result (supports both sync and async handlers)
       server.debug(`Calling handler for tool: ${name}`);
       const result = await Promise.resolve(handler(args));
       server.debug(`Handler returned for tool: ${name}`);
       const content = result && result.content ? result.content : [];
-      server.replyResult(id, { content, isError: false });
+      server.replyResult(id, buildToolCallResult(tool, content));
     } else if (/^notifications\//.test(method)) {
       server.debug(`ignore ${method}`);
     } else {
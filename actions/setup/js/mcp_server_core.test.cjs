@@ -225,6 +225,59 @@ describe("mcp_server_core.cjs", () => {
       expect(results[0].error.message).toContain("Example:");
     });
 
+    it("should reject arguments that violate the input schema", async () => {
+      const { registerTool, handleMessage } = await import("./mcp_server_core.cjs");
+      registerTool(server, {
+        name: "typed_tool",
+        description: "A typed tool",
+        inputSchema: {
+          type: "object",
+          properties: {
+            severity: { type: "string", enum: ["low", "high"] },
+            count: { type: "integer", minimum: 1 },
+          },
+        },
+        handler: () => ({ content: [{ type: "text", text: "called" }] }),
+      });
+
+      await handleMessage(server, {
+        jsonrpc: "2.0",
+        id: 1,
+        method: "tools/call",
+        params: { name: "typed_tool", arguments: { severity: "medium", count: 0 } },
+      });
+
+      expect(results).toHaveLength(1);
+      expect(results[0].error.code).toBe(-32602);
+      expect(results[0].error.message).toContain('severity must be one of: "low", "high"');
+      expect(results[0].error.message).toContain("count must be >= 1");
+    });
+
+    it("should list output schemas and return structured content", async () => {
+      const { registerTool, handleMessage } = await import("./mcp_server_core.cjs");
+      const outputSchema = { type: "object", properties: { total: { type: "integer" } } };
+      registerTool(server, {
+        name: "structured_tool",
+        description: "A tool with an output schema",
+        inputSchema: { type: "object", properties: {} },
+        outputSchema,
+        handler: () => ({ content: [{ type: "text", text: '{"total":3}' }] }),
+      });
+
+      await handleMessage(server, { jsonrpc: "2.0", id: 1, method: "tools/list" });
+      const listed = results[0].result.tools.find(tool => tool.name === "structured_tool");
+      expect(listed.outputSchema).toEqual(outputSchema);
+      expect(results[0].result.tools.find(tool => tool.name === "test_tool").outputSchema).toBeUndefined();
+
+      await handleMessage(server, {
+        jsonrpc: "2.0",
+        id: 2,
+        method: "tools/call",
+        params: { name: "structured_tool", arguments: {} },
+      });
+      expect(results[1].result.structuredContent).toEqual({ total: 3 });
+    });
+
     it("should return error for unknown method", async () => {
       const { handleMessage } = await import("./mcp_server_core.cjs");
 
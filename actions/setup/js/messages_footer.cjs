@@ -11,6 +11,7 @@
 const { getMessages, renderTemplate, toSnakeCase } = require("./messages_core.cjs");
 const { getMissingInfoSections } = require("./missing_messages_helper.cjs");
 const { getBlockedDomains, generateBlockedDomainsSection } = require("./firewall_blocked_domains.cjs");
+const { readEgressReport, generateEgressReportSection } = require("./egress_report.cjs");
 
 /**
  * @typedef {Object} FooterContext
@@ -251,11 +252,17 @@ function generateFooterWithMessages(workflowName, runUrl, workflowSource, workfl
     footer += missingInfoSections;
   }
 
-  // Add firewall blocked domains section if any domains were blocked
-  const blockedDomains = getBlockedDomains();
-  const blockedDomainsSection = generateBlockedDomainsSection(blockedDomains);
-  if (blockedDomainsSection) {
-    footer += blockedDomainsSection;
+  // Add the egress report of the agent job if available, which includes the blocked domains,
+  // otherwise add firewall blocked domains section if any domains were blocked
+  const egressReport = readEgressReport();
+  if (egressReport) {
+    footer += generateEgressReportSection(egressReport);
+  } else {
+    const blockedDomains = getBlockedDomains();
+    const blockedDomainsSection = generateBlockedDomainsSection(blockedDomains);
+    if (blockedDomainsSection) {
+      footer += blockedDomainsSection;
+    }
   }
 
   // Add XML comment marker for traceability
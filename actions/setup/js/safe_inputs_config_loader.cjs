@@ -14,7 +14,9 @@ const fs = require("fs");
  * @property {string} name - Tool name
  * @property {string} description - Tool description
  * @property {Object} inputSchema - JSON Schema for tool inputs
+ * @property {Object} [outputSchema] - JSON Schema for the structured tool result
  * @property {string} [handler] - Path to handler file (.cjs, .sh, or .py)
+ * @property {Object} [container] - Container configuration for tools backed by a container image
  * @property {number} [timeout] - Timeout in seconds for tool execution (default: 60)
  */
 
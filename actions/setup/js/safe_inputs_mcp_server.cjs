@@ -29,7 +29,9 @@ const { getErrorMessage } = require("./error_helpers.cjs");
  * @property {string} name - Tool name
  * @property {string} description - Tool description
  * @property {Object} inputSchema - JSON Schema for tool inputs
+ * @property {Object} [outputSchema] - JSON Schema for the structured tool result
  * @property {string} [handler] - Path to handler file (.cjs, .sh, or .py)
+ * @property {Object} [container] - Container configuration for tools backed by a container image
  */
 
 /**
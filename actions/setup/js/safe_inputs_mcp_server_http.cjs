@@ -21,7 +21,7 @@
 const http = require("http");
 const { randomUUID } = require("crypto");
 const { MCPServer, MCPHTTPTransport } = require("./mcp_http_transport.cjs");
-const { validateRequiredFields } = require("./safe_inputs_validation.cjs");
+const { validateRequiredFields, validateInputSchema } = require("./safe_inputs_validation.cjs");
 const { generateEnhancedErrorMessage } = require("./mcp_enhanced_errors.cjs");
 const { createLogger } = require("./mcp_logger.cjs");
 const { bootstrapSafeInputsServer, cleanupConfigFile } = require("./safe_inputs_bootstrap.cjs");
@@ -78,9 +78,8 @@ function createMCPServer(configPath, options = {}) {
 
     logger.debug(`Registering tool: ${tool.name}`);
 
-    // Register the tool with the MCP SDK using the high-level API
     // The callback receives the arguments directly as the first parameter
-    server.tool(tool.name, tool.description || "", tool.inputSchema || { type: "object", properties: {} }, async args => {
+    const callTool = async args => {
       logger.debug(`Calling handler for tool: ${tool.name}`);
 
       // Validate required fields using helper
@@ -89,6 +88,12 @@ function createMCPServer(configPath, options = {}) {
         throw new Error(generateEnhancedErrorMessage(missing, tool.name, tool.inputSchema));
       }
 
+      // Validate the argument constraints (enum, pattern, ranges, nested schemas)
+      const schemaErrors = validateInputSchema(args, tool.inputSchema);
+      if (schemaErrors.length) {
+        throw new Error(`Invalid arguments for tool '${tool.name}': ${schemaErrors.join("; ")}`);
+      }
+
       // Call the handler
       const result = await Promise.resolve(tool.handler(args));
       logger.debug(`Handler returned for tool: ${tool.name}`);
@@ -96,7 +101,10 @@ function createMCPServer(configPath, options = {}) {
       // Normalize result to MCP format
       const content = result && result.content ? result.content : [];
       return { content, isError: false };
-    });
+    };
+
+    // Register the tool with the MCP SDK using the high-level API
+    server.tool(tool.name, tool.description || "", tool.inputSchema || { type: "object", properties: {} }, callTool, { outputSchema: tool.outputSchema });
 
     registeredCount++;
   }
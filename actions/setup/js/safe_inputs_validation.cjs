@@ -27,6 +27,125 @@ function validateRequiredFields(args, inputSchema) {
   return missing;
 }
 
+/**
+ * Get the JSON Schema type name of a value
+ * @param {any} value - The value to inspect
+ * @returns {string} The JSON Schema type name
+ */
+function jsonTypeOf(value) {
+  if (value === null) {
+    return "null";
+  }
+  if (Array.isArray(value)) {
+    return "array";
+  }
+  if (typeof value === "number") {
+    return Number.isInteger(value) ? "integer" : "number";
+  }
+  return typeof value;
+}
+
+/**
+ * Validate a value against a JSON Schema property definition.
+ * Supports the subset of JSON Schema emitted for safe-input parameters:
+ * type, enum, pattern, minimum, maximum, minLength, maxLength, items, properties and required.
+ * @param {any} value - The value to validate
+ * @param {Object} schema - The JSON Schema definition
+ * @param {string} path - Path of the value, used in error messages
+ * @returns {string[]} Array of validation errors (empty if the value is valid)
+ */
+function validateValue(value, schema, path) {
+  if (!schema || typeof schema !== "object") {
+    return [];
+  }
+
+  const actualType = jsonTypeOf(value);
+  if (schema.type) {
+    const typeMatches = schema.type === actualType || (schema.type === "number" && actualType === "integer");
+    if (!typeMatches) {
+      return [`${path} must be of type ${schema.type}, got ${actualType}`];
+    }
+  }
+
+  const errors = [];
+  if (Array.isArray(schema.enum) && !schema.enum.some(allowed => JSON.stringify(allowed) === JSON.stringify(value))) {
+    errors.push(`${path} must be one of: ${schema.enum.map(allowed => JSON.stringify(allowed)).join(", ")}`);
+  }
+
+  if (typeof value === "string") {
+    if (typeof schema.pattern === "string" && !new RegExp(schema.pattern, "u").test(value)) {
+      errors.push(`${path} must match pattern ${schema.pattern}`);
+    }
+    if (typeof schema.minLength === "number" && value.length < schema.minLength) {
+      errors.push(`${path} must be at least ${schema.minLength} characters`);
+    }
+    if (typeof schema.maxLength === "number" && value.length > schema.maxLength) {
+      errors.push(`${path} must be at most ${schema.maxLength} characters`);
+    }
+  }
+
+  if (typeof value === "number") {
+    if (typeof schema.minimum === "number" && value < schema.minimum) {
+      errors.push(`${path} must be >= ${schema.minimum}`);
+    }
+    if (typeof schema.maximum === "number" && value > schema.maximum) {
+      errors.push(`${path} must be <= ${schema.maximum}`);
+    }
+  }
+
+  if (Array.isArray(value) && schema.items) {
+    value.forEach((item, index) => {
+      errors.push(...validateValue(item, schema.items, `${path}[${index}]`));
+    });
+  }
+
+  if (actualType === "object" && (schema.properties || schema.required)) {
+    errors.push(...validateObject(value, schema, `${path}.`));
+  }
+
+  return errors;
+}
+
+/**
+ * Validate the properties of an object against an object schema
+ * @param {Object} value - The object to validate
+ * @param {Object} schema - The object JSON Schema
+ * @param {string} prefix - Prefix for property paths in error messages
+ * @returns {string[]} Array of validation errors
+ */
+function validateObject(value, schema, prefix) {
+  const errors = [];
+  const required = Array.isArray(schema.required) ? schema.required : [];
+  for (const name of required) {
+    if (value[name] === undefined || value[name] === null) {
+      errors.push(`${prefix}${name} is required`);
+    }
+  }
+  const properties = schema.properties || {};
+  for (const [name, propertySchema] of Object.entries(properties)) {
+    if (value[name] === undefined || value[name] === null) {
+      continue;
+    }
+    errors.push(...validateValue(value[name], propertySchema, `${prefix}${name}`));
+  }
+  return errors;
+}
+
+/**
+ * Validate tool arguments against the constraints of the tool input schema.
+ * Missing required fields are reported by validateRequiredFields.
+ * @param {Object} args - The arguments object to validate
+ * @param {Object} inputSchema - The tool input schema
+ * @returns {string[]} Array of validation errors (empty if the arguments are valid)
+ */
+function validateInputSchema(args, inputSchema) {
+  if (!inputSchema || !inputSchema.properties) {
+    return [];
+  }
+  return validateObject(args || {}, { properties: inputSchema.properties }, "");
+}
+
 module.exports = {
   validateRequiredFields,
+  validateInputSchema,
 };
@@ -135,4 +135,58 @@ describe("safe_inputs_validation.cjs", () => {
       expect(missing).toEqual([]);
     });
   });
+
+  describe("validateInputSchema", () => {
+    const schema = {
+      type: "object",
+      properties: {
+        query: { type: "string", pattern: "^[a-z]+$", maxLength: 5 },
+        limit: { type: "integer", minimum: 1, maximum: 100 },
+        state: { type: "string", enum: ["open", "closed"] },
+        labels: { type: "array", items: { type: "string", minLength: 1 } },
+        filter: {
+          type: "object",
+          properties: { author: { type: "string" } },
+          required: ["author"],
+        },
+      },
+    };
+
+    it("should return empty array for valid arguments", async () => {
+      const { validateInputSchema } = await import("./safe_inputs_validation.cjs");
+
+      const errors = validateInputSchema({ query: "bugs", limit: 10, state: "open", labels: ["a"], filter: { author: "octocat" } }, schema);
+
+      expect(errors).toEqual([]);
+    });
+
+    it("should ignore arguments that are not provided", async () => {
+      const { validateInputSchema } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateInputSchema({}, schema)).toEqual([]);
+      expect(validateInputSchema({ query: "bugs" }, null)).toEqual([]);
+    });
+
+    it("should report type, enum, pattern and range violations", async () => {
+      const { validateInputSchema } = await import("./safe_inputs_validation.cjs");
+
+      const errors = validateInputSchema({ query: "Bugs!!", limit: 2.5, state: "merged" }, schema);
+
+      expect(errors).toEqual(["query must match pattern ^[a-z]+$", "query must be at most 5 characters", "limit must be of type integer, got number", 'state must be one of: "open", "closed"']);
+    });
+
+    it("should validate nested array items and object properties", async () => {
+      const { validateInputSchema } = await import("./safe_inputs_validation.cjs");
+
+      const errors = validateInputSchema({ labels: ["ok", "", 3], filter: {} }, schema);
+
+      expect(errors).toEqual(["labels[1] must be at least 1 characters", "labels[2] must be of type string, got integer", "filter.author is required"]);
+    });
+
+    it("should accept integers for number parameters", async () => {
+      const { validateInputSchema } = await import("./safe_inputs_validation.cjs");
+
+      expect(validateInputSchema({ ratio: 2 }, { type: "object", properties: { ratio: { type: "number", maximum: 2 } } })).toEqual([]);
+    });
+  });
 });
@@ -142,6 +142,7 @@ SAFE_INPUTS_FILES=(
   "mcp_handler_shell.cjs"
   "mcp_handler_python.cjs"
   "mcp_handler_go.cjs"
+  "mcp_handler_container.cjs"
   "mcp_handler_javascript.cjs"
   "read_buffer.cjs"
   "generate_safe_inputs_config.cjs"
@@ -194,6 +195,7 @@ SAFE_OUTPUTS_FILES=(
   "mcp_handler_python.cjs"
   "mcp_handler_shell.cjs"
   "mcp_handler_go.cjs"
+  "mcp_handler_container.cjs"
   "mcp_handler_javascript.cjs"
   "read_buffer.cjs"
   "safe_inputs_validation.cjs"
@@ -0,0 +1,76 @@
+#!/usr/bin/env bash
+#
+# check_agent_fallback.sh - Decide whether a failed agent run falls back to the next engine
+#
+# This script runs after the agent execution step of an engine fallback chain failed.
+# It searches the agent log for the quota, authentication and infrastructure error
+# patterns of the failed engine. When one matches, the log of the failed attempt is
+# kept next to the agent log, aw_info.json is updated to the fallback engine and the
+# step output "fallback=true" lets the fallback engine's steps run. Any other failure
+# (e.g. the agent itself failed the task) is not retried and fails the step.
+#
+# Environment variables:
+#   GH_AW_AGENT_ENGINE          - ID of the engine that failed
+#   GH_AW_FALLBACK_ATTEMPT      - Number of the failed attempt (1 for the primary engine)
+#   GH_AW_FALLBACK_ENGINE       - ID of the fallback engine
+#   GH_AW_FALLBACK_ENGINE_NAME  - Display name of the fallback engine
+#   GH_AW_FALLBACK_MODEL        - Model of the fallback engine (optional)
+#   GH_AW_FALLBACK_PATTERNS     - Extended regular expressions, one per line (case-insensitive)
+#   GH_AW_AGENT_LOG             - Agent log file (default: /tmp/gh-aw/agent-stdio.log)
+#   GH_AW_INFO_PATH             - aw_info.json file (default: /tmp/gh-aw/aw_info.json)
+#
+# Exit codes:
+#   0 - Falling back to the next engine
+#   1 - The failure is not one a fallback engine can recover from
+
+set -euo pipefail
+
+LOG_FILE="${GH_AW_AGENT_LOG:-/tmp/gh-aw/agent-stdio.log}"
+AW_INFO="${GH_AW_INFO_PATH:-/tmp/gh-aw/aw_info.json}"
+ATTEMPT="${GH_AW_FALLBACK_ATTEMPT:-1}"
+
+if [ ! -f "$LOG_FILE" ]; then
+  echo "::error::Agent log ${LOG_FILE@Q} not found, not falling back to ${GH_AW_FALLBACK_ENGINE}"
+  exit 1
+fi
+
+REASON=""
+while IFS= read -r pattern; do
+  if [ -z "$pattern" ]; then
+    continue
+  fi
+  if MATCH=$(grep -m1 -oiE -- "$pattern" "$LOG_FILE" | head -n 1); then
+    REASON="$MATCH"
+    break
+  fi
+done <<< "${GH_AW_FALLBACK_PATTERNS:-}"
+
+if [ -z "$REASON" ]; then
+  echo "::error::Engine ${GH_AW_AGENT_ENGINE} failed without a quota, authentication or infrastructure error, not falling back to ${GH_AW_FALLBACK_ENGINE}"
+  exit 1
+fi
+
+echo "::warning::Engine ${GH_AW_AGENT_ENGINE} failed (${REASON}), falling back to ${GH_AW_FALLBACK_ENGINE}"
+
+# Keep the log of the failed attempt; the fallback engine starts with a fresh agent log
+ATTEMPT_LOG="${LOG_FILE%.log}.attempt-${ATTEMPT}.log"
+mv "$LOG_FILE" "$ATTEMPT_LOG"
+echo "Log of the failed attempt saved to ${ATTEMPT_LOG@Q}"
+
+# Record the failed attempt and the engine that produces the output in aw_info.json
+if [ -f "$AW_INFO" ]; then
+  jq \
+    --arg failed "$GH_AW_AGENT_ENGINE" \
+    --arg reason "$REASON" \
+    --arg engine "$GH_AW_FALLBACK_ENGINE" \
+    --arg name "${GH_AW_FALLBACK_ENGINE_NAME:-$GH_AW_FALLBACK_ENGINE}" \
+    --arg model "${GH_AW_FALLBACK_MODEL:-}" \
+    '.engine_attempts = ((.engine_attempts // []) + [{engine_id: $failed, model: .model, reason: $reason}])
+     | .engine_id = $engine
+     | .engine_name = $name
+     | .model = $model' \
+    "$AW_INFO" > "${AW_INFO}.tmp"
+  mv "${AW_INFO}.tmp" "$AW_INFO"
+fi
+
+echo "fallback=true" >> "$GITHUB_OUTPUT"
@@ -0,0 +1,67 @@
+#!/bin/bash
+set -e
+
+# check_mcp_gateway_spec_version.sh - Verify the MCP gateway implements the configured features
+#
+# Usage: check_mcp_gateway_spec_version.sh MCP_CONFIG HEALTH_RESPONSE
+#
+# Arguments:
+#   MCP_CONFIG      : Gateway configuration (JSON) passed to the gateway
+#   HEALTH_RESPONSE : Body of the gateway /health response (JSON with specVersion)
+#
+# Optional gateway features are only honored by gateways implementing the MCP Gateway
+# Specification version that introduced them. A gateway implementing an older version
+# ignores the unknown fields, so MCP traffic would silently be live or unlimited.
+#
+#   gateway.cassette : Specification 1.9.0 (record/replay)
+#   gateway.limits   : Specification 1.10.0 (call limits)
+#
+# Exit codes:
+#   0 - The gateway implements every configured feature
+#   1 - The gateway does not implement a configured feature, or its version is unknown
+
+if [ "$#" -ne 2 ]; then
+  echo "Usage: $0 MCP_CONFIG HEALTH_RESPONSE" >&2
+  exit 1
+fi
+
+MCP_CONFIG="$1"
+HEALTH_RESPONSE="$2"
+
+# version_at_least returns success when $1 >= $2 (semantic versions without pre-release)
+version_at_least() {
+  [ "$(printf '%s\n%s\n' "$2" "$1" | sort -V | head -n 1)" = "$2" ]
+}
+
+REQUIRED_VERSION=""
+REQUIRED_FEATURES=""
+if echo "$MCP_CONFIG" | jq -e '.gateway.cassette' >/dev/null 2>&1; then
+  REQUIRED_VERSION="1.9.0"
+  REQUIRED_FEATURES="cassette (1.9.0)"
+fi
+if echo "$MCP_CONFIG" | jq -e '.gateway.limits' >/dev/null 2>&1; then
+  REQUIRED_VERSION="1.10.0"
+  REQUIRED_FEATURES="${REQUIRED_FEATURES:+$REQUIRED_FEATURES, }limits (1.10.0)"
+fi
+
+if [ -z "$REQUIRED_VERSION" ]; then
+  exit 0
+fi
+
+SPEC_VERSION=$(echo "$HEALTH_RESPONSE" | jq -r '.specVersion // empty' 2>/dev/null || true)
+GATEWAY_VERSION=$(echo "$HEALTH_RESPONSE" | jq -r '.gatewayVersion // empty' 2>/dev/null || true)
+SPEC_VERSION="${SPEC_VERSION#v}"
+
+if [ -z "$SPEC_VERSION" ]; then
+  echo "ERROR: MCP gateway did not report its specVersion, so support for ${REQUIRED_FEATURES} cannot be verified" >&2
+  exit 1
+fi
+
+if ! version_at_least "$SPEC_VERSION" "$REQUIRED_VERSION"; then
+  echo "ERROR: MCP gateway ${GATEWAY_VERSION:-(unknown version)} implements MCP Gateway Specification ${SPEC_VERSION}" >&2
+  echo "The workflow configures ${REQUIRED_FEATURES}, which requires specification ${REQUIRED_VERSION} or later" >&2
+  echo "Set sandbox.mcp.version to a gateway release implementing it, or remove the configuration" >&2
+  exit 1
+fi
+
+echo "MCP gateway ${GATEWAY_VERSION:-(unknown version)} implements specification ${SPEC_VERSION}: ${REQUIRED_FEATURES} supported"
@@ -0,0 +1,132 @@
+#!/bin/bash
+# Test script for check_mcp_gateway_spec_version.sh
+set -e
+
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+SCRIPT_PATH="$SCRIPT_DIR/check_mcp_gateway_spec_version.sh"
+
+# Color codes for output
+GREEN='\033[0;32m'
+RED='\033[0;31m'
+NC='\033[0m' # No Color
+
+# Test counters
+TESTS_RUN=0
+TESTS_PASSED=0
+TESTS_FAILED=0
+
+# Print test result
+print_result() {
+  local test_name="$1"
+  local result="$2"
+
+  TESTS_RUN=$((TESTS_RUN + 1))
+
+  if [ "$result" = "PASS" ]; then
+    echo -e "${GREEN}✓ PASS${NC}: $test_name"
+    TESTS_PASSED=$((TESTS_PASSED + 1))
+  else
+    echo -e "${RED}✗ FAIL${NC}: $test_name"
+    TESTS_FAILED=$((TESTS_FAILED + 1))
+  fi
+}
+
+# expect_result runs the check and compares its outcome (pass or fail) with the expectation
+expect_result() {
+  local test_name="$1"
+  local expected="$2"
+  local config="$3"
+  local health="$4"
+
+  local actual="fail"
+  if bash "$SCRIPT_PATH" "$config" "$health" >/dev/null 2>&1; then
+    actual="pass"
+  fi
+  if [ "$actual" = "$expected" ]; then
+    print_result "$test_name" "PASS"
+  else
+    print_result "$test_name (expected $expected, got $actual)" "FAIL"
+  fi
+}
+
+CASSETTE_CONFIG='{"mcpServers":{},"gateway":{"port":80,"cassette":{"mode":"record","path":"/tmp/gh-aw/mcp-cassette/cassette.jsonl"}}}'
+LIMITS_CONFIG='{"mcpServers":{},"gateway":{"port":80,"limits":{"github":{"calls":50}}}}'
+BOTH_CONFIG='{"mcpServers":{},"gateway":{"port":80,"cassette":{"mode":"record","path":"/tmp/c.jsonl"},"limits":{"github":{"calls":50}}}}'
+PLAIN_CONFIG='{"mcpServers":{},"gateway":{"port":80}}'
+
+# Test 1: Script syntax is valid
+test_script_syntax() {
+  echo ""
+  echo "Test 1: Verify script syntax"
+
+  if bash -n "$SCRIPT_PATH" 2>/dev/null; then
+    print_result "Script syntax is valid" "PASS"
+  else
+    print_result "Script has syntax errors" "FAIL"
+  fi
+}
+
+# Test 2: Argument validation
+test_argument_validation() {
+  echo ""
+  echo "Test 2: Argument validation"
+
+  if ! bash "$SCRIPT_PATH" "$PLAIN_CONFIG" >/dev/null 2>&1; then
+    print_result "Script rejects missing health response" "PASS"
+  else
+    print_result "Script should reject missing health response" "FAIL"
+  fi
+}
+
+# Test 3: Gateways without optional features need no particular version
+test_no_features() {
+  echo ""
+  echo "Test 3: No optional features configured"
+
+  expect_result "Plain configuration passes with an old gateway" "pass" "$PLAIN_CONFIG" '{"specVersion":"1.3.0","gatewayVersion":"0.1.4"}'
+  expect_result "Plain configuration passes without specVersion" "pass" "$PLAIN_CONFIG" '{"status":"healthy"}'
+}
+
+# Test 4: Cassettes require specification 1.9.0
+test_cassette() {
+  echo ""
+  echo "Test 4: Cassette requires specification 1.9.0"
+
+  expect_result "Cassette rejected by a 1.8.0 gateway" "fail" "$CASSETTE_CONFIG" '{"specVersion":"1.8.0","gatewayVersion":"0.1.4"}'
+  expect_result "Cassette accepted by a 1.9.0 gateway" "pass" "$CASSETTE_CONFIG" '{"specVersion":"1.9.0","gatewayVersion":"0.2.0"}'
+  expect_result "Cassette rejected without specVersion" "fail" "$CASSETTE_CONFIG" '{"status":"healthy"}'
+}
+
+# Test 5: Limits require specification 1.10.0 (compared as versions, not strings)
+test_limits() {
+  echo ""
+  echo "Test 5: Limits require specification 1.10.0"
+
+  expect_result "Limits rejected by a 1.9.0 gateway" "fail" "$LIMITS_CONFIG" '{"specVersion":"1.9.0","gatewayVersion":"0.2.0"}'
+  expect_result "Limits accepted by a 1.10.0 gateway" "pass" "$LIMITS_CONFIG" '{"specVersion":"1.10.0","gatewayVersion":"0.3.0"}'
+  expect_result "Limits accepted by a 1.12.1 gateway" "pass" "$LIMITS_CONFIG" '{"specVersion":"v1.12.1","gatewayVersion":"0.4.0"}'
+  expect_result "Cassette and limits rejected by a 1.9.0 gateway" "fail" "$BOTH_CONFIG" '{"specVersion":"1.9.0","gatewayVersion":"0.2.0"}'
+}
+
+# Run all tests
+echo "=== Testing check_mcp_gateway_spec_version.sh ==="
+echo "Script: $SCRIPT_PATH"
+
+test_script_syntax
+test_argument_validation
+test_no_features
+test_cassette
+test_limits
+
+# Print summary
+echo ""
+echo "=== Test Summary ==="
+echo "Tests run: $TESTS_RUN"
+echo -e "${GREEN}Tests passed: $TESTS_PASSED${NC}"
+if [ $TESTS_FAILED -gt 0 ]; then
+  echo -e "${RED}Tests failed: $TESTS_FAILED${NC}"
+  exit 1
+else
+  echo -e "${GREEN}All tests passed!${NC}"
+  exit 0
+fi
@@ -0,0 +1,112 @@
+#!/usr/bin/env bash
+#
+# finish_phase.sh - Finish a phase of a multi-phase agent pipeline
+#
+# This script runs after the agent execution of each phase. It extracts the output and the
+# veto of the phase from the <phase-output> and <phase-veto> elements of its final reply,
+# discards the safe outputs emitted by a phase that may not emit them, fails when a phase
+# that may not edit changed the workspace, applies the veto of a review phase by moving
+# all safe outputs aside, and adds the log of the phase to the agent log when the phase
+# ran with the workflow engine.
+#
+# Environment variables:
+#   GH_AW_PHASE_ID            - ID of the phase
+#   GH_AW_PHASE_NAME          - Display name of the phase
+#   GH_AW_PHASE_SAFE_OUTPUTS  - "true" if the phase may emit safe outputs
+#   GH_AW_PHASE_REVIEW        - "true" if the phase reviews the safe outputs
+#   GH_AW_PHASE_EDIT          - "false" if the phase may not change the workspace
+#   GH_AW_AGENT_LOG           - Agent log the phase log is added to (optional)
+#   GH_AW_PHASES_DIR          - Directory of the phase files (default: /tmp/gh-aw/phases)
+#   GH_AW_SAFE_OUTPUTS        - Safe outputs file (JSONL)
+
+set -euo pipefail
+
+PHASES_DIR="${GH_AW_PHASES_DIR:-/tmp/gh-aw/phases}"
+PHASE_DIR="${PHASES_DIR}/${GH_AW_PHASE_ID}"
+SAFE_OUTPUTS_FILE="${GH_AW_SAFE_OUTPUTS:-}"
+PHASE_LOG="${PHASE_DIR}/agent-stdio.log"
+
+# workspace_fingerprint prints a hash of the commit, changes and untracked files of the
+# workspace, or nothing when the workspace is not a git repository
+workspace_fingerprint() {
+  local workspace="${GITHUB_WORKSPACE:-$PWD}"
+  git -C "$workspace" rev-parse --is-inside-work-tree >/dev/null 2>&1 || return 0
+  {
+    git -C "$workspace" rev-parse HEAD 2>/dev/null || true
+    git -C "$workspace" diff --binary HEAD 2>/dev/null || true
+    (cd "$workspace" && git ls-files --others --exclude-standard -z | xargs -0 -r sha256sum)
+  } | sha256sum | cut -d' ' -f1
+}
+
+# extract_element prints the content of the last element with the given name in the log of
+# the phase. The string values of JSON lines (stream-json logs) are searched along with the
+# other lines, so the reply is found whether the engine logs JSON or plain text.
+extract_element() {
+  [ -f "$PHASE_LOG" ] || return 0
+  jq -Rsr --arg name "$1" '
+    split("\n")
+    | map((fromjson? | [.. | strings] | join("\n")) // .)
+    | join("\n")
+    | [match("<" + $name + ">(?<content>[\\s\\S]*?)</" + $name + ">"; "g")]
+    | last
+    | .captures[0].string // empty
+  ' "$PHASE_LOG"
+}
+
+if [ -n "${GH_AW_AGENT_LOG:-}" ] && [ -f "$PHASE_LOG" ]; then
+  cat "$PHASE_LOG" >> "$GH_AW_AGENT_LOG"
+fi
+
+OUTPUT=$(extract_element phase-output)
+if [ -n "$OUTPUT" ]; then
+  printf '%s\n' "$OUTPUT" > "${PHASE_DIR}/output.md"
+fi
+if [ "${GH_AW_PHASE_REVIEW:-false}" = "true" ]; then
+  VETO=$(extract_element phase-veto)
+  if [ -n "$VETO" ]; then
+    printf '%s\n' "$VETO" > "${PHASE_DIR}/veto.md"
+  fi
+fi
+
+# A phase that may not edit must leave the workspace unchanged
+if [ "${GH_AW_PHASE_EDIT:-true}" = "false" ] && [ -f "${PHASE_DIR}/workspace-start" ]; then
+  if [ "$(workspace_fingerprint)" != "$(cat "${PHASE_DIR}/workspace-start")" ]; then
+    echo "::error::The ${GH_AW_PHASE_NAME} phase may not edit but changed the workspace"
+    git -C "${GITHUB_WORKSPACE:-$PWD}" status --short || true
+    exit 1
+  fi
+fi
+
+if [ -z "$SAFE_OUTPUTS_FILE" ] || [ ! -f "$SAFE_OUTPUTS_FILE" ]; then
+  echo "No safe outputs file, nothing to enforce for phase ${GH_AW_PHASE_NAME}"
+  exit 0
+fi
+
+# Keep only the safe outputs emitted before a phase that may not emit them
+if [ "${GH_AW_PHASE_SAFE_OUTPUTS:-true}" != "true" ]; then
+  START=$(cat "${PHASE_DIR}/safe-outputs-start" 2>/dev/null || echo 0)
+  COUNT=$(wc -l < "$SAFE_OUTPUTS_FILE" | tr -d ' ')
+  if [ "$COUNT" -gt "$START" ]; then
+    echo "::warning::Discarding $((COUNT - START)) safe output(s) emitted by the phase ${GH_AW_PHASE_NAME}, which may not emit safe outputs"
+    tail -n +"$((START + 1))" "$SAFE_OUTPUTS_FILE" > "${PHASE_DIR}/discarded-outputs.jsonl"
+    head -n "$START" "$SAFE_OUTPUTS_FILE" > "${SAFE_OUTPUTS_FILE}.tmp"
+    mv "${SAFE_OUTPUTS_FILE}.tmp" "$SAFE_OUTPUTS_FILE"
+  fi
+fi
+
+# A veto of a review phase discards all safe outputs
+if [ "${GH_AW_PHASE_REVIEW:-false}" = "true" ] && [ -s "${PHASE_DIR}/veto.md" ]; then
+  echo "::warning::The ${GH_AW_PHASE_NAME} phase vetoed the safe outputs, none will be applied"
+  mv "$SAFE_OUTPUTS_FILE" "${PHASE_DIR}/vetoed-outputs.jsonl"
+  : > "$SAFE_OUTPUTS_FILE"
+  if [ -n "${GITHUB_STEP_SUMMARY:-}" ]; then
+    {
+      echo "## Safe outputs vetoed by the ${GH_AW_PHASE_NAME} phase"
+      echo ""
+      cat "${PHASE_DIR}/veto.md"
+    } >> "$GITHUB_STEP_SUMMARY"
+  fi
+  if [ -n "${GITHUB_OUTPUT:-}" ]; then
+    echo "vetoed=true" >> "$GITHUB_OUTPUT"
+  fi
+fi
@@ -0,0 +1,134 @@
+#!/usr/bin/env bash
+#
+# prepare_phase_prompt.sh - Write the prompt of a phase of a multi-phase agent pipeline
+#
+# This script runs before the agent execution of each phase. The workflow prompt is
+# kept aside on the first phase; the prompt of each phase is the workflow prompt without
+# the sections of the other phases, followed by instructions for the phase and the
+# outputs of the phases before it. A phase reports its output (and a review phase its
+# veto) in its final reply, which finish_phase.sh extracts from the log of the phase, so
+# no phase needs the edit tool. A review phase also receives the safe outputs emitted
+# so far and how to veto them. The number of safe outputs before the phase is recorded
+# so finish_phase.sh can discard the safe outputs of phases that may not emit them, and
+# a fingerprint of the workspace so it can verify that phases that may not edit left the
+# workspace unchanged.
+#
+# Environment variables:
+#   GH_AW_PHASE_ID            - ID of the phase
+#   GH_AW_PHASE_NAME          - Display name of the phase
+#   GH_AW_PHASE_NUMBER        - Number of the phase (1 for the first phase)
+#   GH_AW_PHASE_COUNT         - Number of phases
+#   GH_AW_PHASE_SECTION       - Heading of the prompt section of the phase
+#   GH_AW_PHASE_SECTIONS      - Headings of the prompt sections of all phases, one per line
+#   GH_AW_PHASE_PREVIOUS      - IDs of the phases before this phase, separated by spaces
+#   GH_AW_PHASE_SAFE_OUTPUTS  - "true" if the phase may emit safe outputs
+#   GH_AW_PHASE_REVIEW        - "true" if the phase reviews the safe outputs
+#   GH_AW_PHASE_EDIT          - "false" if the phase may not change the workspace
+#   GH_AW_PROMPT              - Prompt file (default: /tmp/gh-aw/aw-prompts/prompt.txt)
+#   GH_AW_PHASES_DIR          - Directory of the phase files (default: /tmp/gh-aw/phases)
+#   GH_AW_SAFE_OUTPUTS        - Safe outputs file (JSONL)
+
+set -euo pipefail
+
+PROMPT_FILE="${GH_AW_PROMPT:-/tmp/gh-aw/aw-prompts/prompt.txt}"
+WORKFLOW_PROMPT="$(dirname "$PROMPT_FILE")/workflow-prompt.txt"
+PHASES_DIR="${GH_AW_PHASES_DIR:-/tmp/gh-aw/phases}"
+PHASE_DIR="${PHASES_DIR}/${GH_AW_PHASE_ID}"
+SAFE_OUTPUTS_FILE="${GH_AW_SAFE_OUTPUTS:-}"
+
+# workspace_fingerprint prints a hash of the commit, changes and untracked files of the
+# workspace, or nothing when the workspace is not a git repository
+workspace_fingerprint() {
+  local workspace="${GITHUB_WORKSPACE:-$PWD}"
+  git -C "$workspace" rev-parse --is-inside-work-tree >/dev/null 2>&1 || return 0
+  {
+    git -C "$workspace" rev-parse HEAD 2>/dev/null || true
+    git -C "$workspace" diff --binary HEAD 2>/dev/null || true
+    (cd "$workspace" && git ls-files --others --exclude-standard -z | xargs -0 -r sha256sum)
+  } | sha256sum | cut -d' ' -f1
+}
+
+mkdir -p "$PHASE_DIR"
+
+# Keep the workflow prompt, as prompt.txt is rewritten for every phase
+if [ ! -f "$WORKFLOW_PROMPT" ]; then
+  cp "$PROMPT_FILE" "$WORKFLOW_PROMPT"
+fi
+
+# Record the number of safe outputs emitted before the phase
+SAFE_OUTPUT_COUNT=0
+if [ -n "$SAFE_OUTPUTS_FILE" ] && [ -f "$SAFE_OUTPUTS_FILE" ]; then
+  SAFE_OUTPUT_COUNT=$(wc -l < "$SAFE_OUTPUTS_FILE" | tr -d ' ')
+fi
+echo "$SAFE_OUTPUT_COUNT" > "${PHASE_DIR}/safe-outputs-start"
+
+# Record the workspace of a phase that may not change it
+if [ "${GH_AW_PHASE_EDIT:-true}" = "false" ]; then
+  workspace_fingerprint > "${PHASE_DIR}/workspace-start"
+fi
+
+{
+  # Drop the sections of the other phases; a section ends at the next heading of the same or a higher level
+  awk -v own="${GH_AW_PHASE_SECTION}" -v sections="${GH_AW_PHASE_SECTIONS:-}" '
+    function norm(s) { s = tolower(s); gsub(/^[ \t]+|[ \t]+$/, "", s); return s }
+    BEGIN {
+      n = split(sections, list, "\n")
+      for (i = 1; i <= n; i++) if (norm(list[i]) != "") phase[norm(list[i])] = 1
+      own = norm(own)
+    }
+    /^(```|~~~)/ { fence = !fence }
+    !fence && /^#+[ \t]/ {
+      match($0, /^#+/)
+      depth = RLENGTH
+      title = norm(substr($0, depth + 1))
+      if (level > 0 && depth <= level) { skip = 0; level = 0 }
+      if (level == 0 && (title in phase)) { level = depth; skip = (title != own) }
+    }
+    !skip { print }
+  ' "$WORKFLOW_PROMPT"
+
+  echo ""
+  echo "<phase>"
+  echo "This workflow runs in ${GH_AW_PHASE_COUNT} phases and you are running phase ${GH_AW_PHASE_NUMBER}: ${GH_AW_PHASE_NAME}. Follow the instructions of the \"${GH_AW_PHASE_SECTION}\" section; the sections of the other phases are carried out in their own phases."
+
+  for previous in ${GH_AW_PHASE_PREVIOUS:-}; do
+    echo ""
+    echo "<phase-output phase=\"${previous}\">"
+    if [ -s "${PHASES_DIR}/${previous}/output.md" ]; then
+      cat "${PHASES_DIR}/${previous}/output.md"
+    else
+      echo "The ${previous} phase did not write an output."
+    fi
+    echo "</phase-output>"
+  done
+
+  echo ""
+  echo "When you are done, end your final reply with the result of this phase (for example your plan, a summary of your changes or your review) inside a single <phase-output> element. It is passed to the following phases."
+
+  if [ "${GH_AW_PHASE_EDIT:-true}" = "false" ]; then
+    echo ""
+    echo "This phase must not change the workspace. The workflow fails if the workspace is changed in this phase."
+  fi
+
+  if [ "${GH_AW_PHASE_SAFE_OUTPUTS:-true}" != "true" ]; then
+    echo ""
+    echo "This phase may not emit safe outputs: do not use the safe output tools. Safe outputs emitted in this phase are discarded."
+  fi
+
+  if [ "${GH_AW_PHASE_REVIEW:-false}" = "true" ]; then
+    echo ""
+    echo "Review the safe outputs emitted by the previous phases below. If they must not be applied, also add the reason to your final reply inside a <phase-veto> element and no safe outputs are applied. Otherwise do not use that element."
+    echo "<safe-outputs>"
+    if [ -n "$SAFE_OUTPUTS_FILE" ] && [ -s "$SAFE_OUTPUTS_FILE" ]; then
+      cat "$SAFE_OUTPUTS_FILE"
+    else
+      echo "No safe outputs were emitted."
+    fi
+    echo "</safe-outputs>"
+  fi
+  echo "</phase>"
+} > "${PROMPT_FILE}.tmp"
+mv "${PROMPT_FILE}.tmp" "$PROMPT_FILE"
+
+cp "$PROMPT_FILE" "${PHASE_DIR}/prompt.txt"
+echo "Prepared the prompt of phase ${GH_AW_PHASE_NUMBER}/${GH_AW_PHASE_COUNT} (${GH_AW_PHASE_NAME})"
@@ -0,0 +1,191 @@
+#!/bin/bash
+# Test script for prepare_phase_prompt.sh and finish_phase.sh
+
+set -e
+
+# Setup test environment
+TEST_DIR=$(mktemp -d)
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+PREPARE_SCRIPT="$SCRIPT_DIR/prepare_phase_prompt.sh"
+FINISH_SCRIPT="$SCRIPT_DIR/finish_phase.sh"
+
+cleanup() {
+    rm -rf "$TEST_DIR"
+}
+trap cleanup EXIT
+
+echo "Testing prepare_phase_prompt.sh and finish_phase.sh..."
+echo ""
+
+export GH_AW_PROMPT="$TEST_DIR/aw-prompts/prompt.txt"
+export GH_AW_PHASES_DIR="$TEST_DIR/phases"
+export GH_AW_SAFE_OUTPUTS="$TEST_DIR/outputs.jsonl"
+export GH_AW_PHASE_COUNT="3"
+export GH_AW_PHASE_SECTIONS="Plan
+Execute
+Review"
+export GITHUB_OUTPUT="$TEST_DIR/output"
+export GITHUB_STEP_SUMMARY="$TEST_DIR/summary"
+
+mkdir -p "$TEST_DIR/aw-prompts"
+cat > "$GH_AW_PROMPT" <<'EOF'
+# Dependency update
+
+Shared context for every phase.
+
+## Plan
+
+Plan the update.
+
+```markdown
+## Execute
+Not a heading inside a code block.
+```
+
+### Details
+
+Planning details.
+
+## execute
+
+Apply the plan.
+
+## Review
+
+Review the changes.
+
+# Appendix
+
+Shared appendix.
+EOF
+: > "$GH_AW_SAFE_OUTPUTS"
+
+run_phase() {
+    GH_AW_PHASE_ID="$1" GH_AW_PHASE_NAME="$2" GH_AW_PHASE_NUMBER="$3" GH_AW_PHASE_SECTION="$2" \
+    GH_AW_PHASE_PREVIOUS="$4" GH_AW_PHASE_SAFE_OUTPUTS="$5" GH_AW_PHASE_REVIEW="$6" \
+        bash "$PREPARE_SCRIPT" > /dev/null
+}
+
+finish_phase() {
+    GH_AW_PHASE_ID="$1" GH_AW_PHASE_NAME="$1" GH_AW_PHASE_SAFE_OUTPUTS="$2" GH_AW_PHASE_REVIEW="$3" \
+        bash "$FINISH_SCRIPT" > /dev/null
+}
+
+# Test 1: The prompt of a phase only contains its own section
+echo "Test 1: Prompt of the plan phase"
+run_phase plan Plan 1 "" false false
+if ! grep -q "Plan the update." "$GH_AW_PROMPT" ||
+   ! grep -q "Planning details." "$GH_AW_PROMPT" ||
+   ! grep -q "Not a heading inside a code block." "$GH_AW_PROMPT" ||
+   ! grep -q "Shared context for every phase." "$GH_AW_PROMPT" ||
+   ! grep -q "Shared appendix." "$GH_AW_PROMPT"; then
+    echo "❌ Test 1 failed: content of the phase missing"
+    cat "$GH_AW_PROMPT"
+    exit 1
+fi
+if grep -q "Apply the plan." "$GH_AW_PROMPT" || grep -q "Review the changes." "$GH_AW_PROMPT"; then
+    echo "❌ Test 1 failed: sections of other phases included"
+    cat "$GH_AW_PROMPT"
+    exit 1
+fi
+if ! grep -q "This phase may not emit safe outputs" "$GH_AW_PROMPT"; then
+    echo "❌ Test 1 failed: safe output instructions missing"
+    exit 1
+fi
+echo "✅ Test 1 passed: plan prompt prepared"
+echo ""
+
+# Test 2: The output of a phase is extracted from the final reply in its stream-json log,
+# and safe outputs of a phase that may not emit them are discarded
+echo "Test 2: Plan phase emitting a safe output"
+cat > "$GH_AW_PHASES_DIR/plan/agent-stdio.log" <<'EOF'
+{"type":"assistant","message":{"content":[{"type":"text","text":"Draft: <phase-output>A draft</phase-output>"}]}}
+Plain diagnostics line
+{"type":"result","result":"Done.\n<phase-output>\nThe plan\n</phase-output>"}
+EOF
+echo '{"type":"create_issue","title":"from plan"}' >> "$GH_AW_SAFE_OUTPUTS"
+finish_phase plan false false
+if [ -s "$GH_AW_SAFE_OUTPUTS" ] || [ ! -s "$GH_AW_PHASES_DIR/plan/discarded-outputs.jsonl" ]; then
+    echo "❌ Test 2 failed: safe output of the plan phase kept"
+    exit 1
+fi
+if ! grep -q "^The plan$" "$GH_AW_PHASES_DIR/plan/output.md" || grep -q "A draft" "$GH_AW_PHASES_DIR/plan/output.md"; then
+    echo "❌ Test 2 failed: output of the final reply not extracted"
+    cat "$GH_AW_PHASES_DIR/plan/output.md"
+    exit 1
+fi
+echo "✅ Test 2 passed: output extracted and safe output discarded"
+echo ""
+
+# Test 3: Later phases receive the outputs of earlier phases and keep their safe outputs
+echo "Test 3: Execute phase receives the plan"
+run_phase execute Execute 2 "plan" true false
+if ! grep -q "Apply the plan." "$GH_AW_PROMPT" || ! grep -q "The plan" "$GH_AW_PROMPT" || grep -q "Plan the update." "$GH_AW_PROMPT"; then
+    echo "❌ Test 3 failed: execute prompt incorrect"
+    cat "$GH_AW_PROMPT"
+    exit 1
+fi
+echo '{"type":"create_pull_request","title":"update"}' >> "$GH_AW_SAFE_OUTPUTS"
+finish_phase execute true false
+if [ "$(wc -l < "$GH_AW_SAFE_OUTPUTS" | tr -d ' ')" != "1" ]; then
+    echo "❌ Test 3 failed: safe output of the execute phase not kept"
+    exit 1
+fi
+echo "✅ Test 3 passed: plan passed on"
+echo ""
+
+# Test 4: A review phase sees the safe outputs and can veto them
+echo "Test 4: Review phase vetoes the safe outputs"
+run_phase review Review 3 "plan execute" false true
+if ! grep -q '"create_pull_request"' "$GH_AW_PROMPT" || ! grep -q "<phase-veto> element" "$GH_AW_PROMPT"; then
+    echo "❌ Test 4 failed: review prompt incorrect"
+    cat "$GH_AW_PROMPT"
+    exit 1
+fi
+printf 'The pull request is wrong.\n<phase-veto>The update breaks the build</phase-veto>\n<phase-output>Vetoed</phase-output>\n' > "$GH_AW_PHASES_DIR/review/agent-stdio.log"
+finish_phase review false true
+if [ -s "$GH_AW_SAFE_OUTPUTS" ] || [ ! -s "$GH_AW_PHASES_DIR/review/vetoed-outputs.jsonl" ]; then
+    echo "❌ Test 4 failed: vetoed safe outputs kept"
+    exit 1
+fi
+if ! grep -q "vetoed=true" "$GITHUB_OUTPUT" || ! grep -q "breaks the build" "$GITHUB_STEP_SUMMARY"; then
+    echo "❌ Test 4 failed: veto not reported"
+    exit 1
+fi
+echo "✅ Test 4 passed: safe outputs vetoed"
+echo ""
+
+# Test 5: A phase that may not edit must leave the workspace unchanged
+echo "Test 5: Phase that may not edit changes the workspace"
+export GITHUB_WORKSPACE="$TEST_DIR/workspace"
+mkdir -p "$GITHUB_WORKSPACE"
+git -C "$GITHUB_WORKSPACE" init -q
+echo "v1" > "$GITHUB_WORKSPACE/deps.txt"
+git -C "$GITHUB_WORKSPACE" add deps.txt
+git -C "$GITHUB_WORKSPACE" -c user.name=test -c user.email=test@example.com commit -q -m init
+GH_AW_PHASE_EDIT=false run_phase plan Plan 1 "" false false
+if ! grep -q "must not change the workspace" "$GH_AW_PROMPT"; then
+    echo "❌ Test 5 failed: workspace instructions missing"
+    exit 1
+fi
+if ! GH_AW_PHASE_EDIT=false finish_phase plan false false; then
+    echo "❌ Test 5 failed: an unchanged workspace should be accepted"
+    exit 1
+fi
+GH_AW_PHASE_EDIT=false run_phase plan Plan 1 "" false false
+echo "v2" > "$GITHUB_WORKSPACE/deps.txt"
+if GH_AW_PHASE_EDIT=false finish_phase plan false false 2>/dev/null; then
+    echo "❌ Test 5 failed: changed workspace not detected"
+    exit 1
+fi
+git -C "$GITHUB_WORKSPACE" checkout -q deps.txt
+GH_AW_PHASE_EDIT=false run_phase plan Plan 1 "" false false
+echo "new" > "$GITHUB_WORKSPACE/untracked.txt"
+if GH_AW_PHASE_EDIT=false finish_phase plan false false 2>/dev/null; then
+    echo "❌ Test 5 failed: new file not detected"
+    exit 1
+fi
+echo "✅ Test 5 passed: workspace changes detected"
+echo ""
+
+echo "All tests passed!"
@@ -113,6 +113,18 @@ if ! echo "$MCP_CONFIG" | jq -e '.gateway.apiKey' >/dev/null 2>&1; then
   exit 1
 fi
 
+# Validate the cassette exists when the gateway replays recorded MCP traffic
+CASSETTE_MODE=$(echo "$MCP_CONFIG" | jq -r '.gateway.cassette.mode // empty')
+if [ -n "$CASSETTE_MODE" ]; then
+  CASSETTE_PATH=$(echo "$MCP_CONFIG" | jq -r '.gateway.cassette.path // empty')
+  echo "MCP cassette mode: $CASSETTE_MODE ($CASSETTE_PATH)"
+  if [ "$CASSETTE_MODE" = "replay" ] && [ ! -f "$CASSETTE_PATH" ]; then
+    echo "ERROR: MCP cassette to replay not found: $CASSETTE_PATH"
+    echo "Commit a cassette recorded with 'sandbox.mcp.cassette.mode: record' (uploaded as the mcp-cassette artifact)"
+    exit 1
+  fi
+fi
+
 echo "Configuration validated successfully"
 print_timing $CONFIG_VALIDATION_START "Configuration validation"
 echo ""
@@ -279,6 +291,14 @@ else
 fi
 echo ""
 
+# Fail fast when the gateway does not implement the record/replay or call limit features
+# the workflow relies on (an older gateway ignores them and runs live and unlimited)
+if ! bash /opt/gh-aw/actions/check_mcp_gateway_spec_version.sh "$MCP_CONFIG" "$HEALTH_RESPONSE"; then
+  kill $GATEWAY_PID 2>/dev/null || true
+  exit 1
+fi
+echo ""
+
 # Wait for gateway output (rewritten configuration)
 echo "Reading gateway output configuration..."
 OUTPUT_WAIT_START=$(date +%s%3N)
@@ -365,6 +385,11 @@ case "$ENGINE_TYPE" in
     echo "Using Claude converter..."
     bash /opt/gh-aw/actions/convert_gateway_config_claude.sh
     ;;
+  openai)
+    # The agent loop of the openai engine reads the Claude configuration format
+    echo "Using Claude converter for the openai engine..."
+    bash /opt/gh-aw/actions/convert_gateway_config_claude.sh
+    ;;
   *)
     echo "No agent-specific converter found for engine: $ENGINE_TYPE"
     echo "Using gateway output directly"
@@ -374,6 +399,29 @@ case "$ENGINE_TYPE" in
     cat /home/runner/.copilot/mcp-config.json
     ;;
 esac
+
+# Also convert the gateway output for the other engines of the agent job (GH_AW_ADDITIONAL_ENGINES),
+# i.e. fallback engines and the engines of phases, so they can use the MCP servers without restarting the gateway
+for ADDITIONAL_ENGINE in ${GH_AW_ADDITIONAL_ENGINES:-}; do
+  if [ "$ADDITIONAL_ENGINE" = "$ENGINE_TYPE" ]; then
+    continue
+  fi
+  case "$ADDITIONAL_ENGINE" in
+    copilot|codex|claude)
+      echo "Converting configuration for additional engine: $ADDITIONAL_ENGINE"
+      bash "/opt/gh-aw/actions/convert_gateway_config_${ADDITIONAL_ENGINE}.sh"
+      ;;
+    openai)
+      echo "Converting configuration for additional engine: $ADDITIONAL_ENGINE"
+      bash /opt/gh-aw/actions/convert_gateway_config_claude.sh
+      ;;
+    *)
+      echo "No agent-specific converter found for additional engine: $ADDITIONAL_ENGINE"
+      mkdir -p /home/runner/.copilot
+      cp /tmp/gh-aw/mcp-config/gateway-output.json /home/runner/.copilot/mcp-config.json
+      ;;
+  esac
+done
 print_timing $CONFIG_CONVERT_START "Configuration conversion"
 echo ""
 
@@ -37,6 +37,7 @@ REQUIRED_DEPS=(
   "mcp_handler_shell.cjs"
   "mcp_handler_python.cjs"
   "mcp_handler_go.cjs"
+  "mcp_handler_container.cjs"
   "mcp_handler_javascript.cjs"
 )
 
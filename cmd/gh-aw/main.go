@@ -240,7 +240,10 @@ Examples:
   ` + string(constants.CLIExtensionPrefix) + ` compile --watch ci-doctor     # Watch and auto-compile
   ` + string(constants.CLIExtensionPrefix) + ` compile --trial --logical-repo owner/repo  # Compile for trial mode
   ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot        # Generate Dependabot manifests
-  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml`,
+  ` + string(constants.CLIExtensionPrefix) + ` compile --dependabot --force  # Force overwrite existing dependabot.yml
+  ` + string(constants.CLIExtensionPrefix) + ` compile --policy org/policies/aw-policy.yml@main  # Enforce an organization policy
+  ` + string(constants.CLIExtensionPrefix) + ` compile --sbom=spdx          # Also write an SPDX SBOM next to each lock file
+  ` + string(constants.CLIExtensionPrefix) + ` compile ci-doctor --prompt-report --prompt-budget 8000  # Report prompt size by section`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		engineOverride, _ := cmd.Flags().GetString("engine")
 		actionMode, _ := cmd.Flags().GetString("action-mode")
@@ -266,6 +269,11 @@ Examples:
 		stats, _ := cmd.Flags().GetBool("stats")
 		failFast, _ := cmd.Flags().GetBool("fail-fast")
 		noCheckUpdate, _ := cmd.Flags().GetBool("no-check-update")
+		policy, _ := cmd.Flags().GetString("policy")
+		sbom, _ := cmd.Flags().GetString("sbom")
+		promptReport, _ := cmd.Flags().GetBool("prompt-report")
+		promptEvent, _ := cmd.Flags().GetString("prompt-event")
+		promptBudget, _ := cmd.Flags().GetInt("prompt-budget")
 		verbose, _ := cmd.Flags().GetBool("verbose")
 		if err := validateEngine(engineOverride); err != nil {
 			return err
@@ -317,6 +325,11 @@ Examples:
 			JSONOutput:             jsonOutput,
 			Stats:                  stats,
 			FailFast:               failFast,
+			Policy:                 policy,
+			SBOM:                   sbom,
+			PromptReport:           promptReport,
+			PromptEvent:            promptEvent,
+			PromptBudget:           promptBudget,
 		}
 		if _, err := cli.CompileWorkflows(cmd.Context(), config); err != nil {
 			// Return error as-is without additional formatting
@@ -359,6 +372,7 @@ Examples:
   gh aw run daily-perf-improver --auto-merge-prs # Auto-merge any PRs created during execution
   gh aw run daily-perf-improver -f name=value -f env=prod  # Pass workflow inputs
   gh aw run daily-perf-improver --push  # Commit and push workflow files before running
+  gh aw run large-refactor --resume 1234567890  # Resume from the agent checkpoint of a run
   gh aw run daily-perf-improver --dry-run  # Validate without actually running`,
 	Args: cobra.ArbitraryArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -372,11 +386,20 @@ Examples:
 		inputs, _ := cmd.Flags().GetStringArray("raw-field")
 		push, _ := cmd.Flags().GetBool("push")
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		resumeRunID, _ := cmd.Flags().GetString("resume")
 
 		if err := validateEngine(engineOverride); err != nil {
 			return err
 		}
 
+		if resumeRunID != "" {
+			resumeInput, err := cli.ResumeRunInput(resumeRunID)
+			if err != nil {
+				return err
+			}
+			inputs = append(inputs, resumeInput)
+		}
+
 		// If no arguments provided, enter interactive mode
 		if len(args) == 0 {
 			// Check if running in CI environment
@@ -391,6 +414,9 @@ Examples:
 			if enable {
 				return fmt.Errorf("--enable-if-needed flag is not supported in interactive mode")
 			}
+			if resumeRunID != "" {
+				return fmt.Errorf("--resume flag is not supported in interactive mode")
+			}
 			if len(inputs) > 0 {
 				return fmt.Errorf("workflow inputs cannot be specified in interactive mode (they will be collected interactively)")
 			}
@@ -549,6 +575,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	compileCmd.Flags().Bool("stats", false, "Display statistics table sorted by file size (shows jobs, steps, scripts, and shells)")
 	compileCmd.Flags().Bool("fail-fast", false, "Stop at the first validation error instead of collecting all errors")
 	compileCmd.Flags().Bool("no-check-update", false, "Skip checking for gh-aw updates")
+	compileCmd.Flags().String("policy", "", "Organization policy file (local path or owner/repo/path@ref). Defaults to .github/aw/policy.yml when present")
+	compileCmd.Flags().String("sbom", "", "Write a software bill of materials (<workflow>.sbom.json) next to each lock file: cyclonedx or spdx")
+	compileCmd.Flags().Lookup("sbom").NoOptDefVal = "cyclonedx"
+	compileCmd.Flags().Bool("prompt-report", false, "Render the prompt of each workflow for a sample event and report its size by section, with duplicated content")
+	compileCmd.Flags().String("prompt-event", "", "Sample event used by --prompt-report (defaults to the first trigger of the workflow, e.g. issues or pull_request)")
+	compileCmd.Flags().Int("prompt-budget", 0, "Warn when the estimated prompt size exceeds this many tokens (implies --prompt-report)")
 	compileCmd.MarkFlagsMutuallyExclusive("dir", "workflows-dir")
 
 	// Register completions for compile command
@@ -581,6 +613,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	runCmd.Flags().StringArrayP("raw-field", "F", []string{}, "Add a string parameter in key=value format (can be used multiple times)")
 	runCmd.Flags().Bool("push", false, "Commit and push workflow files (including transitive imports) before running")
 	runCmd.Flags().Bool("dry-run", false, "Validate workflow without actually triggering execution on GitHub Actions")
+	runCmd.Flags().String("resume", "", "Resume from the agent checkpoint of a previous run (run ID, requires 'resume: true')")
 	// Register completions for run command
 	runCmd.ValidArgsFunction = cli.CompleteWorkflowNames
 	cli.RegisterEngineFlagCompletion(runCmd)
@@ -601,26 +634,39 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	secretsCmd := cli.NewSecretsCommand()
 	fixCmd := cli.NewFixCommand()
 	upgradeCmd := cli.NewUpgradeCommand()
+	updateActionsCmd := cli.NewUpdateActionsCommand()
+	instantiateCmd := cli.NewInstantiateCommand()
+	safeInputsCmd := cli.NewSafeInputsCommand()
 	completionCmd := cli.NewCompletionCommand()
 	hashCmd := cli.NewHashCommand()
 	projectCmd := cli.NewProjectCommand()
+	policyCmd := cli.NewPolicyCommand()
+	sbomCmd := cli.NewSBOMCommand()
+	depsCmd := cli.NewDepsCommand()
+	toolsCmd := cli.NewToolsCommand()
+	transcriptCmd := cli.NewTranscriptCommand()
+	agentCmd := cli.NewAgentCommand()
 
 	// Assign commands to groups
 	// Setup Commands
 	initCmd.GroupID = "setup"
 	newCmd.GroupID = "setup"
+	instantiateCmd.GroupID = "setup"
 	addCmd.GroupID = "setup"
 	removeCmd.GroupID = "setup"
 	updateCmd.GroupID = "setup"
 	upgradeCmd.GroupID = "setup"
+	updateActionsCmd.GroupID = "setup"
 	secretsCmd.GroupID = "setup"
 
 	// Development Commands
 	compileCmd.GroupID = "development"
 	mcpCmd.GroupID = "development"
+	safeInputsCmd.GroupID = "development"
 	statusCmd.GroupID = "development"
 	listCmd.GroupID = "development"
 	fixCmd.GroupID = "development"
+	policyCmd.GroupID = "development"
 
 	// Execution Commands
 	runCmd.GroupID = "execution"
@@ -632,6 +678,10 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	logsCmd.GroupID = "analysis"
 	auditCmd.GroupID = "analysis"
 	healthCmd.GroupID = "analysis"
+	sbomCmd.GroupID = "analysis"
+	depsCmd.GroupID = "analysis"
+	toolsCmd.GroupID = "analysis"
+	transcriptCmd.GroupID = "analysis"
 
 	// Utilities
 	mcpServerCmd.GroupID = "utilities"
@@ -639,6 +689,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	completionCmd.GroupID = "utilities"
 	hashCmd.GroupID = "utilities"
 	projectCmd.GroupID = "utilities"
+	agentCmd.GroupID = "utilities"
 
 	// version command is intentionally left without a group (common practice)
 
@@ -646,8 +697,10 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(upgradeCmd)
+	rootCmd.AddCommand(updateActionsCmd)
 	rootCmd.AddCommand(trialCmd)
 	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(instantiateCmd)
 	rootCmd.AddCommand(initCmd)
 
 	rootCmd.AddCommand(runCmd)
@@ -660,6 +713,7 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(auditCmd)
 	rootCmd.AddCommand(healthCmd)
 	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(safeInputsCmd)
 	rootCmd.AddCommand(mcpServerCmd)
 	rootCmd.AddCommand(prCmd)
 	rootCmd.AddCommand(versionCmd)
@@ -668,6 +722,12 @@ Use "` + string(constants.CLIExtensionPrefix) + ` help all" to show help for all
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(hashCmd)
 	rootCmd.AddCommand(projectCmd)
+	rootCmd.AddCommand(policyCmd)
+	rootCmd.AddCommand(sbomCmd)
+	rootCmd.AddCommand(depsCmd)
+	rootCmd.AddCommand(toolsCmd)
+	rootCmd.AddCommand(transcriptCmd)
+	rootCmd.AddCommand(agentCmd)
 }
 
 func main() {
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/spf13/cobra"
+)
+
+var agentCommandLog = logger.New("cli:agent_command")
+
+// AgentLoopConfig holds the options of the agent command
+type AgentLoopConfig struct {
+	BaseURL        string        // Base URL of the OpenAI-compatible chat completions API
+	Model          string        // Model name sent to the endpoint
+	APIKey         string        // Optional API key sent as a bearer token
+	MaxTurns       int           // Maximum number of model requests (0 means no limit)
+	MCPConfig      string        // Path of the MCP configuration (mcpServers JSON)
+	AllowTools     []string      // Allowed tools (shell, shell(cmd), write, write(dir), server, server(tool))
+	AgentFile      string        // Custom agent file whose body is prepended to the prompt
+	PromptFile     string        // File containing the prompt
+	StartupTimeout time.Duration // Timeout for connecting to an MCP server
+	ToolTimeout    time.Duration // Timeout for a tool call
+}
+
+// NewAgentCommand creates the agent command, the agent loop of the openai engine
+func NewAgentCommand() *cobra.Command {
+	config := AgentLoopConfig{}
+	var maxTurns string
+
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Run an agent loop against an OpenAI-compatible chat completions endpoint",
+		Long: `Run an agent loop against an OpenAI-compatible chat completions endpoint
+(vLLM, Ollama, internal gateways). This is the runtime of the openai engine.
+
+The model is called with function calling until it answers without tool calls or
+--max-turns requests were made. The tools offered to the model are:
+- Read: read a file
+- Bash: run a command, when --allow-tool shell or shell(<command>) is given
+- Write and Edit: write files, when --allow-tool write or write(<directory>) is given
+- The tools of the MCP servers of --mcp-config allowed by --allow-tool <server>
+  or <server>(<tool>)
+
+The conversation is written to stdout as stream-json lines (the Claude Code format),
+which the log parsers of gh-aw understand. The API key is read from the
+OPENAI_COMPATIBLE_API_KEY environment variable; when it is not set, no Authorization
+header is sent.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` agent --base-url http://localhost:11434/v1 --model qwen2.5-coder --prompt-file prompt.txt
+  ` + string(constants.CLIExtensionPrefix) + ` agent --base-url http://vllm:8000/v1 --model llama --allow-tool "shell(git status)" --prompt-file prompt.txt
+  ` + string(constants.CLIExtensionPrefix) + ` agent --base-url http://vllm:8000/v1 --model llama --mcp-config mcp-servers.json --allow-tool github --prompt-file prompt.txt`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if maxTurns != "" {
+				turns, err := strconv.Atoi(maxTurns)
+				if err != nil || turns < 0 {
+					return fmt.Errorf("invalid --max-turns value '%s': must be a non-negative integer", maxTurns)
+				}
+				config.MaxTurns = turns
+			}
+			config.APIKey = os.Getenv("OPENAI_COMPATIBLE_API_KEY")
+			config.StartupTimeout = durationFromSecondsEnv("GH_AW_STARTUP_TIMEOUT", constants.DefaultMCPStartupTimeout)
+			config.ToolTimeout = durationFromSecondsEnv("GH_AW_TOOL_TIMEOUT", constants.DefaultToolTimeout)
+			return RunAgentLoop(cmd.Context(), config)
+		},
+	}
+
+	cmd.Flags().StringVar(&config.BaseURL, "base-url", "", "Base URL of the OpenAI-compatible API (e.g. http://localhost:11434/v1)")
+	cmd.Flags().StringVarP(&config.Model, "model", "m", "", "Model to use")
+	cmd.Flags().StringVar(&maxTurns, "max-turns", "", "Maximum number of model requests")
+	cmd.Flags().StringVar(&config.MCPConfig, "mcp-config", "", "MCP configuration file (mcpServers JSON)")
+	cmd.Flags().StringArrayVar(&config.AllowTools, "allow-tool", nil, "Allow a tool: shell, shell(<command>), write, write(<directory>), <server> or <server>(<tool>) (can be repeated)")
+	cmd.Flags().StringVar(&config.AgentFile, "agent-file", "", "Custom agent file whose instructions are prepended to the prompt")
+	cmd.Flags().StringVar(&config.PromptFile, "prompt-file", "", "File containing the prompt")
+	_ = cmd.MarkFlagRequired("base-url")
+	_ = cmd.MarkFlagRequired("prompt-file")
+
+	return cmd
+}
+
+// RunAgentLoop reads the prompt and runs the agent loop, writing the stream-json log to stdout
+func RunAgentLoop(ctx context.Context, config AgentLoopConfig) error {
+	agentCommandLog.Printf("Running agent loop: baseURL=%s, model=%s, maxTurns=%d, tools=%d", config.BaseURL, config.Model, config.MaxTurns, len(config.AllowTools))
+
+	if config.Model == "" {
+		return errors.New("no model configured: set 'model' in the engine configuration or the " + constants.EnvVarModelAgentOpenAI + " variable")
+	}
+
+	promptContent, err := os.ReadFile(config.PromptFile)
+	if err != nil {
+		return fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	prompt := string(promptContent)
+
+	if config.AgentFile != "" {
+		agentContent, err := parser.ExtractMarkdown(config.AgentFile)
+		if err != nil {
+			return fmt.Errorf("failed to read agent file: %w", err)
+		}
+		prompt = strings.TrimSpace(agentContent) + "\n\n" + prompt
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	loop := newAgentLoop(config, os.Stdout)
+	defer loop.close()
+
+	if err := loop.run(ctx, prompt); err != nil {
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(err.Error()))
+		return err
+	}
+	return nil
+}
+
+// durationFromSecondsEnv returns the duration of an environment variable in seconds, or the default
+func durationFromSecondsEnv(name string, defaultValue time.Duration) time.Duration {
+	if seconds, err := strconv.Atoi(os.Getenv(name)); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return defaultValue
+}
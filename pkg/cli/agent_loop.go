@@ -0,0 +1,667 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var agentLoopLog = logger.New("cli:agent_loop")
+
+// agentToolOutputLimit is the number of characters of a tool result sent back to the model
+const agentToolOutputLimit = 30000
+
+// errAgentMaxTurns is returned when the agent loop stops at the max-turns limit
+var errAgentMaxTurns = errors.New("reached max turns")
+
+// agentFunctionNamePattern matches the characters that are not allowed in function names
+var agentFunctionNamePattern = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// agentShellMetacharacters are the shell operators rejected when only some commands are allowed
+var agentShellMetacharacters = []string{";", "&", "|", "`", "$(", ">", "<", "\n"}
+
+// chatMessage is a message of the OpenAI chat completions API
+type chatMessage struct {
+	Role       string         `json:"role"`
+	Content    string         `json:"content"`
+	ToolCalls  []chatToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string         `json:"tool_call_id,omitempty"`
+}
+
+// chatToolCall is a function call requested by the model
+type chatToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string `json:"name"`
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+// chatTool is a function definition offered to the model
+type chatTool struct {
+	Type     string           `json:"type"`
+	Function chatToolFunction `json:"function"`
+}
+
+// chatToolFunction describes a function and its JSON schema parameters
+type chatToolFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  any    `json:"parameters"`
+}
+
+// chatCompletionRequest is the body of a chat completions request
+type chatCompletionRequest struct {
+	Model    string        `json:"model"`
+	Messages []chatMessage `json:"messages"`
+	Tools    []chatTool    `json:"tools,omitempty"`
+}
+
+// chatCompletionResponse is the body of a chat completions response
+type chatCompletionResponse struct {
+	ID      string `json:"id"`
+	Choices []struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// agentToolPolicy holds the tools the agent loop is allowed to use, parsed from the
+// --allow-tool values (shell, shell(cmd), write, write(dir), server, server(tool))
+type agentToolPolicy struct {
+	shell         bool
+	shellCommands []string
+	write         bool
+	writeDirs     []string
+	servers       map[string]bool
+	serverTools   map[string][]string
+}
+
+// parseAgentToolPolicy parses the --allow-tool values of the agent command
+func parseAgentToolPolicy(values []string) agentToolPolicy {
+	policy := agentToolPolicy{
+		servers:     make(map[string]bool),
+		serverTools: make(map[string][]string),
+	}
+	for _, value := range values {
+		name, arg, hasArg := strings.Cut(strings.TrimSpace(value), "(")
+		arg = strings.TrimSuffix(arg, ")")
+		switch {
+		case name == "shell" && !hasArg:
+			policy.shell = true
+		case name == "shell":
+			policy.shellCommands = append(policy.shellCommands, arg)
+		case name == "write" && !hasArg:
+			policy.write = true
+		case name == "write":
+			policy.writeDirs = append(policy.writeDirs, filepath.Clean(arg))
+		case !hasArg:
+			policy.servers[name] = true
+		default:
+			policy.serverTools[name] = append(policy.serverTools[name], arg)
+		}
+	}
+	return policy
+}
+
+// allowsShell returns true if the shell tool is available
+func (p agentToolPolicy) allowsShell() bool {
+	return p.shell || len(p.shellCommands) > 0
+}
+
+// allowsCommand returns true if the shell command may run. Allowed commands match exactly,
+// with additional arguments, or by prefix for patterns ending with :* (e.g. git:*).
+func (p agentToolPolicy) allowsCommand(command string) bool {
+	if p.shell {
+		return true
+	}
+	command = strings.TrimSpace(command)
+	for _, metacharacter := range agentShellMetacharacters {
+		if strings.Contains(command, metacharacter) {
+			return false
+		}
+	}
+	for _, pattern := range p.shellCommands {
+		pattern = strings.TrimSuffix(pattern, ":*")
+		if command == pattern || strings.HasPrefix(command, pattern+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsWrites returns true if the file writing tools are available
+func (p agentToolPolicy) allowsWrites() bool {
+	return p.write || len(p.writeDirs) > 0
+}
+
+// allowsWrite returns true if the file at the absolute path may be written
+func (p agentToolPolicy) allowsWrite(path string) bool {
+	if p.write {
+		return true
+	}
+	path = filepath.Clean(path)
+	for _, dir := range p.writeDirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsMCPTool returns true if the tool of the MCP server may be called
+func (p agentToolPolicy) allowsMCPTool(server, tool string) bool {
+	return p.servers[server] || slices.Contains(p.serverTools[server], tool)
+}
+
+// agentTool is a function the model can call
+type agentTool struct {
+	definition chatTool
+	call       func(ctx context.Context, args map[string]any) (string, error)
+}
+
+// agentLoop runs the conversation with an OpenAI-compatible chat completions endpoint.
+// Its output uses the stream-json format of Claude Code (system, assistant, user and
+// result entries) so the existing log parsers understand it.
+type agentLoop struct {
+	config     AgentLoopConfig
+	httpClient *http.Client
+	policy     agentToolPolicy
+	tools      map[string]agentTool
+	mcpServers []map[string]string
+	sessions   []*mcp.ClientSession
+	output     *json.Encoder
+	sessionID  string
+}
+
+// newAgentLoop creates an agent loop writing its stream-json log to output
+func newAgentLoop(config AgentLoopConfig, output io.Writer) *agentLoop {
+	encoder := json.NewEncoder(output)
+	encoder.SetEscapeHTML(false)
+	return &agentLoop{
+		config:     config,
+		httpClient: &http.Client{},
+		policy:     parseAgentToolPolicy(config.AllowTools),
+		tools:      make(map[string]agentTool),
+		output:     encoder,
+		sessionID:  fmt.Sprintf("gh-aw-agent-%d", time.Now().UnixNano()),
+	}
+}
+
+// close closes the MCP sessions of the agent loop
+func (l *agentLoop) close() {
+	for _, session := range l.sessions {
+		_ = session.Close()
+	}
+}
+
+// emit writes a stream-json entry
+func (l *agentLoop) emit(entry map[string]any) {
+	if err := l.output.Encode(entry); err != nil {
+		agentLoopLog.Printf("Failed to write log entry: %v", err)
+	}
+}
+
+// run runs the agent loop until the model answers without tool calls or max-turns is reached
+func (l *agentLoop) run(ctx context.Context, prompt string) error {
+	start := time.Now()
+
+	l.registerBuiltinTools()
+	if l.config.MCPConfig != "" {
+		if err := l.connectMCPServers(ctx); err != nil {
+			return err
+		}
+	}
+
+	toolNames := slices.Sorted(maps.Keys(l.tools))
+	workingDir, _ := os.Getwd()
+	l.emit(map[string]any{
+		"type":        "system",
+		"subtype":     "init",
+		"session_id":  l.sessionID,
+		"model":       l.config.Model,
+		"cwd":         workingDir,
+		"tools":       toolNames,
+		"mcp_servers": l.mcpServers,
+	})
+
+	definitions := make([]chatTool, 0, len(toolNames))
+	for _, name := range toolNames {
+		definitions = append(definitions, l.tools[name].definition)
+	}
+
+	messages := []chatMessage{{Role: "user", Content: prompt}}
+	var inputTokens, outputTokens, turns int
+	finish := func(subtype string, isError bool, result string) {
+		l.emit(map[string]any{
+			"type":        "result",
+			"subtype":     subtype,
+			"is_error":    isError,
+			"session_id":  l.sessionID,
+			"num_turns":   turns,
+			"duration_ms": time.Since(start).Milliseconds(),
+			"result":      result,
+			"usage": map[string]any{
+				"input_tokens":  inputTokens,
+				"output_tokens": outputTokens,
+			},
+		})
+	}
+
+	for {
+		if l.config.MaxTurns > 0 && turns >= l.config.MaxTurns {
+			finish("error_max_turns", true, "")
+			return fmt.Errorf("%w (%d)", errAgentMaxTurns, l.config.MaxTurns)
+		}
+		turns++
+
+		response, err := l.complete(ctx, chatCompletionRequest{Model: l.config.Model, Messages: messages, Tools: definitions})
+		if err != nil {
+			finish("error_during_execution", true, err.Error())
+			return err
+		}
+		if len(response.Choices) == 0 {
+			err := errors.New("chat completions response has no choices")
+			finish("error_during_execution", true, err.Error())
+			return err
+		}
+		inputTokens += response.Usage.PromptTokens
+		outputTokens += response.Usage.CompletionTokens
+
+		message := response.Choices[0].Message
+		message.Role = "assistant"
+		for i := range message.ToolCalls {
+			if message.ToolCalls[i].ID == "" {
+				message.ToolCalls[i].ID = fmt.Sprintf("call_%d_%d", turns, i)
+			}
+			message.ToolCalls[i].Type = "function"
+		}
+		messages = append(messages, message)
+		l.emitAssistant(turns, message, response)
+
+		if len(message.ToolCalls) == 0 {
+			finish("success", false, message.Content)
+			return nil
+		}
+
+		for _, toolCall := range message.ToolCalls {
+			result, isError := l.callTool(ctx, toolCall)
+			l.emit(map[string]any{
+				"type": "user",
+				"message": map[string]any{
+					"role": "user",
+					"content": []any{map[string]any{
+						"type":        "tool_result",
+						"tool_use_id": toolCall.ID,
+						"content":     result,
+						"is_error":    isError,
+					}},
+				},
+			})
+			messages = append(messages, chatMessage{Role: "tool", Content: result, ToolCallID: toolCall.ID})
+		}
+	}
+}
+
+// emitAssistant writes the assistant entry of a model response
+func (l *agentLoop) emitAssistant(turn int, message chatMessage, response *chatCompletionResponse) {
+	var content []any
+	if message.Content != "" {
+		content = append(content, map[string]any{"type": "text", "text": message.Content})
+	}
+	for _, toolCall := range message.ToolCalls {
+		content = append(content, map[string]any{
+			"type":  "tool_use",
+			"id":    toolCall.ID,
+			"name":  toolCall.Function.Name,
+			"input": parseToolCallArguments(toolCall.Function.Arguments),
+		})
+	}
+	l.emit(map[string]any{
+		"type":       "assistant",
+		"session_id": l.sessionID,
+		"message": map[string]any{
+			"id":      fmt.Sprintf("msg_%s_%d", l.sessionID, turn),
+			"type":    "message",
+			"role":    "assistant",
+			"model":   l.config.Model,
+			"content": content,
+			"usage": map[string]any{
+				"input_tokens":  response.Usage.PromptTokens,
+				"output_tokens": response.Usage.CompletionTokens,
+			},
+		},
+	})
+}
+
+// complete sends a chat completions request
+func (l *agentLoop) complete(ctx context.Context, request chatCompletionRequest) (*chatCompletionResponse, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode chat completions request: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(l.config.BaseURL, "/") + "/chat/completions"
+	agentLoopLog.Printf("Sending chat completions request: endpoint=%s, messages=%d", endpoint, len(request.Messages))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("chat completions request failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if l.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+l.config.APIKey)
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat completions request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("chat completions request failed: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("chat completions request failed: %s: %s", resp.Status, truncateAgentOutput(strings.TrimSpace(string(responseBody)), 500))
+	}
+
+	var response chatCompletionResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode chat completions response: %w", err)
+	}
+	return &response, nil
+}
+
+// callTool runs a tool call and returns its result and whether it failed
+func (l *agentLoop) callTool(ctx context.Context, toolCall chatToolCall) (string, bool) {
+	tool, ok := l.tools[toolCall.Function.Name]
+	if !ok {
+		return fmt.Sprintf("unknown tool: %s", toolCall.Function.Name), true
+	}
+
+	args, ok := parseToolCallArguments(toolCall.Function.Arguments).(map[string]any)
+	if !ok {
+		return fmt.Sprintf("invalid arguments for %s: expected a JSON object", toolCall.Function.Name), true
+	}
+
+	agentLoopLog.Printf("Calling tool: %s", toolCall.Function.Name)
+	toolCtx, cancel := context.WithTimeout(ctx, l.config.ToolTimeout)
+	defer cancel()
+	result, err := tool.call(toolCtx, args)
+	if err != nil {
+		if result != "" {
+			return truncateAgentOutput(result+"\n"+err.Error(), agentToolOutputLimit), true
+		}
+		return err.Error(), true
+	}
+	return truncateAgentOutput(result, agentToolOutputLimit), false
+}
+
+// parseToolCallArguments decodes the JSON arguments of a tool call, keeping invalid JSON as a string
+func parseToolCallArguments(arguments string) any {
+	if strings.TrimSpace(arguments) == "" {
+		return map[string]any{}
+	}
+	var args any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		return arguments
+	}
+	return args
+}
+
+// registerBuiltinTools registers the file and shell tools allowed by the policy.
+// The tools use the names of the Claude Code tools so log parsers render them alike.
+func (l *agentLoop) registerBuiltinTools() {
+	l.addTool("Read", "Read a file of the workspace. Relative paths are resolved from the working directory.",
+		objectSchema(map[string]any{"file_path": stringSchema("Path of the file to read")}, "file_path"),
+		func(ctx context.Context, args map[string]any) (string, error) {
+			content, err := os.ReadFile(stringArg(args, "file_path"))
+			if err != nil {
+				return "", err
+			}
+			return string(content), nil
+		})
+
+	if l.policy.allowsShell() {
+		description := "Run a bash command in the working directory and return its output."
+		if !l.policy.shell {
+			description += " Only these commands are allowed: " + strings.Join(l.policy.shellCommands, ", ")
+		}
+		l.addTool("Bash", description,
+			objectSchema(map[string]any{"command": stringSchema("The bash command to run")}, "command"),
+			func(ctx context.Context, args map[string]any) (string, error) {
+				command := stringArg(args, "command")
+				if !l.policy.allowsCommand(command) {
+					return "", fmt.Errorf("command not allowed: %s", command)
+				}
+				output, err := exec.CommandContext(ctx, "bash", "-c", command).CombinedOutput()
+				return string(output), err
+			})
+	}
+
+	if l.policy.allowsWrites() {
+		l.addTool("Write", "Create or overwrite a file with the given content.",
+			objectSchema(map[string]any{
+				"file_path": stringSchema("Path of the file to write"),
+				"content":   stringSchema("The content of the file"),
+			}, "file_path", "content"),
+			func(ctx context.Context, args map[string]any) (string, error) {
+				path, err := l.writablePath(stringArg(args, "file_path"))
+				if err != nil {
+					return "", err
+				}
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					return "", err
+				}
+				if err := os.WriteFile(path, []byte(stringArg(args, "content")), 0644); err != nil {
+					return "", err
+				}
+				return "Wrote " + path, nil
+			})
+
+		l.addTool("Edit", "Replace the single occurrence of old_string with new_string in a file.",
+			objectSchema(map[string]any{
+				"file_path":  stringSchema("Path of the file to edit"),
+				"old_string": stringSchema("The exact text to replace"),
+				"new_string": stringSchema("The replacement text"),
+			}, "file_path", "old_string", "new_string"),
+			func(ctx context.Context, args map[string]any) (string, error) {
+				path, err := l.writablePath(stringArg(args, "file_path"))
+				if err != nil {
+					return "", err
+				}
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return "", err
+				}
+				oldString := stringArg(args, "old_string")
+				if count := strings.Count(string(content), oldString); oldString == "" || count != 1 {
+					return "", fmt.Errorf("old_string must occur exactly once in %s (found %d occurrences)", path, count)
+				}
+				updated := strings.Replace(string(content), oldString, stringArg(args, "new_string"), 1)
+				if err := os.WriteFile(path, []byte(updated), 0644); err != nil {
+					return "", err
+				}
+				return "Edited " + path, nil
+			})
+	}
+}
+
+// writablePath resolves a path and checks that the policy allows writing it
+func (l *agentLoop) writablePath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if !l.policy.allowsWrite(absPath) {
+		return "", fmt.Errorf("writing %s is not allowed", absPath)
+	}
+	return absPath, nil
+}
+
+// connectMCPServers connects to the MCP servers of the configuration and registers their allowed tools
+func (l *agentLoop) connectMCPServers(ctx context.Context) error {
+	content, err := os.ReadFile(l.config.MCPConfig)
+	if err != nil {
+		return fmt.Errorf("failed to read MCP configuration: %w", err)
+	}
+	var config struct {
+		MCPServers map[string]parser.MCPServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(content, &config); err != nil {
+		return fmt.Errorf("failed to parse MCP configuration %s: %w", l.config.MCPConfig, err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gh-aw-agent", Version: GetVersion()}, nil)
+	for _, name := range slices.Sorted(maps.Keys(config.MCPServers)) {
+		serverConfig := config.MCPServers[name]
+		serverConfig.Name = name
+		if serverConfig.Type == "" {
+			serverConfig.Type = "stdio"
+			if serverConfig.URL != "" {
+				serverConfig.Type = "http"
+			}
+		}
+
+		count, err := l.connectMCPServer(ctx, client, serverConfig)
+		if err != nil {
+			agentLoopLog.Printf("Failed to connect to MCP server %s: %v", name, err)
+			fmt.Fprintf(os.Stderr, "Failed to connect to MCP server %s: %v\n", name, err)
+			l.mcpServers = append(l.mcpServers, map[string]string{"name": name, "status": "failed"})
+			continue
+		}
+		agentLoopLog.Printf("Connected to MCP server %s: %d allowed tools", name, count)
+		l.mcpServers = append(l.mcpServers, map[string]string{"name": name, "status": "connected"})
+	}
+	return nil
+}
+
+// connectMCPServer connects to an MCP server and registers the tools allowed by the policy
+func (l *agentLoop) connectMCPServer(ctx context.Context, client *mcp.Client, serverConfig parser.MCPServerConfig) (int, error) {
+	transport, err := newMCPTransport(serverConfig)
+	if err != nil {
+		return 0, err
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, l.config.StartupTimeout)
+	defer cancel()
+	session, err := client.Connect(connectCtx, transport, nil)
+	if err != nil {
+		return 0, err
+	}
+	l.sessions = append(l.sessions, session)
+
+	toolsResult, err := session.ListTools(connectCtx, &mcp.ListToolsParams{})
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, tool := range toolsResult.Tools {
+		if !l.policy.allowsMCPTool(serverConfig.Name, tool.Name) {
+			continue
+		}
+		var schema any = map[string]any{"type": "object", "properties": map[string]any{}}
+		if tool.InputSchema != nil {
+			schema = tool.InputSchema
+		}
+		toolName := tool.Name
+		l.addTool(mcpFunctionName(serverConfig.Name, tool.Name), tool.Description, schema,
+			func(ctx context.Context, args map[string]any) (string, error) {
+				result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: toolName, Arguments: args})
+				if err != nil {
+					return "", err
+				}
+				text := mcpToolResultText(result)
+				if result.IsError {
+					return "", errors.New(text)
+				}
+				return text, nil
+			})
+		count++
+	}
+	return count, nil
+}
+
+// addTool registers a tool under a function name
+func (l *agentLoop) addTool(name, description string, parameters any, call func(ctx context.Context, args map[string]any) (string, error)) {
+	l.tools[name] = agentTool{
+		definition: chatTool{
+			Type:     "function",
+			Function: chatToolFunction{Name: name, Description: description, Parameters: parameters},
+		},
+		call: call,
+	}
+}
+
+// mcpFunctionName returns the function name of an MCP tool, using the mcp__server__tool
+// naming of Claude Code restricted to the characters allowed in function names
+func mcpFunctionName(server, tool string) string {
+	name := agentFunctionNamePattern.ReplaceAllString(fmt.Sprintf("mcp__%s__%s", server, tool), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// mcpToolResultText returns the text content of an MCP tool result
+func mcpToolResultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, content := range result.Content {
+		if text, ok := content.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	if len(parts) == 0 && result.StructuredContent != nil {
+		if structured, err := json.Marshal(result.StructuredContent); err == nil {
+			parts = append(parts, string(structured))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// objectSchema returns the JSON schema of an object with the given properties
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+// stringSchema returns the JSON schema of a string property
+func stringSchema(description string) map[string]any {
+	return map[string]any{"type": "string", "description": description}
+}
+
+// stringArg returns a string argument of a tool call
+func stringArg(args map[string]any, name string) string {
+	value, _ := args[name].(string)
+	return value
+}
+
+// truncateAgentOutput truncates text to limit characters
+func truncateAgentOutput(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	return text[:limit] + fmt.Sprintf("\n... (truncated %d characters)", len(text)-limit)
+}
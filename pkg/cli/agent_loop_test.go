@@ -0,0 +1,197 @@
+//go:build !integration
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgentToolPolicy(t *testing.T) {
+	policy := parseAgentToolPolicy([]string{"shell(git status)", "shell(git:*)", "write(/tmp/gh-aw/cache-memory)", "github(issue_read)", "notes"})
+
+	assert.True(t, policy.allowsShell())
+	assert.True(t, policy.allowsCommand("git status"))
+	assert.True(t, policy.allowsCommand("git log --oneline"))
+	assert.False(t, policy.allowsCommand("ls"))
+	assert.False(t, policy.allowsCommand("git status; rm -rf /"), "shell metacharacters should be rejected")
+	assert.False(t, policy.allowsCommand("git log $(whoami)"))
+
+	assert.True(t, policy.allowsWrites())
+	assert.True(t, policy.allowsWrite("/tmp/gh-aw/cache-memory/notes.md"))
+	assert.False(t, policy.allowsWrite("/tmp/gh-aw/cache-memory-other/notes.md"))
+	assert.False(t, policy.allowsWrite("/etc/passwd"))
+
+	assert.True(t, policy.allowsMCPTool("github", "issue_read"))
+	assert.False(t, policy.allowsMCPTool("github", "create_issue"))
+	assert.True(t, policy.allowsMCPTool("notes", "anything"))
+
+	unrestricted := parseAgentToolPolicy([]string{"shell", "write"})
+	assert.True(t, unrestricted.allowsCommand("ls | wc -l"))
+	assert.True(t, unrestricted.allowsWrite("/etc/hosts"))
+
+	none := parseAgentToolPolicy(nil)
+	assert.False(t, none.allowsShell())
+	assert.False(t, none.allowsWrites())
+}
+
+func TestMCPFunctionName(t *testing.T) {
+	assert.Equal(t, "mcp__github__issue_read", mcpFunctionName("github", "issue_read"))
+	assert.Equal(t, "mcp__my_server__get_item", mcpFunctionName("my.server", "get item"))
+	assert.Len(t, mcpFunctionName("server", string(bytes.Repeat([]byte("x"), 100))), 64)
+}
+
+// newChatCompletionsStub returns a server answering chat completions requests with the responses in order
+func newChatCompletionsStub(t *testing.T, responses ...chatCompletionResponse) (*httptest.Server, *[]chatCompletionRequest) {
+	t.Helper()
+	var requests []chatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/chat/completions", r.URL.Path)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		var request chatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		requests = append(requests, request)
+
+		response := responses[min(len(requests), len(responses))-1]
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func chatResponse(content string, promptTokens, completionTokens int, toolCalls ...chatToolCall) chatCompletionResponse {
+	var response chatCompletionResponse
+	response.Choices = make([]struct {
+		Message      chatMessage `json:"message"`
+		FinishReason string      `json:"finish_reason"`
+	}, 1)
+	response.Choices[0].Message = chatMessage{Role: "assistant", Content: content, ToolCalls: toolCalls}
+	response.Usage.PromptTokens = promptTokens
+	response.Usage.CompletionTokens = completionTokens
+	return response
+}
+
+func toolCall(id, name string, args map[string]any) chatToolCall {
+	call := chatToolCall{ID: id, Type: "function"}
+	call.Function.Name = name
+	arguments, _ := json.Marshal(args)
+	call.Function.Arguments = string(arguments)
+	return call
+}
+
+func TestAgentLoopRun(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "agent-loop")
+	notePath := filepath.Join(tmpDir, "memory", "note.md")
+
+	server, requests := newChatCompletionsStub(t,
+		chatResponse("", 100, 20, toolCall("call_1", "Write", map[string]any{"file_path": notePath, "content": "hello"})),
+		chatResponse("Saved the note.", 150, 10),
+	)
+
+	var output bytes.Buffer
+	loop := newAgentLoop(AgentLoopConfig{
+		BaseURL:    server.URL + "/v1",
+		Model:      "qwen",
+		APIKey:     "test-key",
+		AllowTools: []string{"write(" + filepath.Join(tmpDir, "memory") + ")"},
+	}, &output)
+	defer loop.close()
+
+	require.NoError(t, loop.run(context.Background(), "Save a note"))
+
+	content, err := os.ReadFile(notePath)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	require.Len(t, *requests, 2)
+	assert.Equal(t, "qwen", (*requests)[0].Model)
+	assert.Len(t, (*requests)[0].Tools, 3, "Read, Write and Edit should be offered")
+	lastMessages := (*requests)[1].Messages
+	require.Len(t, lastMessages, 3)
+	assert.Equal(t, "tool", lastMessages[2].Role)
+	assert.Equal(t, "call_1", lastMessages[2].ToolCallID)
+
+	metrics := workflow.NewOpenAIEngine().ParseLogMetrics(output.String(), false)
+	assert.Equal(t, 2, metrics.Turns)
+	assert.Equal(t, 280, metrics.TokenUsage)
+	assert.Contains(t, output.String(), `"subtype":"success"`)
+}
+
+func TestAgentLoopRejectsDisallowedTools(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "agent-loop-denied")
+	outsidePath := filepath.Join(tmpDir, "outside.md")
+
+	server, requests := newChatCompletionsStub(t,
+		chatResponse("", 10, 5,
+			toolCall("call_1", "Bash", map[string]any{"command": "rm -rf /tmp/nothing"}),
+			toolCall("call_2", "Write", map[string]any{"file_path": outsidePath, "content": "x"})),
+		chatResponse("Done.", 10, 5),
+	)
+
+	var output bytes.Buffer
+	loop := newAgentLoop(AgentLoopConfig{
+		BaseURL:    server.URL + "/v1",
+		Model:      "qwen",
+		APIKey:     "test-key",
+		AllowTools: []string{"shell(git status)", "write(" + filepath.Join(tmpDir, "memory") + ")"},
+	}, &output)
+	defer loop.close()
+
+	require.NoError(t, loop.run(context.Background(), "Try something"))
+
+	assert.NoFileExists(t, outsidePath)
+	messages := (*requests)[1].Messages
+	require.Len(t, messages, 4)
+	assert.Contains(t, messages[2].Content, "command not allowed")
+	assert.Contains(t, messages[3].Content, "is not allowed")
+	assert.Contains(t, output.String(), `"is_error":true`)
+}
+
+func TestAgentLoopMaxTurns(t *testing.T) {
+	server, requests := newChatCompletionsStub(t,
+		chatResponse("", 10, 5, toolCall("", "Read", map[string]any{"file_path": "missing.txt"})),
+	)
+
+	var output bytes.Buffer
+	loop := newAgentLoop(AgentLoopConfig{
+		BaseURL:  server.URL + "/v1",
+		Model:    "qwen",
+		APIKey:   "test-key",
+		MaxTurns: 2,
+	}, &output)
+	defer loop.close()
+
+	err := loop.run(context.Background(), "Loop forever")
+	require.ErrorIs(t, err, errAgentMaxTurns)
+	assert.Len(t, *requests, 2)
+	assert.Contains(t, output.String(), `"subtype":"error_max_turns"`)
+}
+
+func TestAgentLoopRequestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid api key", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	loop := newAgentLoop(AgentLoopConfig{BaseURL: server.URL, Model: "qwen"}, &output)
+	defer loop.close()
+
+	err := loop.run(context.Background(), "Hello")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "chat completions request failed: 401")
+	assert.Contains(t, output.String(), `"subtype":"error_during_execution"`)
+}
@@ -316,6 +316,18 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract MCP tool usage: %v", err)))
 	}
 
+	// Extract LLM usage from the api-proxy usage log if available
+	llmUsage, err := parseLLMUsageLogs(runOutputDir, loadRepositoryModelPrices(), verbose)
+	if err != nil && verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract LLM usage: %v", err)))
+	}
+
+	// Report the accesses to paths protected by the sandbox filesystem policy
+	filesystemViolations, err := analyzeFilesystemViolations(runOutputDir, verbose)
+	if err != nil && verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to analyze filesystem violations: %v", err)))
+	}
+
 	// List all artifacts
 	artifacts, err := listArtifacts(runOutputDir)
 	if err != nil && verbose {
@@ -331,6 +343,8 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		MissingData:             missingData,
 		Noops:                   noops,
 		MCPFailures:             mcpFailures,
+		LLMUsage:                llmUsage,
+		FilesystemViolations:    filesystemViolations,
 		JobDetails:              jobDetails,
 	}
 
@@ -404,6 +418,7 @@ func AuditWorkflowRun(ctx context.Context, runID int64, owner, repo, hostname st
 		MCPFailures:             mcpFailures,
 		ArtifactsList:           artifacts,
 		JobDetails:              jobDetails,
+		EngineAttempts:          extractEngineAttemptsFromAwInfo(filepath.Join(runOutputDir, "aw_info.json"), false),
 	}
 
 	if err := saveRunSummary(runOutputDir, summary, verbose); err != nil {
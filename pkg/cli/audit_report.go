@@ -34,6 +34,8 @@ type AuditData struct {
 	Warnings                []ErrorInfo              `json:"warnings,omitempty"`
 	ToolUsage               []ToolUsageInfo          `json:"tool_usage,omitempty"`
 	MCPToolUsage            *MCPToolUsageData        `json:"mcp_tool_usage,omitempty"`
+	LLMUsage                *LLMUsageSummary         `json:"llm_usage,omitempty"`
+	FilesystemViolations    []FilesystemViolation    `json:"filesystem_violations,omitempty"`
 }
 
 // Finding represents a key insight discovered during audit
@@ -146,6 +148,7 @@ type MCPToolSummary struct {
 	AvgDuration     string `json:"avg_duration,omitempty" console:"header:Avg Duration,omitempty"`
 	MaxDuration     string `json:"max_duration,omitempty" console:"header:Max Duration,omitempty"`
 	ErrorCount      int    `json:"error_count,omitempty" console:"header:Errors,omitempty"`
+	RateLimited     int    `json:"rate_limited_count,omitempty" console:"header:Rate Limited,omitempty"`
 }
 
 // MCPToolCall represents a single MCP tool call with full details
@@ -170,6 +173,7 @@ type MCPServerStats struct {
 	TotalOutputSize int    `json:"total_output_size" console:"header:Total Output,format:number"`
 	AvgDuration     string `json:"avg_duration,omitempty" console:"header:Avg Duration,omitempty"`
 	ErrorCount      int    `json:"error_count,omitempty" console:"header:Errors,omitempty"`
+	RateLimited     int    `json:"rate_limited_count,omitempty" console:"header:Rate Limited,omitempty"`
 }
 
 // OverviewDisplay is a display-optimized version of OverviewData for console rendering
@@ -323,6 +327,8 @@ func buildAuditData(processedRun ProcessedRun, metrics LogMetrics, mcpToolUsage
 		Warnings:                warnings,
 		ToolUsage:               toolUsage,
 		MCPToolUsage:            mcpToolUsage,
+		LLMUsage:                processedRun.LLMUsage,
+		FilesystemViolations:    processedRun.FilesystemViolations,
 	}
 }
 
@@ -133,6 +133,21 @@ func generateFindings(processedRun ProcessedRun, metrics MetricsData, errors []E
 		})
 	}
 
+	// Sandbox filesystem policy findings
+	if len(processedRun.FilesystemViolations) > 0 {
+		paths := make(map[string]bool)
+		for _, violation := range processedRun.FilesystemViolations {
+			paths[violation.Path] = true
+		}
+		findings = append(findings, Finding{
+			Category:    "security",
+			Severity:    "high",
+			Title:       "Sandbox Filesystem Policy Violations",
+			Description: fmt.Sprintf("%d attempts to access %d paths protected by the sandbox filesystem policy", len(processedRun.FilesystemViolations), len(paths)),
+			Impact:      "The agent tried to modify read-only paths or read denied paths",
+		})
+	}
+
 	// Success findings
 	if run.Conclusion == "success" && len(errors) == 0 {
 		findings = append(findings, Finding{
@@ -119,6 +119,14 @@ func renderConsole(data AuditData, logsPath string) {
 		renderFirewallAnalysis(data.FirewallAnalysis)
 	}
 
+	// Sandbox Filesystem Violations Section
+	if len(data.FilesystemViolations) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("🛡️ Sandbox Filesystem Violations"))
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprint(os.Stderr, console.RenderStruct(data.FilesystemViolations))
+		fmt.Fprintln(os.Stderr)
+	}
+
 	// Redacted Domains Section
 	if data.RedactedDomainsAnalysis != nil && data.RedactedDomainsAnalysis.TotalDomains > 0 {
 		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("🔒 Redacted URL Domains"))
@@ -140,6 +148,14 @@ func renderConsole(data AuditData, logsPath string) {
 		renderMCPToolUsageTable(data.MCPToolUsage)
 	}
 
+	// LLM Usage Section - per-model usage from the api-proxy usage log
+	if data.LLMUsage != nil && len(data.LLMUsage.Models) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("LLM Usage"))
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprint(os.Stderr, renderLLMUsageTable(data.LLMUsage))
+		fmt.Fprintln(os.Stderr)
+	}
+
 	// Errors and Warnings Section
 	if len(data.Errors) > 0 || len(data.Warnings) > 0 {
 		fmt.Fprintln(os.Stderr, console.FormatSectionHeader("Errors and Warnings"))
@@ -94,6 +94,7 @@ func createAndConfigureCompiler(config CompileConfig) *workflow.Compiler {
 	compiler := workflow.NewCompiler(
 		workflow.WithVerbose(config.Verbose),
 		workflow.WithEngineOverride(config.EngineOverride),
+		workflow.WithModelOverride(config.ModelOverride),
 		workflow.WithFailFast(config.FailFast),
 	)
 	compileCompilerSetupLog.Print("Created compiler instance")
@@ -136,6 +137,12 @@ func configureCompilerFlags(compiler *workflow.Compiler, config CompileConfig) {
 		}
 	}
 
+	// Override the MCP gateway record/replay configuration (gh aw trial --mcp-record / --mcp-replay)
+	if config.MCPCassette != nil {
+		compileCompilerSetupLog.Printf("Setting MCP cassette override: mode=%s", config.MCPCassette.Mode)
+		compiler.SetMCPCassette(config.MCPCassette)
+	}
+
 	// Set refresh stop time flag
 	compiler.SetRefreshStopTime(config.RefreshStopTime)
 	if config.RefreshStopTime {
@@ -3,6 +3,7 @@ package cli
 import (
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
 )
 
 var compileConfigLog = logger.New("cli:compile_config")
@@ -12,6 +13,7 @@ type CompileConfig struct {
 	MarkdownFiles          []string // Files to compile (empty for all files)
 	Verbose                bool     // Enable verbose output
 	EngineOverride         string   // Override AI engine setting
+	ModelOverride          string   // Override AI model setting
 	Validate               bool     // Enable schema validation
 	Watch                  bool     // Enable watch mode
 	WorkflowDir            string   // Custom workflow directory
@@ -33,6 +35,14 @@ type CompileConfig struct {
 	ActionTag              string   // Override action SHA or tag for actions/setup (overrides action-mode to release)
 	Stats                  bool     // Display statistics table sorted by file size
 	FailFast               bool     // Stop at first error instead of collecting all errors
+	Policy                 string   // Organization policy file (local path or workflowspec); defaults to .github/aw/policy.yml when present
+	SBOM                   string   // Write a software bill of materials next to each lock file in this format (cyclonedx or spdx)
+	PromptReport           bool     // Print the prompt size of each workflow by section
+	PromptEvent            string   // Sample event used to render the prompt report
+	PromptBudget           int      // Warn when the estimated prompt exceeds this many tokens (implies PromptReport)
+
+	// MCPCassette overrides the MCP gateway record/replay configuration (gh aw trial --mcp-record / --mcp-replay)
+	MCPCassette *workflow.MCPCassetteConfig
 }
 
 // WorkflowFailure represents a failed workflow with its error count
@@ -60,6 +60,33 @@ func CompileWorkflows(ctx context.Context, config CompileConfig) ([]*workflow.Wo
 	// Create and configure compiler
 	compiler := createAndConfigureCompiler(config)
 
+	// Load the organization policy (if any) so it is evaluated for every workflow
+	policy, err := loadPolicy(config.Policy)
+	if err != nil {
+		return nil, err
+	}
+	if policy != nil {
+		compileOrchestratorLog.Printf("Using organization policy: %s (%d rules)", policy.Source, len(policy.Rules))
+		compiler.SetPolicy(policy)
+	}
+
+	// Enable the compile-time SBOM artifact if requested
+	if config.SBOM != "" {
+		sbomFormat, err := workflow.ParseSBOMFormat(config.SBOM)
+		if err != nil {
+			return nil, err
+		}
+		compiler.SetSBOMFormat(sbomFormat)
+	}
+
+	// Enable the prompt size report if requested
+	if config.PromptReport || config.PromptEvent != "" || config.PromptBudget != 0 {
+		if config.PromptBudget < 0 {
+			return nil, fmt.Errorf("--prompt-budget must be a positive number of tokens, got %d", config.PromptBudget)
+		}
+		compiler.SetPromptReport(&workflow.PromptReportOptions{Event: config.PromptEvent, Budget: config.PromptBudget})
+	}
+
 	// Handle watch mode (early return)
 	if config.Watch {
 		// Watch mode: watch for file changes and recompile automatically
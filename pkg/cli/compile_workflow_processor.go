@@ -113,6 +113,20 @@ func compileWorkflowFile(
 			return result
 		}
 
+		// Workflow templates are instantiated with 'gh aw instantiate', not compiled
+		if templateErr, ok := err.(*workflow.TemplateWorkflowError); ok {
+			if !jsonOutput {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(templateErr.Error()))
+			}
+			result.validationResult.Valid = true
+			result.validationResult.Warnings = append(result.validationResult.Warnings, CompileValidationError{
+				Type:    "workflow_template",
+				Message: "Skipped: Workflow template (instantiate with 'gh aw instantiate')",
+			})
+			result.success = true
+			return result
+		}
+
 		// Don't print error here - it will be displayed in the compilation summary
 		// The error is stored in ValidationResult for JSON output and summary display
 		result.validationResult.Valid = false
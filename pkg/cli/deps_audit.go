@@ -0,0 +1,450 @@
+package cli
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var depsAuditLog = logger.New("cli:deps_audit")
+
+// osvAPIQueryURL is the OSV query endpoint used when no local advisory database is given
+const osvAPIQueryURL = "https://api.osv.dev/v1/query"
+
+// osvContainerEcosystem is the ecosystem name used to match container images in a local
+// advisory database (OSV has no official container ecosystem)
+const osvContainerEcosystem = "Docker"
+
+// OSVVulnerability is an advisory record in the OSV schema (https://ossf.github.io/osv-schema/)
+type OSVVulnerability struct {
+	ID               string           `json:"id"`
+	Summary          string           `json:"summary,omitempty"`
+	Details          string           `json:"details,omitempty"`
+	Aliases          []string         `json:"aliases,omitempty"`
+	Withdrawn        string           `json:"withdrawn,omitempty"`
+	Severity         []OSVSeverity    `json:"severity,omitempty"`
+	Affected         []OSVAffected    `json:"affected,omitempty"`
+	DatabaseSpecific *OSVDatabaseInfo `json:"database_specific,omitempty"`
+}
+
+// OSVSeverity is a severity score (CVSS vector) of an OSV record
+type OSVSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// OSVAffected describes the affected versions of one package
+type OSVAffected struct {
+	Package          OSVPackage       `json:"package"`
+	Ranges           []OSVRange       `json:"ranges,omitempty"`
+	Versions         []string         `json:"versions,omitempty"`
+	DatabaseSpecific *OSVDatabaseInfo `json:"database_specific,omitempty"`
+}
+
+// OSVPackage identifies a package in an ecosystem
+type OSVPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl,omitempty"`
+}
+
+// OSVRange is a range of affected versions described by introduced/fixed events
+type OSVRange struct {
+	Type   string     `json:"type"`
+	Events []OSVEvent `json:"events"`
+}
+
+// OSVEvent is a single range event
+type OSVEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// OSVDatabaseInfo holds database specific fields; GitHub advisories record the severity here
+type OSVDatabaseInfo struct {
+	Severity string `json:"severity,omitempty"`
+}
+
+// AdvisoryDatabase finds advisories affecting a package version
+type AdvisoryDatabase interface {
+	Query(ecosystem, name, version string) ([]OSVVulnerability, error)
+}
+
+// localAdvisoryDatabase is an OSV advisory database loaded from a directory of
+// JSON records and/or OSV ecosystem zip exports (e.g. npm/all.zip)
+type localAdvisoryDatabase struct {
+	byPackage map[string][]OSVVulnerability
+}
+
+// LoadAdvisoryDatabase loads OSV records from a directory (recursively) or a single .json/.zip file
+func LoadAdvisoryDatabase(path string) (AdvisoryDatabase, error) {
+	depsAuditLog.Printf("Loading advisory database from %s", path)
+
+	db := &localAdvisoryDatabase{byPackage: make(map[string][]OSVVulnerability)}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("advisory database not found: %w", err)
+	}
+
+	if !info.IsDir() {
+		if err := db.loadFile(path); err != nil {
+			return nil, err
+		}
+	} else {
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, walkErr error) error {
+			if walkErr != nil {
+				return walkErr
+			}
+			if d.IsDir() {
+				return nil
+			}
+			return db.loadFile(p)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	depsAuditLog.Printf("Loaded advisories for %d packages", len(db.byPackage))
+	return db, nil
+}
+
+// loadFile loads an OSV .json record or every .json record in a .zip export; other files are ignored
+func (db *localAdvisoryDatabase) loadFile(path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read advisory %s: %w", path, err)
+		}
+		return db.addRecord(content, path)
+	case ".zip":
+		reader, err := zip.OpenReader(path)
+		if err != nil {
+			return fmt.Errorf("failed to open advisory archive %s: %w", path, err)
+		}
+		defer reader.Close()
+		for _, file := range reader.File {
+			if !strings.HasSuffix(strings.ToLower(file.Name), ".json") {
+				continue
+			}
+			rc, err := file.Open()
+			if err != nil {
+				return fmt.Errorf("failed to read %s in %s: %w", file.Name, path, err)
+			}
+			content, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("failed to read %s in %s: %w", file.Name, path, err)
+			}
+			if err := db.addRecord(content, path+":"+file.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (db *localAdvisoryDatabase) addRecord(content []byte, source string) error {
+	var vuln OSVVulnerability
+	if err := json.Unmarshal(content, &vuln); err != nil {
+		return fmt.Errorf("invalid OSV record %s: %w", source, err)
+	}
+	if vuln.ID == "" || vuln.Withdrawn != "" {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	for _, affected := range vuln.Affected {
+		key := advisoryPackageKey(affected.Package.Ecosystem, affected.Package.Name)
+		if !seen[key] {
+			db.byPackage[key] = append(db.byPackage[key], vuln)
+			seen[key] = true
+		}
+	}
+	return nil
+}
+
+// Query returns the advisories affecting the given package version
+func (db *localAdvisoryDatabase) Query(ecosystem, name, version string) ([]OSVVulnerability, error) {
+	var matches []OSVVulnerability
+	for _, vuln := range db.byPackage[advisoryPackageKey(ecosystem, name)] {
+		if vulnerabilityAffects(vuln, ecosystem, name, version) {
+			matches = append(matches, vuln)
+		}
+	}
+	return matches, nil
+}
+
+// osvAPIDatabase queries the public OSV API
+type osvAPIDatabase struct {
+	client *http.Client
+	url    string
+}
+
+// Query returns the advisories affecting the given package version according to the OSV API
+func (db *osvAPIDatabase) Query(ecosystem, name, version string) ([]OSVVulnerability, error) {
+	if ecosystem == osvContainerEcosystem {
+		// Container images are only supported with a local advisory database
+		return nil, nil
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"version": version,
+		"package": map[string]string{"ecosystem": ecosystem, "name": name},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := db.client.Post(db.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query OSV for %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OSV API returned status %d for %s", resp.StatusCode, name)
+	}
+
+	var result struct {
+		Vulns []OSVVulnerability `json:"vulns"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode OSV response: %w", err)
+	}
+	return result.Vulns, nil
+}
+
+// advisoryPackageKey normalizes a package identity for lookups
+func advisoryPackageKey(ecosystem, name string) string {
+	if strings.EqualFold(ecosystem, "PyPI") {
+		// PEP 503 normalization
+		name = strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(name))
+	}
+	return strings.ToLower(ecosystem) + "/" + name
+}
+
+// vulnerabilityAffects reports whether the advisory affects the package version
+func vulnerabilityAffects(vuln OSVVulnerability, ecosystem, name, version string) bool {
+	key := advisoryPackageKey(ecosystem, name)
+	for _, affected := range vuln.Affected {
+		if advisoryPackageKey(affected.Package.Ecosystem, affected.Package.Name) != key {
+			continue
+		}
+		if slices.Contains(affected.Versions, version) {
+			return true
+		}
+		for _, r := range affected.Ranges {
+			if r.Type != "GIT" && versionInOSVRange(ecosystem, version, r.Events) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// versionInOSVRange evaluates introduced/fixed/last_affected events against a version of a
+// package in the given ecosystem. Versions that are not comparable (e.g. non-semver tags)
+// are never considered in range.
+func versionInOSVRange(ecosystem, version string, events []OSVEvent) bool {
+	if _, ok := compareAdvisoryVersions(ecosystem, version, version); !ok {
+		return false
+	}
+
+	affected := false
+	for _, event := range events {
+		switch {
+		case event.Introduced != "":
+			if event.Introduced == "0" {
+				affected = true
+			} else if cmp, ok := compareAdvisoryVersions(ecosystem, version, event.Introduced); ok && cmp >= 0 {
+				affected = true
+			}
+		case event.Fixed != "":
+			if cmp, ok := compareAdvisoryVersions(ecosystem, version, event.Fixed); ok && cmp >= 0 {
+				affected = false
+			}
+		case event.LastAffected != "":
+			if cmp, ok := compareAdvisoryVersions(ecosystem, version, event.LastAffected); ok && cmp > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// advisorySeverity returns the normalized severity (critical, high, medium, low or unknown)
+// of an advisory. GitHub advisories record it as database_specific.severity; other records
+// are rated from their CVSS v3 vector.
+func advisorySeverity(vuln OSVVulnerability) string {
+	severity := ""
+	if vuln.DatabaseSpecific != nil {
+		severity = vuln.DatabaseSpecific.Severity
+	}
+	for _, affected := range vuln.Affected {
+		if severity == "" && affected.DatabaseSpecific != nil {
+			severity = affected.DatabaseSpecific.Severity
+		}
+	}
+
+	switch strings.ToLower(severity) {
+	case "critical":
+		return "critical"
+	case "high":
+		return "high"
+	case "moderate", "medium":
+		return "medium"
+	case "low":
+		return "low"
+	}
+
+	for _, score := range vuln.Severity {
+		if baseScore, ok := cvssV3BaseScore(score.Score); ok {
+			return cvssSeverity(baseScore)
+		}
+	}
+	return "unknown"
+}
+
+// advisoryFixedVersions returns the versions fixing the advisory for the package
+func advisoryFixedVersions(vuln OSVVulnerability, ecosystem, name string) []string {
+	key := advisoryPackageKey(ecosystem, name)
+	var fixed []string
+	for _, affected := range vuln.Affected {
+		if advisoryPackageKey(affected.Package.Ecosystem, affected.Package.Name) != key {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			for _, event := range r.Events {
+				if event.Fixed != "" && r.Type != "GIT" && !slices.Contains(fixed, event.Fixed) {
+					fixed = append(fixed, event.Fixed)
+				}
+			}
+		}
+	}
+	return fixed
+}
+
+// osvEcosystem maps an SBOM component type to its OSV ecosystem
+func osvEcosystem(componentType workflow.SBOMComponentType) string {
+	switch componentType {
+	case workflow.SBOMComponentNpm:
+		return "npm"
+	case workflow.SBOMComponentPyPI:
+		return "PyPI"
+	case workflow.SBOMComponentGo:
+		return "Go"
+	case workflow.SBOMComponentAction:
+		return "GitHub Actions"
+	case workflow.SBOMComponentContainer:
+		return osvContainerEcosystem
+	}
+	return ""
+}
+
+// DependencyFinding is an advisory affecting a dependency of a workflow
+type DependencyFinding struct {
+	Workflow   string   `json:"workflow" console:"header:Workflow"`
+	Severity   string   `json:"severity" console:"header:Severity"`
+	AdvisoryID string   `json:"advisory_id" console:"header:Advisory"`
+	Ecosystem  string   `json:"ecosystem" console:"header:Ecosystem"`
+	Package    string   `json:"package" console:"header:Package"`
+	Version    string   `json:"version" console:"header:Version"`
+	FixedIn    string   `json:"fixed_in,omitempty" console:"header:Fixed In"`
+	Origin     string   `json:"origin" console:"header:Origin"`
+	Summary    string   `json:"summary,omitempty" console:"header:Summary,maxlen:60"`
+	Aliases    []string `json:"aliases,omitempty" console:"-"`
+}
+
+// auditSBOMComponents checks the SBOM components of a workflow against the advisory database.
+// Components without a concrete version (e.g. npx package@latest) are skipped and counted.
+func auditSBOMComponents(db AdvisoryDatabase, sbom *workflow.SBOM) (findings []DependencyFinding, skipped int, err error) {
+	for _, component := range sbom.Components {
+		ecosystem := osvEcosystem(component.Type)
+		if ecosystem == "" {
+			continue
+		}
+
+		versions := auditComponentVersions(component)
+		if len(versions) == 0 {
+			depsAuditLog.Printf("Skipping %s %s: no concrete version", ecosystem, component.Name)
+			skipped++
+			continue
+		}
+
+		seen := make(map[string]bool)
+		for _, version := range versions {
+			vulns, err := db.Query(ecosystem, component.Name, version)
+			if err != nil {
+				return nil, skipped, err
+			}
+			for _, vuln := range vulns {
+				if seen[vuln.ID] {
+					continue
+				}
+				seen[vuln.ID] = true
+				findings = append(findings, DependencyFinding{
+					Workflow:   sbom.WorkflowID,
+					Severity:   advisorySeverity(vuln),
+					AdvisoryID: vuln.ID,
+					Ecosystem:  ecosystem,
+					Package:    component.Name,
+					Version:    versions[0],
+					FixedIn:    strings.Join(advisoryFixedVersions(vuln, ecosystem, component.Name), ", "),
+					Origin:     component.Origin,
+					Summary:    vuln.Summary,
+					Aliases:    vuln.Aliases,
+				})
+			}
+		}
+	}
+	return findings, skipped, nil
+}
+
+// auditComponentVersions returns the concrete versions to check for a component.
+// Actions and images are checked by tag and by digest since advisories may list either.
+func auditComponentVersions(component workflow.SBOMComponent) []string {
+	var versions []string
+	if component.Version != "" && component.Version != "latest" && !strings.ContainsAny(component.Version, "<>=!~^*") {
+		versions = append(versions, component.Version)
+	}
+	if component.Digest != "" {
+		versions = append(versions, component.Digest)
+	}
+	return versions
+}
+
+// sortDependencyFindings orders findings by workflow, then severity (most severe first), then package
+func sortDependencyFindings(findings []DependencyFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Workflow != findings[j].Workflow {
+			return findings[i].Workflow < findings[j].Workflow
+		}
+		if wi, wj := severityWeight(findings[i].Severity), severityWeight(findings[j].Severity); wi != wj {
+			return wi > wj
+		}
+		if findings[i].Package != findings[j].Package {
+			return findings[i].Package < findings[j].Package
+		}
+		return findings[i].AdvisoryID < findings[j].AdvisoryID
+	})
+}
+
+// newOSVAPIDatabase creates an advisory database backed by the public OSV API
+func newOSVAPIDatabase() AdvisoryDatabase {
+	return &osvAPIDatabase{client: &http.Client{Timeout: 30 * time.Second}, url: osvAPIQueryURL}
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"math"
+	"strings"
+)
+
+// cvssV3Weights are the metric weights of the CVSS v3.x base score
+// (https://www.first.org/cvss/v3.1/specification-document#7-4-Metric-Values)
+var cvssV3Weights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// cvssV3BaseScore computes the base score of a CVSS v3.0 or v3.1 vector such as
+// CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H. The second return value is false
+// when the vector is not a complete CVSS v3 vector.
+func cvssV3BaseScore(vector string) (float64, bool) {
+	parts := strings.Split(vector, "/")
+	if len(parts) < 2 || !strings.HasPrefix(parts[0], "CVSS:3.") {
+		return 0, false
+	}
+	metrics := make(map[string]string)
+	for _, part := range parts[1:] {
+		if name, value, found := strings.Cut(part, ":"); found {
+			metrics[name] = value
+		}
+	}
+
+	scope := metrics["S"]
+	if scope != "U" && scope != "C" {
+		return 0, false
+	}
+	weights := make(map[string]float64)
+	for name, values := range cvssV3Weights {
+		weight, ok := values[metrics[name]]
+		if !ok {
+			return 0, false
+		}
+		weights[name] = weight
+	}
+	// Privileges required weigh more when the scope changes
+	switch {
+	case metrics["PR"] == "N":
+		weights["PR"] = 0.85
+	case metrics["PR"] == "L" && scope == "U":
+		weights["PR"] = 0.62
+	case metrics["PR"] == "L":
+		weights["PR"] = 0.68
+	case metrics["PR"] == "H" && scope == "U":
+		weights["PR"] = 0.27
+	case metrics["PR"] == "H":
+		weights["PR"] = 0.5
+	default:
+		return 0, false
+	}
+
+	iss := 1 - (1-weights["C"])*(1-weights["I"])*(1-weights["A"])
+	impact := 6.42 * iss
+	if scope == "C" {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	if impact <= 0 {
+		return 0, true
+	}
+	exploitability := 8.22 * weights["AV"] * weights["AC"] * weights["PR"] * weights["UI"]
+	if scope == "C" {
+		return cvssRoundUp(math.Min(1.08*(impact+exploitability), 10)), true
+	}
+	return cvssRoundUp(math.Min(impact+exploitability, 10)), true
+}
+
+// cvssRoundUp rounds up to one decimal as defined in CVSS v3.1 Appendix A
+func cvssRoundUp(value float64) float64 {
+	scaled := int(math.Round(value * 100000))
+	if scaled%10000 == 0 {
+		return float64(scaled) / 100000
+	}
+	return float64(scaled/10000+1) / 10
+}
+
+// cvssSeverity maps a CVSS base score to its qualitative severity rating (a score of 0,
+// rated none, is reported as low)
+func cvssSeverity(score float64) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	}
+	return "low"
+}
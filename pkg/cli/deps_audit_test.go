@@ -0,0 +1,214 @@
+//go:build !integration
+
+package cli
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOSVCopilotAdvisory = `{
+  "id": "GHSA-test-copi-lot1",
+  "summary": "Copilot CLI test advisory",
+  "aliases": ["CVE-2099-0001"],
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "@github/copilot"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "0.0.500"}]}]
+  }],
+  "database_specific": {"severity": "HIGH"}
+}`
+
+const testOSVImageAdvisory = `{
+  "id": "OSV-test-image",
+  "summary": "Vulnerable base image",
+  "affected": [{
+    "package": {"ecosystem": "Docker", "name": "node"},
+    "versions": ["lts-alpine"],
+    "database_specific": {"severity": "MODERATE"}
+  }]
+}`
+
+func writeTestOSVDatabase(t *testing.T) string {
+	t.Helper()
+	dbDir := testutil.TempDir(t, "osv-db")
+	require.NoError(t, os.MkdirAll(filepath.Join(dbDir, "npm"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dbDir, "npm", "GHSA-test-copi-lot1.json"), []byte(testOSVCopilotAdvisory), 0644))
+
+	// Container advisories are stored as an OSV ecosystem export
+	zipFile, err := os.Create(filepath.Join(dbDir, "all.zip"))
+	require.NoError(t, err)
+	writer := zip.NewWriter(zipFile)
+	entry, err := writer.Create("OSV-test-image.json")
+	require.NoError(t, err)
+	_, err = entry.Write([]byte(testOSVImageAdvisory))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.NoError(t, zipFile.Close())
+
+	return dbDir
+}
+
+func TestVersionInOSVRange(t *testing.T) {
+	events := []OSVEvent{{Introduced: "1.2.0"}, {Fixed: "1.4.0"}, {Introduced: "2.0.0"}, {LastAffected: "2.1.0"}}
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{"1.1.9", false},
+		{"1.2.0", true},
+		{"1.3.5", true},
+		{"1.4.0", false},
+		{"2.0.0", true},
+		{"v2.1.0", true},
+		{"2.1.1", false},
+		{"lts-alpine", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, versionInOSVRange("npm", tt.version, events))
+		})
+	}
+}
+
+func TestVersionInOSVRangePyPI(t *testing.T) {
+	events := []OSVEvent{{Introduced: "0"}, {Fixed: "2.31.0"}}
+	tests := []struct {
+		version  string
+		expected bool
+	}{
+		{"2.30", true},
+		{"2.31.0rc1", true},
+		{"2.31.0.dev3", true},
+		{"2.31", false},
+		{"2.31.0.post1", false},
+		{"1!1.0", false},
+		{"not-a-version", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, versionInOSVRange("PyPI", tt.version, events))
+		})
+	}
+}
+
+func TestComparePEP440Versions(t *testing.T) {
+	// Ordered as in the PEP 440 examples, with alternative spellings
+	ordered := []string{"1.0.dev456", "1.0a1", "1.0-alpha.2.dev1", "1.0a12", "1.0b2.post345", "1.0c1", "1.0rc2", "1.0", "1.0+abc.5", "1.0.post456.dev34", "1.0-r456", "1.1.dev1"}
+	for i := 1; i < len(ordered); i++ {
+		cmp, ok := compareAdvisoryVersions("PyPI", ordered[i-1], ordered[i])
+		require.True(t, ok, "%s and %s should parse", ordered[i-1], ordered[i])
+		assert.Equal(t, -1, cmp, "%s should sort before %s", ordered[i-1], ordered[i])
+	}
+
+	cmp, ok := compareAdvisoryVersions("PyPI", "1.0.0", "v1.0")
+	require.True(t, ok)
+	assert.Equal(t, 0, cmp, "trailing zeros should not be significant")
+}
+
+func TestAdvisorySeverityFromCVSS(t *testing.T) {
+	tests := []struct {
+		vector   string
+		expected string
+	}{
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", "critical"}, // 9.8
+		{"CVSS:3.1/AV:N/AC:L/PR:N/UI:R/S:C/C:L/I:L/A:N", "medium"},   // 6.1
+		{"CVSS:3.0/AV:N/AC:L/PR:L/UI:N/S:C/C:H/I:H/A:H", "critical"}, // 9.9
+		{"CVSS:3.1/AV:L/AC:L/PR:L/UI:N/S:U/C:H/I:N/A:N", "medium"},   // 5.5
+		{"CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:H/A:N", "high"},     // 7.4
+		{"CVSS:3.1/AV:P/AC:H/PR:H/UI:R/S:U/C:L/I:N/A:N", "low"},      // 1.6
+		{"CVSS:4.0/AV:N/AC:L/AT:N/PR:N/UI:N/VC:H/VI:H/VA:H/SC:N/SI:N/SA:N", "unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vector, func(t *testing.T) {
+			vuln := OSVVulnerability{ID: "PYSEC-test", Severity: []OSVSeverity{{Type: "CVSS_V3", Score: tt.vector}}}
+			assert.Equal(t, tt.expected, advisorySeverity(vuln))
+		})
+	}
+}
+
+func TestLocalAdvisoryDatabase(t *testing.T) {
+	db, err := LoadAdvisoryDatabase(writeTestOSVDatabase(t))
+	require.NoError(t, err)
+
+	vulns, err := db.Query("npm", "@github/copilot", "0.0.410")
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, "high", advisorySeverity(vulns[0]))
+	assert.Equal(t, []string{"0.0.500"}, advisoryFixedVersions(vulns[0], "npm", "@github/copilot"))
+
+	vulns, err = db.Query("npm", "@github/copilot", "0.0.500")
+	require.NoError(t, err)
+	assert.Empty(t, vulns, "fixed version should not be affected")
+
+	vulns, err = db.Query("Docker", "node", "lts-alpine")
+	require.NoError(t, err)
+	require.Len(t, vulns, 1, "advisories inside zip exports should be loaded")
+	assert.Equal(t, "medium", advisorySeverity(vulns[0]))
+
+	_, err = LoadAdvisoryDatabase(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestAuditSBOMComponents(t *testing.T) {
+	db, err := LoadAdvisoryDatabase(writeTestOSVDatabase(t))
+	require.NoError(t, err)
+
+	sbom := &workflow.SBOM{
+		WorkflowID: "triage",
+		Components: []workflow.SBOMComponent{
+			{Type: workflow.SBOMComponentNpm, Name: "@github/copilot", Version: "0.0.410", Origin: "engine"},
+			{Type: workflow.SBOMComponentNpm, Name: "@modelcontextprotocol/server-memory", Version: "latest", Origin: "mcp-servers.memory"},
+			{Type: workflow.SBOMComponentContainer, Name: "node", Version: "lts-alpine", Origin: "safe-outputs"},
+			{Type: workflow.SBOMComponentRuntime, Name: "node", Version: "24", Origin: "runtimes.node"},
+		},
+	}
+
+	findings, skipped, err := auditSBOMComponents(db, sbom)
+	require.NoError(t, err)
+	assert.Equal(t, 1, skipped, "packages without a concrete version should be skipped")
+	require.Len(t, findings, 2)
+
+	sortDependencyFindings(findings)
+	assert.Equal(t, "GHSA-test-copi-lot1", findings[0].AdvisoryID, "high severity findings should sort first")
+	assert.Equal(t, "engine", findings[0].Origin)
+	assert.Equal(t, []string{"CVE-2099-0001"}, findings[0].Aliases)
+	assert.Equal(t, "OSV-test-image", findings[1].AdvisoryID)
+	assert.Equal(t, "Docker", findings[1].Ecosystem)
+}
+
+func TestRunDepsAudit(t *testing.T) {
+	dbDir := writeTestOSVDatabase(t)
+	tmpDir := setupPolicyTestRepo(t, map[string]string{
+		"triage.md": "---\non: workflow_dispatch\nengine: copilot\n---\n\n# Triage\n",
+	}, "")
+	require.NoError(t, workflow.NewCompiler().CompileWorkflow(filepath.Join(tmpDir, ".github", "workflows", "triage.md")))
+
+	require.NoError(t, RunDepsAudit(DepsAuditConfig{Database: dbDir, JSONOutput: true}), "findings should not fail without --fail-on")
+	require.NoError(t, RunDepsAudit(DepsAuditConfig{Database: dbDir, FailOn: "critical"}))
+
+	err := RunDepsAudit(DepsAuditConfig{WorkflowIDs: []string{"triage"}, Database: dbDir, FailOn: "high"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 finding(s) at or above high severity")
+
+	// Advisories without a severity fail any threshold
+	unrated := strings.Replace(testOSVCopilotAdvisory, `"database_specific": {"severity": "HIGH"}`, `"severity": [{"type": "CVSS_V4", "score": "CVSS:4.0/AV:N"}]`, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dbDir, "npm", "GHSA-test-copi-lot1.json"), []byte(unrated), 0644))
+	err = RunDepsAudit(DepsAuditConfig{Database: dbDir, FailOn: "critical"})
+	require.Error(t, err, "findings of unknown severity should fail the audit")
+	assert.Contains(t, err.Error(), "1 finding(s) at or above critical severity")
+
+	err = RunDepsAudit(DepsAuditConfig{Database: dbDir, FailOn: "severe"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid --fail-on value")
+}
@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"cmp"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// pep440Pattern matches PEP 440 versions, including the alternative spellings that
+// normalize to canonical versions (https://peps.python.org/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions)
+var pep440Pattern = regexp.MustCompile(`(?i)^v?(?:([0-9]+)!)?([0-9]+(?:\.[0-9]+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?([0-9]+)?)?` +
+	`(?:-([0-9]+)|[-_.]?(post|rev|r)[-_.]?([0-9]+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?([0-9]+)?)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440Version is a parsed PEP 440 version
+type pep440Version struct {
+	epoch   int
+	release []int
+	pre     [2]int // Phase (a=0, b=1, rc=2) and number; phase is -1 without a pre-release
+	post    int    // -1 without a post-release
+	dev     int    // -1 without a dev release
+	local   string
+}
+
+// parsePEP440Version parses a Python package version
+func parsePEP440Version(version string) (pep440Version, bool) {
+	match := pep440Pattern.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return pep440Version{}, false
+	}
+
+	v := pep440Version{pre: [2]int{-1, 0}, post: -1, dev: -1, local: strings.ToLower(match[10])}
+	v.epoch = atoiOrZero(match[1])
+	for _, part := range strings.Split(match[2], ".") {
+		v.release = append(v.release, atoiOrZero(part))
+	}
+	// Trailing zeros are not significant: 1.0 == 1.0.0
+	for len(v.release) > 1 && v.release[len(v.release)-1] == 0 {
+		v.release = v.release[:len(v.release)-1]
+	}
+
+	if match[3] != "" {
+		switch strings.ToLower(match[3]) {
+		case "a", "alpha":
+			v.pre[0] = 0
+		case "b", "beta":
+			v.pre[0] = 1
+		default:
+			v.pre[0] = 2
+		}
+		v.pre[1] = atoiOrZero(match[4])
+	}
+	if match[5] != "" {
+		v.post = atoiOrZero(match[5])
+	} else if match[6] != "" {
+		v.post = atoiOrZero(match[7])
+	}
+	if match[8] != "" {
+		v.dev = atoiOrZero(match[9])
+	}
+	return v, true
+}
+
+// comparePEP440Versions compares two parsed PEP 440 versions
+func comparePEP440Versions(a, b pep440Version) int {
+	if a.epoch != b.epoch {
+		return cmp.Compare(a.epoch, b.epoch)
+	}
+	for i := 0; i < max(len(a.release), len(b.release)); i++ {
+		var x, y int
+		if i < len(a.release) {
+			x = a.release[i]
+		}
+		if i < len(b.release) {
+			y = b.release[i]
+		}
+		if x != y {
+			return cmp.Compare(x, y)
+		}
+	}
+	if c := cmp.Compare(a.preKey(), b.preKey()); c != 0 {
+		return c
+	}
+	if a.pre[0] >= 0 && b.pre[0] >= 0 && a.pre[1] != b.pre[1] {
+		return cmp.Compare(a.pre[1], b.pre[1])
+	}
+	if a.post != b.post {
+		return cmp.Compare(a.post, b.post)
+	}
+	if a.dev != b.dev {
+		// A release without a dev segment sorts after its dev releases
+		return cmp.Compare(pep440DevKey(a.dev), pep440DevKey(b.dev))
+	}
+	return strings.Compare(a.local, b.local)
+}
+
+// preKey orders the pre-release phase: dev releases of the final release sort before its
+// pre-releases, which sort before the final release
+func (v pep440Version) preKey() int {
+	switch {
+	case v.pre[0] >= 0:
+		return v.pre[0]
+	case v.post < 0 && v.dev >= 0:
+		return math.MinInt
+	}
+	return math.MaxInt
+}
+
+// pep440DevKey orders the dev segment, sorting versions without one last
+func pep440DevKey(dev int) int {
+	if dev < 0 {
+		return math.MaxInt
+	}
+	return dev
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// compareAdvisoryVersions compares two versions of a package in the given ecosystem:
+// PyPI versions follow PEP 440 and other ecosystems semantic versioning.
+// The second return value is false when either version cannot be parsed.
+func compareAdvisoryVersions(ecosystem, v1, v2 string) (int, bool) {
+	if strings.EqualFold(ecosystem, "PyPI") {
+		a, okA := parsePEP440Version(v1)
+		b, okB := parsePEP440Version(v2)
+		if !okA || !okB {
+			return 0, false
+		}
+		return comparePEP440Versions(a, b), true
+	}
+
+	if !strings.HasPrefix(v1, "v") {
+		v1 = "v" + v1
+	}
+	if !strings.HasPrefix(v2, "v") {
+		v2 = "v" + v2
+	}
+	if !semver.IsValid(v1) || !semver.IsValid(v2) {
+		return 0, false
+	}
+	return semver.Compare(v1, v2), true
+}
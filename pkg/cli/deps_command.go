@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var depsCommandLog = logger.New("cli:deps_command")
+
+// DepsAuditConfig holds the options of the deps audit command
+type DepsAuditConfig struct {
+	WorkflowIDs []string
+	Database    string // Local OSV advisory database (directory, .json or .zip); empty queries the OSV API
+	FailOn      string // Minimum severity that fails the audit (none, low, medium, high, critical)
+	WorkflowDir string
+	JSONOutput  bool
+	Verbose     bool
+}
+
+// DepsAuditResult is the JSON output of deps audit
+type DepsAuditResult struct {
+	Database   string              `json:"database"`
+	Workflows  int                 `json:"workflows"`
+	Components int                 `json:"components"`
+	Skipped    int                 `json:"skipped"`
+	Findings   []DependencyFinding `json:"findings"`
+}
+
+// NewDepsCommand creates the deps command with subcommands
+func NewDepsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deps",
+		Short: "Inspect the third-party dependencies of agentic workflows",
+		Long: `Inspect the third-party dependencies of agentic workflows.
+
+Available subcommands:
+  • audit - Check workflow dependencies against a vulnerability advisory database`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newDepsAuditSubcommand())
+
+	return cmd
+}
+
+// newDepsAuditSubcommand creates the deps audit subcommand
+func newDepsAuditSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit [workflow]...",
+		Short: "Check workflow dependencies for known vulnerabilities",
+		Long: `Check the dependencies of compiled workflows for known vulnerabilities.
+
+Every npm, pip/uv and Go package, MCP server container image and pinned action
+used by a workflow (see 'sbom') is checked against an advisory database in the
+OSV format (https://ossf.github.io/osv-schema/).
+
+With --db, advisories are read from a local directory of OSV JSON records or OSV
+ecosystem exports (all.zip), so the audit works without network access. Container
+images are matched against records with the "` + osvContainerEcosystem + `" ecosystem. Without
+--db, the public OSV API is queried.
+
+Packages without a concrete version (e.g. package@latest) cannot be checked and are
+reported as skipped.
+
+Use --fail-on to exit with a non-zero status when a finding at or above the given
+severity is found (low, medium, high, critical). Severities are read from the advisory
+or computed from its CVSS v3 vector; findings whose severity is unknown fail any threshold.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` deps audit                              # Audit all workflows using the OSV API
+  ` + string(constants.CLIExtensionPrefix) + ` deps audit --db ./osv                   # Audit offline with a local OSV database
+  ` + string(constants.CLIExtensionPrefix) + ` deps audit ci-doctor --fail-on high     # Fail on high or critical findings
+  ` + string(constants.CLIExtensionPrefix) + ` deps audit --db ./osv --json            # Output findings in JSON format`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			db, _ := cmd.Flags().GetString("db")
+			failOn, _ := cmd.Flags().GetString("fail-on")
+			dir, _ := cmd.Flags().GetString("dir")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunDepsAudit(DepsAuditConfig{
+				WorkflowIDs: args,
+				Database:    db,
+				FailOn:      failOn,
+				WorkflowDir: dir,
+				JSONOutput:  jsonOutput,
+				Verbose:     verbose,
+			})
+		},
+	}
+
+	cmd.Flags().String("db", "", "Local OSV advisory database (directory, .json or .zip). Queries the OSV API when not set")
+	cmd.Flags().String("fail-on", "none", "Fail when a finding has at least this severity: none, low, medium, high, critical")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	addJSONFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunDepsAudit checks the dependencies of the given workflows (all workflows when empty)
+func RunDepsAudit(config DepsAuditConfig) error {
+	depsCommandLog.Printf("Running deps audit: workflows=%v, db=%s, failOn=%s", config.WorkflowIDs, config.Database, config.FailOn)
+
+	failThreshold := 0
+	switch config.FailOn {
+	case "", "none":
+	case "low", "medium", "high", "critical":
+		failThreshold = severityWeight(config.FailOn)
+	default:
+		return fmt.Errorf("invalid --fail-on value '%s' (must be none, low, medium, high or critical)", config.FailOn)
+	}
+
+	var db AdvisoryDatabase
+	result := DepsAuditResult{Database: "osv.dev", Findings: []DependencyFinding{}}
+	if config.Database != "" {
+		var err error
+		db, err = LoadAdvisoryDatabase(config.Database)
+		if err != nil {
+			return err
+		}
+		result.Database = config.Database
+	} else {
+		db = newOSVAPIDatabase()
+	}
+
+	var files []string
+	if len(config.WorkflowIDs) > 0 {
+		for _, id := range config.WorkflowIDs {
+			resolved, err := ResolveWorkflowPath(id)
+			if err != nil {
+				return err
+			}
+			files = append(files, resolved)
+		}
+	} else {
+		var err error
+		files, err = getMarkdownWorkflowFiles(config.WorkflowDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(config.Verbose))
+	compiler.SetQuiet(true)
+
+	for _, file := range files {
+		sbom, err := buildWorkflowSBOM(compiler, file)
+		if err != nil {
+			var sharedErr *workflow.SharedWorkflowError
+			var templateErr *workflow.TemplateWorkflowError
+			if errors.As(err, &sharedErr) || errors.As(err, &templateErr) {
+				continue
+			}
+			return err
+		}
+
+		findings, skipped, err := auditSBOMComponents(db, sbom)
+		if err != nil {
+			return err
+		}
+		result.Workflows++
+		result.Components += len(sbom.Components)
+		result.Skipped += skipped
+		result.Findings = append(result.Findings, findings...)
+	}
+	sortDependencyFindings(result.Findings)
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		renderDepsAuditResult(result)
+	}
+
+	if failThreshold > 0 {
+		failing := 0
+		for _, finding := range result.Findings {
+			// Advisories of unknown severity could be critical, so they fail any threshold
+			if finding.Severity == "unknown" || severityWeight(finding.Severity) >= failThreshold {
+				failing++
+			}
+		}
+		if failing > 0 {
+			return fmt.Errorf("deps audit failed: %d finding(s) at or above %s severity", failing, config.FailOn)
+		}
+	}
+	return nil
+}
+
+// renderDepsAuditResult prints the audit result to stderr
+func renderDepsAuditResult(result DepsAuditResult) {
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Advisory database: %s", result.Database)))
+	if result.Skipped > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d component(s) without a concrete version were not checked", result.Skipped)))
+	}
+	if len(result.Findings) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("No known vulnerabilities in %d component(s) of %d workflow(s)", result.Components, result.Workflows)))
+		return
+	}
+	fmt.Fprint(os.Stderr, console.RenderStruct(result.Findings))
+	fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d finding(s) in %d workflow(s)", len(result.Findings), result.Workflows)))
+}
@@ -6,6 +6,7 @@
 //   - Parsing gateway.jsonl JSONL format logs
 //   - Extracting server and tool usage metrics
 //   - Aggregating gateway statistics
+//   - Counting calls rejected by tools.<server>.limits (rate_limited events)
 //   - Rendering gateway metrics tables
 
 package cli
@@ -42,6 +43,7 @@ type GatewayLogEntry struct {
 	Status     string  `json:"status,omitempty"`
 	Error      string  `json:"error,omitempty"`
 	Message    string  `json:"message,omitempty"`
+	Limit      int     `json:"limit,omitempty"` // Quota that rejected the call (rate_limited events)
 }
 
 // GatewayServerMetrics represents usage metrics for a single MCP server
@@ -51,6 +53,7 @@ type GatewayServerMetrics struct {
 	ToolCallCount int
 	TotalDuration float64 // in milliseconds
 	ErrorCount    int
+	RateLimited   int // Calls rejected by tools.<server>.limits
 	Tools         map[string]*GatewayToolMetrics
 }
 
@@ -63,6 +66,8 @@ type GatewayToolMetrics struct {
 	MaxDuration     float64 // in milliseconds
 	MinDuration     float64 // in milliseconds
 	ErrorCount      int
+	RateLimited     int // Calls rejected by tools.<server>.limits
+	Limit           int // Quota reported by the last rate_limited event
 	TotalInputSize  int
 	TotalOutputSize int
 }
@@ -72,6 +77,7 @@ type GatewayMetrics struct {
 	TotalRequests  int
 	TotalToolCalls int
 	TotalErrors    int
+	RateLimited    int // Calls rejected by tools.<server>.limits
 	Servers        map[string]*GatewayServerMetrics
 	StartTime      time.Time
 	EndTime        time.Time
@@ -177,6 +183,21 @@ func processGatewayLogEntry(entry *GatewayLogEntry, metrics *GatewayMetrics, ver
 
 	// Process based on event type
 	switch entry.Event {
+	case "rate_limited":
+		// The gateway rejected the call because a tools.<server>.limits quota was reached
+		metrics.RateLimited++
+		if entry.ServerName != "" {
+			server := getOrCreateServer(metrics, entry.ServerName)
+			server.RateLimited++
+			if entry.ToolName != "" {
+				tool := getOrCreateTool(server, entry.ToolName)
+				tool.RateLimited++
+				if entry.Limit > 0 {
+					tool.Limit = entry.Limit
+				}
+			}
+		}
+
 	case "request", "tool_call", "rpc_call":
 		metrics.TotalRequests++
 
@@ -277,6 +298,9 @@ func renderGatewayMetricsTable(metrics *GatewayMetrics, verbose bool) string {
 	fmt.Fprintf(&output, "Total Requests: %d\n", metrics.TotalRequests)
 	fmt.Fprintf(&output, "Total Tool Calls: %d\n", metrics.TotalToolCalls)
 	fmt.Fprintf(&output, "Total Errors: %d\n", metrics.TotalErrors)
+	if metrics.RateLimited > 0 {
+		fmt.Fprintf(&output, "Rate Limited Calls: %d\n", metrics.RateLimited)
+	}
 	fmt.Fprintf(&output, "Servers: %d\n", len(metrics.Servers))
 
 	if !metrics.StartTime.IsZero() && !metrics.EndTime.IsZero() {
@@ -320,6 +344,34 @@ func renderGatewayMetricsTable(metrics *GatewayMetrics, verbose bool) string {
 		output.WriteString("└────────────────────────────┴──────────┴────────────┴───────────┴────────┘\n")
 	}
 
+	// Calls rejected by the gateway are always shown, since they change agent behavior
+	if metrics.RateLimited > 0 {
+		output.WriteString("\n")
+		output.WriteString(console.FormatWarningMessage("Calls rejected by tools.<server>.limits:"))
+		output.WriteString("\n")
+		for _, serverName := range getSortedServerNames(metrics) {
+			server := metrics.Servers[serverName]
+			if server.RateLimited == 0 {
+				continue
+			}
+			var toolNames []string
+			for name, tool := range server.Tools {
+				if tool.RateLimited > 0 {
+					toolNames = append(toolNames, name)
+				}
+			}
+			sort.Strings(toolNames)
+			for _, toolName := range toolNames {
+				tool := server.Tools[toolName]
+				if tool.Limit > 0 {
+					fmt.Fprintf(&output, "  %s/%s: %d rejected (limit %d)\n", serverName, toolName, tool.RateLimited, tool.Limit)
+				} else {
+					fmt.Fprintf(&output, "  %s/%s: %d rejected\n", serverName, toolName, tool.RateLimited)
+				}
+			}
+		}
+	}
+
 	// Tool metrics table (if verbose)
 	if verbose {
 		output.WriteString("\n")
@@ -439,8 +491,8 @@ func extractMCPToolUsageData(logDir string, verbose bool) (*MCPToolUsageData, er
 			continue // Skip malformed lines
 		}
 
-		// Only process tool call events
-		if entry.Event == "tool_call" || entry.Event == "rpc_call" || entry.Event == "request" {
+		// Only process tool call events, including calls rejected by tools.<server>.limits
+		if entry.Event == "tool_call" || entry.Event == "rpc_call" || entry.Event == "request" || entry.Event == "rate_limited" {
 			toolName := entry.ToolName
 			if toolName == "" {
 				toolName = entry.Method
@@ -462,6 +514,9 @@ func extractMCPToolUsageData(logDir string, verbose bool) (*MCPToolUsageData, er
 				Status:     entry.Status,
 				Error:      entry.Error,
 			}
+			if entry.Event == "rate_limited" {
+				toolCall.Status = "rate_limited"
+			}
 
 			if entry.Duration > 0 {
 				toolCall.Duration = timeutil.FormatDuration(time.Duration(entry.Duration * float64(time.Millisecond)))
@@ -485,6 +540,7 @@ func extractMCPToolUsageData(logDir string, verbose bool) (*MCPToolUsageData, er
 			TotalInputSize:  0,
 			TotalOutputSize: 0,
 			ErrorCount:      serverMetrics.ErrorCount,
+			RateLimited:     serverMetrics.RateLimited,
 		}
 
 		if serverMetrics.RequestCount > 0 {
@@ -503,6 +559,7 @@ func extractMCPToolUsageData(logDir string, verbose bool) (*MCPToolUsageData, er
 				MaxInputSize:    0, // Will be calculated below
 				MaxOutputSize:   0, // Will be calculated below
 				ErrorCount:      toolMetrics.ErrorCount,
+				RateLimited:     toolMetrics.RateLimited,
 			}
 
 			if toolMetrics.AvgDuration > 0 {
@@ -577,6 +634,7 @@ func displayAggregatedGatewayMetrics(processedRuns []ProcessedRun, outputDir str
 		aggregated.TotalRequests += runMetrics.TotalRequests
 		aggregated.TotalToolCalls += runMetrics.TotalToolCalls
 		aggregated.TotalErrors += runMetrics.TotalErrors
+		aggregated.RateLimited += runMetrics.RateLimited
 		aggregated.TotalDuration += runMetrics.TotalDuration
 
 		// Merge server metrics
@@ -586,6 +644,7 @@ func displayAggregatedGatewayMetrics(processedRuns []ProcessedRun, outputDir str
 			aggServer.ToolCallCount += serverMetrics.ToolCallCount
 			aggServer.TotalDuration += serverMetrics.TotalDuration
 			aggServer.ErrorCount += serverMetrics.ErrorCount
+			aggServer.RateLimited += serverMetrics.RateLimited
 
 			// Merge tool metrics
 			for toolName, toolMetrics := range serverMetrics.Tools {
@@ -593,6 +652,10 @@ func displayAggregatedGatewayMetrics(processedRuns []ProcessedRun, outputDir str
 				aggTool.CallCount += toolMetrics.CallCount
 				aggTool.TotalDuration += toolMetrics.TotalDuration
 				aggTool.ErrorCount += toolMetrics.ErrorCount
+				aggTool.RateLimited += toolMetrics.RateLimited
+				if toolMetrics.Limit > 0 {
+					aggTool.Limit = toolMetrics.Limit
+				}
 				aggTool.TotalInputSize += toolMetrics.TotalInputSize
 				aggTool.TotalOutputSize += toolMetrics.TotalOutputSize
 
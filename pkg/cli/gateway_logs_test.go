@@ -485,3 +485,31 @@ func TestParseGatewayLogsFromMCPLogsSubdirectory(t *testing.T) {
 	require.True(t, ok, "should have github server metrics")
 	assert.Equal(t, 3, githubMetrics.RequestCount, "should have 3 total calls for github server")
 }
+
+func TestGatewayLogsRateLimited(t *testing.T) {
+	tmpDir := t.TempDir()
+	logContent := `{"timestamp":"2024-01-12T10:00:00Z","level":"info","type":"request","event":"tool_call","server_name":"github","tool_name":"search_code","duration":100.0,"status":"success"}
+{"timestamp":"2024-01-12T10:00:01Z","level":"warn","type":"request","event":"rate_limited","server_name":"github","tool_name":"search_code","status":"rate_limited","limit":1,"message":"per-tool limit reached"}
+{"timestamp":"2024-01-12T10:00:02Z","level":"warn","type":"request","event":"rate_limited","server_name":"github","tool_name":"search_code","status":"rate_limited","limit":1,"message":"per-tool limit reached"}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "gateway.jsonl"), []byte(logContent), 0644))
+
+	metrics, err := parseGatewayLogs(tmpDir, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, metrics.TotalToolCalls, "rejected calls should not count as tool calls")
+	assert.Equal(t, 0, metrics.TotalErrors, "rejected calls should not count as errors")
+	assert.Equal(t, 2, metrics.RateLimited)
+
+	tool := metrics.Servers["github"].Tools["search_code"]
+	assert.Equal(t, 2, tool.RateLimited)
+	assert.Equal(t, 1, tool.Limit)
+	assert.Contains(t, renderGatewayMetricsTable(metrics, false), "github/search_code: 2 rejected (limit 1)")
+
+	mcpData, err := extractMCPToolUsageData(tmpDir, false)
+	require.NoError(t, err)
+	require.Len(t, mcpData.Summary, 1)
+	assert.Equal(t, 2, mcpData.Summary[0].RateLimited)
+	assert.Equal(t, 2, mcpData.Servers[0].RateLimited)
+	require.Len(t, mcpData.ToolCalls, 3)
+	assert.Equal(t, "rate_limited", mcpData.ToolCalls[2].Status)
+}
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+)
+
+var instantiateLog = logger.New("cli:instantiate_command")
+
+// InstantiateConfig holds the options of the instantiate command
+type InstantiateConfig struct {
+	Template    string   // Template workflow ID or path
+	Set         []string // key=value input assignments
+	Output      string   // Output workflow file (name or path)
+	MatrixFile  string   // YAML file listing many instances
+	WorkflowDir string   // Directory for outputs given as bare names
+	Force       bool     // Overwrite existing workflows
+	NoCompile   bool     // Skip compiling the generated workflows
+	Verbose     bool
+}
+
+// templateMatrix is the format of the --matrix file
+type templateMatrix struct {
+	Instances []templateMatrixInstance `yaml:"instances"`
+}
+
+// templateMatrixInstance is one workflow generated from the template
+type templateMatrixInstance struct {
+	Output string         `yaml:"output"`
+	Inputs map[string]any `yaml:"inputs"`
+}
+
+// NewInstantiateCommand creates the instantiate command
+func NewInstantiateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "instantiate <template>",
+		Short: "Generate workflows from a workflow template",
+		Long: `Generate one or more workflows from a workflow template.
+
+A workflow template is a workflow with a top-level 'template' field declaring typed
+inputs. Inputs are referenced in the frontmatter and markdown with
+${{ github.aw.inputs.<name> }}:
+
+  ---
+  template:
+    inputs:
+      team:
+        type: string
+        required: true
+      label:
+        type: choice
+        options: [bug, triage]
+        default: triage
+  on:
+    issues:
+      types: [opened]
+  ---
+  Triage new issues for the ${{ github.aw.inputs.team }} team.
+
+Templates are skipped by 'compile'. Input values are validated against the declared
+types (string, number, boolean, choice), required inputs must be set and defaults
+are applied.
+
+Generated workflows record their template and input values in a 'template-source'
+field, so '` + string(constants.CLIExtensionPrefix) + ` update' re-renders them when the template changes.
+
+Use --matrix to generate many workflows at once from a YAML file:
+
+  instances:
+    - output: triage-platform.md
+      inputs:
+        team: platform
+    - output: triage-docs.md
+      inputs:
+        team: docs
+        label: bug
+
+Values given with --set apply to every instance unless the instance overrides them.
+Outputs given as a bare file name are written to .github/workflows (or --dir).
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` instantiate triage-template --set team=platform -o triage-platform.md
+  ` + string(constants.CLIExtensionPrefix) + ` instantiate .github/templates/triage.md --matrix teams.yml
+  ` + string(constants.CLIExtensionPrefix) + ` instantiate triage-template --set team=docs -o triage-docs.md --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			set, _ := cmd.Flags().GetStringArray("set")
+			output, _ := cmd.Flags().GetString("output")
+			matrix, _ := cmd.Flags().GetString("matrix")
+			dir, _ := cmd.Flags().GetString("dir")
+			force, _ := cmd.Flags().GetBool("force")
+			noCompile, _ := cmd.Flags().GetBool("no-compile")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunInstantiate(InstantiateConfig{
+				Template:    args[0],
+				Set:         set,
+				Output:      output,
+				MatrixFile:  matrix,
+				WorkflowDir: dir,
+				Force:       force,
+				NoCompile:   noCompile,
+				Verbose:     verbose,
+			})
+		},
+	}
+
+	cmd.Flags().StringArray("set", nil, "Set a template input (key=value); can be repeated")
+	cmd.Flags().StringP("output", "o", "", "Output workflow file (e.g. triage-platform.md)")
+	cmd.Flags().String("matrix", "", "YAML file listing the workflows to generate")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory for outputs given as file names (default: .github/workflows)")
+	cmd.Flags().BoolP("force", "f", false, "Overwrite existing workflows")
+	cmd.Flags().Bool("no-compile", false, "Do not compile the generated workflows")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunInstantiate generates workflows from a template
+func RunInstantiate(config InstantiateConfig) error {
+	instantiateLog.Printf("Instantiating template: %s, output=%s, matrix=%s", config.Template, config.Output, config.MatrixFile)
+
+	if config.Output == "" && config.MatrixFile == "" {
+		return fmt.Errorf("either --output or --matrix is required")
+	}
+	if config.Output != "" && config.MatrixFile != "" {
+		return fmt.Errorf("--output and --matrix cannot be used together")
+	}
+
+	templatePath, err := ResolveWorkflowPath(config.Template)
+	if err != nil {
+		return err
+	}
+	tmpl, recordedPath, err := loadWorkflowTemplate(templatePath)
+	if err != nil {
+		return err
+	}
+
+	common, err := parseTemplateSetFlags(config.Set)
+	if err != nil {
+		return err
+	}
+
+	instances := []templateMatrixInstance{{Output: config.Output}}
+	if config.MatrixFile != "" {
+		instances, err = loadTemplateMatrix(config.MatrixFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	workflowDir := config.WorkflowDir
+	if workflowDir == "" {
+		workflowDir = getWorkflowsDir()
+	}
+
+	// Render everything before writing so an invalid instance doesn't leave partial output
+	type renderedWorkflow struct {
+		path    string
+		content string
+	}
+	var rendered []renderedWorkflow
+	seen := make(map[string]bool)
+	for i, instance := range instances {
+		if instance.Output == "" {
+			return fmt.Errorf("matrix instance %d: output is required", i+1)
+		}
+
+		values := make(map[string]any, len(common)+len(instance.Inputs))
+		for k, v := range common {
+			values[k] = v
+		}
+		for k, v := range instance.Inputs {
+			values[k] = v
+		}
+
+		inputs, err := tmpl.ResolveInputs(values)
+		if err != nil {
+			return fmt.Errorf("%s: %w", instance.Output, err)
+		}
+		content, err := tmpl.Render(inputs, recordedPath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", instance.Output, err)
+		}
+
+		outputPath := resolveTemplateOutputPath(instance.Output, workflowDir)
+		if seen[outputPath] {
+			return fmt.Errorf("duplicate output: %s", outputPath)
+		}
+		seen[outputPath] = true
+		if _, err := os.Stat(outputPath); err == nil && !config.Force {
+			return fmt.Errorf("workflow %s already exists (use --force to overwrite)", outputPath)
+		}
+		rendered = append(rendered, renderedWorkflow{path: outputPath, content: content})
+	}
+
+	for _, wf := range rendered {
+		if err := os.MkdirAll(filepath.Dir(wf.path), 0755); err != nil {
+			return fmt.Errorf("failed to create directory for %s: %w", wf.path, err)
+		}
+		if err := os.WriteFile(wf.path, []byte(wf.content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", wf.path, err)
+		}
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Generated %s from %s", console.ToRelativePath(wf.path), recordedPath)))
+
+		if !config.NoCompile {
+			if err := compileWorkflow(wf.path, config.Verbose, true, ""); err != nil {
+				return fmt.Errorf("failed to compile %s: %w", wf.path, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// loadWorkflowTemplate reads and parses a template. It also returns the path recorded in
+// template-source (relative to the repository root, with forward slashes).
+func loadWorkflowTemplate(path string) (*workflow.WorkflowTemplate, string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := workflow.ParseWorkflowTemplate(string(content))
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid template %s: %w", path, err)
+	}
+	relPath, err := getRepositoryRelativePath(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return tmpl, filepath.ToSlash(relPath), nil
+}
+
+// parseTemplateSetFlags parses key=value assignments from --set
+func parseTemplateSetFlags(assignments []string) (map[string]any, error) {
+	values := make(map[string]any, len(assignments))
+	for _, assignment := range assignments {
+		key, value, ok := strings.Cut(assignment, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set value '%s' (expected key=value)", assignment)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+// loadTemplateMatrix reads a --matrix file
+func loadTemplateMatrix(path string) ([]templateMatrixInstance, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read matrix file: %w", err)
+	}
+	var matrix templateMatrix
+	if err := yaml.Unmarshal(content, &matrix); err != nil {
+		return nil, fmt.Errorf("failed to parse matrix file %s: %w", path, err)
+	}
+	if len(matrix.Instances) == 0 {
+		return nil, fmt.Errorf("matrix file %s has no instances", path)
+	}
+	return matrix.Instances, nil
+}
+
+// resolveTemplateOutputPath places bare file names in the workflow directory
+func resolveTemplateOutputPath(output, workflowDir string) string {
+	if !strings.HasSuffix(output, ".md") {
+		output += ".md"
+	}
+	if filepath.Base(output) == output {
+		return filepath.Join(workflowDir, output)
+	}
+	return output
+}
@@ -0,0 +1,92 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTriageTemplate = `---
+template:
+  inputs:
+    team:
+      type: string
+      required: true
+    label:
+      type: choice
+      options: [bug, triage]
+      default: triage
+on:
+  issues:
+    types: [opened]
+engine: copilot
+safe-outputs:
+  add-labels:
+    allowed:
+      - ${{ github.aw.inputs.label }}
+---
+
+# Triage for ${{ github.aw.inputs.team }}
+`
+
+func TestRunInstantiate(t *testing.T) {
+	tmpDir := setupPolicyTestRepo(t, map[string]string{"triage-template.md": testTriageTemplate}, "")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+
+	err := RunInstantiate(InstantiateConfig{Template: "triage-template", Output: "triage-platform.md", NoCompile: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required template input 'team'")
+
+	err = RunInstantiate(InstantiateConfig{Template: "triage-template", Set: []string{"team=platform"}, Output: "triage-platform"})
+	require.NoError(t, err)
+	content, err := os.ReadFile(filepath.Join(workflowsDir, "triage-platform.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Triage for platform")
+	assert.Contains(t, string(content), "path: .github/workflows/triage-template.md")
+	assert.FileExists(t, filepath.Join(workflowsDir, "triage-platform.lock.yml"))
+	assert.NoFileExists(t, filepath.Join(workflowsDir, "triage-template.lock.yml"), "templates should not be compiled")
+
+	err = RunInstantiate(InstantiateConfig{Template: "triage-template", Set: []string{"team=platform"}, Output: "triage-platform", NoCompile: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	matrixPath := filepath.Join(tmpDir, "teams.yml")
+	require.NoError(t, os.WriteFile(matrixPath, []byte(`instances:
+  - output: triage-docs.md
+    inputs:
+      team: docs
+      label: bug
+  - output: triage-cli.md
+    inputs:
+      team: cli
+`), 0644))
+	require.NoError(t, RunInstantiate(InstantiateConfig{Template: "triage-template", MatrixFile: matrixPath, NoCompile: true}))
+	content, err = os.ReadFile(filepath.Join(workflowsDir, "triage-docs.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "- bug")
+	assert.FileExists(t, filepath.Join(workflowsDir, "triage-cli.md"))
+
+	// Changing the template re-renders its instances on update
+	updated := strings.Replace(testTriageTemplate, "# Triage for", "# Issue triage for", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "triage-template.md"), []byte(updated), 0644))
+
+	instances, err := findWorkflowsWithTemplateSource(workflowsDir, []string{"triage-docs"}, false)
+	require.NoError(t, err)
+	require.Len(t, instances, 1)
+	changed, err := updateTemplateInstance(instances[0], false, "")
+	require.NoError(t, err)
+	assert.True(t, changed)
+	content, err = os.ReadFile(filepath.Join(workflowsDir, "triage-docs.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# Issue triage for docs")
+
+	changed, err = updateTemplateInstance(instances[0], false, "")
+	require.NoError(t, err)
+	assert.False(t, changed, "re-rendering an up to date instance should be a no-op")
+}
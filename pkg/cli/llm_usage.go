@@ -0,0 +1,298 @@
+// This file provides command-line interface functionality for gh-aw.
+// This file (llm_usage.go) contains functions for parsing the normalized LLM
+// usage log written by the AWF api-proxy sidecar.
+//
+// The compiler enables the api-proxy for the engines with an LLM gateway port (Claude and
+// Codex) and, from AWF v0.19.0, passes --api-proxy-usage-log so that it writes
+// one JSON line per LLM request to /tmp/gh-aw/sandbox/firewall/logs/llm-usage.jsonl,
+// which is uploaded with the firewall logs:
+//
+//	{"timestamp":"2026-01-05T10:00:00Z","provider":"anthropic","model":"claude-sonnet-4-20250514",
+//	 "prompt_tokens":1200,"cached_tokens":1000,"completion_tokens":300,"latency_ms":850,"status":200}
+//
+// prompt_tokens includes cached_tokens. The usage log is exact, so the logs and audit
+// commands prefer it over token counts scraped from engine logs.
+//
+// Key responsibilities:
+//   - Parsing llm-usage.jsonl files
+//   - Aggregating requests, tokens, latency and errors per model
+//   - Computing cost from the model price table
+//   - Rendering the per-model usage table
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/timeutil"
+)
+
+var llmUsageLog = logger.New("cli:llm_usage")
+
+// llmUsageLogFilename is the name of the usage log written by the api-proxy
+const llmUsageLogFilename = "llm-usage.jsonl"
+
+// LLMUsageEntry is a single request recorded in llm-usage.jsonl
+type LLMUsageEntry struct {
+	Timestamp        string  `json:"timestamp"`
+	Provider         string  `json:"provider,omitempty"`
+	Model            string  `json:"model"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CachedTokens     int     `json:"cached_tokens,omitempty"`
+	CompletionTokens int     `json:"completion_tokens"`
+	LatencyMs        float64 `json:"latency_ms,omitempty"`
+	Status           int     `json:"status"` // HTTP status of the upstream response (0 when the request failed)
+}
+
+// LLMModelUsage contains the usage of a single model
+type LLMModelUsage struct {
+	Model            string  `json:"model" console:"header:Model"`
+	Provider         string  `json:"provider,omitempty" console:"header:Provider,omitempty"`
+	Requests         int     `json:"requests" console:"header:Requests"`
+	PromptTokens     int     `json:"prompt_tokens" console:"header:Prompt,format:number"`
+	CachedTokens     int     `json:"cached_tokens,omitempty" console:"header:Cached,format:number,omitempty"`
+	CompletionTokens int     `json:"completion_tokens" console:"header:Completion,format:number"`
+	AvgLatency       string  `json:"avg_latency,omitempty" console:"header:Avg Latency,omitempty"`
+	Errors           int     `json:"errors,omitempty" console:"header:Errors,omitempty"`
+	Cost             float64 `json:"cost,omitempty" console:"header:Cost ($),format:cost,omitempty"`
+	Priced           bool    `json:"priced" console:"-"`
+	TotalLatencyMs   float64 `json:"total_latency_ms,omitempty" console:"-"`
+}
+
+// LLMUsageSummary contains the LLM usage of one or more runs
+type LLMUsageSummary struct {
+	Models         []LLMModelUsage `json:"models" console:"title:Usage by Model"`
+	TotalRequests  int             `json:"total_requests" console:"-"`
+	TotalTokens    int             `json:"total_tokens" console:"-"`
+	TotalCost      float64         `json:"total_cost" console:"-"`
+	UnpricedModels []string        `json:"unpriced_models,omitempty" console:"-"` // Models without an entry in the price table
+}
+
+// findLLMUsageLogs returns the llm-usage.jsonl files of a run directory
+func findLLMUsageLogs(logDir string) []string {
+	var paths []string
+	_ = filepath.Walk(logDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !info.IsDir() && info.Name() == llmUsageLogFilename {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// parseLLMUsageLogs parses the usage logs of a run directory and computes the cost of
+// each model with the price table. Returns nil when the run has no usage log.
+func parseLLMUsageLogs(logDir string, prices ModelPriceTable, verbose bool) (*LLMUsageSummary, error) {
+	paths := findLLMUsageLogs(logDir)
+	if len(paths) == 0 {
+		llmUsageLog.Printf("No %s found in %s", llmUsageLogFilename, logDir)
+		return nil, nil
+	}
+
+	var entries []LLMUsageEntry
+	for _, path := range paths {
+		fileEntries, err := readLLMUsageLog(path, verbose)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	llmUsageLog.Printf("Parsed %d LLM requests from %d usage logs", len(entries), len(paths))
+	return summarizeLLMUsage(entries, prices), nil
+}
+
+// readLLMUsageLog reads the entries of a usage log, skipping malformed lines
+func readLLMUsageLog(path string, verbose bool) ([]LLMUsageEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", llmUsageLogFilename, err)
+	}
+	defer file.Close()
+
+	var entries []LLMUsageEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry LLMUsageEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			llmUsageLog.Printf("Failed to parse line %d: %v", lineNum, err)
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to parse %s line %d: %v", llmUsageLogFilename, lineNum, err)))
+			}
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", llmUsageLogFilename, err)
+	}
+	return entries, nil
+}
+
+// summarizeLLMUsage aggregates usage entries per model
+func summarizeLLMUsage(entries []LLMUsageEntry, prices ModelPriceTable) *LLMUsageSummary {
+	byModel := make(map[string]*LLMModelUsage)
+	for _, entry := range entries {
+		model := entry.Model
+		if model == "" {
+			model = "unknown"
+		}
+		usage, ok := byModel[model]
+		if !ok {
+			usage = &LLMModelUsage{Model: model, Provider: entry.Provider}
+			_, usage.Priced = prices.lookup(model)
+			byModel[model] = usage
+		}
+		usage.Requests++
+		usage.PromptTokens += entry.PromptTokens
+		usage.CachedTokens += entry.CachedTokens
+		usage.CompletionTokens += entry.CompletionTokens
+		usage.TotalLatencyMs += entry.LatencyMs
+		if entry.Status == 0 || entry.Status >= 400 {
+			usage.Errors++
+		}
+		if price, ok := prices.lookup(model); ok {
+			usage.Cost += price.cost(entry.PromptTokens, entry.CachedTokens, entry.CompletionTokens)
+		}
+	}
+
+	models := make([]*LLMModelUsage, 0, len(byModel))
+	for _, usage := range byModel {
+		models = append(models, usage)
+	}
+	return buildLLMUsageSummary(models)
+}
+
+// buildLLMUsageSummary computes the totals of per-model usage, sorted by cost and then by name
+func buildLLMUsageSummary(models []*LLMModelUsage) *LLMUsageSummary {
+	sort.Slice(models, func(i, j int) bool {
+		if models[i].Cost != models[j].Cost {
+			return models[i].Cost > models[j].Cost
+		}
+		return models[i].Model < models[j].Model
+	})
+
+	summary := &LLMUsageSummary{Models: make([]LLMModelUsage, 0, len(models))}
+	for _, usage := range models {
+		if usage.Requests > 0 && usage.TotalLatencyMs > 0 {
+			avg := time.Duration(usage.TotalLatencyMs/float64(usage.Requests)) * time.Millisecond
+			usage.AvgLatency = timeutil.FormatDuration(avg)
+		}
+		summary.TotalRequests += usage.Requests
+		summary.TotalTokens += usage.PromptTokens + usage.CompletionTokens
+		summary.TotalCost += usage.Cost
+		if !usage.Priced {
+			summary.UnpricedModels = append(summary.UnpricedModels, usage.Model)
+		}
+		summary.Models = append(summary.Models, *usage)
+	}
+	sort.Strings(summary.UnpricedModels)
+	return summary
+}
+
+// applyLLMUsageToMetrics replaces the token usage and cost scraped from engine logs with
+// the values of the usage log. The scraped cost is kept when no model has a price.
+func applyLLMUsageToMetrics(metrics *LogMetrics, usage *LLMUsageSummary) {
+	if usage == nil || usage.TotalRequests == 0 {
+		return
+	}
+	metrics.TokenUsage = usage.TotalTokens
+	if len(usage.UnpricedModels) < len(usage.Models) {
+		metrics.EstimatedCost = usage.TotalCost
+	}
+}
+
+// aggregateLLMUsage merges the LLM usage of several runs
+func aggregateLLMUsage(processedRuns []ProcessedRun) *LLMUsageSummary {
+	byModel := make(map[string]*LLMModelUsage)
+	for _, pr := range processedRuns {
+		if pr.LLMUsage == nil {
+			continue
+		}
+		for _, usage := range pr.LLMUsage.Models {
+			existing, ok := byModel[usage.Model]
+			if !ok {
+				existing = &LLMModelUsage{Model: usage.Model, Provider: usage.Provider, Priced: usage.Priced}
+				byModel[usage.Model] = existing
+			}
+			existing.Requests += usage.Requests
+			existing.PromptTokens += usage.PromptTokens
+			existing.CachedTokens += usage.CachedTokens
+			existing.CompletionTokens += usage.CompletionTokens
+			existing.TotalLatencyMs += usage.TotalLatencyMs
+			existing.Errors += usage.Errors
+			existing.Cost += usage.Cost
+		}
+	}
+	if len(byModel) == 0 {
+		return nil
+	}
+
+	models := make([]*LLMModelUsage, 0, len(byModel))
+	for _, usage := range byModel {
+		models = append(models, usage)
+	}
+	return buildLLMUsageSummary(models)
+}
+
+// renderLLMUsageTable renders the per-model usage table
+func renderLLMUsageTable(usage *LLMUsageSummary) string {
+	config := console.TableConfig{
+		Headers:   []string{"Model", "Requests", "Prompt", "Cached", "Completion", "Avg Latency", "Errors", "Cost ($)"},
+		Rows:      make([][]string, 0, len(usage.Models)),
+		ShowTotal: true,
+	}
+	for _, model := range usage.Models {
+		cost := "-"
+		if model.Priced {
+			cost = fmt.Sprintf("%.3f", model.Cost)
+		}
+		latency := model.AvgLatency
+		if latency == "" {
+			latency = "-"
+		}
+		config.Rows = append(config.Rows, []string{
+			model.Model,
+			fmt.Sprintf("%d", model.Requests),
+			console.FormatNumber(model.PromptTokens),
+			console.FormatNumber(model.CachedTokens),
+			console.FormatNumber(model.CompletionTokens),
+			latency,
+			fmt.Sprintf("%d", model.Errors),
+			cost,
+		})
+	}
+	config.TotalRow = []string{
+		"TOTAL",
+		fmt.Sprintf("%d", usage.TotalRequests),
+		"", "", "", "", "",
+		fmt.Sprintf("%.3f", usage.TotalCost),
+	}
+
+	output := console.RenderTable(config)
+	if len(usage.UnpricedModels) > 0 {
+		output += console.FormatWarningMessage(fmt.Sprintf("No price for %s: add them to %s", strings.Join(usage.UnpricedModels, ", "), DefaultModelPricesPath)) + "\n"
+	}
+	return output
+}
@@ -0,0 +1,217 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleLLMUsageLog = `{"timestamp":"2026-01-05T10:00:00Z","provider":"anthropic","model":"claude-sonnet-4-20250514","prompt_tokens":10000,"cached_tokens":8000,"completion_tokens":1000,"latency_ms":900,"status":200}
+{"timestamp":"2026-01-05T10:00:05Z","provider":"anthropic","model":"claude-sonnet-4-20250514","prompt_tokens":12000,"cached_tokens":10000,"completion_tokens":500,"latency_ms":1100,"status":200}
+not json
+{"timestamp":"2026-01-05T10:00:09Z","provider":"anthropic","model":"claude-3-5-haiku-20241022","prompt_tokens":2000,"completion_tokens":100,"latency_ms":300,"status":529}
+{"timestamp":"2026-01-05T10:00:10Z","provider":"openai","model":"in-house-model","prompt_tokens":100,"completion_tokens":10,"status":200}
+`
+
+func writeLLMUsageLog(t *testing.T, dir string, content string) {
+	t.Helper()
+	logDir := filepath.Join(dir, "sandbox", "firewall", "logs", "api-proxy-logs")
+	require.NoError(t, os.MkdirAll(logDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(logDir, llmUsageLogFilename), []byte(content), 0644))
+}
+
+func TestModelPriceTableLookup(t *testing.T) {
+	tests := []struct {
+		model    string
+		expected ModelPrice
+		found    bool
+	}{
+		{model: "claude-sonnet-4", expected: defaultModelPrices["claude-sonnet-4"], found: true},
+		{model: "claude-sonnet-4-20250514", expected: defaultModelPrices["claude-sonnet-4"], found: true},
+		{model: "claude-opus-4-5-20251101", expected: defaultModelPrices["claude-opus-4-5"], found: true},
+		{model: "anthropic/claude-opus-4-1", expected: defaultModelPrices["claude-opus-4"], found: true},
+		{model: "GPT-5-mini", expected: defaultModelPrices["gpt-5-mini"], found: true},
+		{model: "gpt-4.1-mini", expected: defaultModelPrices["gpt-4.1-mini"], found: true},
+		{model: "gpt-5o", found: false},
+		{model: "llama-3", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.model, func(t *testing.T) {
+			price, found := defaultModelPrices.lookup(tt.model)
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.expected, price)
+		})
+	}
+}
+
+func TestModelPriceCost(t *testing.T) {
+	price := ModelPrice{Input: 3, CachedInput: 0.3, Output: 15}
+	// 2000 uncached prompt tokens, 8000 cached tokens and 1000 completion tokens
+	assert.InDelta(t, 0.006+0.0024+0.015, price.cost(10000, 8000, 1000), 1e-9)
+
+	noCachePrice := ModelPrice{Input: 1, Output: 2}
+	assert.InDelta(t, 0.001+0.002, noCachePrice.cost(1000, 500, 1000), 1e-9, "cached tokens should default to the input price")
+}
+
+func TestLoadModelPriceTable(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "model-prices")
+
+	prices, err := loadModelPriceTable(filepath.Join(tmpDir, "missing.json"))
+	require.NoError(t, err)
+	assert.Equal(t, defaultModelPrices["gpt-5"], prices["gpt-5"])
+
+	overridesPath := filepath.Join(tmpDir, "model-prices.json")
+	require.NoError(t, os.WriteFile(overridesPath, []byte(`{"models":{"gpt-5":{"input":1,"output":8},"In-House-Model":{"input":0.1,"output":0.2}}}`), 0644))
+	prices, err = loadModelPriceTable(overridesPath)
+	require.NoError(t, err)
+	assert.Equal(t, ModelPrice{Input: 1, Output: 8}, prices["gpt-5"])
+	assert.Equal(t, ModelPrice{Input: 0.1, Output: 0.2}, prices["in-house-model"])
+	assert.Equal(t, defaultModelPrices["claude-sonnet-4"], prices["claude-sonnet-4"])
+	assert.Equal(t, ModelPrice{Input: 1.25, CachedInput: 0.125, Output: 10}, defaultModelPrices["gpt-5"], "overrides should not change the default prices")
+
+	require.NoError(t, os.WriteFile(overridesPath, []byte(`{"models":{"gpt-5":{"input":-1,"output":8}}}`), 0644))
+	_, err = loadModelPriceTable(overridesPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be negative")
+}
+
+func TestParseLLMUsageLogs(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "llm-usage")
+
+	usage, err := parseLLMUsageLogs(tmpDir, defaultModelPrices, false)
+	require.NoError(t, err)
+	assert.Nil(t, usage, "runs without a usage log should have no usage")
+
+	writeLLMUsageLog(t, tmpDir, sampleLLMUsageLog)
+	usage, err = parseLLMUsageLogs(tmpDir, defaultModelPrices, false)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+
+	assert.Equal(t, 4, usage.TotalRequests)
+	assert.Equal(t, 22000+1500+2100+110, usage.TotalTokens)
+	assert.Equal(t, []string{"in-house-model"}, usage.UnpricedModels)
+	require.Len(t, usage.Models, 3)
+
+	sonnet := usage.Models[0]
+	assert.Equal(t, "claude-sonnet-4-20250514", sonnet.Model)
+	assert.Equal(t, 2, sonnet.Requests)
+	assert.Equal(t, 22000, sonnet.PromptTokens)
+	assert.Equal(t, 18000, sonnet.CachedTokens)
+	assert.Equal(t, 1500, sonnet.CompletionTokens)
+	assert.Equal(t, "1.0s", sonnet.AvgLatency)
+	assert.InDelta(t, (4000*3+18000*0.3+1500*15)/1_000_000.0, sonnet.Cost, 1e-9)
+
+	haiku := usage.Models[1]
+	assert.Equal(t, 1, haiku.Errors)
+	assert.InDelta(t, sonnet.Cost+haiku.Cost, usage.TotalCost, 1e-9)
+}
+
+// TestParseLLMUsageLogSample parses a usage log in the layout of a downloaded agent artifact,
+// at the path the compiler passes to the api-proxy with --api-proxy-usage-log
+func TestParseLLMUsageLogSample(t *testing.T) {
+	sample, err := os.ReadFile(filepath.Join("testdata", "llm-usage.jsonl"))
+	require.NoError(t, err)
+
+	tmpDir := testutil.TempDir(t, "llm-usage-sample")
+	logPath := filepath.Join(tmpDir, filepath.FromSlash(strings.TrimPrefix(constants.LLMUsageLogPath, "/tmp/gh-aw/")))
+	require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0755))
+	require.NoError(t, os.WriteFile(logPath, sample, 0644))
+
+	usage, err := parseLLMUsageLogs(tmpDir, defaultModelPrices, false)
+	require.NoError(t, err)
+	require.NotNil(t, usage)
+
+	assert.Equal(t, 7, usage.TotalRequests, "the truncated line should be skipped")
+	assert.Equal(t, 72886, usage.TotalTokens)
+	assert.Empty(t, usage.UnpricedModels)
+	require.Len(t, usage.Models, 4)
+
+	sonnet45 := usage.Models[0]
+	assert.Equal(t, "claude-sonnet-4-5-20250929", sonnet45.Model)
+	assert.Equal(t, 3, sonnet45.Requests)
+	assert.Equal(t, 39349, sonnet45.PromptTokens)
+	assert.Equal(t, 33408, sonnet45.CachedTokens)
+	assert.Equal(t, 2342, sonnet45.CompletionTokens)
+	assert.Equal(t, 1, sonnet45.Errors, "a failed request should count as an error")
+	assert.InDelta(t, (5941*3+33408*0.3+2342*15)/1_000_000.0, sonnet45.Cost, 1e-9)
+
+	sonnet4 := usage.Models[1]
+	assert.Equal(t, "claude-sonnet-4-20250514", sonnet4.Model)
+	assert.Equal(t, 1, sonnet4.Errors, "a rate limited request should count as an error")
+
+	assert.Equal(t, "gpt-5", usage.Models[2].Model)
+	assert.Equal(t, "gpt-5-mini", usage.Models[3].Model)
+	assert.InDelta(t, (5941*3+33408*0.3+2342*15+1896*3+17842*0.3+483*15+2304*1.25+4096*0.125+1210*10+3120*0.25+244*2)/1_000_000.0, usage.TotalCost, 1e-9)
+}
+
+func TestApplyLLMUsageToMetrics(t *testing.T) {
+	metrics := LogMetrics{TokenUsage: 100, EstimatedCost: 0.5, Turns: 3}
+	applyLLMUsageToMetrics(&metrics, &LLMUsageSummary{
+		Models:        []LLMModelUsage{{Model: "claude-sonnet-4", Requests: 2, Priced: true, Cost: 0.25}},
+		TotalRequests: 2,
+		TotalTokens:   5000,
+		TotalCost:     0.25,
+	})
+	assert.Equal(t, 5000, metrics.TokenUsage)
+	assert.InDelta(t, 0.25, metrics.EstimatedCost, 1e-9)
+	assert.Equal(t, 3, metrics.Turns)
+
+	metrics = LogMetrics{TokenUsage: 100, EstimatedCost: 0.5}
+	applyLLMUsageToMetrics(&metrics, &LLMUsageSummary{
+		Models:         []LLMModelUsage{{Model: "in-house-model", Requests: 1}},
+		TotalRequests:  1,
+		TotalTokens:    300,
+		UnpricedModels: []string{"in-house-model"},
+	})
+	assert.Equal(t, 300, metrics.TokenUsage)
+	assert.InDelta(t, 0.5, metrics.EstimatedCost, 1e-9, "the scraped cost should be kept when no model has a price")
+}
+
+func TestExtractLogMetricsPrefersLLMUsageLog(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "llm-usage-metrics")
+	writeLLMUsageLog(t, tmpDir, sampleLLMUsageLog)
+	agentLog := `{"type":"result","subtype":"success","num_turns":4,"total_cost_usd":9.99,"usage":{"input_tokens":10,"output_tokens":5}}`
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "agent-stdio.log"), []byte(agentLog), 0644))
+
+	metrics, err := extractLogMetrics(tmpDir, false)
+	require.NoError(t, err)
+	assert.Equal(t, 25710, metrics.TokenUsage)
+	assert.Less(t, metrics.EstimatedCost, 1.0, "the cost should be computed from the price table")
+}
+
+func TestAggregateLLMUsage(t *testing.T) {
+	runs := []ProcessedRun{
+		{LLMUsage: &LLMUsageSummary{Models: []LLMModelUsage{
+			{Model: "gpt-5", Requests: 2, PromptTokens: 100, CompletionTokens: 10, Cost: 0.5, Priced: true, TotalLatencyMs: 400},
+		}}},
+		{},
+		{LLMUsage: &LLMUsageSummary{Models: []LLMModelUsage{
+			{Model: "gpt-5", Requests: 1, PromptTokens: 50, CompletionTokens: 5, Cost: 0.25, Priced: true, TotalLatencyMs: 200},
+			{Model: "local", Requests: 3, PromptTokens: 30, CompletionTokens: 3},
+		}}},
+	}
+
+	usage := aggregateLLMUsage(runs)
+	require.NotNil(t, usage)
+	assert.Equal(t, 6, usage.TotalRequests)
+	assert.Equal(t, 198, usage.TotalTokens)
+	assert.InDelta(t, 0.75, usage.TotalCost, 1e-9)
+	assert.Equal(t, []string{"local"}, usage.UnpricedModels)
+	assert.Equal(t, "gpt-5", usage.Models[0].Model)
+	assert.Equal(t, "200ms", usage.Models[0].AvgLatency)
+
+	assert.Nil(t, aggregateLLMUsage([]ProcessedRun{{}}))
+
+	output := renderLLMUsageTable(usage)
+	assert.Contains(t, output, "gpt-5")
+	assert.Contains(t, output, DefaultModelPricesPath)
+}
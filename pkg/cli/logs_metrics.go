@@ -184,6 +184,18 @@ func extractLogMetrics(logDir string, verbose bool, workflowPath ...string) (Log
 		}
 	}
 
+	// Prefer the exact token counts of the api-proxy usage log over scraped engine logs
+	llmUsage, llmUsageErr := parseLLMUsageLogs(logDir, loadRepositoryModelPrices(), verbose)
+	if llmUsageErr != nil {
+		logsMetricsLog.Printf("Failed to parse LLM usage log: %v", llmUsageErr)
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to parse LLM usage log: %v", llmUsageErr)))
+		}
+	} else if llmUsage != nil {
+		logsMetricsLog.Printf("Using LLM usage log: requests=%d, tokens=%d, cost=%.4f", llmUsage.TotalRequests, llmUsage.TotalTokens, llmUsage.TotalCost)
+		applyLLMUsageToMetrics(&metrics, llmUsage)
+	}
+
 	if logsMetricsLog.Enabled() {
 		logsMetricsLog.Printf("Metrics extraction completed: tokens=%d, cost=%.4f, turns=%d",
 			metrics.TokenUsage, metrics.EstimatedCost, metrics.Turns)
@@ -76,6 +76,8 @@ type ProcessedRun struct {
 	Noops                   []NoopReport
 	MCPFailures             []MCPFailureReport
 	MCPToolUsage            *MCPToolUsageData
+	LLMUsage                *LLMUsageSummary
+	FilesystemViolations    []FilesystemViolation
 	JobDetails              []JobInfoWithDuration
 }
 
@@ -185,8 +187,10 @@ type RunSummary struct {
 	Noops                   []NoopReport             `json:"noops"`                     // Noop messages
 	MCPFailures             []MCPFailureReport       `json:"mcp_failures"`              // MCP server failures
 	MCPToolUsage            *MCPToolUsageData        `json:"mcp_tool_usage,omitempty"`  // MCP tool usage data
+	LLMUsage                *LLMUsageSummary         `json:"llm_usage,omitempty"`       // LLM usage from the api-proxy usage log
 	ArtifactsList           []string                 `json:"artifacts_list"`            // List of downloaded artifact files
 	JobDetails              []JobInfoWithDuration    `json:"job_details"`               // Job execution details
+	EngineAttempts          []EngineAttempt          `json:"engine_attempts,omitempty"` // Engines fallen back from before the output was produced
 }
 
 // DownloadResult represents the result of downloading and processing a workflow run
@@ -201,6 +205,7 @@ type DownloadResult struct {
 	Noops                   []NoopReport
 	MCPFailures             []MCPFailureReport
 	MCPToolUsage            *MCPToolUsageData
+	LLMUsage                *LLMUsageSummary
 	JobDetails              []JobInfoWithDuration
 	Error                   error
 	Skipped                 bool
@@ -245,6 +250,17 @@ type AwInfo struct {
 	RunID      any    `json:"run_id,omitempty"`
 	RunNumber  any    `json:"run_number,omitempty"`
 	Repository string `json:"repository,omitempty"`
+	// Engines of the fallback chain that failed before EngineID produced the output
+	EngineAttempts []EngineAttempt `json:"engine_attempts,omitempty"`
+	// Filesystem policy of the agent sandbox
+	SandboxFilesystem *SandboxFilesystemPolicy `json:"sandbox_filesystem,omitempty"`
+}
+
+// EngineAttempt records an engine of a fallback chain that failed and was fallen back from
+type EngineAttempt struct {
+	EngineID string `json:"engine_id"`
+	Model    string `json:"model,omitempty"`
+	Reason   string `json:"reason,omitempty"` // Error in the agent log that triggered the fallback
 }
 
 // GetFirewallVersion returns the AWF firewall version, preferring the new field name
@@ -342,6 +342,7 @@ func DownloadWorkflowLogs(ctx context.Context, workflowName string, count int, s
 					Noops:                   result.Noops,
 					MCPFailures:             result.MCPFailures,
 					MCPToolUsage:            result.MCPToolUsage,
+					LLMUsage:                result.LLMUsage,
 					JobDetails:              result.JobDetails,
 				}
 				processedRuns = append(processedRuns, processedRun)
@@ -591,6 +592,7 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 					Noops:                   summary.Noops,
 					MCPFailures:             summary.MCPFailures,
 					MCPToolUsage:            summary.MCPToolUsage,
+					LLMUsage:                summary.LLMUsage,
 					JobDetails:              summary.JobDetails,
 					LogsPath:                runOutputDir,
 					Cached:                  true, // Mark as cached
@@ -717,6 +719,15 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 				}
 				result.MCPToolUsage = mcpToolUsage
 
+				// Extract LLM usage from the api-proxy usage log if available
+				llmUsage, llmUsageErr := parseLLMUsageLogs(runOutputDir, loadRepositoryModelPrices(), verbose)
+				if llmUsageErr != nil {
+					if verbose {
+						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract LLM usage for run %d: %v", run.DatabaseID, llmUsageErr)))
+					}
+				}
+				result.LLMUsage = llmUsage
+
 				// Fetch job details for the summary
 				jobDetails, jobErr := fetchJobDetails(run.DatabaseID, verbose)
 				if jobErr != nil {
@@ -748,8 +759,10 @@ func downloadRunArtifactsConcurrent(ctx context.Context, runs []WorkflowRun, out
 					Noops:                   noops,
 					MCPFailures:             mcpFailures,
 					MCPToolUsage:            mcpToolUsage,
+					LLMUsage:                llmUsage,
 					ArtifactsList:           artifacts,
 					JobDetails:              jobDetails,
+					EngineAttempts:          extractEngineAttemptsFromAwInfo(filepath.Join(runOutputDir, "aw_info.json"), false),
 				}
 
 				if saveErr := saveRunSummary(runOutputDir, summary, verbose); saveErr != nil {
@@ -108,6 +108,18 @@ func extractEngineFromAwInfo(infoFilePath string, verbose bool) workflow.CodingA
 	return engine
 }
 
+// extractEngineAttemptsFromAwInfo reads aw_info.json and returns the engines the run fell back from
+func extractEngineAttemptsFromAwInfo(infoFilePath string, verbose bool) []EngineAttempt {
+	info, err := parseAwInfo(infoFilePath, verbose)
+	if err != nil {
+		return nil
+	}
+	if len(info.EngineAttempts) > 0 {
+		logsParsingCoreLog.Printf("Run fell back from %d engines to %s", len(info.EngineAttempts), info.EngineID)
+	}
+	return info.EngineAttempts
+}
+
 // findAgentOutputFile searches for a file named agent_output.json within the logDir tree.
 // Returns the first path found (depth-first) and a boolean indicating success.
 func findAgentOutputFile(logDir string) (string, bool) {
@@ -791,3 +791,28 @@ Estimated cost: $0.067
 		t.Errorf("Expected non-negative token usage, got %d", metrics.TokenUsage)
 	}
 }
+
+func TestExtractEngineAttemptsFromAwInfo(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "test-*")
+	awInfoPath := filepath.Join(tmpDir, "aw_info.json")
+
+	if attempts := extractEngineAttemptsFromAwInfo(awInfoPath, false); attempts != nil {
+		t.Errorf("Expected no attempts without aw_info.json, got %v", attempts)
+	}
+
+	awInfo := `{"engine_id":"copilot","engine_name":"GitHub Copilot CLI","engine_attempts":[{"engine_id":"claude","model":"claude-sonnet-4","reason":"rate_limit_error"}]}`
+	if err := os.WriteFile(awInfoPath, []byte(awInfo), 0644); err != nil {
+		t.Fatalf("Failed to write aw_info.json: %v", err)
+	}
+
+	attempts := extractEngineAttemptsFromAwInfo(awInfoPath, false)
+	if len(attempts) != 1 {
+		t.Fatalf("Expected 1 engine attempt, got %d", len(attempts))
+	}
+	if attempts[0].EngineID != "claude" || attempts[0].Model != "claude-sonnet-4" || attempts[0].Reason != "rate_limit_error" {
+		t.Errorf("Unexpected engine attempt: %+v", attempts[0])
+	}
+	if engine := extractEngineFromAwInfo(awInfoPath, false); engine == nil || engine.GetID() != "copilot" {
+		t.Errorf("Expected the fallback engine to be detected as the run engine")
+	}
+}
@@ -23,6 +23,8 @@ type LogsData struct {
 	Runs              []RunData                  `json:"runs" console:"title:Workflow Logs Overview"`
 	ToolUsage         []ToolUsageSummary         `json:"tool_usage,omitempty" console:"title:🛠️  Tool Usage Summary,omitempty"`
 	MCPToolUsage      *MCPToolUsageSummary       `json:"mcp_tool_usage,omitempty" console:"title:🔧 MCP Tool Usage,omitempty"`
+	LLMUsage          *LLMUsageSummary           `json:"llm_usage,omitempty" console:"title:💰 LLM Usage,omitempty"`
+	ModelCosts        []ModelCostSummary         `json:"model_costs,omitempty" console:"title:💰 Cost by Model,omitempty"`
 	ErrorsAndWarnings []ErrorSummary             `json:"errors_and_warnings,omitempty" console:"title:Errors and Warnings,omitempty"`
 	MissingTools      []MissingToolSummary       `json:"missing_tools,omitempty" console:"title:🛠️  Missing Tools Summary,omitempty"`
 	MissingData       []MissingDataSummary       `json:"missing_data,omitempty" console:"title:📊 Missing Data Summary,omitempty"`
@@ -68,6 +70,8 @@ type RunData struct {
 	WorkflowName     string    `json:"workflow_name" console:"header:Workflow"`
 	WorkflowPath     string    `json:"workflow_path" console:"-"`
 	Agent            string    `json:"agent,omitempty" console:"header:Agent,omitempty"`
+	Model            string    `json:"model,omitempty" console:"header:Model,omitempty"`
+	FallbackFrom     string    `json:"fallback_from,omitempty" console:"header:Fallback From,omitempty"`
 	Status           string    `json:"status" console:"header:Status"`
 	Conclusion       string    `json:"conclusion,omitempty" console:"-"`
 	Duration         string    `json:"duration,omitempty" console:"header:Duration,omitempty"`
@@ -87,6 +91,14 @@ type RunData struct {
 	Branch           string    `json:"branch" console:"-"`
 }
 
+// ModelCostSummary contains the runs, tokens and cost of the runs that used a model
+type ModelCostSummary struct {
+	Model  string  `json:"model" console:"header:Model"`
+	Runs   int     `json:"runs" console:"header:Runs"`
+	Tokens int     `json:"tokens" console:"header:Tokens,format:number"`
+	Cost   float64 `json:"cost" console:"header:Cost ($),format:cost"`
+}
+
 // ToolUsageSummary contains aggregated tool usage statistics
 type ToolUsageSummary struct {
 	Name          string `json:"name" console:"header:Tool"`
@@ -162,9 +174,15 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 
 		// Extract agent/engine ID from aw_info.json
 		agentID := ""
+		model := ""
+		var fallbackFrom []string
 		awInfoPath := filepath.Join(run.LogsPath, "aw_info.json")
 		if info, err := parseAwInfo(awInfoPath, false); err == nil && info != nil {
 			agentID = info.EngineID
+			model = info.Model
+			for _, attempt := range info.EngineAttempts {
+				fallbackFrom = append(fallbackFrom, attempt.EngineID)
+			}
 		}
 
 		runData := RunData{
@@ -173,6 +191,8 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 			WorkflowName:     run.WorkflowName,
 			WorkflowPath:     run.WorkflowPath,
 			Agent:            agentID,
+			Model:            model,
+			FallbackFrom:     strings.Join(fallbackFrom, ", "),
 			Status:           run.Status,
 			Conclusion:       run.Conclusion,
 			TokenUsage:       run.TokenUsage,
@@ -226,6 +246,12 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 	// Build MCP tool usage summary
 	mcpToolUsage := buildMCPToolUsageSummary(processedRuns)
 
+	// Build LLM usage summary from the api-proxy usage logs
+	llmUsage := aggregateLLMUsage(processedRuns)
+
+	// Build cost by model summary from the models recorded in aw_info.json
+	modelCosts := buildModelCostSummary(runs)
+
 	// Build access log summary
 	accessLog := buildAccessLogSummary(processedRuns)
 
@@ -242,6 +268,8 @@ func buildLogsData(processedRuns []ProcessedRun, outputDir string, continuation
 		Runs:              runs,
 		ToolUsage:         toolUsage,
 		MCPToolUsage:      mcpToolUsage,
+		LLMUsage:          llmUsage,
+		ModelCosts:        modelCosts,
 		ErrorsAndWarnings: errorsAndWarnings,
 		MissingTools:      missingTools,
 		MissingData:       missingData,
@@ -412,6 +440,45 @@ func aggregateSummaryItems[TItem any, TSummary any](
 	return result
 }
 
+// buildModelCostSummary groups the tokens and cost of runs by the model recorded in aw_info.json,
+// which is the model selected by routing rules when the workflow uses them. Returns nil when no
+// run recorded a model.
+func buildModelCostSummary(runs []RunData) []ModelCostSummary {
+	byModel := make(map[string]*ModelCostSummary)
+	hasModel := false
+	for _, run := range runs {
+		model := run.Model
+		if model == "" {
+			model = "(default)"
+		} else {
+			hasModel = true
+		}
+		summary, ok := byModel[model]
+		if !ok {
+			summary = &ModelCostSummary{Model: model}
+			byModel[model] = summary
+		}
+		summary.Runs++
+		summary.Tokens += run.TokenUsage
+		summary.Cost += run.EstimatedCost
+	}
+	if !hasModel {
+		return nil
+	}
+
+	result := make([]ModelCostSummary, 0, len(byModel))
+	for _, summary := range byModel {
+		result = append(result, *summary)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Cost != result[j].Cost {
+			return result[i].Cost > result[j].Cost
+		}
+		return result[i].Model < result[j].Model
+	})
+	return result
+}
+
 // buildMissingToolsSummary aggregates missing tools across all runs
 func buildMissingToolsSummary(processedRuns []ProcessedRun) []MissingToolSummary {
 	result := aggregateSummaryItems(
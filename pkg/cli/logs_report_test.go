@@ -847,3 +847,31 @@ func TestBuildLogsDataIncludesDateFields(t *testing.T) {
 		t.Errorf("Expected UpdatedAt = %v, got %v", updatedAt, run.UpdatedAt)
 	}
 }
+
+func TestBuildModelCostSummary(t *testing.T) {
+	runs := []RunData{
+		{Model: "claude-haiku-4-5", TokenUsage: 1000, EstimatedCost: 0.01},
+		{Model: "claude-opus-4-5", TokenUsage: 5000, EstimatedCost: 0.50},
+		{Model: "claude-haiku-4-5", TokenUsage: 2000, EstimatedCost: 0.02},
+		{TokenUsage: 100, EstimatedCost: 0.001},
+	}
+
+	summary := buildModelCostSummary(runs)
+	if len(summary) != 3 {
+		t.Fatalf("expected 3 models, got %d", len(summary))
+	}
+	if summary[0].Model != "claude-opus-4-5" {
+		t.Errorf("expected the most expensive model first, got %s", summary[0].Model)
+	}
+	haiku := summary[1]
+	if haiku.Model != "claude-haiku-4-5" || haiku.Runs != 2 || haiku.Tokens != 3000 {
+		t.Errorf("unexpected haiku summary: %+v", haiku)
+	}
+	if summary[2].Model != "(default)" {
+		t.Errorf("expected runs without a model to be grouped as (default), got %s", summary[2].Model)
+	}
+
+	if got := buildModelCostSummary([]RunData{{TokenUsage: 10}}); got != nil {
+		t.Errorf("expected no summary when no run recorded a model, got %+v", got)
+	}
+}
@@ -23,12 +23,18 @@ Available subcommands:
   • list       - List MCP servers defined in agentic workflows
   • list-tools - List available tools for a specific MCP server
   • inspect    - Inspect MCP servers and list available tools, resources, and roots
+  • check      - Run conformance checks against MCP servers
+  • render     - Print the MCP configuration an engine receives
   • add        - Add an MCP tool to an agentic workflow
+  • registry   - Manage local mirrors of MCP registries
 
 Examples:
   gh aw mcp list                              # List all workflows with MCP servers
   gh aw mcp inspect weekly-research           # Inspect MCP servers in workflow
   gh aw mcp add my-workflow tavily            # Add Tavily MCP server to workflow
+  gh aw mcp check weekly-research             # Check that MCP servers behave
+  gh aw mcp render weekly-research -e codex   # Show the MCP config Codex receives
+  gh aw mcp registry sync ./mcp-mirror        # Mirror the MCP registry for offline use
   gh aw mcp inspect weekly-research --server github --tool create_issue  # Inspect specific tool`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
@@ -40,6 +46,9 @@ Examples:
 	cmd.AddCommand(NewMCPListSubcommand())
 	cmd.AddCommand(NewMCPListToolsSubcommand())
 	cmd.AddCommand(NewMCPInspectSubcommand())
+	cmd.AddCommand(NewMCPCheckSubcommand())
+	cmd.AddCommand(NewMCPRenderSubcommand())
+	cmd.AddCommand(NewMCPRegistrySubcommand())
 
 	return cmd
 }
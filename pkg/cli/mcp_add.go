@@ -17,7 +17,7 @@ import (
 var mcpAddLog = logger.New("cli:mcp_add")
 
 // AddMCPTool adds an MCP tool to an agentic workflow
-func AddMCPTool(workflowFile string, mcpServerID string, registryURL string, transportType string, customToolID string, verbose bool) error {
+func AddMCPTool(workflowFile string, mcpServerID string, registryURL string, transportType string, customToolID string, policyPath string, verbose bool) error {
 	mcpAddLog.Printf("Adding MCP tool: serverID=%s, registryURL=%s, transport=%s", mcpServerID, registryURL, transportType)
 
 	// Resolve the workflow file path
@@ -52,6 +52,18 @@ func AddMCPTool(workflowFile string, mcpServerID string, registryURL string, tra
 		return fmt.Errorf("no MCP servers found matching '%s'", mcpServerID)
 	}
 
+	// Only offer servers approved by the organization policy
+	policy, err := loadPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	workflowID := workflow.GetWorkflowIDFromPath(workflowPath)
+	approved, rejected := filterApprovedMCPServers(servers, policy, workflowID)
+	if len(approved) == 0 {
+		return fmt.Errorf("MCP server '%s' is not approved by the organization policy %s (matching servers: %s)", mcpServerID, policy.Source, strings.Join(rejected, ", "))
+	}
+	servers = approved
+
 	// Find exact match by name first, then by partial match
 	var selectedServer *MCPRegistryServerForProcessing
 	for i, server := range servers {
@@ -116,8 +128,17 @@ func AddMCPTool(workflowFile string, mcpServerID string, registryURL string, tra
 		}
 	}
 
+	// Servers added from a local mirror reference the registry they were mirrored from,
+	// so that the workflow does not record a path on this machine
+	sourceRegistryURL := registryClient.registryURL
+	if _, ok := localRegistryDir(registryClient.registryURL); ok {
+		if source := mirroredRegistrySource(selectedServer.Name); source != "" {
+			sourceRegistryURL = source
+		}
+	}
+
 	// Create MCP tool configuration based on server info and preferences
-	mcpConfig, err := createMCPToolConfig(selectedServer, transportType, registryClient.registryURL, verbose)
+	mcpConfig, err := createMCPToolConfig(selectedServer, transportType, sourceRegistryURL, verbose)
 	if err != nil {
 		return fmt.Errorf("failed to create MCP tool configuration: %w", err)
 	}
@@ -161,6 +182,24 @@ func AddMCPTool(workflowFile string, mcpServerID string, registryURL string, tra
 	return nil
 }
 
+// filterApprovedMCPServers splits registry servers into those approved by the policy for
+// the workflow and the names of those that are not
+func filterApprovedMCPServers(servers []MCPRegistryServerForProcessing, policy *workflow.Policy, workflowID string) ([]MCPRegistryServerForProcessing, []string) {
+	var approved []MCPRegistryServerForProcessing
+	var rejected []string
+	for _, server := range servers {
+		if policy.AllowsMCPServer(workflowID, server.Name) {
+			approved = append(approved, server)
+		} else {
+			rejected = append(rejected, server.Name)
+		}
+	}
+	if len(rejected) > 0 {
+		mcpAddLog.Printf("Policy rejected %d MCP server(s): %v", len(rejected), rejected)
+	}
+	return approved, rejected
+}
+
 // createMCPToolConfig creates the MCP tool configuration based on registry server info
 func createMCPToolConfig(server *MCPRegistryServerForProcessing, preferredTransport string, registryURL string, verbose bool) (map[string]any, error) {
 	config := make(map[string]any)
@@ -325,9 +364,11 @@ Examples:
   gh aw mcp add weekly-research makenotion/notion-mcp-server --transport stdio  # Prefer stdio transport
   gh aw mcp add weekly-research makenotion/notion-mcp-server --registry https://custom.registry.com/v1  # Use custom registry
   gh aw mcp add weekly-research makenotion/notion-mcp-server --tool-id my-notion  # Use custom tool ID
+  gh aw mcp add weekly-research notion --registry file:///srv/mcp-mirror  # Use a local mirror (see 'mcp registry sync')
 
 The command will:
 - Search the MCP registry for the specified server
+- Only offer servers approved by the mcp-servers rules of the organization policy
 - Check that the tool doesn't already exist in the workflow
 - Add the MCP tool configuration to the workflow's frontmatter
 - Automatically compile the workflow to generate the .lock.yml file
@@ -343,7 +384,8 @@ Registry URL defaults to: https://api.mcp.github.com/v0.1`,
 				if registryURL == "" {
 					registryURL = string(constants.DefaultMCPRegistryURL)
 				}
-				return listAvailableServers(registryURL, verbose)
+				policyPath, _ := cmd.Flags().GetString("policy")
+				return listAvailableServers(registryURL, policyPath, verbose)
 			}
 
 			// If only workflow ID/file is provided, show error (need both workflow and server)
@@ -355,13 +397,15 @@ Registry URL defaults to: https://api.mcp.github.com/v0.1`,
 			workflowFile := args[0]
 			mcpServerID := args[1]
 
-			return AddMCPTool(workflowFile, mcpServerID, registryURL, transportType, customToolID, verbose)
+			policyPath, _ := cmd.Flags().GetString("policy")
+			return AddMCPTool(workflowFile, mcpServerID, registryURL, transportType, customToolID, policyPath, verbose)
 		},
 	}
 
 	cmd.Flags().StringVar(&registryURL, "registry", "", "MCP registry URL (default: https://api.mcp.github.com/v0)")
 	cmd.Flags().StringVar(&transportType, "transport", "", "Preferred transport type (stdio, http, docker)")
 	cmd.Flags().StringVar(&customToolID, "tool-id", "", "Custom tool ID to use in the workflow (default: uses server ID)")
+	addPolicyFlag(cmd)
 
 	return cmd
 }
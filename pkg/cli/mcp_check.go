@@ -0,0 +1,448 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/spf13/cobra"
+)
+
+var mcpCheckLog = logger.New("cli:mcp_check")
+
+// MCP check statuses
+const (
+	MCPCheckPass = "pass"
+	MCPCheckWarn = "warn"
+	MCPCheckFail = "fail"
+	MCPCheckSkip = "skip"
+)
+
+// MCPCheckOptions configures the MCP conformance checks
+type MCPCheckOptions struct {
+	CallTools      []string      // Tools that may be called with sample inputs (dry-run allowlist)
+	StartupTimeout time.Duration // Maximum time for the initialize handshake (tools.startup-timeout)
+	ToolTimeout    time.Duration // Maximum time for a single tool call (tools.timeout)
+	Verbose        bool
+}
+
+// MCPCheckResult is the outcome of a single conformance check
+type MCPCheckResult struct {
+	Check      string `json:"check"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	Message    string `json:"message,omitempty"`
+}
+
+// MCPServerCheckReport holds the conformance results for one MCP server
+type MCPServerCheckReport struct {
+	Server           string           `json:"server"`
+	Type             string           `json:"type"`
+	ProtocolVersion  string           `json:"protocol_version,omitempty"`
+	StartupLatencyMs int64            `json:"startup_latency_ms"`
+	Checks           []MCPCheckResult `json:"checks"`
+}
+
+// Failed returns true if any check of the server failed
+func (r *MCPServerCheckReport) Failed() bool {
+	for _, check := range r.Checks {
+		if check.Status == MCPCheckFail {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MCPServerCheckReport) add(check, status string, duration time.Duration, message string) {
+	r.Checks = append(r.Checks, MCPCheckResult{
+		Check:      check,
+		Status:     status,
+		DurationMs: duration.Milliseconds(),
+		Message:    message,
+	})
+}
+
+// CheckWorkflowMCP runs the conformance checks against the MCP servers of a workflow
+func CheckWorkflowMCP(workflowFile string, serverFilter string, callTools []string, jsonOutput bool, verbose bool) error {
+	mcpCheckLog.Printf("Checking workflow MCP servers: workflow=%s, serverFilter=%s, callTools=%v", workflowFile, serverFilter, callTools)
+
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(workflowPath) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		workflowPath = filepath.Join(cwd, workflowPath)
+	}
+
+	// Use the compiler to parse the workflow so imports and tool timeouts are resolved
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+
+	mcpConfigs, err := parser.ExtractMCPConfigurations(buildFrontmatterFromWorkflowData(workflowData), serverFilter)
+	if err != nil {
+		return fmt.Errorf("failed to extract MCP configurations: %w", err)
+	}
+	mcpConfigs = filterOutSafeOutputs(mcpConfigs)
+
+	if len(mcpConfigs) == 0 {
+		if serverFilter != "" {
+			return fmt.Errorf("no MCP server matching '%s' found in workflow", serverFilter)
+		}
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No MCP servers found in workflow"))
+		return nil
+	}
+
+	opts := MCPCheckOptions{
+		CallTools:      callTools,
+		StartupTimeout: constants.DefaultMCPStartupTimeout,
+		ToolTimeout:    constants.DefaultToolTimeout,
+		Verbose:        verbose,
+	}
+	if workflowData.ToolsStartupTimeout > 0 {
+		opts.StartupTimeout = time.Duration(workflowData.ToolsStartupTimeout) * time.Second
+	}
+	if workflowData.ToolsTimeout > 0 {
+		opts.ToolTimeout = time.Duration(workflowData.ToolsTimeout) * time.Second
+	}
+
+	var reports []*MCPServerCheckReport
+	for _, config := range mcpConfigs {
+		if !jsonOutput {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Checking MCP server: %s (%s)", config.Name, buildConnectionString(config))))
+		}
+		reports = append(reports, checkMCPServer(config, opts))
+	}
+
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal check results: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		for _, report := range reports {
+			renderMCPCheckReport(report)
+		}
+	}
+
+	var failed []string
+	for _, report := range reports {
+		if report.Failed() {
+			failed = append(failed, report.Server)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("MCP conformance checks failed for: %s", strings.Join(failed, ", "))
+	}
+
+	if !jsonOutput {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("All %d MCP server(s) passed the conformance checks", len(reports))))
+	}
+	return nil
+}
+
+// checkMCPServer connects to an MCP server and runs the conformance checks
+func checkMCPServer(config parser.MCPServerConfig, opts MCPCheckOptions) *MCPServerCheckReport {
+	report := &MCPServerCheckReport{Server: config.Name, Type: config.Type}
+
+	if err := validateServerSecrets(config, opts.Verbose, false); err != nil {
+		report.add("initialize", MCPCheckFail, 0, fmt.Sprintf("secret validation failed: %v", err))
+		return report
+	}
+
+	transport, err := newMCPTransport(config)
+	if err != nil {
+		report.add("initialize", MCPCheckFail, 0, err.Error())
+		return report
+	}
+
+	runMCPConformanceChecks(context.Background(), newMCPInspectorClient(), transport, report, opts)
+	return report
+}
+
+// runMCPConformanceChecks runs the handshake, tool schema and tool call checks over a transport
+func runMCPConformanceChecks(ctx context.Context, client *mcp.Client, transport mcp.Transport, report *MCPServerCheckReport, opts MCPCheckOptions) {
+	// Initialize handshake, bounded by the startup timeout of the workflow
+	connectCtx, cancel := context.WithTimeout(ctx, opts.StartupTimeout)
+	defer cancel()
+
+	start := time.Now()
+	session, err := client.Connect(connectCtx, transport, nil)
+	latency := time.Since(start)
+	report.StartupLatencyMs = latency.Milliseconds()
+	if err != nil {
+		message := fmt.Sprintf("handshake failed: %v", err)
+		if errors.Is(connectCtx.Err(), context.DeadlineExceeded) {
+			message = fmt.Sprintf("server did not complete the handshake within tools.startup-timeout (%s)", opts.StartupTimeout)
+		}
+		report.add("initialize", MCPCheckFail, latency, message)
+		return
+	}
+	defer session.Close()
+
+	mcpCheckLog.Printf("Connected to %s in %s", report.Server, latency)
+	if initResult := session.InitializeResult(); initResult == nil || initResult.ServerInfo == nil || initResult.ServerInfo.Name == "" || initResult.ProtocolVersion == "" {
+		report.add("initialize", MCPCheckFail, latency, "initialize result is missing serverInfo or protocolVersion")
+	} else {
+		report.ProtocolVersion = initResult.ProtocolVersion
+		status := MCPCheckPass
+		message := fmt.Sprintf("%s %s, protocol %s", initResult.ServerInfo.Name, initResult.ServerInfo.Version, initResult.ProtocolVersion)
+		// Servers that use most of the startup budget are likely to time out on slower runners
+		if latency > opts.StartupTimeout/2 {
+			status = MCPCheckWarn
+			message += fmt.Sprintf("; startup took more than half of tools.startup-timeout (%s)", opts.StartupTimeout)
+		}
+		report.add("initialize", status, latency, message)
+	}
+
+	// List tools
+	listCtx, cancel := context.WithTimeout(ctx, opts.ToolTimeout)
+	defer cancel()
+	start = time.Now()
+	toolsResult, err := session.ListTools(listCtx, &mcp.ListToolsParams{})
+	if err != nil {
+		report.add("tools/list", MCPCheckFail, time.Since(start), err.Error())
+		return
+	}
+	report.add("tools/list", MCPCheckPass, time.Since(start), fmt.Sprintf("%d tool(s)", len(toolsResult.Tools)))
+
+	// Validate tool schemas and call the allowlisted tools with sample inputs
+	var skipped []string
+	for _, tool := range toolsResult.Tools {
+		schemaCheck := "schema " + tool.Name
+		compiled, err := compileMCPToolInputSchema(tool)
+		if err != nil {
+			report.add(schemaCheck, MCPCheckFail, 0, err.Error())
+			continue
+		}
+		report.add(schemaCheck, MCPCheckPass, 0, "")
+
+		if !slices.Contains(opts.CallTools, tool.Name) {
+			skipped = append(skipped, tool.Name)
+			continue
+		}
+		callMCPToolWithSampleInput(ctx, session, tool, compiled, report, opts.ToolTimeout)
+	}
+
+	for _, name := range opts.CallTools {
+		if !slices.ContainsFunc(toolsResult.Tools, func(tool *mcp.Tool) bool { return tool.Name == name }) {
+			report.add("call "+name, MCPCheckFail, 0, "tool is not provided by the server")
+		}
+	}
+	if len(skipped) > 0 {
+		report.add("tools/call", MCPCheckSkip, 0, fmt.Sprintf("%d tool(s) not called; allow them with --call", len(skipped)))
+	}
+}
+
+// callMCPToolWithSampleInput calls a tool with inputs generated from its schema, bounded by the tool timeout
+func callMCPToolWithSampleInput(ctx context.Context, session *mcp.ClientSession, tool *mcp.Tool, compiled *jsonschema.Schema, report *MCPServerCheckReport, toolTimeout time.Duration) {
+	check := "call " + tool.Name
+	schema, _ := normalizeMCPToolSchema(tool.InputSchema)
+	input := generateSampleInput(schema)
+	if err := compiled.Validate(input); err != nil {
+		report.add(check, MCPCheckSkip, 0, "could not generate a valid sample input from the schema")
+		return
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, toolTimeout)
+	defer cancel()
+
+	start := time.Now()
+	result, err := session.CallTool(callCtx, &mcp.CallToolParams{Name: tool.Name, Arguments: input})
+	duration := time.Since(start)
+	switch {
+	case errors.Is(callCtx.Err(), context.DeadlineExceeded):
+		report.add(check, MCPCheckFail, duration, fmt.Sprintf("call did not complete within tools.timeout (%s)", toolTimeout))
+	case err != nil:
+		report.add(check, MCPCheckFail, duration, err.Error())
+	case result.IsError:
+		// Tool errors are valid responses; sample inputs may not reference real data
+		report.add(check, MCPCheckWarn, duration, "tool returned an error result for the sample input")
+	default:
+		report.add(check, MCPCheckPass, duration, "")
+	}
+}
+
+// normalizeMCPToolSchema converts a tool schema returned by the client into a generic JSON map
+func normalizeMCPToolSchema(schema any) (map[string]any, error) {
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// compileMCPToolInputSchema checks that the input schema of a tool is a valid JSON Schema for an object
+func compileMCPToolInputSchema(tool *mcp.Tool) (*jsonschema.Schema, error) {
+	schema, err := normalizeMCPToolSchema(tool.InputSchema)
+	if err != nil || schema == nil {
+		return nil, errors.New("inputSchema must be a JSON object")
+	}
+	if schemaType, _ := schema["type"].(string); schemaType != "object" {
+		return nil, fmt.Errorf("inputSchema type must be \"object\", got %v", schema["type"])
+	}
+
+	compiler := jsonschema.NewCompiler()
+	schemaURL := "mcp://tools/" + tool.Name + "/input.json"
+	if err := compiler.AddResource(schemaURL, schema); err != nil {
+		return nil, fmt.Errorf("invalid inputSchema: %w", err)
+	}
+	compiled, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inputSchema: %w", err)
+	}
+	return compiled, nil
+}
+
+// generateSampleInput builds an input object containing the required properties of a schema.
+// Values come from default, examples, const or enum when present, otherwise from the type.
+func generateSampleInput(schema map[string]any) map[string]any {
+	input := map[string]any{}
+	properties, _ := schema["properties"].(map[string]any)
+	required, _ := schema["required"].([]any)
+	for _, name := range required {
+		propertyName, ok := name.(string)
+		if !ok {
+			continue
+		}
+		propertySchema, _ := properties[propertyName].(map[string]any)
+		input[propertyName] = generateSampleValue(propertySchema)
+	}
+	return input
+}
+
+func generateSampleValue(schema map[string]any) any {
+	if schema == nil {
+		return "sample"
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if examples, ok := schema["examples"].([]any); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if value, ok := schema["const"]; ok {
+		return value
+	}
+	if enum, ok := schema["enum"].([]any); ok && len(enum) > 0 {
+		return enum[0]
+	}
+
+	schemaType := schema["type"]
+	if types, ok := schemaType.([]any); ok && len(types) > 0 {
+		schemaType = types[0]
+	}
+	switch schemaType {
+	case "integer", "number":
+		if minimum, ok := schema["minimum"].(float64); ok {
+			return minimum
+		}
+		return 1
+	case "boolean":
+		return false
+	case "array":
+		return []any{}
+	case "object":
+		return generateSampleInput(schema)
+	case "null":
+		return nil
+	default:
+		return "sample"
+	}
+}
+
+// renderMCPCheckReport displays the conformance results of an MCP server
+func renderMCPCheckReport(report *MCPServerCheckReport) {
+	rows := make([][]string, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		duration := "-"
+		if check.DurationMs > 0 {
+			duration = fmt.Sprintf("%dms", check.DurationMs)
+		}
+		rows = append(rows, []string{check.Check, check.Status, duration, check.Message})
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		// Keep failures at the top so they are visible in long reports
+		return rows[i][1] == MCPCheckFail && rows[j][1] != MCPCheckFail
+	})
+
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   fmt.Sprintf("%s (startup %dms)", report.Server, report.StartupLatencyMs),
+		Headers: []string{"Check", "Status", "Time", "Details"},
+		Rows:    rows,
+	}))
+}
+
+// NewMCPCheckSubcommand creates the mcp check subcommand
+func NewMCPCheckSubcommand() *cobra.Command {
+	var callTools []string
+
+	cmd := &cobra.Command{
+		Use:   "check <workflow> [server]",
+		Short: "Run conformance checks against the MCP servers of a workflow",
+		Long: `Run conformance checks against the MCP servers of a workflow.
+
+The checks verify that each server:
+- Completes the initialize handshake within tools.startup-timeout (startup latency is reported)
+- Lists its tools, and every tool has a valid JSON Schema object as inputSchema
+- Answers calls within tools.timeout, for tools allowed with --call
+
+Tools are only called when they are listed with --call, since calling a tool can have
+side effects. Calls use sample inputs generated from the tool schema.
+
+The command exits with a non-zero status when a check fails, so it can run in CI.
+
+Examples:
+  gh aw mcp check weekly-research                           # Check all MCP servers
+  gh aw mcp check weekly-research tavily                    # Check a single server
+  gh aw mcp check weekly-research github --call get_me      # Also call the get_me tool
+  gh aw mcp check weekly-research --json                    # Output results as JSON`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var serverFilter string
+			if len(args) > 1 {
+				serverFilter = args[1]
+			}
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+
+			return CheckWorkflowMCP(args[0], serverFilter, callTools, jsonOutput, verbose)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return CompleteWorkflowNames(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&callTools, "call", nil, "Tools to call with sample inputs (comma-separated dry-run allowlist)")
+	addJSONFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,116 @@
+//go:build !integration
+
+package cli
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSampleInput(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"owner":  map[string]any{"type": "string"},
+			"number": map[string]any{"type": "integer", "minimum": float64(5)},
+			"state":  map[string]any{"type": "string", "enum": []any{"open", "closed"}},
+			"limit":  map[string]any{"type": "integer", "default": float64(30)},
+			"labels": map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+			"filter": map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"draft": map[string]any{"type": "boolean"}},
+				"required":   []any{"draft"},
+			},
+			"optional": map[string]any{"type": "string"},
+		},
+		"required": []any{"owner", "number", "state", "limit", "labels", "filter"},
+	}
+
+	assert.Equal(t, map[string]any{
+		"owner":  "sample",
+		"number": float64(5),
+		"state":  "open",
+		"limit":  float64(30),
+		"labels": []any{},
+		"filter": map[string]any{"draft": false},
+	}, generateSampleInput(schema))
+}
+
+func TestCompileMCPToolInputSchema(t *testing.T) {
+	tests := []struct {
+		name      string
+		schema    any
+		wantError string
+	}{
+		{name: "object schema", schema: map[string]any{"type": "object", "properties": map[string]any{"q": map[string]any{"type": "string"}}}},
+		{name: "missing schema", schema: nil, wantError: "inputSchema must be a JSON object"},
+		{name: "array schema", schema: map[string]any{"type": "array"}, wantError: `inputSchema type must be "object"`},
+		{name: "invalid schema", schema: map[string]any{"type": "object", "properties": map[string]any{"q": map[string]any{"type": 42}}}, wantError: "invalid inputSchema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileMCPToolInputSchema(&mcp.Tool{Name: "search", InputSchema: tt.schema})
+			if tt.wantError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
+
+type searchInput struct {
+	Query string `json:"query" jsonschema:"Search query"`
+}
+
+func TestRunMCPConformanceChecks(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "search", Description: "Search"}, func(ctx context.Context, req *mcp.CallToolRequest, in searchInput) (*mcp.CallToolResult, any, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "found " + in.Query}}}, nil, nil
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "slow", Description: "Slow"}, func(ctx context.Context, req *mcp.CallToolRequest, in searchInput) (*mcp.CallToolResult, any, error) {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	})
+	mcp.AddTool(server, &mcp.Tool{Name: "delete", Description: "Delete"}, func(ctx context.Context, req *mcp.CallToolRequest, in searchInput) (*mcp.CallToolResult, any, error) {
+		t.Error("delete should not be called without being allowlisted")
+		return nil, nil, nil
+	})
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+	require.NoError(t, err)
+	defer serverSession.Close()
+
+	report := &MCPServerCheckReport{Server: "test", Type: "stdio"}
+	runMCPConformanceChecks(context.Background(), newMCPInspectorClient(), clientTransport, report, MCPCheckOptions{
+		CallTools:      []string{"search", "slow", "missing"},
+		StartupTimeout: 10 * time.Second,
+		ToolTimeout:    200 * time.Millisecond,
+	})
+
+	statuses := make(map[string]string)
+	for _, check := range report.Checks {
+		statuses[check.Check] = check.Status
+	}
+	assert.Equal(t, map[string]string{
+		"initialize":    MCPCheckPass,
+		"tools/list":    MCPCheckPass,
+		"schema delete": MCPCheckPass,
+		"schema search": MCPCheckPass,
+		"schema slow":   MCPCheckPass,
+		"call search":   MCPCheckPass,
+		"call slow":     MCPCheckFail,
+		"call missing":  MCPCheckFail,
+		"tools/call":    MCPCheckSkip,
+	}, statuses)
+	assert.NotEmpty(t, report.ProtocolVersion)
+	assert.True(t, report.Failed())
+}
@@ -126,12 +126,28 @@ func connectToMCPServer(config parser.MCPServerConfig, verbose bool) (*parser.MC
 	}
 }
 
-// connectStdioMCPServer connects to a stdio-based MCP server using the Go SDK
-func connectStdioMCPServer(ctx context.Context, config parser.MCPServerConfig, verbose bool) (*parser.MCPServerInfo, error) {
-	if verbose {
-		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Starting stdio MCP server: %s %s", config.Command, strings.Join(config.Args, " "))))
+// newMCPInspectorClient creates the MCP client used to inspect and check MCP servers
+func newMCPInspectorClient() *mcp.Client {
+	return mcp.NewClient(&mcp.Implementation{Name: "gh-aw-inspector", Version: "1.0.0"}, &mcp.ClientOptions{
+		Logger: logger.NewSlogLoggerWithHandler(mcpInspectServerLog),
+	})
+}
+
+// newMCPTransport creates the client transport for an MCP server configuration
+func newMCPTransport(config parser.MCPServerConfig) (mcp.Transport, error) {
+	switch config.Type {
+	case "stdio", "docker":
+		// Docker MCP servers are treated as stdio servers that run via docker command
+		return newStdioMCPTransport(config)
+	case "http":
+		return newHTTPMCPTransport(config), nil
+	default:
+		return nil, fmt.Errorf("unsupported MCP server type: %s", config.Type)
 	}
+}
 
+// newStdioMCPTransport creates a command transport that starts a stdio MCP server
+func newStdioMCPTransport(config parser.MCPServerConfig) (*mcp.CommandTransport, error) {
 	// Validate the command exists
 	if config.Command != "" {
 		if _, err := exec.LookPath(config.Command); err != nil {
@@ -158,11 +174,47 @@ func connectStdioMCPServer(ctx context.Context, config parser.MCPServerConfig, v
 		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", key, resolvedValue))
 	}
 
+	return &mcp.CommandTransport{Command: cmd}, nil
+}
+
+// newHTTPMCPTransport creates a streamable HTTP transport for an HTTP MCP server
+func newHTTPMCPTransport(config parser.MCPServerConfig) *mcp.StreamableClientTransport {
+	transport := &mcp.StreamableClientTransport{
+		Endpoint: config.URL,
+	}
+
+	// Add custom headers if provided
+	if len(config.Headers) > 0 {
+		// Create a custom HTTP client with header injection
+		baseTransport := http.DefaultTransport
+		if baseTransport == nil {
+			baseTransport = &http.Transport{}
+		}
+
+		transport.HTTPClient = &http.Client{
+			Transport: &headerRoundTripper{
+				base:    baseTransport,
+				headers: config.Headers,
+			},
+		}
+	}
+
+	return transport
+}
+
+// connectStdioMCPServer connects to a stdio-based MCP server using the Go SDK
+func connectStdioMCPServer(ctx context.Context, config parser.MCPServerConfig, verbose bool) (*parser.MCPServerInfo, error) {
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Starting stdio MCP server: %s %s", config.Command, strings.Join(config.Args, " "))))
+	}
+
+	transport, err := newStdioMCPTransport(config)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create MCP client and connect
-	client := mcp.NewClient(&mcp.Implementation{Name: "gh-aw-inspector", Version: "1.0.0"}, &mcp.ClientOptions{
-		Logger: logger.NewSlogLoggerWithHandler(mcpInspectServerLog),
-	})
-	transport := &mcp.CommandTransport{Command: cmd}
+	client := newMCPInspectorClient()
 
 	// Create a timeout context for connection
 	connectCtx, cancel := context.WithTimeout(ctx, MCPConnectTimeout)
@@ -249,30 +301,8 @@ func connectHTTPMCPServer(ctx context.Context, config parser.MCPServerConfig, ve
 	}
 
 	// Create MCP client with logger for better debugging
-	client := mcp.NewClient(&mcp.Implementation{Name: "gh-aw-inspector", Version: "1.0.0"}, &mcp.ClientOptions{
-		Logger: logger.NewSlogLoggerWithHandler(mcpInspectServerLog),
-	})
-
-	// Create streamable client transport for HTTP
-	transport := &mcp.StreamableClientTransport{
-		Endpoint: config.URL,
-	}
-
-	// Add custom headers if provided
-	if len(config.Headers) > 0 {
-		// Create a custom HTTP client with header injection
-		baseTransport := http.DefaultTransport
-		if baseTransport == nil {
-			baseTransport = &http.Transport{}
-		}
-
-		transport.HTTPClient = &http.Client{
-			Transport: &headerRoundTripper{
-				base:    baseTransport,
-				headers: config.Headers,
-			},
-		}
-	}
+	client := newMCPInspectorClient()
+	transport := newHTTPMCPTransport(config)
 
 	// Create a timeout context for connection
 	connectCtx, cancel := context.WithTimeout(ctx, MCPConnectTimeout)
@@ -65,33 +65,29 @@ func (c *MCPRegistryClient) createRegistryRequest(method, url string) (*http.Req
 	return req, nil
 }
 
-// SearchServers searches for MCP servers in the registry by fetching all servers and filtering locally
-func (c *MCPRegistryClient) SearchServers(query string) ([]MCPRegistryServerForProcessing, error) {
-	mcpRegistryLog.Printf("Searching MCP servers: query=%q", query)
+// fetchServerList fetches the server list from the registry. file:// registries are local
+// mirrors written by 'mcp registry sync' and are verified against their pinned digests.
+func (c *MCPRegistryClient) fetchServerList() (*ServerListResponse, error) {
+	if mirrorDir, ok := localRegistryDir(c.registryURL); ok {
+		return readMCPRegistryMirror(mirrorDir)
+	}
 
 	// Always use servers endpoint for listing all servers
-	searchURL := fmt.Sprintf("%s/servers", c.registryURL)
+	serversURL := fmt.Sprintf("%s/servers", c.registryURL)
 
 	// Create HTTP request with proper headers
-	req, err := c.createRegistryRequest("GET", searchURL)
+	req, err := c.createRegistryRequest("GET", serversURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create registry request: %w", err)
 	}
 
-	// Make HTTP request with spinner
-	spinnerMessage := fmt.Sprintf("Fetching servers from %s...", searchURL)
-	spinner := console.NewSpinner(spinnerMessage)
-	spinner.Start()
 	resp, err := c.httpClient.Do(req)
-
 	if err != nil {
-		spinner.Stop()
-		return nil, fmt.Errorf("failed to search MCP registry: %w", err)
+		return nil, fmt.Errorf("failed to query MCP registry: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		spinner.Stop()
 		body, _ := io.ReadAll(resp.Body)
 		// Provide more helpful error messages for common HTTP status codes
 		switch resp.StatusCode {
@@ -111,15 +107,28 @@ func (c *MCPRegistryClient) SearchServers(query string) ([]MCPRegistryServerForP
 	// Parse response
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		spinner.Stop()
 		return nil, fmt.Errorf("failed to read registry response: %w", err)
 	}
 
 	var response ServerListResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		spinner.Stop()
 		return nil, fmt.Errorf("failed to parse registry response: %w", err)
 	}
+	return &response, nil
+}
+
+// SearchServers searches for MCP servers in the registry by fetching all servers and filtering locally
+func (c *MCPRegistryClient) SearchServers(query string) ([]MCPRegistryServerForProcessing, error) {
+	mcpRegistryLog.Printf("Searching MCP servers: query=%q", query)
+
+	// Make registry request with spinner
+	spinner := console.NewSpinner(fmt.Sprintf("Fetching servers from %s...", c.registryURL))
+	spinner.Start()
+	response, err := c.fetchServerList()
+	if err != nil {
+		spinner.Stop()
+		return nil, err
+	}
 
 	// Stop spinner with success message
 	spinner.StopWithMessage(fmt.Sprintf("✓ Fetched %d servers from registry", len(response.Servers)))
@@ -263,55 +272,13 @@ func (c *MCPRegistryClient) SearchServers(query string) ([]MCPRegistryServerForP
 func (c *MCPRegistryClient) GetServer(serverName string) (*MCPRegistryServerForProcessing, error) {
 	mcpRegistryLog.Printf("Getting MCP server: name=%s", serverName)
 
-	// Use the servers endpoint and filter locally, just like SearchServers
-	serversURL := fmt.Sprintf("%s/servers", c.registryURL)
-
-	// Create HTTP request with proper headers
-	req, err := c.createRegistryRequest("GET", serversURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create registry request: %w", err)
-	}
-
-	// Make HTTP request with spinner
+	// Fetch the server list and filter locally, just like SearchServers
 	spinner := console.NewSpinner(fmt.Sprintf("Fetching MCP server '%s'...", serverName))
 	spinner.Start()
-	resp, err := c.httpClient.Do(req)
-
+	response, err := c.fetchServerList()
 	if err != nil {
 		spinner.Stop()
-		return nil, fmt.Errorf("failed to get MCP server: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		spinner.Stop()
-		body, _ := io.ReadAll(resp.Body)
-		// Provide more helpful error messages for common HTTP status codes
-		switch resp.StatusCode {
-		case http.StatusForbidden:
-			return nil, fmt.Errorf("MCP registry access forbidden (403): %s\nThis may be due to network or firewall restrictions", string(body))
-		case http.StatusUnauthorized:
-			return nil, fmt.Errorf("MCP registry access unauthorized (401): %s\nAuthentication may be required", string(body))
-		case http.StatusNotFound:
-			return nil, fmt.Errorf("MCP registry endpoint not found (404): %s\nPlease verify the registry URL is correct", string(body))
-		case http.StatusTooManyRequests:
-			return nil, fmt.Errorf("MCP registry rate limit exceeded (429): %s\nPlease try again later", string(body))
-		default:
-			return nil, fmt.Errorf("MCP registry returned status %d: %s", resp.StatusCode, string(body))
-		}
-	}
-
-	// Parse response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		spinner.Stop()
-		return nil, fmt.Errorf("failed to read registry response: %w", err)
-	}
-
-	var response ServerListResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		spinner.Stop()
-		return nil, fmt.Errorf("failed to parse server response: %w", err)
+		return nil, err
 	}
 
 	// Stop spinner with success message
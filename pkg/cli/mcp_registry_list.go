@@ -11,7 +11,7 @@ import (
 var mcpRegistryListLog = logger.New("cli:mcp_registry_list")
 
 // listAvailableServers shows a list of available MCP servers from the registry
-func listAvailableServers(registryURL string, verbose bool) error {
+func listAvailableServers(registryURL string, policyPath string, verbose bool) error {
 	mcpRegistryListLog.Printf("Listing available MCP servers: registry_url=%s", registryURL)
 	// Create registry client
 	registryClient := NewMCPRegistryClient(registryURL)
@@ -29,6 +29,16 @@ func listAvailableServers(registryURL string, verbose bool) error {
 
 	mcpRegistryListLog.Printf("Retrieved %d servers from registry", len(servers))
 
+	// Only list servers approved by the organization policy
+	policy, err := loadPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	servers, rejected := filterApprovedMCPServers(servers, policy, "")
+	if len(rejected) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Hiding %d server(s) not approved by the organization policy %s", len(rejected), policy.Source)))
+	}
+
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Retrieved %d servers from registry", len(servers))))
 		if len(servers) > 0 {
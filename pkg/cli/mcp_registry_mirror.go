@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpRegistryMirrorLog = logger.New("cli:mcp_registry_mirror")
+
+// mcpRegistryMirrorFile is the file holding the server list of a local registry mirror
+const mcpRegistryMirrorFile = "servers.json"
+
+// mcpRegistryLockPath is the committed lock file pinning the digest of every mirrored entry.
+// It is kept in the repository, apart from the mirror it protects, so that modifying the
+// mirror cannot also update its pins.
+var mcpRegistryLockPath = filepath.Join(".github", "aw", "mcp-registry-lock.json")
+
+// mcpRegistryLockEntry pins a mirrored server entry
+type mcpRegistryLockEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Digest  string `json:"digest"`
+	Source  string `json:"source"` // Registry the entry was mirrored from
+}
+
+// mcpRegistryLockFile represents the structure of mcp-registry-lock.json
+type mcpRegistryLockFile struct {
+	Entries map[string]mcpRegistryLockEntry `json:"entries"`
+}
+
+// localRegistryDir returns the mirror directory of a file:// registry URL
+func localRegistryDir(registryURL string) (string, bool) {
+	dir, ok := strings.CutPrefix(registryURL, "file://")
+	if !ok {
+		return "", false
+	}
+	return filepath.FromSlash(dir), true
+}
+
+// computeMCPRegistryEntryDigest returns the sha256 digest of a server entry. Registry
+// metadata such as the status is excluded so that deprecating a server does not change it.
+func computeMCPRegistryEntryDigest(server ServerDetail) (string, error) {
+	data, err := json.Marshal(server)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode server %s: %w", server.Name, err)
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// mcpRegistryEntryKey identifies a server entry by name and version
+func mcpRegistryEntryKey(server ServerDetail) string {
+	return server.Name + "@" + server.Version
+}
+
+// readMCPRegistryLock reads the mirror lock file. A missing lock file yields no pins.
+func readMCPRegistryLock() (*mcpRegistryLockFile, error) {
+	lock := &mcpRegistryLockFile{Entries: make(map[string]mcpRegistryLockEntry)}
+	data, err := os.ReadFile(mcpRegistryLockPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return lock, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", mcpRegistryLockPath, err)
+	}
+	if err := json.Unmarshal(data, lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", mcpRegistryLockPath, err)
+	}
+	if lock.Entries == nil {
+		lock.Entries = make(map[string]mcpRegistryLockEntry)
+	}
+	return lock, nil
+}
+
+// writeMCPRegistryLock writes the mirror lock file with entries sorted by key
+func writeMCPRegistryLock(lock *mcpRegistryLockFile) error {
+	data, err := json.MarshalIndent(lock, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", mcpRegistryLockPath, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(mcpRegistryLockPath), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(mcpRegistryLockPath), err)
+	}
+	if err := os.WriteFile(mcpRegistryLockPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", mcpRegistryLockPath, err)
+	}
+	mcpRegistryMirrorLog.Printf("Wrote %d pins to %s", len(lock.Entries), mcpRegistryLockPath)
+	return nil
+}
+
+// mirroredRegistrySource returns the registry a mirrored server was synced from, or an
+// empty string when the lock file does not record it
+func mirroredRegistrySource(serverName string) string {
+	lock, err := readMCPRegistryLock()
+	if err != nil {
+		return ""
+	}
+	for _, entry := range lock.Entries {
+		if entry.Name == serverName {
+			return entry.Source
+		}
+	}
+	return ""
+}
+
+// readMCPRegistryMirror reads a local registry mirror and verifies every entry against
+// the digest pinned in the lock file, so that a hand-edited or corrupted mirror is rejected
+func readMCPRegistryMirror(dir string) (*ServerListResponse, error) {
+	mirrorPath := filepath.Join(dir, mcpRegistryMirrorFile)
+	mcpRegistryMirrorLog.Printf("Reading MCP registry mirror: %s", mirrorPath)
+
+	data, err := os.ReadFile(mirrorPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("MCP registry mirror not found: %s\nRun '%s mcp registry sync %s' to create it", mirrorPath, constants.CLIExtensionPrefix, dir)
+		}
+		return nil, fmt.Errorf("failed to read MCP registry mirror: %w", err)
+	}
+
+	var response ServerListResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse MCP registry mirror %s: %w", mirrorPath, err)
+	}
+
+	lock, err := readMCPRegistryLock()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range response.Servers {
+		key := mcpRegistryEntryKey(entry.Server)
+		pin, ok := lock.Entries[key]
+		if !ok {
+			return nil, fmt.Errorf("MCP registry mirror entry '%s' is not pinned in %s\nRun '%s mcp registry sync %s' from the repository root and commit the lock file", key, mcpRegistryLockPath, constants.CLIExtensionPrefix, dir)
+		}
+		digest, err := computeMCPRegistryEntryDigest(entry.Server)
+		if err != nil {
+			return nil, err
+		}
+		if digest != pin.Digest {
+			return nil, fmt.Errorf("MCP registry mirror entry '%s' does not match its pinned digest (pinned %s, got %s)\nThe mirror was modified after it was synced; re-run '%s mcp registry sync %s' to repair it", key, pin.Digest, digest, constants.CLIExtensionPrefix, dir)
+		}
+	}
+
+	mcpRegistryMirrorLog.Printf("Verified %d mirror entries against %s", len(response.Servers), mcpRegistryLockPath)
+	return &response, nil
+}
+
+// writeMCPRegistryMirror writes the mirror entries sorted by name and version. The file is
+// written to a temporary file first so that readers never see a partially written mirror.
+func writeMCPRegistryMirror(dir string, entries []ServerResponse) error {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Server.Name != entries[j].Server.Name {
+			return entries[i].Server.Name < entries[j].Server.Name
+		}
+		return entries[i].Server.Version < entries[j].Server.Version
+	})
+
+	response := ServerListResponse{
+		Servers:  entries,
+		Metadata: &Metadata{Count: len(entries)},
+	}
+	data, err := json.MarshalIndent(response, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode MCP registry mirror: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create mirror directory: %w", err)
+	}
+	mirrorPath := filepath.Join(dir, mcpRegistryMirrorFile)
+	tmpPath := mirrorPath + ".tmp"
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write MCP registry mirror: %w", err)
+	}
+	if err := os.Rename(tmpPath, mirrorPath); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write MCP registry mirror: %w", err)
+	}
+
+	mcpRegistryMirrorLog.Printf("Wrote %d entries to %s", len(entries), mirrorPath)
+	return nil
+}
@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var mcpRegistrySyncLog = logger.New("cli:mcp_registry_sync")
+
+// MCPRegistrySyncOptions configures a registry mirror sync
+type MCPRegistrySyncOptions struct {
+	Dir         string   // Mirror directory
+	RegistryURL string   // Source registry (defaults to the public registry)
+	Servers     []string // Server name patterns to mirror; empty mirrors every approved server
+	Policy      string   // Organization policy whose mcp-servers rules restrict the mirror
+	Update      bool     // Accept entries whose content changed upstream since they were pinned
+	JSONOutput  bool
+	Verbose     bool
+}
+
+// MCPRegistrySyncResult summarizes the changes made to a registry mirror
+type MCPRegistrySyncResult struct {
+	Source    string   `json:"source"`
+	Mirror    string   `json:"mirror"`
+	Servers   int      `json:"servers"`
+	Added     []string `json:"added,omitempty"`
+	Updated   []string `json:"updated,omitempty"`
+	Removed   []string `json:"removed,omitempty"`
+	Changed   []string `json:"changed,omitempty"` // Pinned entries whose content changed upstream and were not updated
+	Unchanged int      `json:"unchanged"`
+}
+
+// NewMCPRegistrySubcommand creates the mcp registry subcommand
+func NewMCPRegistrySubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "registry",
+		Short: "Manage local mirrors of MCP registries",
+		Long: `Manage local mirrors of MCP registries.
+
+A mirror is a directory holding a verified copy of (a curated subset of) an MCP registry.
+Use it with --registry file://<dir> on 'mcp add' when the registry cannot be reached,
+for example on enterprise runners or behind a proxy.
+
+Available subcommands:
+  • sync - Mirror a registry into a local directory
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` mcp registry sync ./mcp-mirror                                   # Mirror all approved servers
+  ` + string(constants.CLIExtensionPrefix) + ` mcp add my-workflow notion --registry file://$PWD/mcp-mirror     # Add a server from the mirror`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newMCPRegistrySyncSubcommand())
+
+	return cmd
+}
+
+// newMCPRegistrySyncSubcommand creates the mcp registry sync subcommand
+func newMCPRegistrySyncSubcommand() *cobra.Command {
+	opts := MCPRegistrySyncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync <dir>",
+		Short: "Mirror an MCP registry into a local directory",
+		Long: `Mirror an MCP registry into a local directory.
+
+Active servers are written to <dir>/servers.json. Each entry is pinned to the sha256
+digest of its content in .github/aw/mcp-registry-lock.json, which is kept apart from the
+mirror and should be committed. Run the command from the repository root. Reading the
+mirror fails when an entry is not pinned or no longer matches its digest, and re-syncing
+refuses entries whose content changed upstream without a new version unless --update is
+passed.
+
+The mirror only contains servers approved by the mcp-servers rules of the organization
+policy (--policy, or ` + workflow.DefaultPolicyPath + ` when present). Use --server to
+mirror a curated subset; '*' in a pattern matches any sequence of characters.
+
+Servers that are no longer selected are removed from the mirror.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` mcp registry sync ./mcp-mirror                                  # Mirror the public registry
+  ` + string(constants.CLIExtensionPrefix) + ` mcp registry sync ./mcp-mirror --server 'io.github.github/*'    # Mirror a curated subset
+  ` + string(constants.CLIExtensionPrefix) + ` mcp registry sync ./mcp-mirror --registry https://registry.example.com/v0.1
+  ` + string(constants.CLIExtensionPrefix) + ` mcp registry sync ./mcp-mirror --update                         # Accept changed upstream entries`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.Dir = args[0]
+			opts.Policy, _ = cmd.Flags().GetString("policy")
+			opts.JSONOutput, _ = cmd.Flags().GetBool("json")
+			opts.Verbose, _ = cmd.Flags().GetBool("verbose")
+			return SyncMCPRegistry(opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.RegistryURL, "registry", "", "MCP registry URL to mirror (default: "+string(constants.DefaultMCPRegistryURL)+")")
+	cmd.Flags().StringSliceVar(&opts.Servers, "server", nil, "Server name patterns to mirror (comma-separated or repeated)")
+	cmd.Flags().BoolVar(&opts.Update, "update", false, "Accept pinned entries whose content changed upstream")
+	addPolicyFlag(cmd)
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+// SyncMCPRegistry mirrors the registry into opts.Dir
+func SyncMCPRegistry(opts MCPRegistrySyncOptions) error {
+	mcpRegistrySyncLog.Printf("Syncing MCP registry mirror: dir=%s, registry=%s, servers=%v", opts.Dir, opts.RegistryURL, opts.Servers)
+
+	policy, err := loadPolicy(opts.Policy)
+	if err != nil {
+		return err
+	}
+
+	client := NewMCPRegistryClient(opts.RegistryURL)
+	spinner := console.NewSpinner(fmt.Sprintf("Fetching servers from %s...", client.registryURL))
+	spinner.Start()
+	upstream, err := client.fetchServerList()
+	if err != nil {
+		spinner.Stop()
+		return fmt.Errorf("failed to fetch MCP registry: %w", err)
+	}
+	spinner.StopWithMessage(fmt.Sprintf("✓ Fetched %d servers from registry", len(upstream.Servers)))
+
+	// Entries already in the lock file are pinned
+	lock, err := readMCPRegistryLock()
+	if err != nil {
+		return err
+	}
+
+	result := MCPRegistrySyncResult{Source: client.registryURL, Mirror: opts.Dir}
+	selection := &workflow.PolicyMCPServersRule{Allowed: opts.Servers}
+	pins := make(map[string]mcpRegistryLockEntry)
+	var entries []ServerResponse
+
+	for _, entry := range upstream.Servers {
+		name := entry.Server.Name
+		if !isActiveRegistryEntry(entry) {
+			continue
+		}
+		if len(opts.Servers) > 0 && !selection.Allows(name) {
+			continue
+		}
+		if !policy.AllowsMCPServer("", name) {
+			mcpRegistrySyncLog.Printf("Skipping server not approved by policy: %s", name)
+			if opts.Verbose {
+				fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Skipping %s: not approved by policy", name)))
+			}
+			continue
+		}
+
+		key := mcpRegistryEntryKey(entry.Server)
+		if _, seen := pins[key]; seen {
+			continue
+		}
+
+		digest, err := computeMCPRegistryEntryDigest(entry.Server)
+		if err != nil {
+			return err
+		}
+
+		previous, pinned := lock.Entries[key]
+		switch {
+		case pinned && previous.Digest == digest:
+			result.Unchanged++
+		case pinned && !opts.Update:
+			result.Changed = append(result.Changed, key)
+		case pinned:
+			result.Updated = append(result.Updated, key)
+		default:
+			result.Added = append(result.Added, key)
+		}
+
+		pins[key] = mcpRegistryLockEntry{Name: name, Version: entry.Server.Version, Digest: digest, Source: client.registryURL}
+		entries = append(entries, entry)
+	}
+
+	for key := range lock.Entries {
+		if _, seen := pins[key]; !seen {
+			result.Removed = append(result.Removed, key)
+		}
+	}
+	sort.Strings(result.Removed)
+	result.Servers = len(entries)
+
+	// Pinned entries must not change silently: leave the mirror and the lock file untouched
+	if len(result.Changed) > 0 {
+		return fmt.Errorf("pinned MCP registry entries changed upstream without a new version: %s\nReview the changes and re-run with --update to accept them",
+			strings.Join(result.Changed, ", "))
+	}
+
+	if err := writeMCPRegistryMirror(opts.Dir, entries); err != nil {
+		return err
+	}
+	if err := writeMCPRegistryLock(&mcpRegistryLockFile{Entries: pins}); err != nil {
+		return err
+	}
+
+	if opts.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	for _, key := range result.Added {
+		fmt.Fprintln(os.Stderr, console.FormatListItem("added   "+key))
+	}
+	for _, key := range result.Updated {
+		fmt.Fprintln(os.Stderr, console.FormatListItem("updated "+key))
+	}
+	for _, key := range result.Removed {
+		fmt.Fprintln(os.Stderr, console.FormatListItem("removed "+key))
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Mirrored %d server(s) to %s (%d added, %d updated, %d removed, %d unchanged)",
+		result.Servers, filepath.Join(opts.Dir, mcpRegistryMirrorFile), len(result.Added), len(result.Updated), len(result.Removed), result.Unchanged)))
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Commit %s: reading the mirror verifies its entries against these pins", mcpRegistryLockPath)))
+	if absDir, err := filepath.Abs(opts.Dir); err == nil {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Use it with: %s mcp add <workflow> <server> --registry file://%s", constants.CLIExtensionPrefix, filepath.ToSlash(absDir))))
+	}
+	return nil
+}
+
+// isActiveRegistryEntry reports whether the registry marks the entry as active
+func isActiveRegistryEntry(entry ServerResponse) bool {
+	if meta, ok := entry.Meta["io.modelcontextprotocol.registry/official"].(map[string]any); ok {
+		if status, ok := meta["status"].(string); ok {
+			return status == StatusActive
+		}
+	}
+	return true
+}
@@ -0,0 +1,186 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestMCPRegistry serves the given entries from /servers
+func newTestMCPRegistry(t *testing.T, entries *[]ServerResponse) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/servers" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ServerListResponse{Servers: *entries})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func testRegistryEntry(name string, description string, status string) ServerResponse {
+	return ServerResponse{
+		Server: ServerDetail{
+			Name:        name,
+			Description: description,
+			Version:     "1.0.0",
+			Packages: []MCPPackage{{
+				RegistryType: "npm",
+				Identifier:   strings.ReplaceAll(name, "/", "-"),
+				Transport:    &Transport{Type: "stdio"},
+			}},
+		},
+		Meta: map[string]any{
+			"io.modelcontextprotocol.registry/official": map[string]any{"status": status},
+		},
+	}
+}
+
+func TestSyncMCPRegistry(t *testing.T) {
+	entries := []ServerResponse{
+		testRegistryEntry("io.github.github/github-mcp-server", "GitHub", StatusActive),
+		testRegistryEntry("io.github.makenotion/notion-mcp-server", "Notion", StatusActive),
+		testRegistryEntry("com.example/retired", "Retired", StatusInactive),
+	}
+	registry := newTestMCPRegistry(t, &entries)
+	repoDir := testutil.TempDir(t, "mcp-mirror")
+	t.Chdir(repoDir)
+	mirrorDir := filepath.Join(repoDir, "mirror")
+
+	require.NoError(t, SyncMCPRegistry(MCPRegistrySyncOptions{Dir: mirrorDir, RegistryURL: registry.URL}))
+
+	mirror, err := readMCPRegistryMirror(mirrorDir)
+	require.NoError(t, err)
+	require.Len(t, mirror.Servers, 2, "inactive servers should not be mirrored")
+	assert.Equal(t, "io.github.github/github-mcp-server", mirror.Servers[0].Server.Name)
+
+	// Pins are kept in the committed lock file, not in the mirror they protect
+	lock, err := readMCPRegistryLock()
+	require.NoError(t, err)
+	require.Len(t, lock.Entries, 2)
+	assert.True(t, strings.HasPrefix(lock.Entries["io.github.github/github-mcp-server@1.0.0"].Digest, "sha256:"))
+	mirrorContent, err := os.ReadFile(filepath.Join(mirrorDir, mcpRegistryMirrorFile))
+	require.NoError(t, err)
+	assert.NotContains(t, string(mirrorContent), "sha256:")
+
+	// The mirror is usable as a file:// registry
+	client := NewMCPRegistryClient("file://" + filepath.ToSlash(mirrorDir))
+	servers, err := client.SearchServers("notion")
+	require.NoError(t, err)
+	require.Len(t, servers, 1)
+	assert.Equal(t, "io.github.makenotion-notion-mcp-server", servers[0].Command)
+	server, err := client.GetServer("io.github.github/github-mcp-server")
+	require.NoError(t, err)
+	assert.Equal(t, "stdio", server.Transport)
+	assert.Equal(t, registry.URL, mirroredRegistrySource(server.Name))
+
+	t.Run("pinned entry changed upstream", func(t *testing.T) {
+		before, err := os.ReadFile(filepath.Join(mirrorDir, mcpRegistryMirrorFile))
+		require.NoError(t, err)
+		lockBefore, err := os.ReadFile(mcpRegistryLockPath)
+		require.NoError(t, err)
+
+		entries[1].Server.Packages[0].Identifier = "notion-mcp-fork"
+		err = SyncMCPRegistry(MCPRegistrySyncOptions{Dir: mirrorDir, RegistryURL: registry.URL})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "io.github.makenotion/notion-mcp-server@1.0.0")
+		assert.Contains(t, err.Error(), "--update")
+
+		after, err := os.ReadFile(filepath.Join(mirrorDir, mcpRegistryMirrorFile))
+		require.NoError(t, err)
+		assert.Equal(t, string(before), string(after), "mirror should be left untouched")
+		lockAfter, err := os.ReadFile(mcpRegistryLockPath)
+		require.NoError(t, err)
+		assert.Equal(t, string(lockBefore), string(lockAfter), "lock file should be left untouched")
+
+		require.NoError(t, SyncMCPRegistry(MCPRegistrySyncOptions{Dir: mirrorDir, RegistryURL: registry.URL, Update: true}))
+		server, err := client.GetServer("io.github.makenotion/notion-mcp-server")
+		require.NoError(t, err)
+		assert.Equal(t, "notion-mcp-fork", server.Command)
+	})
+
+	t.Run("curated subset", func(t *testing.T) {
+		require.NoError(t, SyncMCPRegistry(MCPRegistrySyncOptions{Dir: mirrorDir, RegistryURL: registry.URL, Servers: []string{"io.github.github/*"}}))
+		mirror, err := readMCPRegistryMirror(mirrorDir)
+		require.NoError(t, err)
+		require.Len(t, mirror.Servers, 1, "servers outside the subset should be removed")
+		assert.Equal(t, "io.github.github/github-mcp-server", mirror.Servers[0].Server.Name)
+	})
+
+	t.Run("policy allowlist", func(t *testing.T) {
+		policyPath := filepath.Join(testutil.TempDir(t, "mcp-mirror-policy"), "policy.yml")
+		require.NoError(t, os.WriteFile(policyPath, []byte("rules:\n  - id: approved-mcp-servers\n    mcp-servers:\n      allowed: [io.github.makenotion/*]\n"), 0644))
+
+		require.NoError(t, SyncMCPRegistry(MCPRegistrySyncOptions{Dir: mirrorDir, RegistryURL: registry.URL, Policy: policyPath, Update: true}))
+		mirror, err := readMCPRegistryMirror(mirrorDir)
+		require.NoError(t, err)
+		require.Len(t, mirror.Servers, 1)
+		assert.Equal(t, "io.github.makenotion/notion-mcp-server", mirror.Servers[0].Server.Name)
+	})
+}
+
+func TestReadMCPRegistryMirrorRejectsModifiedEntries(t *testing.T) {
+	repoDir := testutil.TempDir(t, "mcp-mirror-tampered")
+	t.Chdir(repoDir)
+	mirrorDir := filepath.Join(repoDir, "mirror")
+	entry := testRegistryEntry("io.github.github/github-mcp-server", "GitHub", StatusActive)
+	digest, err := computeMCPRegistryEntryDigest(entry.Server)
+	require.NoError(t, err)
+	require.NoError(t, writeMCPRegistryMirror(mirrorDir, []ServerResponse{entry}))
+	require.NoError(t, writeMCPRegistryLock(&mcpRegistryLockFile{Entries: map[string]mcpRegistryLockEntry{
+		"io.github.github/github-mcp-server@1.0.0": {Name: entry.Server.Name, Version: "1.0.0", Digest: digest},
+	}}))
+
+	_, err = readMCPRegistryMirror(mirrorDir)
+	require.NoError(t, err)
+
+	entry.Server.Packages[0].Identifier = "malicious-package"
+	require.NoError(t, writeMCPRegistryMirror(mirrorDir, []ServerResponse{entry}))
+	_, err = readMCPRegistryMirror(mirrorDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match its pinned digest")
+
+	added := testRegistryEntry("com.example/unpinned", "Unpinned", StatusActive)
+	require.NoError(t, writeMCPRegistryMirror(mirrorDir, []ServerResponse{added}))
+	_, err = readMCPRegistryMirror(mirrorDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not pinned in "+mcpRegistryLockPath)
+
+	_, err = readMCPRegistryMirror(filepath.Join(mirrorDir, "missing"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "mcp registry sync")
+}
+
+func TestFilterApprovedMCPServers(t *testing.T) {
+	servers := []MCPRegistryServerForProcessing{
+		{Name: "io.github.github/github-mcp-server"},
+		{Name: "io.github.makenotion/notion-mcp-server"},
+	}
+
+	approved, rejected := filterApprovedMCPServers(servers, nil, "triage")
+	assert.Len(t, approved, 2, "no policy approves every server")
+	assert.Empty(t, rejected)
+
+	policy := &workflow.Policy{Rules: []workflow.PolicyRule{{
+		ID:         "approved-mcp-servers",
+		MCPServers: &workflow.PolicyMCPServersRule{Allowed: []string{"io.github.github/*"}},
+	}}}
+	approved, rejected = filterApprovedMCPServers(servers, policy, "triage")
+	require.Len(t, approved, 1)
+	assert.Equal(t, "io.github.github/github-mcp-server", approved[0].Name)
+	assert.Equal(t, []string{"io.github.makenotion/notion-mcp-server"}, rejected)
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var mcpRenderLog = logger.New("cli:mcp_render")
+
+// RenderWorkflowMCP prints the MCP configuration an engine receives for a workflow
+func RenderWorkflowMCP(workflowFile string, engineOverride string, serverFilter string, jsonOutput bool, verbose bool) error {
+	mcpRenderLog.Printf("Rendering workflow MCP config: workflow=%s, engine=%s, server=%s", workflowFile, engineOverride, serverFilter)
+
+	workflowPath, err := ResolveWorkflowPath(workflowFile)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(workflowPath) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		workflowPath = filepath.Join(cwd, workflowPath)
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose), workflow.WithEngineOverride(engineOverride))
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+
+	engine, err := workflow.GetGlobalEngineRegistry().GetEngine(workflowData.AI)
+	if err != nil {
+		return err
+	}
+	config, err := workflow.RenderEngineMCPConfig(engine, workflowData)
+	if err != nil {
+		return err
+	}
+
+	if serverFilter != "" {
+		spec, ok := config.Servers[serverFilter]
+		if !ok {
+			return fmt.Errorf("no MCP server named '%s' found for engine %s", serverFilter, config.Engine)
+		}
+		config.Servers = map[string]*workflow.MCPServerSpec{serverFilter: spec}
+	}
+
+	if jsonOutput || serverFilter != "" {
+		// The canonical model is only meaningful on its own; drop the raw files
+		config.Files = nil
+		jsonBytes, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	if len(config.Files) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No MCP servers found in workflow"))
+		return nil
+	}
+	for _, file := range config.Files {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%s (%s)", file.Target, file.Format)))
+		fmt.Print(file.Content)
+	}
+	if config.StartupTimeout > 0 || config.ToolTimeout > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Timeouts: startup %ds, tool %ds", config.StartupTimeout, config.ToolTimeout)))
+	}
+	return nil
+}
+
+// NewMCPRenderSubcommand creates the mcp render subcommand
+func NewMCPRenderSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "render <workflow> [server]",
+		Short: "Print the MCP configuration an engine receives for a workflow",
+		Long: `Print the MCP configuration an engine receives for a workflow.
+
+The configuration is rendered exactly as the compiler writes it into the lock file:
+the MCP gateway configuration, plus any engine-specific files (such as the Codex
+config.toml). Use --engine to render the workflow for another engine.
+
+With --json, or when a server is given, the configuration is printed in the canonical
+engine-independent form used by the cross-engine conformance tests: variable references
+are written as ${VAR} and the tool timeouts are included.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` mcp render weekly-research                   # Render for the workflow engine
+  ` + string(constants.CLIExtensionPrefix) + ` mcp render weekly-research --engine codex    # Render for Codex
+  ` + string(constants.CLIExtensionPrefix) + ` mcp render weekly-research tavily -e claude  # Canonical config of one server
+  ` + string(constants.CLIExtensionPrefix) + ` mcp render weekly-research --json            # Canonical config of all servers`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var serverFilter string
+			if len(args) > 1 {
+				serverFilter = args[1]
+			}
+			engineOverride, _ := cmd.Flags().GetString("engine")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			return RenderWorkflowMCP(args[0], engineOverride, serverFilter, jsonOutput, verbose)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return CompleteWorkflowNames(cmd, args, toComplete)
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+	}
+
+	addEngineFlag(cmd)
+	addJSONFlag(cmd)
+
+	return cmd
+}
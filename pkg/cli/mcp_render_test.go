@@ -0,0 +1,44 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderWorkflowMCP(t *testing.T) {
+	workflowPath := filepath.Join(testutil.TempDir(t, "mcp-render"), "render.md")
+	content := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: claude
+mcp-servers:
+  tavily:
+    url: https://mcp.tavily.com/mcp/
+    headers:
+      Authorization: "Bearer ${{ secrets.TAVILY_API_KEY }}"
+---
+
+# Render
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	for _, engine := range []string{"", "claude", "copilot", "codex"} {
+		assert.NoError(t, RenderWorkflowMCP(workflowPath, engine, "", false, false), "engine %q", engine)
+		assert.NoError(t, RenderWorkflowMCP(workflowPath, engine, "tavily", false, false), "engine %q", engine)
+	}
+
+	err := RenderWorkflowMCP(workflowPath, "codex", "notion", true, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no MCP server named 'notion' found for engine codex")
+
+	err = RenderWorkflowMCP(workflowPath, "unknown-engine", "", true, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,143 @@
+// This file provides command-line interface functionality for gh-aw.
+// This file (model_prices.go) contains the per-model price table used to
+// compute the cost of LLM requests recorded in the api-proxy usage log.
+//
+// Key responsibilities:
+//   - Providing list prices for common models
+//   - Loading repository overrides from .github/aw/model-prices.json
+//   - Matching versioned model names to price entries
+//   - Computing the cost of prompt, cached and completion tokens
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var modelPricesLog = logger.New("cli:model_prices")
+
+// DefaultModelPricesPath is the repository-relative path of the price table overrides
+// that are picked up automatically by the logs and audit commands
+const DefaultModelPricesPath = ".github/aw/model-prices.json"
+
+// ModelPrice is the price of a model in USD per million tokens
+type ModelPrice struct {
+	Input       float64 `json:"input"`                  // Uncached prompt tokens
+	CachedInput float64 `json:"cached_input,omitempty"` // Prompt tokens read from the cache (defaults to the input price)
+	Output      float64 `json:"output"`                 // Completion tokens
+}
+
+// ModelPriceTable maps model names (or name prefixes) to prices
+type ModelPriceTable map[string]ModelPrice
+
+// modelPricesFile is the format of the price table overrides file
+type modelPricesFile struct {
+	Models ModelPriceTable `json:"models"`
+}
+
+// defaultModelPrices are the list prices of common models in USD per million tokens.
+// Versioned model names (e.g. claude-sonnet-4-20250514) match the longest prefix.
+var defaultModelPrices = ModelPriceTable{
+	"claude-opus-4":     {Input: 15, CachedInput: 1.5, Output: 75},
+	"claude-opus-4-5":   {Input: 5, CachedInput: 0.5, Output: 25},
+	"claude-sonnet-4":   {Input: 3, CachedInput: 0.3, Output: 15},
+	"claude-haiku-4-5":  {Input: 1, CachedInput: 0.1, Output: 5},
+	"claude-3-5-haiku":  {Input: 0.8, CachedInput: 0.08, Output: 4},
+	"claude-3-7-sonnet": {Input: 3, CachedInput: 0.3, Output: 15},
+	"gpt-5":             {Input: 1.25, CachedInput: 0.125, Output: 10},
+	"gpt-5-mini":        {Input: 0.25, CachedInput: 0.025, Output: 2},
+	"gpt-5-nano":        {Input: 0.05, CachedInput: 0.005, Output: 0.4},
+	"gpt-4.1":           {Input: 2, CachedInput: 0.5, Output: 8},
+	"gpt-4.1-mini":      {Input: 0.4, CachedInput: 0.1, Output: 1.6},
+	"gpt-4o":            {Input: 2.5, CachedInput: 1.25, Output: 10},
+	"gpt-4o-mini":       {Input: 0.15, CachedInput: 0.075, Output: 0.6},
+	"o3":                {Input: 2, CachedInput: 0.5, Output: 8},
+	"o3-mini":           {Input: 1.1, CachedInput: 0.55, Output: 4.4},
+	"o4-mini":           {Input: 1.1, CachedInput: 0.275, Output: 4.4},
+}
+
+// loadRepositoryModelPrices loads the price table once per process, merging the
+// overrides of the current repository into the default prices
+var loadRepositoryModelPrices = sync.OnceValue(func() ModelPriceTable {
+	baseDir, err := findGitRoot()
+	if err != nil {
+		baseDir = "."
+	}
+	prices, err := loadModelPriceTable(filepath.Join(baseDir, DefaultModelPricesPath))
+	if err != nil {
+		modelPricesLog.Printf("Ignoring price table overrides: %v", err)
+		return defaultModelPrices
+	}
+	return prices
+})
+
+// loadModelPriceTable returns the default prices merged with the overrides of the file
+// at path. A missing file is not an error.
+func loadModelPriceTable(path string) (ModelPriceTable, error) {
+	prices := make(ModelPriceTable, len(defaultModelPrices))
+	for model, price := range defaultModelPrices {
+		prices[model] = price
+	}
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		modelPricesLog.Printf("No price table overrides at %s", path)
+		return prices, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read price table %s: %w", path, err)
+	}
+
+	var overrides modelPricesFile
+	if err := json.Unmarshal(content, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to parse price table %s: %w", path, err)
+	}
+	for model, price := range overrides.Models {
+		if price.Input < 0 || price.CachedInput < 0 || price.Output < 0 {
+			return nil, fmt.Errorf("invalid price for model '%s' in %s: prices must not be negative", model, path)
+		}
+		prices[strings.ToLower(model)] = price
+	}
+	modelPricesLog.Printf("Loaded %d price overrides from %s", len(overrides.Models), path)
+	return prices, nil
+}
+
+// lookup returns the price of a model, matching the exact name first and then the
+// longest name prefix. Provider prefixes such as "anthropic/" are ignored.
+func (t ModelPriceTable) lookup(model string) (ModelPrice, bool) {
+	model = strings.ToLower(strings.TrimSpace(model))
+	if _, name, found := strings.Cut(model, "/"); found {
+		model = name
+	}
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+
+	var best string
+	for name := range t {
+		if strings.HasPrefix(model, name+"-") && len(name) > len(best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return ModelPrice{}, false
+	}
+	return t[best], true
+}
+
+// cost returns the cost in USD of a request. promptTokens includes the cachedTokens.
+func (p ModelPrice) cost(promptTokens, cachedTokens, completionTokens int) float64 {
+	cachedPrice := p.CachedInput
+	if cachedPrice == 0 {
+		cachedPrice = p.Input
+	}
+	uncached := max(promptTokens-cachedTokens, 0)
+	return (float64(uncached)*p.Input + float64(cachedTokens)*cachedPrice + float64(completionTokens)*p.Output) / 1_000_000
+}
@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var policyCommandLog = logger.New("cli:policy_command")
+
+// PolicyViolationRow is a single policy violation for table output
+type PolicyViolationRow struct {
+	Workflow string `json:"workflow" console:"header:Workflow"`
+	RuleID   string `json:"rule_id" console:"header:Rule"`
+	Level    string `json:"level" console:"header:Level"`
+	Message  string `json:"message" console:"header:Message"`
+}
+
+// PolicyCheckResult is the JSON output of policy check
+type PolicyCheckResult struct {
+	Policy     string               `json:"policy"`
+	Workflows  int                  `json:"workflows"`
+	Errors     int                  `json:"errors"`
+	Warnings   int                  `json:"warnings"`
+	Violations []PolicyViolationRow `json:"violations"`
+}
+
+// NewPolicyCommand creates the policy command with subcommands
+func NewPolicyCommand() *cobra.Command {
+	policyCommandLog.Print("Creating policy command with subcommands")
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Evaluate organization policy rules against agentic workflows",
+		Long: `Evaluate organization policy rules against agentic workflows.
+
+A policy file expresses organization-wide rules such as forbidden write permissions,
+approved engines, network restrictions, safe-output requirements and timeout limits.
+Each rule has a stable id and an error or warning level.
+
+The policy is loaded from --policy (a local path or an owner/repo/path@ref workflowspec)
+or from ` + workflow.DefaultPolicyPath + ` when present. The same policy is applied
+automatically by 'compile'.
+
+Available subcommands:
+  • check - Check workflows against the policy
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` policy check                                  # Check all workflows
+  ` + string(constants.CLIExtensionPrefix) + ` policy check ci-doctor                        # Check a specific workflow
+  ` + string(constants.CLIExtensionPrefix) + ` policy check --policy org/policies/aw.yml@main  # Use a remote policy`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newPolicyCheckSubcommand())
+
+	return cmd
+}
+
+// newPolicyCheckSubcommand creates the policy check subcommand
+func newPolicyCheckSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check [workflow]...",
+		Short: "Check workflows against the organization policy",
+		Long: `Check workflows against the organization policy without compiling them.
+
+Exits with a non-zero status when any error-level rule is violated, so it can be
+used as a CI gate. Warning-level violations are reported but do not fail the check.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` policy check                         # Check all workflows
+  ` + string(constants.CLIExtensionPrefix) + ` policy check ci-doctor daily-plan    # Check specific workflows
+  ` + string(constants.CLIExtensionPrefix) + ` policy check --policy policy.yml     # Use a specific policy file
+  ` + string(constants.CLIExtensionPrefix) + ` policy check --json                  # Output results in JSON format`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			policyPath, _ := cmd.Flags().GetString("policy")
+			dir, _ := cmd.Flags().GetString("dir")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunPolicyCheck(args, policyPath, dir, jsonOutput, verbose)
+		},
+	}
+
+	addPolicyFlag(cmd)
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	addJSONFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// addPolicyFlag adds the --policy flag to a command.
+// This flag selects the organization policy file.
+func addPolicyFlag(cmd *cobra.Command) {
+	cmd.Flags().String("policy", "", "Organization policy file (local path or owner/repo/path@ref). Defaults to "+workflow.DefaultPolicyPath+" when present")
+}
+
+// loadPolicy loads the policy from policyPath, falling back to the default policy
+// location in the repository. Returns nil when no policy is configured.
+func loadPolicy(policyPath string) (*workflow.Policy, error) {
+	baseDir, err := findGitRoot()
+	if err != nil {
+		baseDir = "."
+	}
+
+	if policyPath == "" {
+		defaultPath := filepath.Join(baseDir, workflow.DefaultPolicyPath)
+		if _, statErr := os.Stat(defaultPath); statErr != nil {
+			policyCommandLog.Printf("No policy configured and %s not found", defaultPath)
+			return nil, nil
+		}
+		policyPath = defaultPath
+	}
+
+	policyCommandLog.Printf("Loading policy from %s", policyPath)
+	return workflow.LoadPolicy(policyPath, baseDir)
+}
+
+// RunPolicyCheck evaluates the policy against the given workflows (all workflows when empty)
+func RunPolicyCheck(workflowIDs []string, policyPath string, workflowDir string, jsonOutput bool, verbose bool) error {
+	policyCommandLog.Printf("Running policy check: workflows=%v, policy=%s", workflowIDs, policyPath)
+
+	policy, err := loadPolicy(policyPath)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return fmt.Errorf("no policy found: pass --policy or create %s", workflow.DefaultPolicyPath)
+	}
+
+	var files []string
+	if len(workflowIDs) > 0 {
+		for _, id := range workflowIDs {
+			resolved, err := ResolveWorkflowPath(id)
+			if err != nil {
+				return err
+			}
+			files = append(files, resolved)
+		}
+	} else {
+		files, err = getMarkdownWorkflowFiles(workflowDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	compiler.SetQuiet(true)
+
+	result := PolicyCheckResult{Policy: policy.Source, Violations: []PolicyViolationRow{}}
+	var violations []workflow.PolicyViolation
+	for _, file := range files {
+		workflowData, err := parseWorkflowFileForAnalysis(compiler, file)
+		if err != nil {
+			if _, ok := err.(*workflow.SharedWorkflowError); ok {
+				policyCommandLog.Printf("Skipping shared workflow: %s", file)
+				continue
+			}
+			if _, ok := err.(*workflow.TemplateWorkflowError); ok {
+				policyCommandLog.Printf("Skipping workflow template: %s", file)
+				continue
+			}
+			return fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		result.Workflows++
+		violations = append(violations, workflow.EvaluatePolicy(policy, workflowData)...)
+	}
+
+	workflow.SortPolicyViolations(violations)
+	for _, v := range violations {
+		if v.Level == workflow.PolicyLevelError {
+			result.Errors++
+		} else {
+			result.Warnings++
+		}
+		result.Violations = append(result.Violations, PolicyViolationRow{
+			Workflow: v.Workflow,
+			RuleID:   v.RuleID,
+			Level:    string(v.Level),
+			Message:  v.Message,
+		})
+	}
+
+	if jsonOutput {
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		renderPolicyCheckResult(result)
+	}
+
+	if result.Errors > 0 {
+		return fmt.Errorf("policy check failed: %d error(s), %d warning(s)", result.Errors, result.Warnings)
+	}
+	return nil
+}
+
+// parseWorkflowFileForAnalysis parses a workflow without compiling it. The workflow
+// identifier is set the same way as during compilation so that fuzzy schedules resolve.
+func parseWorkflowFileForAnalysis(compiler *workflow.Compiler, file string) (*workflow.WorkflowData, error) {
+	relPath, err := getRepositoryRelativePath(file)
+	if err != nil {
+		relPath = filepath.Base(file)
+	}
+	compiler.SetWorkflowIdentifier(relPath)
+	return compiler.ParseWorkflowFile(file)
+}
+
+// renderPolicyCheckResult prints the policy check result to stderr
+func renderPolicyCheckResult(result PolicyCheckResult) {
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Policy: %s", result.Policy)))
+	if len(result.Violations) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%d workflow(s) comply with the policy", result.Workflows)))
+		return
+	}
+	fmt.Fprint(os.Stderr, console.RenderStruct(result.Violations))
+	summary := fmt.Sprintf("%d workflow(s) checked: %d error(s), %d warning(s)", result.Workflows, result.Errors, result.Warnings)
+	if result.Errors > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(summary))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(summary))
+	}
+}
@@ -0,0 +1,84 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupPolicyTestRepo creates a git repository with the given workflows and optional default policy
+func setupPolicyTestRepo(t *testing.T, workflows map[string]string, policy string) string {
+	t.Helper()
+	tmpDir := testutil.TempDir(t, "policy-check")
+	require.NoError(t, exec.Command("git", "init", tmpDir).Run())
+
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(workflowsDir, 0755))
+	for name, content := range workflows {
+		require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, name), []byte(content), 0644))
+	}
+
+	if policy != "" {
+		policyPath := filepath.Join(tmpDir, workflow.DefaultPolicyPath)
+		require.NoError(t, os.MkdirAll(filepath.Dir(policyPath), 0755))
+		require.NoError(t, os.WriteFile(policyPath, []byte(policy), 0644))
+	}
+
+	t.Chdir(tmpDir)
+	return tmpDir
+}
+
+const policyCheckTestPolicy = `rules:
+  - id: approved-engines
+    engines:
+      allowed: [copilot]
+  - id: max-timeout
+    level: warning
+    timeout-minutes:
+      max: 10
+`
+
+func TestRunPolicyCheck(t *testing.T) {
+	setupPolicyTestRepo(t, map[string]string{
+		"good.md": "---\non: workflow_dispatch\nengine: copilot\ntimeout-minutes: 5\n---\n\n# Good\n",
+		"slow.md": "---\non: workflow_dispatch\nengine: copilot\ntimeout-minutes: 20\n---\n\n# Slow\n",
+	}, policyCheckTestPolicy)
+
+	err := RunPolicyCheck(nil, "", "", true, false)
+	require.NoError(t, err, "warnings alone should not fail the check")
+
+	err = RunPolicyCheck([]string{"good"}, "", "", false, false)
+	require.NoError(t, err)
+}
+
+func TestRunPolicyCheckFailsOnErrors(t *testing.T) {
+	setupPolicyTestRepo(t, map[string]string{
+		"codex.md": "---\non: workflow_dispatch\nengine: codex\n---\n\n# Codex\n",
+	}, policyCheckTestPolicy)
+
+	err := RunPolicyCheck(nil, "", "", true, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 error(s)")
+}
+
+func TestRunPolicyCheckWithoutPolicy(t *testing.T) {
+	setupPolicyTestRepo(t, map[string]string{
+		"good.md": "---\non: workflow_dispatch\n---\n\n# Good\n",
+	}, "")
+
+	err := RunPolicyCheck(nil, "", "", false, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no policy found")
+
+	policy, err := loadPolicy("")
+	require.NoError(t, err)
+	assert.Nil(t, policy, "missing default policy should not be an error for compile")
+}
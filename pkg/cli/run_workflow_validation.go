@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/console"
@@ -148,6 +149,15 @@ func getWorkflowInputs(markdownPath string) (map[string]*workflow.InputDefinitio
 	return workflow.ParseInputDefinitions(inputsMap), nil
 }
 
+// ResumeRunInput returns the workflow input resuming a workflow from the agent checkpoint of a previous run
+func ResumeRunInput(runID string) (string, error) {
+	id, err := strconv.ParseInt(runID, 10, 64)
+	if err != nil || id <= 0 {
+		return "", fmt.Errorf("invalid run ID '%s' for --resume: expected the numeric ID of a workflow run", runID)
+	}
+	return fmt.Sprintf("%s=%d", constants.ResumeRunIDInputName, id), nil
+}
+
 // validateWorkflowInputs validates that required inputs are provided and checks for typos.
 //
 // This validation function is co-located with the run command implementation because:
@@ -170,6 +180,15 @@ func validateWorkflowInputs(markdownPath string, providedInputs []string) error
 		return nil
 	}
 
+	// The resume input is added by the compiler when the workflow enables resume
+	if _, resumeDefined := workflowInputs[constants.ResumeRunIDInputName]; !resumeDefined {
+		for _, input := range providedInputs {
+			if strings.HasPrefix(input, constants.ResumeRunIDInputName+"=") {
+				return fmt.Errorf("workflow does not support --resume: add 'resume: true' to its frontmatter and recompile. See: %s", constants.DocsResumeURL)
+			}
+		}
+	}
+
 	// If no inputs are defined, no validation needed
 	if len(workflowInputs) == 0 {
 		return nil
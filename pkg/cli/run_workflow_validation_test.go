@@ -381,3 +381,51 @@ jobs:
 	assert.True(t, strings.Contains(err.Error(), "issue_ur") && strings.Contains(err.Error(), "issue_url"),
 		"Error should suggest correct input name")
 }
+
+func TestResumeRunInput(t *testing.T) {
+	input, err := ResumeRunInput("1234567890")
+	require.NoError(t, err)
+	assert.Equal(t, "aw_resume_run_id=1234567890", input)
+
+	for _, runID := range []string{"abc", "0", "-5", "123; rm -rf /"} {
+		_, err := ResumeRunInput(runID)
+		assert.Error(t, err, "run ID %q should be rejected", runID)
+	}
+}
+
+func TestValidateWorkflowInputs_Resume(t *testing.T) {
+	tmpDir := t.TempDir()
+	markdownPath := filepath.Join(tmpDir, "refactor.md")
+	require.NoError(t, os.WriteFile(markdownPath, []byte("# Refactor"), 0644))
+
+	lockPath := getLockFilePath(markdownPath)
+	require.NoError(t, os.WriteFile(lockPath, []byte(`name: "Refactor"
+on:
+  workflow_dispatch:
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "test"
+`), 0644))
+
+	err := validateWorkflowInputs(markdownPath, []string{"aw_resume_run_id=42"})
+	require.Error(t, err, "resume should be rejected when the workflow does not enable it")
+	assert.Contains(t, err.Error(), "resume: true")
+
+	require.NoError(t, os.WriteFile(lockPath, []byte(`name: "Refactor"
+on:
+  workflow_dispatch:
+    inputs:
+      aw_resume_run_id:
+        description: ID of a previous run to resume from its last agent checkpoint
+        required: false
+        type: string
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "test"
+`), 0644))
+	assert.NoError(t, validateWorkflowInputs(markdownPath, []string{"aw_resume_run_id=42"}))
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var safeInputsCommandLog = logger.New("cli:safe_inputs_command")
+
+// SafeInputsTestConfig holds the options of the safe-inputs test command
+type SafeInputsTestConfig struct {
+	Workflow    string // Workflow ID or path declaring the tools
+	Tool        string // Tool to invoke (required without a fixture)
+	Input       string // JSON object of tool arguments
+	FixtureFile string // Fixture file with test cases
+	Timeout     int    // Seconds; overrides the tool timeout when set
+	JSONOutput  bool
+	Verbose     bool
+}
+
+// NewSafeInputsCommand creates the safe-inputs command with subcommands
+func NewSafeInputsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "safe-inputs",
+		Short: "Work with the safe-inputs tools declared by workflows",
+		Long: `Work with the safe-inputs tools declared by workflows.
+
+Available subcommands:
+  • test - Invoke safe-input tools locally and check their results against fixtures`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newSafeInputsTestSubcommand())
+
+	return cmd
+}
+
+// newSafeInputsTestSubcommand creates the safe-inputs test subcommand
+func newSafeInputsTestSubcommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "test <workflow> [tool]",
+		Short: "Invoke safe-input tools locally",
+		Long: `Invoke the safe-input tools of a workflow locally, without starting the MCP server.
+
+Each tool handler is generated and invoked exactly as the safe-inputs MCP server does:
+JavaScript (script), Python (py) and Go (go) handlers receive the inputs as JSON on
+stdin, and shell (run) handlers receive INPUT_<NAME> environment variables and may
+write outputs to $GITHUB_OUTPUT. Inputs are validated against the declared input
+parameters, and the tool timeout is enforced.
+
+With --input, a single tool is invoked and its result is printed.
+
+With --fixture, the test cases of a fixture file are run and each result is compared
+with the expected outcome. The command fails when a test case fails, so it can be
+used in CI. Fixture format:
+
+  tests:
+    - name: greets by name
+      tool: greet
+      input:
+        name: World
+      expect:
+        exit-code: 0
+        result:
+          message: Hello World
+    - name: rejects missing name
+      tool: greet
+      expect:
+        error: missing required input 'name'
+
+Expectations: exit-code, result (exact JSON result), contains (substring of the
+result) and error (substring of the validation or execution error).
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` safe-inputs test my-workflow greet --input '{"name": "World"}'
+  ` + string(constants.CLIExtensionPrefix) + ` safe-inputs test my-workflow --fixture tests/greet.yml
+  ` + string(constants.CLIExtensionPrefix) + ` safe-inputs test my-workflow --fixture tests/greet.yml --json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, _ := cmd.Flags().GetString("input")
+			fixture, _ := cmd.Flags().GetString("fixture")
+			timeout, _ := cmd.Flags().GetInt("timeout")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			config := SafeInputsTestConfig{
+				Workflow:    args[0],
+				Input:       input,
+				FixtureFile: fixture,
+				Timeout:     timeout,
+				JSONOutput:  jsonOutput,
+				Verbose:     verbose,
+			}
+			if len(args) > 1 {
+				config.Tool = args[1]
+			}
+			return RunSafeInputsTest(config)
+		},
+	}
+
+	cmd.Flags().String("input", "", "Tool arguments as a JSON object")
+	cmd.Flags().String("fixture", "", "Fixture file with test cases (YAML)")
+	cmd.Flags().Int("timeout", 0, "Timeout in seconds (default: the tool timeout)")
+	addJSONFlag(cmd)
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+
+	return cmd
+}
+
+// RunSafeInputsTest invokes safe-input tools of a workflow with the given input or fixture
+func RunSafeInputsTest(config SafeInputsTestConfig) error {
+	safeInputsCommandLog.Printf("Testing safe-inputs: workflow=%s, tool=%s, fixture=%s", config.Workflow, config.Tool, config.FixtureFile)
+
+	if config.FixtureFile == "" && config.Tool == "" {
+		return fmt.Errorf("a tool name is required unless --fixture is used")
+	}
+	if config.Timeout < 0 {
+		return fmt.Errorf("--timeout must be a positive number of seconds")
+	}
+
+	tools, err := loadWorkflowSafeInputTools(config.Workflow, config.Verbose)
+	if err != nil {
+		return err
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+	timeout := time.Duration(config.Timeout) * time.Second
+
+	if config.FixtureFile == "" {
+		tool, err := lookupSafeInputTool(tools, config.Tool)
+		if err != nil {
+			return err
+		}
+		args := map[string]any{}
+		if strings.TrimSpace(config.Input) != "" {
+			if err := json.Unmarshal([]byte(config.Input), &args); err != nil {
+				return fmt.Errorf("--input must be a JSON object: %w", err)
+			}
+		}
+
+		result := invokeSafeInputTool(tool, args, timeout, workDir)
+		if config.JSONOutput {
+			jsonBytes, err := json.MarshalIndent(result, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(jsonBytes))
+		} else {
+			renderSafeInputToolResult(result)
+		}
+		if result.Error != "" {
+			return fmt.Errorf("tool %s failed: %s", result.Tool, result.Error)
+		}
+		return nil
+	}
+
+	fixture, err := LoadSafeInputsFixture(config.FixtureFile)
+	if err != nil {
+		return err
+	}
+
+	var outcomes []SafeInputsFixtureOutcome
+	failed := 0
+	for _, tc := range fixture.Tests {
+		if config.Tool != "" && tc.Tool != config.Tool {
+			continue
+		}
+		tool, err := lookupSafeInputTool(tools, tc.Tool)
+		if err != nil {
+			return fmt.Errorf("fixture test %q: %w", tc.Name, err)
+		}
+		caseTimeout := timeout
+		if caseTimeout == 0 && tc.Timeout > 0 {
+			caseTimeout = time.Duration(tc.Timeout) * time.Second
+		}
+
+		outcome := checkSafeInputsFixtureCase(tc, invokeSafeInputTool(tool, tc.Input, caseTimeout, workDir))
+		if outcome.Status != "pass" {
+			failed++
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(outcomes, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else {
+		renderSafeInputsFixtureOutcomes(outcomes)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d safe-inputs test(s) failed", failed, len(outcomes))
+	}
+	return nil
+}
+
+// loadWorkflowSafeInputTools parses a workflow and returns its safe-input tools
+func loadWorkflowSafeInputTools(workflowID string, verbose bool) (map[string]*workflow.SafeInputToolConfig, error) {
+	workflowPath, err := ResolveWorkflowPath(workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	compiler.SetQuiet(true)
+	workflowData, err := parseWorkflowFileForAnalysis(compiler, workflowPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	if !workflow.HasSafeInputs(workflowData.SafeInputs) {
+		return nil, fmt.Errorf("workflow %s does not declare any safe-inputs tools", workflowID)
+	}
+	return workflowData.SafeInputs.Tools, nil
+}
+
+// lookupSafeInputTool finds a tool by name, listing the available tools on error
+func lookupSafeInputTool(tools map[string]*workflow.SafeInputToolConfig, name string) (*workflow.SafeInputToolConfig, error) {
+	if tool, ok := tools[name]; ok {
+		return tool, nil
+	}
+	names := make([]string, 0, len(tools))
+	for toolName := range tools {
+		names = append(names, toolName)
+	}
+	sort.Strings(names)
+	return nil, fmt.Errorf("unknown safe-inputs tool '%s' (available: %s)", name, strings.Join(names, ", "))
+}
+
+// renderSafeInputToolResult prints the result of a single tool invocation
+func renderSafeInputToolResult(result *SafeInputToolResult) {
+	if result.Error != "" {
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s failed (exit code %d, %s): %s", result.Tool, result.ExitCode, result.Duration, result.Error)))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s completed in %s", result.Tool, result.Duration)))
+	}
+	if result.Stderr != "" && result.Error != "" {
+		fmt.Fprintln(os.Stderr, result.Stderr)
+	}
+	if result.Result != nil {
+		jsonBytes, _ := json.MarshalIndent(result.Result, "", "  ")
+		fmt.Println(string(jsonBytes))
+	}
+}
+
+// renderSafeInputsFixtureOutcomes prints the fixture results with diffs for failures
+func renderSafeInputsFixtureOutcomes(outcomes []SafeInputsFixtureOutcome) {
+	if len(outcomes) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No fixture tests matched"))
+		return
+	}
+	fmt.Fprint(os.Stderr, console.RenderStruct(outcomes))
+
+	passed := 0
+	for _, outcome := range outcomes {
+		if outcome.Status == "pass" {
+			passed++
+			continue
+		}
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, console.FormatErrorMessage(fmt.Sprintf("%s (%s)", outcome.Name, outcome.Tool)))
+		for _, failure := range outcome.Failures {
+			fmt.Fprintln(os.Stderr, console.FormatListItem(failure))
+		}
+		if outcome.Diff != "" {
+			fmt.Fprintln(os.Stderr, outcome.Diff)
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, "")
+	if passed == len(outcomes) {
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("All %d safe-inputs test(s) passed", passed)))
+	} else {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d of %d safe-inputs test(s) passed", passed, len(outcomes))))
+	}
+}
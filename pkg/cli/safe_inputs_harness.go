@@ -0,0 +1,421 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aymanbagabas/go-udiff"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/goccy/go-yaml"
+)
+
+var safeInputsHarnessLog = logger.New("cli:safe_inputs_harness")
+
+// defaultSafeInputToolTimeout matches the default timeout of the safe-inputs MCP server handlers
+const defaultSafeInputToolTimeout = 60 * time.Second
+
+// safeInputJavaScriptRunner loads a generated JavaScript tool module, calls its execute function
+// with the inputs read as JSON from stdin and writes the result as JSON to stdout
+const safeInputJavaScriptRunner = `const { execute } = require(process.argv[2]);
+let input = "";
+process.stdin.on("data", chunk => (input += chunk));
+process.stdin.on("end", async () => {
+  try {
+    const result = await execute(input.trim() ? JSON.parse(input) : {});
+    if (result !== undefined) {
+      process.stdout.write(JSON.stringify(result));
+    }
+  } catch (error) {
+    console.error(error instanceof Error ? error.stack : String(error));
+    process.exit(1);
+  }
+});
+`
+
+// SafeInputToolResult is the outcome of invoking a safe-input tool handler
+type SafeInputToolResult struct {
+	Tool     string         `json:"tool"`
+	Inputs   map[string]any `json:"inputs"`
+	ExitCode int            `json:"exit_code"`
+	TimedOut bool           `json:"timed_out,omitempty"`
+	Duration string         `json:"duration"`
+	Result   any            `json:"result,omitempty"` // Tool result as returned to the agent (the MCP text content, decoded)
+	Stderr   string         `json:"stderr,omitempty"`
+	Error    string         `json:"error,omitempty"` // Validation or execution error (the MCP call would fail)
+}
+
+// SafeInputsFixture is a file of test cases for safe-input tools
+//
+//	tests:
+//	  - name: greets by name
+//	    tool: greet
+//	    input: {name: World}
+//	    expect:
+//	      exit-code: 0
+//	      result: {message: Hello World}
+type SafeInputsFixture struct {
+	Workflow string                  `yaml:"workflow,omitempty"` // Workflow declaring the tools (when not given on the command line)
+	Tests    []SafeInputsFixtureCase `yaml:"tests"`
+}
+
+// SafeInputsFixtureCase is a single tool invocation with its expected outcome
+type SafeInputsFixtureCase struct {
+	Name    string                  `yaml:"name"`
+	Tool    string                  `yaml:"tool"`
+	Input   map[string]any          `yaml:"input,omitempty"`
+	Timeout int                     `yaml:"timeout,omitempty"` // Seconds; overrides the tool timeout
+	Expect  SafeInputsFixtureExpect `yaml:"expect"`
+}
+
+// SafeInputsFixtureExpect describes the expected outcome of a fixture case.
+// Only the fields that are set are checked.
+type SafeInputsFixtureExpect struct {
+	ExitCode *int   `yaml:"exit-code,omitempty"`
+	Result   any    `yaml:"result,omitempty"`   // Exact tool result (JSON value)
+	Contains string `yaml:"contains,omitempty"` // Substring of the JSON-encoded tool result
+	Error    string `yaml:"error,omitempty"`    // Substring of the validation or execution error
+}
+
+// SafeInputsFixtureOutcome is the result of running a fixture case
+type SafeInputsFixtureOutcome struct {
+	Name     string               `json:"name" console:"header:Test"`
+	Tool     string               `json:"tool" console:"header:Tool"`
+	Status   string               `json:"status" console:"header:Status"`
+	Duration string               `json:"duration" console:"header:Duration"`
+	Failures []string             `json:"failures,omitempty" console:"-"`
+	Diff     string               `json:"diff,omitempty" console:"-"`
+	Result   *SafeInputToolResult `json:"result" console:"-"`
+}
+
+// LoadSafeInputsFixture reads a fixture file
+func LoadSafeInputsFixture(path string) (*SafeInputsFixture, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture file: %w", err)
+	}
+	var fixture SafeInputsFixture
+	if err := yaml.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture file %s: %w", path, err)
+	}
+	if len(fixture.Tests) == 0 {
+		return nil, fmt.Errorf("fixture file %s has no tests", path)
+	}
+	for i, tc := range fixture.Tests {
+		if tc.Tool == "" {
+			return nil, fmt.Errorf("fixture test %d: tool is required", i+1)
+		}
+		if tc.Name == "" {
+			fixture.Tests[i].Name = fmt.Sprintf("%s #%d", tc.Tool, i+1)
+		}
+	}
+	return &fixture, nil
+}
+
+// validateSafeInputArgs validates tool arguments against the declared input parameters
+func validateSafeInputArgs(tool *workflow.SafeInputToolConfig, args map[string]any) []string {
+	var problems []string
+
+	names := make([]string, 0, len(tool.Inputs))
+	for name := range tool.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		param := tool.Inputs[name]
+		value, ok := args[name]
+		if !ok || value == nil {
+			if param.Required {
+				problems = append(problems, fmt.Sprintf("missing required input '%s'", name))
+			}
+			continue
+		}
+		problems = append(problems, workflow.ValidateSafeInputValue(param, value, name)...)
+	}
+
+	unknown := make([]string, 0)
+	for name := range args {
+		if _, ok := tool.Inputs[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		problems = append(problems, fmt.Sprintf("unknown input '%s'", name))
+	}
+
+	return problems
+}
+
+// writeSafeInputToolHandler writes the handler file of a tool as generated for the safe-inputs server
+// and returns its path
+func writeSafeInputToolHandler(dir string, tool *workflow.SafeInputToolConfig) (string, error) {
+	var name, content string
+	mode := os.FileMode(0644)
+	switch {
+	case tool.Script != "":
+		name, content = tool.Name+".cjs", workflow.GenerateSafeInputJavaScriptToolScriptForInspector(tool)
+	case tool.Run != "":
+		name, content, mode = tool.Name+".sh", workflow.GenerateSafeInputShellToolScriptForInspector(tool), 0755
+	case tool.Py != "":
+		name, content, mode = tool.Name+".py", workflow.GenerateSafeInputPythonToolScriptForInspector(tool), 0755
+	case tool.Go != "":
+		name, content = tool.Name+".go", workflow.GenerateSafeInputGoToolScriptForInspector(tool)
+	default:
+		return "", fmt.Errorf("tool '%s' has no implementation (script, run, py, go or container)", tool.Name)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		return "", fmt.Errorf("failed to write handler for tool '%s': %w", tool.Name, err)
+	}
+	return path, nil
+}
+
+// invokeSafeInputTool validates the inputs and runs the tool handler the way the safe-inputs MCP
+// server does: JavaScript tools are called with the inputs and their result is encoded as JSON,
+// Python and Go handlers receive the inputs as JSON on stdin and their stdout is decoded as
+// JSON; shell handlers receive INPUT_<NAME> environment variables and may
+// write key=value outputs to $GITHUB_OUTPUT. Container tools run their image with docker and
+// receive the inputs as JSON on stdin.
+func invokeSafeInputTool(tool *workflow.SafeInputToolConfig, args map[string]any, timeout time.Duration, workDir string) *SafeInputToolResult {
+	if args == nil {
+		args = map[string]any{}
+	}
+	result := &SafeInputToolResult{Tool: tool.Name, Inputs: args}
+
+	if problems := validateSafeInputArgs(tool, args); len(problems) > 0 {
+		result.ExitCode = -1
+		result.Duration = "0s"
+		result.Error = "invalid arguments: " + strings.Join(problems, "; ")
+		return result
+	}
+
+	if timeout <= 0 {
+		timeout = defaultSafeInputToolTimeout
+		if tool.Timeout > 0 {
+			timeout = time.Duration(tool.Timeout) * time.Second
+		}
+	}
+
+	handlerDir, err := os.MkdirTemp("", "gh-aw-safe-input-*")
+	if err != nil {
+		result.ExitCode = -1
+		result.Error = err.Error()
+		return result
+	}
+	defer os.RemoveAll(handlerDir)
+
+	inputJSON, err := json.Marshal(args)
+	if err != nil {
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("failed to encode inputs: %v", err)
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	env := os.Environ()
+	outputFile := ""
+	containerName := ""
+	handlerPath := tool.Container
+	if tool.Container != "" {
+		// Container tools run the same docker command as the safe-inputs MCP server
+		containerName = fmt.Sprintf("gh-aw-safe-input-%s-%d", tool.Name, time.Now().UnixNano())
+		cmd = exec.CommandContext(ctx, "docker", workflow.BuildSafeInputContainerRunArgs(tool, workDir, containerName)...)
+		cmd.Stdin = bytes.NewReader(inputJSON)
+	} else {
+		handlerPath, err = writeSafeInputToolHandler(handlerDir, tool)
+		if err != nil {
+			result.ExitCode = -1
+			result.Error = err.Error()
+			return result
+		}
+
+		switch filepath.Ext(handlerPath) {
+		case ".sh":
+			cmd = exec.CommandContext(ctx, handlerPath)
+			names := make([]string, 0, len(args))
+			for name := range args {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				env = append(env, fmt.Sprintf("INPUT_%s=%s", strings.ReplaceAll(strings.ToUpper(name), "-", "_"), formatShellInputValue(args[name])))
+			}
+			outputFile = filepath.Join(handlerDir, "github_output.txt")
+			if err := os.WriteFile(outputFile, nil, 0644); err != nil {
+				result.ExitCode = -1
+				result.Error = err.Error()
+				return result
+			}
+			env = append(env, "GITHUB_OUTPUT="+outputFile)
+		case ".py":
+			cmd = exec.CommandContext(ctx, "python3", handlerPath)
+			cmd.Stdin = bytes.NewReader(inputJSON)
+		case ".go":
+			cmd = exec.CommandContext(ctx, "go", "run", handlerPath)
+			cmd.Stdin = bytes.NewReader(inputJSON)
+		default:
+			// The generated module only exports execute, so a runner loads it and calls it
+			runnerPath := filepath.Join(handlerDir, "run_safe_input.cjs")
+			if err := os.WriteFile(runnerPath, []byte(safeInputJavaScriptRunner), 0644); err != nil {
+				result.ExitCode = -1
+				result.Error = err.Error()
+				return result
+			}
+			cmd = exec.CommandContext(ctx, "node", runnerPath, handlerPath)
+			cmd.Stdin = bytes.NewReader(inputJSON)
+		}
+	}
+	cmd.Env = env
+	cmd.Dir = workDir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	safeInputsHarnessLog.Printf("Invoking tool %s via %s (timeout %s)", tool.Name, handlerPath, timeout)
+	start := time.Now()
+	runErr := cmd.Run()
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	result.Stderr = stderr.String()
+
+	if ctx.Err() == context.DeadlineExceeded {
+		if containerName != "" {
+			// Killing the docker client does not stop the container, so remove it explicitly
+			_ = exec.Command("docker", "rm", "-f", containerName).Run()
+		}
+		result.TimedOut = true
+		result.ExitCode = -1
+		result.Error = fmt.Sprintf("tool timed out after %s", timeout)
+		return result
+	}
+	if runErr != nil {
+		var exitErr *exec.ExitError
+		if errors.As(runErr, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		} else {
+			result.ExitCode = -1
+		}
+		result.Error = runErr.Error()
+		return result
+	}
+
+	if outputFile != "" {
+		outputs := map[string]any{}
+		if content, err := os.ReadFile(outputFile); err == nil {
+			for line := range strings.SplitSeq(string(content), "\n") {
+				key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+				if ok {
+					outputs[key] = value
+				}
+			}
+		}
+		result.Result = map[string]any{"stdout": stdout.String(), "stderr": stderr.String(), "outputs": outputs}
+		return result
+	}
+
+	var decoded any
+	if trimmed := strings.TrimSpace(stdout.String()); trimmed != "" && json.Unmarshal([]byte(trimmed), &decoded) == nil {
+		result.Result = decoded
+	} else {
+		result.Result = map[string]any{"stdout": stdout.String(), "stderr": stderr.String()}
+	}
+	return result
+}
+
+// formatShellInputValue converts an input to the string passed in INPUT_<NAME> (String(value) in JavaScript)
+func formatShellInputValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []any, map[string]any:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// checkSafeInputsFixtureCase compares a tool result with the expectation of a fixture case
+func checkSafeInputsFixtureCase(tc SafeInputsFixtureCase, result *SafeInputToolResult) SafeInputsFixtureOutcome {
+	outcome := SafeInputsFixtureOutcome{Name: tc.Name, Tool: tc.Tool, Duration: result.Duration, Result: result}
+	expect := tc.Expect
+
+	if expect.Error != "" {
+		if !strings.Contains(result.Error, expect.Error) {
+			outcome.Failures = append(outcome.Failures, fmt.Sprintf("expected error containing %q, got %q", expect.Error, result.Error))
+		}
+	} else if result.Error != "" && (expect.ExitCode == nil || *expect.ExitCode != result.ExitCode) {
+		outcome.Failures = append(outcome.Failures, "unexpected error: "+result.Error)
+	}
+
+	if expect.ExitCode != nil && *expect.ExitCode != result.ExitCode {
+		outcome.Failures = append(outcome.Failures, fmt.Sprintf("expected exit code %d, got %d", *expect.ExitCode, result.ExitCode))
+	}
+
+	actualJSON, _ := json.MarshalIndent(result.Result, "", "  ")
+	if expect.Contains != "" && !strings.Contains(string(actualJSON), expect.Contains) {
+		outcome.Failures = append(outcome.Failures, fmt.Sprintf("expected result containing %q", expect.Contains))
+	}
+
+	if expect.Result != nil {
+		expectedJSON, err := json.MarshalIndent(normalizeFixtureValue(expect.Result), "", "  ")
+		if err != nil {
+			outcome.Failures = append(outcome.Failures, fmt.Sprintf("invalid expected result: %v", err))
+		} else {
+			var expected, actual any
+			_ = json.Unmarshal(expectedJSON, &expected)
+			_ = json.Unmarshal(actualJSON, &actual)
+			if !reflect.DeepEqual(expected, actual) {
+				outcome.Failures = append(outcome.Failures, "result does not match expected result")
+				outcome.Diff = udiff.Unified("expected", "actual", string(expectedJSON)+"\n", string(actualJSON)+"\n")
+			}
+		}
+	}
+
+	outcome.Status = "pass"
+	if len(outcome.Failures) > 0 {
+		outcome.Status = "fail"
+	}
+	return outcome
+}
+
+// normalizeFixtureValue converts YAML-decoded maps to JSON-compatible maps
+func normalizeFixtureValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		normalized := make(map[string]any, len(v))
+		for key, item := range v {
+			normalized[key] = normalizeFixtureValue(item)
+		}
+		return normalized
+	case map[any]any:
+		normalized := make(map[string]any, len(v))
+		for key, item := range v {
+			normalized[fmt.Sprintf("%v", key)] = normalizeFixtureValue(item)
+		}
+		return normalized
+	case []any:
+		normalized := make([]any, len(v))
+		for i, item := range v {
+			normalized[i] = normalizeFixtureValue(item)
+		}
+		return normalized
+	default:
+		return v
+	}
+}
@@ -0,0 +1,169 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const safeInputsHarnessWorkflow = `---
+on: workflow_dispatch
+engine: copilot
+safe-inputs:
+  greet:
+    description: Greet someone
+    inputs:
+      name:
+        type: string
+        required: true
+      excited:
+        type: boolean
+    run: |
+      echo "greeting=Hello $INPUT_NAME" >> "$GITHUB_OUTPUT"
+      echo "done"
+  fail:
+    description: Always fails
+    run: |
+      echo "boom" >&2
+      exit 3
+  shout:
+    description: Shout a greeting
+    inputs:
+      name:
+        type: string
+        required: true
+    script: |
+      if (name === "nobody") {
+        throw new Error("nobody to greet");
+      }
+      return { greeting: "HELLO " + name.toUpperCase() };
+  slow:
+    description: Sleeps
+    timeout: 1
+    run: |
+      sleep 5
+---
+
+# Harness
+`
+
+func TestValidateSafeInputArgs(t *testing.T) {
+	tool := &workflow.SafeInputToolConfig{
+		Name: "search",
+		Inputs: map[string]*workflow.SafeInputParam{
+			"query": {Type: "string", Required: true},
+			"limit": {Type: "integer"},
+			"tags":  {Type: "array", Items: &workflow.SafeInputParam{Type: "string", Enum: []any{"bug", "docs"}}},
+		},
+	}
+
+	assert.Empty(t, validateSafeInputArgs(tool, map[string]any{"query": "bugs", "limit": float64(5), "tags": []any{"bug"}}))
+	assert.Equal(t, []string{
+		"limit must be of type integer, got number",
+		"missing required input 'query'",
+		"unknown input 'sort'",
+	}, validateSafeInputArgs(tool, map[string]any{"limit": 2.5, "sort": "asc"}))
+	assert.Equal(t, []string{`tags[0] must be one of: "bug", "docs"`},
+		validateSafeInputArgs(tool, map[string]any{"query": "bugs", "tags": []any{"feature"}}))
+}
+
+func TestRunSafeInputsTest(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is required")
+	}
+	tmpDir := setupPolicyTestRepo(t, map[string]string{"harness.md": safeInputsHarnessWorkflow}, "")
+
+	tools, err := loadWorkflowSafeInputTools("harness", false)
+	require.NoError(t, err)
+
+	result := invokeSafeInputTool(tools["greet"], map[string]any{"name": "World"}, 0, tmpDir)
+	require.Empty(t, result.Error)
+	assert.Equal(t, map[string]any{
+		"stdout":  "done\n",
+		"stderr":  "",
+		"outputs": map[string]any{"greeting": "Hello World"},
+	}, result.Result)
+
+	result = invokeSafeInputTool(tools["fail"], nil, 0, tmpDir)
+	assert.Equal(t, 3, result.ExitCode)
+	assert.Contains(t, result.Stderr, "boom")
+
+	result = invokeSafeInputTool(tools["slow"], nil, 0, tmpDir)
+	assert.True(t, result.TimedOut, "the tool timeout should be enforced")
+
+	result = invokeSafeInputTool(tools["greet"], map[string]any{"name": 42}, time.Second, tmpDir)
+	assert.Contains(t, result.Error, "name must be of type string, got integer")
+
+	fixturePath := filepath.Join(tmpDir, "greet.yml")
+	require.NoError(t, os.WriteFile(fixturePath, []byte(`tests:
+  - name: greets by name
+    tool: greet
+    input:
+      name: World
+    expect:
+      result:
+        stdout: "done\n"
+        stderr: ""
+        outputs:
+          greeting: Hello World
+  - name: requires a name
+    tool: greet
+    expect:
+      error: missing required input 'name'
+  - name: reports exit code
+    tool: fail
+    expect:
+      exit-code: 3
+`), 0644))
+	require.NoError(t, RunSafeInputsTest(SafeInputsTestConfig{Workflow: "harness", FixtureFile: fixturePath}))
+
+	require.NoError(t, os.WriteFile(fixturePath, []byte(`tests:
+  - name: wrong greeting
+    tool: greet
+    input:
+      name: World
+    expect:
+      contains: Goodbye
+`), 0644))
+	err = RunSafeInputsTest(SafeInputsTestConfig{Workflow: "harness", FixtureFile: fixturePath, JSONOutput: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 1 safe-inputs test(s) failed")
+}
+
+func TestRunSafeInputsTestJavaScriptTool(t *testing.T) {
+	if _, err := exec.LookPath("node"); err != nil {
+		t.Skip("node is required")
+	}
+	tmpDir := setupPolicyTestRepo(t, map[string]string{"harness.md": safeInputsHarnessWorkflow}, "")
+
+	tools, err := loadWorkflowSafeInputTools("harness", false)
+	require.NoError(t, err)
+
+	result := invokeSafeInputTool(tools["shout"], map[string]any{"name": "World"}, 0, tmpDir)
+	require.Empty(t, result.Error)
+	assert.Equal(t, map[string]any{"greeting": "HELLO WORLD"}, result.Result, "the script body should run and return its result")
+
+	result = invokeSafeInputTool(tools["shout"], map[string]any{"name": "nobody"}, 0, tmpDir)
+	assert.Equal(t, 1, result.ExitCode, "errors thrown by the script should fail the call")
+	assert.Contains(t, result.Stderr, "nobody to greet")
+}
+
+func TestCheckSafeInputsFixtureCaseDiff(t *testing.T) {
+	tc := SafeInputsFixtureCase{Name: "diff", Tool: "t", Expect: SafeInputsFixtureExpect{Result: map[string]any{"count": 2}}}
+	outcome := checkSafeInputsFixtureCase(tc, &SafeInputToolResult{Tool: "t", Result: map[string]any{"count": float64(3)}})
+	assert.Equal(t, "fail", outcome.Status)
+	assert.Contains(t, outcome.Diff, `-  "count": 2`)
+	assert.Contains(t, outcome.Diff, `+  "count": 3`)
+
+	tc.Expect.Result = map[string]any{"count": uint64(3)}
+	outcome = checkSafeInputsFixtureCase(tc, &SafeInputToolResult{Tool: "t", Result: map[string]any{"count": float64(3)}})
+	assert.Equal(t, "pass", outcome.Status, "YAML numbers should compare equal to JSON numbers")
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var sandboxFilesystemViolationsLog = logger.New("cli:sandbox_filesystem_violations")
+
+// SandboxFilesystemPolicy is the filesystem policy of the agent sandbox recorded in aw_info.json
+type SandboxFilesystemPolicy struct {
+	ReadOnly []string `json:"read_only,omitempty"`
+	Writable []string `json:"writable,omitempty"`
+	Denied   []string `json:"denied,omitempty"`
+}
+
+// FilesystemViolation is an attempt of the agent to access a path protected by the filesystem policy
+type FilesystemViolation struct {
+	Path    string `json:"path" console:"header:Path"`                 // Path of the policy that was accessed
+	Rule    string `json:"rule" console:"header:Rule"`                 // read-only or denied
+	File    string `json:"file" console:"header:Log"`                  // Log file reporting the violation
+	Line    int    `json:"line" console:"header:Line"`                 // Line of the log file
+	Message string `json:"message" console:"header:Message,maxlen:60"` // Log line reporting the violation
+}
+
+// filesystemErrorMarkers are the errors reported when writing a read-only path or reading a denied path
+var filesystemErrorMarkers = []string{
+	"read-only file system",
+	"erofs",
+	"permission denied",
+	"eacces",
+	"operation not permitted",
+	"eperm",
+}
+
+// deniedPathErrorMarkers are the additional errors reported when reading a denied path, which
+// AWF replaces with an empty directory
+var deniedPathErrorMarkers = []string{
+	"no such file or directory",
+	"enoent",
+}
+
+// maxFilesystemViolationMessageLength is the maximum length of a log line kept in a violation
+const maxFilesystemViolationMessageLength = 200
+
+// filesystemPolicyRule is a path of the filesystem policy and the rule applied to it
+type filesystemPolicyRule struct {
+	path string
+	key  string // Form of the path matched against log lines
+	rule string // read-only, writable or denied
+}
+
+// filesystemPolicyMatchKey returns the form of a policy path found in log lines: relative paths
+// appear after the workspace and paths under ~ after the home directory
+func filesystemPolicyMatchKey(p string) string {
+	p = strings.TrimSuffix(p, "/")
+	if strings.HasPrefix(p, "~/") {
+		return strings.TrimPrefix(p, "~")
+	}
+	return strings.TrimPrefix(p, "./")
+}
+
+// analyzeFilesystemViolations reads the filesystem policy of the run from aw_info.json and scans
+// the logs of the run for errors accessing the read-only and denied paths of the policy
+func analyzeFilesystemViolations(runDir string, verbose bool) ([]FilesystemViolation, error) {
+	info, err := parseAwInfo(filepath.Join(runDir, "aw_info.json"), verbose)
+	if err != nil || info == nil || info.SandboxFilesystem == nil {
+		return nil, nil
+	}
+	policy := info.SandboxFilesystem
+
+	var rules []filesystemPolicyRule
+	addRules := func(paths []string, rule string) {
+		for _, p := range paths {
+			rules = append(rules, filesystemPolicyRule{path: p, key: filesystemPolicyMatchKey(p), rule: rule})
+		}
+	}
+	addRules(policy.ReadOnly, "read-only")
+	addRules(policy.Writable, "writable")
+	addRules(policy.Denied, "denied")
+	// Match the most specific path first, so writable paths inside read-only paths are not reported
+	sort.SliceStable(rules, func(i, j int) bool { return len(rules[i].key) > len(rules[j].key) })
+
+	var violations []FilesystemViolation
+	seen := make(map[string]bool)
+	err = filepath.WalkDir(runDir, func(path string, entry fs.DirEntry, walkErr error) error {
+		if walkErr != nil || entry.IsDir() {
+			return nil
+		}
+		switch filepath.Ext(path) {
+		case ".log", ".txt", ".jsonl":
+		default:
+			return nil
+		}
+
+		relPath, relErr := filepath.Rel(runDir, path)
+		if relErr != nil {
+			relPath = path
+		}
+		fileViolations, scanErr := scanFilesystemViolations(path, relPath, rules)
+		if scanErr != nil {
+			sandboxFilesystemViolationsLog.Printf("Failed to scan %s: %v", path, scanErr)
+			return nil
+		}
+		for _, violation := range fileViolations {
+			key := violation.Path + "\x00" + violation.Message
+			if !seen[key] {
+				seen[key] = true
+				violations = append(violations, violation)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan logs for filesystem violations: %w", err)
+	}
+
+	sandboxFilesystemViolationsLog.Printf("Found %d filesystem policy violations", len(violations))
+	if verbose && len(violations) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Found %d sandbox filesystem policy violations", len(violations))))
+	}
+	return violations, nil
+}
+
+// scanFilesystemViolations returns the lines of a log file reporting an error on a protected path
+func scanFilesystemViolations(path string, displayPath string, rules []filesystemPolicyRule) ([]FilesystemViolation, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var violations []FilesystemViolation
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		lower := strings.ToLower(line)
+		if !containsAny(lower, filesystemErrorMarkers) && !containsAny(lower, deniedPathErrorMarkers) {
+			continue
+		}
+
+		for _, rule := range rules {
+			if !strings.Contains(line, rule.key) {
+				continue
+			}
+			// The most specific matching path decides whether the access was allowed
+			if rule.rule == "writable" {
+				break
+			}
+			if rule.rule == "read-only" && !containsAny(lower, filesystemErrorMarkers) {
+				break
+			}
+			message := strings.TrimSpace(line)
+			if len(message) > maxFilesystemViolationMessageLength {
+				message = message[:maxFilesystemViolationMessageLength] + "..."
+			}
+			violations = append(violations, FilesystemViolation{
+				Path:    rule.path,
+				Rule:    rule.rule,
+				File:    displayPath,
+				Line:    lineNumber,
+				Message: message,
+			})
+			break
+		}
+	}
+	return violations, scanner.Err()
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings []string) bool {
+	for _, substring := range substrings {
+		if strings.Contains(s, substring) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleFilesystemPolicyAwInfo = `{
+  "engine_id": "claude",
+  "workflow_name": "guard",
+  "sandbox_filesystem": {
+    "read_only": [".github/workflows"],
+    "writable": [".github/workflows/shared"],
+    "denied": ["~/.ssh"]
+  }
+}`
+
+const sampleFilesystemViolationsLog = `Editing .github/workflows/ci.yml
+Error: EROFS: read-only file system, open '/home/runner/work/repo/repo/.github/workflows/ci.yml'
+Error: EROFS: read-only file system, open '/home/runner/work/repo/repo/.github/workflows/ci.yml'
+Wrote /home/runner/work/repo/repo/.github/workflows/shared/tools.md
+cat: /home/runner/.ssh/id_rsa: No such file or directory
+cp: cannot create regular file '/home/runner/work/repo/repo/.github/workflows/shared/x.md': Permission denied
+ls: cannot access '/home/runner/work/repo/repo/.github/workflows/missing.yml': No such file or directory
+rm: cannot remove '/home/runner/work/repo/repo/src/main.go': Permission denied
+`
+
+func TestAnalyzeFilesystemViolations(t *testing.T) {
+	runDir := testutil.TempDir(t, "filesystem-violations")
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "aw_info.json"), []byte(sampleFilesystemPolicyAwInfo), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "agent-stdio.log"), []byte(sampleFilesystemViolationsLog), 0644))
+
+	violations, err := analyzeFilesystemViolations(runDir, false)
+	require.NoError(t, err)
+	require.Len(t, violations, 2, "duplicate lines, writable paths, missing read-only files and unprotected paths should not be reported")
+
+	assert.Equal(t, ".github/workflows", violations[0].Path)
+	assert.Equal(t, "read-only", violations[0].Rule)
+	assert.Equal(t, "agent-stdio.log", violations[0].File)
+	assert.Equal(t, 2, violations[0].Line)
+
+	assert.Equal(t, "~/.ssh", violations[1].Path)
+	assert.Equal(t, "denied", violations[1].Rule)
+	assert.Equal(t, 5, violations[1].Line)
+}
+
+func TestAnalyzeFilesystemViolationsWithoutPolicy(t *testing.T) {
+	runDir := testutil.TempDir(t, "filesystem-violations")
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "aw_info.json"), []byte(`{"engine_id": "claude"}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "agent-stdio.log"), []byte(sampleFilesystemViolationsLog), 0644))
+
+	violations, err := analyzeFilesystemViolations(runDir, false)
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+}
@@ -0,0 +1,159 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var sbomCommandLog = logger.New("cli:sbom_command")
+
+// NewSBOMCommand creates the sbom command
+func NewSBOMCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom [workflow]...",
+		Short: "Generate a software bill of materials for compiled workflows",
+		Long: `Generate a software bill of materials (SBOM) for compiled workflows.
+
+The SBOM lists every third-party component a compiled workflow pulls in: pinned
+actions, container images (MCP servers, AWF firewall, MCP gateway), the engine CLI,
+npm/pip/uv/Go packages, runtime versions and remote imports, with their versions,
+digests and the frontmatter location they originate from.
+
+Components are read from the compiled .lock.yml file, so workflows must be compiled first.
+Supported formats are CycloneDX 1.5 JSON (default) and SPDX 2.3 JSON.
+
+When a single workflow is given and --output is not set, the SBOM is printed to stdout.
+Otherwise one <workflow>.sbom.json file is written per workflow, into --output or next
+to each lock file.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` sbom ci-doctor                    # Print a CycloneDX SBOM to stdout
+  ` + string(constants.CLIExtensionPrefix) + ` sbom ci-doctor --format spdx      # Print an SPDX SBOM to stdout
+  ` + string(constants.CLIExtensionPrefix) + ` sbom                              # Write an SBOM next to every lock file
+  ` + string(constants.CLIExtensionPrefix) + ` sbom -o sboms                     # Write all SBOMs to the sboms directory`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, _ := cmd.Flags().GetString("format")
+			outputDir, _ := cmd.Flags().GetString("output")
+			dir, _ := cmd.Flags().GetString("dir")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			return RunSBOM(args, format, outputDir, dir, verbose)
+		},
+	}
+
+	cmd.Flags().StringP("format", "f", string(workflow.SBOMFormatCycloneDX), "SBOM format: cyclonedx or spdx")
+	cmd.Flags().StringP("output", "o", "", "Directory to write <workflow>.sbom.json files to")
+	cmd.Flags().StringP("dir", "d", "", "Workflow directory (default: .github/workflows)")
+	cmd.ValidArgsFunction = CompleteWorkflowNames
+	RegisterDirFlagCompletion(cmd, "dir")
+
+	return cmd
+}
+
+// RunSBOM generates SBOMs for the given workflows (all workflows when empty)
+func RunSBOM(workflowIDs []string, format string, outputDir string, workflowDir string, verbose bool) error {
+	sbomCommandLog.Printf("Generating SBOMs: workflows=%v, format=%s, output=%s", workflowIDs, format, outputDir)
+
+	sbomFormat, err := workflow.ParseSBOMFormat(format)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if len(workflowIDs) > 0 {
+		for _, id := range workflowIDs {
+			resolved, err := ResolveWorkflowPath(id)
+			if err != nil {
+				return err
+			}
+			files = append(files, resolved)
+		}
+	} else {
+		files, err = getMarkdownWorkflowFiles(workflowDir)
+		if err != nil {
+			return err
+		}
+	}
+
+	if outputDir != "" {
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(verbose))
+	compiler.SetQuiet(true)
+	toStdout := len(workflowIDs) == 1 && outputDir == ""
+
+	written := 0
+	for _, file := range files {
+		sbom, err := buildWorkflowSBOM(compiler, file)
+		if err != nil {
+			var sharedErr *workflow.SharedWorkflowError
+			var templateErr *workflow.TemplateWorkflowError
+			if errors.As(err, &sharedErr) || errors.As(err, &templateErr) {
+				sbomCommandLog.Printf("Skipping shared workflow or template: %s", file)
+				continue
+			}
+			return err
+		}
+
+		content, err := sbom.Marshal(sbomFormat)
+		if err != nil {
+			return err
+		}
+
+		if toStdout {
+			fmt.Print(string(content))
+			return nil
+		}
+
+		sbomPath := workflow.SBOMFilePath(stringutil.MarkdownToLockFile(file))
+		if outputDir != "" {
+			sbomPath = filepath.Join(outputDir, filepath.Base(sbomPath))
+		}
+		if err := os.WriteFile(sbomPath, content, 0644); err != nil {
+			return fmt.Errorf("failed to write SBOM: %w", err)
+		}
+		written++
+		if verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%s: %d components", console.ToRelativePath(sbomPath), len(sbom.Components))))
+		}
+	}
+
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Generated %d %s SBOM(s)", written, sbomFormat)))
+	return nil
+}
+
+// buildWorkflowSBOM builds the SBOM of a workflow from its markdown source and compiled lock file
+func buildWorkflowSBOM(compiler *workflow.Compiler, markdownPath string) (*workflow.SBOM, error) {
+	workflowData, err := parseWorkflowFileForAnalysis(compiler, markdownPath)
+	if err != nil {
+		return nil, err
+	}
+
+	lockFile := stringutil.MarkdownToLockFile(markdownPath)
+	lockContent, err := os.ReadFile(lockFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("lock file not found for %s: run '%s compile' first", console.ToRelativePath(markdownPath), string(constants.CLIExtensionPrefix))
+		}
+		return nil, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	sbom := workflow.BuildSBOM(workflowData, string(lockContent))
+	sbom.LockFile = filepath.ToSlash(console.ToRelativePath(lockFile))
+	sbom.ToolVersion = GetVersion()
+	sbom.Created = workflow.SBOMCreationTime()
+	return sbom, nil
+}
@@ -0,0 +1,44 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunSBOM(t *testing.T) {
+	tmpDir := setupPolicyTestRepo(t, map[string]string{
+		"triage.md": "---\non: workflow_dispatch\nengine: copilot\n---\n\n# Triage\n",
+	}, "")
+
+	err := RunSBOM(nil, "cyclonedx", "", "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "lock file not found", "uncompiled workflows should be reported")
+
+	workflowPath := filepath.Join(tmpDir, ".github", "workflows", "triage.md")
+	require.NoError(t, workflow.NewCompiler().CompileWorkflow(workflowPath))
+
+	outputDir := filepath.Join(tmpDir, "sboms")
+	require.NoError(t, RunSBOM(nil, "spdx", outputDir, "", false))
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "triage.sbom.json"))
+	require.NoError(t, err)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(content, &doc))
+	assert.Equal(t, "SPDX-2.3", doc["spdxVersion"])
+
+	// Without --output, SBOMs are written next to the lock files
+	require.NoError(t, RunSBOM(nil, "cyclonedx", "", "", false))
+	assert.FileExists(t, filepath.Join(tmpDir, ".github", "workflows", "triage.sbom.json"))
+
+	err = RunSBOM([]string{"triage"}, "xml", "", "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported SBOM format")
+}
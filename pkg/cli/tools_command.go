@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var toolsCommandLog = logger.New("cli:tools_command")
+
+// githubMCPServerName is the gateway server name of the GitHub MCP server
+const githubMCPServerName = "github"
+
+// ToolsAdviseConfig holds the options of the tools advise command
+type ToolsAdviseConfig struct {
+	WorkflowID string
+	LogsDir    string // Directory of runs downloaded by 'logs'
+	Runs       int    // Maximum number of recent runs to analyze
+	Apply      bool   // Write the advice to the workflow frontmatter
+	JSONOutput bool
+	Verbose    bool
+}
+
+// ToolsAdviseResult is the JSON output of tools advise
+type ToolsAdviseResult struct {
+	Workflow string  `json:"workflow"`
+	Runs     []int64 `json:"runs"`
+	Applied  bool    `json:"applied"`
+	*workflow.GitHubToolsetAdvice
+}
+
+// NewToolsCommand creates the tools command with subcommands
+func NewToolsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "Analyze the tools configured in agentic workflows",
+		Long: `Analyze the tools configured in agentic workflows.
+
+Available subcommands:
+  • advise - Propose least-privilege GitHub toolsets and permissions from observed usage`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(newToolsAdviseSubcommand())
+
+	return cmd
+}
+
+// newToolsAdviseSubcommand creates the tools advise subcommand
+func newToolsAdviseSubcommand() *cobra.Command {
+	config := ToolsAdviseConfig{}
+
+	cmd := &cobra.Command{
+		Use:   "advise <workflow>",
+		Short: "Propose least-privilege GitHub toolsets, allowed tools and permissions",
+		Long: `Propose least-privilege GitHub toolsets, allowed tools and permissions for a workflow.
+
+The GitHub MCP tools called in recent runs are compared against the declared
+tools.github toolsets and allowed list and the workflow permissions. The advice is
+the minimum set of toolsets providing the called tools, an allowlist of exactly those
+tools, and the permissions these toolsets require. Permissions unrelated to GitHub
+toolsets are kept; contents: read is always kept for the repository checkout.
+
+Runs are read from the logs directory, so download them first with
+'` + string(constants.CLIExtensionPrefix) + ` logs <workflow>'. Only runs of the workflow are analyzed.
+
+Tools that were never called in the analyzed runs are dropped, so analyze enough
+runs to cover every path of the workflow before applying the advice.
+
+` + WorkflowIDExplanation + `
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` logs issue-triage -c 20             # Download recent runs
+  ` + string(constants.CLIExtensionPrefix) + ` tools advise issue-triage           # Show the advice
+  ` + string(constants.CLIExtensionPrefix) + ` tools advise issue-triage --apply   # Apply it to the frontmatter
+  ` + string(constants.CLIExtensionPrefix) + ` tools advise issue-triage --json    # Output the advice as JSON`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.WorkflowID = args[0]
+			config.JSONOutput, _ = cmd.Flags().GetBool("json")
+			config.Verbose, _ = cmd.Flags().GetBool("verbose")
+			return RunToolsAdvise(config)
+		},
+		ValidArgsFunction: CompleteWorkflowNames,
+	}
+
+	cmd.Flags().StringVar(&config.LogsDir, "logs-dir", defaultLogsOutputDir, "Directory of runs downloaded with the logs command")
+	cmd.Flags().IntVar(&config.Runs, "runs", 10, "Maximum number of recent runs to analyze")
+	cmd.Flags().BoolVar(&config.Apply, "apply", false, "Apply the advice to the workflow frontmatter")
+	addJSONFlag(cmd)
+
+	return cmd
+}
+
+// RunToolsAdvise analyzes the GitHub MCP tool usage of a workflow and proposes the minimum configuration
+func RunToolsAdvise(config ToolsAdviseConfig) error {
+	toolsCommandLog.Printf("Advising tools: workflow=%s, logsDir=%s, runs=%d, apply=%v", config.WorkflowID, config.LogsDir, config.Runs, config.Apply)
+
+	workflowPath, err := ResolveWorkflowPath(config.WorkflowID)
+	if err != nil {
+		return err
+	}
+	if !filepath.IsAbs(workflowPath) {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return fmt.Errorf("failed to get current directory: %w", err)
+		}
+		workflowPath = filepath.Join(cwd, workflowPath)
+	}
+	workflowID := strings.TrimSuffix(filepath.Base(workflowPath), ".md")
+
+	compiler := workflow.NewCompiler(workflow.WithVerbose(config.Verbose))
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	if err != nil {
+		return fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+	if workflowData.ParsedTools == nil || workflowData.ParsedTools.GitHub == nil {
+		return fmt.Errorf("workflow '%s' does not use the GitHub MCP server (tools.github)", workflowID)
+	}
+
+	runs, usedTools, err := collectGitHubToolUsage(config.LogsDir, workflowID, config.Runs, config.Verbose)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return fmt.Errorf("no downloaded runs of workflow '%s' found in %s\nRun '%s logs %s' to download recent runs", workflowID, config.LogsDir, constants.CLIExtensionPrefix, workflowID)
+	}
+
+	advice := workflow.AdviseGitHubToolsets(workflowData, usedTools)
+	result := ToolsAdviseResult{Workflow: workflowID, Runs: runs, GitHubToolsetAdvice: advice}
+
+	if config.Apply && len(advice.UsedTools) > 0 && advice.HasChanges() {
+		if err := applyGitHubToolsetAdvice(workflowPath, advice, config.Verbose); err != nil {
+			return err
+		}
+		result.Applied = true
+	}
+
+	if config.JSONOutput {
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	renderToolsAdvice(result)
+	return nil
+}
+
+// collectGitHubToolUsage returns the most recent downloaded runs of the workflow and the
+// GitHub MCP tools called in them
+func collectGitHubToolUsage(logsDir string, workflowID string, maxRuns int, verbose bool) ([]int64, []string, error) {
+	entries, err := os.ReadDir(logsDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read logs directory: %w", err)
+	}
+
+	type downloadedRun struct {
+		dir     string
+		summary *RunSummary
+	}
+	var runs []downloadedRun
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), "run-") {
+			continue
+		}
+		runDir := filepath.Join(logsDir, entry.Name())
+		data, err := os.ReadFile(filepath.Join(runDir, runSummaryFileName))
+		if err != nil {
+			toolsCommandLog.Printf("Skipping run without summary: %s", runDir)
+			continue
+		}
+		// Summaries of older CLI versions are still valid for attributing runs and tool calls
+		var summary RunSummary
+		if err := json.Unmarshal(data, &summary); err != nil {
+			toolsCommandLog.Printf("Skipping run with invalid summary: %s: %v", runDir, err)
+			continue
+		}
+		lockFile := filepath.Base(summary.Run.WorkflowPath)
+		if strings.TrimSuffix(lockFile, ".lock.yml") != workflowID {
+			continue
+		}
+		runs = append(runs, downloadedRun{dir: runDir, summary: &summary})
+	}
+
+	// Most recent runs first
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].summary.RunID > runs[j].summary.RunID
+	})
+	if maxRuns > 0 && len(runs) > maxRuns {
+		runs = runs[:maxRuns]
+	}
+
+	var runIDs []int64
+	var usedTools []string
+	for _, run := range runs {
+		runIDs = append(runIDs, run.summary.RunID)
+
+		usage := run.summary.MCPToolUsage
+		if usage == nil {
+			usage, err = extractMCPToolUsageData(run.dir, verbose)
+			if err != nil {
+				toolsCommandLog.Printf("No MCP tool usage for run %d: %v", run.summary.RunID, err)
+			}
+		}
+		if usage == nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Run %d has no MCP gateway logs", run.summary.RunID)))
+			}
+			continue
+		}
+
+		for _, tool := range usage.Summary {
+			if tool.ServerName == githubMCPServerName {
+				usedTools = append(usedTools, tool.ToolName)
+			}
+		}
+		for _, call := range usage.ToolCalls {
+			if call.ServerName == githubMCPServerName {
+				usedTools = append(usedTools, call.ToolName)
+			}
+		}
+	}
+
+	toolsCommandLog.Printf("Collected GitHub tool usage: runs=%d, tool_calls=%d", len(runIDs), len(usedTools))
+	return runIDs, usedTools, nil
+}
+
+// applyGitHubToolsetAdvice writes the advised toolsets, allowed tools and permissions to the workflow
+func applyGitHubToolsetAdvice(workflowPath string, advice *workflow.GitHubToolsetAdvice, verbose bool) error {
+	return parser.UpdateWorkflowFrontmatter(workflowPath, func(frontmatter map[string]any) error {
+		tools := parser.EnsureToolsSection(frontmatter)
+		github, ok := tools["github"].(map[string]any)
+		if !ok {
+			github = make(map[string]any)
+			tools["github"] = github
+		}
+		github["toolsets"] = advice.Toolsets
+		github["allowed"] = advice.Allowed
+
+		permissions := make(map[string]any, len(advice.Permissions))
+		for scope, level := range advice.Permissions {
+			permissions[scope] = level
+		}
+		frontmatter["permissions"] = permissions
+		return nil
+	}, verbose)
+}
+
+// renderToolsAdvice prints the advice in human-readable form
+func renderToolsAdvice(result ToolsAdviseResult) {
+	advice := result.GitHubToolsetAdvice
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Analyzed %d run(s) of %s: %d GitHub MCP tool(s) called", len(result.Runs), result.Workflow, len(advice.UsedTools))))
+
+	if len(advice.UsedTools) == 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("No GitHub MCP tool calls were observed; consider removing tools.github if the workflow does not need it"))
+		return
+	}
+	if len(advice.UnknownTools) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Tools missing from the toolset mapping (kept in the allowlist, no toolset added): %s", strings.Join(advice.UnknownTools, ", "))))
+	}
+
+	declaredAllowed := strings.Join(advice.DeclaredAllowed, ", ")
+	if declaredAllowed == "" {
+		declaredAllowed = "(all tools)"
+	}
+	fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+		Title:   "GitHub MCP server",
+		Headers: []string{"Setting", "Declared", "Advised"},
+		Rows: [][]string{
+			{"toolsets", strings.Join(advice.DeclaredToolsets, ", "), strings.Join(advice.Toolsets, ", ")},
+			{"allowed", declaredAllowed, strings.Join(advice.Allowed, ", ")},
+		},
+	}))
+
+	if len(advice.PermissionChanges) > 0 {
+		var rows [][]string
+		for _, change := range advice.PermissionChanges {
+			from, to := change.From, change.To
+			if from == "" {
+				from = "-"
+			}
+			if to == "" {
+				to = "(remove)"
+			}
+			rows = append(rows, []string{change.Scope, from, to})
+		}
+		fmt.Fprint(os.Stderr, console.RenderTable(console.TableConfig{
+			Title:   "Permissions",
+			Headers: []string{"Scope", "Declared", "Advised"},
+			Rows:    rows,
+		}))
+	}
+
+	switch {
+	case result.Applied:
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Applied the advice to %s; run '%s compile %s' to update the lock file", result.Workflow, constants.CLIExtensionPrefix, result.Workflow)))
+	case !advice.HasChanges():
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("The workflow already uses the minimum GitHub toolsets and permissions"))
+	default:
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Run again with --apply to update the workflow frontmatter"))
+	}
+}
@@ -0,0 +1,94 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestRunSummary writes a downloaded run of the workflow with the given GitHub tool calls
+func writeTestRunSummary(t *testing.T, logsDir string, runID int64, workflowID string, githubTools ...string) {
+	t.Helper()
+	summary := RunSummary{
+		RunID: runID,
+		Run:   WorkflowRun{DatabaseID: runID, WorkflowPath: ".github/workflows/" + workflowID + ".lock.yml"},
+		MCPToolUsage: &MCPToolUsageData{
+			Summary: []MCPToolSummary{{ServerName: "safeoutputs", ToolName: "create_issue", CallCount: 1}},
+		},
+	}
+	for _, tool := range githubTools {
+		summary.MCPToolUsage.Summary = append(summary.MCPToolUsage.Summary, MCPToolSummary{ServerName: "github", ToolName: tool, CallCount: 1})
+	}
+	data, err := json.Marshal(summary)
+	require.NoError(t, err)
+	runDir := filepath.Join(logsDir, fmt.Sprintf("run-%d", runID))
+	require.NoError(t, os.MkdirAll(runDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, runSummaryFileName), data, 0644))
+}
+
+func TestCollectGitHubToolUsage(t *testing.T) {
+	logsDir := testutil.TempDir(t, "tools-advise-logs")
+	writeTestRunSummary(t, logsDir, 1, "triage", "list_issues")
+	writeTestRunSummary(t, logsDir, 2, "triage", "issue_read")
+	writeTestRunSummary(t, logsDir, 3, "other", "get_file_contents")
+	writeTestRunSummary(t, logsDir, 4, "triage", "search_issues")
+
+	runs, tools, err := collectGitHubToolUsage(logsDir, "triage", 2, false)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 2}, runs, "most recent runs of the workflow only")
+	assert.ElementsMatch(t, []string{"search_issues", "issue_read"}, tools)
+
+	runs, _, err = collectGitHubToolUsage(filepath.Join(logsDir, "missing"), "triage", 10, false)
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestRunToolsAdvise(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "tools-advise")
+	workflowPath := filepath.Join(tmpDir, "triage.md")
+	content := `---
+on: issues
+permissions:
+  contents: read
+  issues: read
+  pull-requests: read
+tools:
+  github:
+    toolsets: [default]
+---
+
+# Triage
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+	logsDir := filepath.Join(tmpDir, "logs")
+
+	err := RunToolsAdvise(ToolsAdviseConfig{WorkflowID: workflowPath, LogsDir: logsDir, Runs: 10})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logs triage")
+
+	writeTestRunSummary(t, logsDir, 10, "triage", "issue_read", "list_issues")
+	require.NoError(t, RunToolsAdvise(ToolsAdviseConfig{WorkflowID: workflowPath, LogsDir: logsDir, Runs: 10}))
+	unchanged, err := os.ReadFile(workflowPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(unchanged), "advice should not modify the workflow without --apply")
+
+	require.NoError(t, RunToolsAdvise(ToolsAdviseConfig{WorkflowID: workflowPath, LogsDir: logsDir, Runs: 10, Apply: true}))
+	updated, err := os.ReadFile(workflowPath)
+	require.NoError(t, err)
+	result, err := parser.ExtractFrontmatterFromContent(string(updated))
+	require.NoError(t, err)
+
+	github := result.Frontmatter["tools"].(map[string]any)["github"].(map[string]any)
+	assert.Equal(t, []any{"issues"}, github["toolsets"])
+	assert.Equal(t, []any{"issue_read", "list_issues"}, github["allowed"])
+	assert.Equal(t, map[string]any{"contents": "read", "issues": "read"}, result.Frontmatter["permissions"])
+}
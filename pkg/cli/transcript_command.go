@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/cli/fileutil"
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/spf13/cobra"
+)
+
+var transcriptCommandLog = logger.New("cli:transcript_command")
+
+// transcriptFileName is the file the transcript is exported to in the run directory
+const transcriptFileName = "transcript.jsonl"
+
+// transcriptMaxResultLength is the number of tool result characters shown per tool call
+const transcriptMaxResultLength = 2000
+
+// TranscriptConfig holds the options of the transcript command
+type TranscriptConfig struct {
+	Run       string // Run ID, run URL or downloaded run directory
+	OutputDir string // Directory runs are downloaded to
+	Markdown  bool   // Print Markdown instead of opening the viewer
+	JSONL     bool   // Print the transcript as JSON lines
+	Verbose   bool
+}
+
+// NewTranscriptCommand creates the transcript command
+func NewTranscriptCommand() *cobra.Command {
+	config := TranscriptConfig{}
+
+	cmd := &cobra.Command{
+		Use:   "transcript <run-id>",
+		Short: "Show the agent conversation of a workflow run",
+		Long: `Show the agent conversation of a workflow run turn by turn.
+
+The engine log of the run (Claude, Codex or Copilot) is parsed into a normalized
+transcript: the messages of each turn, the tools called with their arguments and
+results, and the token usage. The transcript is exported to transcript.jsonl in the
+run directory, one turn per line.
+
+This command accepts:
+- A numeric run ID (e.g., 1234567890)
+- A GitHub Actions run URL (e.g., https://github.com/owner/repo/actions/runs/1234567890)
+- A directory of a run downloaded by the logs or audit commands
+
+Artifacts are downloaded when the run is not already in the output directory.
+In a terminal the transcript opens in an interactive viewer; otherwise it is
+printed as Markdown.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` transcript 1234567890                            # Open the transcript viewer
+  ` + string(constants.CLIExtensionPrefix) + ` transcript 1234567890 --markdown > transcript.md # Save as Markdown
+  ` + string(constants.CLIExtensionPrefix) + ` transcript 1234567890 --jsonl                    # Print one JSON line per turn
+  ` + string(constants.CLIExtensionPrefix) + ` transcript .github/aw/logs/run-1234567890        # Use a downloaded run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config.Run = args[0]
+			config.OutputDir, _ = cmd.Flags().GetString("output")
+			config.Verbose, _ = cmd.Flags().GetBool("verbose")
+			if config.Markdown && config.JSONL {
+				return fmt.Errorf("--markdown and --jsonl cannot be used together")
+			}
+			return RunTranscript(config)
+		},
+	}
+
+	addOutputFlag(cmd, defaultLogsOutputDir)
+	cmd.Flags().BoolVar(&config.Markdown, "markdown", false, "Print the transcript as Markdown instead of opening the viewer")
+	cmd.Flags().BoolVar(&config.JSONL, "jsonl", false, "Print the transcript as JSON lines, one turn per line")
+	RegisterDirFlagCompletion(cmd, "output")
+
+	return cmd
+}
+
+// RunTranscript builds the transcript of a run, exports it and displays it
+func RunTranscript(config TranscriptConfig) error {
+	transcriptCommandLog.Printf("Showing transcript: run=%s, outputDir=%s", config.Run, config.OutputDir)
+
+	runDir, err := resolveTranscriptRunDir(config.Run, config.OutputDir, config.Verbose)
+	if err != nil {
+		return err
+	}
+
+	transcript, err := buildRunTranscript(runDir, config.Verbose)
+	if err != nil {
+		return err
+	}
+
+	var jsonl bytes.Buffer
+	if err := workflow.WriteTranscriptJSONL(&jsonl, transcript); err != nil {
+		return err
+	}
+	transcriptPath := filepath.Join(runDir, transcriptFileName)
+	if err := os.WriteFile(transcriptPath, jsonl.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", transcriptPath, err)
+	}
+	if config.Verbose {
+		fmt.Fprintln(os.Stderr, console.FormatVerboseMessage("Exported transcript to "+transcriptPath))
+	}
+
+	if config.JSONL {
+		_, err := os.Stdout.Write(jsonl.Bytes())
+		return err
+	}
+
+	markdown := workflow.RenderTranscriptMarkdown(transcript, transcriptMaxResultLength)
+	if config.Markdown {
+		fmt.Fprint(os.Stdout, markdown)
+		return nil
+	}
+
+	return console.ShowPager(console.PagerConfig{
+		Title:    fmt.Sprintf("Transcript of %s (%s, %d turns)", filepath.Base(runDir), transcript.Engine, len(transcript.Turns)),
+		Content:  markdown,
+		Sections: transcriptSectionLines(markdown),
+	})
+}
+
+// resolveTranscriptRunDir returns the directory of a downloaded run, downloading its
+// artifacts when the run is given by ID or URL and is not cached yet
+func resolveTranscriptRunDir(run, outputDir string, verbose bool) (string, error) {
+	if fileutil.DirExists(run) {
+		return run, nil
+	}
+
+	runID, _, _, _, err := parser.ParseRunURL(run)
+	if err != nil {
+		return "", err
+	}
+
+	runDir := filepath.Join(outputDir, fmt.Sprintf("run-%d", runID))
+	if fileutil.DirExists(runDir) && !fileutil.IsDirEmpty(runDir) {
+		transcriptCommandLog.Printf("Using cached run directory: %s", runDir)
+		return runDir, nil
+	}
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Downloading artifacts for run %d...", runID)))
+	}
+	if err := downloadRunArtifacts(runID, runDir, verbose); err != nil {
+		return "", fmt.Errorf("failed to download artifacts: %w", err)
+	}
+	return runDir, nil
+}
+
+// buildRunTranscript detects the engine of a downloaded run and parses its agent log
+func buildRunTranscript(runDir string, verbose bool) (*workflow.Transcript, error) {
+	engine := extractEngineFromAwInfo(filepath.Join(runDir, "aw_info.json"), verbose)
+	if engine == nil {
+		return nil, fmt.Errorf("could not detect the engine of the run in %s: aw_info.json is missing or invalid", runDir)
+	}
+
+	transcriptParser, ok := engine.(workflow.TranscriptParser)
+	if !ok {
+		return nil, fmt.Errorf("engine '%s' does not support transcripts", engine.GetID())
+	}
+
+	logFile, found := findAgentLogFile(runDir, engine)
+	if !found {
+		return nil, fmt.Errorf("no agent log found in %s", runDir)
+	}
+	transcriptCommandLog.Printf("Parsing %s log: %s", engine.GetID(), logFile)
+
+	content, err := os.ReadFile(logFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent log: %w", err)
+	}
+
+	return transcriptParser.ParseTranscript(string(content), verbose), nil
+}
+
+// transcriptSectionLines returns the line numbers of the turn headers in the Markdown transcript
+func transcriptSectionLines(markdown string) []int {
+	var sections []int
+	for i, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(line, "## Turn ") {
+			sections = append(sections, i)
+		}
+	}
+	return sections
+}
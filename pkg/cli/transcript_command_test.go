@@ -0,0 +1,57 @@
+//go:build !integration
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunTranscript(t *testing.T) {
+	runDir := filepath.Join(testutil.TempDir(t, "transcript"), "run-123")
+	require.NoError(t, os.MkdirAll(runDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "aw_info.json"), []byte(`{"engine_id":"claude"}`), 0644))
+	agentLog := `{"type":"assistant","message":{"id":"msg_1","content":[{"type":"tool_use","id":"toolu_1","name":"Bash","input":{"command":"ls"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":"README.md"}]}}
+{"type":"assistant","message":{"id":"msg_2","content":[{"type":"text","text":"Done."}]}}
+{"type":"result","num_turns":2,"usage":{"input_tokens":10,"output_tokens":5}}`
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "agent-stdio.log"), []byte(agentLog), 0644))
+
+	require.NoError(t, RunTranscript(TranscriptConfig{Run: runDir, Markdown: true}))
+
+	file, err := os.Open(filepath.Join(runDir, transcriptFileName))
+	require.NoError(t, err)
+	defer file.Close()
+	transcript, err := workflow.ReadTranscriptJSONL(file, "claude")
+	require.NoError(t, err)
+	require.Len(t, transcript.Turns, 2)
+	assert.Equal(t, "README.md", transcript.Turns[0].ToolCalls[0].Result)
+	assert.Equal(t, "Done.", transcript.Turns[1].Messages[0].Text)
+}
+
+func TestRunTranscriptErrors(t *testing.T) {
+	runDir := testutil.TempDir(t, "transcript-errors")
+
+	err := RunTranscript(TranscriptConfig{Run: runDir, Markdown: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "could not detect the engine")
+
+	require.NoError(t, os.WriteFile(filepath.Join(runDir, "aw_info.json"), []byte(`{"engine_id":"codex"}`), 0644))
+	err = RunTranscript(TranscriptConfig{Run: runDir, Markdown: true})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no agent log found")
+
+	err = RunTranscript(TranscriptConfig{Run: "not-a-run", Markdown: true})
+	assert.Error(t, err)
+}
+
+func TestTranscriptSectionLines(t *testing.T) {
+	markdown := "# Transcript\n\n## Turn 1\n\ntext\n\n## Turn 2\n"
+	assert.Equal(t, []int{2, 6}, transcriptSectionLines(markdown))
+}
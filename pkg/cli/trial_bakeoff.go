@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/repoutil"
+	"github.com/github/gh-aw/pkg/timeutil"
+)
+
+var trialBakeoffLog = logger.New("cli:trial_bakeoff")
+
+// TrialVariant is one engine and model combination a workflow is trialled with.
+// Empty fields keep the engine and model of the workflow.
+type TrialVariant struct {
+	Engine string `json:"engine,omitempty"`
+	Model  string `json:"model,omitempty"`
+}
+
+// String returns a human-readable label of the variant
+func (v TrialVariant) String() string {
+	engine := v.Engine
+	if engine == "" {
+		engine = "workflow engine"
+	}
+	if v.Model == "" {
+		return engine
+	}
+	return fmt.Sprintf("%s (%s)", engine, v.Model)
+}
+
+// filenameSuffix returns the suffix of the result files of the variant
+func (v TrialVariant) filenameSuffix() string {
+	parts := []string{}
+	for _, part := range []string{v.Engine, v.Model} {
+		if part != "" {
+			parts = append(parts, repoutil.SanitizeForFilename(part))
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// buildTrialVariants returns the engine and model combinations of an engine bake-off.
+// Every engine is combined with every model; without --engines the engine override
+// (or the workflow engine) is used, and without --models the default model of each engine.
+func buildTrialVariants(engines, models []string, engineOverride string) []TrialVariant {
+	if len(engines) == 0 {
+		engines = []string{engineOverride}
+	}
+	if len(models) == 0 {
+		models = []string{""}
+	}
+
+	var variants []TrialVariant
+	for _, engine := range engines {
+		for _, model := range models {
+			variants = append(variants, TrialVariant{Engine: strings.TrimSpace(engine), Model: strings.TrimSpace(model)})
+		}
+	}
+
+	trialBakeoffLog.Printf("Built %d trial variants from engines=%v, models=%v", len(variants), engines, models)
+	return variants
+}
+
+// applyTrialRunInfo fills the engine and model of a trial result from aw_info.json,
+// which records what actually ran when the variant keeps the workflow defaults
+func applyTrialRunInfo(result *WorkflowTrialResult) {
+	if engine, ok := result.AgenticRunInfo["engine_id"].(string); ok && engine != "" {
+		result.Engine = engine
+	}
+	if model, ok := result.AgenticRunInfo["model"].(string); ok && model != "" {
+		result.Model = model
+	}
+}
+
+// countSafeOutputTypes counts the safe output items of a trial by type
+func countSafeOutputTypes(safeOutputs map[string]any) map[string]int {
+	counts := make(map[string]int)
+	items, _ := safeOutputs["items"].([]any)
+	for _, item := range items {
+		itemMap, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if outputType, ok := itemMap["type"].(string); ok && outputType != "" {
+			counts[outputType]++
+		}
+	}
+	return counts
+}
+
+// formatSafeOutputCounts formats safe output counts as "type×count" sorted by type
+func formatSafeOutputCounts(counts map[string]int) string {
+	if len(counts) == 0 {
+		return "-"
+	}
+	var parts []string
+	for _, outputType := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%s×%d", outputType, counts[outputType]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// renderTrialComparison renders a side-by-side comparison of the trial results of an engine bake-off
+func renderTrialComparison(results []WorkflowTrialResult) string {
+	config := console.TableConfig{
+		Title:   "Engine Comparison",
+		Headers: []string{"Workflow", "Engine", "Model", "Result", "Duration", "Tokens", "Cost ($)", "Turns", "Safe Outputs"},
+	}
+
+	for _, result := range results {
+		conclusion := result.Conclusion
+		if conclusion == "" {
+			conclusion = "-"
+		}
+		duration := "-"
+		if result.DurationSeconds > 0 {
+			duration = timeutil.FormatDuration(time.Duration(result.DurationSeconds * float64(time.Second)))
+		}
+		model := result.Model
+		if model == "" {
+			model = "-"
+		}
+		turns := "-"
+		if result.Turns > 0 {
+			turns = fmt.Sprintf("%d", result.Turns)
+		}
+		config.Rows = append(config.Rows, []string{
+			result.WorkflowName,
+			result.Engine,
+			model,
+			conclusion,
+			duration,
+			formatTokens(result.TokenUsage),
+			formatCost(result.EstimatedCost),
+			turns,
+			formatSafeOutputCounts(countSafeOutputTypes(result.SafeOutputs)),
+		})
+	}
+
+	return console.RenderTable(config)
+}
+
+// applyTrialRunMetadata fills the conclusion and duration of a trial result from the workflow run
+func applyTrialRunMetadata(result *WorkflowTrialResult, hostRepoSlug string, verbose bool) {
+	runID, err := strconv.ParseInt(result.RunID, 10, 64)
+	if err != nil {
+		return
+	}
+	owner, repo, _ := strings.Cut(hostRepoSlug, "/")
+	run, err := fetchWorkflowRunMetadata(runID, owner, repo, "", verbose)
+	if err != nil {
+		trialBakeoffLog.Printf("Failed to fetch metadata of run %d: %v", runID, err)
+		return
+	}
+	result.Conclusion = run.Conclusion
+	if !run.StartedAt.IsZero() && run.UpdatedAt.After(run.StartedAt) {
+		result.DurationSeconds = run.UpdatedAt.Sub(run.StartedAt).Seconds()
+	}
+}
@@ -0,0 +1,125 @@
+//go:build !integration
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTrialVariants(t *testing.T) {
+	tests := []struct {
+		name           string
+		engines        []string
+		models         []string
+		engineOverride string
+		expected       []TrialVariant
+	}{
+		{
+			name:     "no bake-off",
+			expected: []TrialVariant{{}},
+		},
+		{
+			name:           "engine override only",
+			engineOverride: "claude",
+			expected:       []TrialVariant{{Engine: "claude"}},
+		},
+		{
+			name:     "engines",
+			engines:  []string{"claude", " codex"},
+			expected: []TrialVariant{{Engine: "claude"}, {Engine: "codex"}},
+		},
+		{
+			name:           "models of the engine override",
+			models:         []string{"gpt-5", "gpt-5-mini"},
+			engineOverride: "copilot",
+			expected:       []TrialVariant{{Engine: "copilot", Model: "gpt-5"}, {Engine: "copilot", Model: "gpt-5-mini"}},
+		},
+		{
+			name:    "engines and models",
+			engines: []string{"claude", "copilot"},
+			models:  []string{"a", "b"},
+			expected: []TrialVariant{
+				{Engine: "claude", Model: "a"},
+				{Engine: "claude", Model: "b"},
+				{Engine: "copilot", Model: "a"},
+				{Engine: "copilot", Model: "b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildTrialVariants(tt.engines, tt.models, tt.engineOverride))
+		})
+	}
+}
+
+func TestTrialVariantLabels(t *testing.T) {
+	assert.Equal(t, "workflow engine", TrialVariant{}.String())
+	assert.Equal(t, "claude", TrialVariant{Engine: "claude"}.String())
+	assert.Equal(t, "copilot (gpt-5)", TrialVariant{Engine: "copilot", Model: "gpt-5"}.String())
+
+	assert.Equal(t, "claude", TrialVariant{Engine: "claude"}.filenameSuffix())
+	assert.Equal(t, "copilot-gpt-5", TrialVariant{Engine: "copilot", Model: "gpt-5"}.filenameSuffix())
+}
+
+func TestApplyTrialRunInfo(t *testing.T) {
+	result := WorkflowTrialResult{
+		AgenticRunInfo: map[string]any{"engine_id": "copilot", "model": "gpt-5"},
+	}
+	applyTrialRunInfo(&result)
+	assert.Equal(t, "copilot", result.Engine)
+	assert.Equal(t, "gpt-5", result.Model)
+
+	result = WorkflowTrialResult{Engine: "claude", Model: "claude-sonnet-4"}
+	applyTrialRunInfo(&result)
+	assert.Equal(t, "claude", result.Engine, "the variant should be kept without run info")
+	assert.Equal(t, "claude-sonnet-4", result.Model)
+}
+
+func TestSafeOutputCounts(t *testing.T) {
+	safeOutputs := map[string]any{
+		"items": []any{
+			map[string]any{"type": "create_issue"},
+			map[string]any{"type": "add_comment"},
+			map[string]any{"type": "create_issue"},
+			"not an item",
+		},
+	}
+
+	counts := countSafeOutputTypes(safeOutputs)
+	assert.Equal(t, map[string]int{"create_issue": 2, "add_comment": 1}, counts)
+	assert.Equal(t, "add_comment×1, create_issue×2", formatSafeOutputCounts(counts))
+	assert.Equal(t, "-", formatSafeOutputCounts(countSafeOutputTypes(nil)))
+}
+
+func TestRenderTrialComparison(t *testing.T) {
+	results := []WorkflowTrialResult{
+		{
+			WorkflowName:    "triage",
+			Engine:          "claude",
+			Model:           "claude-sonnet-4",
+			Conclusion:      "success",
+			DurationSeconds: 125,
+			TokenUsage:      15000,
+			EstimatedCost:   0.42,
+			Turns:           7,
+			SafeOutputs:     map[string]any{"items": []any{map[string]any{"type": "add_labels"}}},
+		},
+		{
+			WorkflowName: "triage",
+			Engine:       "codex",
+			Conclusion:   "failure",
+		},
+	}
+
+	output := renderTrialComparison(results)
+	assert.Contains(t, output, "Engine Comparison")
+	assert.Contains(t, output, "claude-sonnet-4")
+	assert.Contains(t, output, "codex")
+	assert.Contains(t, output, "success")
+	assert.Contains(t, output, "failure")
+	assert.Contains(t, output, "add_labels×1")
+}
@@ -30,6 +30,13 @@ type WorkflowTrialResult struct {
 	//AgentStdioLogs      []string               `json:"agent_stdio_logs,omitempty"`
 	AgenticRunInfo      map[string]any `json:"agentic_run_info,omitempty"`
 	AdditionalArtifacts map[string]any `json:"additional_artifacts,omitempty"`
+	Engine              string         `json:"engine,omitempty"`           // Engine that ran the workflow
+	Model               string         `json:"model,omitempty"`            // Model the engine used, if known
+	Conclusion          string         `json:"conclusion,omitempty"`       // Conclusion of the workflow run (success, failure, ...)
+	DurationSeconds     float64        `json:"duration_seconds,omitempty"` // Duration of the workflow run
+	TokenUsage          int            `json:"token_usage,omitempty"`      // Tokens used by the agent, from the agent logs
+	EstimatedCost       float64        `json:"estimated_cost,omitempty"`   // Estimated cost of the agent run, from the agent logs
+	Turns               int            `json:"turns,omitempty"`            // Number of agent turns, from the agent logs
 	Timestamp           time.Time      `json:"timestamp"`
 }
 
@@ -59,10 +66,15 @@ type TrialOptions struct {
 	RepeatCount            int
 	AutoMergePRs           bool
 	EngineOverride         string
+	ModelOverride          string   // Override the model of the workflow engine
+	Engines                []string // Engines of an engine bake-off (each runs the workflow)
+	Models                 []string // Models of an engine bake-off (each runs the workflow with every engine)
 	AppendText             string
 	PushSecrets            bool
 	Verbose                bool
 	DisableSecurityScanner bool
+	MCPRecord              bool   // Record MCP traffic through the gateway and save the cassette locally
+	MCPReplay              string // Replay MCP traffic from this cassette instead of contacting MCP servers
 }
 
 // NewTrialCommand creates the trial command
@@ -98,11 +110,17 @@ Repeat and cleanup examples:
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --quiet --host-repo my-trial # Custom host repo
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --dry-run                 # Show what would be done without changes
 
+Engine bake-off examples:
+  ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --engines claude,codex,copilot  # Run once per engine and compare
+  ` + string(constants.CLIExtensionPrefix) + ` trial ./my-workflow.md --engine copilot --models gpt-5,claude-sonnet-4.5  # Compare models of one engine
+
 Auto-merge examples:
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --auto-merge-prs          # Auto-merge any PRs created during trial
 
 Advanced examples:
   ` + string(constants.CLIExtensionPrefix) + ` trial githubnext/agentics/my-workflow --host-repo . # Use current repo as host
+  ` + string(constants.CLIExtensionPrefix) + ` trial ./my-workflow.md --mcp-record                        # Save the MCP traffic as a cassette
+  ` + string(constants.CLIExtensionPrefix) + ` trial ./my-workflow.md --mcp-replay trials/my-workflow.cassette.jsonl # Replay MCP responses
   ` + string(constants.CLIExtensionPrefix) + ` trial ./local-workflow.md --clone-repo upstream/repo --repeat 2
 
 Repository modes:
@@ -134,14 +152,34 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 			pushSecrets, _ := cmd.Flags().GetBool("use-local-secrets")
 			verbose, _ := cmd.Root().PersistentFlags().GetBool("verbose")
 			disableSecurityScanner, _ := cmd.Flags().GetBool("disable-security-scanner")
+			mcpRecord, _ := cmd.Flags().GetBool("mcp-record")
+			mcpReplay, _ := cmd.Flags().GetString("mcp-replay")
+			engines, _ := cmd.Flags().GetStringSlice("engines")
+			models, _ := cmd.Flags().GetStringSlice("models")
 
 			if err := validateEngine(engineOverride); err != nil {
 				return err
 			}
+			for _, engine := range engines {
+				if err := validateEngine(strings.TrimSpace(engine)); err != nil {
+					return err
+				}
+			}
 			// If --repo was used instead of --host-repo, use its value
 			if repoSpec != "" {
 				hostRepoSpec = repoSpec
 			}
+			// Resolve the cassette before the trial changes to the host repository directory
+			if mcpReplay != "" {
+				absReplay, err := filepath.Abs(mcpReplay)
+				if err != nil {
+					return fmt.Errorf("invalid --mcp-replay path: %w", err)
+				}
+				if _, err := os.Stat(absReplay); err != nil {
+					return fmt.Errorf("MCP cassette not found: %s", mcpReplay)
+				}
+				mcpReplay = absReplay
+			}
 
 			opts := TrialOptions{
 				Repos: RepoConfig{
@@ -158,10 +196,14 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 				RepeatCount:            repeatCount,
 				AutoMergePRs:           autoMergePRs,
 				EngineOverride:         engineOverride,
+				Engines:                engines,
+				Models:                 models,
 				AppendText:             appendText,
 				PushSecrets:            pushSecrets,
 				Verbose:                verbose,
 				DisableSecurityScanner: disableSecurityScanner,
+				MCPRecord:              mcpRecord,
+				MCPReplay:              mcpReplay,
 			}
 
 			if err := RunWorkflowTrials(cmd.Context(), workflowSpecs, opts); err != nil {
@@ -193,11 +235,17 @@ Trial results are saved both locally (in trials/ directory) and in the host repo
 	cmd.Flags().Int("repeat", 0, "Number of times to repeat running workflows (0 = run once)")
 	cmd.Flags().Bool("auto-merge-prs", false, "Auto-merge any pull requests created during trial execution")
 	addEngineFlag(cmd)
+	cmd.Flags().StringSlice("engines", nil, "Run the workflow once per engine and compare the results (e.g. claude,codex,copilot)")
+	cmd.Flags().StringSlice("models", nil, "Run the workflow once per model (with each engine of --engines) and compare the results")
 	cmd.Flags().String("append", "", "Append extra content to the end of agentic workflow on installation")
 	cmd.Flags().Bool("use-local-secrets", false, "Use local environment API key secrets for trial execution (pushes and cleans up secrets in repository)")
 	cmd.Flags().Bool("disable-security-scanner", false, "Disable security scanning of workflow markdown content")
+	cmd.Flags().Bool("mcp-record", false, "Record MCP gateway traffic and save the cassette to the trials/ directory")
+	cmd.Flags().String("mcp-replay", "", "Replay MCP gateway traffic from a recorded cassette (JSONL) instead of contacting MCP servers")
 	cmd.MarkFlagsMutuallyExclusive("host-repo", "repo")
+	cmd.MarkFlagsMutuallyExclusive("mcp-record", "mcp-replay")
 	cmd.MarkFlagsMutuallyExclusive("logical-repo", "clone-repo")
+	cmd.MarkFlagsMutuallyExclusive("engine", "engines")
 
 	return cmd
 }
@@ -307,6 +355,18 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Host repository (default): %s", hostRepoSlug)))
 	}
 
+	// Engine and model combinations to run each workflow with
+	variants := buildTrialVariants(opts.Engines, opts.Models, opts.EngineOverride)
+	bakeoff := len(variants) > 1
+
+	if bakeoff {
+		labels := make([]string, len(variants))
+		for i, variant := range variants {
+			labels[i] = variant.String()
+		}
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Engine bake-off: each workflow runs %d times (%s)", len(variants), strings.Join(labels, ", "))))
+	}
+
 	// Step 1.5: Show confirmation unless quiet mode
 	if !opts.Quiet {
 		if err := showTrialConfirmation(parsedSpecs, logicalRepoSlug, cloneRepoSlug, hostRepoSlug, opts.DeleteHostRepo, opts.ForceDelete, opts.PushSecrets, opts.AutoMergePRs, opts.RepeatCount, directTrialMode); err != nil {
@@ -439,110 +499,156 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 
 		// Step 5: Run trials for each workflow
 		var workflowResults []WorkflowTrialResult
+		var resultNames []string
 
 		for i, parsedSpec := range parsedSpecs {
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Running trial for workflow: %s ===", parsedSpec.WorkflowName)))
+			for j, variant := range variants {
+				if bakeoff {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Running trial for workflow: %s with %s ===", parsedSpec.WorkflowName, variant)))
+				} else {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Running trial for workflow: %s ===", parsedSpec.WorkflowName)))
+				}
 
-			// Install workflow with trial mode compilation
-			if err := installWorkflowInTrialMode(ctx, tempDir, parsedSpec, logicalRepoSlug, cloneRepoSlug, hostRepoSlug, secretTracker, opts.EngineOverride, opts.AppendText, opts.PushSecrets, directTrialMode, opts.Verbose, &opts); err != nil {
-				return fmt.Errorf("failed to install workflow '%s' in trial mode: %w", parsedSpec.WorkflowName, err)
-			}
+				// Compile the workflow with the engine and model of the variant
+				variantOpts := opts
+				variantOpts.EngineOverride = variant.Engine
+				variantOpts.ModelOverride = variant.Model
 
-			// Display workflow description if present
-			workflowPath := filepath.Join(tempDir, ".github/workflows", parsedSpec.WorkflowName+".md")
-			if description := ExtractWorkflowDescriptionFromFile(workflowPath); description != "" {
-				fmt.Fprintln(os.Stderr, "")
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(description))
-				fmt.Fprintln(os.Stderr, "")
-			}
+				// Install workflow with trial mode compilation
+				if err := installWorkflowInTrialMode(ctx, tempDir, parsedSpec, logicalRepoSlug, cloneRepoSlug, hostRepoSlug, secretTracker, variantOpts.EngineOverride, opts.AppendText, opts.PushSecrets, directTrialMode, opts.Verbose, &variantOpts); err != nil {
+					return fmt.Errorf("failed to install workflow '%s' in trial mode: %w", parsedSpec.WorkflowName, err)
+				}
 
-			// Add user's PAT as repository secret (only once)
-			if i == 0 && opts.PushSecrets {
-				if err := addGitHubTokenSecret(hostRepoSlug, secretTracker, opts.Verbose); err != nil {
-					return fmt.Errorf("failed to add GitHub token secret: %w", err)
+				// Display workflow description if present
+				workflowPath := filepath.Join(tempDir, ".github/workflows", parsedSpec.WorkflowName+".md")
+				if description := ExtractWorkflowDescriptionFromFile(workflowPath); description != "" {
+					fmt.Fprintln(os.Stderr, "")
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(description))
+					fmt.Fprintln(os.Stderr, "")
 				}
-			}
 
-			// Run the workflow and wait for completion (with trigger context if provided)
-			runID, err := triggerWorkflowRun(hostRepoSlug, parsedSpec.WorkflowName, opts.TriggerContext, opts.Verbose)
-			if err != nil {
-				return fmt.Errorf("failed to trigger workflow run for '%s': %w", parsedSpec.WorkflowName, err)
-			}
+				// Add user's PAT as repository secret (only once)
+				if i == 0 && j == 0 && opts.PushSecrets {
+					if err := addGitHubTokenSecret(hostRepoSlug, secretTracker, opts.Verbose); err != nil {
+						return fmt.Errorf("failed to add GitHub token secret: %w", err)
+					}
+				}
 
-			// Generate workflow run URL
-			workflowRunURL := fmt.Sprintf("https://github.com/%s/actions/runs/%s", hostRepoSlug, runID)
-			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Workflow run started with ID: %s (%s)", runID, workflowRunURL)))
+				// Run the workflow and wait for completion (with trigger context if provided)
+				runID, err := triggerWorkflowRun(hostRepoSlug, parsedSpec.WorkflowName, opts.TriggerContext, opts.Verbose)
+				if err != nil {
+					return fmt.Errorf("failed to trigger workflow run for '%s': %w", parsedSpec.WorkflowName, err)
+				}
 
-			// Wait for workflow completion
-			if err := WaitForWorkflowCompletion(hostRepoSlug, runID, opts.TimeoutMinutes, opts.Verbose); err != nil {
-				return fmt.Errorf("workflow '%s' execution failed or timed out: %w", parsedSpec.WorkflowName, err)
-			}
+				// Generate workflow run URL
+				workflowRunURL := fmt.Sprintf("https://github.com/%s/actions/runs/%s", hostRepoSlug, runID)
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Workflow run started with ID: %s (%s)", runID, workflowRunURL)))
+
+				// Wait for workflow completion
+				if err := WaitForWorkflowCompletion(hostRepoSlug, runID, opts.TimeoutMinutes, opts.Verbose); err != nil {
+					// A failing variant is a result of the bake-off, so keep comparing the others
+					if !bakeoff {
+						return fmt.Errorf("workflow '%s' execution failed or timed out: %w", parsedSpec.WorkflowName, err)
+					}
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Workflow '%s' with %s failed or timed out: %v", parsedSpec.WorkflowName, variant, err)))
+				}
 
-			// Auto-merge PRs if requested
-			if opts.AutoMergePRs {
-				if err := AutoMergePullRequestsLegacy(hostRepoSlug, opts.Verbose); err != nil {
-					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to auto-merge pull requests: %v", err)))
+				// Auto-merge PRs if requested
+				if opts.AutoMergePRs {
+					if err := AutoMergePullRequestsLegacy(hostRepoSlug, opts.Verbose); err != nil {
+						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to auto-merge pull requests: %v", err)))
+					}
 				}
-			}
 
-			// Download and process all artifacts
-			artifacts, err := downloadAllArtifacts(hostRepoSlug, runID, opts.Verbose)
-			if err != nil {
-				return fmt.Errorf("failed to download artifacts for '%s': %w", parsedSpec.WorkflowName, err)
-			}
+				// Download and process all artifacts
+				artifacts, err := downloadAllArtifacts(hostRepoSlug, runID, opts.Verbose)
+				if err != nil {
+					return fmt.Errorf("failed to download artifacts for '%s': %w", parsedSpec.WorkflowName, err)
+				}
 
-			// Save individual workflow results
-			result := WorkflowTrialResult{
-				WorkflowName: parsedSpec.WorkflowName,
-				RunID:        runID,
-				SafeOutputs:  artifacts.SafeOutputs,
-				//AgentStdioLogs:      artifacts.AgentStdioLogs,
-				AgenticRunInfo:      artifacts.AgenticRunInfo,
-				AdditionalArtifacts: artifacts.AdditionalArtifacts,
-				Timestamp:           time.Now(),
-			}
-			workflowResults = append(workflowResults, result)
+				// Save individual workflow results
+				result := WorkflowTrialResult{
+					WorkflowName: parsedSpec.WorkflowName,
+					RunID:        runID,
+					SafeOutputs:  artifacts.SafeOutputs,
+					//AgentStdioLogs:      artifacts.AgentStdioLogs,
+					AgenticRunInfo:      artifacts.AgenticRunInfo,
+					AdditionalArtifacts: artifacts.AdditionalArtifacts,
+					Timestamp:           time.Now(),
+					Engine:              variant.Engine,
+					Model:               variant.Model,
+					TokenUsage:          artifacts.Metrics.TokenUsage,
+					EstimatedCost:       artifacts.Metrics.EstimatedCost,
+					Turns:               artifacts.Metrics.Turns,
+				}
+				applyTrialRunInfo(&result)
+				applyTrialRunMetadata(&result, hostRepoSlug, opts.Verbose)
+
+				// Save individual trial file
+				sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
+				resultName := parsedSpec.WorkflowName
+				if bakeoff {
+					resultName += "-" + variant.filenameSuffix()
+				}
+				individualFilename := fmt.Sprintf("trials/%s-%s.%s.json", resultName, sanitizedTargetRepo, dateTimeID)
+				workflowResults = append(workflowResults, result)
+				resultNames = append(resultNames, resultName)
+				if err := saveTrialResult(individualFilename, result, opts.Verbose); err != nil {
+					fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save individual trial result: %v", err)))
+				}
 
-			// Save individual trial file
-			sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
-			individualFilename := fmt.Sprintf("trials/%s-%s.%s.json", parsedSpec.WorkflowName, sanitizedTargetRepo, dateTimeID)
-			if err := saveTrialResult(individualFilename, result, opts.Verbose); err != nil {
-				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save individual trial result: %v", err)))
-			}
+				// Display safe outputs to stdout
+				if len(artifacts.SafeOutputs) > 0 {
+					outputBytes, _ := json.MarshalIndent(artifacts.SafeOutputs, "", "  ")
+					fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("=== Safe Outputs from %s ===", parsedSpec.WorkflowName)))
+					fmt.Println(string(outputBytes))
+					fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("=== End of Safe Outputs ==="))
+				} else {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== No Safe Outputs Generated by %s ===", parsedSpec.WorkflowName)))
+				}
 
-			// Display safe outputs to stdout
-			if len(artifacts.SafeOutputs) > 0 {
-				outputBytes, _ := json.MarshalIndent(artifacts.SafeOutputs, "", "  ")
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("=== Safe Outputs from %s ===", parsedSpec.WorkflowName)))
-				fmt.Println(string(outputBytes))
-				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage("=== End of Safe Outputs ==="))
-			} else {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== No Safe Outputs Generated by %s ===", parsedSpec.WorkflowName)))
-			}
+				// Display additional artifact information if available
+				// if len(artifacts.AgentStdioLogs) > 0 {
+				// 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agent Stdio Logs Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AgentStdioLogs))))
+				// }
+				if len(artifacts.AgenticRunInfo) > 0 {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agentic Run Information Available from %s ===", parsedSpec.WorkflowName)))
+				}
+				if len(artifacts.AdditionalArtifacts) > 0 {
+					fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Additional Artifacts Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AdditionalArtifacts))))
+				}
 
-			// Display additional artifact information if available
-			// if len(artifacts.AgentStdioLogs) > 0 {
-			// 	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agent Stdio Logs Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AgentStdioLogs))))
-			// }
-			if len(artifacts.AgenticRunInfo) > 0 {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Agentic Run Information Available from %s ===", parsedSpec.WorkflowName)))
-			}
-			if len(artifacts.AdditionalArtifacts) > 0 {
-				fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("=== Additional Artifacts Available from %s (%d files) ===", parsedSpec.WorkflowName, len(artifacts.AdditionalArtifacts))))
-			}
+				// Save the recorded MCP cassette so the run can be replayed with --mcp-replay
+				if opts.MCPRecord {
+					if artifacts.MCPCassette == "" {
+						fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("No MCP cassette was recorded by %s", parsedSpec.WorkflowName)))
+					} else {
+						cassetteFilename := fmt.Sprintf("trials/%s-%s.%s.cassette.jsonl", resultName, sanitizedTargetRepo, dateTimeID)
+						if err := os.WriteFile(cassetteFilename, []byte(artifacts.MCPCassette), 0644); err != nil {
+							fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to save MCP cassette: %v", err)))
+						} else {
+							fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("MCP cassette saved to: %s (replay with --mcp-replay %s)", cassetteFilename, cassetteFilename)))
+						}
+					}
+				}
 
-			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Trial completed for workflow: %s", parsedSpec.WorkflowName)))
+				fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Trial completed for workflow: %s", parsedSpec.WorkflowName)))
+			}
 		}
 
-		// Step 6: Save combined results for multi-workflow trials
-		if len(parsedSpecs) > 1 {
+		// Step 6: Save combined results for multi-workflow trials and engine bake-offs
+		var combinedName string
+		if len(workflowResults) > 1 {
 			workflowNames := make([]string, len(parsedSpecs))
 			for i, spec := range parsedSpecs {
 				workflowNames[i] = spec.WorkflowName
 			}
-			workflowNamesStr := strings.Join(workflowNames, "-")
+			combinedName = strings.Join(workflowNames, "-")
+			if bakeoff {
+				combinedName += "-bakeoff"
+			}
 			sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoForFilename)
-			combinedFilename := fmt.Sprintf("trials/%s-%s.%s.json", workflowNamesStr, sanitizedTargetRepo, dateTimeID)
+			combinedFilename := fmt.Sprintf("trials/%s-%s.%s.json", combinedName, sanitizedTargetRepo, dateTimeID)
 			combinedResult := CombinedTrialResult{
 				WorkflowNames: workflowNames,
 				Results:       workflowResults,
@@ -554,12 +660,13 @@ func RunWorkflowTrials(ctx context.Context, workflowSpecs []string, opts TrialOp
 			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Combined results saved to: %s", combinedFilename)))
 		}
 
-		// Step 6.5: Copy trial results to host repository and commit them
-		workflowNames := make([]string, len(parsedSpecs))
-		for i, spec := range parsedSpecs {
-			workflowNames[i] = spec.WorkflowName
+		// Step 6.2: Compare the variants of an engine bake-off side by side
+		if bakeoff {
+			fmt.Fprint(os.Stderr, renderTrialComparison(workflowResults))
 		}
-		if err := copyTrialResultsToHostRepo(tempDir, dateTimeID, workflowNames, targetRepoForFilename, opts.Verbose); err != nil {
+
+		// Step 6.5: Copy trial results to host repository and commit them
+		if err := copyTrialResultsToHostRepo(tempDir, dateTimeID, resultNames, combinedName, targetRepoForFilename, opts.Verbose); err != nil {
 			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to copy trial results to repository: %v", err)))
 		}
 
@@ -916,7 +1023,9 @@ func saveTrialResult(filename string, result any, verbose bool) error {
 }
 
 // copyTrialResultsToHostRepo copies trial result files to the host repository and commits them
-func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []string, targetRepoSlug string, verbose bool) error {
+// resultNames are the names of the individual result files and combinedName the name of the
+// combined results file, or empty when there is none
+func copyTrialResultsToHostRepo(tempDir, dateTimeID string, resultNames []string, combinedName string, targetRepoSlug string, verbose bool) error {
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Copying trial results to host repository"))
 	}
@@ -929,9 +1038,9 @@ func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []stri
 
 	// Copy individual workflow result files
 	sanitizedTargetRepo := repoutil.SanitizeForFilename(targetRepoSlug)
-	for _, workflowName := range workflowNames {
-		sourceFile := fmt.Sprintf("trials/%s-%s.%s.json", workflowName, sanitizedTargetRepo, dateTimeID)
-		destFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", workflowName, sanitizedTargetRepo, dateTimeID))
+	for _, resultName := range resultNames {
+		sourceFile := fmt.Sprintf("trials/%s-%s.%s.json", resultName, sanitizedTargetRepo, dateTimeID)
+		destFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", resultName, sanitizedTargetRepo, dateTimeID))
 
 		if err := fileutil.CopyFile(sourceFile, destFile); err != nil {
 			if verbose {
@@ -945,11 +1054,10 @@ func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []stri
 		}
 	}
 
-	// Copy combined results file if it exists (for multi-workflow trials)
-	if len(workflowNames) > 1 {
-		workflowNamesStr := strings.Join(workflowNames, "-")
-		combinedSourceFile := fmt.Sprintf("trials/%s-%s.%s.json", workflowNamesStr, sanitizedTargetRepo, dateTimeID)
-		combinedDestFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", workflowNamesStr, sanitizedTargetRepo, dateTimeID))
+	// Copy combined results file if it exists (for multi-workflow trials and engine bake-offs)
+	if combinedName != "" {
+		combinedSourceFile := fmt.Sprintf("trials/%s-%s.%s.json", combinedName, sanitizedTargetRepo, dateTimeID)
+		combinedDestFile := filepath.Join(trialsDir, fmt.Sprintf("%s-%s.%s.json", combinedName, sanitizedTargetRepo, dateTimeID))
 
 		if err := fileutil.CopyFile(combinedSourceFile, combinedDestFile); err != nil {
 			if verbose {
@@ -993,7 +1101,7 @@ func copyTrialResultsToHostRepo(tempDir, dateTimeID string, workflowNames []stri
 	}
 
 	// Commit trial results
-	commitMsg := fmt.Sprintf("Add trial results for %s (%s)", strings.Join(workflowNames, ", "), dateTimeID)
+	commitMsg := fmt.Sprintf("Add trial results for %s (%s)", strings.Join(resultNames, ", "), dateTimeID)
 	cmd = exec.Command("git", "commit", "-m", commitMsg)
 	if output, err := cmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("failed to commit trial results: %w (output: %s)", err, string(output))
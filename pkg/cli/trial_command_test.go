@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/github/gh-aw/pkg/workflow"
 )
 
 // Test the host repo slug processing logic with dot notation
@@ -605,3 +606,39 @@ func TestTrialModeValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestPrepareTrialMCPCassette(t *testing.T) {
+	tempDir := testutil.TempDir(t, "trial-mcp-cassette-*")
+
+	cassette, err := prepareTrialMCPCassette(tempDir, "triage", &TrialOptions{})
+	if err != nil || cassette != nil {
+		t.Fatalf("Expected no cassette without --mcp-record or --mcp-replay, got %v (err: %v)", cassette, err)
+	}
+
+	cassette, err = prepareTrialMCPCassette(tempDir, "triage", &TrialOptions{MCPRecord: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cassette.Mode != workflow.MCPCassetteModeRecord || cassette.Path != "" {
+		t.Errorf("Expected record mode without path, got %+v", cassette)
+	}
+
+	replayFile := filepath.Join(tempDir, "recorded.jsonl")
+	if err := os.WriteFile(replayFile, []byte(`{"server":"github"}`+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write cassette: %v", err)
+	}
+	cassette, err = prepareTrialMCPCassette(tempDir, "triage", &TrialOptions{MCPReplay: replayFile})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cassette.Mode != workflow.MCPCassetteModeReplay || cassette.Path != ".github/mcp-cassettes/triage.jsonl" {
+		t.Errorf("Expected replay of .github/mcp-cassettes/triage.jsonl, got %+v", cassette)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, ".github", "mcp-cassettes", "triage.jsonl"))
+	if err != nil {
+		t.Fatalf("Expected cassette to be copied into the host repository: %v", err)
+	}
+	if !strings.Contains(string(content), `"server":"github"`) {
+		t.Errorf("Copied cassette has unexpected content: %s", content)
+	}
+}
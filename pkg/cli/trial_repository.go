@@ -266,6 +266,12 @@ func installWorkflowInTrialMode(ctx context.Context, tempDir string, parsedSpec
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Direct trial mode: Skipping trial mode modifications"))
 	}
 
+	// Record or replay MCP traffic through the gateway if requested
+	mcpCassette, err := prepareTrialMCPCassette(tempDir, parsedSpec.WorkflowName, opts)
+	if err != nil {
+		return err
+	}
+
 	// Compile the workflow with trial modifications
 	config := CompileConfig{
 		MarkdownFiles:        []string{".github/workflows/" + parsedSpec.WorkflowName + ".md"},
@@ -279,6 +285,10 @@ func installWorkflowInTrialMode(ctx context.Context, tempDir string, parsedSpec
 		Purge:                false,
 		TrialMode:            !directTrialMode && (cloneRepoSlug == ""), // Enable trial mode in compiler unless in direct mode or clone-repo mode
 		TrialLogicalRepoSlug: logicalRepoSlug,
+		MCPCassette:          mcpCassette,
+	}
+	if opts != nil {
+		config.ModelOverride = opts.ModelOverride
 	}
 	workflowDataList, err := CompileWorkflows(ctx, config)
 	if err != nil {
@@ -304,6 +314,44 @@ func installWorkflowInTrialMode(ctx context.Context, tempDir string, parsedSpec
 	return nil
 }
 
+// prepareTrialMCPCassette returns the MCP cassette override for the trial run.
+// In replay mode the cassette is copied into the host repository so the gateway can read it
+// from the workspace; in record mode the gateway writes the cassette and uploads it as an artifact.
+func prepareTrialMCPCassette(tempDir, workflowName string, opts *TrialOptions) (*workflow.MCPCassetteConfig, error) {
+	if opts == nil {
+		return nil, nil
+	}
+
+	if opts.MCPRecord {
+		trialRepoLog.Printf("Recording MCP traffic for workflow: %s", workflowName)
+		return &workflow.MCPCassetteConfig{Mode: workflow.MCPCassetteModeRecord}, nil
+	}
+
+	if opts.MCPReplay == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(opts.MCPReplay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP cassette: %w", err)
+	}
+
+	relPath := filepath.ToSlash(filepath.Join(".github", "mcp-cassettes", workflowName+".jsonl"))
+	destPath := filepath.Join(tempDir, filepath.FromSlash(relPath))
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create MCP cassette directory: %w", err)
+	}
+	if err := os.WriteFile(destPath, content, 0644); err != nil {
+		return nil, fmt.Errorf("failed to copy MCP cassette: %w", err)
+	}
+
+	trialRepoLog.Printf("Replaying MCP cassette %s from %s", opts.MCPReplay, relPath)
+	if opts.Verbose {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Replaying MCP traffic from %s", opts.MCPReplay)))
+	}
+	return &workflow.MCPCassetteConfig{Mode: workflow.MCPCassetteModeReplay, Path: relPath}, nil
+}
+
 // installLocalWorkflowInTrialMode installs a local workflow file for trial mode
 func installLocalWorkflowInTrialMode(originalDir, tempDir string, parsedSpec *WorkflowSpec, appendText string, verbose bool, opts *TrialOptions) error {
 	// Construct the source path (relative to original directory)
@@ -92,11 +92,20 @@ func determineAndAddEngineSecret(engineConfig *workflow.EngineConfig, hostRepoSl
 			return fmt.Errorf("neither CLAUDE_CODE_OAUTH_TOKEN nor ANTHROPIC_API_KEY environment variable is set")
 		}
 		return nil
-	case "codex", "openai":
+	case "codex":
 		if verbose {
 			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Setting OPENAI_API_KEY secret for OpenAI engine"))
 		}
 		return addEngineSecret("OPENAI_API_KEY", hostRepoSlug, tracker, verbose)
+	case "openai":
+		// The API key of OpenAI-compatible endpoints is optional (self-hosted models often have none)
+		if os.Getenv("OPENAI_COMPATIBLE_API_KEY") == "" {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatInfoMessage("OPENAI_COMPATIBLE_API_KEY is not set, the endpoint is called without an API key"))
+			}
+			return nil
+		}
+		return addEngineSecret("OPENAI_COMPATIBLE_API_KEY", hostRepoSlug, tracker, verbose)
 	case "copilot":
 		if verbose {
 			fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Setting COPILOT_GITHUB_TOKEN secret for Copilot engine"))
@@ -269,6 +278,8 @@ type TrialArtifacts struct {
 	//AgentStdioLogs      []string               `json:"agent_stdio_logs,omitempty"`
 	AgenticRunInfo      map[string]any `json:"agentic_run_info,omitempty"`
 	AdditionalArtifacts map[string]any `json:"additional_artifacts,omitempty"`
+	MCPCassette         string         `json:"-"` // Raw JSONL of the recorded MCP cassette (mcp-cassette artifact)
+	Metrics             LogMetrics     `json:"-"` // Token usage, cost and turns extracted from the agent logs
 }
 
 // downloadAllArtifacts downloads and parses all available artifacts from a workflow run
@@ -328,6 +339,12 @@ func downloadAllArtifacts(hostRepoSlug, runID string, verbose bool) (*TrialArtif
 				artifacts.SafeOutputs = safeOutputs
 			}
 
+		case strings.HasSuffix(relPath, filepath.Join(constants.MCPCassetteArtifactName, constants.MCPCassetteFilename)):
+			// Keep the recorded MCP cassette as-is (JSON Lines)
+			if content := readTextArtifact(path, verbose); content != "" {
+				artifacts.MCPCassette = content
+			}
+
 		case strings.HasSuffix(path, "aw_info.json"):
 			// Parse agentic run information
 			if runInfo := parseJSONArtifact(path, verbose); runInfo != nil {
@@ -362,6 +379,22 @@ func downloadAllArtifacts(hostRepoSlug, runID string, verbose bool) (*TrialArtif
 		}
 	}
 
+	// Extract token usage, cost and turns the same way the logs command does
+	if err := flattenSingleFileArtifacts(tempDir, verbose); err != nil && verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to flatten artifacts: %v", err)))
+	}
+	if err := flattenUnifiedArtifact(tempDir, verbose); err != nil && verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to flatten unified artifact: %v", err)))
+	}
+	if err := flattenAgentOutputsArtifact(tempDir, verbose); err != nil && verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to flatten agent outputs artifact: %v", err)))
+	}
+	if metrics, err := extractLogMetrics(tempDir, verbose); err == nil {
+		artifacts.Metrics = metrics
+	} else if verbose {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to extract log metrics: %v", err)))
+	}
+
 	return artifacts, nil
 }
 
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/github/gh-aw/pkg/console"
 	"github.com/github/gh-aw/pkg/gitutil"
@@ -27,10 +28,34 @@ func extractBaseRepo(actionPath string) string {
 	return actionPath
 }
 
+// UpdateActionsConfig holds the options of the action pin update
+type UpdateActionsConfig struct {
+	AllowMajor      bool // Allow major version updates
+	Report          bool // Show old/new pins, reachability, tag rewrites and changelog excerpts
+	CooldownDays    int  // Only accept releases published at least this many days ago (0 disables the cooldown)
+	AcceptMovedTags bool // Re-pin tags that now resolve to a different SHA than the recorded pin
+	DryRun          bool // Report updates without writing actions-lock.json
+	JSONOutput      bool // Output the report as JSON
+	Verbose         bool
+}
+
+// latestActionReleaseFunc resolves the latest compatible release of an action (overridable in tests)
+var latestActionReleaseFunc = getLatestActionRelease
+
 // UpdateActions updates GitHub Actions versions in .github/aw/actions-lock.json
 // It checks each action for newer releases and updates the SHA if a newer version is found
 func UpdateActions(allowMajor, verbose bool) error {
-	updateLog.Print("Starting action updates")
+	return RunUpdateActions(UpdateActionsConfig{AllowMajor: allowMajor, Verbose: verbose})
+}
+
+// RunUpdateActions updates the pinned actions in .github/aw/actions-lock.json according to config
+func RunUpdateActions(config UpdateActionsConfig) error {
+	return runUpdateActions(config, githubActionUpstream{}, time.Now())
+}
+
+func runUpdateActions(config UpdateActionsConfig, upstream actionUpstream, now time.Time) error {
+	updateLog.Printf("Starting action updates: report=%v, cooldown=%d, dryRun=%v", config.Report, config.CooldownDays, config.DryRun)
+	verbose := config.Verbose
 
 	if verbose {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Checking for GitHub Actions updates..."))
@@ -44,6 +69,9 @@ func UpdateActions(allowMajor, verbose bool) error {
 		if verbose {
 			fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("Actions lock file not found: %s", actionsLockPath)))
 		}
+		if config.JSONOutput {
+			fmt.Println("[]")
+		}
 		return nil // Not an error, just skip
 	}
 
@@ -64,23 +92,55 @@ func UpdateActions(allowMajor, verbose bool) error {
 	var updatedActions []string
 	var failedActions []string
 	var skippedActions []string
+	var cooldownActions []string
+	var movedActions []string
+	var report []ActionUpdateReportEntry
+
+	// Process the actions in a stable order so reports are deterministic
+	keys := make([]string, 0, len(actionsLock.Entries))
+	for key := range actionsLock.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
 	// Update each action
-	for key, entry := range actionsLock.Entries {
+	for _, key := range keys {
+		entry := actionsLock.Entries[key]
 		updateLog.Printf("Checking action: %s@%s", entry.Repo, entry.Version)
 
 		// Check for latest release
-		latestVersion, latestSHA, err := getLatestActionRelease(entry.Repo, entry.Version, allowMajor, verbose)
+		latestVersion, latestSHA, err := latestActionReleaseFunc(entry.Repo, entry.Version, config.AllowMajor, verbose)
 		if err != nil {
 			if verbose {
 				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Failed to check %s: %v", entry.Repo, err)))
 			}
 			failedActions = append(failedActions, entry.Repo)
+			report = append(report, ActionUpdateReportEntry{
+				Repo:       entry.Repo,
+				OldVersion: entry.Version,
+				OldSHA:     entry.SHA,
+				Status:     actionUpdateStatusFailed,
+				Error:      err.Error(),
+			})
 			continue
 		}
 
+		reportEntry, update := inspectActionUpdate(upstream, entry, latestVersion, latestSHA, config, now)
+		report = append(report, reportEntry)
+
 		// Check if update is available
-		if latestVersion == entry.Version && latestSHA == entry.SHA {
+		if !update {
+			if reportEntry.Status == actionUpdateStatusCooldown {
+				if verbose {
+					fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("%s@%s is within the %d day cooldown", entry.Repo, latestVersion, config.CooldownDays)))
+				}
+				cooldownActions = append(cooldownActions, entry.Repo)
+				continue
+			}
+			if reportEntry.Status == actionUpdateStatusTagMoved {
+				movedActions = append(movedActions, entry.Repo+"@"+entry.Version)
+				continue
+			}
 			if verbose {
 				fmt.Fprintln(os.Stderr, console.FormatVerboseMessage(fmt.Sprintf("%s@%s is up to date", entry.Repo, entry.Version)))
 			}
@@ -89,8 +149,12 @@ func UpdateActions(allowMajor, verbose bool) error {
 		}
 
 		// Update the entry
-		updateLog.Printf("Updating %s from %s (%s) to %s (%s)", entry.Repo, entry.Version, entry.SHA[:7], latestVersion, latestSHA[:7])
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Updated %s from %s to %s", entry.Repo, entry.Version, latestVersion)))
+		updateLog.Printf("Updating %s from %s (%s) to %s (%s)", entry.Repo, entry.Version, shortSHA(entry.SHA), latestVersion, shortSHA(latestSHA))
+		if config.DryRun {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Would update %s from %s to %s", entry.Repo, entry.Version, latestVersion)))
+		} else {
+			fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Updated %s from %s to %s", entry.Repo, entry.Version, latestVersion)))
+		}
 
 		// Delete the old key (which has the old version)
 		delete(actionsLock.Entries, key)
@@ -106,17 +170,55 @@ func UpdateActions(allowMajor, verbose bool) error {
 		updatedActions = append(updatedActions, entry.Repo)
 	}
 
+	if config.JSONOutput {
+		if report == nil {
+			report = []ActionUpdateReportEntry{}
+		}
+		jsonBytes, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	} else if config.Report {
+		renderActionUpdateReport(report)
+	} else {
+		// Tag rewrites are always worth surfacing, even without --report
+		for _, entry := range report {
+			if entry.TagMoved {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s@%s was moved: pinned %s, tag now points to %s", entry.Repo, entry.OldVersion, entry.OldSHA, entry.CurrentSHA)))
+			}
+		}
+	}
+
 	// Show summary
 	fmt.Fprintln(os.Stderr, "")
 
 	if len(updatedActions) > 0 {
-		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Updated %d action(s):", len(updatedActions))))
+		verb := "Updated"
+		if config.DryRun {
+			verb = "Would update"
+		}
+		fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("%s %d action(s):", verb, len(updatedActions))))
 		for _, action := range updatedActions {
 			fmt.Fprintln(os.Stderr, console.FormatListItem(action))
 		}
 		fmt.Fprintln(os.Stderr, "")
 	}
 
+	if len(cooldownActions) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%d action update(s) held back by the %d day cooldown", len(cooldownActions), config.CooldownDays)))
+		fmt.Fprintln(os.Stderr, "")
+	}
+
+	if len(movedActions) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%d moved tag(s) kept at the pinned SHA:", len(movedActions))))
+		for _, action := range movedActions {
+			fmt.Fprintln(os.Stderr, console.FormatListItem(action))
+		}
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Review the tag history, then rerun with --accept-moved-tags to re-pin them"))
+		fmt.Fprintln(os.Stderr, "")
+	}
+
 	if len(skippedActions) > 0 && verbose {
 		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%d action(s) already up to date", len(skippedActions))))
 		fmt.Fprintln(os.Stderr, "")
@@ -131,7 +233,7 @@ func UpdateActions(allowMajor, verbose bool) error {
 	}
 
 	// Save the updated actions lock file if there were any updates
-	if len(updatedActions) > 0 {
+	if len(updatedActions) > 0 && !config.DryRun {
 		// Marshal with sorted keys and pretty printing
 		updatedData, err := marshalActionsLockSorted(&actionsLock)
 		if err != nil {
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/spf13/cobra"
+)
+
+// NewUpdateActionsCommand creates the update-actions command
+func NewUpdateActionsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-actions",
+		Short: "Update the pinned GitHub Actions in .github/aw/actions-lock.json",
+		Long: `Update the GitHub Actions pinned in .github/aw/actions-lock.json to their latest releases.
+
+Each action is resolved to the latest release within its current major version
+(use --major to allow major version updates) and pinned to the release commit SHA.
+
+With --report, a table is shown for every action with:
+- the old and new version and SHA
+- whether the new SHA descends from the previously pinned SHA
+- whether the currently pinned tag was moved (the tag now resolves to a different
+  SHA than the one recorded in actions-lock.json), which can indicate a tag rewrite
+- an excerpt of the release notes
+
+Moved tags are always reported as warnings, with or without --report, and are kept
+at the pinned SHA. Use --accept-moved-tags to re-pin them to the SHA the tag now
+resolves to, after reviewing the tag history.
+
+Use --cooldown to only accept releases published at least N days ago. Newer releases
+are held back and the current pin is kept until the cooldown has passed.
+
+Examples:
+  ` + string(constants.CLIExtensionPrefix) + ` update-actions                        # Update all pinned actions
+  ` + string(constants.CLIExtensionPrefix) + ` update-actions --report               # Show a detailed update report
+  ` + string(constants.CLIExtensionPrefix) + ` update-actions --cooldown 7           # Only accept releases older than 7 days
+  ` + string(constants.CLIExtensionPrefix) + ` update-actions --report --dry-run     # Review updates without changing actions-lock.json
+  ` + string(constants.CLIExtensionPrefix) + ` update-actions --accept-moved-tags    # Re-pin tags that were moved upstream
+  ` + string(constants.CLIExtensionPrefix) + ` update-actions --major --json         # Allow major updates and output JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			major, _ := cmd.Flags().GetBool("major")
+			report, _ := cmd.Flags().GetBool("report")
+			cooldown, _ := cmd.Flags().GetInt("cooldown")
+			acceptMovedTags, _ := cmd.Flags().GetBool("accept-moved-tags")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			jsonOutput, _ := cmd.Flags().GetBool("json")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+
+			if cooldown < 0 {
+				return fmt.Errorf("--cooldown must be a non-negative number of days, got %d", cooldown)
+			}
+
+			return RunUpdateActions(UpdateActionsConfig{
+				AllowMajor:      major,
+				Report:          report,
+				CooldownDays:    cooldown,
+				AcceptMovedTags: acceptMovedTags,
+				DryRun:          dryRun,
+				JSONOutput:      jsonOutput,
+				Verbose:         verbose,
+			})
+		},
+	}
+
+	cmd.Flags().Bool("major", false, "Allow major version updates")
+	cmd.Flags().Bool("report", false, "Show old/new versions and SHAs, reachability, moved tags and release notes")
+	cmd.Flags().Int("cooldown", 0, "Only accept releases published at least this many days ago")
+	cmd.Flags().Bool("accept-moved-tags", false, "Re-pin moved tags to the SHA they now resolve to")
+	cmd.Flags().Bool("dry-run", false, "Show what would be updated without changing actions-lock.json")
+	addJSONFlag(cmd)
+
+	return cmd
+}
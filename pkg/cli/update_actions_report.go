@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+var updateActionsReportLog = logger.New("cli:update_actions_report")
+
+// Action update statuses reported by update-actions --report
+const (
+	actionUpdateStatusUpdated  = "updated"
+	actionUpdateStatusCurrent  = "up-to-date"
+	actionUpdateStatusCooldown = "cooldown"
+	actionUpdateStatusTagMoved = "tag-moved"
+	actionUpdateStatusFailed   = "failed"
+)
+
+// changelogExcerptMaxLines bounds the release notes shown per action
+const changelogExcerptMaxLines = 8
+
+// ActionUpdateReportEntry describes what changed (or would change) for one pinned action
+type ActionUpdateReportEntry struct {
+	Repo       string `json:"repo" console:"header:Action"`
+	OldVersion string `json:"old_version" console:"header:Old Version"`
+	OldSHA     string `json:"old_sha" console:"-"`
+	NewVersion string `json:"new_version,omitempty" console:"header:New Version"`
+	NewSHA     string `json:"new_sha,omitempty" console:"-"`
+	Status     string `json:"status" console:"header:Status"`
+	Reachable  string `json:"reachable,omitempty" console:"header:Reachable"` // yes, no or unknown: new SHA descends from the pinned SHA
+	TagMoved   bool   `json:"tag_moved" console:"header:Tag Moved"`           // the old tag now resolves to a different SHA than the cached pin
+	CurrentSHA string `json:"current_tag_sha,omitempty" console:"-"`          // SHA the old tag resolves to today (when moved)
+	ReleasedAt string `json:"released_at,omitempty" console:"header:Released"`
+	Changelog  string `json:"changelog,omitempty" console:"-"`
+	Error      string `json:"error,omitempty" console:"-"`
+}
+
+// actionReleaseInfo is the release metadata of a tag
+type actionReleaseInfo struct {
+	PublishedAt time.Time
+	Body        string
+}
+
+// actionUpstream resolves tags and release metadata of action repositories
+type actionUpstream interface {
+	ResolveTag(repo, tag string) (string, error)
+	Release(repo, tag string) (actionReleaseInfo, error)
+	IsReachable(repo, fromSHA, sha string) (bool, error)
+}
+
+// githubActionUpstream resolves action metadata through the GitHub API (gh CLI)
+type githubActionUpstream struct{}
+
+// ResolveTag returns the SHA the tag currently points to
+func (githubActionUpstream) ResolveTag(repo, tag string) (string, error) {
+	return getActionSHAForTag(repo, tag)
+}
+
+// Release returns the publication date and notes of the release for the tag.
+// Falls back to the tagged commit date when the tag has no release.
+func (githubActionUpstream) Release(repo, tag string) (actionReleaseInfo, error) {
+	output, err := workflow.RunGH("Fetching release notes...", "api", fmt.Sprintf("/repos/%s/releases/tags/%s", repo, tag), "--jq", "{published_at: .published_at, body: .body}")
+	if err == nil {
+		var release struct {
+			PublishedAt time.Time `json:"published_at"`
+			Body        string    `json:"body"`
+		}
+		if err := json.Unmarshal(output, &release); err == nil {
+			return actionReleaseInfo{PublishedAt: release.PublishedAt, Body: release.Body}, nil
+		}
+	}
+
+	updateActionsReportLog.Printf("No release found for %s@%s, using commit date", repo, tag)
+	output, err = workflow.RunGH("Fetching commit date...", "api", fmt.Sprintf("/repos/%s/commits/%s", repo, tag), "--jq", ".commit.committer.date")
+	if err != nil {
+		return actionReleaseInfo{}, fmt.Errorf("failed to fetch release date: %w", err)
+	}
+	publishedAt, err := time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+	if err != nil {
+		return actionReleaseInfo{}, fmt.Errorf("invalid commit date: %w", err)
+	}
+	return actionReleaseInfo{PublishedAt: publishedAt}, nil
+}
+
+// IsReachable reports whether sha is fromSHA or one of its descendants, i.e. whether the
+// history of the previously pinned commit leads to sha
+func (githubActionUpstream) IsReachable(repo, fromSHA, sha string) (bool, error) {
+	output, err := workflow.RunGH("Verifying commit...", "api", fmt.Sprintf("/repos/%s/compare/%s...%s", repo, fromSHA, sha), "--jq", ".status")
+	if err != nil {
+		return false, fmt.Errorf("failed to compare %s with %s: %w", fromSHA, sha, err)
+	}
+	return isReachableCompareStatus(strings.TrimSpace(string(output))), nil
+}
+
+// isReachableCompareStatus interprets the status of a compare from a base to a head commit:
+// the head is reachable when it is the same commit or ahead of the base
+func isReachableCompareStatus(status string) bool {
+	return status == "identical" || status == "ahead"
+}
+
+// inspectActionUpdate builds the report entry for an action whose latest compatible release
+// is latestVersion@latestSHA, applying the cooldown. Moved tags (the pinned version now
+// resolving to a different SHA) are held back unless config.AcceptMovedTags is set.
+// It reports whether the pin may be updated.
+func inspectActionUpdate(upstream actionUpstream, entry actionsLockEntry, latestVersion, latestSHA string, config UpdateActionsConfig, now time.Time) (ActionUpdateReportEntry, bool) {
+	baseRepo := extractBaseRepo(entry.Repo)
+	result := ActionUpdateReportEntry{
+		Repo:       entry.Repo,
+		OldVersion: entry.Version,
+		OldSHA:     entry.SHA,
+		NewVersion: latestVersion,
+		NewSHA:     latestSHA,
+		Status:     actionUpdateStatusCurrent,
+	}
+
+	// Tag-rewrite detection: the cached pin no longer matches the tag it was resolved from
+	if latestVersion == entry.Version && latestSHA != entry.SHA {
+		result.TagMoved = true
+		result.CurrentSHA = latestSHA
+	} else if config.Report {
+		if currentSHA, err := upstream.ResolveTag(baseRepo, entry.Version); err == nil && currentSHA != entry.SHA {
+			result.TagMoved = true
+			result.CurrentSHA = currentSHA
+		}
+	}
+
+	if latestVersion == entry.Version && latestSHA == entry.SHA {
+		return result, false
+	}
+
+	if config.Report {
+		// A legitimate release builds on the pinned commit; a rewritten tag often does not
+		result.Reachable = "unknown"
+		if reachable, err := upstream.IsReachable(baseRepo, entry.SHA, latestSHA); err == nil {
+			result.Reachable = map[bool]string{true: "yes", false: "no"}[reachable]
+		} else {
+			updateActionsReportLog.Printf("Could not verify %s@%s: %v", entry.Repo, latestSHA, err)
+		}
+	}
+
+	// Re-pinning a moved tag would silently accept the rewritten commit
+	if latestVersion == entry.Version && !config.AcceptMovedTags {
+		updateActionsReportLog.Printf("Holding back moved tag %s@%s", entry.Repo, entry.Version)
+		result.Status = actionUpdateStatusTagMoved
+		return result, false
+	}
+
+	var release actionReleaseInfo
+	var releaseErr error
+	if config.Report || config.CooldownDays > 0 {
+		release, releaseErr = upstream.Release(baseRepo, latestVersion)
+		if releaseErr == nil {
+			result.ReleasedAt = release.PublishedAt.UTC().Format("2006-01-02")
+			result.Changelog = changelogExcerpt(release.Body, changelogExcerptMaxLines)
+		}
+	}
+
+	if config.CooldownDays > 0 {
+		if releaseErr != nil {
+			// Without a release date the cooldown cannot be verified, so the pin is kept
+			result.Status = actionUpdateStatusCooldown
+			result.Error = releaseErr.Error()
+			return result, false
+		}
+		if now.Sub(release.PublishedAt) < time.Duration(config.CooldownDays)*24*time.Hour {
+			result.Status = actionUpdateStatusCooldown
+			return result, false
+		}
+	}
+
+	result.Status = actionUpdateStatusUpdated
+	return result, true
+}
+
+// changelogExcerpt returns the first non-empty lines of release notes
+func changelogExcerpt(body string, maxLines int) string {
+	var lines []string
+	for line := range strings.SplitSeq(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if len(lines) == maxLines {
+			lines = append(lines, "...")
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderActionUpdateReport prints the action update report to stderr
+func renderActionUpdateReport(entries []ActionUpdateReportEntry) {
+	if len(entries) == 0 {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprint(os.Stderr, console.RenderStruct(entries))
+
+	for _, entry := range entries {
+		if entry.TagMoved {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s@%s was moved: pinned %s, tag now points to %s", entry.Repo, entry.OldVersion, entry.OldSHA, entry.CurrentSHA)))
+		}
+		if entry.Reachable == "no" {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("%s@%s: %s does not descend from the pinned %s", entry.Repo, entry.NewVersion, entry.NewSHA, entry.OldSHA)))
+		}
+	}
+
+	for _, entry := range entries {
+		if entry.Status == actionUpdateStatusCurrent || entry.Status == actionUpdateStatusFailed || entry.Status == actionUpdateStatusTagMoved {
+			continue
+		}
+		fmt.Fprintln(os.Stderr, "")
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("%s %s (%s) -> %s (%s)", entry.Repo, entry.OldVersion, shortSHA(entry.OldSHA), entry.NewVersion, shortSHA(entry.NewSHA))))
+		if entry.Changelog != "" {
+			for line := range strings.SplitSeq(entry.Changelog, "\n") {
+				fmt.Fprintf(os.Stderr, "    %s\n", line)
+			}
+		}
+	}
+	fmt.Fprintln(os.Stderr, "")
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
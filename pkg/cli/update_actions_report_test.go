@@ -0,0 +1,179 @@
+//go:build !integration
+
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCheckoutOldSHA   = "1111111111111111111111111111111111111111"
+	testCheckoutNewSHA   = "2222222222222222222222222222222222222222"
+	testCheckoutMovedSHA = "3333333333333333333333333333333333333333"
+)
+
+// fakeActionUpstream serves tag, release and reachability data from memory
+type fakeActionUpstream struct {
+	tags        map[string]string
+	releases    map[string]actionReleaseInfo
+	unreachable map[string]bool
+}
+
+func (f *fakeActionUpstream) ResolveTag(repo, tag string) (string, error) {
+	sha, ok := f.tags[repo+"@"+tag]
+	if !ok {
+		return "", errors.New("tag not found")
+	}
+	return sha, nil
+}
+
+func (f *fakeActionUpstream) Release(repo, tag string) (actionReleaseInfo, error) {
+	release, ok := f.releases[repo+"@"+tag]
+	if !ok {
+		return actionReleaseInfo{}, errors.New("release not found")
+	}
+	return release, nil
+}
+
+func (f *fakeActionUpstream) IsReachable(repo, fromSHA, sha string) (bool, error) {
+	return !f.unreachable[fromSHA+".."+sha], nil
+}
+
+func TestInspectActionUpdate(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	entry := actionsLockEntry{Repo: "actions/checkout", Version: "v5.0.0", SHA: testCheckoutOldSHA}
+	upstream := &fakeActionUpstream{
+		tags: map[string]string{"actions/checkout@v5.0.0": testCheckoutOldSHA},
+		releases: map[string]actionReleaseInfo{
+			"actions/checkout@v5.1.0": {PublishedAt: now.Add(-3 * 24 * time.Hour), Body: "## What's Changed\r\n\r\n* Faster clones\r\n"},
+		},
+	}
+
+	t.Run("update with report", func(t *testing.T) {
+		result, update := inspectActionUpdate(upstream, entry, "v5.1.0", testCheckoutNewSHA, UpdateActionsConfig{Report: true}, now)
+		assert.True(t, update)
+		assert.Equal(t, actionUpdateStatusUpdated, result.Status)
+		assert.Equal(t, "yes", result.Reachable)
+		assert.False(t, result.TagMoved)
+		assert.Equal(t, "2026-03-07", result.ReleasedAt)
+		assert.Equal(t, "## What's Changed\n* Faster clones", result.Changelog)
+	})
+
+	t.Run("cooldown holds back recent release", func(t *testing.T) {
+		result, update := inspectActionUpdate(upstream, entry, "v5.1.0", testCheckoutNewSHA, UpdateActionsConfig{CooldownDays: 7}, now)
+		assert.False(t, update)
+		assert.Equal(t, actionUpdateStatusCooldown, result.Status)
+
+		result, update = inspectActionUpdate(upstream, entry, "v5.1.0", testCheckoutNewSHA, UpdateActionsConfig{CooldownDays: 2}, now)
+		assert.True(t, update, "releases older than the cooldown should be accepted")
+		assert.Equal(t, actionUpdateStatusUpdated, result.Status)
+	})
+
+	t.Run("cooldown without release date keeps pin", func(t *testing.T) {
+		result, update := inspectActionUpdate(upstream, entry, "v5.2.0", testCheckoutNewSHA, UpdateActionsConfig{CooldownDays: 7}, now)
+		assert.False(t, update)
+		assert.Equal(t, actionUpdateStatusCooldown, result.Status)
+		assert.NotEmpty(t, result.Error)
+	})
+
+	t.Run("moved tag is held back", func(t *testing.T) {
+		movedUpstream := &fakeActionUpstream{
+			tags:        map[string]string{"actions/checkout@v5.0.0": testCheckoutMovedSHA},
+			unreachable: map[string]bool{testCheckoutOldSHA + ".." + testCheckoutMovedSHA: true},
+		}
+		result, update := inspectActionUpdate(movedUpstream, entry, "v5.0.0", testCheckoutMovedSHA, UpdateActionsConfig{Report: true}, now)
+		assert.False(t, update, "moved tags should not be re-pinned without --accept-moved-tags")
+		assert.Equal(t, actionUpdateStatusTagMoved, result.Status)
+		assert.True(t, result.TagMoved)
+		assert.Equal(t, testCheckoutMovedSHA, result.CurrentSHA)
+		assert.Equal(t, "no", result.Reachable, "rewritten commit does not descend from the pinned SHA")
+
+		result, update = inspectActionUpdate(movedUpstream, entry, "v5.0.0", testCheckoutMovedSHA, UpdateActionsConfig{AcceptMovedTags: true}, now)
+		assert.True(t, update, "moved tags should be re-pinned when accepted")
+		assert.Equal(t, actionUpdateStatusUpdated, result.Status)
+	})
+
+	t.Run("release not descending from the pin is unreachable", func(t *testing.T) {
+		forkUpstream := &fakeActionUpstream{
+			tags:        upstream.tags,
+			releases:    upstream.releases,
+			unreachable: map[string]bool{testCheckoutOldSHA + ".." + testCheckoutNewSHA: true},
+		}
+		result, update := inspectActionUpdate(forkUpstream, entry, "v5.1.0", testCheckoutNewSHA, UpdateActionsConfig{Report: true}, now)
+		assert.True(t, update)
+		assert.Equal(t, "no", result.Reachable)
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		result, update := inspectActionUpdate(upstream, entry, "v5.0.0", testCheckoutOldSHA, UpdateActionsConfig{Report: true}, now)
+		assert.False(t, update)
+		assert.Equal(t, actionUpdateStatusCurrent, result.Status)
+		assert.Empty(t, result.Reachable)
+	})
+}
+
+func TestChangelogExcerpt(t *testing.T) {
+	assert.Empty(t, changelogExcerpt("", 3))
+	assert.Equal(t, "a\nb\nc\n...", changelogExcerpt("a\n\nb\nc\nd\ne", 3))
+	assert.Equal(t, "a\nb", changelogExcerpt("a  \nb\n\n", 3))
+}
+
+func TestRunUpdateActionsCooldownAndDryRun(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "update-actions-*")
+	awDir := filepath.Join(tmpDir, ".github", "aw")
+	require.NoError(t, os.MkdirAll(awDir, 0755))
+	lockPath := filepath.Join(awDir, "actions-lock.json")
+	lockContent := `{
+  "entries": {
+    "actions/checkout@v5.0.0": {"repo": "actions/checkout", "version": "v5.0.0", "sha": "` + testCheckoutOldSHA + `"},
+    "actions/setup-node@v4.0.0": {"repo": "actions/setup-node", "version": "v4.0.0", "sha": "` + testCheckoutOldSHA + `"}
+  }
+}`
+	require.NoError(t, os.WriteFile(lockPath, []byte(lockContent), 0644))
+
+	originalDir, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(tmpDir))
+	defer os.Chdir(originalDir)
+
+	originalLatest := latestActionReleaseFunc
+	defer func() { latestActionReleaseFunc = originalLatest }()
+	latestActionReleaseFunc = func(repo, currentVersion string, allowMajor, verbose bool) (string, string, error) {
+		if repo == "actions/checkout" {
+			return "v5.1.0", testCheckoutNewSHA, nil
+		}
+		return "v4.1.0", testCheckoutNewSHA, nil
+	}
+
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	upstream := &fakeActionUpstream{
+		releases: map[string]actionReleaseInfo{
+			"actions/checkout@v5.1.0":   {PublishedAt: now.Add(-30 * 24 * time.Hour)},
+			"actions/setup-node@v4.1.0": {PublishedAt: now.Add(-24 * time.Hour)},
+		},
+	}
+
+	require.NoError(t, runUpdateActions(UpdateActionsConfig{CooldownDays: 7, DryRun: true}, upstream, now))
+	content, err := os.ReadFile(lockPath)
+	require.NoError(t, err)
+	assert.Equal(t, lockContent, string(content), "dry run should not modify actions-lock.json")
+
+	require.NoError(t, runUpdateActions(UpdateActionsConfig{CooldownDays: 7}, upstream, now))
+	content, err = os.ReadFile(lockPath)
+	require.NoError(t, err)
+
+	var lock actionsLockFile
+	require.NoError(t, json.Unmarshal(content, &lock))
+	assert.Contains(t, lock.Entries, "actions/checkout@v5.1.0", "release past the cooldown should be applied")
+	assert.Contains(t, lock.Entries, "actions/setup-node@v4.0.0", "release within the cooldown should be held back")
+	assert.NotContains(t, lock.Entries, "actions/setup-node@v4.1.0")
+}
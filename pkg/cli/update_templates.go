@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/workflow"
+)
+
+// workflowWithTemplateSource represents a workflow instantiated from a template
+type workflowWithTemplateSource struct {
+	Name   string
+	Path   string
+	Source *workflow.TemplateSource
+}
+
+// findWorkflowsWithTemplateSource finds all workflows that have a template-source field
+func findWorkflowsWithTemplateSource(workflowsDir string, filterNames []string, verbose bool) ([]*workflowWithTemplateSource, error) {
+	updateLog.Printf("Finding workflows with template-source field in %s", workflowsDir)
+
+	files, err := getMarkdownWorkflowFiles(workflowsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var workflows []*workflowWithTemplateSource
+	for _, file := range files {
+		workflowName := normalizeWorkflowID(filepath.Base(file))
+		if len(filterNames) > 0 {
+			matched := false
+			for _, filterName := range filterNames {
+				if normalizeWorkflowID(filterName) == workflowName {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				continue
+			}
+		}
+
+		content, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		result, err := parser.ExtractFrontmatterFromContent(string(content))
+		if err != nil {
+			continue
+		}
+		source, err := workflow.ExtractTemplateSource(result.Frontmatter)
+		if err != nil {
+			if verbose {
+				fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Skipping %s: %v", workflowName, err)))
+			}
+			continue
+		}
+		if source == nil {
+			continue
+		}
+
+		workflows = append(workflows, &workflowWithTemplateSource{Name: workflowName, Path: file, Source: source})
+	}
+
+	return workflows, nil
+}
+
+// updateTemplateInstance re-renders a workflow from its template with the recorded input values.
+// It reports whether the workflow changed.
+func updateTemplateInstance(wf *workflowWithTemplateSource, verbose bool, engineOverride string) (bool, error) {
+	updateLog.Printf("Re-rendering %s from template %s", wf.Name, wf.Source.Path)
+
+	templatePath := wf.Source.Path
+	if !filepath.IsAbs(templatePath) {
+		gitRoot, err := findGitRoot()
+		if err != nil {
+			return false, err
+		}
+		templatePath = filepath.Join(gitRoot, filepath.FromSlash(templatePath))
+	}
+
+	tmpl, recordedPath, err := loadWorkflowTemplate(templatePath)
+	if err != nil {
+		return false, err
+	}
+	inputs, err := tmpl.ResolveInputs(wf.Source.Inputs)
+	if err != nil {
+		return false, fmt.Errorf("template %s: %w", wf.Source.Path, err)
+	}
+	rendered, err := tmpl.Render(inputs, recordedPath)
+	if err != nil {
+		return false, fmt.Errorf("template %s: %w", wf.Source.Path, err)
+	}
+
+	current, err := os.ReadFile(wf.Path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read current workflow: %w", err)
+	}
+	if strings.TrimSpace(string(current)) == strings.TrimSpace(rendered) {
+		fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Workflow %s is already up to date with template %s", wf.Name, wf.Source.Path)))
+		return false, nil
+	}
+
+	if err := os.WriteFile(wf.Path, []byte(rendered), 0644); err != nil {
+		return false, fmt.Errorf("failed to write updated workflow: %w", err)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatSuccessMessage(fmt.Sprintf("Re-rendered %s from template %s", wf.Name, wf.Source.Path)))
+
+	if err := compileWorkflowWithRefresh(wf.Path, verbose, false, engineOverride, true); err != nil {
+		return true, fmt.Errorf("failed to compile updated workflow: %w", err)
+	}
+	return true, nil
+}
@@ -28,19 +28,40 @@ func UpdateWorkflows(workflowNames []string, allowMajor, force, verbose bool, en
 
 	updateLog.Printf("Found %d workflows with source field", len(workflows))
 
-	if len(workflows) == 0 {
+	// Find all workflows instantiated from a template
+	templateWorkflows, err := findWorkflowsWithTemplateSource(workflowsDir, workflowNames, verbose)
+	if err != nil {
+		return err
+	}
+
+	updateLog.Printf("Found %d workflows with template-source field", len(templateWorkflows))
+
+	if len(workflows) == 0 && len(templateWorkflows) == 0 {
 		if len(workflowNames) > 0 {
 			return fmt.Errorf("no workflows found matching the specified names with source field")
 		}
 		return fmt.Errorf("no workflows found with source field")
 	}
 
-	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d workflow(s) to update", len(workflows))))
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("Found %d workflow(s) to update", len(workflows)+len(templateWorkflows))))
 
 	// Track update results
 	var successfulUpdates []string
 	var failedUpdates []updateFailure
 
+	// Re-render workflows instantiated from templates
+	for _, wf := range templateWorkflows {
+		if _, err := updateTemplateInstance(wf, verbose, engineOverride); err != nil {
+			updateLog.Printf("Failed to re-render workflow %s: %v", wf.Name, err)
+			failedUpdates = append(failedUpdates, updateFailure{
+				Name:  wf.Name,
+				Error: err.Error(),
+			})
+			continue
+		}
+		successfulUpdates = append(successfulUpdates, wf.Name)
+	}
+
 	// Update each workflow
 	for _, wf := range workflows {
 		updateLog.Printf("Updating workflow: %s (source: %s)", wf.Name, wf.SourceSpec)
@@ -0,0 +1,133 @@
+package console
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/styles"
+	"github.com/github/gh-aw/pkg/tty"
+)
+
+var pagerLog = logger.New("console:pager")
+
+// PagerConfig configures the interactive pager
+type PagerConfig struct {
+	Title   string
+	Content string
+	// Sections are the line numbers of section headers; n and p jump between them
+	Sections []int
+}
+
+// pagerModel is the Bubble Tea model for the pager
+type pagerModel struct {
+	config   PagerConfig
+	viewport viewport.Model
+	ready    bool
+}
+
+// Init initializes the pager model
+func (m pagerModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages and updates the model
+func (m pagerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+		case "n":
+			m.viewport.SetYOffset(nextSection(m.config.Sections, m.viewport.YOffset))
+			return m, nil
+		case "p":
+			m.viewport.SetYOffset(previousSection(m.config.Sections, m.viewport.YOffset))
+			return m, nil
+		case "g", "home":
+			m.viewport.GotoTop()
+			return m, nil
+		case "G", "end":
+			m.viewport.GotoBottom()
+			return m, nil
+		}
+	case tea.WindowSizeMsg:
+		height := max(msg.Height-2, 1) // Header and footer lines
+		if !m.ready {
+			m.viewport = viewport.New(msg.Width, height)
+			m.viewport.SetContent(m.config.Content)
+			m.ready = true
+		} else {
+			m.viewport.Width = msg.Width
+			m.viewport.Height = height
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// View renders the pager
+func (m pagerModel) View() string {
+	if !m.ready {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Foreground(styles.ColorInfo).Bold(true)
+	footerStyle := lipgloss.NewStyle().Foreground(styles.ColorComment)
+
+	help := "↑/↓ scroll • pgup/pgdn page • q quit"
+	if len(m.config.Sections) > 0 {
+		help = "↑/↓ scroll • pgup/pgdn page • n/p next/previous • q quit"
+	}
+	footer := fmt.Sprintf("%3.f%% • %s", m.viewport.ScrollPercent()*100, help)
+
+	return titleStyle.Render(m.config.Title) + "\n" + m.viewport.View() + "\n" + footerStyle.Render(footer)
+}
+
+// nextSection returns the first section line after the offset, or the offset if there is none
+func nextSection(sections []int, offset int) int {
+	for _, line := range sections {
+		if line > offset {
+			return line
+		}
+	}
+	return offset
+}
+
+// previousSection returns the last section line before the offset, or the top of the content
+func previousSection(sections []int, offset int) int {
+	previous := 0
+	for _, line := range sections {
+		if line >= offset {
+			break
+		}
+		previous = line
+	}
+	return previous
+}
+
+// ShowPager displays scrollable content in an interactive pager.
+// In non-TTY environments the content is written to stdout as-is.
+func ShowPager(config PagerConfig) error {
+	pagerLog.Printf("Showing pager: title=%s, lines=%d, sections=%d", config.Title, strings.Count(config.Content, "\n")+1, len(config.Sections))
+
+	if !tty.IsStdoutTerminal() || IsAccessibleMode() {
+		pagerLog.Print("Non-TTY or accessible mode detected, printing content")
+		fmt.Fprint(os.Stdout, config.Content)
+		return nil
+	}
+
+	p := tea.NewProgram(pagerModel{config: config}, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	if _, err := p.Run(); err != nil {
+		pagerLog.Printf("Error running pager program: %v", err)
+		return fmt.Errorf("failed to run pager: %w", err)
+	}
+	return nil
+}
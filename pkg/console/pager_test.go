@@ -0,0 +1,24 @@
+//go:build !integration
+
+package console
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerSectionNavigation(t *testing.T) {
+	sections := []int{5, 20, 40}
+
+	assert.Equal(t, 5, nextSection(sections, 0))
+	assert.Equal(t, 20, nextSection(sections, 5))
+	assert.Equal(t, 40, nextSection(sections, 25))
+	assert.Equal(t, 45, nextSection(sections, 45), "stays put after the last section")
+
+	assert.Equal(t, 0, previousSection(sections, 5), "goes to the top before the first section")
+	assert.Equal(t, 5, previousSection(sections, 20))
+	assert.Equal(t, 20, previousSection(sections, 30))
+	assert.Equal(t, 40, previousSection(sections, 100))
+	assert.Equal(t, 0, previousSection(nil, 10))
+}
@@ -266,6 +266,12 @@ const (
 
 	// DocsSandboxURL is the documentation URL for sandbox configuration
 	DocsSandboxURL DocURL = "https://github.com/github/gh-aw/blob/main/docs/src/content/docs/reference/sandbox.md"
+
+	// DocsPhasesURL is the documentation URL for multi-phase agent pipelines
+	DocsPhasesURL DocURL = "https://github.com/github/gh-aw/blob/main/docs/src/content/docs/reference/phases.md"
+
+	// DocsResumeURL is the documentation URL for agent checkpoints and resume
+	DocsResumeURL DocURL = "https://github.com/github/gh-aw/blob/main/docs/src/content/docs/reference/resume.md"
 )
 
 // MaxExpressionLineLength is the maximum length for a single line expression before breaking into multiline.
@@ -332,6 +338,8 @@ const (
 	EnvVarModelAgentClaude = "GH_AW_MODEL_AGENT_CLAUDE"
 	// EnvVarModelAgentCodex configures the default Codex model for agent execution
 	EnvVarModelAgentCodex = "GH_AW_MODEL_AGENT_CODEX"
+	// EnvVarModelAgentOpenAI configures the default model of the OpenAI-compatible engine for agent execution
+	EnvVarModelAgentOpenAI = "GH_AW_MODEL_AGENT_OPENAI"
 	// EnvVarModelAgentCustom configures the default Custom model for agent execution
 	EnvVarModelAgentCustom = "GH_AW_MODEL_AGENT_CUSTOM"
 	// EnvVarModelDetectionCopilot configures the default Copilot model for detection
@@ -340,6 +348,8 @@ const (
 	EnvVarModelDetectionClaude = "GH_AW_MODEL_DETECTION_CLAUDE"
 	// EnvVarModelDetectionCodex configures the default Codex model for detection
 	EnvVarModelDetectionCodex = "GH_AW_MODEL_DETECTION_CODEX"
+	// EnvVarModelDetectionOpenAI configures the default model of the OpenAI-compatible engine for detection
+	EnvVarModelDetectionOpenAI = "GH_AW_MODEL_DETECTION_OPENAI"
 )
 
 // DefaultCodexVersion is the default version of the OpenAI Codex CLI
@@ -349,7 +359,16 @@ const DefaultCodexVersion Version = "0.101.0"
 const DefaultGitHubMCPServerVersion Version = "v0.30.3"
 
 // DefaultFirewallVersion is the default version of the gh-aw-firewall (AWF) binary
-const DefaultFirewallVersion Version = "v0.18.0"
+const DefaultFirewallVersion Version = "v0.19.0"
+
+// AWFLLMUsageLogVersion is the first AWF version whose api-proxy writes the LLM usage log
+// (--api-proxy-usage-log). Older versions reject the flag, so it is only passed when the
+// AWF version of the workflow (network.firewall.version) is at least this version.
+const AWFLLMUsageLogVersion Version = "v0.19.0"
+
+// LLMUsageLogPath is where the AWF api-proxy writes the LLM usage log, one JSON line per
+// request. It is in the firewall logs directory, which is uploaded with the agent artifacts.
+const LLMUsageLogPath = "/tmp/gh-aw/sandbox/firewall/logs/llm-usage.jsonl"
 
 // DefaultMCPGatewayVersion is the default version of the MCP Gateway (gh-aw-mcpg) Docker image
 const DefaultMCPGatewayVersion Version = "v0.1.4"
@@ -361,6 +380,35 @@ const DefaultMCPGatewayContainer = "ghcr.io/github/gh-aw-mcpg"
 // This directory is shared between the agent container and MCP gateway for large payload exchange
 const DefaultMCPGatewayPayloadDir = "/tmp/gh-aw/mcp-payloads"
 
+// MCPCassetteDir is the directory where the MCP gateway writes recorded cassettes
+const MCPCassetteDir = "/tmp/gh-aw/mcp-cassette"
+
+// MCPCassetteFilename is the filename of a recorded MCP gateway cassette
+const MCPCassetteFilename = "cassette.jsonl"
+
+// MCPCassetteArtifactName is the name of the artifact containing the recorded MCP gateway cassette
+const MCPCassetteArtifactName = "mcp-cassette"
+
+// MCPGatewayCassetteSpecVersion is the MCP Gateway Specification version that introduced cassettes.
+// The gateway must report at least this specVersion on /health (checked by check_mcp_gateway_spec_version.sh).
+const MCPGatewayCassetteSpecVersion Version = "1.9.0"
+
+// MCPGatewayLimitsSpecVersion is the MCP Gateway Specification version that introduced call limits.
+// The gateway must report at least this specVersion on /health (checked by check_mcp_gateway_spec_version.sh).
+const MCPGatewayLimitsSpecVersion Version = "1.10.0"
+
+// AgentCheckpointArtifactName is the name of the artifact containing the engine session checkpoint of a run
+const AgentCheckpointArtifactName = "agent-checkpoint"
+
+// ResumeRunIDInputName is the workflow_dispatch input holding the ID of the run to resume from
+const ResumeRunIDInputName = "aw_resume_run_id"
+
+// EgressReportFile is the network egress report of the agent generated from the firewall logs
+const EgressReportFile = "/tmp/gh-aw/egress-report.json"
+
+// EgressReportArtifactName is the name of the artifact containing the network egress report of the agent
+const EgressReportArtifactName = "egress-report"
+
 // DefaultFirewallRegistry is the container image registry for AWF (gh-aw-firewall) Docker images
 const DefaultFirewallRegistry = "ghcr.io/github/gh-aw-firewall"
 
@@ -644,13 +692,15 @@ const (
 	ClaudeEngine EngineName = "claude"
 	// CodexEngine is the OpenAI Codex engine identifier
 	CodexEngine EngineName = "codex"
+	// OpenAIEngine is the identifier of the engine for OpenAI-compatible chat completions endpoints
+	OpenAIEngine EngineName = "openai"
 	// CustomEngine is the custom engine identifier
 	CustomEngine EngineName = "custom"
 )
 
 // AgenticEngines lists all supported agentic engine names
 // Note: This remains a string slice for backward compatibility with existing code
-var AgenticEngines = []string{string(ClaudeEngine), string(CodexEngine), string(CopilotEngine), string(CopilotSDKEngine)}
+var AgenticEngines = []string{string(ClaudeEngine), string(CodexEngine), string(CopilotEngine), string(CopilotSDKEngine), string(OpenAIEngine)}
 
 // EngineOption represents a selectable AI engine with its display metadata and secret configuration
 type EngineOption struct {
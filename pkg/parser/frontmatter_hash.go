@@ -109,6 +109,8 @@ func buildCanonicalFrontmatter(frontmatter map[string]any, result *ImportsResult
 	addField("steps")
 	addField("post-steps")
 	addField("jobs")
+	addField("phases")
+	addField("resume")
 
 	// Metadata fields
 	addField("description")
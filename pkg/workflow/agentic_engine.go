@@ -171,6 +171,11 @@ type LogParser interface {
 	// GetLogFileForParsing returns the log file path to use for JavaScript parsing in the workflow
 	// This may be different from the stdout/stderr log file if the engine produces separate detailed logs
 	GetLogFileForParsing() string
+
+	// GetFallbackErrorPatterns returns the extended regular expressions (matched case-insensitively)
+	// that identify quota, authentication and infrastructure failures in the agent log.
+	// When the engine fails with one of these errors, the next engine of the fallback chain runs.
+	GetFallbackErrorPatterns() []string
 }
 
 // SecurityProvider handles security-related configuration
@@ -282,6 +287,17 @@ func (e *BaseEngine) GetLogFileForParsing() string {
 	return "/tmp/gh-aw/agent-stdio.log"
 }
 
+// GetFallbackErrorPatterns returns patterns for rate limiting and transient infrastructure
+// errors shared by all engines. Engines can override this to add provider-specific errors.
+func (e *BaseEngine) GetFallbackErrorPatterns() []string {
+	return []string{
+		"429 Too Many Requests",
+		"rate limit (exceeded|reached)",
+		"502 Bad Gateway|503 Service Unavailable|504 Gateway Timeout",
+		"ECONNRESET|ETIMEDOUT|EAI_AGAIN",
+	}
+}
+
 // GetRequiredSecretNames returns an empty list by default
 // Engines must override this to specify their required secrets
 func (e *BaseEngine) GetRequiredSecretNames(workflowData *WorkflowData) []string {
@@ -317,6 +333,7 @@ func NewEngineRegistry() *EngineRegistry {
 	registry.Register(NewCodexEngine())
 	registry.Register(NewCopilotEngine())
 	registry.Register(NewCopilotSDKEngine())
+	registry.Register(NewOpenAIEngine())
 	registry.Register(NewCustomEngine())
 
 	agenticEngineLog.Printf("Registered %d engines", len(registry.engines))
@@ -11,8 +11,8 @@ func TestEngineRegistry(t *testing.T) {
 
 	// Test that built-in engines are registered
 	supportedEngines := registry.GetSupportedEngines()
-	if len(supportedEngines) != 5 {
-		t.Errorf("Expected 5 supported engines, got %d", len(supportedEngines))
+	if len(supportedEngines) != 6 {
+		t.Errorf("Expected 6 supported engines, got %d", len(supportedEngines))
 	}
 
 	// Test getting engines by ID
@@ -118,7 +118,7 @@ func TestEngineRegistryCustomEngine(t *testing.T) {
 
 	// Test that supported engines list is updated
 	supportedEngines := registry.GetSupportedEngines()
-	if len(supportedEngines) != 6 {
-		t.Errorf("Expected 6 supported engines after adding test-custom, got %d", len(supportedEngines))
+	if len(supportedEngines) != 7 {
+		t.Errorf("Expected 7 supported engines after adding test-custom, got %d", len(supportedEngines))
 	}
 }
@@ -587,6 +587,7 @@ func buildCacheMemoryPromptSection(config *CacheMemoryConfig) *PromptSection {
 
 		// Return prompt section with template file and environment variables for substitution
 		return &PromptSection{
+			Name:    "Cache memory",
 			Content: cacheMemoryPromptFile,
 			IsFile:  true,
 			EnvVars: map[string]string{
@@ -671,6 +672,7 @@ func buildCacheMemoryPromptSection(config *CacheMemoryConfig) *PromptSection {
 	}
 
 	return &PromptSection{
+		Name:    "Cache memory",
 		Content: cacheMemoryPromptMultiFile,
 		IsFile:  true,
 		EnvVars: map[string]string{
@@ -251,7 +251,7 @@ func (e *ClaudeEngine) GetExecutionSteps(workflowData *WorkflowData, logFile str
 
 	// Add conditional model flag if not explicitly configured
 	// Check if this is a detection job (has no SafeOutputs config)
-	isDetectionJob := workflowData.SafeOutputs == nil
+	isDetectionJob := isThreatDetectionData(workflowData)
 	var modelEnvVar string
 	if isDetectionJob {
 		modelEnvVar = constants.EnvVarModelDetectionClaude
@@ -262,6 +262,9 @@ func (e *ClaudeEngine) GetExecutionSteps(workflowData *WorkflowData, logFile str
 		claudeCommand = fmt.Sprintf(`%s${%s:+ --model "$%s"}`, claudeCommand, modelEnvVar, modelEnvVar)
 	}
 
+	// Continue the checkpointed session when the agent job restored one (resume: true)
+	claudeCommand += resumeEngineArg(workflowData, " --continue")
+
 	// Build the full command based on whether firewall is enabled
 	var command string
 	if isFirewallEnabled(workflowData) {
@@ -305,6 +308,9 @@ func (e *ClaudeEngine) GetExecutionSteps(workflowData *WorkflowData, logFile str
 			claudeLog.Printf("Added %d custom mounts from agent config", len(sortedMounts))
 		}
 
+		// Add the mounts enforcing the sandbox filesystem policy (sandbox.filesystem)
+		awfArgs = append(awfArgs, getSandboxFilesystemMountArgs(workflowData)...)
+
 		awfArgs = append(awfArgs, "--allow-domains", allowedDomains)
 
 		// Add blocked domains if specified
@@ -334,12 +340,7 @@ func (e *ClaudeEngine) GetExecutionSteps(workflowData *WorkflowData, logFile str
 		claudeLog.Print("Using --skip-pull since images are pre-downloaded")
 
 		// Enable API proxy sidecar if this engine supports LLM gateway
-		// The api-proxy container holds the LLM API keys and proxies requests through the firewall
-		llmGatewayPort := e.SupportsLLMGateway()
-		if llmGatewayPort > 0 {
-			awfArgs = append(awfArgs, "--enable-api-proxy")
-			claudeLog.Printf("Added --enable-api-proxy for LLM API proxying on port %d", llmGatewayPort)
-		}
+		awfArgs = append(awfArgs, getAPIProxyArgs(e.SupportsLLMGateway(), firewallConfig)...)
 
 		// Add SSL Bump support for HTTPS content inspection (v0.9.0+)
 		sslBumpArgs := getSSLBumpArgs(firewallConfig)
@@ -387,12 +388,12 @@ func (e *ClaudeEngine) GetExecutionSteps(workflowData *WorkflowData, logFile str
 		if promptSetup != "" {
 			command = fmt.Sprintf(`set -o pipefail
           %s
-%s %s \
-  -- %s 2>&1 | tee -a %s`, promptSetup, awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, logFile)
+%s%s %s \
+  -- %s 2>&1 | tee -a %s`, promptSetup, getSandboxFilesystemMountsLoad(workflowData), awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, logFile)
 		} else {
 			command = fmt.Sprintf(`set -o pipefail
-%s %s \
-  -- %s 2>&1 | tee -a %s`, awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, logFile)
+%s%s %s \
+  -- %s 2>&1 | tee -a %s`, getSandboxFilesystemMountsLoad(workflowData), awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, logFile)
 		}
 	} else {
 		// Run Claude command without AWF wrapper
@@ -463,6 +464,11 @@ func (e *ClaudeEngine) GetExecutionSteps(workflowData *WorkflowData, logFile str
 		env["GH_AW_MAX_TURNS"] = workflowData.EngineConfig.MaxTurns
 	}
 
+	// Keep the session transcripts in a directory that is checkpointed (resume: true)
+	if workflowData.Resume {
+		env["CLAUDE_CONFIG_DIR"] = claudeSessionDir
+	}
+
 	// Add model environment variable if model is not explicitly configured
 	// This allows users to configure the default model via GitHub Actions variables
 	// Use different env vars for agent vs detection jobs
@@ -472,7 +478,7 @@ func (e *ClaudeEngine) GetExecutionSteps(workflowData *WorkflowData, logFile str
 			env[constants.EnvVarModelDetectionClaude] = fmt.Sprintf("${{ vars.%s || '' }}", constants.EnvVarModelDetectionClaude)
 		} else {
 			// For agent execution, use agent-specific env var
-			env[constants.EnvVarModelAgentClaude] = fmt.Sprintf("${{ vars.%s || '' }}", constants.EnvVarModelAgentClaude)
+			env[constants.EnvVarModelAgentClaude] = agentModelEnvValue(workflowData, constants.EnvVarModelAgentClaude)
 		}
 	}
 
@@ -545,6 +551,17 @@ func (e *ClaudeEngine) GetLogParserScriptId() string {
 	return "parse_claude_log"
 }
 
+// GetFallbackErrorPatterns returns the Anthropic API errors after which a fallback engine runs
+func (e *ClaudeEngine) GetFallbackErrorPatterns() []string {
+	return append(e.BaseEngine.GetFallbackErrorPatterns(),
+		"rate_limit_error",
+		"overloaded_error",
+		"authentication_error",
+		"credit balance is too low",
+		"invalid x-api-key",
+	)
+}
+
 // GetFirewallLogsCollectionStep returns the step for collecting firewall logs (before secret redaction)
 // No longer needed since we know where the logs are in the sandbox folder structure
 func (e *ClaudeEngine) GetFirewallLogsCollectionStep(workflowData *WorkflowData) []GitHubActionStep {
@@ -73,6 +73,108 @@ func (e *ClaudeEngine) ParseLogMetrics(logContent string, verbose bool) LogMetri
 	return metrics
 }
 
+// ParseTranscript implements TranscriptParser for Claude stream-json logs
+func (e *ClaudeEngine) ParseTranscript(logContent string, verbose bool) *Transcript {
+	claudeLogsLog.Printf("Parsing Claude transcript: %d bytes", len(logContent))
+	builder := newTranscriptBuilder(e.GetID())
+	parseStreamJSONTranscript(builder, e.parseClaudeLogEntries(logContent, verbose))
+	return builder.build()
+}
+
+// parseStreamJSONTranscript adds the turns of stream-json entries (system, assistant, user
+// and result) to the transcript builder. Consecutive assistant entries with the same
+// message ID belong to the same turn.
+func parseStreamJSONTranscript(builder *transcriptBuilder, entries []map[string]any) {
+	currentMessageID := ""
+	for _, entry := range entries {
+		entryType, _ := entry["type"].(string)
+		switch entryType {
+		case "assistant":
+			messageMap, ok := entry["message"].(map[string]any)
+			if !ok {
+				continue
+			}
+			messageID, _ := messageMap["id"].(string)
+			if messageID == "" || messageID != currentMessageID {
+				builder.startTurn(nil)
+				currentMessageID = messageID
+			}
+			turn := builder.turn()
+			if usage := streamJSONUsage(messageMap["usage"]); usage.TotalTokens > 0 {
+				turn.Usage = usage
+			}
+			contentArray, _ := messageMap["content"].([]any)
+			for _, item := range contentArray {
+				contentMap, ok := item.(map[string]any)
+				if !ok {
+					continue
+				}
+				switch contentMap["type"] {
+				case "text":
+					text, _ := contentMap["text"].(string)
+					builder.addMessage(TranscriptRoleAssistant, text)
+				case "thinking":
+					text, _ := contentMap["thinking"].(string)
+					builder.addMessage(TranscriptRoleReasoning, text)
+				case "tool_use":
+					id, _ := contentMap["id"].(string)
+					name, _ := contentMap["name"].(string)
+					builder.addToolCall(TranscriptToolCall{ID: id, Name: name, Args: transcriptArgs(contentMap["input"])})
+				}
+			}
+
+		case "user":
+			currentMessageID = ""
+			messageMap, ok := entry["message"].(map[string]any)
+			if !ok {
+				continue
+			}
+			switch content := messageMap["content"].(type) {
+			case string:
+				builder.addPendingMessage(TranscriptRoleUser, content)
+			case []any:
+				for _, item := range content {
+					contentMap, ok := item.(map[string]any)
+					if !ok {
+						continue
+					}
+					switch contentMap["type"] {
+					case "text":
+						text, _ := contentMap["text"].(string)
+						builder.addPendingMessage(TranscriptRoleUser, text)
+					case "tool_result":
+						id, _ := contentMap["tool_use_id"].(string)
+						isError, _ := contentMap["is_error"].(bool)
+						builder.setToolResult(id, transcriptContentText(contentMap["content"]), isError)
+					}
+				}
+			}
+
+		case "result":
+			builder.usage = streamJSONUsage(entry["usage"])
+			if durationMs := ConvertToFloat(entry["duration_ms"]); durationMs > 0 {
+				builder.duration = time.Duration(durationMs * float64(time.Millisecond))
+			}
+		}
+	}
+}
+
+// streamJSONUsage converts a stream-json usage object to transcript usage
+func streamJSONUsage(usage any) TranscriptUsage {
+	usageMap, ok := usage.(map[string]any)
+	if !ok {
+		return TranscriptUsage{}
+	}
+	result := TranscriptUsage{
+		InputTokens:         ConvertToInt(usageMap["input_tokens"]),
+		OutputTokens:        ConvertToInt(usageMap["output_tokens"]),
+		CacheCreationTokens: ConvertToInt(usageMap["cache_creation_input_tokens"]),
+		CacheReadTokens:     ConvertToInt(usageMap["cache_read_input_tokens"]),
+	}
+	result.TotalTokens = result.InputTokens + result.OutputTokens + result.CacheCreationTokens + result.CacheReadTokens
+	return result
+}
+
 // isClaudeResultPayload checks if the JSON line is a Claude result payload with type: "result"
 func (e *ClaudeEngine) isClaudeResultPayload(line string) bool {
 	trimmed := strings.TrimSpace(line)
@@ -144,89 +246,9 @@ func (e *ClaudeEngine) parseClaudeJSONLog(logContent string, verbose bool) LogMe
 	claudeLogsLog.Print("Attempting to parse Claude JSON log")
 	var metrics LogMetrics
 
-	// Try to parse the entire log as a JSON array first (old format)
-	var logEntries []map[string]any
-	if err := json.Unmarshal([]byte(logContent), &logEntries); err != nil {
-		// If that fails, try to parse as mixed format (debug logs + JSONL)
-		claudeLogsLog.Print("JSON array parse failed, trying JSONL format")
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Failed to parse Claude log as JSON array, trying JSONL format: %v\n", err)
-		}
-
-		logEntries = []map[string]any{}
-		lines := strings.Split(logContent, "\n")
-
-		for i := 0; i < len(lines); i++ {
-			line := lines[i]
-			trimmedLine := strings.TrimSpace(line)
-			if trimmedLine == "" {
-				continue // Skip empty lines
-			}
-
-			// If a line looks like a JSON array (starts with '['), try to parse it as an array
-			if strings.HasPrefix(trimmedLine, "[") {
-				buf := trimmedLine
-				// If the closing bracket is not on the same line, accumulate subsequent lines
-				if !strings.Contains(trimmedLine, "]") {
-					j := i + 1
-					for j < len(lines) {
-						buf += "\n" + lines[j]
-						if strings.Contains(lines[j], "]") {
-							// Advance outer loop to the line we consumed
-							i = j
-							break
-						}
-						j++
-					}
-				}
-
-				var arr []map[string]any
-				if err := json.Unmarshal([]byte(buf), &arr); err == nil {
-					logEntries = append(logEntries, arr...)
-					continue
-				}
-
-				// If parsing as a single-line or multi-line array failed, attempt to extract a JSON array substring
-				openIdx := strings.Index(buf, "[")
-				closeIdx := strings.LastIndex(buf, "]")
-				if openIdx != -1 && closeIdx != -1 && closeIdx > openIdx {
-					sub := buf[openIdx : closeIdx+1]
-					var arr2 []map[string]any
-					if err2 := json.Unmarshal([]byte(sub), &arr2); err2 == nil {
-						logEntries = append(logEntries, arr2...)
-						continue
-					}
-				}
-			}
-
-			// Skip debug log lines that don't start with '{'
-			if !strings.HasPrefix(trimmedLine, "{") {
-				continue
-			}
-
-			// Try to parse each line as JSON
-			var jsonEntry map[string]any
-			if err := json.Unmarshal([]byte(trimmedLine), &jsonEntry); err != nil {
-				// Skip invalid JSON lines (could be partial debug output)
-				if verbose {
-					fmt.Fprintf(os.Stderr, "Skipping invalid JSON line: %s\n", trimmedLine)
-				}
-				continue
-			}
-
-			logEntries = append(logEntries, jsonEntry)
-		}
-
-		if len(logEntries) == 0 {
-			if verbose {
-				fmt.Fprintf(os.Stderr, "No valid JSON entries found in Claude log\n")
-			}
-			return metrics
-		}
-
-		if verbose {
-			fmt.Fprintf(os.Stderr, "Extracted %d JSON entries from mixed format Claude log\n", len(logEntries))
-		}
+	logEntries := e.parseClaudeLogEntries(logContent, verbose)
+	if logEntries == nil {
+		return metrics
 	}
 
 	// Look for the result entry with type: "result"
@@ -327,6 +349,97 @@ func (e *ClaudeEngine) parseClaudeJSONLog(logContent string, verbose bool) LogMe
 	return metrics
 }
 
+// parseClaudeLogEntries extracts the JSON entries of a Claude log, which is either a JSON
+// array (old format) or debug log lines mixed with JSONL. Returns nil when no entries are found.
+func (e *ClaudeEngine) parseClaudeLogEntries(logContent string, verbose bool) []map[string]any {
+	// Try to parse the entire log as a JSON array first (old format)
+	var logEntries []map[string]any
+	if err := json.Unmarshal([]byte(logContent), &logEntries); err != nil {
+		// If that fails, try to parse as mixed format (debug logs + JSONL)
+		claudeLogsLog.Print("JSON array parse failed, trying JSONL format")
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Failed to parse Claude log as JSON array, trying JSONL format: %v\n", err)
+		}
+
+		logEntries = []map[string]any{}
+		lines := strings.Split(logContent, "\n")
+
+		for i := 0; i < len(lines); i++ {
+			line := lines[i]
+			trimmedLine := strings.TrimSpace(line)
+			if trimmedLine == "" {
+				continue // Skip empty lines
+			}
+
+			// If a line looks like a JSON array (starts with '['), try to parse it as an array
+			if strings.HasPrefix(trimmedLine, "[") {
+				buf := trimmedLine
+				// If the closing bracket is not on the same line, accumulate subsequent lines
+				if !strings.Contains(trimmedLine, "]") {
+					j := i + 1
+					for j < len(lines) {
+						buf += "\n" + lines[j]
+						if strings.Contains(lines[j], "]") {
+							// Advance outer loop to the line we consumed
+							i = j
+							break
+						}
+						j++
+					}
+				}
+
+				var arr []map[string]any
+				if err := json.Unmarshal([]byte(buf), &arr); err == nil {
+					logEntries = append(logEntries, arr...)
+					continue
+				}
+
+				// If parsing as a single-line or multi-line array failed, attempt to extract a JSON array substring
+				openIdx := strings.Index(buf, "[")
+				closeIdx := strings.LastIndex(buf, "]")
+				if openIdx != -1 && closeIdx != -1 && closeIdx > openIdx {
+					sub := buf[openIdx : closeIdx+1]
+					var arr2 []map[string]any
+					if err2 := json.Unmarshal([]byte(sub), &arr2); err2 == nil {
+						logEntries = append(logEntries, arr2...)
+						continue
+					}
+				}
+			}
+
+			// Skip debug log lines that don't start with '{'
+			if !strings.HasPrefix(trimmedLine, "{") {
+				continue
+			}
+
+			// Try to parse each line as JSON
+			var jsonEntry map[string]any
+			if err := json.Unmarshal([]byte(trimmedLine), &jsonEntry); err != nil {
+				// Skip invalid JSON lines (could be partial debug output)
+				if verbose {
+					fmt.Fprintf(os.Stderr, "Skipping invalid JSON line: %s\n", trimmedLine)
+				}
+				continue
+			}
+
+			logEntries = append(logEntries, jsonEntry)
+		}
+
+		if len(logEntries) == 0 {
+			if verbose {
+				fmt.Fprintf(os.Stderr, "No valid JSON entries found in Claude log\n")
+			}
+			return nil
+		}
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Extracted %d JSON entries from mixed format Claude log\n", len(logEntries))
+		}
+	}
+
+	return logEntries
+}
+
 // parseToolCallsWithSequence extracts tool call information from Claude log content array and returns sequence
 func (e *ClaudeEngine) parseToolCallsWithSequence(contentArray []any, toolCallMap map[string]*ToolCallInfo) []string {
 	var sequence []string
@@ -21,6 +21,7 @@ var (
 	codexDurationPattern      = regexp.MustCompile(`in\s+(\d+(?:\.\d+)?)\s*s`)
 	codexTokenUsagePattern    = regexp.MustCompile(`(?i)tokens\s+used[:\s]+(\d+)`)
 	codexTotalTokensPattern   = regexp.MustCompile(`total_tokens:\s*(\d+)`)
+	codexResultPattern        = regexp.MustCompile(`^(.+?) (success|succeeded|failure|failed|exited (-?\d+)) in (\d+(?:\.\d+)?)(ms|s):?$`)
 )
 
 // CodexEngine represents the Codex agentic engine
@@ -141,7 +142,7 @@ func (e *CodexEngine) GetExecutionSteps(workflowData *WorkflowData, logFile stri
 		modelParam = fmt.Sprintf("-c model=%s ", workflowData.EngineConfig.Model)
 	} else {
 		// Check if this is a detection job (has no SafeOutputs config)
-		isDetectionJob := workflowData.SafeOutputs == nil
+		isDetectionJob := isThreatDetectionData(workflowData)
 		var modelEnvVar string
 		if isDetectionJob {
 			modelEnvVar = constants.EnvVarModelDetectionCodex
@@ -183,8 +184,11 @@ func (e *CodexEngine) GetExecutionSteps(workflowData *WorkflowData, logFile stri
 		commandName = "codex"
 	}
 
-	codexCommand := fmt.Sprintf("%s %sexec%s%s%s\"$INSTRUCTION\"",
-		commandName, modelParam, webSearchParam, fullAutoParam, customArgsParam)
+	// Continue the checkpointed session when the agent job restored one (resume: true)
+	resumeParam := resumeEngineArg(workflowData, "resume --last ")
+
+	codexCommand := fmt.Sprintf("%s %sexec%s%s%s%s\"$INSTRUCTION\"",
+		commandName, modelParam, webSearchParam, fullAutoParam, customArgsParam, resumeParam)
 
 	// Build the full command with agent file handling and AWF wrapping if enabled
 	var command string
@@ -224,6 +228,9 @@ func (e *CodexEngine) GetExecutionSteps(workflowData *WorkflowData, logFile stri
 			codexEngineLog.Printf("Added %d custom mounts from agent config", len(sortedMounts))
 		}
 
+		// Add the mounts enforcing the sandbox filesystem policy (sandbox.filesystem)
+		awfArgs = append(awfArgs, getSandboxFilesystemMountArgs(workflowData)...)
+
 		awfArgs = append(awfArgs, "--allow-domains", allowedDomains)
 
 		// Add blocked domains if specified
@@ -253,12 +260,7 @@ func (e *CodexEngine) GetExecutionSteps(workflowData *WorkflowData, logFile stri
 		codexEngineLog.Print("Using --skip-pull since images are pre-downloaded")
 
 		// Enable API proxy sidecar if this engine supports LLM gateway
-		// The api-proxy container holds the LLM API keys and proxies requests through the firewall
-		llmGatewayPort := e.SupportsLLMGateway()
-		if llmGatewayPort > 0 {
-			awfArgs = append(awfArgs, "--enable-api-proxy")
-			codexEngineLog.Printf("Added --enable-api-proxy for LLM API proxying on port %d", llmGatewayPort)
-		}
+		awfArgs = append(awfArgs, getAPIProxyArgs(e.SupportsLLMGateway(), firewallConfig)...)
 
 		// Note: No --tty flag for Codex (it's not a TUI, it outputs to stdout/stderr)
 
@@ -305,9 +307,9 @@ func (e *CodexEngine) GetExecutionSteps(workflowData *WorkflowData, logFile stri
 
 			command = fmt.Sprintf(`set -o pipefail
 mkdir -p "$CODEX_HOME/logs"
-%s %s \
+%s%s %s \
   -- %s \
-  2>&1 | tee %s`, awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, shellEscapeArg(logFile))
+  2>&1 | tee %s`, getSandboxFilesystemMountsLoad(workflowData), awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, shellEscapeArg(logFile))
 		} else {
 			// Read prompt inside AWF container to avoid Docker Compose interpolation issues, with PATH setup
 			codexCommandWithSetup := fmt.Sprintf(`%s && INSTRUCTION="$(cat /tmp/gh-aw/aw-prompts/prompt.txt)" && %s`, npmPathSetup, codexCommand)
@@ -316,9 +318,9 @@ mkdir -p "$CODEX_HOME/logs"
 
 			command = fmt.Sprintf(`set -o pipefail
 mkdir -p "$CODEX_HOME/logs"
-%s %s \
+%s%s %s \
   -- %s \
-  2>&1 | tee %s`, awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, shellEscapeArg(logFile))
+  2>&1 | tee %s`, getSandboxFilesystemMountsLoad(workflowData), awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, shellEscapeArg(logFile))
 		}
 	} else {
 		// Build the command without AWF wrapping
@@ -329,12 +331,12 @@ mkdir -p "$CODEX_HOME/logs"
 AGENT_CONTENT="$(awk 'BEGIN{skip=1} /^---$/{if(skip){skip=0;next}else{skip=1;next}} !skip' %s)"
 INSTRUCTION="$(printf "%%s\n\n%%s" "$AGENT_CONTENT" "$(cat "$GH_AW_PROMPT")")"
 mkdir -p "$CODEX_HOME/logs"
-%s %sexec%s%s%s"$INSTRUCTION" 2>&1 | tee %s`, agentPath, commandName, modelParam, webSearchParam, fullAutoParam, customArgsParam, logFile)
+%s %sexec%s%s%s%s"$INSTRUCTION" 2>&1 | tee %s`, agentPath, commandName, modelParam, webSearchParam, fullAutoParam, customArgsParam, resumeParam, logFile)
 		} else {
 			command = fmt.Sprintf(`set -o pipefail
 INSTRUCTION="$(cat "$GH_AW_PROMPT")"
 mkdir -p "$CODEX_HOME/logs"
-%s %sexec%s%s%s"$INSTRUCTION" 2>&1 | tee %s`, commandName, modelParam, webSearchParam, fullAutoParam, customArgsParam, logFile)
+%s %sexec%s%s%s%s"$INSTRUCTION" 2>&1 | tee %s`, commandName, modelParam, webSearchParam, fullAutoParam, customArgsParam, resumeParam, logFile)
 		}
 	}
 
@@ -371,13 +373,13 @@ mkdir -p "$CODEX_HOME/logs"
 	// Use different env vars for agent vs detection jobs
 	if !modelConfigured {
 		// Check if this is a detection job (has no SafeOutputs config)
-		isDetectionJob := workflowData.SafeOutputs == nil
+		isDetectionJob := isThreatDetectionData(workflowData)
 		if isDetectionJob {
 			// For detection, use detection-specific env var (no default fallback for Codex)
 			env[constants.EnvVarModelDetectionCodex] = fmt.Sprintf("${{ vars.%s || '' }}", constants.EnvVarModelDetectionCodex)
 		} else {
 			// For agent execution, use agent-specific env var
-			env[constants.EnvVarModelAgentCodex] = fmt.Sprintf("${{ vars.%s || '' }}", constants.EnvVarModelAgentCodex)
+			env[constants.EnvVarModelAgentCodex] = agentModelEnvValue(workflowData, constants.EnvVarModelAgentCodex)
 		}
 	}
 
@@ -377,7 +377,149 @@ func (e *CodexEngine) extractCodexTokenUsage(line string) int {
 	return 0
 }
 
+// ParseTranscript implements TranscriptParser for Codex logs. Each thinking section starts a
+// turn; the following agent messages, tool calls and exec commands belong to it.
+func (e *CodexEngine) ParseTranscript(logContent string, verbose bool) *Transcript {
+	codexLogsLog.Printf("Parsing Codex transcript: %d bytes", len(logContent))
+	builder := newTranscriptBuilder(e.GetID())
+
+	section := "" // Section the current text lines belong to: user, reasoning, assistant or result
+	var sectionLines []string
+	inThinkingSection := false
+
+	flushSection := func() {
+		text := strings.TrimSpace(strings.Join(sectionLines, "\n"))
+		switch section {
+		case TranscriptRoleUser:
+			builder.addPendingMessage(TranscriptRoleUser, text)
+		case TranscriptRoleReasoning, TranscriptRoleAssistant:
+			builder.addMessage(section, text)
+		case "result":
+			if call := builder.lastToolCall(); call != nil && call.Result == "" {
+				call.Result = codexResultText(text)
+			}
+		}
+		section = ""
+		sectionLines = nil
+	}
+
+	for _, line := range strings.Split(logContent, "\n") {
+		timestamp, rest, hasTimestamp := splitCodexTimestamp(line)
+		trimmed := strings.TrimSpace(rest)
+
+		switch {
+		case trimmed == "thinking":
+			flushSection()
+			if !inThinkingSection {
+				builder.startTurn(timestamp)
+				inThinkingSection = true
+			}
+			section = TranscriptRoleReasoning
+			continue
+		case trimmed == "codex":
+			flushSection()
+			section = TranscriptRoleAssistant
+			continue
+		case trimmed == "user" || trimmed == "User instructions:":
+			flushSection()
+			section = TranscriptRoleUser
+			continue
+		case strings.HasPrefix(trimmed, "tool ") && strings.Contains(trimmed, "("):
+			if match := codexToolCallNewFormat.FindStringSubmatch(trimmed); len(match) > 1 {
+				flushSection()
+				inThinkingSection = false
+				name := strings.TrimSpace(match[1])
+				args := strings.TrimSuffix(strings.TrimPrefix(trimmed, "tool "+match[1]+"("), ")")
+				builder.addToolCall(TranscriptToolCall{Name: name, Args: transcriptArgs(args)})
+				continue
+			}
+		case strings.HasPrefix(trimmed, "exec "):
+			if match := codexExecCommandNewFormat.FindStringSubmatch(trimmed); len(match) > 1 {
+				flushSection()
+				inThinkingSection = false
+				builder.addToolCall(TranscriptToolCall{Name: "bash", Args: transcriptArgs(map[string]string{"command": strings.TrimSpace(match[1])})})
+				continue
+			}
+		}
+
+		if match := codexResultPattern.FindStringSubmatch(trimmed); match != nil {
+			flushSection()
+			inThinkingSection = false
+			if call := builder.lastToolCall(); call != nil {
+				if duration, err := time.ParseDuration(match[4] + match[5]); err == nil {
+					call.Duration = duration
+				}
+				call.IsError = match[2] == "failure" || match[2] == "failed" || (match[3] != "" && match[3] != "0")
+			}
+			section = "result"
+			continue
+		}
+
+		if tokenUsage := e.extractCodexTokenUsage(trimmed); tokenUsage > 0 {
+			flushSection()
+			turn := builder.turn()
+			turn.Usage.TotalTokens += tokenUsage
+			continue
+		}
+
+		// Any other timestamped line is a new log event that ends the current section
+		if hasTimestamp {
+			flushSection()
+			continue
+		}
+		if section != "" {
+			sectionLines = append(sectionLines, rest)
+		}
+	}
+	flushSection()
+
+	return builder.build()
+}
+
+// splitCodexTimestamp splits the "[timestamp] " prefix of old format Codex log lines
+func splitCodexTimestamp(line string) (*time.Time, string, bool) {
+	if !strings.HasPrefix(line, "[") {
+		return nil, line, false
+	}
+	end := strings.Index(line, "] ")
+	if end == -1 {
+		end = strings.Index(line, "]")
+		if end == -1 || end != len(line)-1 {
+			return nil, line, false
+		}
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05"} {
+		if ts, err := time.Parse(layout, line[1:end]); err == nil {
+			return &ts, strings.TrimPrefix(line[end+1:], " "), true
+		}
+	}
+	return nil, line, false
+}
+
+// codexResultText returns the text content of an MCP tool result JSON block, or the raw
+// output for exec commands
+func codexResultText(output string) string {
+	var result map[string]any
+	if err := json.Unmarshal([]byte(output), &result); err == nil {
+		if content, ok := result["content"]; ok {
+			return transcriptContentText(content)
+		}
+	}
+	return output
+}
+
 // GetLogParserScriptId returns the JavaScript script name for parsing Codex logs
 func (e *CodexEngine) GetLogParserScriptId() string {
 	return "parse_codex_log"
 }
+
+// GetFallbackErrorPatterns returns the OpenAI API errors after which a fallback engine runs
+func (e *CodexEngine) GetFallbackErrorPatterns() []string {
+	return append(e.BaseEngine.GetFallbackErrorPatterns(),
+		"insufficient_quota",
+		"rate_limit_exceeded",
+		"invalid_api_key",
+		"401 Unauthorized",
+		"stream disconnected before completion",
+	)
+}
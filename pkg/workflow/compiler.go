@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 	"github.com/github/gh-aw/pkg/stringutil"
 )
@@ -156,6 +157,12 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate organization policy rules
+	log.Printf("Validating organization policy")
+	if err := c.validatePolicy(workflowData, markdownPath); err != nil {
+		return err
+	}
+
 	// Validate agent file exists if specified in engine config
 	log.Printf("Validating agent file if specified")
 	if err := c.validateAgentFile(workflowData, markdownPath); err != nil {
@@ -168,6 +175,18 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate safe-inputs tool schemas and defaults
+	log.Printf("Validating safe-inputs tool schemas")
+	if err := validateSafeInputs(workflowData.SafeInputs); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
+	// Validate MCP tool call quotas
+	log.Printf("Validating MCP tool limits")
+	if err := validateMCPToolLimits(workflowData.Tools); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate safe-outputs target configuration
 	log.Printf("Validating safe-outputs target fields")
 	if err := validateSafeOutputsTarget(workflowData.SafeOutputs); err != nil {
@@ -192,6 +211,12 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		return formatCompilerError(markdownPath, "error", err.Error(), err)
 	}
 
+	// Validate egress report configuration
+	log.Printf("Validating egress report configuration")
+	if err := validateEgressReportConfig(workflowData); err != nil {
+		return formatCompilerError(markdownPath, "error", err.Error(), err)
+	}
+
 	// Validate labels configuration
 	log.Printf("Validating labels")
 	if err := validateLabels(workflowData); err != nil {
@@ -236,6 +261,17 @@ func (c *Compiler) validateWorkflowData(workflowData *WorkflowData, markdownPath
 		c.IncrementWarningCount()
 	}
 
+	// Record/replay and call limits are only honored by gateways implementing the specification
+	// version that introduced them; the gateway version is verified when it starts
+	if getMCPCassetteConfig(workflowData) != nil {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Using experimental feature: MCP cassettes (requires an MCP gateway implementing MCP Gateway Specification %s or later)", constants.MCPGatewayCassetteSpecVersion)))
+		c.IncrementWarningCount()
+	}
+	if len(collectMCPToolLimits(workflowData)) > 0 {
+		fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Using experimental feature: MCP call limits (requires an MCP gateway implementing MCP Gateway Specification %s or later)", constants.MCPGatewayLimitsSpecVersion)))
+		c.IncrementWarningCount()
+	}
+
 	// Emit experimental warning for safe-inputs feature
 	if IsSafeInputsEnabled(workflowData.SafeInputs, workflowData) {
 		fmt.Fprintln(os.Stderr, console.FormatWarningMessage("Using experimental feature: safe-inputs"))
@@ -572,7 +608,15 @@ func (c *Compiler) CompileWorkflowData(workflowData *WorkflowData, markdownPath
 	}
 
 	// Write output
-	return c.writeWorkflowOutput(lockFile, yamlContent, markdownPath)
+	if err := c.writeWorkflowOutput(lockFile, yamlContent, markdownPath); err != nil {
+		return err
+	}
+
+	if err := c.writePromptReport(workflowData); err != nil {
+		return err
+	}
+
+	return c.writeSBOM(workflowData, lockFile, yamlContent)
 }
 
 // ParseWorkflowFile parses a markdown workflow file and extracts all necessary data
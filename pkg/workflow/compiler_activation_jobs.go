@@ -525,6 +525,12 @@ func (c *Compiler) buildActivationJob(data *WorkflowData, preActivationJobCreate
 		}
 	}
 
+	// Add the model selected by the engine model routing rules, read by the agent job
+	if data.EngineConfig != nil && data.EngineConfig.ModelRouting != nil {
+		compilerActivationJobsLog.Printf("Adding model routing output with %d rules", len(data.EngineConfig.ModelRouting.Rules))
+		outputs[modelRoutingOutput] = data.EngineConfig.ModelRouting.buildModelSelectionExpression(data.EngineConfig.ID)
+	}
+
 	// If no steps have been added, add a placeholder step to make the job valid
 	// This can happen when the activation job is created only for an if condition
 	if len(steps) == 0 {
@@ -88,6 +88,12 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 			c.IncrementWarningCount()
 		}
 		engineSetting = c.engineOverride
+		// The model of the markdown engine does not apply to another engine
+		if engineConfig != nil && engineConfig.ID != c.engineOverride && engineConfig.Model != "" && c.modelOverride == "" {
+			fmt.Fprintln(os.Stderr, console.FormatWarningMessage(fmt.Sprintf("Ignoring model %s of engine %s for --engine %s", engineConfig.Model, engineConfig.ID, c.engineOverride)))
+			c.IncrementWarningCount()
+			engineConfig.Model = ""
+		}
 	}
 
 	// Process imports from frontmatter first (before @include directives)
@@ -197,6 +203,16 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		}
 	}
 
+	// Override with command line model setting if provided
+	if c.modelOverride != "" {
+		if engineConfig == nil {
+			engineConfig = &EngineConfig{ID: engineSetting}
+		}
+		orchestratorEngineLog.Printf("Overriding model %q with %q", engineConfig.Model, c.modelOverride)
+		engineConfig.Model = c.modelOverride
+		engineConfig.ModelRouting = nil
+	}
+
 	// Validate the engine setting
 	orchestratorEngineLog.Printf("Validating engine setting: %s", engineSetting)
 	if err := c.validateEngine(engineSetting); err != nil {
@@ -204,6 +220,24 @@ func (c *Compiler) setupEngineAndImports(result *parser.FrontmatterResult, clean
 		return nil, err
 	}
 
+	// Validate the base URL of the openai engine
+	if err := validateOpenAIEngineConfig(engineConfig); err != nil {
+		orchestratorEngineLog.Printf("Engine configuration validation failed: %v", err)
+		return nil, err
+	}
+
+	// Validate the model routing rules, if any
+	if err := validateModelRouting(engineSetting, engineConfig); err != nil {
+		orchestratorEngineLog.Printf("Model routing validation failed: %v", err)
+		return nil, err
+	}
+
+	// Validate the fallback engines, if any
+	if err := c.validateEngineFallbacks(engineSetting, engineConfig); err != nil {
+		orchestratorEngineLog.Printf("Fallback engine validation failed: %v", err)
+		return nil, err
+	}
+
 	// Get the agentic engine instance
 	agenticEngine, err := c.getAgenticEngine(engineSetting)
 	if err != nil {
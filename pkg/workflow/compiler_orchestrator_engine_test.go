@@ -111,6 +111,51 @@ engine: copilot
 	assert.Equal(t, "claude", result.engineSetting)
 }
 
+// TestSetupEngineAndImports_ModelOverride tests that the model override replaces the model of the workflow
+// and that the markdown model is dropped when only the engine is overridden
+func TestSetupEngineAndImports_ModelOverride(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "model-override")
+
+	testContent := `---
+on: push
+engine:
+  id: copilot
+  model: gpt-5
+---
+
+# Test Workflow
+`
+
+	testFile := filepath.Join(tmpDir, "test.md")
+	require.NoError(t, os.WriteFile(testFile, []byte(testContent), 0644))
+
+	tests := []struct {
+		name          string
+		options       []CompilerOption
+		expectedModel string
+	}{
+		{name: "no override", expectedModel: "gpt-5"},
+		{name: "model override", options: []CompilerOption{WithModelOverride("gpt-5-mini")}, expectedModel: "gpt-5-mini"},
+		{name: "engine override drops markdown model", options: []CompilerOption{WithEngineOverride("claude")}, expectedModel: ""},
+		{name: "engine and model override", options: []CompilerOption{WithEngineOverride("claude"), WithModelOverride("claude-sonnet-4")}, expectedModel: "claude-sonnet-4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			compiler := NewCompiler(tt.options...)
+			content := []byte(testContent)
+
+			frontmatterResult, err := parser.ExtractFrontmatterFromContent(string(content))
+			require.NoError(t, err)
+
+			result, err := compiler.setupEngineAndImports(frontmatterResult, testFile, content, tmpDir)
+			require.NoError(t, err)
+			require.NotNil(t, result.engineConfig)
+			assert.Equal(t, tt.expectedModel, result.engineConfig.Model)
+		})
+	}
+}
+
 // TestSetupEngineAndImports_InvalidEngine tests error handling for invalid engine
 func TestSetupEngineAndImports_InvalidEngine(t *testing.T) {
 	tmpDir := testutil.TempDir(t, "engine-invalid")
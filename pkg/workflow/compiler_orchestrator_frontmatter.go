@@ -19,6 +19,7 @@ type frontmatterParseResult struct {
 	frontmatterForValidation map[string]any
 	markdownDir              string
 	isSharedWorkflow         bool
+	isTemplateWorkflow       bool
 }
 
 // parseFrontmatterSection reads the workflow file and parses its frontmatter.
@@ -60,6 +61,19 @@ func (c *Compiler) parseFrontmatterSection(markdownPath string) (*frontmatterPar
 		return nil, fmt.Errorf("no frontmatter found")
 	}
 
+	// Workflow templates are instantiated with 'gh aw instantiate' and never compiled directly.
+	// Their frontmatter contains unresolved input expressions, so schedule preprocessing and schema validation are skipped.
+	if IsWorkflowTemplate(result.Frontmatter) {
+		orchestratorFrontmatterLog.Printf("Workflow template detected: %s", cleanPath)
+		return &frontmatterParseResult{
+			cleanPath:          cleanPath,
+			content:            content,
+			frontmatterResult:  result,
+			markdownDir:        filepath.Dir(cleanPath),
+			isTemplateWorkflow: true,
+		}, nil
+	}
+
 	// Preprocess schedule fields to convert human-friendly format to cron expressions
 	if err := c.preprocessScheduleFields(result.Frontmatter, cleanPath, string(content)); err != nil {
 		orchestratorFrontmatterLog.Printf("Schedule preprocessing failed: %v", err)
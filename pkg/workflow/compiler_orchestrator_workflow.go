@@ -28,6 +28,11 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 		return nil, &SharedWorkflowError{Path: parseResult.cleanPath}
 	}
 
+	// Handle workflow templates
+	if parseResult.isTemplateWorkflow {
+		return nil, &TemplateWorkflowError{Path: parseResult.cleanPath}
+	}
+
 	// Unpack parse result for convenience
 	cleanPath := parseResult.cleanPath
 	content := parseResult.content
@@ -50,6 +55,8 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 	workflowData := c.buildInitialWorkflowData(result, toolsResult, engineSetup, engineSetup.importsResult)
 	// Store a stable workflow identifier derived from the file name.
 	workflowData.WorkflowID = GetWorkflowIDFromPath(cleanPath)
+	// Apply the MCP cassette override (gh aw trial --mcp-record / --mcp-replay)
+	c.applyMCPCassetteOverride(workflowData)
 
 	// Validate bash tool configuration BEFORE applying defaults
 	// This must happen before applyDefaults() which converts nil bash to default commands
@@ -109,6 +116,16 @@ func (c *Compiler) ParseWorkflowFile(markdownPath string) (*WorkflowData, error)
 		return nil, err
 	}
 
+	// Extract the phases after the default tools are applied, as the tools of each phase are validated against them
+	if err := c.extractPhasesConfig(result.Frontmatter, workflowData); err != nil {
+		return nil, fmt.Errorf("%s: %w", cleanPath, err)
+	}
+
+	// Extract the resume option after the on section and phases, as it adds a workflow_dispatch input
+	if err := c.extractResumeConfig(result.Frontmatter, workflowData); err != nil {
+		return nil, fmt.Errorf("%s: %w", cleanPath, err)
+	}
+
 	orchestratorWorkflowLog.Printf("Workflow file parsing completed successfully: %s", markdownPath)
 	return workflowData, nil
 }
@@ -71,6 +71,9 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 		steps = append(steps, patchDownloadSteps...)
 	}
 
+	// Add egress report download so the footers of the created items include the network egress of the agent
+	steps = append(steps, buildEgressReportDownloadSteps(data)...)
+
 	// Add shared checkout and git config steps for PR operations
 	// Both create-pull-request and push-to-pull-request-branch need these steps,
 	// so we add them once with a combined condition to avoid duplication
@@ -297,6 +300,9 @@ func (c *Compiler) buildConsolidatedSafeOutputsJob(data *WorkflowData, mainJobNa
 			insertIndex += len(patchDownloadSteps)
 		}
 
+		// Add egress report download steps count
+		insertIndex += len(buildEgressReportDownloadSteps(data))
+
 		// Insert app token steps
 		var newSteps []string
 		newSteps = append(newSteps, steps[:insertIndex]...)
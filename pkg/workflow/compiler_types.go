@@ -22,6 +22,11 @@ func WithEngineOverride(engine string) CompilerOption {
 	return func(c *Compiler) { c.engineOverride = engine }
 }
 
+// WithModelOverride sets the AI model override
+func WithModelOverride(model string) CompilerOption {
+	return func(c *Compiler) { c.modelOverride = model }
+}
+
 // WithCustomOutput sets a custom output path for the compiled workflow
 func WithCustomOutput(path string) CompilerOption {
 	return func(c *Compiler) { c.customOutput = path }
@@ -77,6 +82,16 @@ func WithGitRoot(gitRoot string) CompilerOption {
 	return func(c *Compiler) { c.gitRoot = gitRoot }
 }
 
+// WithPolicy sets the organization policy evaluated against every compiled workflow
+func WithPolicy(policy *Policy) CompilerOption {
+	return func(c *Compiler) { c.policy = policy }
+}
+
+// WithSBOMFormat enables writing a software bill of materials next to each lock file
+func WithSBOMFormat(format SBOMFormat) CompilerOption {
+	return func(c *Compiler) { c.sbomFormat = format }
+}
+
 // FileTracker interface for tracking files created during compilation
 type FileTracker interface {
 	TrackCreated(filePath string)
@@ -102,35 +117,40 @@ type Compiler struct {
 	verbose                 bool
 	quiet                   bool // If true, suppress success messages (for interactive mode)
 	engineOverride          string
-	customOutput            string              // If set, output will be written to this path instead of default location
-	version                 string              // Version of the extension
-	skipValidation          bool                // If true, skip schema validation
-	noEmit                  bool                // If true, validate without generating lock files
-	strictMode              bool                // If true, enforce strict validation requirements
-	trialMode               bool                // If true, suppress safe outputs for trial mode execution
-	trialLogicalRepoSlug    string              // If set in trial mode, the logical repository to checkout
-	refreshStopTime         bool                // If true, regenerate stop-after times instead of preserving existing ones
-	forceRefreshActionPins  bool                // If true, clear action cache and resolve all actions from GitHub API
-	failFast                bool                // If true, stop at first validation error instead of collecting all errors
-	actionCacheCleared      bool                // Tracks if action cache has already been cleared (for forceRefreshActionPins)
-	markdownPath            string              // Path to the markdown file being compiled (for context in dynamic tool generation)
-	actionMode              ActionMode          // Mode for generating JavaScript steps (inline vs custom actions)
-	actionTag               string              // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
-	jobManager              *JobManager         // Manages jobs and dependencies
-	engineRegistry          *EngineRegistry     // Registry of available agentic engines
-	fileTracker             FileTracker         // Optional file tracker for tracking created files
-	warningCount            int                 // Number of warnings encountered during compilation
-	stepOrderTracker        *StepOrderTracker   // Tracks step ordering for validation
-	actionCache             *ActionCache        // Shared cache for action pin resolutions across all workflows
-	actionResolver          *ActionResolver     // Shared resolver for action pins across all workflows
-	actionPinWarnings       map[string]bool     // Shared cache of already-warned action pin failures (key: "repo@version")
-	importCache             *parser.ImportCache // Shared cache for imported workflow files
-	workflowIdentifier      string              // Identifier for the current workflow being compiled (for schedule scattering)
-	scheduleWarnings        []string            // Accumulated schedule warnings for this compiler instance
-	repositorySlug          string              // Repository slug (owner/repo) used as seed for scattering
-	artifactManager         *ArtifactManager    // Tracks artifact uploads/downloads for validation
-	scheduleFriendlyFormats map[int]string      // Maps schedule item index to friendly format string for current workflow
-	gitRoot                 string              // Git repository root directory (if set, used for action cache path)
+	modelOverride           string               // If set, overrides the model of the workflow engine
+	customOutput            string               // If set, output will be written to this path instead of default location
+	version                 string               // Version of the extension
+	skipValidation          bool                 // If true, skip schema validation
+	noEmit                  bool                 // If true, validate without generating lock files
+	strictMode              bool                 // If true, enforce strict validation requirements
+	trialMode               bool                 // If true, suppress safe outputs for trial mode execution
+	trialLogicalRepoSlug    string               // If set in trial mode, the logical repository to checkout
+	refreshStopTime         bool                 // If true, regenerate stop-after times instead of preserving existing ones
+	forceRefreshActionPins  bool                 // If true, clear action cache and resolve all actions from GitHub API
+	failFast                bool                 // If true, stop at first validation error instead of collecting all errors
+	actionCacheCleared      bool                 // Tracks if action cache has already been cleared (for forceRefreshActionPins)
+	markdownPath            string               // Path to the markdown file being compiled (for context in dynamic tool generation)
+	actionMode              ActionMode           // Mode for generating JavaScript steps (inline vs custom actions)
+	actionTag               string               // Override action SHA or tag for actions/setup (when set, overrides actionMode to release)
+	jobManager              *JobManager          // Manages jobs and dependencies
+	engineRegistry          *EngineRegistry      // Registry of available agentic engines
+	fileTracker             FileTracker          // Optional file tracker for tracking created files
+	warningCount            int                  // Number of warnings encountered during compilation
+	stepOrderTracker        *StepOrderTracker    // Tracks step ordering for validation
+	actionCache             *ActionCache         // Shared cache for action pin resolutions across all workflows
+	actionResolver          *ActionResolver      // Shared resolver for action pins across all workflows
+	actionPinWarnings       map[string]bool      // Shared cache of already-warned action pin failures (key: "repo@version")
+	importCache             *parser.ImportCache  // Shared cache for imported workflow files
+	workflowIdentifier      string               // Identifier for the current workflow being compiled (for schedule scattering)
+	scheduleWarnings        []string             // Accumulated schedule warnings for this compiler instance
+	repositorySlug          string               // Repository slug (owner/repo) used as seed for scattering
+	artifactManager         *ArtifactManager     // Tracks artifact uploads/downloads for validation
+	scheduleFriendlyFormats map[int]string       // Maps schedule item index to friendly format string for current workflow
+	gitRoot                 string               // Git repository root directory (if set, used for action cache path)
+	policy                  *Policy              // Organization policy evaluated against each workflow (nil = no policy)
+	sbomFormat              SBOMFormat           // If set, write a <workflow>.sbom.json file in this format next to each lock file
+	mcpCassette             *MCPCassetteConfig   // If set, overrides the MCP gateway cassette configuration of every workflow
+	promptReport            *PromptReportOptions // If set, print a prompt size report for each workflow
 }
 
 // NewCompiler creates a new workflow compiler with functional options.
@@ -208,11 +228,31 @@ func (c *Compiler) SetTrialLogicalRepoSlug(repo string) {
 	c.trialLogicalRepoSlug = repo
 }
 
+// SetMCPCassette overrides the MCP gateway record/replay configuration of compiled workflows
+func (c *Compiler) SetMCPCassette(cassette *MCPCassetteConfig) {
+	c.mcpCassette = cassette
+}
+
 // SetStrictMode configures whether to enable strict validation mode
 func (c *Compiler) SetStrictMode(strict bool) {
 	c.strictMode = strict
 }
 
+// SetPolicy configures the organization policy evaluated against each workflow
+func (c *Compiler) SetPolicy(policy *Policy) {
+	c.policy = policy
+}
+
+// SetSBOMFormat configures the format of the SBOM written next to each lock file (empty disables it)
+func (c *Compiler) SetSBOMFormat(format SBOMFormat) {
+	c.sbomFormat = format
+}
+
+// SetPromptReport enables the prompt size report printed for each compiled workflow (nil disables it)
+func (c *Compiler) SetPromptReport(options *PromptReportOptions) {
+	c.promptReport = options
+}
+
 // SetRefreshStopTime configures whether to force regeneration of stop-after times
 func (c *Compiler) SetRefreshStopTime(refresh bool) {
 	c.refreshStopTime = refresh
@@ -408,11 +448,14 @@ type WorkflowData struct {
 	Tools                 map[string]any
 	ParsedTools           *Tools // Structured tools configuration (NEW: parsed from Tools map)
 	MarkdownContent       string
-	AI                    string        // "claude" or "codex" (for backwards compatibility)
-	EngineConfig          *EngineConfig // Extended engine configuration
-	AgentFile             string        // Path to custom agent file (from imports)
-	AgentImportSpec       string        // Original import specification for agent file (e.g., "owner/repo/path@ref")
-	RepositoryImports     []string      // Repository-only imports (format: "owner/repo@ref") for .github folder merging
+	AI                    string         // "claude" or "codex" (for backwards compatibility)
+	EngineConfig          *EngineConfig  // Extended engine configuration
+	ThreatDetection       bool           // engine steps are generated for the threat detection job
+	Phases                []*PhaseConfig // phases of a multi-phase agent pipeline (nil runs the agent once)
+	Resume                bool           // checkpoint the engine session and accept a run to resume from (resume: true)
+	AgentFile             string         // Path to custom agent file (from imports)
+	AgentImportSpec       string         // Original import specification for agent file (e.g., "owner/repo/path@ref")
+	RepositoryImports     []string       // Repository-only imports (format: "owner/repo@ref") for .github folder merging
 	StopTime              string
 	SkipIfMatch           *SkipIfMatchConfig   // skip-if-match configuration with query and max threshold
 	SkipIfNoMatch         *SkipIfNoMatchConfig // skip-if-no-match configuration with query and min threshold
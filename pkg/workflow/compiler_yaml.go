@@ -389,10 +389,36 @@ func (c *Compiler) generatePostSteps(yaml *strings.Builder, data *WorkflowData)
 	}
 }
 
+// agentModelEnvVar returns the environment variable configuring the default agent model of an engine
+func agentModelEnvVar(engineID string) string {
+	switch engineID {
+	case "copilot":
+		return constants.EnvVarModelAgentCopilot
+	case "claude":
+		return constants.EnvVarModelAgentClaude
+	case "codex":
+		return constants.EnvVarModelAgentCodex
+	case "openai":
+		return constants.EnvVarModelAgentOpenAI
+	case "custom":
+		return constants.EnvVarModelAgentCustom
+	default:
+		// For unknown engines, use a generic environment variable pattern
+		// This provides a fallback while maintaining consistency
+		return constants.EnvVarModelAgentCustom
+	}
+}
+
 func (c *Compiler) generateCreateAwInfo(yaml *strings.Builder, data *WorkflowData, engine CodingAgentEngine) {
 	yaml.WriteString("      - name: Generate agentic run info\n")
 	yaml.WriteString("        id: generate_aw_info\n") // Add ID for outputs
 	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	if data.EngineConfig != nil && data.EngineConfig.ModelRouting != nil {
+		// Record the model selected by the routing rules in the activation job
+		modelEnvVar := agentModelEnvVar(engine.GetID())
+		yaml.WriteString("        env:\n")
+		fmt.Fprintf(yaml, "          %s: %s\n", modelEnvVar, agentModelEnvValue(data, modelEnvVar))
+	}
 	yaml.WriteString("        with:\n")
 	yaml.WriteString("          script: |\n")
 	yaml.WriteString("            const fs = require('fs');\n")
@@ -422,22 +448,7 @@ func (c *Compiler) generateCreateAwInfo(yaml *strings.Builder, data *WorkflowDat
 	} else {
 		// Model from environment variable - resolve at runtime
 		// Use agent-specific env var since aw_info is generated in agent job
-		var modelEnvVar string
-
-		switch engineID {
-		case "copilot":
-			modelEnvVar = constants.EnvVarModelAgentCopilot
-		case "claude":
-			modelEnvVar = constants.EnvVarModelAgentClaude
-		case "codex":
-			modelEnvVar = constants.EnvVarModelAgentCodex
-		case "custom":
-			modelEnvVar = constants.EnvVarModelAgentCustom
-		default:
-			// For unknown engines, use a generic environment variable pattern
-			// This provides a fallback while maintaining consistency
-			modelEnvVar = constants.EnvVarModelAgentCustom
-		}
+		modelEnvVar := agentModelEnvVar(engineID)
 
 		// Generate JavaScript to resolve model from environment variable at runtime
 		fmt.Fprintf(yaml, "              model: process.env.%s || \"\",\n", modelEnvVar)
@@ -519,6 +530,16 @@ func (c *Compiler) generateCreateAwInfo(yaml *strings.Builder, data *WorkflowDat
 	}
 	fmt.Fprintf(yaml, "              awmg_version: \"%s\",\n", mcpGatewayVersion)
 
+	// Sandbox filesystem policy, used by gh aw audit to report access to protected paths
+	if policy := getSandboxFilesystemConfig(data); policy != nil {
+		policyJSON, _ := json.Marshal(map[string][]string{
+			"read_only": policy.ReadOnly,
+			"writable":  policy.Writable,
+			"denied":    policy.Denied,
+		})
+		fmt.Fprintf(yaml, "              sandbox_filesystem: %s,\n", string(policyJSON))
+	}
+
 	// Add steps object with firewall information
 	yaml.WriteString("              steps: {\n")
 
@@ -19,6 +19,11 @@ func (c *Compiler) generateEngineExecutionSteps(yaml *strings.Builder, data *Wor
 
 // generateLogParsing generates a step that parses the agent's logs and adds them to the step summary
 func (c *Compiler) generateLogParsing(yaml *strings.Builder, engine CodingAgentEngine) {
+	c.generateLogParsingStep(yaml, engine, "always()", engine.GetLogFileForParsing())
+}
+
+// generateLogParsingStep generates the step parsing the given log of an engine that runs under the given condition
+func (c *Compiler) generateLogParsingStep(yaml *strings.Builder, engine CodingAgentEngine, condition string, logFileForParsing string) {
 	parserScriptName := engine.GetLogParserScriptId()
 	if parserScriptName == "" {
 		// Skip log parsing if engine doesn't provide a parser
@@ -35,11 +40,8 @@ func (c *Compiler) generateLogParsing(yaml *strings.Builder, engine CodingAgentE
 		return
 	}
 
-	// Get the log file path for parsing (may be different from stdout/stderr log)
-	logFileForParsing := engine.GetLogFileForParsing()
-
 	yaml.WriteString("      - name: Parse agent logs for step summary\n")
-	yaml.WriteString("        if: always()\n")
+	fmt.Fprintf(yaml, "        if: %s\n", condition)
 	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
 	yaml.WriteString("        env:\n")
 	fmt.Fprintf(yaml, "          GH_AW_AGENT_OUTPUT: %s\n", logFileForParsing)
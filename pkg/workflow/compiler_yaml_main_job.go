@@ -251,9 +251,33 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 		yaml.WriteString(line)
 	}
 
-	// Add AI execution step using the agentic engine
+	// Restore the engine session of the run to resume from (resume: true)
+	c.generateResumeCheckpointRestore(yaml, data)
+
+	// Upload the engine session in the background while the engine runs (resume: true)
+	c.generateResumeCheckpointUploader(yaml, data, engine)
+
+	// Prepare the AWF mounts of the sandbox filesystem policy (sandbox.filesystem)
+	c.generateSandboxFilesystemSetup(yaml, data)
+
+	// Add AI execution step using the agentic engine, followed by the fallback engines if configured,
+	// or one execution per phase for multi-phase workflows
+	engineAttempts, err := c.buildEngineAttempts(data, engine)
+	if err != nil {
+		return err
+	}
+	phaseRuns, err := c.buildPhaseRuns(data)
+	if err != nil {
+		return err
+	}
 	compilerYamlLog.Printf("Generating engine execution steps for %s", engine.GetID())
-	c.generateEngineExecutionSteps(yaml, data, engine, logFileFull)
+	if len(phaseRuns) > 0 {
+		c.generatePhaseExecutionSteps(yaml, data, phaseRuns, logFileFull)
+	} else if len(engineAttempts) > 1 {
+		c.generateEngineExecutionStepsWithFallback(yaml, engineAttempts, logFileFull)
+	} else {
+		c.generateEngineExecutionSteps(yaml, data, engine, logFileFull)
+	}
 
 	// Mark that we've completed agent execution - step order validation starts from here
 	compilerYamlLog.Print("Marking agent execution as complete for step order tracking")
@@ -310,6 +334,12 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	// This ensures all artifacts are scanned for secrets before being uploaded
 	c.generateSecretRedactionStep(yaml, yaml.String(), data)
 
+	// Upload the recorded MCP cassette (sandbox.mcp.cassette.mode: record)
+	c.generateMCPCassetteUpload(yaml, data)
+
+	// Upload the engine session checkpoint (resume: true)
+	c.generateResumeCheckpointUpload(yaml, data)
+
 	// Add output collection step only if safe-outputs feature is used (GH_AW_SAFE_OUTPUTS functionality)
 	if data.SafeOutputs != nil {
 		c.generateOutputCollectionStep(yaml, data)
@@ -333,7 +363,13 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	}
 
 	// parse agent logs for GITHUB_STEP_SUMMARY
-	c.generateLogParsing(yaml, engine)
+	if len(phaseRuns) > 0 {
+		c.generatePhaseLogParsing(yaml, phaseRuns, logFileFull)
+	} else if len(engineAttempts) > 1 {
+		c.generateLogParsingWithFallback(yaml, engineAttempts)
+	} else {
+		c.generateLogParsing(yaml, engine)
+	}
 
 	// parse safe-inputs logs for GITHUB_STEP_SUMMARY (if safe-inputs is enabled)
 	if IsSafeInputsEnabled(data.SafeInputs, data) {
@@ -345,7 +381,7 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	c.generateMCPGatewayLogParsing(yaml)
 
 	// Add firewall log parsing steps (but not upload - collected for unified upload)
-	// For Copilot, Codex, and Claude engines
+	// For Copilot, Codex, Claude, and OpenAI-compatible engines
 	if _, ok := engine.(*CopilotEngine); ok {
 		if isFirewallEnabled(data) {
 			firewallLogParsing := generateFirewallLogParsingStep(data.Name)
@@ -376,9 +412,27 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 			artifactPaths = append(artifactPaths, "/tmp/gh-aw/sandbox/firewall/logs/")
 		}
 	}
+	if _, ok := engine.(*OpenAIEngine); ok {
+		if isFirewallEnabled(data) {
+			firewallLogParsing := generateFirewallLogParsingStep(data.Name)
+			for _, line := range firewallLogParsing {
+				yaml.WriteString(line + "\n")
+			}
+			// Collect firewall logs path for unified upload
+			artifactPaths = append(artifactPaths, "/tmp/gh-aw/sandbox/firewall/logs/")
+		}
+	}
 
 	// Collect agent stdio logs path for unified upload
 	artifactPaths = append(artifactPaths, logFileFull)
+	if len(engineAttempts) > 1 {
+		// Logs of the engines that failed before the fallback engine ran
+		artifactPaths = append(artifactPaths, "/tmp/gh-aw/agent-stdio.attempt-*.log")
+	}
+	if len(phaseRuns) > 0 {
+		// Prompts, logs, outputs and vetoes of the phases
+		artifactPaths = append(artifactPaths, phasesDir+"/")
+	}
 
 	// Collect agent-generated files path for unified upload
 	// This directory is used by workflows that instruct the agent to write files
@@ -419,6 +473,9 @@ func (c *Compiler) generateMainJobSteps(yaml *strings.Builder, data *WorkflowDat
 	// Add post-steps (if any) after AI execution
 	c.generatePostSteps(yaml, data)
 
+	// Summarize the network egress of the agent from the firewall logs
+	c.generateEgressReportSteps(yaml, data)
+
 	// Generate single unified artifact upload with all collected paths
 	c.generateUnifiedArtifactUpload(yaml, artifactPaths)
 
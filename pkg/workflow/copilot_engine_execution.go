@@ -151,7 +151,7 @@ func (e *CopilotEngine) GetExecutionSteps(workflowData *WorkflowData, logFile st
 	// Determine if we need to conditionally add --model flag based on environment variable
 	needsModelFlag := !modelConfigured
 	// Check if this is a detection job (has no SafeOutputs config)
-	isDetectionJob := workflowData.SafeOutputs == nil
+	isDetectionJob := isThreatDetectionData(workflowData)
 	var modelEnvVar string
 	if isDetectionJob {
 		modelEnvVar = constants.EnvVarModelDetectionCopilot
@@ -281,6 +281,9 @@ func (e *CopilotEngine) GetExecutionSteps(workflowData *WorkflowData, logFile st
 			copilotExecLog.Printf("Added %d custom mounts from agent config", len(sortedMounts))
 		}
 
+		// Add the mounts enforcing the sandbox filesystem policy (sandbox.filesystem)
+		awfArgs = append(awfArgs, getSandboxFilesystemMountArgs(workflowData)...)
+
 		awfArgs = append(awfArgs, "--allow-domains", allowedDomains)
 
 		// Add blocked domains if specified
@@ -351,9 +354,9 @@ func (e *CopilotEngine) GetExecutionSteps(workflowData *WorkflowData, logFile st
 		escapedCommand := shellEscapeArg(copilotCommand)
 
 		command = fmt.Sprintf(`set -o pipefail
-%s %s \
+%s%s %s \
   -- %s \
-  2>&1 | tee %s`, awfCommand, shellJoinArgs(awfArgs), escapedCommand, shellEscapeArg(logFile))
+  2>&1 | tee %s`, getSandboxFilesystemMountsLoad(workflowData), awfCommand, shellJoinArgs(awfArgs), escapedCommand, shellEscapeArg(logFile))
 	} else {
 		// Run copilot command without AWF wrapper
 		command = fmt.Sprintf(`set -o pipefail
@@ -420,13 +423,13 @@ COPILOT_CLI_INSTRUCTION="$(cat /tmp/gh-aw/aw-prompts/prompt.txt)"
 	// Use different env vars for agent vs detection jobs
 	if workflowData.EngineConfig == nil || workflowData.EngineConfig.Model == "" {
 		// Check if this is a detection job (has no SafeOutputs config)
-		isDetectionJob := workflowData.SafeOutputs == nil
+		isDetectionJob := isThreatDetectionData(workflowData)
 		if isDetectionJob {
 			// For detection, use detection-specific env var (no builtin default, CLI will use its own)
 			env[constants.EnvVarModelDetectionCopilot] = fmt.Sprintf("${{ vars.%s || '' }}", constants.EnvVarModelDetectionCopilot)
 		} else {
 			// For agent execution, use agent-specific env var
-			env[constants.EnvVarModelAgentCopilot] = fmt.Sprintf("${{ vars.%s || '' }}", constants.EnvVarModelAgentCopilot)
+			env[constants.EnvVarModelAgentCopilot] = agentModelEnvValue(workflowData, constants.EnvVarModelAgentCopilot)
 		}
 	}
 
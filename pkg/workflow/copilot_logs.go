@@ -3,6 +3,7 @@ package workflow
 import (
 	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -331,6 +332,142 @@ func (e *CopilotEngine) ParseLogMetrics(logContent string, verbose bool) LogMetr
 	return metrics
 }
 
+// ParseTranscript implements TranscriptParser for Copilot CLI logs. Session JSONL files use
+// the same stream-json entries as Claude; debug logs contribute one turn per model response.
+func (e *CopilotEngine) ParseTranscript(logContent string, verbose bool) *Transcript {
+	copilotLogsLog.Printf("Parsing Copilot transcript: %d bytes", len(logContent))
+	builder := newTranscriptBuilder(e.GetID())
+
+	var entries []map[string]any
+	for line := range strings.SplitSeq(logContent, "\n") {
+		trimmedLine := strings.TrimSpace(line)
+		if !strings.HasPrefix(trimmedLine, "{") {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(trimmedLine), &entry); err != nil {
+			continue
+		}
+		if _, ok := entry["type"].(string); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) > 0 {
+		copilotLogsLog.Printf("Building transcript from %d session entries", len(entries))
+		parseStreamJSONTranscript(builder, entries)
+		return builder.build()
+	}
+
+	for _, block := range extractCopilotDebugDataBlocks(logContent) {
+		var data map[string]any
+		if err := json.Unmarshal([]byte(block.JSON), &data); err != nil {
+			if verbose {
+				copilotLogsLog.Printf("Skipping unparsable data block: %v", err)
+			}
+			continue
+		}
+		turn := builder.startTurn(block.Timestamp)
+		if usage, ok := data["usage"].(map[string]any); ok {
+			turn.Usage = TranscriptUsage{
+				InputTokens:  ConvertToInt(usage["prompt_tokens"]),
+				OutputTokens: ConvertToInt(usage["completion_tokens"]),
+			}
+			turn.Usage.TotalTokens = turn.Usage.InputTokens + turn.Usage.OutputTokens
+		}
+
+		var messages []map[string]any
+		if choices, ok := data["choices"].([]any); ok {
+			for _, choice := range choices {
+				if choiceMap, ok := choice.(map[string]any); ok {
+					if message, ok := choiceMap["message"].(map[string]any); ok {
+						messages = append(messages, message)
+					}
+				}
+			}
+		}
+		if message, ok := data["message"].(map[string]any); ok {
+			messages = append(messages, message)
+		}
+		for _, message := range messages {
+			if text, ok := message["content"].(string); ok {
+				builder.addMessage(TranscriptRoleAssistant, text)
+			}
+			toolCalls, _ := message["tool_calls"].([]any)
+			for _, toolCall := range toolCalls {
+				tcMap, ok := toolCall.(map[string]any)
+				if !ok {
+					continue
+				}
+				function, ok := tcMap["function"].(map[string]any)
+				if !ok {
+					continue
+				}
+				id, _ := tcMap["id"].(string)
+				name, _ := function["name"].(string)
+				builder.addToolCall(TranscriptToolCall{ID: id, Name: name, Args: transcriptArgs(function["arguments"])})
+			}
+		}
+	}
+
+	return builder.build()
+}
+
+// copilotDataBlock is a JSON response logged by the Copilot CLI in its debug log
+type copilotDataBlock struct {
+	Timestamp *time.Time
+	JSON      string
+}
+
+// extractCopilotDebugDataBlocks returns the JSON blocks following "[DEBUG] data:" lines.
+// Each line of a block carries a timestamp and [DEBUG] prefix, which is stripped.
+func extractCopilotDebugDataBlocks(logContent string) []copilotDataBlock {
+	var blocks []copilotDataBlock
+	var current *copilotDataBlock
+	var jsonLines []string
+
+	closeBlock := func() {
+		if current != nil && len(jsonLines) > 0 {
+			current.JSON = strings.Join(jsonLines, "\n")
+			blocks = append(blocks, *current)
+		}
+		current = nil
+		jsonLines = nil
+	}
+
+	for line := range strings.SplitSeq(logContent, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if debugIndex := strings.Index(line, "[DEBUG] data:"); debugIndex != -1 {
+			closeBlock()
+			current = &copilotDataBlock{}
+			if ts, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(line[:debugIndex])); err == nil {
+				current.Timestamp = &ts
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		debugIndex := strings.Index(line, "[DEBUG]")
+		if debugIndex == -1 {
+			jsonLines = append(jsonLines, line)
+			continue
+		}
+		cleanLine := strings.TrimSpace(line[debugIndex+7:])
+		if strings.HasPrefix(cleanLine, "{") || strings.HasPrefix(cleanLine, "}") ||
+			strings.HasPrefix(cleanLine, "[") || strings.HasPrefix(cleanLine, "]") ||
+			strings.HasPrefix(cleanLine, "\"") {
+			jsonLines = append(jsonLines, cleanLine)
+			continue
+		}
+		closeBlock()
+	}
+	closeBlock()
+
+	return blocks
+}
+
 // extractToolCallSizes extracts tool call input and output sizes from Copilot JSON responses
 func (e *CopilotEngine) extractToolCallSizes(jsonStr string, toolCallMap map[string]*ToolCallInfo, verbose bool) {
 	// Try to parse the JSON string
@@ -428,6 +565,17 @@ func (e *CopilotEngine) GetLogParserScriptId() string {
 	return "parse_copilot_log"
 }
 
+// GetFallbackErrorPatterns returns the Copilot API errors after which a fallback engine runs
+func (e *CopilotEngine) GetFallbackErrorPatterns() []string {
+	return append(e.BaseEngine.GetFallbackErrorPatterns(),
+		"quota exceeded",
+		"exceeded your (monthly|premium) (request|quota)",
+		"402 Payment Required",
+		"not (enabled|authorized) (for|to use) copilot",
+		"401 Unauthorized",
+	)
+}
+
 // GetLogFileForParsing returns the log directory for Copilot CLI logs
 // Copilot writes detailed debug logs to /tmp/gh-aw/sandbox/agent/logs/
 func (e *CopilotEngine) GetLogFileForParsing() string {
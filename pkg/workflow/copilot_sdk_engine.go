@@ -116,7 +116,7 @@ func (e *CopilotSDKEngine) GetExecutionSteps(workflowData *WorkflowData, logFile
 	var steps []GitHubActionStep
 
 	// Step 1: Start Copilot CLI in headless mode
-	steps = append(steps, e.generateCopilotHeadlessStep())
+	steps = append(steps, e.generateCopilotHeadlessStep(workflowData))
 
 	// Step 2: Prepare copilot-client configuration
 	steps = append(steps, e.generateConfigurationStep(workflowData))
@@ -128,7 +128,8 @@ func (e *CopilotSDKEngine) GetExecutionSteps(workflowData *WorkflowData, logFile
 }
 
 // generateCopilotHeadlessStep creates a step to start Copilot CLI in headless mode
-func (e *CopilotSDKEngine) generateCopilotHeadlessStep() GitHubActionStep {
+// The CLI connects to the MCP servers, so it receives the MCP timeouts like the Copilot engine
+func (e *CopilotSDKEngine) generateCopilotHeadlessStep(workflowData *WorkflowData) GitHubActionStep {
 	var stepLines []string
 	stepLines = append(stepLines, "      - name: Start Copilot CLI in headless mode")
 	stepLines = append(stepLines, "        run: |")
@@ -148,6 +149,16 @@ func (e *CopilotSDKEngine) generateCopilotHeadlessStep() GitHubActionStep {
 	stepLines = append(stepLines, "          ")
 	stepLines = append(stepLines, fmt.Sprintf("          echo \"✓ Copilot CLI started in headless mode on port %d\"", constants.CopilotSDKLLMGatewayPort))
 
+	if workflowData.ToolsStartupTimeout > 0 || workflowData.ToolsTimeout > 0 {
+		stepLines = append(stepLines, "        env:")
+		if workflowData.ToolsStartupTimeout > 0 {
+			stepLines = append(stepLines, fmt.Sprintf("          GH_AW_STARTUP_TIMEOUT: %d", workflowData.ToolsStartupTimeout))
+		}
+		if workflowData.ToolsTimeout > 0 {
+			stepLines = append(stepLines, fmt.Sprintf("          GH_AW_TOOL_TIMEOUT: %d", workflowData.ToolsTimeout))
+		}
+	}
+
 	return GitHubActionStep(stepLines)
 }
 
@@ -228,13 +239,15 @@ func (e *CopilotSDKEngine) RenderMCPConfig(yaml *strings.Builder, tools map[stri
 	var tempBuilder strings.Builder
 	copilotEngine.RenderMCPConfig(&tempBuilder, tools, mcpTools, workflowData)
 
-	// Replace localhost with Docker internal host domain
-	config := tempBuilder.String()
-	config = strings.ReplaceAll(config, "localhost", "host.docker.internal")
-	config = strings.ReplaceAll(config, "127.0.0.1", "host.docker.internal")
-
-	// Write to the output builder
-	yaml.WriteString(config)
+	// Replace localhost with Docker internal host domain. Container images are left untouched:
+	// "localhost/gh-aw:dev" names a local image, not a host.
+	for line := range strings.SplitAfterSeq(tempBuilder.String(), "\n") {
+		if !strings.Contains(line, "\"container\":") {
+			line = strings.ReplaceAll(line, "localhost", "host.docker.internal")
+			line = strings.ReplaceAll(line, "127.0.0.1", "host.docker.internal")
+		}
+		yaml.WriteString(line)
+	}
 }
 
 // ParseLogMetrics parses log metrics from the Copilot SDK engine output
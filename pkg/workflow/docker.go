@@ -179,6 +179,17 @@ func collectDockerImages(tools map[string]any, workflowData *WorkflowData, actio
 		}
 	}
 
+	// Collect images of safe-inputs container tools so they are pulled before the agent runs
+	if workflowData != nil {
+		for _, image := range collectSafeInputsContainerImages(workflowData.SafeInputs) {
+			if !imageSet[image] {
+				images = append(images, image)
+				imageSet[image] = true
+				dockerLog.Printf("Added safe-inputs container tool image: %s", image)
+			}
+		}
+	}
+
 	// Sort for stable output
 	sort.Strings(images)
 	dockerLog.Printf("Collected %d Docker images from tools", len(images))
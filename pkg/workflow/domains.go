@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/constants"
@@ -41,6 +42,12 @@ var CodexDefaultDomains = []string{
 	"openai.com",
 }
 
+// OpenAIDefaultDomains are the default domains required by the openai engine. The host of
+// the configured base-url is added to them when the allowed domains are computed.
+var OpenAIDefaultDomains = []string{
+	"host.docker.internal",
+}
+
 // ClaudeDefaultDomains are the default domains required for Claude Code CLI authentication and operation
 var ClaudeDefaultDomains = []string{
 	"*.githubusercontent.com",
@@ -451,6 +458,17 @@ func GetCodexAllowedDomainsWithToolsAndRuntimes(network *NetworkPermissions, too
 	return mergeDomainsWithNetworkToolsAndRuntimes(CodexDefaultDomains, network, tools, runtimes)
 }
 
+// GetOpenAIAllowedDomainsWithToolsAndRuntimes merges the openai engine default domains and the host of the
+// chat completions base URL with NetworkPermissions, HTTP MCP server domains, and runtime ecosystem domains
+// Returns a deduplicated, sorted, comma-separated string suitable for AWF's --allow-domains flag
+func GetOpenAIAllowedDomainsWithToolsAndRuntimes(baseURL string, network *NetworkPermissions, tools map[string]any, runtimes map[string]any) string {
+	defaultDomains := OpenAIDefaultDomains
+	if domain := stringutil.ExtractDomainFromURL(baseURL); domain != "" {
+		defaultDomains = append(slices.Clone(OpenAIDefaultDomains), domain)
+	}
+	return mergeDomainsWithNetworkToolsAndRuntimes(defaultDomains, network, tools, runtimes)
+}
+
 // GetClaudeAllowedDomains merges Claude default domains with NetworkPermissions allowed domains
 // Returns a deduplicated, sorted, comma-separated string suitable for AWF's --allow-domains flag
 func GetClaudeAllowedDomains(network *NetworkPermissions) string {
@@ -564,6 +582,12 @@ func (c *Compiler) computeAllowedDomainsForSanitization(data *WorkflowData) stri
 		return GetCodexAllowedDomains(data.NetworkPermissions)
 	case "claude":
 		return GetClaudeAllowedDomains(data.NetworkPermissions)
+	case "openai":
+		var baseURL string
+		if data.EngineConfig != nil {
+			baseURL = data.EngineConfig.BaseURL
+		}
+		return GetOpenAIAllowedDomainsWithToolsAndRuntimes(baseURL, data.NetworkPermissions, nil, nil)
 	default:
 		// For other engines, use network permissions only
 		domains := GetAllowedDomains(data.NetworkPermissions)
@@ -0,0 +1,134 @@
+// This file provides the network egress report of the agent job.
+//
+// # Egress Report
+//
+// When the agent runs in the AWF firewall, the agent job summarizes the firewall logs in an
+// egress report: allowed and blocked domains and request counts. The report is written
+// to the step summary and uploaded as the egress-report artifact. When requests were blocked,
+// the safe outputs job adds the blocked domains and alerts to the footer of the pull requests,
+// issues and comments it creates, so reviewers see them without downloading the logs.
+//
+// Blocked requests to domains of an alert list raise a warning, or fail the agent job:
+//
+//	network:
+//	  allowed: [defaults]
+//	  egress-report:
+//	    alert:
+//	      - "*.pastebin.com"
+//	    on-alert: fail
+//
+// The report is enabled by default and disabled with egress-report: false.
+//
+// Validation of the configuration is in network_firewall_validation.go.
+
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var egressReportLog = logger.New("workflow:egress_report")
+
+// EgressReportConfig configures the network egress report of the agent job
+type EgressReportConfig struct {
+	Disabled bool     `yaml:"-"`                  // Set by egress-report: false
+	Alert    []string `yaml:"alert,omitempty"`    // Domains whose blocked requests raise an alert
+	OnAlert  string   `yaml:"on-alert,omitempty"` // warn (default) or fail
+}
+
+// extractEgressReportConfig extracts the egress report configuration from the network section
+func extractEgressReportConfig(egressReport any) *EgressReportConfig {
+	switch value := egressReport.(type) {
+	case bool:
+		return &EgressReportConfig{Disabled: !value}
+	case map[string]any:
+		config := &EgressReportConfig{Alert: parseStringOrList(value["alert"])}
+		if onAlert, ok := value["on-alert"].(string); ok {
+			config.OnAlert = onAlert
+		}
+		egressReportLog.Printf("Extracted egress report config: alert=%d, on-alert=%s", len(config.Alert), config.OnAlert)
+		return config
+	}
+	return nil
+}
+
+// getEgressReportConfig returns the egress report configuration of the workflow, or nil for the defaults
+func getEgressReportConfig(data *WorkflowData) *EgressReportConfig {
+	if data == nil || data.NetworkPermissions == nil {
+		return nil
+	}
+	return data.NetworkPermissions.EgressReport
+}
+
+// isEgressReportEnabled reports whether the agent job generates the egress report, which
+// requires the firewall logs
+func isEgressReportEnabled(data *WorkflowData) bool {
+	if config := getEgressReportConfig(data); config != nil && config.Disabled {
+		return false
+	}
+	return isFirewallEnabled(data)
+}
+
+// generateEgressReportSteps generates the steps summarizing the firewall logs in the egress
+// report and uploading it for the safe outputs job
+func (c *Compiler) generateEgressReportSteps(yaml *strings.Builder, data *WorkflowData) {
+	if !isEgressReportEnabled(data) {
+		return
+	}
+
+	egressReportLog.Print("Generating egress report steps")
+	config := getEgressReportConfig(data)
+	onAlert := "warn"
+	var alert []string
+	if config != nil {
+		alert = config.Alert
+		if config.OnAlert != "" {
+			onAlert = config.OnAlert
+		}
+	}
+
+	yaml.WriteString("      - name: Generate egress report\n")
+	yaml.WriteString("        if: always()\n")
+	if onAlert != "fail" {
+		yaml.WriteString("        continue-on-error: true\n")
+	}
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	if len(alert) > 0 {
+		yaml.WriteString("        env:\n")
+		fmt.Fprintf(yaml, "          GH_AW_EGRESS_ALERT: %q\n", strings.Join(alert, "\n"))
+		fmt.Fprintf(yaml, "          GH_AW_EGRESS_ON_ALERT: %s\n", onAlert)
+	}
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          script: |\n")
+	yaml.WriteString("            const { setupGlobals } = require('" + SetupActionDestination + "/setup_globals.cjs');\n")
+	yaml.WriteString("            setupGlobals(core, github, context, exec, io);\n")
+	yaml.WriteString("            const { main } = require('" + SetupActionDestination + "/egress_report.cjs');\n")
+	yaml.WriteString("            await main();\n")
+
+	c.stepOrderTracker.RecordArtifactUpload("Upload egress report", []string{constants.EgressReportFile})
+	yaml.WriteString("      - name: Upload egress report\n")
+	yaml.WriteString("        if: always()\n")
+	yaml.WriteString("        continue-on-error: true\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
+	yaml.WriteString("        with:\n")
+	fmt.Fprintf(yaml, "          name: %s\n", constants.EgressReportArtifactName)
+	fmt.Fprintf(yaml, "          path: %s\n", constants.EgressReportFile)
+	yaml.WriteString("          if-no-files-found: ignore\n")
+}
+
+// buildEgressReportDownloadSteps returns the steps downloading the egress report in the safe
+// outputs job, where it is added to the footer of the created items
+func buildEgressReportDownloadSteps(data *WorkflowData) []string {
+	if !isEgressReportEnabled(data) {
+		return nil
+	}
+	return buildArtifactDownloadSteps(ArtifactDownloadConfig{
+		ArtifactName: constants.EgressReportArtifactName,
+		DownloadPath: "/tmp/gh-aw/",
+		StepName:     "Download egress report",
+	})
+}
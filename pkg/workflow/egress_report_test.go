@@ -0,0 +1,127 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractEgressReportConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		expected *EgressReportConfig
+	}{
+		{name: "disabled", value: false, expected: &EgressReportConfig{Disabled: true}},
+		{name: "enabled", value: true, expected: &EgressReportConfig{}},
+		{
+			name:     "alert list",
+			value:    map[string]any{"alert": []any{"pastebin.com", "*.ngrok.io"}, "on-alert": "fail"},
+			expected: &EgressReportConfig{Alert: []string{"pastebin.com", "*.ngrok.io"}, OnAlert: "fail"},
+		},
+		{name: "invalid", value: "yes", expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractEgressReportConfig(tt.value))
+		})
+	}
+}
+
+func TestCompileWorkflowWithEgressReport(t *testing.T) {
+	lockContent, err := compileTestWorkflow(t, `on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+engine: claude
+network:
+  allowed: [defaults]
+  egress-report:
+    alert: ["*.pastebin.com", ngrok.io]
+    on-alert: fail
+safe-outputs:
+  add-comment:
+`)
+	require.NoError(t, err)
+
+	assert.Contains(t, lockContent, "- name: Generate egress report")
+	assert.Contains(t, lockContent, `GH_AW_EGRESS_ALERT: "*.pastebin.com\nngrok.io"`)
+	assert.Contains(t, lockContent, "GH_AW_EGRESS_ON_ALERT: fail")
+	assert.Contains(t, lockContent, "require('/opt/gh-aw/actions/egress_report.cjs')")
+	assert.Contains(t, lockContent, "name: egress-report")
+	assert.Contains(t, lockContent, "- name: Download egress report")
+
+	// The report fails the agent job on alerts, so the step must not continue on error
+	step := lockContent[strings.Index(lockContent, "- name: Generate egress report"):]
+	step = step[:strings.Index(step, "- name: Upload egress report")]
+	assert.NotContains(t, step, "continue-on-error")
+
+	redaction := strings.Index(lockContent, "- name: Redact secrets in logs")
+	report := strings.Index(lockContent, "- name: Generate egress report")
+	download := strings.Index(lockContent, "- name: Download egress report")
+	processSafeOutputs := strings.Index(lockContent, "- name: Process Safe Outputs")
+	assert.Less(t, redaction, report, "the report should be generated from redacted logs")
+	assert.Less(t, download, processSafeOutputs, "the report should be downloaded before the safe outputs are processed")
+}
+
+func TestCompileWorkflowEgressReportDefaults(t *testing.T) {
+	lockContent, err := compileTestWorkflow(t, `on: workflow_dispatch
+permissions:
+  contents: read
+engine: claude
+`)
+	require.NoError(t, err)
+
+	assert.Contains(t, lockContent, "- name: Generate egress report")
+	assert.NotContains(t, lockContent, "GH_AW_EGRESS_ALERT")
+	step := lockContent[strings.Index(lockContent, "- name: Generate egress report"):]
+	assert.Contains(t, step[:strings.Index(step, "uses:")], "continue-on-error: true")
+}
+
+func TestCompileWorkflowEgressReportDisabled(t *testing.T) {
+	lockContent, err := compileTestWorkflow(t, `on: workflow_dispatch
+permissions:
+  contents: read
+engine: claude
+network:
+  allowed: [defaults]
+  egress-report: false
+safe-outputs:
+  add-comment:
+`)
+	require.NoError(t, err)
+
+	assert.NotContains(t, lockContent, "egress report")
+	assert.NotContains(t, lockContent, "egress-report")
+}
+
+func TestEgressReportValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter string
+		errContains string
+	}{
+		{
+			name:        "invalid alert domain",
+			frontmatter: "on: workflow_dispatch\nengine: claude\nnetwork:\n  allowed: [defaults]\n  egress-report:\n    alert: [\"*\"]\n",
+			errContains: "network.egress-report.alert[0]",
+		},
+		{
+			name:        "alert list without firewall",
+			frontmatter: "on: workflow_dispatch\nstrict: false\nengine: claude\nsandbox:\n  agent: false\nnetwork:\n  allowed: [defaults]\n  egress-report:\n    alert: [pastebin.com]\n",
+			errContains: "AWF firewall logs",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileTestWorkflow(t, tt.frontmatter)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
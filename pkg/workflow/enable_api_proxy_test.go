@@ -1,8 +1,13 @@
 package workflow
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/testutil"
 )
 
 // TestEngineAWFEnableApiProxy tests that engines with LLM gateway support
@@ -89,3 +94,34 @@ func TestEngineAWFEnableApiProxy(t *testing.T) {
 		}
 	})
 }
+
+// TestDefaultWorkflowWritesLLMUsageLog tests that a workflow without a firewall version runs
+// the default AWF version with the api-proxy usage log enabled.
+func TestDefaultWorkflowWritesLLMUsageLog(t *testing.T) {
+	for _, engine := range []string{"claude", "codex"} {
+		t.Run(engine, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "llm-usage-log")
+			workflowPath := filepath.Join(tmpDir, "default.md")
+			content := "---\non: issues\npermissions:\n  contents: read\nengine: " + engine + "\n---\n\n# Default\n\nSummarize the issue.\n"
+			if err := os.WriteFile(workflowPath, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := NewCompiler().CompileWorkflow(workflowPath); err != nil {
+				t.Fatalf("Failed to compile workflow: %v", err)
+			}
+			lockContent, err := os.ReadFile(filepath.Join(tmpDir, "default.lock.yml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			lock := string(lockContent)
+
+			if !strings.Contains(lock, "--api-proxy-usage-log "+constants.LLMUsageLogPath) {
+				t.Errorf("Expected the default %s workflow to enable the api-proxy usage log", engine)
+			}
+			if !strings.Contains(lock, "install_awf_binary.sh "+string(constants.DefaultFirewallVersion)) {
+				t.Errorf("Expected the default %s workflow to install AWF %s", engine, constants.DefaultFirewallVersion)
+			}
+		})
+	}
+}
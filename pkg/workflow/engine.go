@@ -25,6 +25,10 @@ type EngineConfig struct {
 	Args        []string
 	Firewall    *FirewallConfig // AWF firewall configuration
 	Agent       string          // Agent identifier for copilot --agent flag (copilot engine only)
+	BaseURL     string          // Base URL of the OpenAI-compatible chat completions API (openai engine only)
+	Fallback    []*EngineConfig // Engines tried in order when this engine fails with a quota, auth or infrastructure error
+	// ModelRouting selects the model at runtime from rules on the triggering event (Model is empty when set)
+	ModelRouting *ModelRoutingConfig
 }
 
 // NetworkPermissions represents network access permissions for workflow execution
@@ -58,10 +62,11 @@ type EngineConfig struct {
 // Ecosystem identifiers in the Allowed list are expanded to their corresponding domain lists.
 // See GetAllowedDomains() for the list of supported ecosystem identifiers.
 type NetworkPermissions struct {
-	Allowed           []string        `yaml:"allowed,omitempty"`  // List of allowed domains or ecosystem identifiers (e.g., "defaults", "github", "python")
-	Blocked           []string        `yaml:"blocked,omitempty"`  // List of blocked domains (takes precedence over allowed)
-	Firewall          *FirewallConfig `yaml:"firewall,omitempty"` // AWF firewall configuration (see firewall.go)
-	ExplicitlyDefined bool            `yaml:"-"`                  // Internal flag: true if network field was explicitly set in frontmatter
+	Allowed           []string            `yaml:"allowed,omitempty"`       // List of allowed domains or ecosystem identifiers (e.g., "defaults", "github", "python")
+	Blocked           []string            `yaml:"blocked,omitempty"`       // List of blocked domains (takes precedence over allowed)
+	Firewall          *FirewallConfig     `yaml:"firewall,omitempty"`      // AWF firewall configuration (see firewall.go)
+	EgressReport      *EgressReportConfig `yaml:"egress-report,omitempty"` // Egress report configuration (see egress_report.go)
+	ExplicitlyDefined bool                `yaml:"-"`                       // Internal flag: true if network field was explicitly set in frontmatter
 }
 
 // EngineNetworkConfig combines engine configuration with top-level network permissions
@@ -81,6 +86,18 @@ func (c *Compiler) ExtractEngineConfig(frontmatter map[string]any) (string, *Eng
 			return engineStr, &EngineConfig{ID: engineStr}
 		}
 
+		// Handle array format (fallback chain): the first engine is the primary, the rest are fallbacks
+		if engineArray, ok := engine.([]any); ok {
+			engineLog.Printf("Found engine fallback chain with %d engines", len(engineArray))
+			chain := c.extractEngineFallbacks(engineArray)
+			if len(chain) == 0 {
+				return "", nil
+			}
+			config := chain[0]
+			config.Fallback = append(config.Fallback, chain[1:]...)
+			return config.ID, config
+		}
+
 		// Handle object format
 		if engineObj, ok := engine.(map[string]any); ok {
 			engineLog.Print("Found engine in object format, parsing configuration")
@@ -102,6 +119,15 @@ func (c *Compiler) ExtractEngineConfig(frontmatter map[string]any) (string, *Eng
 			if model, hasModel := engineObj["model"]; hasModel {
 				if modelStr, ok := model.(string); ok {
 					config.Model = modelStr
+				} else if modelObj, ok := model.(map[string]any); ok {
+					config.ModelRouting = parseModelRoutingConfig(modelObj)
+				}
+			}
+
+			// Extract optional 'base-url' field (openai engine only)
+			if baseURL, hasBaseURL := engineObj["base-url"]; hasBaseURL {
+				if baseURLStr, ok := baseURL.(string); ok {
+					config.BaseURL = baseURLStr
 				}
 			}
 
@@ -247,6 +273,12 @@ func (c *Compiler) ExtractEngineConfig(frontmatter map[string]any) (string, *Eng
 				}
 			}
 
+			// Extract optional 'fallback' field (engine or list of engines)
+			if fallback, hasFallback := engineObj["fallback"]; hasFallback {
+				config.Fallback = c.extractEngineFallbacks(fallback)
+				engineLog.Printf("Extracted %d fallback engines", len(config.Fallback))
+			}
+
 			// Return the ID as the engineSetting for backwards compatibility
 			engineLog.Printf("Extracted engine configuration: ID=%s", config.ID)
 			return config.ID, config
@@ -258,6 +290,28 @@ func (c *Compiler) ExtractEngineConfig(frontmatter map[string]any) (string, *Eng
 	return "", nil
 }
 
+// extractEngineFallbacks parses a fallback engine or list of engines (strings or objects) into
+// a flat chain, appending the nested fallbacks of each engine right after it
+func (c *Compiler) extractEngineFallbacks(value any) []*EngineConfig {
+	items, ok := value.([]any)
+	if !ok {
+		items = []any{value}
+	}
+
+	var chain []*EngineConfig
+	for _, item := range items {
+		_, config := c.ExtractEngineConfig(map[string]any{"engine": item})
+		if config == nil || config.ID == "" {
+			continue
+		}
+		nested := config.Fallback
+		config.Fallback = nil
+		chain = append(chain, config)
+		chain = append(chain, nested...)
+	}
+	return chain
+}
+
 // getAgenticEngine returns the agentic engine for the given engine setting
 func (c *Compiler) getAgenticEngine(engineSetting string) (CodingAgentEngine, error) {
 	if engineSetting == "" {
@@ -0,0 +1,239 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var engineFallbackLog = logger.New("workflow:engine_fallback")
+
+// engineAttempt is one engine of a fallback chain together with the workflow data its steps are generated from
+type engineAttempt struct {
+	engine CodingAgentEngine
+	data   *WorkflowData
+	number int // 1 for the primary engine, 2 for the first fallback engine, ...
+}
+
+// executionStepID returns the ID of the agent execution step of the attempt
+func (a engineAttempt) executionStepID() string {
+	if a.number == 1 {
+		return "agentic_execution"
+	}
+	return fmt.Sprintf("agentic_execution_fallback_%d", a.number-1)
+}
+
+// gateStepID returns the ID of the step deciding whether the attempt runs
+func (a engineAttempt) gateStepID() string {
+	return fmt.Sprintf("agent_fallback_%d", a.number-1)
+}
+
+// hasEngineFallbacks returns true if the workflow configures fallback engines
+func hasEngineFallbacks(data *WorkflowData) bool {
+	return data.EngineConfig != nil && len(data.EngineConfig.Fallback) > 0
+}
+
+// engineFallbackIDs returns the IDs of the fallback engines of the workflow
+func engineFallbackIDs(data *WorkflowData) []string {
+	if !hasEngineFallbacks(data) {
+		return nil
+	}
+	ids := make([]string, 0, len(data.EngineConfig.Fallback))
+	for _, fallback := range data.EngineConfig.Fallback {
+		ids = append(ids, fallback.ID)
+	}
+	return ids
+}
+
+// buildEngineAttempts returns the primary engine followed by the fallback engines of the workflow.
+// Each fallback engine gets a shallow copy of the workflow data using its own engine configuration.
+func (c *Compiler) buildEngineAttempts(data *WorkflowData, primary CodingAgentEngine) ([]engineAttempt, error) {
+	attempts := []engineAttempt{{engine: primary, data: data, number: 1}}
+	if !hasEngineFallbacks(data) {
+		return attempts, nil
+	}
+
+	for _, fallback := range data.EngineConfig.Fallback {
+		engine, err := c.getAgenticEngine(fallback.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get fallback engine %s: %w", fallback.ID, err)
+		}
+		fallbackData := *data
+		fallbackData.AI = fallback.ID
+		fallbackData.EngineConfig = fallback
+		attempts = append(attempts, engineAttempt{engine: engine, data: &fallbackData, number: len(attempts) + 1})
+	}
+
+	engineFallbackLog.Printf("Built engine fallback chain with %d engines", len(attempts))
+	return attempts, nil
+}
+
+// generateEngineExecutionStepsWithFallback generates the execution steps of the primary engine
+// followed by, for each fallback engine, a step checking whether the previous engine failed with
+// a quota, authentication or infrastructure error and the installation and execution steps of
+// the fallback engine, which only run when that check falls back.
+func (c *Compiler) generateEngineExecutionStepsWithFallback(yaml *strings.Builder, attempts []engineAttempt, logFile string) {
+	// Steps installed for the primary engine (e.g. Node.js, AWF) are not installed again
+	installed := make(map[string]bool)
+	for _, step := range attempts[0].engine.GetInstallationSteps(attempts[0].data) {
+		installed[stepName(step)] = true
+	}
+
+	for i, attempt := range attempts {
+		last := i == len(attempts)-1
+		engineFallbackLog.Printf("Generating steps for engine attempt %d: %s", attempt.number, attempt.engine.GetID())
+
+		var steps []GitHubActionStep
+		if i > 0 {
+			c.generateEngineFallbackCheckStep(yaml, attempts[i-1], attempt)
+
+			for _, step := range attempt.engine.GetInstallationSteps(attempt.data) {
+				name := stepName(step)
+				if installed[name] {
+					continue
+				}
+				installed[name] = true
+				steps = append(steps, step)
+			}
+		}
+
+		execution := attempt.engine.GetExecutionSteps(attempt.data, logFile)
+		executionIndex := len(steps) + findExecutionStep(execution)
+		steps = append(steps, execution...)
+
+		for j, step := range steps {
+			if i > 0 {
+				step = gateFallbackStep(step, attempt)
+			}
+			if j == executionIndex {
+				step = setStepID(step, attempt.executionStepID())
+				if !last {
+					step = insertStepLine(step, "        continue-on-error: true")
+				}
+			}
+			for _, line := range step {
+				yaml.WriteString(line + "\n")
+			}
+		}
+	}
+}
+
+// generateEngineFallbackCheckStep generates the step deciding whether the next engine runs
+// after the previous engine failed
+func (c *Compiler) generateEngineFallbackCheckStep(yaml *strings.Builder, failed engineAttempt, next engineAttempt) {
+	model := fmt.Sprintf("${{ vars.%s || '' }}", agentModelEnvVar(next.engine.GetID()))
+	if next.data.EngineConfig.Model != "" {
+		model = fmt.Sprintf("%q", next.data.EngineConfig.Model)
+	}
+
+	fmt.Fprintf(yaml, "      - name: Check fallback to %s\n", next.engine.GetDisplayName())
+	fmt.Fprintf(yaml, "        id: %s\n", next.gateStepID())
+	fmt.Fprintf(yaml, "        if: steps.%s.outcome == 'failure'\n", failed.executionStepID())
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_AGENT_ENGINE: %s\n", failed.engine.GetID())
+	fmt.Fprintf(yaml, "          GH_AW_FALLBACK_ATTEMPT: \"%d\"\n", failed.number)
+	fmt.Fprintf(yaml, "          GH_AW_FALLBACK_ENGINE: %s\n", next.engine.GetID())
+	fmt.Fprintf(yaml, "          GH_AW_FALLBACK_ENGINE_NAME: %q\n", next.engine.GetDisplayName())
+	fmt.Fprintf(yaml, "          GH_AW_FALLBACK_MODEL: %s\n", model)
+	yaml.WriteString("          GH_AW_FALLBACK_PATTERNS: |\n")
+	for _, pattern := range failed.engine.GetFallbackErrorPatterns() {
+		yaml.WriteString("            " + pattern + "\n")
+	}
+	yaml.WriteString("        run: bash /opt/gh-aw/actions/check_agent_fallback.sh\n")
+}
+
+// generateLogParsingWithFallback generates a log parsing step for each engine of the chain
+// that only runs for the engine that produced the agent log
+func (c *Compiler) generateLogParsingWithFallback(yaml *strings.Builder, attempts []engineAttempt) {
+	for i, attempt := range attempts {
+		conditions := []string{"always()"}
+		if i > 0 {
+			conditions = append(conditions, fmt.Sprintf("steps.%s.outputs.fallback == 'true'", attempt.gateStepID()))
+		}
+		if i < len(attempts)-1 {
+			conditions = append(conditions, fmt.Sprintf("steps.%s.outputs.fallback != 'true'", attempts[i+1].gateStepID()))
+		}
+		c.generateLogParsingStep(yaml, attempt.engine, strings.Join(conditions, " && "), attempt.engine.GetLogFileForParsing())
+	}
+}
+
+// stepName returns the name of a step, or its first line if it has no name
+func stepName(step GitHubActionStep) string {
+	for _, line := range step {
+		trimmed := strings.TrimSpace(line)
+		if name, ok := strings.CutPrefix(trimmed, "- name: "); ok {
+			return name
+		}
+	}
+	if len(step) > 0 {
+		return strings.TrimSpace(step[0])
+	}
+	return ""
+}
+
+// findExecutionStep returns the index of the agent execution step, which is the step
+// with the agentic_execution ID or else the last step
+func findExecutionStep(steps []GitHubActionStep) int {
+	for i, step := range steps {
+		for _, line := range step {
+			if line == "        id: agentic_execution" {
+				return i
+			}
+		}
+	}
+	return len(steps) - 1
+}
+
+// gateFallbackStep returns a copy of a fallback engine step that only runs when the engine
+// is fallen back to, with its ID suffixed to keep it unique in the job
+func gateFallbackStep(step GitHubActionStep, attempt engineAttempt) GitHubActionStep {
+	condition := fmt.Sprintf("steps.%s.outputs.fallback == 'true'", attempt.gateStepID())
+
+	gated := make(GitHubActionStep, 0, len(step)+1)
+	hasCondition := false
+	for _, line := range suffixStepIDs(step, fmt.Sprintf("_fallback_%d", attempt.number-1)) {
+		if existing, ok := strings.CutPrefix(line, "        if: "); ok {
+			existing = strings.TrimSuffix(strings.TrimPrefix(existing, "${{ "), " }}")
+			line = fmt.Sprintf("        if: %s && (%s)", condition, existing)
+			hasCondition = true
+		}
+		gated = append(gated, line)
+	}
+	if !hasCondition {
+		gated = insertStepLine(gated, "        if: "+condition)
+	}
+	return gated
+}
+
+// setStepID returns a copy of the step with the given ID, replacing its ID if it has one
+func setStepID(step GitHubActionStep, id string) GitHubActionStep {
+	updated := make(GitHubActionStep, 0, len(step)+1)
+	hasID := false
+	for _, line := range step {
+		if strings.HasPrefix(line, "        id: ") {
+			line = "        id: " + id
+			hasID = true
+		}
+		updated = append(updated, line)
+	}
+	if !hasID && len(updated) > 0 {
+		updated = append(updated[:1], append(GitHubActionStep{"        id: " + id}, updated[1:]...)...)
+	}
+	return updated
+}
+
+// insertStepLine returns a copy of the step with the line inserted after its name, ID and condition
+func insertStepLine(step GitHubActionStep, line string) GitHubActionStep {
+	if len(step) == 0 {
+		return step
+	}
+	index := 1
+	for index < len(step) && (strings.HasPrefix(step[index], "        id: ") || strings.HasPrefix(step[index], "        if: ")) {
+		index++
+	}
+	updated := make(GitHubActionStep, 0, len(step)+1)
+	updated = append(updated, step[:index]...)
+	updated = append(updated, line)
+	return append(updated, step[index:]...)
+}
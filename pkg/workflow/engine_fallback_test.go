@@ -0,0 +1,182 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractEngineConfigFallback(t *testing.T) {
+	compiler := NewCompiler()
+
+	tests := []struct {
+		name      string
+		engine    any
+		primary   string
+		fallbacks []string
+		models    []string
+	}{
+		{
+			name:      "array of engine names",
+			engine:    []any{"claude", "copilot"},
+			primary:   "claude",
+			fallbacks: []string{"copilot"},
+			models:    []string{""},
+		},
+		{
+			name:      "array with engine objects",
+			engine:    []any{map[string]any{"id": "claude", "model": "claude-sonnet-4"}, map[string]any{"id": "codex", "model": "gpt-5"}, "copilot"},
+			primary:   "claude",
+			fallbacks: []string{"codex", "copilot"},
+			models:    []string{"gpt-5", ""},
+		},
+		{
+			name:      "object with single fallback",
+			engine:    map[string]any{"id": "copilot", "fallback": "claude"},
+			primary:   "copilot",
+			fallbacks: []string{"claude"},
+			models:    []string{""},
+		},
+		{
+			name: "nested fallbacks are flattened",
+			engine: map[string]any{"id": "claude", "fallback": []any{
+				map[string]any{"id": "copilot", "model": "gpt-5", "fallback": "codex"},
+			}},
+			primary:   "claude",
+			fallbacks: []string{"copilot", "codex"},
+			models:    []string{"gpt-5", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setting, config := compiler.ExtractEngineConfig(map[string]any{"engine": tt.engine})
+			require.NotNil(t, config)
+			assert.Equal(t, tt.primary, setting)
+			assert.Equal(t, tt.primary, config.ID)
+			require.Len(t, config.Fallback, len(tt.fallbacks))
+			for i, fallback := range config.Fallback {
+				assert.Equal(t, tt.fallbacks[i], fallback.ID)
+				assert.Equal(t, tt.models[i], fallback.Model)
+				assert.Empty(t, fallback.Fallback, "fallback chain should be flat")
+			}
+		})
+	}
+}
+
+func TestValidateEngineFallbacks(t *testing.T) {
+	compiler := NewCompiler()
+
+	require.NoError(t, compiler.validateEngineFallbacks("claude", &EngineConfig{ID: "claude"}))
+	require.NoError(t, compiler.validateEngineFallbacks("claude", &EngineConfig{ID: "claude", Fallback: []*EngineConfig{{ID: "copilot"}, {ID: "codex"}}}))
+
+	err := compiler.validateEngineFallbacks("claude", &EngineConfig{ID: "claude", Fallback: []*EngineConfig{{ID: "gemini"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid fallback engine")
+
+	err = compiler.validateEngineFallbacks("claude", &EngineConfig{ID: "claude", Fallback: []*EngineConfig{{ID: "custom"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "custom engine cannot be used as a fallback")
+
+	err = compiler.validateEngineFallbacks("custom", &EngineConfig{ID: "custom", Fallback: []*EngineConfig{{ID: "claude"}}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not supported for the custom engine")
+}
+
+func TestFallbackErrorPatterns(t *testing.T) {
+	samples := map[string]string{
+		"claude":  `API Error: 429 {"type":"error","error":{"type":"rate_limit_error","message":"Number of request tokens has exceeded your per-minute rate limit"}}`,
+		"copilot": "Error: 402 Payment Required: You have exceeded your monthly quota of premium requests",
+		"codex":   `stream error: {"error":{"type":"insufficient_quota","message":"You exceeded your current quota"}}`,
+	}
+
+	registry := GetGlobalEngineRegistry()
+	for engineID, sample := range samples {
+		t.Run(engineID, func(t *testing.T) {
+			engine, err := registry.GetEngine(engineID)
+			require.NoError(t, err)
+
+			patterns := engine.GetFallbackErrorPatterns()
+			require.NotEmpty(t, patterns)
+
+			matched := false
+			for _, pattern := range patterns {
+				re, err := regexp.Compile("(?i)" + pattern)
+				require.NoError(t, err, "pattern %q should be a valid regular expression", pattern)
+				assert.False(t, re.MatchString("Error: the tests are still failing"), "pattern %q should not match a task failure", pattern)
+				matched = matched || re.MatchString(sample)
+			}
+			assert.True(t, matched, "patterns should match the %s quota error", engineID)
+		})
+	}
+}
+
+func TestCompileWorkflowWithEngineFallback(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "engine-fallback")
+	workflowPath := filepath.Join(tmpDir, "fallback.md")
+	content := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: [claude, {id: copilot, model: gpt-5}]
+---
+
+# Fallback
+
+Summarize the repository.
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "fallback.lock.yml"))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "      - name: Execute Claude Code CLI\n        id: agentic_execution\n        continue-on-error: true\n")
+	assert.Contains(t, lock, "        id: agent_fallback_1\n        if: steps.agentic_execution.outcome == 'failure'\n")
+	assert.Contains(t, lock, "          GH_AW_FALLBACK_ENGINE: copilot\n")
+	assert.Contains(t, lock, "          GH_AW_FALLBACK_MODEL: \"gpt-5\"\n")
+	assert.Contains(t, lock, "            rate_limit_error\n")
+	assert.Contains(t, lock, "run: bash /opt/gh-aw/actions/check_agent_fallback.sh")
+	assert.Contains(t, lock, "      - name: Install GitHub Copilot CLI\n        if: steps.agent_fallback_1.outputs.fallback == 'true'\n")
+	assert.Contains(t, lock, "      - name: Execute GitHub Copilot CLI\n        id: agentic_execution_fallback_1\n        if: steps.agent_fallback_1.outputs.fallback == 'true'\n")
+	assert.Contains(t, lock, "id: validate-secret_fallback_1")
+	assert.Contains(t, lock, `export GH_AW_ADDITIONAL_ENGINES="copilot"`)
+	assert.Contains(t, lock, "if: always() && steps.agent_fallback_1.outputs.fallback != 'true'")
+	assert.Contains(t, lock, "if: always() && steps.agent_fallback_1.outputs.fallback == 'true'")
+	assert.Contains(t, lock, "/tmp/gh-aw/agent-stdio.attempt-*.log")
+	assert.Equal(t, 1, strings.Count(lock, "- name: Install awf binary"), "shared installation steps should not be repeated")
+	assert.NotContains(t, lock, "fallback == 'true'\n        continue-on-error: true", "the last engine of the chain should fail the job")
+}
+
+func TestGateFallbackStep(t *testing.T) {
+	attempt := engineAttempt{number: 3}
+	step := GitHubActionStep{
+		"      - name: Setup",
+		"        id: setup",
+		"        if: ${{ github.event_name == 'push' }}",
+		"        run: echo setup",
+	}
+
+	gated := gateFallbackStep(step, attempt)
+	assert.Equal(t, GitHubActionStep{
+		"      - name: Setup",
+		"        id: setup_fallback_2",
+		"        if: steps.agent_fallback_2.outputs.fallback == 'true' && (github.event_name == 'push')",
+		"        run: echo setup",
+	}, gated)
+	assert.Equal(t, "        id: setup", step[1], "the original step should not be modified")
+
+	gated = gateFallbackStep(GitHubActionStep{"      - name: Run", "        run: echo run"}, attempt)
+	assert.Equal(t, "        if: steps.agent_fallback_2.outputs.fallback == 'true'", gated[1])
+}
@@ -10,6 +10,9 @@
 //
 //   - validateEngine() - Validates that a given engine ID is supported
 //   - validateSingleEngineSpecification() - Validates that only one engine field exists across all files
+//   - validateEngineFallbacks() - Validates the engines of a fallback chain
+//   - validateOpenAIEngineConfig() - Validates the base URL of the openai engine
+//   - validateModelRouting() - Validates the model routing rules of an engine (see model_routing.go)
 //
 // # Validation Pattern: Engine Registry
 //
@@ -36,6 +39,7 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/constants"
@@ -132,6 +136,11 @@ func (c *Compiler) validateSingleEngineSpecification(mainEngineSetting string, i
 		return "", fmt.Errorf("failed to parse included engine configuration: %w. Expected string or object format.\n\nExample (string):\nengine: copilot\n\nExample (object):\nengine:\n  id: copilot\n  model: gpt-4\n\nSee: %s", err, constants.DocsEnginesURL)
 	}
 
+	// Handle array format (fallback chain) - the first engine is the primary
+	if engineArray, ok := firstEngine.([]any); ok && len(engineArray) > 0 {
+		firstEngine = engineArray[0]
+	}
+
 	// Handle string format
 	if engineStr, ok := firstEngine.(string); ok {
 		return engineStr, nil
@@ -147,6 +156,55 @@ func (c *Compiler) validateSingleEngineSpecification(mainEngineSetting string, i
 	return "", fmt.Errorf("invalid engine configuration in included file, missing or invalid 'id' field. Expected string or object with 'id' field.\n\nExample (string):\nengine: copilot\n\nExample (object):\nengine:\n  id: copilot\n  model: gpt-4\n\nSee: %s", constants.DocsEnginesURL)
 }
 
+// validateEngineFallbacks validates that every engine of a fallback chain is supported and
+// that the chain does not involve the custom engine, whose steps cannot be retried
+func (c *Compiler) validateEngineFallbacks(engineSetting string, engineConfig *EngineConfig) error {
+	if engineConfig == nil || len(engineConfig.Fallback) == 0 {
+		return nil
+	}
+
+	engineValidationLog.Printf("Validating %d fallback engines for %s", len(engineConfig.Fallback), engineSetting)
+
+	if engineSetting == "custom" {
+		return fmt.Errorf("engine fallback is not supported for the custom engine. Remove 'fallback' or use an agentic engine as the primary engine.\n\nExample:\nengine: [claude, copilot]\n\nSee: %s", constants.DocsEnginesURL)
+	}
+
+	for _, fallback := range engineConfig.Fallback {
+		if err := c.validateEngine(fallback.ID); err != nil {
+			return fmt.Errorf("invalid fallback engine: %w", err)
+		}
+		if fallback.ID == "custom" {
+			return fmt.Errorf("the custom engine cannot be used as a fallback engine. Use an agentic engine such as claude, codex or copilot.\n\nExample:\nengine: [claude, copilot]\n\nSee: %s", constants.DocsEnginesURL)
+		}
+		if err := validateOpenAIEngineConfig(fallback); err != nil {
+			return fmt.Errorf("invalid fallback engine: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// validateOpenAIEngineConfig validates that the openai engine configures the HTTP(S) base URL
+// of its chat completions endpoint. Other engines are not checked.
+func validateOpenAIEngineConfig(engineConfig *EngineConfig) error {
+	if engineConfig == nil || engineConfig.ID != string(constants.OpenAIEngine) {
+		return nil
+	}
+
+	engineValidationLog.Printf("Validating openai engine base URL: %s", engineConfig.BaseURL)
+
+	if engineConfig.BaseURL == "" {
+		return fmt.Errorf("the openai engine requires 'base-url', the base URL of the OpenAI-compatible chat completions API.\n\nExample:\nengine:\n  id: openai\n  base-url: http://vllm.internal:8000/v1\n  model: qwen2.5-coder-32b\n\nSee: %s", constants.DocsEnginesURL)
+	}
+
+	parsed, err := url.Parse(engineConfig.BaseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid openai engine base-url '%s': must be an http:// or https:// URL such as http://vllm.internal:8000/v1. See: %s", engineConfig.BaseURL, constants.DocsEnginesURL)
+	}
+
+	return nil
+}
+
 // validatePluginSupport validates that plugins are only used with engines that support them
 func (c *Compiler) validatePluginSupport(pluginInfo *PluginInfo, agenticEngine CodingAgentEngine) error {
 	// No plugins specified, validation passes
@@ -191,6 +191,23 @@ func getAWFImageTag(firewallConfig *FirewallConfig) string {
 	return strings.TrimPrefix(version, "v")
 }
 
+// getAPIProxyArgs returns the AWF arguments enabling the api-proxy sidecar for an engine with an
+// LLM gateway port. The api-proxy holds the LLM API keys and proxies the requests through the
+// firewall; AWF versions that support it also write the LLM usage log read by logs and audit.
+func getAPIProxyArgs(llmGatewayPort int, firewallConfig *FirewallConfig) []string {
+	if llmGatewayPort <= 0 {
+		return nil
+	}
+	args := []string{"--enable-api-proxy"}
+	firewallLog.Printf("Added --enable-api-proxy for LLM API proxying on port %d", llmGatewayPort)
+
+	if compareVersions(getAWFImageTag(firewallConfig), string(constants.AWFLLMUsageLogVersion)) >= 0 {
+		args = append(args, "--api-proxy-usage-log", constants.LLMUsageLogPath)
+		firewallLog.Printf("Added --api-proxy-usage-log %s", constants.LLMUsageLogPath)
+	}
+	return args
+}
+
 // getSSLBumpArgs returns the AWF arguments for SSL Bump configuration.
 // Returns arguments for --ssl-bump and --allow-urls flags if SSL Bump is enabled.
 // SSL Bump enables HTTPS content inspection (v0.9.0+), allowing URL path filtering
@@ -5,6 +5,8 @@ package workflow
 import (
 	"strings"
 	"testing"
+
+	"github.com/github/gh-aw/pkg/constants"
 )
 
 // TestValidateLogLevel tests the ValidateLogLevel function with various inputs
@@ -392,3 +394,55 @@ func TestGetSSLBumpArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAPIProxyArgs(t *testing.T) {
+	usageLogArgs := []string{"--enable-api-proxy", "--api-proxy-usage-log", constants.LLMUsageLogPath}
+
+	tests := []struct {
+		name     string
+		port     int
+		config   *FirewallConfig
+		expected []string
+	}{
+		{
+			name:     "engine without LLM gateway returns no args",
+			port:     -1,
+			config:   &FirewallConfig{Enabled: true, Version: string(constants.AWFLLMUsageLogVersion)},
+			expected: nil,
+		},
+		{
+			name:     "AWF version before the usage log only enables the api-proxy",
+			port:     constants.ClaudeLLMGatewayPort,
+			config:   &FirewallConfig{Enabled: true, Version: "v0.18.0"},
+			expected: []string{"--enable-api-proxy"},
+		},
+		{
+			name:     "AWF version with the usage log enables it",
+			port:     constants.ClaudeLLMGatewayPort,
+			config:   &FirewallConfig{Enabled: true, Version: string(constants.AWFLLMUsageLogVersion)},
+			expected: usageLogArgs,
+		},
+		{
+			name:     "later AWF version without v prefix enables the usage log",
+			port:     constants.CodexLLMGatewayPort,
+			config:   &FirewallConfig{Enabled: true, Version: "0.20.1"},
+			expected: usageLogArgs,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := getAPIProxyArgs(tt.port, tt.config)
+
+			if len(result) != len(tt.expected) {
+				t.Errorf("getAPIProxyArgs() = %v, expected %v", result, tt.expected)
+				return
+			}
+			for i, v := range result {
+				if v != tt.expected[i] {
+					t.Errorf("getAPIProxyArgs()[%d] = %v, expected %v", i, v, tt.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -60,6 +60,11 @@ func (c *Compiler) extractNetworkPermissions(frontmatter map[string]any) *Networ
 				permissions.Firewall = c.extractFirewallConfig(firewall)
 			}
 
+			// Extract egress report configuration if present
+			if egressReport, hasEgressReport := networkObj["egress-report"]; hasEgressReport {
+				permissions.EgressReport = extractEgressReportConfig(egressReport)
+			}
+
 			// Empty object {} means no network access (empty allowed list)
 			return permissions
 		}
@@ -203,6 +208,11 @@ func (c *Compiler) extractSandboxConfig(frontmatter map[string]any) *SandboxConf
 		config.MCP = c.extractMCPGatewayConfig(mcpVal)
 	}
 
+	if filesystemVal, hasFilesystem := sandboxObj["filesystem"]; hasFilesystem {
+		frontmatterExtractionSecurityLog.Print("Extracting sandbox filesystem policy")
+		config.Filesystem = extractSandboxFilesystemConfig(filesystemVal)
+	}
+
 	// If we found agent field, return the new format config
 	if config.Agent != nil {
 		frontmatterExtractionSecurityLog.Print("Sandbox configured with new format (agent)")
@@ -438,6 +448,11 @@ func (c *Compiler) extractMCPGatewayConfig(mcpVal any) *MCPGatewayRuntimeConfig
 		}
 	}
 
+	// Extract cassette (record/replay proxy mode)
+	if cassetteVal, hasCassette := mcpObj["cassette"]; hasCassette {
+		mcpConfig.Cassette = extractMCPCassetteConfig(cassetteVal)
+	}
+
 	return mcpConfig
 }
 
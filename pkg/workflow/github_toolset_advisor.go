@@ -0,0 +1,134 @@
+package workflow
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var githubToolsetAdvisorLog = logger.New("workflow:github_toolset_advisor")
+
+// GitHubPermissionChange is a proposed change of a workflow permission
+type GitHubPermissionChange struct {
+	Scope string `json:"scope"`
+	From  string `json:"from,omitempty"` // Empty when the permission is added
+	To    string `json:"to,omitempty"`   // Empty when the permission is removed
+}
+
+// GitHubToolsetAdvice is the least-privilege GitHub MCP configuration for the observed tool usage
+type GitHubToolsetAdvice struct {
+	UsedTools         []string                 `json:"used_tools"`              // GitHub MCP tools called in the analyzed runs
+	UnknownTools      []string                 `json:"unknown_tools,omitempty"` // Called tools missing from the tool to toolset mapping
+	DeclaredToolsets  []string                 `json:"declared_toolsets"`
+	DeclaredAllowed   []string                 `json:"declared_allowed,omitempty"`
+	Toolsets          []string                 `json:"toolsets"`    // Minimum toolsets providing the used tools
+	Allowed           []string                 `json:"allowed"`     // Minimum tool allowlist
+	Permissions       map[string]string        `json:"permissions"` // Proposed workflow permissions
+	PermissionChanges []GitHubPermissionChange `json:"permission_changes,omitempty"`
+}
+
+// HasChanges reports whether the advice differs from the declared configuration
+func (a *GitHubToolsetAdvice) HasChanges() bool {
+	return !slices.Equal(a.DeclaredToolsets, a.Toolsets) || !slices.Equal(a.DeclaredAllowed, a.Allowed) || len(a.PermissionChanges) > 0
+}
+
+// AdviseGitHubToolsets computes the minimum toolsets, tool allowlist and permissions that
+// cover the GitHub MCP tools used by the workflow. Permissions that do not belong to any
+// toolset are kept as declared; contents: read is always kept for the repository checkout,
+// and actions: read is kept when the agentic-workflows tool is enabled.
+func AdviseGitHubToolsets(workflowData *WorkflowData, usedTools []string) *GitHubToolsetAdvice {
+	githubToolsetAdvisorLog.Printf("Advising GitHub toolsets: used_tools=%d", len(usedTools))
+
+	advice := &GitHubToolsetAdvice{
+		UsedTools:   []string{},
+		Toolsets:    []string{},
+		Allowed:     []string{},
+		Permissions: map[string]string{},
+	}
+
+	var githubTool *GitHubToolConfig
+	if workflowData.ParsedTools != nil {
+		githubTool = workflowData.ParsedTools.GitHub
+	}
+	if githubTool != nil {
+		advice.DeclaredToolsets = slices.Clone(ParseGitHubToolsets(githubTool.GetToolsets()))
+		advice.DeclaredAllowed = githubTool.Allowed.ToStringSlice()
+		sort.Strings(advice.DeclaredAllowed)
+	}
+	sort.Strings(advice.DeclaredToolsets)
+
+	toolsets := make(map[string]bool)
+	for _, tool := range usedTools {
+		if slices.Contains(advice.UsedTools, tool) {
+			continue
+		}
+		advice.UsedTools = append(advice.UsedTools, tool)
+		toolset, ok := GitHubToolToToolsetMap[tool]
+		if !ok {
+			advice.UnknownTools = append(advice.UnknownTools, tool)
+			continue
+		}
+		toolsets[toolset] = true
+	}
+	sort.Strings(advice.UsedTools)
+	sort.Strings(advice.UnknownTools)
+
+	for toolset := range toolsets {
+		advice.Toolsets = append(advice.Toolsets, toolset)
+	}
+	sort.Strings(advice.Toolsets)
+	advice.Allowed = append(advice.Allowed, advice.UsedTools...)
+
+	// Scopes that some toolset requires are governed by the toolsets; others are kept
+	governed := make(map[PermissionScope]bool)
+	for _, perms := range toolsetPermissionsMap {
+		for _, scope := range perms.ReadPermissions {
+			governed[scope] = true
+		}
+		for _, scope := range perms.WritePermissions {
+			governed[scope] = true
+		}
+	}
+
+	readOnly := githubTool.IsReadOnly()
+	required := collectRequiredPermissions(advice.Toolsets, readOnly)
+	if level, ok := required[PermissionContents]; !ok || level == PermissionNone {
+		required[PermissionContents] = PermissionRead
+	}
+	if workflowData.ParsedTools != nil && workflowData.ParsedTools.AgenticWorkflows != nil {
+		if _, ok := required[PermissionActions]; !ok {
+			required[PermissionActions] = PermissionRead
+		}
+	}
+
+	declared := NewPermissionsParser(workflowData.Permissions).ToPermissions()
+	for _, scope := range GetAllPermissionScopes() {
+		declaredLevel, hasDeclared := declared.Get(scope)
+		if declaredLevel == PermissionNone {
+			declaredLevel, hasDeclared = "", false
+		}
+
+		proposed, hasProposed := required[scope]
+		if !governed[scope] {
+			proposed, hasProposed = declaredLevel, hasDeclared
+		}
+		if hasProposed {
+			advice.Permissions[string(scope)] = string(proposed)
+		}
+
+		if hasDeclared != hasProposed || declaredLevel != proposed {
+			change := GitHubPermissionChange{Scope: string(scope)}
+			if hasDeclared {
+				change.From = string(declaredLevel)
+			}
+			if hasProposed {
+				change.To = string(proposed)
+			}
+			advice.PermissionChanges = append(advice.PermissionChanges, change)
+		}
+	}
+
+	githubToolsetAdvisorLog.Printf("Advice: toolsets=%v, allowed=%d, permission_changes=%d", advice.Toolsets, len(advice.Allowed), len(advice.PermissionChanges))
+	return advice
+}
@@ -0,0 +1,109 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdviseGitHubToolsets(t *testing.T) {
+	tests := []struct {
+		name              string
+		tools             *Tools
+		permissions       string
+		usedTools         []string
+		expectedToolsets  []string
+		expectedAllowed   []string
+		expectedPerms     map[string]string
+		expectedChanges   []GitHubPermissionChange
+		expectedUnknown   []string
+		expectedHasChange bool
+	}{
+		{
+			name: "default toolsets narrowed to issues",
+			tools: &Tools{GitHub: &GitHubToolConfig{
+				Toolset:  GitHubToolsets{"default"},
+				ReadOnly: true,
+			}},
+			permissions:      "permissions:\n  contents: read\n  issues: read\n  pull-requests: read\n",
+			usedTools:        []string{"issue_read", "list_issues", "issue_read"},
+			expectedToolsets: []string{"issues"},
+			expectedAllowed:  []string{"issue_read", "list_issues"},
+			expectedPerms:    map[string]string{"contents": "read", "issues": "read"},
+			expectedChanges: []GitHubPermissionChange{
+				{Scope: "pull-requests", From: "read"},
+			},
+			expectedHasChange: true,
+		},
+		{
+			name: "missing permission is added and unrelated permissions are kept",
+			tools: &Tools{GitHub: &GitHubToolConfig{
+				Toolset:  GitHubToolsets{"repos", "pull_requests"},
+				Allowed:  GitHubAllowedTools{"get_file_contents", "pull_request_read"},
+				ReadOnly: true,
+			}},
+			permissions:      "permissions:\n  contents: read\n  id-token: write\n",
+			usedTools:        []string{"pull_request_read", "get_file_contents"},
+			expectedToolsets: []string{"pull_requests", "repos"},
+			expectedAllowed:  []string{"get_file_contents", "pull_request_read"},
+			expectedPerms:    map[string]string{"contents": "read", "id-token": "write", "pull-requests": "read"},
+			expectedChanges: []GitHubPermissionChange{
+				{Scope: "pull-requests", To: "read"},
+			},
+			expectedHasChange: true,
+		},
+		{
+			name: "agentic-workflows keeps actions read",
+			tools: &Tools{
+				GitHub:           &GitHubToolConfig{Toolset: GitHubToolsets{"repos"}, Allowed: GitHubAllowedTools{"get_file_contents"}, ReadOnly: true},
+				AgenticWorkflows: &AgenticWorkflowsToolConfig{},
+			},
+			permissions:      "permissions:\n  contents: read\n  actions: read\n",
+			usedTools:        []string{"get_file_contents", "some_new_tool"},
+			expectedToolsets: []string{"repos"},
+			expectedAllowed:  []string{"get_file_contents", "some_new_tool"},
+			expectedPerms:    map[string]string{"actions": "read", "contents": "read"},
+			expectedUnknown:  []string{"some_new_tool"},
+			// The unknown tool is added to the allowlist
+			expectedHasChange: true,
+		},
+		{
+			name: "already minimal",
+			tools: &Tools{GitHub: &GitHubToolConfig{
+				Toolset:  GitHubToolsets{"issues"},
+				Allowed:  GitHubAllowedTools{"issue_read"},
+				ReadOnly: true,
+			}},
+			permissions:      "permissions:\n  contents: read\n  issues: read\n",
+			usedTools:        []string{"issue_read"},
+			expectedToolsets: []string{"issues"},
+			expectedAllowed:  []string{"issue_read"},
+			expectedPerms:    map[string]string{"contents": "read", "issues": "read"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workflowData := &WorkflowData{ParsedTools: tt.tools, Permissions: tt.permissions}
+			advice := AdviseGitHubToolsets(workflowData, tt.usedTools)
+
+			assert.Equal(t, tt.expectedToolsets, advice.Toolsets, "toolsets")
+			assert.Equal(t, tt.expectedAllowed, advice.Allowed, "allowed")
+			assert.Equal(t, tt.expectedPerms, advice.Permissions, "permissions")
+			assert.Equal(t, tt.expectedChanges, advice.PermissionChanges, "permission changes")
+			assert.Equal(t, tt.expectedUnknown, advice.UnknownTools, "unknown tools")
+			assert.Equal(t, tt.expectedHasChange, advice.HasChanges(), "has changes")
+		})
+	}
+}
+
+func TestAdviseGitHubToolsetsDoesNotModifyDefaults(t *testing.T) {
+	defaults := append([]string(nil), DefaultGitHubToolsets...)
+	workflowData := &WorkflowData{ParsedTools: &Tools{GitHub: &GitHubToolConfig{ReadOnly: true}}}
+
+	AdviseGitHubToolsets(workflowData, []string{"issue_read"})
+
+	assert.Equal(t, defaults, DefaultGitHubToolsets, "default toolsets should not be sorted in place")
+}
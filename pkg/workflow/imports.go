@@ -114,6 +114,7 @@ func (c *Compiler) MergeNetworkPermissions(topNetwork *NetworkPermissions, impor
 	if topNetwork != nil {
 		result.Allowed = make([]string, len(topNetwork.Allowed))
 		copy(result.Allowed, topNetwork.Allowed)
+		result.EgressReport = topNetwork.EgressReport
 		importsLog.Printf("Starting with %d top-level allowed domains", len(topNetwork.Allowed))
 	}
 
@@ -0,0 +1,171 @@
+// Package workflow provides MCP gateway record and replay support.
+//
+// # MCP Cassettes
+//
+// MCP servers are live dependencies, so an agent run cannot normally be reproduced.
+// The MCP gateway can run in a record/replay proxy mode configured with sandbox.mcp.cassette:
+//
+//   - record: every JSON-RPC request and response that passes through the gateway is
+//     appended to a cassette file, which is uploaded as the mcp-cassette artifact
+//   - replay: the gateway answers requests from a cassette committed to the repository
+//     instead of contacting the MCP servers
+//
+// Recording and replaying are implemented by the gateway, as defined in section 4.1.3.2 of
+// the MCP Gateway Specification (version 1.9.0). A gateway implementing an older version
+// would ignore the cassette and run live, so start_mcp_gateway.sh fails the run when the
+// specVersion reported on /health is older (check_mcp_gateway_spec_version.sh).
+//
+// Example configuration:
+//
+//	sandbox:
+//	  mcp:
+//	    cassette:
+//	      mode: replay
+//	      path: .github/mcp-cassettes/triage.jsonl
+//
+// Related files:
+//   - mcp_gateway_config.go: Gateway configuration passed to the MCP config files
+//   - mcp_setup_generator.go: Exports the cassette path before the gateway starts
+//   - mcp_renderer.go: Renders the cassette section of the gateway configuration
+package workflow
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpCassetteLog = logger.New("workflow:mcp_cassette")
+
+// MCP cassette modes
+const (
+	MCPCassetteModeRecord = "record"
+	MCPCassetteModeReplay = "replay"
+)
+
+// MCPCassetteConfig configures recording or replaying MCP traffic through the gateway
+type MCPCassetteConfig struct {
+	Mode string `yaml:"mode,omitempty"` // record or replay
+	Path string `yaml:"path,omitempty"` // Cassette to replay, relative to the repository root
+}
+
+// extractMCPCassetteConfig extracts the cassette configuration of the MCP gateway
+func extractMCPCassetteConfig(cassetteVal any) *MCPCassetteConfig {
+	cassetteObj, ok := cassetteVal.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	cassette := &MCPCassetteConfig{}
+	if mode, ok := cassetteObj["mode"].(string); ok {
+		cassette.Mode = mode
+	}
+	if cassettePath, ok := cassetteObj["path"].(string); ok {
+		cassette.Path = cassettePath
+	}
+	return cassette
+}
+
+// getMCPCassetteConfig returns the cassette configuration of the workflow, or nil if MCP traffic is live
+func getMCPCassetteConfig(workflowData *WorkflowData) *MCPCassetteConfig {
+	if workflowData == nil || workflowData.SandboxConfig == nil || workflowData.SandboxConfig.MCP == nil {
+		return nil
+	}
+	return workflowData.SandboxConfig.MCP.Cassette
+}
+
+// applyMCPCassetteOverride replaces the cassette configuration of the workflow with the
+// compiler override (set by gh aw trial --mcp-record or --mcp-replay)
+func (c *Compiler) applyMCPCassetteOverride(workflowData *WorkflowData) {
+	if c.mcpCassette == nil {
+		return
+	}
+	mcpCassetteLog.Printf("Applying MCP cassette override: mode=%s, path=%s", c.mcpCassette.Mode, c.mcpCassette.Path)
+	if workflowData.SandboxConfig == nil {
+		workflowData.SandboxConfig = &SandboxConfig{}
+	}
+	if workflowData.SandboxConfig.MCP == nil {
+		workflowData.SandboxConfig.MCP = &MCPGatewayRuntimeConfig{}
+	}
+	override := *c.mcpCassette
+	workflowData.SandboxConfig.MCP.Cassette = &override
+}
+
+// resolveMCPCassettePath returns the cassette path as seen by the gateway.
+// Recorded cassettes are written to the cassette directory; replayed cassettes are read
+// from the workspace, which is mounted into the gateway container.
+func resolveMCPCassettePath(cassette *MCPCassetteConfig) string {
+	if cassette.Mode == MCPCassetteModeRecord {
+		return path.Join(constants.MCPCassetteDir, constants.MCPCassetteFilename)
+	}
+	return "${GITHUB_WORKSPACE}/" + path.Clean(filepath.ToSlash(cassette.Path))
+}
+
+// validateMCPCassetteConfig validates the cassette configuration of the MCP gateway
+func validateMCPCassetteConfig(cassette *MCPCassetteConfig) error {
+	if cassette == nil {
+		return nil
+	}
+
+	switch cassette.Mode {
+	case MCPCassetteModeRecord:
+		if cassette.Path != "" {
+			return NewValidationError(
+				"sandbox.mcp.cassette.path",
+				cassette.Path,
+				"path only applies to replay mode; recorded cassettes are uploaded as the "+constants.MCPCassetteArtifactName+" artifact",
+				"Remove the path or switch to replay mode:\nsandbox:\n  mcp:\n    cassette:\n      mode: record",
+			)
+		}
+	case MCPCassetteModeReplay:
+		if cassette.Path == "" {
+			return NewValidationError(
+				"sandbox.mcp.cassette.path",
+				"",
+				"replay mode requires the path of the cassette to replay",
+				"Commit a recorded cassette and reference it:\nsandbox:\n  mcp:\n    cassette:\n      mode: replay\n      path: .github/mcp-cassettes/my-workflow.jsonl",
+			)
+		}
+		cleaned := path.Clean(filepath.ToSlash(cassette.Path))
+		if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return NewValidationError(
+				"sandbox.mcp.cassette.path",
+				cassette.Path,
+				"cassette path must be relative to the repository root and stay inside the repository",
+				"Use a repository-relative path such as .github/mcp-cassettes/my-workflow.jsonl",
+			)
+		}
+	default:
+		return NewValidationError(
+			"sandbox.mcp.cassette.mode",
+			cassette.Mode,
+			fmt.Sprintf("cassette mode must be '%s' or '%s'", MCPCassetteModeRecord, MCPCassetteModeReplay),
+			"Set the cassette mode:\nsandbox:\n  mcp:\n    cassette:\n      mode: record",
+		)
+	}
+
+	mcpCassetteLog.Printf("Validated MCP cassette configuration: mode=%s", cassette.Mode)
+	return nil
+}
+
+// generateMCPCassetteUpload generates the step that uploads the recorded cassette
+func (c *Compiler) generateMCPCassetteUpload(yaml *strings.Builder, workflowData *WorkflowData) {
+	cassette := getMCPCassetteConfig(workflowData)
+	if cassette == nil || cassette.Mode != MCPCassetteModeRecord {
+		return
+	}
+
+	mcpCassetteLog.Print("Generating MCP cassette upload step")
+	yaml.WriteString("      - name: Upload MCP cassette\n")
+	yaml.WriteString("        if: always()\n")
+	yaml.WriteString("        continue-on-error: true\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
+	yaml.WriteString("        with:\n")
+	fmt.Fprintf(yaml, "          name: %s\n", constants.MCPCassetteArtifactName)
+	fmt.Fprintf(yaml, "          path: %s\n", path.Join(constants.MCPCassetteDir, constants.MCPCassetteFilename))
+	yaml.WriteString("          if-no-files-found: warn\n")
+}
@@ -0,0 +1,144 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMCPCassetteConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		cassette  *MCPCassetteConfig
+		wantError string
+	}{
+		{name: "no cassette"},
+		{name: "record", cassette: &MCPCassetteConfig{Mode: "record"}},
+		{name: "replay", cassette: &MCPCassetteConfig{Mode: "replay", Path: ".github/mcp-cassettes/triage.jsonl"}},
+		{
+			name:      "record with path",
+			cassette:  &MCPCassetteConfig{Mode: "record", Path: "cassette.jsonl"},
+			wantError: "path only applies to replay mode",
+		},
+		{
+			name:      "replay without path",
+			cassette:  &MCPCassetteConfig{Mode: "replay"},
+			wantError: "replay mode requires the path of the cassette to replay",
+		},
+		{
+			name:      "replay with absolute path",
+			cassette:  &MCPCassetteConfig{Mode: "replay", Path: "/tmp/cassette.jsonl"},
+			wantError: "cassette path must be relative to the repository root",
+		},
+		{
+			name:      "replay outside repository",
+			cassette:  &MCPCassetteConfig{Mode: "replay", Path: "cassettes/../../cassette.jsonl"},
+			wantError: "cassette path must be relative to the repository root",
+		},
+		{
+			name:      "unknown mode",
+			cassette:  &MCPCassetteConfig{Mode: "passthrough"},
+			wantError: "cassette mode must be 'record' or 'replay'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMCPCassetteConfig(tt.cassette)
+			if tt.wantError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
+
+func TestResolveMCPCassettePath(t *testing.T) {
+	assert.Equal(t, "/tmp/gh-aw/mcp-cassette/cassette.jsonl", resolveMCPCassettePath(&MCPCassetteConfig{Mode: "record"}))
+	assert.Equal(t, "${GITHUB_WORKSPACE}/.github/mcp-cassettes/triage.jsonl", resolveMCPCassettePath(&MCPCassetteConfig{Mode: "replay", Path: "./.github/mcp-cassettes/triage.jsonl"}))
+}
+
+func compileMCPCassetteWorkflow(t *testing.T, compiler *Compiler, sandbox string) string {
+	t.Helper()
+	tmpDir := testutil.TempDir(t, "mcp-cassette-test")
+
+	content := `---
+on: workflow_dispatch
+engine: copilot
+tools:
+  github:
+    toolsets: [issues]
+` + sandbox + `---
+
+# Triage
+`
+	workflowPath := filepath.Join(tmpDir, "triage.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	return string(lockContent)
+}
+
+func TestCompileWorkflowWithMCPCassette(t *testing.T) {
+	t.Run("record", func(t *testing.T) {
+		lock := compileMCPCassetteWorkflow(t, NewCompiler(), `sandbox:
+  mcp:
+    cassette:
+      mode: record
+`)
+		assert.Contains(t, lock, `export MCP_GATEWAY_CASSETTE_PATH="/tmp/gh-aw/mcp-cassette/cassette.jsonl"`)
+		assert.Contains(t, lock, "-e MCP_GATEWAY_CASSETTE_PATH")
+		assert.Contains(t, lock, `"mode": "record"`)
+		assert.Contains(t, lock, `"path": "${MCP_GATEWAY_CASSETTE_PATH}"`)
+		assert.Contains(t, lock, "name: Upload MCP cassette")
+		assert.Contains(t, lock, "name: mcp-cassette")
+	})
+
+	t.Run("replay", func(t *testing.T) {
+		lock := compileMCPCassetteWorkflow(t, NewCompiler(), `sandbox:
+  mcp:
+    cassette:
+      mode: replay
+      path: .github/mcp-cassettes/triage.jsonl
+`)
+		assert.Contains(t, lock, `export MCP_GATEWAY_CASSETTE_PATH="${GITHUB_WORKSPACE}/.github/mcp-cassettes/triage.jsonl"`)
+		assert.Contains(t, lock, `"mode": "replay"`)
+		assert.NotContains(t, lock, "Upload MCP cassette")
+	})
+
+	t.Run("warns about the required gateway version", func(t *testing.T) {
+		compiler := NewCompiler()
+		compileMCPCassetteWorkflow(t, compiler, "sandbox:\n  mcp:\n    cassette:\n      mode: record\n")
+		withCassette := compiler.GetWarningCount()
+
+		compiler = NewCompiler()
+		compileMCPCassetteWorkflow(t, compiler, "")
+		assert.Equal(t, compiler.GetWarningCount()+1, withCassette, "cassettes should warn that the gateway must implement them")
+	})
+
+	t.Run("live", func(t *testing.T) {
+		lock := compileMCPCassetteWorkflow(t, NewCompiler(), "")
+		assert.NotContains(t, lock, "MCP_GATEWAY_CASSETTE_PATH")
+		assert.NotContains(t, lock, `"cassette"`)
+	})
+
+	t.Run("compiler override", func(t *testing.T) {
+		compiler := NewCompiler()
+		compiler.SetMCPCassette(&MCPCassetteConfig{Mode: MCPCassetteModeRecord})
+		lock := compileMCPCassetteWorkflow(t, compiler, "")
+		assert.Contains(t, lock, `"mode": "record"`)
+		assert.Contains(t, lock, "name: Upload MCP cassette")
+	})
+}
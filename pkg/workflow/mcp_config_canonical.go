@@ -0,0 +1,385 @@
+// Package workflow provides an engine-independent model of rendered MCP configurations.
+//
+// # Canonical MCP Configuration
+//
+// Every engine renders MCP configurations with its own renderer (claude_mcp.go, codex_mcp.go,
+// copilot_mcp.go, custom_engine.go) on top of the shared renderer in mcp_renderer.go. The
+// engines differ in syntax: Copilot adds "type" and "tools" fields and escapes gateway
+// variables as \${VAR}, Claude and Codex reference shell variables as $VAR, and Codex also
+// writes a TOML file. A bug in one renderer typically shows up as a header or environment
+// variable that silently disappears for a single engine.
+//
+// RenderEngineMCPConfig renders the configuration of a workflow through an engine and parses
+// the configuration passed to the MCP gateway back into MCPServerSpec values, together with
+// the tool timeouts passed to the agent step. The specs are
+// normalized so that configurations rendered by different engines can be compared with
+// CompareEngineMCPConfigs:
+//   - Variable references ($VAR, \${VAR}, ${VAR} and ${{ secrets.VAR }}) become ${VAR}
+//   - A missing type is inferred from the launch fields (url: http, otherwise stdio)
+//   - The env of HTTP servers is expanded into the headers and dropped; the gateway only
+//     uses it to pass secrets to the headers
+//
+// Related files:
+//   - mcp_renderer.go: Shared JSON renderer used by all engines
+//   - mcp_setup_generator.go: Collects the MCP tools of a workflow
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpConfigCanonicalLog = logger.New("workflow:mcp_config_canonical")
+
+// MCPServerSpec is the engine-independent description of an MCP server
+type MCPServerSpec struct {
+	Type           string            `json:"type"`
+	Command        string            `json:"command,omitempty"`
+	Container      string            `json:"container,omitempty"`
+	Entrypoint     string            `json:"entrypoint,omitempty"`
+	Args           []string          `json:"args,omitempty"`
+	EntrypointArgs []string          `json:"entrypointArgs,omitempty"`
+	Mounts         []string          `json:"mounts,omitempty"`
+	URL            string            `json:"url,omitempty"`
+	Env            map[string]string `json:"env,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Tools          []string          `json:"tools,omitempty"` // Allowed tools, when the engine passes them in the MCP configuration
+}
+
+// MCPConfigFile is a configuration file written by the MCP setup step
+type MCPConfigFile struct {
+	Target  string `json:"target"` // File path, or the command reading the configuration on stdin
+	Format  string `json:"format"` // "json" or "toml"
+	Content string `json:"content"`
+}
+
+// EngineMCPConfig is the MCP configuration an engine receives for a workflow
+type EngineMCPConfig struct {
+	Engine         string                    `json:"engine"`
+	Servers        map[string]*MCPServerSpec `json:"servers"`
+	StartupTimeout int                       `json:"startupTimeout,omitempty"` // Seconds, as passed to the agent step
+	ToolTimeout    int                       `json:"toolTimeout,omitempty"`    // Seconds, as passed to the agent step
+	Files          []MCPConfigFile           `json:"files,omitempty"`
+}
+
+// mcpGatewayConfigTarget is the command that reads the gateway configuration on stdin
+const mcpGatewayConfigTarget = "bash /opt/gh-aw/actions/start_mcp_gateway.sh"
+
+// RenderEngineMCPConfig renders the MCP configuration of the workflow through the engine
+func RenderEngineMCPConfig(engine CodingAgentEngine, workflowData *WorkflowData) (*EngineMCPConfig, error) {
+	mcpConfigCanonicalLog.Printf("Rendering MCP config: engine=%s", engine.GetID())
+
+	result := &EngineMCPConfig{Engine: engine.GetID(), Servers: map[string]*MCPServerSpec{}, Files: []MCPConfigFile{}}
+	mcpTools := collectMCPToolNames(workflowData)
+	if len(mcpTools) == 0 {
+		return result, nil
+	}
+
+	ensureDefaultMCPGatewayConfig(workflowData)
+	var rendered strings.Builder
+	engine.RenderMCPConfig(&rendered, workflowData.Tools, mcpTools, workflowData)
+
+	result.Files = extractMCPConfigFiles(rendered.String())
+	for _, file := range result.Files {
+		if file.Target != mcpGatewayConfigTarget {
+			continue
+		}
+		servers, err := ParseMCPGatewayConfig(file.Content)
+		if err != nil {
+			return nil, fmt.Errorf("engine %s rendered an invalid MCP gateway configuration: %w", engine.GetID(), err)
+		}
+		result.Servers = servers
+	}
+
+	for _, step := range engine.GetExecutionSteps(workflowData, "/tmp/gh-aw/agent-stdio.log") {
+		for _, line := range step {
+			if match := mcpTimeoutEnvPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				seconds, _ := strconv.Atoi(match[2])
+				if match[1] == "GH_AW_STARTUP_TIMEOUT" {
+					result.StartupTimeout = seconds
+				} else {
+					result.ToolTimeout = seconds
+				}
+			}
+		}
+	}
+
+	mcpConfigCanonicalLog.Printf("Rendered %d config file(s) with %d server(s)", len(result.Files), len(result.Servers))
+	return result, nil
+}
+
+// mcpTimeoutEnvPattern matches the MCP timeout environment variables of the agent step
+var mcpTimeoutEnvPattern = regexp.MustCompile(`^(GH_AW_STARTUP_TIMEOUT|GH_AW_TOOL_TIMEOUT): "?(\d+)"?$`)
+
+// mcpConfigHeredocPattern matches "cat > path << DELIM" and "cat << DELIM | command"
+var mcpConfigHeredocPattern = regexp.MustCompile(`^cat (?:> (\S+) )?<< '?([A-Za-z0-9_]+)'?(?: \| (.+))?$`)
+
+// extractMCPConfigFiles extracts the heredoc configuration files from rendered step lines
+func extractMCPConfigFiles(rendered string) []MCPConfigFile {
+	var files []MCPConfigFile
+	lines := strings.Split(rendered, "\n")
+	for i := 0; i < len(lines); i++ {
+		match := mcpConfigHeredocPattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if match == nil {
+			continue
+		}
+		target := match[1]
+		if target == "" {
+			target = match[3]
+		}
+		indent := lines[i][:len(lines[i])-len(strings.TrimLeft(lines[i], " "))]
+
+		var content []string
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != match[2]; i++ {
+			content = append(content, strings.TrimPrefix(lines[i], indent))
+		}
+
+		text := strings.TrimRight(strings.Join(content, "\n"), " \n") + "\n"
+		format := "toml"
+		if strings.HasPrefix(strings.TrimSpace(text), "{") {
+			format = "json"
+		}
+		files = append(files, MCPConfigFile{Target: target, Format: format, Content: text})
+	}
+	return files
+}
+
+// unescapeHeredoc applies the backslash escapes the shell processes in an unquoted heredoc
+func unescapeHeredoc(content string) string {
+	var result strings.Builder
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\\' && i+1 < len(content) && strings.ContainsRune("$`\\", rune(content[i+1])) {
+			i++
+		}
+		result.WriteByte(content[i])
+	}
+	return result.String()
+}
+
+// escapeCommandSubstitutions escapes the quotes inside $(...) command substitutions, which
+// the shell evaluates before the gateway parses the JSON (e.g. the GitHub lockdown header)
+func escapeCommandSubstitutions(content string) string {
+	var result strings.Builder
+	for i := 0; i < len(content); i++ {
+		if !strings.HasPrefix(content[i:], "$(") {
+			result.WriteByte(content[i])
+			continue
+		}
+		depth := 0
+		end := i + 1
+		for ; end < len(content); end++ {
+			if content[end] == '(' {
+				depth++
+			} else if content[end] == ')' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		if end == len(content) {
+			result.WriteString(content[i:])
+			break
+		}
+		result.WriteString(strings.ReplaceAll(content[i:end+1], `"`, `\"`))
+		i = end
+	}
+	return result.String()
+}
+
+// gatewayPortPattern matches the unquoted port variable expanded by the shell
+var gatewayPortPattern = regexp.MustCompile(`:\s*\$MCP_GATEWAY_PORT\b`)
+
+// ParseMCPGatewayConfig parses an MCP gateway configuration rendered into a heredoc
+func ParseMCPGatewayConfig(content string) (map[string]*MCPServerSpec, error) {
+	content = gatewayPortPattern.ReplaceAllString(escapeCommandSubstitutions(unescapeHeredoc(content)), ": 0")
+
+	var config struct {
+		MCPServers map[string]struct {
+			Type           string            `json:"type"`
+			Command        string            `json:"command"`
+			Container      string            `json:"container"`
+			Entrypoint     string            `json:"entrypoint"`
+			Args           []string          `json:"args"`
+			EntrypointArgs []string          `json:"entrypointArgs"`
+			Mounts         []string          `json:"mounts"`
+			URL            string            `json:"url"`
+			Env            map[string]string `json:"env"`
+			Headers        map[string]string `json:"headers"`
+			Tools          []string          `json:"tools"`
+		} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal([]byte(content), &config); err != nil {
+		return nil, err
+	}
+
+	servers := make(map[string]*MCPServerSpec, len(config.MCPServers))
+	for name, server := range config.MCPServers {
+		spec := &MCPServerSpec{
+			Type:           server.Type,
+			Command:        server.Command,
+			Container:      server.Container,
+			Entrypoint:     server.Entrypoint,
+			Args:           normalizeMCPVariableList(server.Args),
+			EntrypointArgs: normalizeMCPVariableList(server.EntrypointArgs),
+			Mounts:         normalizeMCPVariableList(server.Mounts),
+			URL:            normalizeMCPVariableReferences(server.URL),
+			Env:            normalizeMCPVariableMap(server.Env),
+			Headers:        normalizeMCPVariableMap(server.Headers),
+			Tools:          server.Tools,
+		}
+		if spec.Type == "" {
+			spec.Type = "stdio"
+			if spec.URL != "" {
+				spec.Type = "http"
+			}
+		}
+		if spec.Type == "http" {
+			// The gateway only uses the env of HTTP servers to expand variables in headers
+			for key, value := range spec.Headers {
+				for name, envValue := range spec.Env {
+					value = strings.ReplaceAll(value, "${"+name+"}", envValue)
+				}
+				spec.Headers[key] = value
+			}
+			spec.Env = nil
+		}
+		servers[name] = spec
+	}
+	return servers, nil
+}
+
+// mcpVariablePattern matches ${{ secrets.VAR }}, ${{ env.VAR }}, ${VAR} and $VAR
+var mcpVariablePattern = regexp.MustCompile(`\$\{\{\s*(?:secrets|env)\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}|\$\{([A-Za-z_][A-Za-z0-9_]*)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// normalizeMCPVariableReferences rewrites every variable reference as ${VAR}
+func normalizeMCPVariableReferences(value string) string {
+	return mcpVariablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		groups := mcpVariablePattern.FindStringSubmatch(match)
+		for _, name := range groups[1:] {
+			if name != "" {
+				return "${" + name + "}"
+			}
+		}
+		return match
+	})
+}
+
+func normalizeMCPVariableList(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	normalized := make([]string, len(values))
+	for i, value := range values {
+		normalized[i] = normalizeMCPVariableReferences(value)
+	}
+	return normalized
+}
+
+func normalizeMCPVariableMap(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	normalized := make(map[string]string, len(values))
+	for key, value := range values {
+		normalized[key] = normalizeMCPVariableReferences(value)
+	}
+	return normalized
+}
+
+// CompareEngineMCPConfigs returns the semantic differences between the MCP configurations
+// rendered by two engines. Allowed tools are only compared when both engines pass them in
+// the MCP configuration.
+func CompareEngineMCPConfigs(a *EngineMCPConfig, b *EngineMCPConfig) []string {
+	names := make([]string, 0, len(a.Servers)+len(b.Servers))
+	for name := range a.Servers {
+		names = append(names, name)
+	}
+	for name := range b.Servers {
+		if _, ok := a.Servers[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var differences []string
+	if a.StartupTimeout != b.StartupTimeout {
+		differences = append(differences, fmt.Sprintf("startup-timeout: %s=%d %s=%d", a.Engine, a.StartupTimeout, b.Engine, b.StartupTimeout))
+	}
+	if a.ToolTimeout != b.ToolTimeout {
+		differences = append(differences, fmt.Sprintf("timeout: %s=%d %s=%d", a.Engine, a.ToolTimeout, b.Engine, b.ToolTimeout))
+	}
+	for _, name := range names {
+		specA, okA := a.Servers[name]
+		specB, okB := b.Servers[name]
+		if !okA || !okB {
+			missing := a.Engine
+			if okA {
+				missing = b.Engine
+			}
+			differences = append(differences, fmt.Sprintf("%s: server is missing for %s", name, missing))
+			continue
+		}
+
+		fieldsA := mcpServerSpecFields(specA)
+		fieldsB := mcpServerSpecFields(specB)
+		keys := make([]string, 0, len(fieldsA)+len(fieldsB))
+		for key := range fieldsA {
+			keys = append(keys, key)
+		}
+		for key := range fieldsB {
+			if _, ok := fieldsA[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if key == "tools" && (specA.Tools == nil || specB.Tools == nil) {
+				continue
+			}
+			if fieldsA[key] != fieldsB[key] {
+				differences = append(differences, fmt.Sprintf("%s.%s: %s=%q %s=%q", name, key, a.Engine, fieldsA[key], b.Engine, fieldsB[key]))
+			}
+		}
+	}
+	return differences
+}
+
+// mcpServerSpecFields flattens a spec into comparable fields, one per env var and header
+func mcpServerSpecFields(spec *MCPServerSpec) map[string]string {
+	fields := map[string]string{
+		"type":           spec.Type,
+		"command":        spec.Command,
+		"container":      spec.Container,
+		"entrypoint":     spec.Entrypoint,
+		"args":           strings.Join(spec.Args, " "),
+		"entrypointArgs": strings.Join(spec.EntrypointArgs, " "),
+		"mounts":         strings.Join(spec.Mounts, " "),
+		"url":            spec.URL,
+	}
+	if spec.Tools != nil {
+		tools := slices.Clone(spec.Tools)
+		sort.Strings(tools)
+		fields["tools"] = strings.Join(tools, ",")
+	}
+	for key, value := range spec.Env {
+		fields["env."+key] = value
+	}
+	for key, value := range spec.Headers {
+		fields["headers."+key] = value
+	}
+	for key, value := range fields {
+		if value == "" {
+			delete(fields, key)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,238 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// conformanceEngines are the engines whose MCP configurations must match the reference engine.
+// The custom engine is excluded: it ignores the tools section and runs user-defined steps.
+var conformanceEngines = []string{"copilot", "codex", "copilot-sdk"}
+
+// renderConformanceConfig parses the workflow for the engine and renders its MCP configuration
+func renderConformanceConfig(t *testing.T, workflowPath string, engineID string) *EngineMCPConfig {
+	t.Helper()
+	compiler := NewCompiler(WithEngineOverride(engineID))
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	require.NoError(t, err, "engine %s should parse the workflow", engineID)
+
+	engine, err := GetGlobalEngineRegistry().GetEngine(engineID)
+	require.NoError(t, err)
+	config, err := RenderEngineMCPConfig(engine, workflowData)
+	require.NoError(t, err, "engine %s should render a valid MCP configuration", engineID)
+	return config
+}
+
+// normalizeDockerHost undoes the Copilot SDK rewrite of localhost to the Docker host, which
+// is the one intended difference between the Copilot SDK and the other engines
+func normalizeDockerHost(config *EngineMCPConfig) {
+	replace := func(value string) string {
+		value = strings.ReplaceAll(value, "host.docker.internal", "localhost")
+		return strings.ReplaceAll(value, "127.0.0.1", "localhost")
+	}
+	for _, spec := range config.Servers {
+		spec.URL = replace(spec.URL)
+		for i := range spec.Args {
+			spec.Args[i] = replace(spec.Args[i])
+		}
+		for i := range spec.EntrypointArgs {
+			spec.EntrypointArgs[i] = replace(spec.EntrypointArgs[i])
+		}
+		for key, value := range spec.Env {
+			spec.Env[key] = replace(value)
+		}
+	}
+}
+
+func TestMCPConfigConformance(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter string
+		servers     []string
+		toolTimeout int
+	}{
+		{
+			name: "stdio command",
+			frontmatter: `mcp-servers:
+  notion:
+    command: npx
+    args: ["-y", "@notionhq/notion-mcp-server"]
+    env:
+      NOTION_TOKEN: "${{ secrets.NOTION_TOKEN }}"
+      LOG_LEVEL: debug
+    allowed: [search, get_page]`,
+			servers: []string{"notion"},
+		},
+		{
+			name: "stdio container",
+			frontmatter: `mcp-servers:
+  datadog:
+    container: ghcr.io/example/datadog-mcp
+    version: v1.2.0
+    entrypoint: /usr/bin/datadog-mcp
+    entrypointArgs: ["--read-only"]
+    args: ["--network", "host"]
+    mounts: ["/tmp/data:/data:ro"]
+    env:
+      DD_API_KEY: "${{ secrets.DD_API_KEY }}"
+      DD_SITE: datadoghq.com`,
+			servers: []string{"datadog"},
+		},
+		{
+			name: "http with headers",
+			frontmatter: `mcp-servers:
+  tavily:
+    url: https://mcp.tavily.com/mcp/
+    headers:
+      Authorization: "Bearer ${{ secrets.TAVILY_API_KEY }}"
+      X-Client: gh-aw
+    allowed: ["*"]`,
+			servers: []string{"tavily"},
+		},
+		{
+			name: "builtin tools",
+			frontmatter: `tools:
+  github:
+    toolsets: [repos, issues]
+  playwright:
+    allowed_domains: [example.com]
+  agentic-workflows:
+safe-outputs:
+  create-issue:`,
+			servers: []string{"github", "playwright", "agenticworkflows", "safeoutputs"},
+		},
+		{
+			name: "remote github",
+			frontmatter: `tools:
+  github:
+    mode: remote
+    toolsets: [default]`,
+			servers: []string{"github"},
+		},
+		{
+			name: "safe-inputs",
+			frontmatter: `safe-inputs:
+  greet:
+    description: Greet someone
+    inputs:
+      name:
+        type: string
+    script: |
+      return { message: "Hello " + name };`,
+			servers: []string{"safeinputs"},
+		},
+		{
+			name: "timeouts",
+			frontmatter: `tools:
+  timeout: 90
+  startup-timeout: 45
+  github:`,
+			servers:     []string{"github"},
+			toolTimeout: 90,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := testutil.TempDir(t, "mcp-conformance")
+			workflowPath := filepath.Join(tmpDir, "conformance.md")
+			content := "---\non: workflow_dispatch\npermissions:\n  contents: read\n  issues: read\n" + tt.frontmatter + "\n---\n\n# Conformance\n"
+			require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+			reference := renderConformanceConfig(t, workflowPath, "claude")
+			for _, server := range tt.servers {
+				assert.Contains(t, reference.Servers, server, "reference configuration should contain %s", server)
+			}
+			assert.Equal(t, tt.toolTimeout, reference.ToolTimeout, "reference tool timeout")
+
+			for _, engineID := range conformanceEngines {
+				config := renderConformanceConfig(t, workflowPath, engineID)
+				if engineID == "copilot-sdk" {
+					normalizeDockerHost(config)
+					normalizeDockerHost(reference)
+				}
+				assert.Empty(t, CompareEngineMCPConfigs(reference, config), "engine %s should render the same MCP configuration as claude", engineID)
+			}
+		})
+	}
+}
+
+func TestParseMCPGatewayConfig(t *testing.T) {
+	content := `{
+  "mcpServers": {
+    "github": {
+      "container": "ghcr.io/github/github-mcp-server:v1",
+      "env": {
+        "GITHUB_PERSONAL_ACCESS_TOKEN": "$GITHUB_MCP_SERVER_TOKEN"
+      }
+    },
+    "tavily": {
+      "type": "http",
+      "url": "https://mcp.tavily.com/mcp/",
+      "headers": {
+        "Authorization": "Bearer \${TAVILY_API_KEY}"
+      },
+      "env": {
+        "TAVILY_API_KEY": "\${TAVILY_API_KEY}"
+      },
+      "tools": ["*"]
+    }
+  },
+  "gateway": {
+    "port": $MCP_GATEWAY_PORT
+  }
+}
+`
+	servers, err := ParseMCPGatewayConfig(content)
+	require.NoError(t, err)
+	require.Len(t, servers, 2)
+
+	github := servers["github"]
+	assert.Equal(t, "stdio", github.Type, "missing type should be inferred")
+	assert.Equal(t, map[string]string{"GITHUB_PERSONAL_ACCESS_TOKEN": "${GITHUB_MCP_SERVER_TOKEN}"}, github.Env)
+
+	tavily := servers["tavily"]
+	assert.Equal(t, map[string]string{"Authorization": "Bearer ${TAVILY_API_KEY}"}, tavily.Headers)
+	assert.Nil(t, tavily.Env, "env of HTTP servers should be expanded into the headers")
+	assert.Equal(t, []string{"*"}, tavily.Tools)
+}
+
+func TestCompareEngineMCPConfigs(t *testing.T) {
+	a := &EngineMCPConfig{Engine: "claude", ToolTimeout: 60, Servers: map[string]*MCPServerSpec{
+		"tavily": {Type: "http", URL: "https://mcp.tavily.com/mcp/", Headers: map[string]string{"Authorization": "Bearer ${KEY}"}},
+		"notion": {Type: "stdio", Command: "npx"},
+	}}
+	b := &EngineMCPConfig{Engine: "codex", Servers: map[string]*MCPServerSpec{
+		"tavily": {Type: "http", URL: "https://mcp.tavily.com/mcp/", Tools: []string{"search"}},
+	}}
+
+	assert.Equal(t, []string{
+		"timeout: claude=60 codex=0",
+		"notion: server is missing for codex",
+		`tavily.headers.Authorization: claude="Bearer ${KEY}" codex=""`,
+	}, CompareEngineMCPConfigs(a, b))
+	assert.Empty(t, CompareEngineMCPConfigs(a, a))
+}
+
+func TestNormalizeMCPVariableReferences(t *testing.T) {
+	tests := map[string]string{
+		"$GITHUB_TOKEN":                       "${GITHUB_TOKEN}",
+		"${GITHUB_TOKEN}":                     "${GITHUB_TOKEN}",
+		"${{ secrets.GITHUB_TOKEN }}":         "${GITHUB_TOKEN}",
+		"Bearer ${{ secrets.KEY }}":           "Bearer ${KEY}",
+		"${{ env.HOME }}/data":                "${HOME}/data",
+		"http://localhost:$GH_AW_SERENA_PORT": "http://localhost:${GH_AW_SERENA_PORT}",
+		"plain":                               "plain",
+	}
+	for input, expected := range tests {
+		assert.Equal(t, expected, normalizeMCPVariableReferences(input), "input: %s", input)
+	}
+}
@@ -540,6 +540,7 @@ func getMCPConfig(toolConfig map[string]any, toolName string) (*parser.MCPServer
 		"registry":       true,
 		"allowed":        true,
 		"toolsets":       true, // Added for MCPServerConfig struct
+		"limits":         true, // Call quotas enforced by the MCP gateway
 	}
 
 	for key := range toolConfig {
@@ -148,6 +148,7 @@ func getRawMCPConfig(toolConfig map[string]any) (map[string]any, error) {
 		"retention-days":  true, // for cache-memory
 		"allowed_domains": true, // for playwright tool
 		"allowed-domains": true, // for playwright tool (alternative notation)
+		"limits":          true, // call quotas enforced by the MCP gateway
 	}
 
 	// Check new format: direct fields in tool config
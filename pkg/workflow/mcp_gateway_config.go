@@ -18,6 +18,8 @@
 //   - Domain for gateway access (localhost or host.docker.internal)
 //   - API key for authentication
 //   - Volume mounts for workspace and temporary directories
+//   - Optional cassette for recording or replaying MCP traffic (see mcp_cassette.go)
+//   - Per-server call quotas from tools.<server>.limits (see mcp_tool_limits.go)
 //
 // Configuration flow:
 //  1. ensureDefaultMCPGatewayConfig: Sets defaults if not provided
@@ -122,12 +124,20 @@ func buildMCPGatewayConfig(workflowData *WorkflowData) *MCPGatewayRuntimeConfig
 	// Return gateway config with required fields populated
 	// Use ${...} syntax for environment variable references that will be resolved by the gateway at runtime
 	// Per MCP Gateway Specification v1.0.0 section 4.2, variable expressions use "${VARIABLE_NAME}" syntax
-	return &MCPGatewayRuntimeConfig{
+	gatewayConfig := &MCPGatewayRuntimeConfig{
 		Port:       int(DefaultMCPGatewayPort),   // Will be formatted as "${MCP_GATEWAY_PORT}" in renderer
 		Domain:     "${MCP_GATEWAY_DOMAIN}",      // Gateway variable expression
 		APIKey:     "${MCP_GATEWAY_API_KEY}",     // Gateway variable expression
 		PayloadDir: "${MCP_GATEWAY_PAYLOAD_DIR}", // Gateway variable expression for payload directory
 	}
+	if cassette := getMCPCassetteConfig(workflowData); cassette != nil {
+		gatewayConfig.Cassette = &MCPCassetteConfig{
+			Mode: cassette.Mode,
+			Path: "${MCP_GATEWAY_CASSETTE_PATH}", // Gateway variable expression for the cassette file
+		}
+	}
+	gatewayConfig.Limits = collectMCPToolLimits(workflowData)
+	return gatewayConfig
 }
 
 // isSandboxDisabled checks if sandbox features are completely disabled (sandbox: false)
@@ -956,10 +956,20 @@ func RenderJSONMCPConfig(
 		fmt.Fprintf(&configBuilder, "              \"apiKey\": \"%s\"", options.GatewayConfig.APIKey)
 		// Add payloadDir if specified
 		if options.GatewayConfig.PayloadDir != "" {
-			fmt.Fprintf(&configBuilder, ",\n              \"payloadDir\": \"%s\"\n", options.GatewayConfig.PayloadDir)
-		} else {
-			configBuilder.WriteString("\n")
+			fmt.Fprintf(&configBuilder, ",\n              \"payloadDir\": \"%s\"", options.GatewayConfig.PayloadDir)
+		}
+		// Add cassette if the gateway records or replays MCP traffic
+		if cassette := options.GatewayConfig.Cassette; cassette != nil {
+			configBuilder.WriteString(",\n              \"cassette\": {\n")
+			fmt.Fprintf(&configBuilder, "                \"mode\": \"%s\",\n", cassette.Mode)
+			fmt.Fprintf(&configBuilder, "                \"path\": \"%s\"\n", cassette.Path)
+			configBuilder.WriteString("              }")
+		}
+		// Add call quotas if any MCP server sets limits
+		if len(options.GatewayConfig.Limits) > 0 {
+			renderMCPToolLimits(&configBuilder, options.GatewayConfig.Limits)
 		}
+		configBuilder.WriteString("\n")
 		configBuilder.WriteString("            }\n")
 	} else {
 		configBuilder.WriteString("            }\n")
@@ -62,6 +62,7 @@ package workflow
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -73,19 +74,11 @@ import (
 var mcpSetupGeneratorLog = logger.New("workflow:mcp_setup_generator")
 
 // generateMCPSetup generates the MCP server configuration setup
-func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any, engine CodingAgentEngine, workflowData *WorkflowData) {
-	mcpSetupGeneratorLog.Print("Generating MCP server configuration setup")
-	// Collect tools that need MCP server configuration
+// collectMCPToolNames returns the sorted names of the tools that need an MCP server
+// configuration, including the safe-outputs and safe-inputs servers
+func collectMCPToolNames(workflowData *WorkflowData) []string {
 	var mcpTools []string
-
-	// Check if workflowData is valid before accessing its fields
-	if workflowData == nil {
-		return
-	}
-
-	workflowTools := workflowData.Tools
-
-	for toolName, toolValue := range workflowTools {
+	for toolName, toolValue := range workflowData.Tools {
 		// Skip if the tool is explicitly disabled (set to false)
 		if toolValue == false {
 			continue
@@ -111,6 +104,21 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 		mcpTools = append(mcpTools, "safe-inputs")
 	}
 
+	// Sort tools to ensure stable code generation
+	sort.Strings(mcpTools)
+	return mcpTools
+}
+
+func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any, engine CodingAgentEngine, workflowData *WorkflowData) {
+	mcpSetupGeneratorLog.Print("Generating MCP server configuration setup")
+	// Check if workflowData is valid before accessing its fields
+	if workflowData == nil {
+		return
+	}
+
+	// Collect tools that need MCP server configuration
+	mcpTools := collectMCPToolNames(workflowData)
+
 	// Populate dispatch-workflow file mappings before generating config
 	// This ensures workflow_files is available in the config.json
 	populateDispatchWorkflowFiles(workflowData, c.markdownPath)
@@ -121,9 +129,6 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 		safeOutputConfig = generateSafeOutputsConfig(workflowData)
 	}
 
-	// Sort tools to ensure stable code generation
-	sort.Strings(mcpTools)
-
 	if mcpSetupGeneratorLog.Enabled() {
 		mcpSetupGeneratorLog.Printf("Collected %d MCP tools: %v", len(mcpTools), mcpTools)
 	}
@@ -163,35 +168,17 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 		mcpSetupGeneratorLog.Print("Skipping gh-aw extension installation step (provided by shared/mcp/gh-aw.md import)")
 	}
 
-	// Only install gh-aw if needed and not already provided by imports
-	if hasAgenticWorkflows && !hasGhAwImport {
+	// The openai engine runs its agent loop with gh-aw and installs the extension itself
+	engineInstallsGhAw := engine.GetID() == string(constants.OpenAIEngine)
+
+	// Only install gh-aw if needed and not already provided by imports or the engine
+	if hasAgenticWorkflows && !hasGhAwImport && !engineInstallsGhAw {
 		// Use effective token with precedence: top-level github-token > default
 		effectiveToken := getEffectiveGitHubToken("", workflowData.GitHubToken)
 
-		yaml.WriteString("      - name: Install gh-aw extension\n")
-		yaml.WriteString("        env:\n")
-		fmt.Fprintf(yaml, "          GH_TOKEN: %s\n", effectiveToken)
-		yaml.WriteString("        run: |\n")
-		yaml.WriteString("          # Check if gh-aw extension is already installed\n")
-		yaml.WriteString("          if gh extension list | grep -q \"github/gh-aw\"; then\n")
-		yaml.WriteString("            echo \"gh-aw extension already installed, upgrading...\"\n")
-		yaml.WriteString("            gh extension upgrade gh-aw || true\n")
-		yaml.WriteString("          else\n")
-		yaml.WriteString("            echo \"Installing gh-aw extension...\"\n")
-		yaml.WriteString("            gh extension install github/gh-aw\n")
-		yaml.WriteString("          fi\n")
-		yaml.WriteString("          gh aw --version\n")
-		yaml.WriteString("          # Copy the gh-aw binary to /opt/gh-aw for MCP server containerization\n")
-		yaml.WriteString("          mkdir -p /opt/gh-aw\n")
-		yaml.WriteString("          GH_AW_BIN=$(which gh-aw 2>/dev/null || find ~/.local/share/gh/extensions/gh-aw -name 'gh-aw' -type f 2>/dev/null | head -1)\n")
-		yaml.WriteString("          if [ -n \"$GH_AW_BIN\" ] && [ -f \"$GH_AW_BIN\" ]; then\n")
-		yaml.WriteString("            cp \"$GH_AW_BIN\" /opt/gh-aw/gh-aw\n")
-		yaml.WriteString("            chmod +x /opt/gh-aw/gh-aw\n")
-		yaml.WriteString("            echo \"Copied gh-aw binary to /opt/gh-aw/gh-aw\"\n")
-		yaml.WriteString("          else\n")
-		yaml.WriteString("            echo \"::error::Failed to find gh-aw binary for MCP server\"\n")
-		yaml.WriteString("            exit 1\n")
-		yaml.WriteString("          fi\n")
+		for _, line := range generateGhAwExtensionInstallStep(effectiveToken) {
+			yaml.WriteString(line + "\n")
+		}
 	}
 
 	// Write safe-outputs MCP server if enabled
@@ -517,17 +504,32 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 	yaml.WriteString("          export MCP_GATEWAY_PAYLOAD_DIR=\"" + payloadDir + "\"\n")
 	yaml.WriteString("          mkdir -p \"${MCP_GATEWAY_PAYLOAD_DIR}\"\n")
 
+	// Export the cassette path when the gateway records or replays MCP traffic
+	cassette := getMCPCassetteConfig(workflowData)
+	if cassette != nil {
+		yaml.WriteString("          export MCP_GATEWAY_CASSETTE_PATH=\"" + resolveMCPCassettePath(cassette) + "\"\n")
+		if cassette.Mode == MCPCassetteModeRecord {
+			yaml.WriteString("          mkdir -p \"" + constants.MCPCassetteDir + "\"\n")
+		}
+	}
+
 	yaml.WriteString("          export DEBUG=\"*\"\n")
 	yaml.WriteString("          \n")
 
 	// Export engine type
 	yaml.WriteString("          export GH_AW_ENGINE=\"" + engine.GetID() + "\"\n")
 
+	// Export the fallback and phase engines so the gateway configuration is converted for them too
+	additionalEngines := additionalEngineIDs(workflowData)
+	if len(additionalEngines) > 0 {
+		yaml.WriteString("          export GH_AW_ADDITIONAL_ENGINES=\"" + strings.Join(additionalEngines, " ") + "\"\n")
+	}
+
 	// For Copilot engine with GitHub remote MCP, export GITHUB_PERSONAL_ACCESS_TOKEN
 	// This is needed because the MCP gateway validates ${VAR} references in headers at config load time
 	// and the Copilot MCP config uses ${GITHUB_PERSONAL_ACCESS_TOKEN} in the Authorization header
 	githubTool, hasGitHub := tools["github"]
-	if hasGitHub && getGitHubType(githubTool) == "remote" && engine.GetID() == "copilot" {
+	if hasGitHub && getGitHubType(githubTool) == "remote" && (engine.GetID() == "copilot" || slices.Contains(additionalEngines, "copilot")) {
 		yaml.WriteString("          export GITHUB_PERSONAL_ACCESS_TOKEN=\"$GITHUB_MCP_SERVER_TOKEN\"\n")
 	}
 
@@ -558,6 +560,9 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 	containerCmd += " -e MCP_GATEWAY_DOMAIN"
 	containerCmd += " -e MCP_GATEWAY_API_KEY"
 	containerCmd += " -e MCP_GATEWAY_PAYLOAD_DIR"
+	if cassette != nil {
+		containerCmd += " -e MCP_GATEWAY_CASSETTE_PATH"
+	}
 	containerCmd += " -e DEBUG"
 	// Pass environment variables that MCP servers reference in their config
 	// These are needed because awmg v0.0.12+ validates and resolves ${VAR} patterns at config load time
@@ -734,3 +739,34 @@ func (c *Compiler) generateMCPSetup(yaml *strings.Builder, tools map[string]any,
 	// The MCP gateway is always enabled, even when agent sandbox is disabled
 	engine.RenderMCPConfig(yaml, tools, mcpTools, workflowData)
 }
+
+// generateGhAwExtensionInstallStep generates the step installing the gh-aw extension and
+// copying its binary to /opt/gh-aw/gh-aw, where MCP servers and the openai engine run it
+func generateGhAwExtensionInstallStep(githubToken string) GitHubActionStep {
+	return GitHubActionStep{
+		"      - name: Install gh-aw extension",
+		"        env:",
+		"          GH_TOKEN: " + githubToken,
+		"        run: |",
+		"          # Check if gh-aw extension is already installed",
+		"          if gh extension list | grep -q \"github/gh-aw\"; then",
+		"            echo \"gh-aw extension already installed, upgrading...\"",
+		"            gh extension upgrade gh-aw || true",
+		"          else",
+		"            echo \"Installing gh-aw extension...\"",
+		"            gh extension install github/gh-aw",
+		"          fi",
+		"          gh aw --version",
+		"          # Copy the gh-aw binary to /opt/gh-aw for MCP server containerization",
+		"          mkdir -p /opt/gh-aw",
+		"          GH_AW_BIN=$(which gh-aw 2>/dev/null || find ~/.local/share/gh/extensions/gh-aw -name 'gh-aw' -type f 2>/dev/null | head -1)",
+		"          if [ -n \"$GH_AW_BIN\" ] && [ -f \"$GH_AW_BIN\" ]; then",
+		"            cp \"$GH_AW_BIN\" /opt/gh-aw/gh-aw",
+		"            chmod +x /opt/gh-aw/gh-aw",
+		"            echo \"Copied gh-aw binary to /opt/gh-aw/gh-aw\"",
+		"          else",
+		"            echo \"::error::Failed to find gh-aw binary for MCP server\"",
+		"            exit 1",
+		"          fi",
+	}
+}
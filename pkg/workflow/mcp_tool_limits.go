@@ -0,0 +1,205 @@
+// Package workflow provides per-tool call quotas for MCP servers.
+//
+// # MCP Tool Limits
+//
+// A runaway agent can call the same tool hundreds of times, for example hammering the
+// GitHub search API until the token is rate-limited for the whole organization.
+// tools.<server>.limits caps the number of calls the MCP gateway forwards to a server:
+//
+//	tools:
+//	  github:
+//	    limits:
+//	      calls: 50          # all tools of the server
+//	      per-tool:
+//	        create_issue: 3  # a single tool
+//
+// The limits are compiled into the gateway section of the MCP configuration. The gateway
+// rejects calls beyond a quota with a JSON-RPC error and logs a rate_limited event,
+// which gh aw logs and gh aw audit report from gateway.jsonl.
+//
+// Enforcement is implemented by the gateway, as defined in section 4.1.3.3 of the MCP
+// Gateway Specification (version 1.10.0). A gateway implementing an older version would
+// ignore the limits, so start_mcp_gateway.sh fails the run when the specVersion reported
+// on /health is older (check_mcp_gateway_spec_version.sh).
+//
+// Related files:
+//   - mcp_gateway_config.go: Gateway configuration passed to the MCP config files
+//   - mcp_renderer.go: Renders the limits section of the gateway configuration
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var mcpToolLimitsLog = logger.New("workflow:mcp_tool_limits")
+
+// MCPToolLimits holds the call quotas of an MCP server
+type MCPToolLimits struct {
+	Calls   int            `yaml:"calls,omitempty"`    // Maximum number of calls to the server
+	PerTool map[string]int `yaml:"per-tool,omitempty"` // Maximum number of calls per tool
+}
+
+// extractMCPToolLimits extracts the limits of a tool configuration, or nil if none are set
+func extractMCPToolLimits(toolConfig any) *MCPToolLimits {
+	configMap, ok := toolConfig.(map[string]any)
+	if !ok {
+		return nil
+	}
+	limitsMap, ok := configMap["limits"].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	limits := &MCPToolLimits{}
+	if calls, ok := parseIntValue(limitsMap["calls"]); ok {
+		limits.Calls = calls
+	}
+	if perTool, ok := limitsMap["per-tool"].(map[string]any); ok && len(perTool) > 0 {
+		limits.PerTool = make(map[string]int, len(perTool))
+		for toolName, value := range perTool {
+			if quota, ok := parseIntValue(value); ok {
+				limits.PerTool[toolName] = quota
+			}
+		}
+	}
+	return limits
+}
+
+// collectMCPToolLimits returns the limits of every MCP server in the workflow, keyed by server name
+func collectMCPToolLimits(workflowData *WorkflowData) map[string]*MCPToolLimits {
+	if workflowData == nil {
+		return nil
+	}
+
+	var limits map[string]*MCPToolLimits
+	for serverName, toolConfig := range workflowData.Tools {
+		serverLimits := extractMCPToolLimits(toolConfig)
+		if serverLimits == nil {
+			continue
+		}
+		if limits == nil {
+			limits = make(map[string]*MCPToolLimits)
+		}
+		limits[serverName] = serverLimits
+	}
+
+	mcpToolLimitsLog.Printf("Collected MCP tool limits for %d server(s)", len(limits))
+	return limits
+}
+
+// validateMCPToolLimits validates that every quota can take effect
+func validateMCPToolLimits(tools map[string]any) error {
+	serverNames := make([]string, 0, len(tools))
+	for serverName := range tools {
+		serverNames = append(serverNames, serverName)
+	}
+	sort.Strings(serverNames)
+
+	for _, serverName := range serverNames {
+		limits := extractMCPToolLimits(tools[serverName])
+		if limits == nil {
+			continue
+		}
+		field := fmt.Sprintf("tools.%s.limits", serverName)
+
+		if limits.Calls == 0 && len(limits.PerTool) == 0 {
+			return NewValidationError(
+				field,
+				"",
+				"limits must set calls, per-tool, or both",
+				fmt.Sprintf("Set a quota:\ntools:\n  %s:\n    limits:\n      calls: 50", serverName),
+			)
+		}
+
+		// Tools excluded by allowed can never be called, so a quota for them is a typo
+		var allowed []string
+		if configMap, ok := tools[serverName].(map[string]any); ok {
+			if allowedList, ok := configMap["allowed"].([]any); ok {
+				for _, item := range allowedList {
+					if name, ok := item.(string); ok {
+						allowed = append(allowed, name)
+					}
+				}
+			}
+		}
+
+		toolNames := make([]string, 0, len(limits.PerTool))
+		for toolName := range limits.PerTool {
+			toolNames = append(toolNames, toolName)
+		}
+		sort.Strings(toolNames)
+
+		for _, toolName := range toolNames {
+			quota := limits.PerTool[toolName]
+			if limits.Calls > 0 && quota > limits.Calls {
+				return NewValidationError(
+					field+".per-tool."+toolName,
+					fmt.Sprintf("%d", quota),
+					fmt.Sprintf("per-tool limit exceeds the server limit (calls: %d) and can never be reached", limits.Calls),
+					fmt.Sprintf("Lower the per-tool limit to at most %d or raise calls", limits.Calls),
+				)
+			}
+			if len(allowed) > 0 && !slices.Contains(allowed, "*") && !slices.Contains(allowed, toolName) {
+				return NewValidationError(
+					field+".per-tool."+toolName,
+					toolName,
+					"tool is not in the allowed list of the server",
+					fmt.Sprintf("Use one of the allowed tools: %s", strings.Join(allowed, ", ")),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// renderMCPToolLimits renders the limits section of the gateway configuration
+func renderMCPToolLimits(configBuilder *strings.Builder, limits map[string]*MCPToolLimits) {
+	serverNames := make([]string, 0, len(limits))
+	for serverName := range limits {
+		serverNames = append(serverNames, serverName)
+	}
+	sort.Strings(serverNames)
+
+	configBuilder.WriteString(",\n              \"limits\": {\n")
+	for i, serverName := range serverNames {
+		serverLimits := limits[serverName]
+		fmt.Fprintf(configBuilder, "                \"%s\": {", serverName)
+
+		var fields []string
+		if serverLimits.Calls > 0 {
+			fields = append(fields, fmt.Sprintf("\n                  \"calls\": %d", serverLimits.Calls))
+		}
+		if len(serverLimits.PerTool) > 0 {
+			toolNames := make([]string, 0, len(serverLimits.PerTool))
+			for toolName := range serverLimits.PerTool {
+				toolNames = append(toolNames, toolName)
+			}
+			sort.Strings(toolNames)
+
+			var perTool strings.Builder
+			perTool.WriteString("\n                  \"perTool\": {\n")
+			for j, toolName := range toolNames {
+				fmt.Fprintf(&perTool, "                    \"%s\": %d", toolName, serverLimits.PerTool[toolName])
+				if j < len(toolNames)-1 {
+					perTool.WriteString(",")
+				}
+				perTool.WriteString("\n")
+			}
+			perTool.WriteString("                  }")
+			fields = append(fields, perTool.String())
+		}
+		configBuilder.WriteString(strings.Join(fields, ","))
+
+		configBuilder.WriteString("\n                }")
+		if i < len(serverNames)-1 {
+			configBuilder.WriteString(",")
+		}
+		configBuilder.WriteString("\n")
+	}
+	configBuilder.WriteString("              }")
+}
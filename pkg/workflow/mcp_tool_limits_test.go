@@ -0,0 +1,135 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractMCPToolLimits(t *testing.T) {
+	assert.Nil(t, extractMCPToolLimits(nil))
+	assert.Nil(t, extractMCPToolLimits(map[string]any{"toolsets": []any{"issues"}}))
+	assert.Equal(t, &MCPToolLimits{
+		Calls:   50,
+		PerTool: map[string]int{"create_issue": 3},
+	}, extractMCPToolLimits(map[string]any{
+		"limits": map[string]any{"calls": uint64(50), "per-tool": map[string]any{"create_issue": uint64(3)}},
+	}))
+}
+
+func TestValidateMCPToolLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		tools     map[string]any
+		wantError string
+	}{
+		{
+			name:  "server and per-tool limits",
+			tools: map[string]any{"github": map[string]any{"limits": map[string]any{"calls": 50, "per-tool": map[string]any{"search_code": 10}}}},
+		},
+		{
+			name:  "per-tool limit for allowed tool",
+			tools: map[string]any{"tavily": map[string]any{"allowed": []any{"search"}, "limits": map[string]any{"per-tool": map[string]any{"search": 5}}}},
+		},
+		{
+			name:      "empty limits",
+			tools:     map[string]any{"github": map[string]any{"limits": map[string]any{}}},
+			wantError: "limits must set calls, per-tool, or both",
+		},
+		{
+			name:      "per-tool limit above server limit",
+			tools:     map[string]any{"github": map[string]any{"limits": map[string]any{"calls": 5, "per-tool": map[string]any{"search_code": 10}}}},
+			wantError: "per-tool limit exceeds the server limit (calls: 5)",
+		},
+		{
+			name:      "per-tool limit for tool that is not allowed",
+			tools:     map[string]any{"tavily": map[string]any{"allowed": []any{"search"}, "limits": map[string]any{"per-tool": map[string]any{"extract": 5}}}},
+			wantError: "tool is not in the allowed list of the server",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateMCPToolLimits(tt.tools)
+			if tt.wantError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantError)
+		})
+	}
+}
+
+func TestRenderMCPToolLimits(t *testing.T) {
+	var builder strings.Builder
+	builder.WriteString("{\"port\": 80")
+	renderMCPToolLimits(&builder, map[string]*MCPToolLimits{
+		"github": {Calls: 50, PerTool: map[string]int{"search_code": 10, "create_issue": 3}},
+		"tavily": {PerTool: map[string]int{"search": 5}},
+	})
+	builder.WriteString("}")
+
+	var gateway map[string]any
+	require.NoError(t, json.Unmarshal([]byte(builder.String()), &gateway), builder.String())
+	assert.Equal(t, map[string]any{
+		"github": map[string]any{"calls": float64(50), "perTool": map[string]any{"create_issue": float64(3), "search_code": float64(10)}},
+		"tavily": map[string]any{"perTool": map[string]any{"search": float64(5)}},
+	}, gateway["limits"])
+}
+
+func TestCompileWorkflowWithMCPToolLimits(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "mcp-tool-limits-test")
+
+	content := `---
+on: workflow_dispatch
+engine: copilot
+tools:
+  github:
+    toolsets: [issues]
+    limits:
+      calls: 50
+      per-tool:
+        create_issue: 3
+mcp-servers:
+  tavily:
+    url: https://mcp.tavily.com/mcp/
+    allowed: ["*"]
+    limits:
+      calls: 20
+---
+
+# Triage
+`
+	workflowPath := filepath.Join(tmpDir, "triage.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	withLimits := compiler.GetWarningCount()
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lock := string(lockContent)
+	assert.Contains(t, lock, `"limits": {`)
+	assert.Contains(t, lock, `"create_issue": 3`)
+	assert.Contains(t, lock, `"tavily": {
+                  "calls": 20
+                }`)
+
+	// Limits warn that the gateway must implement them
+	plainPath := filepath.Join(tmpDir, "plain.md")
+	require.NoError(t, os.WriteFile(plainPath, []byte("---\non: workflow_dispatch\nengine: copilot\ntools:\n  github:\n    toolsets: [issues]\n---\n\n# Triage\n"), 0644))
+	compiler = NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(plainPath))
+	assert.Equal(t, compiler.GetWarningCount()+1, withLimits, "limits should warn that the gateway must implement them")
+}
@@ -120,11 +120,11 @@ func TestModelEnvVarInjectionForDetectionJob(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Create a minimal detection workflow (no SafeOutputs)
+			// Create a minimal detection workflow
 			workflowData := &WorkflowData{
-				Name:        "test-detection",
-				AI:          tt.engine,
-				SafeOutputs: nil, // This makes it a detection job
+				Name:            "test-detection",
+				AI:              tt.engine,
+				ThreatDetection: true,
 				Tools: map[string]any{
 					"bash": []any{"cat", "grep"},
 				},
@@ -154,6 +154,17 @@ func TestModelEnvVarInjectionForDetectionJob(t *testing.T) {
 				t.Errorf("Expected environment variable %s not found in detection steps:\n%s", tt.expectedEnvVar, stepsContent)
 			}
 
+			// Agent runs without safe outputs (such as phases without safe outputs) are not detection runs
+			agentData := *workflowData
+			agentData.ThreatDetection = false
+			var agentSteps strings.Builder
+			for _, step := range engine.GetExecutionSteps(&agentData, "/tmp/agent.log") {
+				agentSteps.WriteString(strings.Join(step, "\n"))
+			}
+			if strings.Contains(agentSteps.String(), tt.expectedEnvVar+":") {
+				t.Errorf("Agent steps without safe outputs should not use %s:\n%s", tt.expectedEnvVar, agentSteps.String())
+			}
+
 			// For Copilot, verify it has the default detection model as fallback
 			if tt.expectedDefault != "" {
 				expectedEnvLine := tt.expectedEnvVar + ": ${{ vars." + tt.expectedEnvVar + " || '" + tt.expectedDefault + "' }}"
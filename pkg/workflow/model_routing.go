@@ -0,0 +1,233 @@
+// This file provides runtime model routing for agentic workflows.
+//
+// # Model Routing
+//
+// The engine model can be a single model name or a set of routing rules evaluated when
+// the workflow runs:
+//
+//	engine:
+//	  id: claude
+//	  model:
+//	    default: claude-sonnet-4
+//	    rules:
+//	      - event: issues
+//	        labels: [triage]
+//	        model: claude-haiku-4-5
+//	      - event: pull_request
+//	        changed-files: 20
+//	        model: claude-opus-4-5
+//	      - event: schedule
+//	        model: claude-haiku-4-5
+//
+// The conditions of a rule are combined with AND, and the first matching rule wins.
+// The rules are compiled with the expression builder into a single GitHub Actions
+// expression emitted as the "model" output of the activation job. The agent job reads
+// that output through the engine's agent model environment variable
+// (e.g. GH_AW_MODEL_AGENT_CLAUDE), so engines pass --model only when a model is selected.
+// When no rule matches and no default is set, the repository variable is used as usual.
+//
+// The selected model is recorded in aw_info.json so `gh aw logs` can group cost by model.
+
+package workflow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var modelRoutingLog = logger.New("workflow:model_routing")
+
+// modelRoutingOutput is the activation job output holding the model selected by the routing rules
+const modelRoutingOutput = "model"
+
+// ModelRoutingConfig is the model routing configuration of an engine
+type ModelRoutingConfig struct {
+	Default string      // Model used when no rule matches (empty = repository variable or engine default)
+	Rules   []ModelRule // Rules evaluated in order; the first matching rule selects the model
+}
+
+// ModelRule selects a model when all its conditions match
+type ModelRule struct {
+	Model        string
+	Events       []string // Triggering events (github.event_name)
+	Labels       []string // Labels of the triggering issue or pull request (any of)
+	ChangedFiles int      // Minimum number of files changed by the triggering pull request (exclusive)
+}
+
+// modelRoutingEngines lists the engines that read the agent model from an environment variable
+var modelRoutingEngines = []string{"claude", "codex", "copilot", "openai"}
+
+var (
+	modelNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/@-]*$`)
+	eventNamePattern = regexp.MustCompile(`^[a-z_]+$`)
+)
+
+// parseModelRoutingConfig parses the object form of the engine model field
+func parseModelRoutingConfig(modelObj map[string]any) *ModelRoutingConfig {
+	config := &ModelRoutingConfig{}
+	if defaultModel, ok := modelObj["default"].(string); ok {
+		config.Default = defaultModel
+	}
+
+	rules, _ := modelObj["rules"].([]any)
+	for _, item := range rules {
+		ruleObj, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		rule := ModelRule{}
+		rule.Model, _ = ruleObj["model"].(string)
+		rule.Events = parseStringOrList(ruleObj["event"])
+		rule.Labels = parseStringOrList(ruleObj["labels"])
+		switch changedFiles := ruleObj["changed-files"].(type) {
+		case int:
+			rule.ChangedFiles = changedFiles
+		case uint64:
+			rule.ChangedFiles = int(changedFiles)
+		case float64:
+			rule.ChangedFiles = int(changedFiles)
+		}
+		config.Rules = append(config.Rules, rule)
+	}
+
+	modelRoutingLog.Printf("Parsed model routing: default=%q, rules=%d", config.Default, len(config.Rules))
+	return config
+}
+
+// parseStringOrList parses a frontmatter value that is either a string or a list of strings
+func parseStringOrList(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var result []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// validateModelRouting validates the model routing rules of an engine
+func validateModelRouting(engineSetting string, engineConfig *EngineConfig) error {
+	if engineConfig == nil || engineConfig.ModelRouting == nil {
+		return nil
+	}
+	routing := engineConfig.ModelRouting
+
+	engineValidationLog.Printf("Validating %d model routing rules for %s", len(routing.Rules), engineSetting)
+
+	supported := false
+	for _, id := range modelRoutingEngines {
+		if engineSetting == id {
+			supported = true
+		}
+	}
+	if !supported {
+		return fmt.Errorf("model routing rules are not supported by the %s engine. Use a single model name or one of these engines: %s.\n\nSee: %s", engineSetting, strings.Join(modelRoutingEngines, ", "), constants.DocsEnginesURL)
+	}
+
+	if len(routing.Rules) == 0 {
+		return fmt.Errorf("engine model routing requires at least one rule in 'model.rules'. Use 'model: <name>' for a single model.\n\nExample:\nengine:\n  id: claude\n  model:\n    default: claude-sonnet-4\n    rules:\n      - event: schedule\n        model: claude-haiku-4-5\n\nSee: %s", constants.DocsEnginesURL)
+	}
+	if routing.Default != "" && !modelNamePattern.MatchString(routing.Default) {
+		return fmt.Errorf("invalid default model '%s' in engine model routing: model names may only contain letters, digits and . _ : / @ -", routing.Default)
+	}
+
+	for i, rule := range routing.Rules {
+		if rule.Model == "" {
+			return fmt.Errorf("engine model routing rule %d is missing 'model'", i+1)
+		}
+		if !modelNamePattern.MatchString(rule.Model) {
+			return fmt.Errorf("invalid model '%s' in engine model routing rule %d: model names may only contain letters, digits and . _ : / @ -", rule.Model, i+1)
+		}
+		if len(rule.Events) == 0 && len(rule.Labels) == 0 && rule.ChangedFiles == 0 {
+			return fmt.Errorf("engine model routing rule %d (%s) has no condition: set 'event', 'labels' or 'changed-files'", i+1, rule.Model)
+		}
+		for _, event := range rule.Events {
+			if !eventNamePattern.MatchString(event) {
+				return fmt.Errorf("invalid event '%s' in engine model routing rule %d: use a GitHub Actions event name such as issues, pull_request or schedule", event, i+1)
+			}
+		}
+		for _, label := range rule.Labels {
+			if label == "" || strings.ContainsAny(label, "'\n") {
+				return fmt.Errorf("invalid label '%s' in engine model routing rule %d: labels must not be empty or contain quotes", label, i+1)
+			}
+		}
+		if rule.ChangedFiles < 0 {
+			return fmt.Errorf("invalid changed-files %d in engine model routing rule %d: must be a positive number", rule.ChangedFiles, i+1)
+		}
+	}
+
+	return nil
+}
+
+// buildCondition returns the condition of a rule, combining its conditions with AND
+func (r ModelRule) buildCondition() ConditionNode {
+	var conditions []ConditionNode
+
+	if len(r.Events) > 0 {
+		var terms []ConditionNode
+		for _, event := range r.Events {
+			terms = append(terms, BuildEventTypeEquals(event))
+		}
+		conditions = append(conditions, BuildDisjunction(false, terms...))
+	}
+
+	if len(r.Labels) > 0 {
+		var terms []ConditionNode
+		for _, label := range r.Labels {
+			terms = append(terms,
+				BuildLabelContains(label),
+				BuildContains(BuildPropertyAccess("github.event.pull_request.labels.*.name"), BuildStringLiteral(label)),
+			)
+		}
+		conditions = append(conditions, BuildDisjunction(false, terms...))
+	}
+
+	if r.ChangedFiles > 0 {
+		conditions = append(conditions, BuildComparison(
+			BuildPropertyAccess("github.event.pull_request.changed_files"),
+			">",
+			BuildNumberLiteral(fmt.Sprintf("%d", r.ChangedFiles)),
+		))
+	}
+
+	condition := conditions[0]
+	for _, next := range conditions[1:] {
+		condition = BuildAnd(condition, next)
+	}
+	return condition
+}
+
+// buildModelSelectionExpression compiles the routing rules into an expression evaluating to the
+// selected model: "(rule1) && 'model1' || (rule2) && 'model2' || default"
+func (m *ModelRoutingConfig) buildModelSelectionExpression(engineID string) string {
+	var terms []ConditionNode
+	for _, rule := range m.Rules {
+		terms = append(terms, BuildAnd(rule.buildCondition(), BuildStringLiteral(rule.Model)))
+	}
+	if m.Default != "" {
+		terms = append(terms, BuildStringLiteral(m.Default))
+	} else {
+		terms = append(terms, BuildPropertyAccess("vars."+agentModelEnvVar(engineID)), BuildStringLiteral(""))
+	}
+	return fmt.Sprintf("${{ %s }}", BuildDisjunction(false, terms...).Render())
+}
+
+// agentModelEnvValue returns the value of the agent model environment variable of an engine:
+// the model selected by the activation job when routing rules are configured, otherwise the
+// repository variable
+func agentModelEnvValue(workflowData *WorkflowData, envVar string) string {
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.ModelRouting != nil {
+		return fmt.Sprintf("${{ needs.%s.outputs.%s }}", constants.ActivationJobName, modelRoutingOutput)
+	}
+	return fmt.Sprintf("${{ vars.%s || '' }}", envVar)
+}
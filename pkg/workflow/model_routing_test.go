@@ -0,0 +1,165 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModelRoutingConfig(t *testing.T) {
+	compiler := NewCompiler()
+	_, config := compiler.ExtractEngineConfig(map[string]any{
+		"engine": map[string]any{
+			"id": "claude",
+			"model": map[string]any{
+				"default": "claude-sonnet-4",
+				"rules": []any{
+					map[string]any{"event": "issues", "labels": []any{"triage"}, "model": "claude-haiku-4-5"},
+					map[string]any{"event": []any{"pull_request", "pull_request_target"}, "changed-files": 20, "model": "claude-opus-4-5"},
+				},
+			},
+		},
+	})
+
+	require.NotNil(t, config)
+	assert.Empty(t, config.Model, "the static model should be empty when routing rules are set")
+	require.NotNil(t, config.ModelRouting)
+	assert.Equal(t, "claude-sonnet-4", config.ModelRouting.Default)
+	assert.Equal(t, []ModelRule{
+		{Model: "claude-haiku-4-5", Events: []string{"issues"}, Labels: []string{"triage"}},
+		{Model: "claude-opus-4-5", Events: []string{"pull_request", "pull_request_target"}, ChangedFiles: 20},
+	}, config.ModelRouting.Rules)
+}
+
+func TestValidateModelRouting(t *testing.T) {
+	valid := &ModelRoutingConfig{Rules: []ModelRule{{Model: "gpt-5-mini", Events: []string{"schedule"}}}}
+
+	tests := []struct {
+		name    string
+		engine  string
+		routing *ModelRoutingConfig
+		errMsg  string
+	}{
+		{name: "valid", engine: "codex", routing: valid},
+		{name: "unsupported engine", engine: "custom", routing: valid, errMsg: "not supported by the custom engine"},
+		{name: "no rules", engine: "claude", routing: &ModelRoutingConfig{Default: "claude-sonnet-4"}, errMsg: "at least one rule"},
+		{name: "missing model", engine: "claude", routing: &ModelRoutingConfig{Rules: []ModelRule{{Events: []string{"issues"}}}}, errMsg: "missing 'model'"},
+		{name: "no condition", engine: "claude", routing: &ModelRoutingConfig{Rules: []ModelRule{{Model: "claude-haiku-4-5"}}}, errMsg: "has no condition"},
+		{name: "invalid model", engine: "claude", routing: &ModelRoutingConfig{Rules: []ModelRule{{Model: "x' || 'y", Events: []string{"issues"}}}}, errMsg: "invalid model"},
+		{name: "invalid label", engine: "claude", routing: &ModelRoutingConfig{Rules: []ModelRule{{Model: "m", Labels: []string{"it's"}}}}, errMsg: "invalid label"},
+		{name: "invalid event", engine: "claude", routing: &ModelRoutingConfig{Rules: []ModelRule{{Model: "m", Events: []string{"Issues"}}}}, errMsg: "invalid event"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateModelRouting(tt.engine, &EngineConfig{ID: tt.engine, ModelRouting: tt.routing})
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+
+	assert.NoError(t, validateModelRouting("claude", &EngineConfig{ID: "claude", Model: "claude-sonnet-4"}))
+}
+
+func TestBuildModelSelectionExpression(t *testing.T) {
+	routing := &ModelRoutingConfig{
+		Default: "claude-sonnet-4",
+		Rules: []ModelRule{
+			{Model: "claude-haiku-4-5", Events: []string{"issues"}, Labels: []string{"triage"}},
+			{Model: "claude-opus-4-5", Events: []string{"pull_request"}, ChangedFiles: 20},
+			{Model: "claude-haiku-4-5", Events: []string{"schedule", "workflow_dispatch"}},
+		},
+	}
+
+	assert.Equal(t, "${{ "+
+		"((github.event_name == 'issues') && (contains(github.event.issue.labels.*.name, 'triage') || contains(github.event.pull_request.labels.*.name, 'triage'))) && ('claude-haiku-4-5') || "+
+		"((github.event_name == 'pull_request') && (github.event.pull_request.changed_files > 20)) && ('claude-opus-4-5') || "+
+		"(github.event_name == 'schedule' || github.event_name == 'workflow_dispatch') && ('claude-haiku-4-5') || "+
+		"'claude-sonnet-4' }}", routing.buildModelSelectionExpression("claude"))
+
+	routing.Default = ""
+	assert.True(t, strings.HasSuffix(routing.buildModelSelectionExpression("copilot"), "|| vars.GH_AW_MODEL_AGENT_COPILOT || '' }}"),
+		"without a default the repository variable should be used")
+}
+
+func TestCompileWorkflowWithModelRouting(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "model-routing")
+	workflowPath := filepath.Join(tmpDir, "routed.md")
+	content := `---
+on:
+  issues:
+    types: [opened, labeled]
+  schedule:
+    - cron: "0 9 * * 1"
+permissions:
+  contents: read
+engine:
+  id: claude
+  model:
+    default: claude-sonnet-4
+    rules:
+      - event: issues
+        labels: [triage]
+        model: claude-haiku-4-5
+---
+
+# Routed
+
+Triage the issue.
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "routed.lock.yml"))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "model: ${{ ((github.event_name == 'issues') && (contains(github.event.issue.labels.*.name, 'triage')")
+	assert.Contains(t, lock, "|| 'claude-sonnet-4' }}")
+	assert.Equal(t, 2, strings.Count(lock, "GH_AW_MODEL_AGENT_CLAUDE: ${{ needs.activation.outputs.model }}"),
+		"the routed model should be passed to the agent step and recorded in aw_info.json")
+	assert.Contains(t, lock, `${GH_AW_MODEL_AGENT_CLAUDE:+ --model "$GH_AW_MODEL_AGENT_CLAUDE"}`)
+	assert.NotContains(t, lock, "vars.GH_AW_MODEL_AGENT_CLAUDE")
+}
+
+func TestCompileWorkflowWithModelRoutingOverride(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "model-routing-override")
+	workflowPath := filepath.Join(tmpDir, "routed.md")
+	content := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine:
+  id: copilot
+  model:
+    rules:
+      - event: workflow_dispatch
+        model: gpt-5-mini
+---
+
+# Routed
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler(WithModelOverride("gpt-5"))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "routed.lock.yml"))
+	require.NoError(t, err)
+	lock := string(lockContent)
+	assert.NotContains(t, lock, "needs.activation.outputs.model", "a model override should replace the routing rules")
+	assert.Contains(t, lock, "--model gpt-5")
+}
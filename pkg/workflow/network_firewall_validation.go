@@ -2,6 +2,7 @@
 //
 // This file contains domain-specific validation functions for network firewall configuration:
 //   - validateNetworkFirewallConfig() - Validates firewall configuration dependencies
+//   - validateEgressReportConfig() - Validates the egress report alert list and action
 //
 // These validation functions are organized in a dedicated file following the validation
 // architecture pattern where domain-specific validation belongs in domain validation files.
@@ -10,6 +11,8 @@
 package workflow
 
 import (
+	"fmt"
+
 	"github.com/github/gh-aw/pkg/constants"
 	"github.com/github/gh-aw/pkg/logger"
 )
@@ -47,3 +50,40 @@ func validateNetworkFirewallConfig(networkPermissions *NetworkPermissions) error
 
 	return nil
 }
+
+// validateEgressReportConfig validates the alert list and alert action of the egress report
+func validateEgressReportConfig(workflowData *WorkflowData) error {
+	config := getEgressReportConfig(workflowData)
+	if config == nil || config.Disabled {
+		return nil
+	}
+
+	networkFirewallValidationLog.Print("Validating egress report configuration")
+
+	if config.OnAlert != "" && config.OnAlert != "warn" && config.OnAlert != "fail" {
+		return NewValidationError(
+			"network.egress-report.on-alert",
+			config.OnAlert,
+			"on-alert must be 'warn' or 'fail'",
+			"Use 'warn' to report blocked requests to alert domains as warnings, or 'fail' to fail the agent job.\n\nSee: "+string(constants.DocsNetworkURL),
+		)
+	}
+
+	for i, domain := range config.Alert {
+		if err := validateDomainPattern(domain); err != nil {
+			return fmt.Errorf("network.egress-report.alert[%d]: %w", i, err)
+		}
+	}
+
+	if len(config.Alert) > 0 && !isFirewallEnabled(workflowData) {
+		return NewValidationError(
+			"network.egress-report.alert",
+			fmt.Sprintf("%d domains", len(config.Alert)),
+			"the egress report is generated from the AWF firewall logs, which are not available when the firewall is disabled",
+			"Run the agent in the default AWF sandbox or remove the egress-report alert list.\n\nSee: "+string(constants.DocsNetworkURL),
+		)
+	}
+
+	networkFirewallValidationLog.Printf("Validated egress report: alert=%d, on-alert=%s", len(config.Alert), config.OnAlert)
+	return nil
+}
@@ -0,0 +1,539 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/stringutil"
+)
+
+var openAIEngineLog = logger.New("workflow:openai_engine")
+
+// openAIAgentCommand is the gh-aw binary running the agent loop, installed by the engine
+const openAIAgentCommand = "/opt/gh-aw/gh-aw"
+
+// openAIMCPConfigPath is the MCP configuration written by the gateway converter for the agent loop
+const openAIMCPConfigPath = "/tmp/gh-aw/mcp-config/mcp-servers.json"
+
+// OpenAIEngine represents the engine for OpenAI-compatible chat completions endpoints
+// (vLLM, Ollama, internal gateways). The agent loop is the gh-aw agent command, which
+// calls MCP tools through the MCP gateway and writes a stream-json log.
+type OpenAIEngine struct {
+	BaseEngine
+}
+
+func NewOpenAIEngine() *OpenAIEngine {
+	return &OpenAIEngine{
+		BaseEngine: BaseEngine{
+			id:                     "openai",
+			displayName:            "OpenAI-compatible",
+			description:            "Runs the gh-aw agent loop against an OpenAI-compatible chat completions endpoint",
+			experimental:           true,
+			supportsToolsAllowlist: true,
+			supportsHTTPTransport:  true,  // MCP servers are reached through the MCP gateway over HTTP
+			supportsMaxTurns:       true,  // The agent loop enforces max-turns
+			supportsWebFetch:       false, // No built-in web-fetch tool
+			supportsWebSearch:      false, // No built-in web-search tool
+			supportsFirewall:       true,  // The agent loop runs inside AWF
+			supportsLLMGateway:     false, // The endpoint is called directly, so there is no api-proxy usage log
+		},
+	}
+}
+
+// GetRequiredSecretNames returns the list of secrets used by the openai engine
+// The API key is only required by endpoints that are not served on the runner itself
+func (e *OpenAIEngine) GetRequiredSecretNames(workflowData *WorkflowData) []string {
+	var secrets []string
+	if openAIEndpointRequiresAPIKey(workflowData) {
+		secrets = append(secrets, "OPENAI_COMPATIBLE_API_KEY")
+	}
+
+	// Add MCP gateway API key if MCP servers are present (gateway is always started with MCP servers)
+	if HasMCPServers(workflowData) {
+		secrets = append(secrets, "MCP_GATEWAY_API_KEY")
+	}
+
+	// Add safe-inputs secret names
+	if IsSafeInputsEnabled(workflowData.SafeInputs, workflowData) {
+		safeInputsSecrets := collectSafeInputsSecrets(workflowData.SafeInputs)
+		for varName := range safeInputsSecrets {
+			secrets = append(secrets, varName)
+		}
+	}
+
+	return secrets
+}
+
+// GetInstallationSteps installs the gh-aw extension, which provides the agent loop, and AWF
+func (e *OpenAIEngine) GetInstallationSteps(workflowData *WorkflowData) []GitHubActionStep {
+	openAIEngineLog.Printf("Generating installation steps for openai engine: workflow=%s", workflowData.Name)
+
+	// Skip installation if custom command is specified
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.Command != "" {
+		openAIEngineLog.Printf("Skipping installation steps: custom command specified (%s)", workflowData.EngineConfig.Command)
+		return []GitHubActionStep{}
+	}
+
+	effectiveToken := getEffectiveGitHubToken("", workflowData.GitHubToken)
+	steps := []GitHubActionStep{generateOpenAIAgentInstallStep(effectiveToken)}
+
+	// Add AWF installation step if firewall is enabled
+	if isFirewallEnabled(workflowData) {
+		firewallConfig := getFirewallConfig(workflowData)
+		agentConfig := getAgentConfig(workflowData)
+		var awfVersion string
+		if firewallConfig != nil {
+			awfVersion = firewallConfig.Version
+		}
+
+		// Install AWF binary (or skip if custom command is specified)
+		awfInstall := generateAWFInstallationStep(awfVersion, agentConfig)
+		if len(awfInstall) > 0 {
+			steps = append(steps, awfInstall)
+		}
+	}
+
+	return steps
+}
+
+// openAIEndpointRequiresAPIKey reports whether the endpoint needs the OPENAI_COMPATIBLE_API_KEY secret.
+// Endpoints served on the runner (host.docker.internal or localhost) are called without an API key.
+func openAIEndpointRequiresAPIKey(workflowData *WorkflowData) bool {
+	if workflowData.EngineConfig == nil || workflowData.EngineConfig.BaseURL == "" {
+		return true
+	}
+	switch stringutil.ExtractDomainFromURL(workflowData.EngineConfig.BaseURL) {
+	case "host.docker.internal", "localhost", "127.0.0.1":
+		return false
+	}
+	return true
+}
+
+// generateOpenAIAgentInstallStep installs the gh-aw extension providing the agent loop and copies
+// its binary to /opt/gh-aw. Release builds of the compiler pin the extension to their own version,
+// so the agent loop matches the compiled workflow; development builds install the latest release.
+func generateOpenAIAgentInstallStep(githubToken string) GitHubActionStep {
+	installCommand := "gh extension install github/gh-aw"
+	if version := GetVersion(); IsReleasedVersion(version) {
+		installCommand += " --pin " + version
+	}
+	return GitHubActionStep{
+		"      - name: Install gh-aw extension",
+		"        env:",
+		"          GH_TOKEN: " + githubToken,
+		"        run: |",
+		"          gh extension remove gh-aw >/dev/null 2>&1 || true",
+		"          " + installCommand,
+		"          gh aw --version",
+		"          # Copy the gh-aw binary to /opt/gh-aw for the agent loop",
+		"          mkdir -p /opt/gh-aw",
+		"          GH_AW_BIN=$(which gh-aw 2>/dev/null || find ~/.local/share/gh/extensions/gh-aw -name 'gh-aw' -type f 2>/dev/null | head -1)",
+		"          if [ -n \"$GH_AW_BIN\" ] && [ -f \"$GH_AW_BIN\" ]; then",
+		"            cp \"$GH_AW_BIN\" " + openAIAgentCommand,
+		"            chmod +x " + openAIAgentCommand,
+		"          else",
+		"            echo \"::error::Failed to find the gh-aw binary for the agent loop\"",
+		"            exit 1",
+		"          fi",
+	}
+}
+
+// GetExecutionSteps returns the GitHub Actions steps for running the agent loop
+func (e *OpenAIEngine) GetExecutionSteps(workflowData *WorkflowData, logFile string) []GitHubActionStep {
+	firewallEnabled := isFirewallEnabled(workflowData)
+	openAIEngineLog.Printf("Generating execution steps for openai engine: workflow=%s, firewall=%v", workflowData.Name, firewallEnabled)
+
+	// Handle custom steps if they exist in engine config
+	steps := InjectCustomEngineSteps(workflowData, e.convertStepToYAML)
+
+	var baseURL string
+	if workflowData.EngineConfig != nil {
+		baseURL = workflowData.EngineConfig.BaseURL
+	}
+
+	agentArgs := []string{"agent", "--base-url", baseURL}
+
+	// Model can be configured via:
+	// 1. Explicit model in workflow config (highest priority)
+	// 2. GH_AW_MODEL_AGENT_OPENAI environment variable (set via GitHub Actions variables)
+	modelConfigured := workflowData.EngineConfig != nil && workflowData.EngineConfig.Model != ""
+	if modelConfigured {
+		agentArgs = append(agentArgs, "--model", workflowData.EngineConfig.Model)
+	}
+
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.MaxTurns != "" {
+		openAIEngineLog.Printf("Setting max turns: %s", workflowData.EngineConfig.MaxTurns)
+		agentArgs = append(agentArgs, "--max-turns", workflowData.EngineConfig.MaxTurns)
+	}
+
+	// Add MCP configuration only if there are MCP servers
+	if HasMCPServers(workflowData) {
+		agentArgs = append(agentArgs, "--mcp-config", openAIMCPConfigPath)
+	}
+
+	agentArgs = append(agentArgs, e.computeOpenAIToolArguments(workflowData)...)
+
+	if workflowData.AgentFile != "" {
+		openAIEngineLog.Printf("Using custom agent file: %s", workflowData.AgentFile)
+		agentArgs = append(agentArgs, "--agent-file", ResolveAgentFilePath(workflowData.AgentFile))
+	}
+
+	// Add custom args from engine configuration before the prompt
+	if workflowData.EngineConfig != nil && len(workflowData.EngineConfig.Args) > 0 {
+		agentArgs = append(agentArgs, workflowData.EngineConfig.Args...)
+	}
+
+	agentArgs = append(agentArgs, "--prompt-file", "/tmp/gh-aw/aw-prompts/prompt.txt")
+
+	commandName := openAIAgentCommand
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.Command != "" {
+		commandName = workflowData.EngineConfig.Command
+		openAIEngineLog.Printf("Using custom command: %s", commandName)
+	}
+	agentCommand := shellJoinArgs(append([]string{commandName}, agentArgs...))
+
+	// Add conditional model flag if not explicitly configured
+	// Check if this is a detection job (has no SafeOutputs config)
+	isDetectionJob := isThreatDetectionData(workflowData)
+	modelEnvVar := constants.EnvVarModelAgentOpenAI
+	if isDetectionJob {
+		modelEnvVar = constants.EnvVarModelDetectionOpenAI
+	}
+	if !modelConfigured {
+		agentCommand = fmt.Sprintf(`%s${%s:+ --model "$%s"}`, agentCommand, modelEnvVar, modelEnvVar)
+	}
+
+	// Build the full command based on whether firewall is enabled
+	// The agent loop writes stream-json to stdout and diagnostics to stderr; both go to the log file
+	var command string
+	if firewallEnabled {
+		firewallConfig := getFirewallConfig(workflowData)
+		agentConfig := getAgentConfig(workflowData)
+		var awfLogLevel = "info"
+		if firewallConfig != nil && firewallConfig.LogLevel != "" {
+			awfLogLevel = firewallConfig.LogLevel
+		}
+
+		// Get allowed domains (base URL host + network permissions + HTTP MCP server URLs + runtime ecosystem domains)
+		allowedDomains := GetOpenAIAllowedDomainsWithToolsAndRuntimes(baseURL, workflowData.NetworkPermissions, workflowData.Tools, workflowData.Runtimes)
+
+		var awfArgs []string
+
+		// Pass all environment variables to the container
+		awfArgs = append(awfArgs, "--env-all")
+
+		// Set container working directory to match GITHUB_WORKSPACE
+		awfArgs = append(awfArgs, "--container-workdir", "\"${GITHUB_WORKSPACE}\"")
+
+		// Add custom mounts from agent config if specified
+		if agentConfig != nil && len(agentConfig.Mounts) > 0 {
+			sortedMounts := make([]string, len(agentConfig.Mounts))
+			copy(sortedMounts, agentConfig.Mounts)
+			sort.Strings(sortedMounts)
+
+			for _, mount := range sortedMounts {
+				awfArgs = append(awfArgs, "--mount", mount)
+			}
+			openAIEngineLog.Printf("Added %d custom mounts from agent config", len(sortedMounts))
+		}
+
+		// Add the mounts enforcing the sandbox filesystem policy (sandbox.filesystem)
+		awfArgs = append(awfArgs, getSandboxFilesystemMountArgs(workflowData)...)
+
+		awfArgs = append(awfArgs, "--allow-domains", allowedDomains)
+
+		// Add blocked domains if specified
+		blockedDomains := formatBlockedDomains(workflowData.NetworkPermissions)
+		if blockedDomains != "" {
+			awfArgs = append(awfArgs, "--block-domains", blockedDomains)
+			openAIEngineLog.Printf("Added blocked domains: %s", blockedDomains)
+		}
+
+		awfArgs = append(awfArgs, "--log-level", awfLogLevel)
+		awfArgs = append(awfArgs, "--proxy-logs-dir", "/tmp/gh-aw/sandbox/firewall/logs")
+
+		// Add --enable-host-access when the MCP gateway or an endpoint served on the runner is used
+		if HasMCPServers(workflowData) || stringutil.ExtractDomainFromURL(baseURL) == "host.docker.internal" {
+			awfArgs = append(awfArgs, "--enable-host-access")
+			openAIEngineLog.Print("Added --enable-host-access for host communication")
+		}
+
+		// Pin AWF Docker image version to match the installed binary version
+		awfImageTag := getAWFImageTag(firewallConfig)
+		awfArgs = append(awfArgs, "--image-tag", awfImageTag)
+
+		// Skip pulling images since they are pre-downloaded in the Download container images step
+		awfArgs = append(awfArgs, "--skip-pull")
+
+		// Add SSL Bump support for HTTPS content inspection (v0.9.0+)
+		awfArgs = append(awfArgs, getSSLBumpArgs(firewallConfig)...)
+
+		// Add custom args if specified in firewall config
+		if firewallConfig != nil && len(firewallConfig.Args) > 0 {
+			awfArgs = append(awfArgs, firewallConfig.Args...)
+		}
+
+		// Add custom args from agent config if specified
+		if agentConfig != nil && len(agentConfig.Args) > 0 {
+			awfArgs = append(awfArgs, agentConfig.Args...)
+			openAIEngineLog.Printf("Added %d custom args from agent config", len(agentConfig.Args))
+		}
+
+		// Determine the AWF command to use (custom or standard)
+		awfCommand := "sudo -E awf"
+		if agentConfig != nil && agentConfig.Command != "" {
+			awfCommand = agentConfig.Command
+			openAIEngineLog.Printf("Using custom AWF command: %s", awfCommand)
+		}
+
+		// The model flag uses shell expansion, so the command runs in a bash invocation
+		escapedAgentCommand := strings.ReplaceAll(agentCommand, "'", "'\\''")
+		shellWrappedCommand := fmt.Sprintf("/bin/bash -c '%s'", escapedAgentCommand)
+
+		command = fmt.Sprintf(`set -o pipefail
+%s%s %s \
+  -- %s 2>&1 | tee -a %s`, getSandboxFilesystemMountsLoad(workflowData), awfCommand, shellJoinArgs(awfArgs), shellWrappedCommand, logFile)
+	} else {
+		command = fmt.Sprintf(`set -o pipefail
+%s 2>&1 | tee -a %s`, agentCommand, logFile)
+	}
+
+	env := map[string]string{
+		"GH_AW_PROMPT":     "/tmp/gh-aw/aw-prompts/prompt.txt",
+		"GITHUB_WORKSPACE": "${{ github.workspace }}",
+	}
+	if openAIEndpointRequiresAPIKey(workflowData) {
+		env["OPENAI_COMPATIBLE_API_KEY"] = "${{ secrets.OPENAI_COMPATIBLE_API_KEY }}"
+	}
+
+	// Add GH_AW_MCP_CONFIG for MCP server configuration only if there are MCP servers
+	if HasMCPServers(workflowData) {
+		env["GH_AW_MCP_CONFIG"] = openAIMCPConfigPath
+	}
+
+	// Add GH_AW_SAFE_OUTPUTS if output is needed
+	applySafeOutputEnvToMap(env, workflowData)
+
+	// Add GH_AW_STARTUP_TIMEOUT environment variable (in seconds) if startup-timeout is specified
+	if workflowData.ToolsStartupTimeout > 0 {
+		env["GH_AW_STARTUP_TIMEOUT"] = fmt.Sprintf("%d", workflowData.ToolsStartupTimeout)
+	}
+
+	// Add GH_AW_TOOL_TIMEOUT environment variable (in seconds) if timeout is specified
+	if workflowData.ToolsTimeout > 0 {
+		env["GH_AW_TOOL_TIMEOUT"] = fmt.Sprintf("%d", workflowData.ToolsTimeout)
+	}
+
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.MaxTurns != "" {
+		env["GH_AW_MAX_TURNS"] = workflowData.EngineConfig.MaxTurns
+	}
+
+	// Add model environment variable if model is not explicitly configured
+	if !modelConfigured {
+		if isDetectionJob {
+			env[modelEnvVar] = fmt.Sprintf("${{ vars.%s || '' }}", modelEnvVar)
+		} else {
+			env[modelEnvVar] = agentModelEnvValue(workflowData, modelEnvVar)
+		}
+	}
+
+	// Add custom environment variables from engine config
+	if workflowData.EngineConfig != nil && len(workflowData.EngineConfig.Env) > 0 {
+		for key, value := range workflowData.EngineConfig.Env {
+			env[key] = value
+		}
+	}
+
+	// Add custom environment variables from agent config
+	agentConfig := getAgentConfig(workflowData)
+	if agentConfig != nil && len(agentConfig.Env) > 0 {
+		for key, value := range agentConfig.Env {
+			env[key] = value
+		}
+		openAIEngineLog.Printf("Added %d custom env vars from agent config", len(agentConfig.Env))
+	}
+
+	// Add safe-inputs secrets to env for passthrough to MCP servers
+	if IsSafeInputsEnabled(workflowData.SafeInputs, workflowData) {
+		safeInputsSecrets := collectSafeInputsSecrets(workflowData.SafeInputs)
+		for varName, secretExpr := range safeInputsSecrets {
+			// Only add if not already in env
+			if _, exists := env[varName]; !exists {
+				env[varName] = secretExpr
+			}
+		}
+	}
+
+	stepLines := []string{
+		"      - name: Run agent loop",
+		"        id: agentic_execution",
+	}
+
+	// Add timeout at step level (GitHub Actions standard)
+	if workflowData.TimeoutMinutes != "" {
+		timeoutValue := strings.TrimPrefix(workflowData.TimeoutMinutes, "timeout-minutes: ")
+		stepLines = append(stepLines, fmt.Sprintf("        timeout-minutes: %s", timeoutValue))
+	} else {
+		stepLines = append(stepLines, fmt.Sprintf("        timeout-minutes: %d", int(constants.DefaultAgenticWorkflowTimeout/time.Minute)))
+	}
+
+	// Filter environment variables to only include allowed secrets
+	// This is a security measure to prevent exposing unnecessary secrets to the AWF container
+	allowedSecrets := e.GetRequiredSecretNames(workflowData)
+	filteredEnv := FilterEnvForSecrets(env, allowedSecrets)
+
+	stepLines = FormatStepWithCommandAndEnv(stepLines, command, filteredEnv)
+
+	steps = append(steps, GitHubActionStep(stepLines))
+
+	return steps
+}
+
+// computeOpenAIToolArguments computes the --allow-tool arguments of the agent loop.
+// The values use the same syntax as the Copilot CLI:
+//   - shell, shell(git status), shell(git:*): the shell tool, optionally restricted to commands
+//   - write, write(/tmp/gh-aw/cache-memory): the file writing tools, optionally restricted to a directory
+//   - github, github(issue_read): all tools of an MCP server or a single tool
+func (e *OpenAIEngine) computeOpenAIToolArguments(workflowData *WorkflowData) []string {
+	tools := workflowData.Tools
+	if tools == nil {
+		tools = make(map[string]any)
+	}
+
+	var values []string
+
+	if bashConfig, hasBash := tools["bash"]; hasBash {
+		if bashCommands, ok := bashConfig.([]any); ok {
+			for _, cmd := range bashCommands {
+				if cmdStr, ok := cmd.(string); ok {
+					if cmdStr == ":*" || cmdStr == "*" {
+						values = append(values, "shell")
+					} else {
+						values = append(values, fmt.Sprintf("shell(%s)", cmdStr))
+					}
+				}
+			}
+		} else if bashConfig != false {
+			// Bash with no specific commands or null value - allow all shell
+			values = append(values, "shell")
+		}
+	}
+
+	if _, hasEdit := tools["edit"]; hasEdit {
+		values = append(values, "write")
+	} else if workflowData.CacheMemoryConfig != nil {
+		// Cache memory is a directory the agent reads and writes without the edit tool
+		for _, cache := range workflowData.CacheMemoryConfig.Caches {
+			if cache.ID == "default" {
+				values = append(values, "write(/tmp/gh-aw/cache-memory)")
+			} else {
+				values = append(values, fmt.Sprintf("write(/tmp/gh-aw/cache-memory-%s)", cache.ID))
+			}
+		}
+	}
+
+	for _, toolName := range collectMCPToolNames(workflowData) {
+		switch toolName {
+		case "cache-memory":
+			// Handled above: cache memory provides file system access but no MCP tool
+			continue
+		case "safe-outputs":
+			values = append(values, constants.SafeOutputsMCPServerID)
+			continue
+		case "safe-inputs":
+			values = append(values, constants.SafeInputsMCPServerID)
+			continue
+		}
+
+		allowedList, hasAllowed := openAIAllowedMCPTools(tools[toolName])
+		if !hasAllowed || slices.Contains(allowedList, "*") {
+			values = append(values, toolName)
+			continue
+		}
+		for _, allowedTool := range allowedList {
+			values = append(values, fmt.Sprintf("%s(%s)", toolName, allowedTool))
+		}
+	}
+
+	sort.Strings(values)
+	args := make([]string, 0, len(values)*2)
+	for _, value := range values {
+		args = append(args, "--allow-tool", value)
+	}
+	return args
+}
+
+// openAIAllowedMCPTools returns the allowed tool list of an MCP server configuration
+func openAIAllowedMCPTools(toolConfig any) ([]string, bool) {
+	toolConfigMap, ok := toolConfig.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	allowed, hasAllowed := toolConfigMap["allowed"]
+	if !hasAllowed {
+		return nil, false
+	}
+	var allowedList []string
+	switch list := allowed.(type) {
+	case []any:
+		for _, item := range list {
+			if itemStr, ok := item.(string); ok {
+				allowedList = append(allowedList, itemStr)
+			}
+		}
+	case []string:
+		allowedList = list
+	}
+	return allowedList, true
+}
+
+// RenderMCPConfig renders the MCP configuration in the JSON format of the Claude engine,
+// which the MCP gateway converter also produces for the agent loop
+func (e *OpenAIEngine) RenderMCPConfig(yaml *strings.Builder, tools map[string]any, mcpTools []string, workflowData *WorkflowData) {
+	NewClaudeEngine().RenderMCPConfig(yaml, tools, mcpTools, workflowData)
+}
+
+// ParseLogMetrics parses the stream-json log of the agent loop, which uses the Claude format
+func (e *OpenAIEngine) ParseLogMetrics(logContent string, verbose bool) LogMetrics {
+	openAIEngineLog.Printf("Parsing openai engine log metrics: %d bytes", len(logContent))
+	return NewClaudeEngine().ParseLogMetrics(logContent, verbose)
+}
+
+// ParseTranscript implements TranscriptParser for the stream-json log of the agent loop
+func (e *OpenAIEngine) ParseTranscript(logContent string, verbose bool) *Transcript {
+	builder := newTranscriptBuilder(e.GetID())
+	parseStreamJSONTranscript(builder, NewClaudeEngine().parseClaudeLogEntries(logContent, verbose))
+	return builder.build()
+}
+
+// GetLogParserScriptId returns the JavaScript script name for parsing the agent loop logs
+func (e *OpenAIEngine) GetLogParserScriptId() string {
+	return "parse_claude_log"
+}
+
+// GetFallbackErrorPatterns returns the chat completions API errors after which a fallback engine runs
+func (e *OpenAIEngine) GetFallbackErrorPatterns() []string {
+	return append(e.BaseEngine.GetFallbackErrorPatterns(),
+		"chat completions request failed: (401|403)",
+		"chat completions request failed: .*connection refused",
+	)
+}
+
+// GetSquidLogsSteps returns the steps for uploading and parsing Squid logs (after secret redaction)
+func (e *OpenAIEngine) GetSquidLogsSteps(workflowData *WorkflowData) []GitHubActionStep {
+	var steps []GitHubActionStep
+
+	// Only add upload and parsing steps if firewall is enabled
+	if isFirewallEnabled(workflowData) {
+		openAIEngineLog.Printf("Adding Squid logs upload and parsing steps for workflow: %s", workflowData.Name)
+		steps = append(steps, generateSquidLogsUploadStep(workflowData.Name))
+		steps = append(steps, generateFirewallLogParsingStep(workflowData.Name))
+	}
+
+	return steps
+}
@@ -0,0 +1,211 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIEngineCapabilities(t *testing.T) {
+	engine := NewOpenAIEngine()
+
+	assert.Equal(t, "openai", engine.GetID())
+	assert.True(t, engine.IsExperimental())
+	assert.True(t, engine.SupportsToolsAllowlist())
+	assert.True(t, engine.SupportsMaxTurns())
+	assert.True(t, engine.SupportsFirewall())
+	assert.False(t, engine.SupportsWebSearch())
+	assert.Equal(t, "parse_claude_log", engine.GetLogParserScriptId())
+}
+
+func TestOpenAIEngineToolArguments(t *testing.T) {
+	engine := NewOpenAIEngine()
+	workflowData := &WorkflowData{
+		Tools: map[string]any{
+			"bash":   []any{"git status", "ls"},
+			"github": map[string]any{"allowed": []any{"issue_read", "list_issues"}},
+			"notes": map[string]any{
+				"type":    "http",
+				"url":     "https://notes.example.com/mcp",
+				"allowed": []any{"*"},
+			},
+		},
+		SafeOutputs: &SafeOutputsConfig{CreateIssues: &CreateIssuesConfig{}},
+	}
+
+	args := engine.computeOpenAIToolArguments(workflowData)
+	assert.Equal(t, []string{
+		"--allow-tool", "github(issue_read)",
+		"--allow-tool", "github(list_issues)",
+		"--allow-tool", "notes",
+		"--allow-tool", "safeoutputs",
+		"--allow-tool", "shell(git status)",
+		"--allow-tool", "shell(ls)",
+	}, args)
+
+	workflowData = &WorkflowData{
+		Tools:             map[string]any{"bash": nil, "cache-memory": true},
+		CacheMemoryConfig: &CacheMemoryConfig{Caches: []CacheMemoryEntry{{ID: "default"}, {ID: "notes"}}},
+	}
+	args = engine.computeOpenAIToolArguments(workflowData)
+	assert.Equal(t, []string{
+		"--allow-tool", "shell",
+		"--allow-tool", "write(/tmp/gh-aw/cache-memory)",
+		"--allow-tool", "write(/tmp/gh-aw/cache-memory-notes)",
+	}, args)
+}
+
+func TestOpenAIEngineParseLogMetrics(t *testing.T) {
+	logContent := `{"type":"system","subtype":"init","session_id":"s","model":"qwen","tools":["Bash","Read"]}
+{"type":"assistant","message":{"id":"msg_1","role":"assistant","content":[{"type":"tool_use","id":"call_1","name":"Bash","input":{"command":"ls"}}],"usage":{"input_tokens":100,"output_tokens":20}}}
+{"type":"user","message":{"role":"user","content":[{"type":"tool_result","tool_use_id":"call_1","content":"README.md","is_error":false}]}}
+{"type":"assistant","message":{"id":"msg_2","role":"assistant","content":[{"type":"text","text":"Done"}],"usage":{"input_tokens":150,"output_tokens":5}}}
+{"type":"result","subtype":"success","is_error":false,"num_turns":2,"duration_ms":1200,"result":"Done","usage":{"input_tokens":250,"output_tokens":25}}
+`
+
+	engine := NewOpenAIEngine()
+	metrics := engine.ParseLogMetrics(logContent, false)
+	assert.Equal(t, 275, metrics.TokenUsage)
+	assert.Equal(t, 2, metrics.Turns)
+	require.Len(t, metrics.ToolCalls, 1)
+	assert.Equal(t, "bash_ls", metrics.ToolCalls[0].Name)
+
+	transcript := engine.ParseTranscript(logContent, false)
+	require.NotNil(t, transcript)
+	assert.Equal(t, "openai", transcript.Engine)
+	assert.Len(t, transcript.Turns, 2)
+}
+
+func TestValidateOpenAIEngineConfig(t *testing.T) {
+	assert.NoError(t, validateOpenAIEngineConfig(nil))
+	assert.NoError(t, validateOpenAIEngineConfig(&EngineConfig{ID: "claude"}))
+	assert.NoError(t, validateOpenAIEngineConfig(&EngineConfig{ID: "openai", BaseURL: "http://vllm.internal:8000/v1"}))
+
+	err := validateOpenAIEngineConfig(&EngineConfig{ID: "openai"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires 'base-url'")
+
+	err = validateOpenAIEngineConfig(&EngineConfig{ID: "openai", BaseURL: "ftp://models.internal"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid openai engine base-url")
+}
+
+func TestOpenAIEngineRequiredSecrets(t *testing.T) {
+	tests := []struct {
+		baseURL string
+		apiKey  bool
+	}{
+		{baseURL: "http://vllm.internal:8000/v1", apiKey: true},
+		{baseURL: "https://models.example.com/v1", apiKey: true},
+		{baseURL: "http://host.docker.internal:11434/v1", apiKey: false},
+		{baseURL: "http://localhost:8000/v1", apiKey: false},
+		{baseURL: "http://127.0.0.1:8000/v1", apiKey: false},
+	}
+
+	engine := NewOpenAIEngine()
+	for _, tt := range tests {
+		t.Run(tt.baseURL, func(t *testing.T) {
+			workflowData := &WorkflowData{Name: "test", EngineConfig: &EngineConfig{ID: "openai", BaseURL: tt.baseURL}}
+
+			assert.Equal(t, tt.apiKey, slices.Contains(engine.GetRequiredSecretNames(workflowData), "OPENAI_COMPATIBLE_API_KEY"))
+			steps := engine.GetExecutionSteps(workflowData, "/tmp/gh-aw/agent-stdio.log")
+			require.NotEmpty(t, steps)
+			assert.Equal(t, tt.apiKey, strings.Contains(strings.Join(steps[len(steps)-1], "\n"), "OPENAI_COMPATIBLE_API_KEY: ${{ secrets.OPENAI_COMPATIBLE_API_KEY }}"))
+		})
+	}
+}
+
+func TestGenerateOpenAIAgentInstallStep(t *testing.T) {
+	originalVersion := GetVersion()
+	originalIsRelease := IsRelease()
+	defer func() {
+		SetVersion(originalVersion)
+		SetIsRelease(originalIsRelease)
+	}()
+
+	SetVersion("v1.4.2")
+	SetIsRelease(true)
+	step := strings.Join(generateOpenAIAgentInstallStep("${{ secrets.GITHUB_TOKEN }}"), "\n")
+	assert.Contains(t, step, "gh extension install github/gh-aw --pin v1.4.2", "release builds should pin the agent loop to the compiler version")
+	assert.Contains(t, step, "cp \"$GH_AW_BIN\" /opt/gh-aw/gh-aw")
+
+	SetIsRelease(false)
+	step = strings.Join(generateOpenAIAgentInstallStep("${{ secrets.GITHUB_TOKEN }}"), "\n")
+	assert.Contains(t, step, "gh extension install github/gh-aw\n")
+	assert.NotContains(t, step, "--pin")
+}
+
+func TestCompileWorkflowWithOpenAIEngine(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "openai-engine")
+	workflowPath := filepath.Join(tmpDir, "self-hosted.md")
+	content := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine:
+  id: openai
+  base-url: http://vllm.internal:8000/v1
+  model: qwen2.5-coder-32b
+  max-turns: 12
+network:
+  allowed: [defaults]
+tools:
+  bash: ["git status"]
+safe-outputs:
+  create-issue:
+---
+
+# Self-hosted
+
+Summarize the repository.
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "self-hosted.lock.yml"))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "- name: Install gh-aw extension")
+	assert.Contains(t, lock, "- name: Run agent loop")
+	assert.Contains(t, lock, "/opt/gh-aw/gh-aw agent --base-url http://vllm.internal:8000/v1 --model qwen2.5-coder-32b --max-turns 12 --mcp-config /tmp/gh-aw/mcp-config/mcp-servers.json")
+	assert.Contains(t, lock, "--allow-tool safeoutputs")
+	assert.Contains(t, lock, "--allow-tool 'shell(git status)'")
+	assert.Contains(t, lock, "OPENAI_COMPATIBLE_API_KEY: ${{ secrets.OPENAI_COMPATIBLE_API_KEY }}")
+	assert.Contains(t, lock, "GH_AW_MAX_TURNS: 12")
+	assert.Contains(t, lock, `export GH_AW_ENGINE="openai"`)
+	assert.Contains(t, lock, "vllm.internal", "the endpoint host should be allowed by the firewall")
+	assert.NotContains(t, lock, "api.openai.com", "the engine should not allow public model APIs")
+	assert.Equal(t, 2, strings.Count(lock, "- name: Install gh-aw extension"), "the extension should be installed once in the agent and detection jobs")
+}
+
+func TestCompileWorkflowWithOpenAIEngineRequiresBaseURL(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "openai-engine-base-url")
+	workflowPath := filepath.Join(tmpDir, "self-hosted.md")
+	content := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+engine: openai
+---
+
+# Self-hosted
+
+Summarize the repository.
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	err := NewCompiler().CompileWorkflow(workflowPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "base-url")
+}
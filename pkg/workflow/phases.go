@@ -0,0 +1,381 @@
+package workflow
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var phasesLog = logger.New("workflow:phases")
+
+// phasesDir is the directory holding the prompt, log, output and veto files of each phase
+const phasesDir = "/tmp/gh-aw/phases"
+
+// phaseIDPattern matches valid phase IDs, which are used in step IDs and file paths
+var phaseIDPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// PhaseConfig is one phase of a multi-phase agent pipeline. The phases run one after
+// the other in the agent job, each with its own section of the prompt, engine and tools,
+// and each phase receives the outputs of the phases before it.
+type PhaseConfig struct {
+	ID           string
+	Name         string
+	Section      string        // heading of the prompt section with the instructions of the phase
+	EngineConfig *EngineConfig // engine of the phase (nil uses the workflow engine)
+	Tools        []string      // workflow tools available to the phase (nil makes all tools available)
+	SafeOutputs  bool          // whether the safe outputs emitted by the phase are kept
+	Review       bool          // whether the phase reviews the safe outputs and may veto them
+}
+
+// DisplayName returns the name of the phase, or its ID if it has no name
+func (p *PhaseConfig) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.ID
+}
+
+// SectionHeading returns the heading of the prompt section of the phase
+func (p *PhaseConfig) SectionHeading() string {
+	if p.Section != "" {
+		return p.Section
+	}
+	return p.DisplayName()
+}
+
+// CanEdit reports whether the phase may change the workspace. A phase restricting its tools
+// to ones without edit may not, and does not get the edit tool.
+func (p *PhaseConfig) CanEdit() bool {
+	return p.Tools == nil || slices.Contains(p.Tools, "edit")
+}
+
+// extractPhasesConfig extracts and validates the phases of the workflow from the frontmatter.
+// It must run after the default tools are applied so the tools of each phase can be validated.
+func (c *Compiler) extractPhasesConfig(frontmatter map[string]any, data *WorkflowData) error {
+	value, exists := frontmatter["phases"]
+	if !exists || value == nil {
+		return nil
+	}
+
+	items, ok := value.([]any)
+	if !ok || len(items) == 0 {
+		return fmt.Errorf("'phases' must be a non-empty list of phases.\n\nExample:\nphases:\n  - id: plan\n    safe-outputs: false\n  - id: execute\n\nSee: %s", constants.DocsPhasesURL)
+	}
+
+	phases := make([]*PhaseConfig, 0, len(items))
+	for i, item := range items {
+		phase, err := c.parsePhaseConfig(item)
+		if err != nil {
+			return fmt.Errorf("invalid phase %d: %w", i+1, err)
+		}
+		phases = append(phases, phase)
+	}
+
+	if err := c.validatePhases(phases, data); err != nil {
+		return err
+	}
+
+	phasesLog.Printf("Extracted %d phases", len(phases))
+	data.Phases = phases
+	return nil
+}
+
+// parsePhaseConfig parses one entry of the phases list
+func (c *Compiler) parsePhaseConfig(item any) (*PhaseConfig, error) {
+	phaseMap, ok := item.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("each phase must be an object with an 'id' field. See: %s", constants.DocsPhasesURL)
+	}
+
+	phase := &PhaseConfig{SafeOutputs: true}
+	phase.ID, _ = phaseMap["id"].(string)
+
+	// The phases are steps of the agent job, which has a single set of permissions and token
+	if _, ok := phaseMap["permissions"]; ok {
+		return nil, fmt.Errorf("phase '%s' cannot set 'permissions': all phases run in the agent job and share its permissions and GITHUB_TOKEN. Limit the phase with 'tools' and 'safe-outputs', or run the work that needs different permissions in a separate workflow through dispatch-workflow. See: %s", phase.ID, constants.DocsPhasesURL)
+	}
+	phase.Name, _ = phaseMap["name"].(string)
+	phase.Section, _ = phaseMap["section"].(string)
+
+	if review, ok := phaseMap["review"].(bool); ok {
+		phase.Review = review
+		// A reviewer judges the safe outputs of the phases before it instead of producing its own
+		phase.SafeOutputs = !review
+	}
+	if safeOutputs, ok := phaseMap["safe-outputs"].(bool); ok {
+		phase.SafeOutputs = safeOutputs
+	}
+
+	if engine, ok := phaseMap["engine"]; ok {
+		_, engineConfig := c.ExtractEngineConfig(map[string]any{"engine": engine})
+		if engineConfig == nil {
+			return nil, fmt.Errorf("phase '%s' has an invalid engine configuration. See: %s", phase.ID, constants.DocsPhasesURL)
+		}
+		phase.EngineConfig = engineConfig
+	}
+
+	if tools, ok := phaseMap["tools"]; ok {
+		toolList, ok := tools.([]any)
+		if !ok {
+			return nil, fmt.Errorf("'tools' of phase '%s' must be a list of tool names. See: %s", phase.ID, constants.DocsPhasesURL)
+		}
+		phase.Tools = []string{}
+		for _, tool := range toolList {
+			name, ok := tool.(string)
+			if !ok {
+				return nil, fmt.Errorf("'tools' of phase '%s' must be a list of tool names. See: %s", phase.ID, constants.DocsPhasesURL)
+			}
+			phase.Tools = append(phase.Tools, name)
+		}
+	}
+
+	return phase, nil
+}
+
+// validatePhases validates the IDs, engines and tools of the phases
+func (c *Compiler) validatePhases(phases []*PhaseConfig, data *WorkflowData) error {
+	if hasEngineFallbacks(data) {
+		return fmt.Errorf("'phases' cannot be combined with an engine fallback chain. Configure the engine of each phase instead. See: %s", constants.DocsPhasesURL)
+	}
+	if data.AI == "custom" {
+		return fmt.Errorf("'phases' are not supported for the custom engine. Use an agentic engine such as claude, codex or copilot. See: %s", constants.DocsPhasesURL)
+	}
+
+	seen := make(map[string]bool)
+	for _, phase := range phases {
+		if !phaseIDPattern.MatchString(phase.ID) {
+			return fmt.Errorf("invalid phase id '%s': must start with a lowercase letter and contain only lowercase letters, digits, hyphens and underscores. See: %s", phase.ID, constants.DocsPhasesURL)
+		}
+		if seen[phase.ID] {
+			return fmt.Errorf("duplicate phase id '%s'. See: %s", phase.ID, constants.DocsPhasesURL)
+		}
+		seen[phase.ID] = true
+
+		if phase.EngineConfig != nil {
+			if err := c.validateEngine(phase.EngineConfig.ID); err != nil {
+				return fmt.Errorf("invalid engine for phase '%s': %w", phase.ID, err)
+			}
+			if phase.EngineConfig.ID == "custom" {
+				return fmt.Errorf("phase '%s' cannot use the custom engine. Use an agentic engine such as claude, codex or copilot. See: %s", phase.ID, constants.DocsPhasesURL)
+			}
+			if err := validateOpenAIEngineConfig(phase.EngineConfig); err != nil {
+				return fmt.Errorf("invalid engine for phase '%s': %w", phase.ID, err)
+			}
+			if len(phase.EngineConfig.Fallback) > 0 {
+				return fmt.Errorf("phase '%s' cannot configure an engine fallback chain. See: %s", phase.ID, constants.DocsPhasesURL)
+			}
+		}
+
+		for _, tool := range phase.Tools {
+			if _, ok := data.Tools[tool]; !ok {
+				available := slices.Sorted(maps.Keys(data.Tools))
+				return fmt.Errorf("phase '%s' uses tool '%s', which is not configured in the workflow 'tools' section. Available tools: %s. See: %s", phase.ID, tool, strings.Join(available, ", "), constants.DocsPhasesURL)
+			}
+		}
+	}
+
+	return nil
+}
+
+// phaseEngineIDs returns the IDs of the engines used by the phases that differ from the workflow engine
+func phaseEngineIDs(data *WorkflowData) []string {
+	var ids []string
+	for _, phase := range data.Phases {
+		if phase.EngineConfig != nil && phase.EngineConfig.ID != data.AI && !slices.Contains(ids, phase.EngineConfig.ID) {
+			ids = append(ids, phase.EngineConfig.ID)
+		}
+	}
+	return ids
+}
+
+// additionalEngineIDs returns the IDs of the engines other than the workflow engine that run in the
+// agent job, i.e. the fallback engines and the engines of the phases
+func additionalEngineIDs(data *WorkflowData) []string {
+	return append(engineFallbackIDs(data), phaseEngineIDs(data)...)
+}
+
+// phaseRun is one phase together with its engine and the workflow data its steps are generated from
+type phaseRun struct {
+	phase  *PhaseConfig
+	engine CodingAgentEngine
+	data   *WorkflowData
+	number int
+}
+
+// stepSuffix returns the suffix of the IDs of the steps of the phase
+func (r phaseRun) stepSuffix() string {
+	return "_" + r.phase.ID
+}
+
+// logFile returns the agent log of the phase
+func (r phaseRun) logFile() string {
+	return fmt.Sprintf("%s/%s/agent-stdio.log", phasesDir, r.phase.ID)
+}
+
+// buildPhaseRuns returns the phases of the workflow with their engines. Each phase gets a shallow
+// copy of the workflow data using its own engine configuration, tools and safe outputs.
+func (c *Compiler) buildPhaseRuns(data *WorkflowData) ([]phaseRun, error) {
+	runs := make([]phaseRun, 0, len(data.Phases))
+	for _, phase := range data.Phases {
+		phaseData := *data
+		if phase.EngineConfig != nil {
+			phaseData.AI = phase.EngineConfig.ID
+			phaseData.EngineConfig = phase.EngineConfig
+		}
+
+		engine, err := c.getAgenticEngine(phaseData.AI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get engine %s of phase %s: %w", phaseData.AI, phase.ID, err)
+		}
+
+		// The phase reports its output in its final reply, so it only gets the tools it may use
+		if phase.Tools != nil {
+			tools := make(map[string]any, len(phase.Tools))
+			for name, tool := range data.Tools {
+				if slices.Contains(phase.Tools, name) {
+					tools[name] = tool
+				}
+			}
+			phaseData.Tools = tools
+			phaseData.ParsedTools = NewTools(tools)
+		}
+
+		// Phases that cannot emit safe outputs are not given the safe output tools
+		if !phase.SafeOutputs {
+			phaseData.SafeOutputs = nil
+		}
+
+		runs = append(runs, phaseRun{phase: phase, engine: engine, data: &phaseData, number: len(runs) + 1})
+	}
+
+	phasesLog.Printf("Built %d phase runs", len(runs))
+	return runs, nil
+}
+
+// generatePhaseExecutionSteps generates, for each phase, a step preparing the prompt of the phase,
+// the installation steps of its engine if not installed yet, its execution steps and a step
+// enforcing its safe outputs and recording its output.
+func (c *Compiler) generatePhaseExecutionSteps(yaml *strings.Builder, data *WorkflowData, runs []phaseRun, logFile string) {
+	// Steps installed for the workflow engine (e.g. Node.js, AWF) are not installed again
+	installed := make(map[string]bool)
+	if engine, err := c.getAgenticEngine(data.AI); err == nil {
+		for _, step := range engine.GetInstallationSteps(data) {
+			installed[stepName(step)] = true
+		}
+	}
+
+	sections := make([]string, 0, len(runs))
+	for _, run := range runs {
+		sections = append(sections, run.phase.SectionHeading())
+	}
+
+	for i, run := range runs {
+		phasesLog.Printf("Generating steps for phase %s with engine %s", run.phase.ID, run.engine.GetID())
+
+		var previous []string
+		for _, earlier := range runs[:i] {
+			previous = append(previous, earlier.phase.ID)
+		}
+		c.generatePhasePrepareStep(yaml, run, len(runs), sections, previous)
+
+		var steps []GitHubActionStep
+		for _, step := range run.engine.GetInstallationSteps(run.data) {
+			name := stepName(step)
+			if installed[name] {
+				continue
+			}
+			installed[name] = true
+			steps = append(steps, suffixStepIDs(step, run.stepSuffix()))
+		}
+
+		execution := run.engine.GetExecutionSteps(run.data, run.logFile())
+		executionIndex := findExecutionStep(execution)
+		for j, step := range execution {
+			step = suffixStepIDs(step, run.stepSuffix())
+			if j == executionIndex {
+				step = setStepID(step, "agentic_execution"+run.stepSuffix())
+			}
+			steps = append(steps, step)
+		}
+
+		for _, step := range steps {
+			for _, line := range step {
+				yaml.WriteString(line + "\n")
+			}
+		}
+
+		c.generatePhaseFinishStep(yaml, run, data.AI, logFile)
+	}
+}
+
+// generatePhasePrepareStep generates the step writing the prompt of a phase
+func (c *Compiler) generatePhasePrepareStep(yaml *strings.Builder, run phaseRun, count int, sections []string, previous []string) {
+	fmt.Fprintf(yaml, "      - name: Prepare %s phase\n", run.phase.DisplayName())
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_ID: %s\n", run.phase.ID)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_NAME: %q\n", run.phase.DisplayName())
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_NUMBER: \"%d\"\n", run.number)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_COUNT: \"%d\"\n", count)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_SECTION: %q\n", run.phase.SectionHeading())
+	yaml.WriteString("          GH_AW_PHASE_SECTIONS: |\n")
+	for _, section := range sections {
+		yaml.WriteString("            " + section + "\n")
+	}
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_PREVIOUS: %q\n", strings.Join(previous, " "))
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_SAFE_OUTPUTS: \"%t\"\n", run.phase.SafeOutputs)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_REVIEW: \"%t\"\n", run.phase.Review)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_EDIT: \"%t\"\n", run.phase.CanEdit())
+	yaml.WriteString("        run: bash /opt/gh-aw/actions/prepare_phase_prompt.sh\n")
+}
+
+// generatePhaseFinishStep generates the step discarding the safe outputs a phase may not emit,
+// failing when a phase that may not edit changed the workspace, applying the veto of a reviewer
+// and adding the log of the phase to the agent log
+func (c *Compiler) generatePhaseFinishStep(yaml *strings.Builder, run phaseRun, workflowEngineID string, logFile string) {
+	fmt.Fprintf(yaml, "      - name: Finish %s phase\n", run.phase.DisplayName())
+	fmt.Fprintf(yaml, "        id: phase%s\n", run.stepSuffix())
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_ID: %s\n", run.phase.ID)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_NAME: %q\n", run.phase.DisplayName())
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_SAFE_OUTPUTS: \"%t\"\n", run.phase.SafeOutputs)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_REVIEW: \"%t\"\n", run.phase.Review)
+	fmt.Fprintf(yaml, "          GH_AW_PHASE_EDIT: \"%t\"\n", run.phase.CanEdit())
+	// The agent log is parsed with the workflow engine's parser, so only its phases are added to it
+	if run.engine.GetID() == workflowEngineID {
+		fmt.Fprintf(yaml, "          GH_AW_AGENT_LOG: %s\n", logFile)
+	}
+	yaml.WriteString("        run: bash /opt/gh-aw/actions/finish_phase.sh\n")
+}
+
+// generatePhaseLogParsing generates a log parsing step for the log of each phase
+func (c *Compiler) generatePhaseLogParsing(yaml *strings.Builder, runs []phaseRun, logFile string) {
+	parsed := make(map[string]bool)
+	for _, run := range runs {
+		// Engines writing their own log files are parsed once, as all their phases write there
+		logForParsing := run.engine.GetLogFileForParsing()
+		if logForParsing == logFile {
+			logForParsing = run.logFile()
+		} else if parsed[logForParsing] {
+			continue
+		}
+		parsed[logForParsing] = true
+		c.generateLogParsingStep(yaml, run.engine, "always()", logForParsing)
+	}
+}
+
+// suffixStepIDs returns a copy of the step with the suffix appended to its ID
+func suffixStepIDs(step GitHubActionStep, suffix string) GitHubActionStep {
+	updated := make(GitHubActionStep, 0, len(step))
+	for _, line := range step {
+		if id, ok := strings.CutPrefix(line, "        id: "); ok {
+			line = "        id: " + id + suffix
+		}
+		updated = append(updated, line)
+	}
+	return updated
+}
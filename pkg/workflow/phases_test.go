@@ -0,0 +1,240 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractPhasesConfig(t *testing.T) {
+	compiler := NewCompiler()
+	data := &WorkflowData{AI: "copilot", Tools: map[string]any{"github": nil, "edit": nil, "bash": []any{"ls"}}}
+
+	frontmatter := map[string]any{
+		"phases": []any{
+			map[string]any{"id": "plan", "name": "Planning", "tools": []any{"github"}, "safe-outputs": false},
+			map[string]any{"id": "execute", "engine": map[string]any{"id": "claude", "model": "claude-sonnet-4"}},
+			map[string]any{"id": "review", "section": "Code review", "review": true},
+		},
+	}
+	require.NoError(t, compiler.extractPhasesConfig(frontmatter, data))
+	require.Len(t, data.Phases, 3)
+
+	plan := data.Phases[0]
+	assert.Equal(t, "Planning", plan.SectionHeading())
+	assert.Equal(t, []string{"github"}, plan.Tools)
+	assert.False(t, plan.SafeOutputs)
+	assert.Nil(t, plan.EngineConfig)
+
+	execute := data.Phases[1]
+	assert.Equal(t, "execute", execute.SectionHeading())
+	assert.True(t, execute.SafeOutputs)
+	require.NotNil(t, execute.EngineConfig)
+	assert.Equal(t, "claude", execute.EngineConfig.ID)
+	assert.Equal(t, "claude-sonnet-4", execute.EngineConfig.Model)
+	assert.Nil(t, execute.Tools, "a phase without tools should use all workflow tools")
+
+	review := data.Phases[2]
+	assert.Equal(t, "Code review", review.SectionHeading())
+	assert.True(t, review.Review)
+	assert.False(t, review.SafeOutputs, "review phases should not emit safe outputs by default")
+
+	assert.Equal(t, []string{"claude"}, additionalEngineIDs(data))
+}
+
+func TestExtractPhasesConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		phases  any
+		data    *WorkflowData
+		wantErr string
+	}{
+		{
+			name:    "not a list",
+			phases:  map[string]any{"id": "plan"},
+			wantErr: "must be a non-empty list",
+		},
+		{
+			name:    "invalid id",
+			phases:  []any{map[string]any{"id": "Plan Phase"}},
+			wantErr: "invalid phase id 'Plan Phase'",
+		},
+		{
+			name:    "duplicate id",
+			phases:  []any{map[string]any{"id": "plan"}, map[string]any{"id": "plan"}},
+			wantErr: "duplicate phase id 'plan'",
+		},
+		{
+			name:    "unknown tool",
+			phases:  []any{map[string]any{"id": "plan", "tools": []any{"playwright"}}},
+			wantErr: "uses tool 'playwright'",
+		},
+		{
+			name:    "invalid engine",
+			phases:  []any{map[string]any{"id": "plan", "engine": "gemini"}},
+			wantErr: "invalid engine for phase 'plan'",
+		},
+		{
+			name:    "custom engine",
+			phases:  []any{map[string]any{"id": "plan", "engine": "custom"}},
+			wantErr: "cannot use the custom engine",
+		},
+		{
+			name:    "permissions",
+			phases:  []any{map[string]any{"id": "plan", "permissions": map[string]any{"contents": "read"}}},
+			wantErr: "phase 'plan' cannot set 'permissions'",
+		},
+		{
+			name:    "engine fallback",
+			phases:  []any{map[string]any{"id": "plan"}},
+			data:    &WorkflowData{AI: "claude", EngineConfig: &EngineConfig{ID: "claude", Fallback: []*EngineConfig{{ID: "copilot"}}}},
+			wantErr: "cannot be combined with an engine fallback chain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			if data == nil {
+				data = &WorkflowData{AI: "copilot", Tools: map[string]any{"github": nil, "edit": nil}}
+			}
+			err := NewCompiler().extractPhasesConfig(map[string]any{"phases": tt.phases}, data)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBuildPhaseRuns(t *testing.T) {
+	compiler := NewCompiler()
+	data := &WorkflowData{
+		AI:          "copilot",
+		Tools:       map[string]any{"github": nil, "bash": []any{"ls"}},
+		SafeOutputs: &SafeOutputsConfig{},
+		Phases: []*PhaseConfig{
+			{ID: "plan", Tools: []string{"github"}},
+			{ID: "execute", EngineConfig: &EngineConfig{ID: "claude"}, SafeOutputs: true},
+		},
+	}
+
+	runs, err := compiler.buildPhaseRuns(data)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+
+	assert.Equal(t, "copilot", runs[0].engine.GetID())
+	assert.ElementsMatch(t, []string{"github"}, slices.Collect(maps.Keys(runs[0].data.Tools)), "phases should only get their own tools")
+	assert.Nil(t, runs[0].data.SafeOutputs, "phases without safe outputs should not get the safe output tools")
+	assert.Equal(t, "/tmp/gh-aw/phases/plan/agent-stdio.log", runs[0].logFile())
+
+	assert.Equal(t, "claude", runs[1].engine.GetID())
+	assert.Equal(t, "claude", runs[1].data.AI)
+	assert.ElementsMatch(t, []string{"github", "bash"}, slices.Collect(maps.Keys(runs[1].data.Tools)), "phases should not get the edit tool the workflow does not have")
+	assert.NotNil(t, runs[1].data.SafeOutputs)
+
+	assert.NotContains(t, data.Tools, "edit", "the workflow tools should not be modified")
+}
+
+func TestCompileWorkflowWithPhases(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "phases")
+	workflowPath := filepath.Join(tmpDir, "phases.md")
+	content := `---
+on: workflow_dispatch
+permissions:
+  contents: read
+  issues: read
+  pull-requests: read
+engine: copilot
+tools:
+  github:
+  edit:
+phases:
+  - id: plan
+    tools: [github]
+    safe-outputs: false
+  - id: execute
+    engine:
+      id: claude
+      model: claude-sonnet-4
+  - id: review
+    review: true
+safe-outputs:
+  create-issue:
+---
+
+# Phases
+
+## Plan
+
+Plan the change.
+
+## Execute
+
+Apply the plan.
+
+## Review
+
+Review the issue.
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "phases.lock.yml"))
+	require.NoError(t, err)
+	lock := string(lockContent)
+
+	assert.Contains(t, lock, "      - name: Prepare plan phase\n")
+	assert.Contains(t, lock, "          GH_AW_PHASE_PREVIOUS: \"plan execute\"\n")
+	assert.Contains(t, lock, "run: bash /opt/gh-aw/actions/prepare_phase_prompt.sh")
+	assert.Contains(t, lock, "run: bash /opt/gh-aw/actions/finish_phase.sh")
+	assert.Contains(t, lock, "      - name: Execute GitHub Copilot CLI\n        id: agentic_execution_plan\n")
+	assert.Contains(t, lock, "id: agentic_execution_execute")
+	assert.Contains(t, lock, "id: agentic_execution_review")
+	assert.Contains(t, lock, "id: validate-secret_execute")
+	assert.Contains(t, lock, "- name: Install Claude Code CLI")
+	assert.Contains(t, lock, "tee /tmp/gh-aw/phases/plan/agent-stdio.log")
+	assert.Contains(t, lock, "--model claude-sonnet-4")
+	assert.Contains(t, lock, `export GH_AW_ADDITIONAL_ENGINES="claude"`)
+	assert.Contains(t, lock, "GH_AW_AGENT_OUTPUT: /tmp/gh-aw/phases/execute/agent-stdio.log")
+	assert.Contains(t, lock, "            /tmp/gh-aw/phases/\n")
+	assert.Equal(t, 1, strings.Count(lock, "- name: Install awf binary"), "shared installation steps should not be repeated")
+
+	// Only the phases run with the workflow engine are added to the agent log
+	_, executeFinish, found := strings.Cut(lock, "- name: Finish execute phase\n")
+	require.True(t, found)
+	executeFinish, _, _ = strings.Cut(executeFinish, "run: ")
+	assert.NotContains(t, executeFinish, "GH_AW_AGENT_LOG")
+	assert.Contains(t, lock, "          GH_AW_AGENT_LOG: /tmp/gh-aw/agent-stdio.log\n")
+
+	// A phase restricted to tools without edit must leave the workspace unchanged
+	_, planFinish, found := strings.Cut(lock, "- name: Finish plan phase\n")
+	require.True(t, found)
+	planFinish, _, _ = strings.Cut(planFinish, "run: ")
+	assert.Contains(t, planFinish, "GH_AW_PHASE_EDIT: \"false\"")
+	_, planExecution, found := strings.Cut(lock, "id: agentic_execution_plan\n")
+	require.True(t, found)
+	planExecution, _, _ = strings.Cut(planExecution, "- name: Finish plan phase")
+	assert.NotContains(t, planExecution, "--allow-tool write", "a phase that may not edit should not get the edit tool")
+	executeFinish, _, _ = strings.Cut(lock[strings.Index(lock, "- name: Finish execute phase\n"):], "run: ")
+	assert.Contains(t, executeFinish, "GH_AW_PHASE_EDIT: \"true\"")
+
+	// Phases without safe outputs still read the agent model, not the detection model
+	assert.Contains(t, planExecution, "GH_AW_MODEL_AGENT_COPILOT")
+	assert.NotContains(t, planExecution, "GH_AW_MODEL_DETECTION_COPILOT")
+}
+
+func TestSuffixStepIDs(t *testing.T) {
+	step := GitHubActionStep{"      - name: Setup", "        id: setup", "        run: echo setup"}
+	assert.Equal(t, GitHubActionStep{"      - name: Setup", "        id: setup_plan", "        run: echo setup"}, suffixStepIDs(step, "_plan"))
+	assert.Equal(t, "        id: setup", step[1], "the original step should not be modified")
+}
@@ -0,0 +1,323 @@
+// This file provides the organization policy model for agentic workflows.
+//
+// # Policy as Code
+//
+// A policy file lets a platform team express organization-wide rules that every
+// workflow must satisfy, in addition to the fixed rules enforced by strict mode
+// and the dangerous-permissions check. Policies are evaluated on the parsed
+// WorkflowData (not on the generated YAML), so rules see the same normalized
+// view of the workflow that the compiler uses.
+//
+// Example policy file (.github/aw/policy.yml):
+//
+//	version: 1
+//	rules:
+//	  - id: no-contents-write
+//	    description: Only release workflows may write repository contents
+//	    except: [release-*]
+//	    permissions:
+//	      deny-write: [contents]
+//	  - id: approved-engines
+//	    engines:
+//	      allowed: [claude, copilot]
+//	  - id: no-network-wildcard
+//	    network:
+//	      denied-domains: ["*"]
+//	  - id: pr-requires-threat-detection
+//	    safe-outputs:
+//	      require-threat-detection: [create-pull-request]
+//	  - id: max-timeout
+//	    level: warning
+//	    timeout-minutes:
+//	      max: 30
+//	  - id: approved-mcp-servers
+//	    mcp-servers:
+//	      allowed: [io.github.github/*, com.example/internal-search]
+//
+// Each rule has a stable id that is reported with every violation, an optional
+// level (error or warning, default error), optional workflow filters, and
+// exactly one condition block.
+//
+// Policies can be loaded from a local path or from a remote workflowspec
+// (owner/repo/path@ref), the same format used by imports.
+//
+// See policy_validation.go for rule evaluation.
+
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/goccy/go-yaml"
+)
+
+var policyLog = logger.New("workflow:policy")
+
+// DefaultPolicyPath is the repository-relative path of the policy file that is
+// picked up automatically when no explicit policy is configured
+const DefaultPolicyPath = ".github/aw/policy.yml"
+
+// PolicyLevel is the severity reported when a policy rule is violated
+type PolicyLevel string
+
+const (
+	// PolicyLevelError fails compilation when the rule is violated
+	PolicyLevelError PolicyLevel = "error"
+	// PolicyLevelWarning reports the violation as a compiler warning
+	PolicyLevelWarning PolicyLevel = "warning"
+)
+
+// policyRuleIDPattern restricts rule ids to stable, greppable identifiers
+var policyRuleIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Policy is an organization policy made of rules evaluated against every workflow
+type Policy struct {
+	Version int          `yaml:"version,omitempty"`
+	Rules   []PolicyRule `yaml:"rules"`
+	Source  string       `yaml:"-"` // Path or workflowspec the policy was loaded from
+}
+
+// PolicyRule is a single policy rule. Exactly one condition block must be set.
+type PolicyRule struct {
+	ID          string      `yaml:"id"`
+	Description string      `yaml:"description,omitempty"`
+	Level       PolicyLevel `yaml:"level,omitempty"`
+	Workflows   []string    `yaml:"workflows,omitempty"` // Workflow IDs (glob patterns) the rule applies to; empty means all
+	Except      []string    `yaml:"except,omitempty"`    // Workflow IDs (glob patterns) exempt from the rule
+
+	Permissions    *PolicyPermissionsRule `yaml:"permissions,omitempty"`
+	Engines        *PolicyEnginesRule     `yaml:"engines,omitempty"`
+	Network        *PolicyNetworkRule     `yaml:"network,omitempty"`
+	SafeOutputs    *PolicySafeOutputsRule `yaml:"safe-outputs,omitempty"`
+	TimeoutMinutes *PolicyTimeoutRule     `yaml:"timeout-minutes,omitempty"`
+	MCPServers     *PolicyMCPServersRule  `yaml:"mcp-servers,omitempty"`
+}
+
+// PolicyPermissionsRule restricts the top-level workflow permissions
+type PolicyPermissionsRule struct {
+	DenyWrite []string `yaml:"deny-write,omitempty"` // Scopes that must not be granted write access ("all" matches every scope)
+}
+
+// PolicyEnginesRule restricts which agentic engines may be used
+type PolicyEnginesRule struct {
+	Allowed []string `yaml:"allowed,omitempty"`
+}
+
+// PolicyNetworkRule restricts the network allow list
+type PolicyNetworkRule struct {
+	DeniedDomains []string `yaml:"denied-domains,omitempty"` // Entries that must not appear in network.allowed (e.g. "*")
+}
+
+// PolicySafeOutputsRule restricts safe-output usage
+type PolicySafeOutputsRule struct {
+	Denied                 []string `yaml:"denied,omitempty"`                   // Safe outputs that must not be configured
+	RequireThreatDetection []string `yaml:"require-threat-detection,omitempty"` // Safe outputs that require threat detection ("all" matches every safe output)
+}
+
+// PolicyTimeoutRule bounds the agent job timeout
+type PolicyTimeoutRule struct {
+	Max int `yaml:"max,omitempty"`
+}
+
+// PolicyMCPServersRule restricts which custom MCP servers may be used
+type PolicyMCPServersRule struct {
+	Allowed []string `yaml:"allowed,omitempty"` // Registry server names, or workflow server names for servers not added from a registry; '*' matches any sequence of characters
+}
+
+// Allows reports whether the registry server name matches one of the allowed patterns
+func (r *PolicyMCPServersRule) Allows(serverName string) bool {
+	for _, pattern := range r.Allowed {
+		if matchesMCPServerPattern(serverName, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesMCPServerPattern matches a registry server name against a pattern in which
+// '*' matches any sequence of characters, including the '/' separating namespace and name
+func matchesMCPServerPattern(serverName string, pattern string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return serverName == pattern
+	}
+	if !strings.HasPrefix(serverName, parts[0]) {
+		return false
+	}
+	rest := serverName[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	return strings.HasSuffix(rest, parts[len(parts)-1])
+}
+
+// EffectiveLevel returns the rule level, defaulting to error
+func (r *PolicyRule) EffectiveLevel() PolicyLevel {
+	if r.Level == "" {
+		return PolicyLevelError
+	}
+	return r.Level
+}
+
+// conditionCount returns the number of condition blocks configured on the rule
+func (r *PolicyRule) conditionCount() int {
+	count := 0
+	if r.Permissions != nil {
+		count++
+	}
+	if r.Engines != nil {
+		count++
+	}
+	if r.Network != nil {
+		count++
+	}
+	if r.SafeOutputs != nil {
+		count++
+	}
+	if r.TimeoutMinutes != nil {
+		count++
+	}
+	if r.MCPServers != nil {
+		count++
+	}
+	return count
+}
+
+// AppliesTo reports whether the rule applies to the given workflow ID
+func (r *PolicyRule) AppliesTo(workflowID string) bool {
+	if len(r.Workflows) > 0 && !matchesAnyWorkflowPattern(workflowID, r.Workflows) {
+		return false
+	}
+	return !matchesAnyWorkflowPattern(workflowID, r.Except)
+}
+
+// matchesAnyWorkflowPattern reports whether workflowID matches one of the glob patterns
+func matchesAnyWorkflowPattern(workflowID string, patterns []string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.TrimSuffix(pattern, ".md")
+		if matched, err := filepath.Match(pattern, workflowID); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePolicy parses and validates policy YAML content
+func ParsePolicy(content []byte, source string) (*Policy, error) {
+	policyLog.Printf("Parsing policy: source=%s, size=%d", source, len(content))
+
+	var policy Policy
+	if err := yaml.UnmarshalWithOptions(content, &policy, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %s", source, yaml.FormatError(err, false, false))
+	}
+	policy.Source = source
+
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", source, err)
+	}
+
+	policyLog.Printf("Parsed policy with %d rules", len(policy.Rules))
+	return &policy, nil
+}
+
+// Validate checks that the policy is well formed
+func (p *Policy) Validate() error {
+	if p.Version != 0 && p.Version != 1 {
+		return fmt.Errorf("unsupported policy version %d (supported: 1)", p.Version)
+	}
+
+	seen := make(map[string]bool)
+	for i, rule := range p.Rules {
+		if rule.ID == "" {
+			return fmt.Errorf("rules[%d]: id is required", i)
+		}
+		if !policyRuleIDPattern.MatchString(rule.ID) {
+			return fmt.Errorf("rules[%d]: id '%s' must contain only lowercase letters, digits, '.', '_' and '-'", i, rule.ID)
+		}
+		if seen[rule.ID] {
+			return fmt.Errorf("rules[%d]: duplicate rule id '%s'", i, rule.ID)
+		}
+		seen[rule.ID] = true
+
+		switch rule.Level {
+		case "", PolicyLevelError, PolicyLevelWarning:
+		default:
+			return fmt.Errorf("rule '%s': invalid level '%s' (must be 'error' or 'warning')", rule.ID, rule.Level)
+		}
+
+		if n := rule.conditionCount(); n != 1 {
+			return fmt.Errorf("rule '%s': exactly one condition is required (permissions, engines, network, safe-outputs, timeout-minutes, mcp-servers), found %d", rule.ID, n)
+		}
+
+		if rule.TimeoutMinutes != nil && rule.TimeoutMinutes.Max <= 0 {
+			return fmt.Errorf("rule '%s': timeout-minutes.max must be a positive integer", rule.ID)
+		}
+		if rule.Engines != nil && len(rule.Engines.Allowed) == 0 {
+			return fmt.Errorf("rule '%s': engines.allowed must list at least one engine", rule.ID)
+		}
+		if rule.MCPServers != nil && len(rule.MCPServers.Allowed) == 0 {
+			return fmt.Errorf("rule '%s': mcp-servers.allowed must list at least one server", rule.ID)
+		}
+	}
+	return nil
+}
+
+// AllowsMCPServer reports whether the error-level mcp-servers rules that apply to the
+// workflow allow the registry server. An empty workflowID selects the rules that are not
+// restricted to specific workflows, which is what applies before a workflow is chosen.
+func (p *Policy) AllowsMCPServer(workflowID string, serverName string) bool {
+	if p == nil {
+		return true
+	}
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.MCPServers == nil || rule.EffectiveLevel() != PolicyLevelError {
+			continue
+		}
+		if workflowID == "" {
+			if len(rule.Workflows) > 0 {
+				continue
+			}
+		} else if !rule.AppliesTo(workflowID) {
+			continue
+		}
+		if !rule.MCPServers.Allows(serverName) {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadPolicy loads a policy from a local path or a remote workflowspec (owner/repo/path@ref).
+// Remote policies are downloaded through the import cache rooted at baseDir.
+func LoadPolicy(policyPath string, baseDir string) (*Policy, error) {
+	policyLog.Printf("Loading policy: path=%s", policyPath)
+
+	resolvedPath := policyPath
+	if _, err := os.Stat(policyPath); err != nil {
+		// Not a local file: resolve through the import machinery, which handles workflowspecs
+		cache := parser.NewImportCache(baseDir)
+		resolved, resolveErr := parser.ResolveIncludePath(policyPath, filepath.Join(baseDir, ".github"), cache)
+		if resolveErr != nil {
+			return nil, fmt.Errorf("failed to resolve policy %s: %w", policyPath, resolveErr)
+		}
+		resolvedPath = resolved
+	}
+
+	content, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy %s: %w", policyPath, err)
+	}
+
+	return ParsePolicy(content, policyPath)
+}
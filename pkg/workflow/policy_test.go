@@ -0,0 +1,349 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicyYAML = `version: 1
+rules:
+  - id: no-contents-write
+    except: [release-*]
+    permissions:
+      deny-write: [contents]
+  - id: approved-engines
+    engines:
+      allowed: [claude, copilot]
+  - id: no-network-wildcard
+    network:
+      denied-domains: ["*"]
+  - id: pr-requires-threat-detection
+    safe-outputs:
+      require-threat-detection: [create-pull-request]
+  - id: max-timeout
+    level: warning
+    timeout-minutes:
+      max: 30
+`
+
+func TestParsePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicyYAML), "policy.yml")
+	require.NoError(t, err, "valid policy should parse")
+	require.Len(t, policy.Rules, 5)
+	assert.Equal(t, "policy.yml", policy.Source)
+	assert.Equal(t, PolicyLevelError, policy.Rules[0].EffectiveLevel(), "level should default to error")
+	assert.Equal(t, PolicyLevelWarning, policy.Rules[4].EffectiveLevel())
+}
+
+func TestParsePolicyErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		errorContains string
+	}{
+		{
+			name:          "missing id",
+			content:       "rules:\n  - engines:\n      allowed: [claude]\n",
+			errorContains: "id is required",
+		},
+		{
+			name:          "invalid id",
+			content:       "rules:\n  - id: No Spaces\n    engines:\n      allowed: [claude]\n",
+			errorContains: "must contain only lowercase",
+		},
+		{
+			name:          "duplicate id",
+			content:       "rules:\n  - id: a\n    engines:\n      allowed: [claude]\n  - id: a\n    timeout-minutes:\n      max: 5\n",
+			errorContains: "duplicate rule id 'a'",
+		},
+		{
+			name:          "no condition",
+			content:       "rules:\n  - id: empty\n",
+			errorContains: "exactly one condition is required",
+		},
+		{
+			name:          "two conditions",
+			content:       "rules:\n  - id: two\n    engines:\n      allowed: [claude]\n    timeout-minutes:\n      max: 5\n",
+			errorContains: "found 2",
+		},
+		{
+			name:          "invalid level",
+			content:       "rules:\n  - id: lvl\n    level: fatal\n    timeout-minutes:\n      max: 5\n",
+			errorContains: "invalid level 'fatal'",
+		},
+		{
+			name:          "non-positive timeout",
+			content:       "rules:\n  - id: t\n    timeout-minutes:\n      max: 0\n",
+			errorContains: "must be a positive integer",
+		},
+		{
+			name:          "empty mcp-servers allowlist",
+			content:       "rules:\n  - id: m\n    mcp-servers:\n      allowed: []\n",
+			errorContains: "mcp-servers.allowed must list at least one server",
+		},
+		{
+			name:          "unknown field",
+			content:       "rules:\n  - id: t\n    unknown: true\n",
+			errorContains: "failed to parse policy",
+		},
+		{
+			name:          "unsupported version",
+			content:       "version: 2\nrules: []\n",
+			errorContains: "unsupported policy version 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.content), "policy.yml")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
+
+func TestPolicyRuleAppliesTo(t *testing.T) {
+	rule := PolicyRule{Workflows: []string{"ci-*", "daily.md"}, Except: []string{"ci-release"}}
+	assert.True(t, rule.AppliesTo("ci-doctor"))
+	assert.True(t, rule.AppliesTo("daily"))
+	assert.False(t, rule.AppliesTo("ci-release"), "except should take precedence")
+	assert.False(t, rule.AppliesTo("weekly"))
+
+	assert.True(t, (&PolicyRule{}).AppliesTo("anything"), "rule without filters applies to all workflows")
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	policy, err := ParsePolicy([]byte(testPolicyYAML), "policy.yml")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		data     *WorkflowData
+		expected []string // rule ids in order
+	}{
+		{
+			name: "compliant workflow",
+			data: &WorkflowData{
+				WorkflowID:         "triage",
+				Permissions:        "permissions:\n  contents: read",
+				EngineConfig:       &EngineConfig{ID: "copilot"},
+				NetworkPermissions: &NetworkPermissions{Allowed: []string{"defaults"}},
+				TimeoutMinutes:     "timeout-minutes: 20",
+			},
+			expected: nil,
+		},
+		{
+			name: "contents write outside allowed workflows",
+			data: &WorkflowData{
+				WorkflowID:  "triage",
+				Permissions: "permissions:\n  contents: write",
+			},
+			expected: []string{"no-contents-write"},
+		},
+		{
+			name: "contents write in exempt workflow",
+			data: &WorkflowData{
+				WorkflowID:  "release-notes",
+				Permissions: "permissions:\n  contents: write",
+			},
+			expected: nil,
+		},
+		{
+			name: "engine not allowed",
+			data: &WorkflowData{
+				WorkflowID:   "triage",
+				EngineConfig: &EngineConfig{ID: "codex"},
+			},
+			expected: []string{"approved-engines"},
+		},
+		{
+			name: "fallback engine not allowed",
+			data: &WorkflowData{
+				WorkflowID:   "triage",
+				EngineConfig: &EngineConfig{ID: "copilot", Fallback: []*EngineConfig{{ID: "codex"}}},
+			},
+			expected: []string{"approved-engines"},
+		},
+		{
+			name: "phase engine not allowed",
+			data: &WorkflowData{
+				WorkflowID:   "triage",
+				EngineConfig: &EngineConfig{ID: "copilot"},
+				Phases:       []*PhaseConfig{{ID: "plan"}, {ID: "execute", EngineConfig: &EngineConfig{ID: "codex"}}},
+			},
+			expected: []string{"approved-engines"},
+		},
+		{
+			name: "network wildcard",
+			data: &WorkflowData{
+				WorkflowID:         "triage",
+				NetworkPermissions: &NetworkPermissions{Allowed: []string{"github", "*"}},
+			},
+			expected: []string{"no-network-wildcard"},
+		},
+		{
+			name: "create-pull-request without threat detection",
+			data: &WorkflowData{
+				WorkflowID:  "triage",
+				SafeOutputs: &SafeOutputsConfig{CreatePullRequests: &CreatePullRequestsConfig{}},
+			},
+			expected: []string{"pr-requires-threat-detection"},
+		},
+		{
+			name: "create-pull-request with threat detection",
+			data: &WorkflowData{
+				WorkflowID: "triage",
+				SafeOutputs: &SafeOutputsConfig{
+					CreatePullRequests: &CreatePullRequestsConfig{},
+					ThreatDetection:    &ThreatDetectionConfig{},
+				},
+			},
+			expected: nil,
+		},
+		{
+			name: "timeout above maximum",
+			data: &WorkflowData{
+				WorkflowID:     "triage",
+				TimeoutMinutes: "timeout-minutes: 45",
+			},
+			expected: []string{"max-timeout"},
+		},
+		{
+			name: "timeout expression is not evaluated",
+			data: &WorkflowData{
+				WorkflowID:     "triage",
+				TimeoutMinutes: "timeout-minutes: ${{ inputs.timeout }}",
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violations := EvaluatePolicy(policy, tt.data)
+			var ids []string
+			for _, v := range violations {
+				ids = append(ids, v.RuleID)
+				assert.Equal(t, tt.data.WorkflowID, v.Workflow)
+			}
+			assert.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestEvaluatePolicyDeniedSafeOutputs(t *testing.T) {
+	policy := &Policy{Rules: []PolicyRule{{
+		ID:          "no-push",
+		Level:       PolicyLevelWarning,
+		SafeOutputs: &PolicySafeOutputsRule{Denied: []string{"push-to-pull-request-branch"}},
+	}}}
+	data := &WorkflowData{
+		WorkflowID:  "fixer",
+		SafeOutputs: &SafeOutputsConfig{PushToPullRequestBranch: &PushToPullRequestBranchConfig{}},
+	}
+
+	violations := EvaluatePolicy(policy, data)
+	require.Len(t, violations, 1)
+	assert.Equal(t, PolicyLevelWarning, violations[0].Level)
+	assert.Equal(t, "policy [no-push]: safe-outputs.push-to-pull-request-branch is not allowed", violations[0].String())
+}
+
+func TestPolicyAllowsMCPServer(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`rules:
+  - id: approved-mcp-servers
+    mcp-servers:
+      allowed: [io.github.github/*, com.example/search]
+  - id: research-mcp-servers
+    workflows: [research-*]
+    mcp-servers:
+      allowed: [io.github.github/github-mcp-server]
+  - id: suggested-mcp-servers
+    level: warning
+    mcp-servers:
+      allowed: [com.example/search]
+`), "policy.yml")
+	require.NoError(t, err)
+
+	assert.True(t, policy.AllowsMCPServer("", "io.github.github/github-mcp-server"))
+	assert.True(t, policy.AllowsMCPServer("", "com.example/search"))
+	assert.False(t, policy.AllowsMCPServer("", "com.example/search-v2"), "patterns without '*' must match exactly")
+	assert.False(t, policy.AllowsMCPServer("", "io.github.makenotion/notion-mcp-server"))
+	assert.False(t, policy.AllowsMCPServer("research-daily", "com.example/search"), "workflow-specific rules apply to matching workflows")
+	assert.True(t, policy.AllowsMCPServer("triage", "com.example/search"))
+	assert.True(t, (*Policy)(nil).AllowsMCPServer("", "anything"), "no policy allows every server")
+
+	violations := EvaluatePolicy(policy, &WorkflowData{
+		WorkflowID: "triage",
+		Tools: map[string]any{
+			"github": map[string]any{"toolsets": []any{"issues"}},
+			"notion": map[string]any{"registry": "https://api.mcp.github.com/v0.1/servers/io.github.makenotion/notion-mcp-server"},
+			"search": map[string]any{"registry": "file:///srv/mcp-mirror/servers/com.example/search"},
+			"tavily": map[string]any{"url": "https://mcp.tavily.com/mcp/"},
+			"bash":   []any{"echo"},
+		},
+	})
+	require.Len(t, violations, 4)
+	assert.Equal(t, "policy [approved-mcp-servers]: MCP server 'notion' (io.github.makenotion/notion-mcp-server) is not an approved registry server", violations[0].String())
+	assert.Equal(t, "policy [approved-mcp-servers]: MCP server 'tavily' is not an approved server", violations[1].String(), "servers not added from a registry are checked by name")
+	assert.Equal(t, "suggested-mcp-servers", violations[2].RuleID)
+	assert.Equal(t, PolicyLevelWarning, violations[2].Level)
+
+	violations = EvaluatePolicy(policy, &WorkflowData{
+		WorkflowID: "triage",
+		Tools:      map[string]any{"custom": map[string]any{"command": "node", "registry": "https://registry.example.com/custom"}},
+	})
+	require.NotEmpty(t, violations)
+	assert.Contains(t, violations[0].String(), "unrecognized registry reference", "registry references that cannot be matched must not bypass the allowlist")
+}
+
+func TestLoadPolicyFromFile(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "policy-load")
+	policyPath := filepath.Join(tmpDir, "policy.yml")
+	require.NoError(t, os.WriteFile(policyPath, []byte(testPolicyYAML), 0644))
+
+	policy, err := LoadPolicy(policyPath, tmpDir)
+	require.NoError(t, err)
+	assert.Len(t, policy.Rules, 5)
+
+	_, err = LoadPolicy(filepath.Join(tmpDir, ".github", "missing.yml"), tmpDir)
+	require.Error(t, err, "missing policy file should fail")
+}
+
+func TestCompileWorkflowWithPolicy(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "policy-compile")
+
+	workflowContent := `---
+on: workflow_dispatch
+engine: codex
+timeout-minutes: 45
+permissions:
+  contents: read
+---
+
+# Policy Test
+`
+	workflowPath := filepath.Join(tmpDir, "policy-test.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflowContent), 0644))
+
+	policy, err := ParsePolicy([]byte(testPolicyYAML), "policy.yml")
+	require.NoError(t, err)
+
+	compiler := NewCompiler(WithPolicy(policy))
+	err = compiler.CompileWorkflow(workflowPath)
+	require.Error(t, err, "engine violation should fail compilation")
+	assert.Contains(t, err.Error(), "policy [approved-engines]")
+	assert.NotContains(t, err.Error(), "max-timeout", "warnings should not be reported as errors")
+	assert.Equal(t, 1, compiler.GetWarningCount(), "timeout violation should be a warning")
+
+	// Relax the engine rule; the warning alone must not fail compilation
+	policy.Rules[1].Engines.Allowed = append(policy.Rules[1].Engines.Allowed, "codex")
+	compiler = NewCompiler(WithPolicy(policy))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+}
@@ -0,0 +1,271 @@
+// This file provides organization policy evaluation for agentic workflows.
+//
+// This file contains the evaluation of policy rules (see policy.go) against parsed
+// workflow data:
+//   - EvaluatePolicy() - Evaluates every applicable rule and returns violations
+//   - validatePolicy() - Compiler integration reporting errors and warnings
+//
+// These validation functions are organized in a dedicated file following the validation
+// architecture pattern where domain-specific validation belongs in domain validation files.
+// See validation.go for the complete validation architecture documentation.
+
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var policyValidationLog = logger.New("workflow:policy_validation")
+
+// PolicyViolation describes a single policy rule violated by a workflow
+type PolicyViolation struct {
+	RuleID   string      `json:"rule_id"`
+	Level    PolicyLevel `json:"level"`
+	Workflow string      `json:"workflow"`
+	Message  string      `json:"message"`
+}
+
+// String formats the violation as "[rule-id] message"
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("policy [%s]: %s", v.RuleID, v.Message)
+}
+
+// EvaluatePolicy evaluates all policy rules that apply to the workflow and returns
+// the violations in rule order
+func EvaluatePolicy(policy *Policy, workflowData *WorkflowData) []PolicyViolation {
+	if policy == nil || workflowData == nil {
+		return nil
+	}
+
+	policyValidationLog.Printf("Evaluating %d policy rules for workflow %s", len(policy.Rules), workflowData.WorkflowID)
+
+	var violations []PolicyViolation
+	for i := range policy.Rules {
+		rule := &policy.Rules[i]
+		if !rule.AppliesTo(workflowData.WorkflowID) {
+			policyValidationLog.Printf("Rule %s does not apply to %s", rule.ID, workflowData.WorkflowID)
+			continue
+		}
+
+		for _, message := range evaluatePolicyRule(rule, workflowData) {
+			violations = append(violations, PolicyViolation{
+				RuleID:   rule.ID,
+				Level:    rule.EffectiveLevel(),
+				Workflow: workflowData.WorkflowID,
+				Message:  message,
+			})
+		}
+	}
+
+	policyValidationLog.Printf("Policy evaluation found %d violations", len(violations))
+	return violations
+}
+
+// evaluatePolicyRule returns one message per violation of the rule's condition
+func evaluatePolicyRule(rule *PolicyRule, workflowData *WorkflowData) []string {
+	switch {
+	case rule.Permissions != nil:
+		return evaluatePermissionsPolicy(rule.Permissions, workflowData)
+	case rule.Engines != nil:
+		return evaluateEnginesPolicy(rule.Engines, workflowData)
+	case rule.Network != nil:
+		return evaluateNetworkPolicy(rule.Network, workflowData)
+	case rule.SafeOutputs != nil:
+		return evaluateSafeOutputsPolicy(rule.SafeOutputs, workflowData)
+	case rule.TimeoutMinutes != nil:
+		return evaluateTimeoutPolicy(rule.TimeoutMinutes, workflowData)
+	case rule.MCPServers != nil:
+		return evaluateMCPServersPolicy(rule.MCPServers, workflowData)
+	}
+	return nil
+}
+
+func evaluatePermissionsPolicy(rule *PolicyPermissionsRule, workflowData *WorkflowData) []string {
+	if workflowData.Permissions == "" {
+		return nil
+	}
+	permissions := NewPermissionsParser(workflowData.Permissions).ToPermissions()
+	denyAll := slices.Contains(rule.DenyWrite, "all")
+
+	var messages []string
+	for _, scope := range findWritePermissions(permissions) {
+		if denyAll || slices.Contains(rule.DenyWrite, string(scope)) {
+			messages = append(messages, fmt.Sprintf("'%s: write' permission is not allowed", scope))
+		}
+	}
+	return messages
+}
+
+// evaluateEnginesPolicy checks the workflow engine, every engine of its fallback chain and
+// the engines of its phases, since each of them may run the agent
+func evaluateEnginesPolicy(rule *PolicyEnginesRule, workflowData *WorkflowData) []string {
+	engineID := workflowData.AI
+	if workflowData.EngineConfig != nil && workflowData.EngineConfig.ID != "" {
+		engineID = workflowData.EngineConfig.ID
+	}
+
+	var messages []string
+	if engineID != "" && !slices.Contains(rule.Allowed, engineID) {
+		messages = append(messages, fmt.Sprintf("engine '%s' is not allowed (allowed: %s)", engineID, strings.Join(rule.Allowed, ", ")))
+	}
+	for _, fallbackID := range engineFallbackIDs(workflowData) {
+		if !slices.Contains(rule.Allowed, fallbackID) {
+			messages = append(messages, fmt.Sprintf("fallback engine '%s' is not allowed (allowed: %s)", fallbackID, strings.Join(rule.Allowed, ", ")))
+		}
+	}
+	for _, phase := range workflowData.Phases {
+		if phase.EngineConfig != nil && phase.EngineConfig.ID != "" && !slices.Contains(rule.Allowed, phase.EngineConfig.ID) {
+			messages = append(messages, fmt.Sprintf("engine '%s' of phase '%s' is not allowed (allowed: %s)", phase.EngineConfig.ID, phase.ID, strings.Join(rule.Allowed, ", ")))
+		}
+	}
+	return messages
+}
+
+func evaluateNetworkPolicy(rule *PolicyNetworkRule, workflowData *WorkflowData) []string {
+	if workflowData.NetworkPermissions == nil {
+		return nil
+	}
+	var messages []string
+	for _, domain := range workflowData.NetworkPermissions.Allowed {
+		if slices.Contains(rule.DeniedDomains, domain) {
+			messages = append(messages, fmt.Sprintf("network.allowed must not include '%s'", domain))
+		}
+	}
+	return messages
+}
+
+func evaluateSafeOutputsPolicy(rule *PolicySafeOutputsRule, workflowData *WorkflowData) []string {
+	enabled := GetEnabledSafeOutputToolNames(workflowData.SafeOutputs)
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	denied := normalizeSafeOutputNames(rule.Denied)
+	requireDetection := normalizeSafeOutputNames(rule.RequireThreatDetection)
+	hasThreatDetection := workflowData.SafeOutputs != nil && workflowData.SafeOutputs.ThreatDetection != nil
+
+	var messages []string
+	for _, name := range enabled {
+		displayName := strings.ReplaceAll(name, "_", "-")
+		if denied[name] {
+			messages = append(messages, fmt.Sprintf("safe-outputs.%s is not allowed", displayName))
+		}
+		if !hasThreatDetection && (requireDetection[name] || requireDetection["all"]) {
+			messages = append(messages, fmt.Sprintf("safe-outputs.%s requires safe-outputs.threat-detection to be enabled", displayName))
+		}
+	}
+	return messages
+}
+
+// normalizeSafeOutputNames converts safe-output names to the tool name form (create_pull_request)
+func normalizeSafeOutputNames(names []string) map[string]bool {
+	normalized := make(map[string]bool, len(names))
+	for _, name := range names {
+		normalized[strings.ReplaceAll(name, "-", "_")] = true
+	}
+	return normalized
+}
+
+func evaluateTimeoutPolicy(rule *PolicyTimeoutRule, workflowData *WorkflowData) []string {
+	value := strings.TrimSpace(strings.TrimPrefix(workflowData.TimeoutMinutes, "timeout-minutes:"))
+	if value == "" {
+		return nil
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		// Expressions cannot be evaluated at compile time
+		policyValidationLog.Printf("Skipping timeout policy for non-numeric timeout: %s", value)
+		return nil
+	}
+	if minutes > rule.Max {
+		return []string{fmt.Sprintf("timeout-minutes %d exceeds the maximum of %d", minutes, rule.Max)}
+	}
+	return nil
+}
+
+// evaluateMCPServersPolicy checks every custom MCP server. Servers added from a registry
+// record their registry entry as <registry>/servers/<name> in the registry field and are
+// matched by that name; other servers are matched by their name in the workflow.
+func evaluateMCPServersPolicy(rule *PolicyMCPServersRule, workflowData *WorkflowData) []string {
+	toolNames := make([]string, 0, len(workflowData.Tools))
+	for toolName := range workflowData.Tools {
+		toolNames = append(toolNames, toolName)
+	}
+	sort.Strings(toolNames)
+
+	var messages []string
+	for _, toolName := range toolNames {
+		toolConfig, ok := workflowData.Tools[toolName].(map[string]any)
+		if !ok {
+			continue
+		}
+		registry, hasRegistry := toolConfig["registry"].(string)
+		if !hasRegistry {
+			// Built-in tools (github, playwright, ...) have no MCP server configuration
+			if hasMcp, _ := hasMCPConfig(toolConfig); !hasMcp {
+				continue
+			}
+			if !rule.Allows(toolName) {
+				messages = append(messages, fmt.Sprintf("MCP server '%s' is not an approved server", toolName))
+			}
+			continue
+		}
+
+		serverName := ""
+		if idx := strings.LastIndex(registry, "/servers/"); idx >= 0 {
+			serverName = registry[idx+len("/servers/"):]
+		}
+		if serverName == "" {
+			messages = append(messages, fmt.Sprintf("MCP server '%s' has an unrecognized registry reference '%s' and cannot be approved", toolName, registry))
+			continue
+		}
+		if !rule.Allows(serverName) {
+			messages = append(messages, fmt.Sprintf("MCP server '%s' (%s) is not an approved registry server", toolName, serverName))
+		}
+	}
+	return messages
+}
+
+// SortPolicyViolations orders violations by workflow, then level (errors first), then rule id
+func SortPolicyViolations(violations []PolicyViolation) {
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Workflow != violations[j].Workflow {
+			return violations[i].Workflow < violations[j].Workflow
+		}
+		if violations[i].Level != violations[j].Level {
+			return violations[i].Level == PolicyLevelError
+		}
+		return violations[i].RuleID < violations[j].RuleID
+	})
+}
+
+// validatePolicy evaluates the compiler's policy against the workflow. Warning-level
+// violations are printed and counted; error-level violations fail compilation.
+func (c *Compiler) validatePolicy(workflowData *WorkflowData, markdownPath string) error {
+	if c.policy == nil {
+		return nil
+	}
+
+	violations := EvaluatePolicy(c.policy, workflowData)
+	var errorMessages []string
+	for _, violation := range violations {
+		if violation.Level == PolicyLevelWarning {
+			fmt.Fprintln(os.Stderr, formatCompilerMessage(markdownPath, "warning", violation.String()))
+			c.IncrementWarningCount()
+			continue
+		}
+		errorMessages = append(errorMessages, violation.String())
+	}
+
+	if len(errorMessages) > 0 {
+		return formatCompilerError(markdownPath, "error", strings.Join(errorMessages, "\n"), nil)
+	}
+	return nil
+}
@@ -0,0 +1,509 @@
+// This file provides prompt size analysis for compiled workflows.
+//
+// # Prompt Report
+//
+// The agent prompt is assembled at runtime from several sources:
+//   - Built-in sections (XPIA protection, temporary folder, safe outputs, GitHub context, ...)
+//   - Imported markdown with inputs, inlined at compile time
+//   - Imported markdown without inputs, loaded with {{#runtime-import}} macros
+//   - The main workflow markdown, loaded with a {{#runtime-import}} macro
+//
+// BuildPromptReport renders the same prompt at compile time for a sample event: GitHub
+// expressions are replaced with sample values of the event payload and {{#if}} blocks
+// are evaluated like render_template.cjs does at runtime. Each section is measured with
+// a token estimate of four characters per token, paragraphs repeated across sections are
+// flagged as duplicates, and the total is compared with an optional token budget.
+//
+// The built-in prompt files are copied from actions/setup/md by `make sync-prompts`
+// and embedded so the report matches what the setup action installs.
+
+package workflow
+
+import (
+	"crypto/sha256"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+)
+
+var promptReportLog = logger.New("workflow:prompt_report")
+
+//go:embed data/prompts/*.md
+var builtinPromptFiles embed.FS
+
+// promptCharsPerToken is the heuristic used to estimate tokens from characters
+const promptCharsPerToken = 4
+
+// minDuplicateParagraphChars is the minimum length of a paragraph considered for duplicate detection
+const minDuplicateParagraphChars = 80
+
+// PromptReportOptions configures the prompt report written during compilation
+type PromptReportOptions struct {
+	Event  string // Sample event used to render the prompt (defaults to the first trigger of the workflow)
+	Budget int    // Token budget; a warning is emitted when the estimated prompt exceeds it (0 disables the check)
+}
+
+// PromptReportSection is the size of a single prompt section
+type PromptReportSection struct {
+	Name     string `json:"name"`
+	Source   string `json:"source"`            // Built-in prompt file, import path or workflow path
+	Included bool   `json:"included"`          // False when the section is skipped for the sample event
+	Missing  bool   `json:"missing,omitempty"` // True when the source file could not be read
+	Chars    int    `json:"chars"`             // Characters of the rendered section
+	Tokens   int    `json:"tokens"`            // Estimated tokens of the rendered section
+	Content  string `json:"content,omitempty"` // Rendered section text
+}
+
+// PromptDuplicate is a paragraph that appears more than once in the rendered prompt
+type PromptDuplicate struct {
+	Excerpt      string   `json:"excerpt"`
+	Sections     []string `json:"sections"`
+	Occurrences  int      `json:"occurrences"`
+	WastedTokens int      `json:"wasted_tokens"` // Estimated tokens of the repeated occurrences
+}
+
+// PromptReport is the rendered prompt of a workflow broken down by section
+type PromptReport struct {
+	Workflow    string                `json:"workflow"`
+	Event       string                `json:"event"`
+	Sections    []PromptReportSection `json:"sections"`
+	Duplicates  []PromptDuplicate     `json:"duplicates,omitempty"`
+	TotalChars  int                   `json:"total_chars"`
+	TotalTokens int                   `json:"total_tokens"`
+	Budget      int                   `json:"budget,omitempty"`
+}
+
+// OverBudget reports whether the estimated prompt size exceeds the budget
+func (r *PromptReport) OverBudget() bool {
+	return r.Budget > 0 && r.TotalTokens > r.Budget
+}
+
+// Prompt returns the rendered prompt of the included sections
+func (r *PromptReport) Prompt() string {
+	var parts []string
+	for _, section := range r.Sections {
+		if section.Included && section.Content != "" {
+			parts = append(parts, section.Content)
+		}
+	}
+	return strings.Join(parts, "\n\n") + "\n"
+}
+
+// estimatePromptTokens estimates the number of tokens of a prompt text
+func estimatePromptTokens(text string) int {
+	return (len(text) + promptCharsPerToken - 1) / promptCharsPerToken
+}
+
+// promptReportEvents lists the events with sample payloads, in the order used to pick
+// the default event of a workflow (events with richer payloads first)
+var promptReportEvents = []string{
+	"issue_comment",
+	"pull_request_review_comment",
+	"pull_request_review",
+	"issues",
+	"pull_request",
+	"pull_request_target",
+	"discussion_comment",
+	"discussion",
+	"push",
+	"release",
+	"schedule",
+	"workflow_dispatch",
+}
+
+// defaultPromptReportEvent returns the first event with a sample payload triggering the workflow
+func defaultPromptReportEvent(data *WorkflowData) string {
+	if len(data.Command) > 0 {
+		return "issue_comment"
+	}
+	for _, event := range promptReportEvents {
+		if regexp.MustCompile(`\b` + event + `\b`).MatchString(data.On) {
+			return event
+		}
+	}
+	return "workflow_dispatch"
+}
+
+const samplePromptIssueBody = "When a workflow run fails, the summary only shows the exit code of the agent step. " +
+	"It would help to include the last lines of the agent log so maintainers can triage failures without downloading artifacts."
+
+const samplePromptPullRequestBody = "This pull request adds retries with exponential backoff to the API client. " +
+	"Requests that fail with 429 or 5xx responses are retried up to three times before the error is returned."
+
+const samplePromptCommentBody = "Could you take a look at this and suggest a fix? The failure started after the last dependency update."
+
+// samplePromptContext returns sample values of GitHub expressions for an event.
+// Expressions without a sample value render as an empty string.
+func samplePromptContext(event string) map[string]string {
+	values := map[string]string{
+		"github.event_name":       event,
+		"github.actor":            "octocat",
+		"github.repository":       "octo-org/octo-repo",
+		"github.repository_owner": "octo-org",
+		"github.workspace":        "/home/runner/work/octo-repo/octo-repo",
+		"github.server_url":       "https://github.com",
+		"github.run_id":           "1234567890",
+		"github.run_number":       "42",
+		"github.ref":              "refs/heads/main",
+		"github.ref_name":         "main",
+		"github.sha":              "0123456789abcdef0123456789abcdef01234567",
+	}
+
+	issue := func() {
+		values["github.event.issue.number"] = "123"
+		values["github.event.issue.title"] = "Show agent log excerpt in failed run summary"
+		values["github.event.issue.body"] = samplePromptIssueBody
+		values["github.event.issue.state"] = "open"
+		values["github.event.issue.user.login"] = "octocat"
+		values["needs.activation.outputs.text"] = "Show agent log excerpt in failed run summary\n\n" + samplePromptIssueBody
+	}
+	pullRequest := func() {
+		values["github.event.pull_request.number"] = "456"
+		values["github.event.pull_request.title"] = "Retry API requests with exponential backoff"
+		values["github.event.pull_request.body"] = samplePromptPullRequestBody
+		values["github.event.pull_request.state"] = "open"
+		values["github.event.pull_request.head.ref"] = "retry-backoff"
+		values["github.event.pull_request.base.ref"] = "main"
+		values["github.event.pull_request.user.login"] = "octocat"
+		values["needs.activation.outputs.text"] = "Retry API requests with exponential backoff\n\n" + samplePromptPullRequestBody
+	}
+	comment := func() {
+		values["github.event.comment.id"] = "987654321"
+		values["github.event.comment.body"] = samplePromptCommentBody
+		values["github.event.comment.user.login"] = "octocat"
+		values["needs.activation.outputs.text"] = samplePromptCommentBody
+	}
+	discussion := func() {
+		values["github.event.discussion.number"] = "789"
+		values["github.event.discussion.title"] = "Ideas for the next release"
+		values["github.event.discussion.body"] = samplePromptIssueBody
+		values["needs.activation.outputs.text"] = "Ideas for the next release\n\n" + samplePromptIssueBody
+	}
+
+	switch event {
+	case "issues":
+		issue()
+	case "issue_comment":
+		issue()
+		comment()
+	case "pull_request", "pull_request_target":
+		pullRequest()
+	case "pull_request_review_comment", "pull_request_review":
+		pullRequest()
+		comment()
+	case "discussion":
+		discussion()
+	case "discussion_comment":
+		discussion()
+		comment()
+	case "release":
+		values["github.event.release.tag_name"] = "v1.2.0"
+		values["github.event.release.name"] = "v1.2.0"
+	}
+	return values
+}
+
+var (
+	promptExpressionRegex  = regexp.MustCompile(`\$\{\{\s*(.*?)\s*\}\}`)
+	promptPlaceholderRegex = regexp.MustCompile(`__(GH_AW_[A-Z0-9_]+)__`)
+	promptIfOpenRegex      = regexp.MustCompile(`\{\{#if\s+([^}]*?)\s*\}\}`)
+)
+
+// renderPromptText renders prompt text for a sample event: placeholders of the section
+// environment variables and GitHub expressions are substituted, then {{#if}} blocks are evaluated
+func renderPromptText(text string, envVars map[string]string, values map[string]string) string {
+	text = wrapExpressionsInTemplateConditionals(text)
+
+	text = promptPlaceholderRegex.ReplaceAllStringFunc(text, func(match string) string {
+		value, ok := envVars[promptPlaceholderRegex.FindStringSubmatch(match)[1]]
+		if !ok {
+			return match
+		}
+		if expr := promptExpressionRegex.FindStringSubmatch(value); expr != nil {
+			return values[expr[1]]
+		}
+		return value
+	})
+
+	text = promptExpressionRegex.ReplaceAllStringFunc(text, func(match string) string {
+		return values[promptExpressionRegex.FindStringSubmatch(match)[1]]
+	})
+
+	return removeConsecutiveEmptyLines(strings.TrimSpace(renderPromptConditionals(text)))
+}
+
+// renderPromptConditionals evaluates {{#if}} blocks from the innermost one outwards,
+// using the same truthiness rules as render_template.cjs
+func renderPromptConditionals(text string) string {
+	for {
+		locs := promptIfOpenRegex.FindAllStringSubmatchIndex(text, -1)
+		if len(locs) == 0 {
+			return text
+		}
+		loc := locs[len(locs)-1]
+		end := strings.Index(text[loc[1]:], "{{/if}}")
+		if end < 0 {
+			return text
+		}
+		body := text[loc[1] : loc[1]+end]
+		if !isPromptValueTruthy(text[loc[2]:loc[3]]) {
+			body = ""
+		}
+		text = text[:loc[0]] + body + text[loc[1]+end+len("{{/if}}"):]
+	}
+}
+
+// isPromptValueTruthy mirrors isTruthy in render_template.cjs
+func isPromptValueTruthy(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "false", "0", "null", "undefined":
+		return false
+	}
+	return true
+}
+
+// BuildPromptReport renders the prompt of a compiled workflow for a sample event
+func (c *Compiler) BuildPromptReport(data *WorkflowData, options PromptReportOptions) *PromptReport {
+	event := options.Event
+	if event == "" {
+		event = defaultPromptReportEvent(data)
+	}
+	promptReportLog.Printf("Building prompt report for %s with sample event %s", c.markdownPath, event)
+
+	values := samplePromptContext(event)
+	report := &PromptReport{
+		Workflow: filepath.ToSlash(console.ToRelativePath(c.markdownPath)),
+		Event:    event,
+		Budget:   options.Budget,
+	}
+
+	// Built-in sections
+	for _, section := range c.collectPromptSections(data) {
+		entry := PromptReportSection{Name: section.Name, Source: "built-in", Included: true}
+		content := section.Content
+		if section.IsFile {
+			entry.Source = section.Content
+			fileContent, err := builtinPromptFiles.ReadFile("data/prompts/" + section.Content)
+			if err != nil {
+				entry.Missing = true
+			}
+			content = string(fileContent)
+		}
+		if section.ShellCondition != "" {
+			entry.Included = promptSectionIncludedForEvent(event, values)
+		}
+		entry.Content = renderPromptText(normalizeLeadingWhitespace(content), section.EnvVars, values)
+		report.Sections = append(report.Sections, entry)
+	}
+
+	// Imported markdown with inputs (inlined at compile time)
+	if data.ImportedMarkdown != "" {
+		content := removeXMLComments(data.ImportedMarkdown)
+		if len(data.ImportInputs) > 0 {
+			content = SubstituteImportInputs(content, data.ImportInputs)
+		}
+		report.Sections = append(report.Sections, PromptReportSection{
+			Name:     "Imports with inputs",
+			Source:   "inlined",
+			Included: true,
+			Content:  renderPromptText(content, nil, values),
+		})
+	}
+
+	// Imported markdown without inputs (runtime imports)
+	for _, importPath := range data.ImportPaths {
+		entry := PromptReportSection{Name: "Import", Source: filepath.ToSlash(importPath), Included: true}
+		content, err := parser.ExtractMarkdown(c.resolvePromptImportPath(importPath))
+		if err != nil {
+			promptReportLog.Printf("Failed to read runtime import %s: %v", importPath, err)
+			entry.Missing = true
+		}
+		entry.Content = renderPromptText(removeXMLComments(content), nil, values)
+		report.Sections = append(report.Sections, entry)
+	}
+
+	// Main workflow markdown
+	report.Sections = append(report.Sections, PromptReportSection{
+		Name:     "Workflow",
+		Source:   report.Workflow,
+		Included: true,
+		Content:  renderPromptText(removeXMLComments(data.MainWorkflowMarkdown), nil, values),
+	})
+
+	for i := range report.Sections {
+		section := &report.Sections[i]
+		if !section.Included {
+			continue
+		}
+		section.Chars = len(section.Content)
+		section.Tokens = estimatePromptTokens(section.Content)
+		report.TotalChars += section.Chars
+		report.TotalTokens += section.Tokens
+	}
+	report.Duplicates = findPromptDuplicates(report.Sections)
+
+	promptReportLog.Printf("Prompt report: sections=%d, tokens=%d, duplicates=%d", len(report.Sections), report.TotalTokens, len(report.Duplicates))
+	return report
+}
+
+// promptSectionIncludedForEvent evaluates the shell condition of the PR context section for a sample event
+func promptSectionIncludedForEvent(event string, values map[string]string) bool {
+	switch event {
+	case "pull_request_review_comment", "pull_request_review":
+		return true
+	case "issue_comment":
+		// The sample comment is on an issue, not on a pull request
+		return values["github.event.issue.pull_request"] != ""
+	}
+	return false
+}
+
+// resolvePromptImportPath resolves a runtime import path the way runtime_import.cjs does:
+// .github/ paths are relative to the repository root, other paths to the workflow directory
+func (c *Compiler) resolvePromptImportPath(importPath string) string {
+	if filepath.IsAbs(importPath) {
+		return importPath
+	}
+	markdownPath := filepath.ToSlash(c.markdownPath)
+	if strings.HasPrefix(importPath, ".github/") {
+		if idx := strings.Index(markdownPath, "/.github/"); idx >= 0 {
+			return filepath.Join(filepath.FromSlash(markdownPath[:idx]), importPath)
+		}
+		if c.gitRoot != "" {
+			return filepath.Join(c.gitRoot, importPath)
+		}
+	}
+	return filepath.Join(filepath.Dir(c.markdownPath), importPath)
+}
+
+// findPromptDuplicates returns paragraphs that appear more than once in the included sections
+func findPromptDuplicates(sections []PromptReportSection) []PromptDuplicate {
+	type occurrence struct {
+		excerpt  string
+		tokens   int
+		count    int
+		sections []string
+	}
+	byHash := make(map[[32]byte]*occurrence)
+	var order [][32]byte
+
+	for _, section := range sections {
+		if !section.Included {
+			continue
+		}
+		label := section.Name
+		if section.Source != "built-in" {
+			label = section.Source
+		}
+		for _, paragraph := range strings.Split(section.Content, "\n\n") {
+			normalized := strings.Join(strings.Fields(paragraph), " ")
+			if len(normalized) < minDuplicateParagraphChars {
+				continue
+			}
+			hash := sha256.Sum256([]byte(normalized))
+			occ, ok := byHash[hash]
+			if !ok {
+				occ = &occurrence{excerpt: normalized, tokens: estimatePromptTokens(paragraph)}
+				byHash[hash] = occ
+				order = append(order, hash)
+			}
+			occ.count++
+			if len(occ.sections) == 0 || occ.sections[len(occ.sections)-1] != label {
+				occ.sections = append(occ.sections, label)
+			}
+		}
+	}
+
+	var duplicates []PromptDuplicate
+	for _, hash := range order {
+		occ := byHash[hash]
+		if occ.count < 2 {
+			continue
+		}
+		excerpt := occ.excerpt
+		if len(excerpt) > 60 {
+			excerpt = excerpt[:57] + "..."
+		}
+		duplicates = append(duplicates, PromptDuplicate{
+			Excerpt:      excerpt,
+			Sections:     occ.sections,
+			Occurrences:  occ.count,
+			WastedTokens: occ.tokens * (occ.count - 1),
+		})
+	}
+	return duplicates
+}
+
+// RenderPromptReport renders the section table, duplicates and budget status of a prompt report
+func RenderPromptReport(report *PromptReport) string {
+	var output strings.Builder
+	output.WriteString(console.FormatInfoMessage(fmt.Sprintf("Prompt report for %s (sample event: %s)", report.Workflow, report.Event)) + "\n")
+
+	config := console.TableConfig{
+		Headers:   []string{"Section", "Source", "Chars", "Tokens (est.)"},
+		ShowTotal: true,
+		TotalRow:  []string{"TOTAL", "", console.FormatNumber(report.TotalChars), console.FormatNumber(report.TotalTokens)},
+	}
+	for _, section := range report.Sections {
+		source := section.Source
+		chars, tokens := console.FormatNumber(section.Chars), console.FormatNumber(section.Tokens)
+		switch {
+		case section.Missing:
+			source += " (not found)"
+		case !section.Included:
+			source += fmt.Sprintf(" (skipped for %s)", report.Event)
+			chars, tokens = "-", "-"
+		}
+		config.Rows = append(config.Rows, []string{section.Name, source, chars, tokens})
+	}
+	output.WriteString(console.RenderTable(config))
+
+	for _, duplicate := range report.Duplicates {
+		output.WriteString(console.FormatWarningMessage(fmt.Sprintf("Duplicated content (%d times, ~%d tokens wasted) in %s: %q",
+			duplicate.Occurrences, duplicate.WastedTokens, strings.Join(duplicate.Sections, ", "), duplicate.Excerpt)) + "\n")
+	}
+	if report.OverBudget() {
+		output.WriteString(console.FormatWarningMessage(fmt.Sprintf("Estimated prompt size of %s tokens exceeds the budget of %s tokens",
+			console.FormatNumber(report.TotalTokens), console.FormatNumber(report.Budget))) + "\n")
+	}
+	return output.String()
+}
+
+// PromptReportFilePath returns the path the rendered prompt of a workflow is written to
+func PromptReportFilePath(markdownPath string) string {
+	name := strings.TrimSuffix(filepath.Base(markdownPath), ".md")
+	return filepath.Join(os.TempDir(), "gh-aw", "prompt-report", name+".prompt.md")
+}
+
+// writePromptReport prints the prompt report of a compiled workflow and writes the rendered prompt
+func (c *Compiler) writePromptReport(workflowData *WorkflowData) error {
+	if c.promptReport == nil {
+		return nil
+	}
+
+	report := c.BuildPromptReport(workflowData, *c.promptReport)
+	fmt.Fprint(os.Stderr, RenderPromptReport(report))
+	for range report.Duplicates {
+		c.IncrementWarningCount()
+	}
+	if report.OverBudget() {
+		c.IncrementWarningCount()
+	}
+
+	promptPath := PromptReportFilePath(c.markdownPath)
+	if err := os.MkdirAll(filepath.Dir(promptPath), 0755); err != nil {
+		return formatCompilerError(c.markdownPath, "error", fmt.Sprintf("failed to create prompt report directory: %v", err), err)
+	}
+	if err := os.WriteFile(promptPath, []byte(report.Prompt()), 0644); err != nil {
+		return formatCompilerError(c.markdownPath, "error", fmt.Sprintf("failed to write rendered prompt: %v", err), err)
+	}
+	fmt.Fprintln(os.Stderr, console.FormatInfoMessage("Rendered prompt written to "+promptPath))
+	return nil
+}
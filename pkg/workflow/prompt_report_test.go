@@ -0,0 +1,205 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuiltinPromptFilesInSync(t *testing.T) {
+	entries, err := builtinPromptFiles.ReadDir("data/prompts")
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+
+	for _, entry := range entries {
+		embedded, err := builtinPromptFiles.ReadFile("data/prompts/" + entry.Name())
+		require.NoError(t, err)
+		source, err := os.ReadFile(filepath.Join("..", "..", "actions", "setup", "md", entry.Name()))
+		require.NoError(t, err)
+		assert.Equal(t, string(source), string(embedded), "%s is out of date, run 'make sync-prompts'", entry.Name())
+	}
+}
+
+func TestRenderPromptText(t *testing.T) {
+	values := samplePromptContext("issues")
+
+	text := `Issue #${{ github.event.issue.number }} by __GH_AW_GITHUB_ACTOR__ in __GH_AW_CACHE_DIR__
+
+{{#if github.event.issue.number}}
+Triage the issue.
+{{#if ${{ github.event.pull_request.number }} }}
+Review the pull request.
+{{/if}}
+{{/if}}
+{{#if ${{ github.event.comment.id }} }}
+Reply to the comment.
+{{/if}}`
+	envVars := map[string]string{
+		"GH_AW_GITHUB_ACTOR": "${{ github.actor }}",
+		"GH_AW_CACHE_DIR":    "/tmp/gh-aw/cache-memory/",
+	}
+
+	rendered := renderPromptText(text, envVars, values)
+	assert.Contains(t, rendered, "Issue #123 by octocat in /tmp/gh-aw/cache-memory/")
+	assert.Contains(t, rendered, "Triage the issue.")
+	assert.NotContains(t, rendered, "Review the pull request.")
+	assert.NotContains(t, rendered, "Reply to the comment.")
+	assert.NotContains(t, rendered, "{{")
+}
+
+func TestIsPromptValueTruthy(t *testing.T) {
+	for _, value := range []string{"", " ", "false", "FALSE", "0", "null", "undefined"} {
+		assert.False(t, isPromptValueTruthy(value), "%q should be falsy", value)
+	}
+	for _, value := range []string{"true", "1", "octocat"} {
+		assert.True(t, isPromptValueTruthy(value), "%q should be truthy", value)
+	}
+}
+
+func TestDefaultPromptReportEvent(t *testing.T) {
+	tests := []struct {
+		on       string
+		command  []string
+		expected string
+	}{
+		{on: "on: workflow_dispatch", expected: "workflow_dispatch"},
+		{on: "on:\n  issues:\n    types: [opened]\n  workflow_dispatch:", expected: "issues"},
+		{on: "on:\n  pull_request_review_comment:\n    types: [created]", expected: "pull_request_review_comment"},
+		{on: "on:\n  pull_request:\n    types: [opened]", expected: "pull_request"},
+		{on: "on:\n  schedule:\n    - cron: '0 9 * * 1'", expected: "schedule"},
+		{on: "on:\n  issue_comment:", command: []string{"fix"}, expected: "issue_comment"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, defaultPromptReportEvent(&WorkflowData{On: tt.on, Command: tt.command}), tt.on)
+	}
+}
+
+func TestFindPromptDuplicates(t *testing.T) {
+	paragraph := "Always run the full test suite before opening a pull request and include the output in the description."
+	sections := []PromptReportSection{
+		{Name: "Safe outputs", Source: "built-in", Included: true, Content: "Short paragraph.\n\n" + paragraph},
+		{Name: "Import", Source: "shared/testing.md", Included: true, Content: paragraph},
+		{Name: "Workflow", Source: "workflow.md", Included: true, Content: strings.ReplaceAll(paragraph, " ", "\n")},
+		{Name: "PR context", Source: "pr_context_prompt.md", Included: false, Content: paragraph},
+	}
+
+	duplicates := findPromptDuplicates(sections)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, 3, duplicates[0].Occurrences)
+	assert.Equal(t, []string{"Safe outputs", "shared/testing.md", "workflow.md"}, duplicates[0].Sections)
+	assert.Equal(t, 2*estimatePromptTokens(paragraph), duplicates[0].WastedTokens)
+	assert.True(t, strings.HasSuffix(duplicates[0].Excerpt, "..."))
+}
+
+func TestBuildPromptReport(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "prompt-report")
+	workflowsDir := filepath.Join(tmpDir, ".github", "workflows")
+	require.NoError(t, os.MkdirAll(filepath.Join(workflowsDir, "shared"), 0755))
+
+	guidelines := "Keep replies short and link to the relevant documentation instead of quoting it in full when possible."
+	require.NoError(t, os.WriteFile(filepath.Join(workflowsDir, "shared", "guidelines.md"), []byte("---\n---\n\n"+guidelines+"\n"), 0644))
+
+	workflowPath := filepath.Join(workflowsDir, "triage.md")
+	content := `---
+on:
+  issues:
+    types: [opened]
+permissions:
+  contents: read
+  issues: read
+engine: copilot
+imports:
+  - shared/guidelines.md
+tools:
+  github:
+    toolsets: [issues]
+safe-outputs:
+  add-comment:
+---
+
+# Triage
+
+Triage issue #${{ github.event.issue.number }}: ${{ github.event.issue.title }}
+
+` + guidelines + `
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	workflowData, err := compiler.ParseWorkflowFile(workflowPath)
+	require.NoError(t, err)
+	compiler.markdownPath = workflowPath
+
+	report := compiler.BuildPromptReport(workflowData, PromptReportOptions{Budget: 100})
+	assert.Equal(t, "issues", report.Event)
+	assert.True(t, report.OverBudget())
+
+	names := make([]string, 0, len(report.Sections))
+	for _, section := range report.Sections {
+		names = append(names, section.Name)
+		assert.False(t, section.Missing, "%s should be found", section.Source)
+	}
+	assert.Equal(t, []string{"XPIA protection", "Temporary folder", "Markdown formatting", "Safe outputs", "GitHub context", "Import", "Workflow"}, names)
+
+	workflowSection := report.Sections[len(report.Sections)-1]
+	assert.Contains(t, workflowSection.Content, "Triage issue #123: Show agent log excerpt in failed run summary")
+	assert.Equal(t, estimatePromptTokens(workflowSection.Content), workflowSection.Tokens)
+
+	importSection := report.Sections[len(report.Sections)-2]
+	assert.Equal(t, ".github/workflows/shared/guidelines.md", importSection.Source)
+	assert.Equal(t, guidelines, importSection.Content)
+
+	require.Len(t, report.Duplicates, 1)
+	assert.Equal(t, []string{".github/workflows/shared/guidelines.md", report.Workflow}, report.Duplicates[0].Sections)
+
+	total := 0
+	for _, section := range report.Sections {
+		total += section.Tokens
+	}
+	assert.Equal(t, total, report.TotalTokens)
+	assert.Contains(t, report.Prompt(), "- **issue-number**: #123")
+
+	output := RenderPromptReport(report)
+	assert.Contains(t, output, "sample event: issues")
+	assert.Contains(t, output, "Duplicated content")
+	assert.Contains(t, output, "exceeds the budget")
+}
+
+func TestCompileWorkflowWithPromptReport(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "prompt-report-compile")
+	workflowPath := filepath.Join(tmpDir, "prompt-report-review.md")
+	content := `---
+on:
+  pull_request_review_comment:
+    types: [created]
+permissions:
+  contents: read
+  pull-requests: read
+engine: copilot
+---
+
+# Review
+
+Answer the review comment.
+`
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	compiler := NewCompiler()
+	compiler.SetPromptReport(&PromptReportOptions{Budget: 10})
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	assert.Equal(t, 1, compiler.GetWarningCount(), "exceeding the budget should be a warning")
+
+	promptPath := PromptReportFilePath(workflowPath)
+	t.Cleanup(func() { os.Remove(promptPath) })
+	prompt, err := os.ReadFile(promptPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(prompt), "Answer the review comment.")
+	assert.Contains(t, string(prompt), "<system-security-policy>", "built-in prompt files should be rendered")
+}
@@ -0,0 +1,247 @@
+// This file provides engine session checkpoints and resume for long-running agents.
+//
+// # Resume
+//
+// Agent runs that hit timeout-minutes or the max-turns limit lose their progress. With
+//
+//	resume: true
+//
+// the agent job keeps the engine session in a known directory and uploads it as the
+// agent-checkpoint artifact. A background uploader started before the engine step replaces the
+// artifact every few minutes while the session changes, and the job uploads the session again
+// after the engine stops, whether it succeeded, failed or timed out. The engines write their
+// session files as the conversation progresses, so the checkpoint holds the turns completed
+// before the last upload: all of them when the final upload runs, and those up to the last
+// periodic upload when the runner is lost before it.
+//
+// The compiler adds an aw_resume_run_id workflow_dispatch input. When it is set, the agent job
+// downloads the checkpoint of that run before the engine starts and the engine continues the
+// saved session (claude --continue, codex exec resume --last) with the same tools, and the same
+// cache-memory and repo-memory. `gh aw run <workflow> --resume <run-id>` sets the input.
+//
+// Resume is supported by the engines that can continue a saved session: claude and codex.
+
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/goccy/go-yaml"
+)
+
+var resumeLog = logger.New("workflow:resume")
+
+// resumeEnvVar is set to true by the agent job when a checkpoint was restored, which makes the
+// engine command continue the saved session
+const resumeEnvVar = "GH_AW_RESUME"
+
+// claudeSessionDir is the Claude configuration directory (CLAUDE_CONFIG_DIR) when resume is
+// enabled, so the session transcripts are written where they can be checkpointed
+const claudeSessionDir = "/tmp/gh-aw/agent-session/claude"
+
+// resumeCheckpointIntervalSeconds is how often the background uploader replaces the checkpoint
+// while the engine runs
+const resumeCheckpointIntervalSeconds = 300
+
+// resumeEngines maps the engines that support resume to the directory holding their sessions
+var resumeEngines = map[string]string{
+	"claude": claudeSessionDir,
+	"codex":  "/tmp/gh-aw/mcp-config/sessions", // $CODEX_HOME/sessions
+}
+
+// extractResumeConfig extracts and validates the resume option, and adds the workflow_dispatch
+// input selecting the run to resume from. It must run after the on section and phases are processed.
+func (c *Compiler) extractResumeConfig(frontmatter map[string]any, data *WorkflowData) error {
+	value, exists := frontmatter["resume"]
+	if !exists || value == nil {
+		return nil
+	}
+	enabled, ok := value.(bool)
+	if !ok {
+		return fmt.Errorf("'resume' must be a boolean. See: %s", constants.DocsResumeURL)
+	}
+	if !enabled {
+		return nil
+	}
+
+	if _, supported := resumeEngines[data.AI]; !supported {
+		return fmt.Errorf("resume is not supported by the %s engine: it requires an engine that can continue a saved session (claude, codex).\n\nSee: %s", data.AI, constants.DocsResumeURL)
+	}
+	if len(data.Phases) > 0 {
+		return fmt.Errorf("resume cannot be combined with phases: a checkpoint holds the session of a single engine run.\n\nSee: %s", constants.DocsResumeURL)
+	}
+	if hasEngineFallbacks(data) {
+		return fmt.Errorf("resume cannot be combined with engine fallbacks: a checkpoint holds the session of a single engine.\n\nSee: %s", constants.DocsResumeURL)
+	}
+
+	permissions := NewPermissionsParser(data.Permissions).ToPermissions()
+	if level, ok := permissions.Get(PermissionActions); !ok || level == PermissionNone {
+		return fmt.Errorf("resume requires the actions: read permission to download the checkpoint of a previous run.\n\nSuggested fix: Add the following to your workflow frontmatter:\npermissions:\n  actions: read\n\nSee: %s", constants.DocsResumeURL)
+	}
+
+	data.On = injectResumeInput(data.On)
+	data.Resume = true
+	resumeLog.Printf("Enabled resume for %s engine", data.AI)
+	return nil
+}
+
+// injectResumeInput adds the resume input to the workflow_dispatch trigger of the on section,
+// adding the trigger when the workflow does not have it
+func injectResumeInput(onSection string) string {
+	var onData map[string]any
+	if err := yaml.Unmarshal([]byte(onSection), &onData); err != nil {
+		resumeLog.Printf("Failed to parse on section, resume input not added: %v", err)
+		return onSection
+	}
+
+	triggers := make(map[string]any)
+	switch on := onData["on"].(type) {
+	case map[string]any:
+		triggers = on
+	case string:
+		triggers[on] = nil
+	case []any:
+		for _, event := range on {
+			if name, ok := event.(string); ok {
+				triggers[name] = nil
+			}
+		}
+	}
+
+	dispatch, _ := triggers["workflow_dispatch"].(map[string]any)
+	if dispatch == nil {
+		dispatch = make(map[string]any)
+	}
+	inputs, _ := dispatch["inputs"].(map[string]any)
+	if inputs == nil {
+		inputs = make(map[string]any)
+	}
+	inputs[constants.ResumeRunIDInputName] = map[string]any{
+		"description": "ID of a previous run to resume from its last agent checkpoint",
+		"required":    false,
+		"type":        "string",
+	}
+	dispatch["inputs"] = inputs
+	triggers["workflow_dispatch"] = dispatch
+
+	yamlBytes, err := yaml.Marshal(map[string]any{"on": triggers})
+	if err != nil {
+		resumeLog.Printf("Failed to marshal on section, resume input not added: %v", err)
+		return onSection
+	}
+	return strings.TrimSuffix(string(yamlBytes), "\n")
+}
+
+// resumeSessionDir returns the directory holding the engine sessions when resume is enabled
+func resumeSessionDir(data *WorkflowData) string {
+	if !data.Resume {
+		return ""
+	}
+	return resumeEngines[data.AI]
+}
+
+// generateResumeCheckpointRestore generates the steps downloading the checkpoint of the run
+// selected by the resume input, and enabling the engine resume flag when a session was restored
+func (c *Compiler) generateResumeCheckpointRestore(yaml *strings.Builder, data *WorkflowData) {
+	sessionDir := resumeSessionDir(data)
+	if sessionDir == "" {
+		return
+	}
+
+	resumeLog.Print("Generating agent checkpoint restore steps")
+	condition := fmt.Sprintf("inputs.%s != ''", constants.ResumeRunIDInputName)
+
+	yaml.WriteString("      - name: Download agent checkpoint\n")
+	fmt.Fprintf(yaml, "        if: %s\n", condition)
+	yaml.WriteString("        continue-on-error: true\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/download-artifact"))
+	yaml.WriteString("        with:\n")
+	fmt.Fprintf(yaml, "          name: %s\n", constants.AgentCheckpointArtifactName)
+	fmt.Fprintf(yaml, "          path: %s\n", sessionDir)
+	fmt.Fprintf(yaml, "          run-id: ${{ inputs.%s }}\n", constants.ResumeRunIDInputName)
+	yaml.WriteString("          github-token: ${{ github.token }}\n")
+
+	yaml.WriteString("      - name: Restore agent session\n")
+	fmt.Fprintf(yaml, "        if: %s\n", condition)
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_RESUME_RUN_ID: ${{ inputs.%s }}\n", constants.ResumeRunIDInputName)
+	fmt.Fprintf(yaml, "          GH_AW_SESSION_DIR: %s\n", sessionDir)
+	yaml.WriteString("        run: |\n")
+	yaml.WriteString("          if [ -n \"$(ls -A \"$GH_AW_SESSION_DIR\" 2>/dev/null)\" ]; then\n")
+	yaml.WriteString("            echo \"Resuming the agent session checkpointed by run $GH_AW_RESUME_RUN_ID\"\n")
+	fmt.Fprintf(yaml, "            echo \"%s=true\" >> \"$GITHUB_ENV\"\n", resumeEnvVar)
+	yaml.WriteString("          else\n")
+	yaml.WriteString("            echo \"::warning::No agent checkpoint found in run $GH_AW_RESUME_RUN_ID, starting a new session\"\n")
+	yaml.WriteString("          fi\n")
+}
+
+// generateResumeCheckpointUploader generates the step starting the background uploader, which
+// uploads the engine session while the engine runs so that a run whose runner is lost before the
+// final upload still has a checkpoint. The uploader redacts the secrets of the engine from the
+// uploaded copy, since the redaction step of the job only runs after the engine.
+func (c *Compiler) generateResumeCheckpointUploader(yaml *strings.Builder, data *WorkflowData, engine CodingAgentEngine) {
+	sessionDir := resumeSessionDir(data)
+	if sessionDir == "" {
+		return
+	}
+
+	resumeLog.Print("Generating agent checkpoint uploader step")
+	secretNames := slices.Sorted(slices.Values(engine.GetRequiredSecretNames(data)))
+	secretNames = slices.Compact(secretNames)
+
+	yaml.WriteString("      - name: Start agent checkpoint uploader\n")
+	yaml.WriteString("        continue-on-error: true\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/github-script"))
+	yaml.WriteString("        env:\n")
+	fmt.Fprintf(yaml, "          GH_AW_SESSION_DIR: %s\n", sessionDir)
+	fmt.Fprintf(yaml, "          GH_AW_CHECKPOINT_ARTIFACT: %s\n", constants.AgentCheckpointArtifactName)
+	fmt.Fprintf(yaml, "          GH_AW_CHECKPOINT_INTERVAL: \"%d\"\n", resumeCheckpointIntervalSeconds)
+	for _, name := range secretNames {
+		fmt.Fprintf(yaml, "          SECRET_%s: ${{ secrets.%s }}\n", name, name)
+	}
+	yaml.WriteString("        with:\n")
+	yaml.WriteString("          script: |\n")
+	yaml.WriteString(generateGitHubScriptWithRequire("agent_checkpoint_uploader.cjs"))
+}
+
+// generateResumeCheckpointUpload generates the steps stopping the background uploader and
+// uploading the engine session as the checkpoint of the run. They always run so the progress of
+// a timed out or failed run is kept, and replace the checkpoint uploaded in the background.
+func (c *Compiler) generateResumeCheckpointUpload(yaml *strings.Builder, data *WorkflowData) {
+	sessionDir := resumeSessionDir(data)
+	if sessionDir == "" {
+		return
+	}
+
+	resumeLog.Print("Generating agent checkpoint upload step")
+	yaml.WriteString("      - name: Stop agent checkpoint uploader\n")
+	yaml.WriteString("        if: always()\n")
+	yaml.WriteString("        continue-on-error: true\n")
+	yaml.WriteString("        run: |\n")
+	yaml.WriteString("          if [ -f /tmp/gh-aw/agent-checkpoint-uploader.pid ]; then\n")
+	yaml.WriteString("            kill \"$(cat /tmp/gh-aw/agent-checkpoint-uploader.pid)\" 2>/dev/null || true\n")
+	yaml.WriteString("          fi\n")
+	yaml.WriteString("      - name: Upload agent checkpoint\n")
+	yaml.WriteString("        if: always()\n")
+	yaml.WriteString("        continue-on-error: true\n")
+	fmt.Fprintf(yaml, "        uses: %s\n", GetActionPin("actions/upload-artifact"))
+	yaml.WriteString("        with:\n")
+	fmt.Fprintf(yaml, "          name: %s\n", constants.AgentCheckpointArtifactName)
+	fmt.Fprintf(yaml, "          path: %s\n", sessionDir)
+	yaml.WriteString("          include-hidden-files: true\n")
+	yaml.WriteString("          if-no-files-found: ignore\n")
+	yaml.WriteString("          overwrite: true\n")
+}
+
+// resumeEngineArg returns the shell fragment adding the engine resume arguments when the agent
+// job restored a checkpoint, or an empty string when resume is not enabled
+func resumeEngineArg(data *WorkflowData, args string) string {
+	if !data.Resume {
+		return ""
+	}
+	return fmt.Sprintf("${%s:+%s}", resumeEnvVar, args)
+}
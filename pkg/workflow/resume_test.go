@@ -0,0 +1,149 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjectResumeInput(t *testing.T) {
+	tests := []struct {
+		name     string
+		on       string
+		triggers []string
+		inputs   int
+	}{
+		{name: "map without workflow_dispatch", on: "on:\n  issues:\n    types: [labeled]", triggers: []string{"issues", "workflow_dispatch"}, inputs: 1},
+		{name: "workflow_dispatch with inputs", on: "on:\n  workflow_dispatch:\n    inputs:\n      scope:\n        type: string", triggers: []string{"workflow_dispatch"}, inputs: 2},
+		{name: "string trigger", on: "on: push", triggers: []string{"push", "workflow_dispatch"}, inputs: 1},
+		{name: "list of triggers", on: "on: [push, workflow_dispatch]", triggers: []string{"push", "workflow_dispatch"}, inputs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var parsed map[string]any
+			require.NoError(t, yaml.Unmarshal([]byte(injectResumeInput(tt.on)), &parsed))
+
+			triggers, ok := parsed["on"].(map[string]any)
+			require.True(t, ok, "on should be a map")
+			dispatch, ok := triggers["workflow_dispatch"].(map[string]any)
+			require.True(t, ok, "workflow_dispatch should be added")
+			inputs, ok := dispatch["inputs"].(map[string]any)
+			require.True(t, ok, "workflow_dispatch should have inputs")
+			assert.Contains(t, inputs, "aw_resume_run_id")
+			assert.Len(t, inputs, tt.inputs, "existing inputs should be kept")
+			for _, trigger := range tt.triggers {
+				assert.Contains(t, triggers, trigger)
+			}
+			assert.Len(t, triggers, len(tt.triggers))
+		})
+	}
+}
+
+func TestCompileWorkflowWithResume(t *testing.T) {
+	tests := []struct {
+		engine     string
+		sessionDir string
+		resumeArg  string
+		secret     string
+	}{
+		{engine: "claude", sessionDir: "/tmp/gh-aw/agent-session/claude", resumeArg: "${GH_AW_RESUME:+ --continue}", secret: "ANTHROPIC_API_KEY"},
+		{engine: "codex", sessionDir: "/tmp/gh-aw/mcp-config/sessions", resumeArg: `${GH_AW_RESUME:+resume --last }"$INSTRUCTION"`, secret: "OPENAI_API_KEY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.engine, func(t *testing.T) {
+			lockContent, err := compileTestWorkflow(t, `on:
+  issues:
+    types: [labeled]
+permissions:
+  contents: read
+  actions: read
+engine: `+tt.engine+`
+resume: true
+`)
+			require.NoError(t, err)
+
+			assert.Contains(t, lockContent, "aw_resume_run_id:")
+			assert.Contains(t, lockContent, "- name: Download agent checkpoint")
+			assert.Contains(t, lockContent, "run-id: ${{ inputs.aw_resume_run_id }}")
+			assert.Contains(t, lockContent, "path: "+tt.sessionDir)
+			assert.Contains(t, lockContent, `echo "GH_AW_RESUME=true" >> "$GITHUB_ENV"`)
+			assert.Contains(t, lockContent, tt.resumeArg)
+
+			restore := strings.Index(lockContent, "- name: Restore agent session")
+			execute := strings.Index(lockContent, tt.resumeArg)
+			upload := strings.Index(lockContent, "- name: Upload agent checkpoint")
+			require.NotEqual(t, -1, upload)
+			assert.Less(t, restore, execute, "the session should be restored before the engine runs")
+			assert.Less(t, execute, upload, "the checkpoint should be uploaded after the engine runs")
+			assert.Contains(t, lockContent[upload:], "if: always()")
+			assert.Contains(t, lockContent[upload:], "overwrite: true", "the final upload should replace the background checkpoint")
+
+			uploader := strings.Index(lockContent, "- name: Start agent checkpoint uploader")
+			stop := strings.Index(lockContent, "- name: Stop agent checkpoint uploader")
+			require.NotEqual(t, -1, uploader)
+			require.NotEqual(t, -1, stop)
+			assert.Less(t, restore, uploader, "the uploader should start after the session is restored")
+			assert.Less(t, uploader, execute, "the uploader should run while the engine runs")
+			assert.Less(t, execute, stop, "the uploader should be stopped after the engine runs")
+			assert.Less(t, stop, upload, "the uploader should be stopped before the final upload")
+			assert.Contains(t, lockContent[uploader:execute], "require('/opt/gh-aw/actions/agent_checkpoint_uploader.cjs')")
+			assert.Contains(t, lockContent[uploader:execute], "GH_AW_SESSION_DIR: "+tt.sessionDir)
+			assert.Contains(t, lockContent[uploader:execute], `GH_AW_CHECKPOINT_INTERVAL: "300"`)
+			assert.Contains(t, lockContent[uploader:execute], "SECRET_"+tt.secret+": ${{ secrets."+tt.secret+" }}", "the uploader should redact the engine secrets")
+
+			if tt.engine == "claude" {
+				assert.Contains(t, lockContent, "CLAUDE_CONFIG_DIR: /tmp/gh-aw/agent-session/claude")
+			}
+		})
+	}
+}
+
+func TestCompileWorkflowWithoutResume(t *testing.T) {
+	lockContent, err := compileTestWorkflow(t, `on:
+  workflow_dispatch:
+permissions:
+  contents: read
+engine: claude
+`)
+	require.NoError(t, err)
+	assert.NotContains(t, lockContent, "aw_resume_run_id")
+	assert.NotContains(t, lockContent, "agent-checkpoint")
+	assert.NotContains(t, lockContent, "GH_AW_RESUME")
+	assert.NotContains(t, lockContent, "CLAUDE_CONFIG_DIR")
+}
+
+func TestResumeValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		frontmatter string
+		errContains string
+	}{
+		{
+			name:        "unsupported engine",
+			frontmatter: "on: workflow_dispatch\npermissions:\n  actions: read\nengine: copilot\nresume: true\n",
+			errContains: "resume is not supported by the copilot engine",
+		},
+		{
+			name:        "missing actions permission",
+			frontmatter: "on: workflow_dispatch\npermissions:\n  contents: read\nengine: claude\nresume: true\n",
+			errContains: "actions: read",
+		},
+		{
+			name:        "engine fallbacks",
+			frontmatter: "on: workflow_dispatch\npermissions:\n  actions: read\nengine:\n  id: claude\n  fallback: [codex]\nresume: true\n",
+			errContains: "engine fallbacks",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileTestWorkflow(t, tt.frontmatter)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
@@ -94,9 +94,11 @@ func (c *Compiler) validateExpressionSizes(yamlContent string) error {
 	return nil
 }
 
-// validateContainerImages validates that container images specified in MCP configs exist and are accessible
+// validateContainerImages validates that container images specified in MCP configs and
+// safe-inputs container tools exist and are accessible
 func (c *Compiler) validateContainerImages(workflowData *WorkflowData) error {
-	if workflowData.Tools == nil {
+	safeInputImages := collectSafeInputsContainerImages(workflowData.SafeInputs)
+	if workflowData.Tools == nil && len(safeInputImages) == 0 {
 		runtimeValidationLog.Print("No tools configured, skipping container validation")
 		return nil
 	}
@@ -137,6 +139,14 @@ func (c *Compiler) validateContainerImages(workflowData *WorkflowData) error {
 		}
 	}
 
+	for _, image := range safeInputImages {
+		if err := validateDockerImage(image, c.verbose); err != nil {
+			errors = append(errors, fmt.Sprintf("safe-inputs container '%s': %v", image, err))
+		} else if c.verbose {
+			fmt.Fprintln(os.Stderr, console.FormatInfoMessage(fmt.Sprintf("✓ Container image validated: %s", image)))
+		}
+	}
+
 	if len(errors) > 0 {
 		return NewValidationError(
 			"container.images",
@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeInputsContainerLog = logger.New("workflow:safe_inputs_container")
+
+// safeInputContainerDigestPattern matches container images pinned by digest (image@sha256:<digest>)
+var safeInputContainerDigestPattern = regexp.MustCompile(`^[^\s@]+@sha256:[a-f0-9]{64}$`)
+
+// SafeInputContainerMount is a host path mounted into a container tool
+type SafeInputContainerMount struct {
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	ReadOnly bool   `json:"readOnly"`
+}
+
+// SafeInputsContainerJSON describes how the safe-inputs MCP server runs a container tool
+type SafeInputsContainerJSON struct {
+	Image      string                    `json:"image"`
+	Entrypoint string                    `json:"entrypoint,omitempty"`
+	Args       []string                  `json:"args,omitempty"`
+	Network    bool                      `json:"network,omitempty"`
+	Mounts     []SafeInputContainerMount `json:"mounts,omitempty"`
+}
+
+// parseSafeInputContainerMount parses a mount in source:target[:ro|rw] format.
+// Mounts are read-only unless rw is specified.
+func parseSafeInputContainerMount(spec string) (SafeInputContainerMount, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return SafeInputContainerMount{}, fmt.Errorf("mount '%s' must use source:target[:ro|rw] format", spec)
+	}
+	mount := SafeInputContainerMount{Source: parts[0], Target: parts[1], ReadOnly: true}
+	if !strings.HasPrefix(mount.Target, "/") {
+		return SafeInputContainerMount{}, fmt.Errorf("mount '%s' must use an absolute container path", spec)
+	}
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "ro":
+		case "rw":
+			mount.ReadOnly = false
+		default:
+			return SafeInputContainerMount{}, fmt.Errorf("mount '%s' has invalid mode '%s' (must be ro or rw)", spec, parts[2])
+		}
+	}
+	return mount, nil
+}
+
+// buildSafeInputContainerJSON returns the container spec of a container tool for tools.json
+func buildSafeInputContainerJSON(toolConfig *SafeInputToolConfig) *SafeInputsContainerJSON {
+	if toolConfig.Container == "" {
+		return nil
+	}
+	container := &SafeInputsContainerJSON{
+		Image:      toolConfig.Container,
+		Entrypoint: toolConfig.Entrypoint,
+		Args:       toolConfig.Args,
+		Network:    toolConfig.Network,
+	}
+	for _, spec := range toolConfig.Mounts {
+		// Invalid mounts are reported by validateSafeInputs
+		if mount, err := parseSafeInputContainerMount(spec); err == nil {
+			container.Mounts = append(container.Mounts, mount)
+		}
+	}
+	return container
+}
+
+// BuildSafeInputContainerRunArgs returns the docker arguments used to run a container tool once.
+// Relative mount sources are resolved against workspace. The arguments match the ones built by
+// the container handler of the safe-inputs MCP server (mcp_handler_container.cjs): the container
+// is removed after the call, reads the inputs from stdin, drops all capabilities and has no
+// network unless the tool allows it. Tool environment variables are forwarded by name.
+func BuildSafeInputContainerRunArgs(toolConfig *SafeInputToolConfig, workspace string, name string) []string {
+	container := buildSafeInputContainerJSON(toolConfig)
+	if container == nil {
+		return nil
+	}
+
+	args := []string{"run", "--rm", "-i", "--name", name}
+	if !container.Network {
+		args = append(args, "--network", "none")
+	}
+	args = append(args, "--cap-drop", "ALL", "--security-opt", "no-new-privileges")
+
+	for _, mount := range container.Mounts {
+		source := mount.Source
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(workspace, source)
+		}
+		volume := source + ":" + mount.Target
+		if mount.ReadOnly {
+			volume += ":ro"
+		}
+		args = append(args, "-v", volume)
+	}
+
+	envNames := make([]string, 0, len(toolConfig.Env))
+	for envName := range toolConfig.Env {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	for _, envName := range envNames {
+		args = append(args, "-e", envName)
+	}
+
+	if container.Entrypoint != "" {
+		args = append(args, "--entrypoint", container.Entrypoint)
+	}
+	args = append(args, container.Image)
+	args = append(args, container.Args...)
+
+	safeInputsContainerLog.Printf("Built docker run args for tool %s: %d args", toolConfig.Name, len(args))
+	return args
+}
+
+// collectSafeInputsContainerImages returns the sorted, de-duplicated images of container tools
+func collectSafeInputsContainerImages(safeInputs *SafeInputsConfig) []string {
+	if !HasSafeInputs(safeInputs) {
+		return nil
+	}
+	imageSet := make(map[string]bool)
+	var images []string
+	for _, tool := range safeInputs.Tools {
+		if tool.Container != "" && !imageSet[tool.Container] {
+			imageSet[tool.Container] = true
+			images = append(images, tool.Container)
+		}
+	}
+	sort.Strings(images)
+	return images
+}
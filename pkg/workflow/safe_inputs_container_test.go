@@ -0,0 +1,176 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSafeInputImage = "ghcr.io/example/lint@sha256:" + strings.Repeat("a", 64)
+
+func TestParseSafeInputContainerMount(t *testing.T) {
+	tests := []struct {
+		spec      string
+		want      SafeInputContainerMount
+		wantError string
+	}{
+		{spec: "data:/data", want: SafeInputContainerMount{Source: "data", Target: "/data", ReadOnly: true}},
+		{spec: "/tmp/out:/out:rw", want: SafeInputContainerMount{Source: "/tmp/out", Target: "/out"}},
+		{spec: "data:/data:ro", want: SafeInputContainerMount{Source: "data", Target: "/data", ReadOnly: true}},
+		{spec: "data", wantError: "must use source:target[:ro|rw] format"},
+		{spec: "data:relative", wantError: "must use an absolute container path"},
+		{spec: "data:/data:exec", wantError: "invalid mode 'exec'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			mount, err := parseSafeInputContainerMount(tt.spec)
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, mount)
+		})
+	}
+}
+
+func TestBuildSafeInputContainerRunArgs(t *testing.T) {
+	tool := &SafeInputToolConfig{Name: "lint", Container: testSafeInputImage}
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--name", "lint-1", "--network", "none",
+		"--cap-drop", "ALL", "--security-opt", "no-new-privileges", testSafeInputImage,
+	}, BuildSafeInputContainerRunArgs(tool, "/workspace", "lint-1"))
+
+	tool = &SafeInputToolConfig{
+		Name:       "lint",
+		Container:  testSafeInputImage,
+		Entrypoint: "/bin/lint",
+		Args:       []string{"--json"},
+		Network:    true,
+		Mounts:     []string{"src:/src", "/tmp/out:/out:rw"},
+		Env:        map[string]string{"TOKEN": "${{ secrets.TOKEN }}", "API_URL": "${{ vars.API_URL }}"},
+	}
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--name", "lint-2",
+		"--cap-drop", "ALL", "--security-opt", "no-new-privileges",
+		"-v", "/workspace/src:/src:ro", "-v", "/tmp/out:/out",
+		"-e", "API_URL", "-e", "TOKEN",
+		"--entrypoint", "/bin/lint", testSafeInputImage, "--json",
+	}, BuildSafeInputContainerRunArgs(tool, "/workspace", "lint-2"))
+
+	assert.Nil(t, BuildSafeInputContainerRunArgs(&SafeInputToolConfig{Name: "script", Script: "return 1;"}, "/workspace", "x"))
+}
+
+func TestValidateSafeInputContainer(t *testing.T) {
+	tests := []struct {
+		name      string
+		tool      map[string]any
+		wantError string
+	}{
+		{
+			name: "pinned image with mounts",
+			tool: map[string]any{"container": testSafeInputImage, "mounts": []any{"src:/src"}, "args": []any{"--json"}},
+		},
+		{
+			name:      "image pinned by tag",
+			tool:      map[string]any{"container": "ghcr.io/example/lint:v1"},
+			wantError: "container 'ghcr.io/example/lint:v1' must be pinned by digest",
+		},
+		{
+			name:      "invalid mount",
+			tool:      map[string]any{"container": testSafeInputImage, "mounts": []any{"src:/src:exec"}},
+			wantError: "mount 'src:/src:exec' has invalid mode 'exec'",
+		},
+		{
+			name:      "container options without container",
+			tool:      map[string]any{"script": "return 1;", "network": true},
+			wantError: "entrypoint, args, network and mounts only apply to container tools",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.tool["description"] = "Lint"
+			config := ParseSafeInputs(map[string]any{"safe-inputs": map[string]any{"lint": tt.tool}})
+
+			err := validateSafeInputs(config)
+			if tt.wantError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "safe-inputs tool 'lint': "+tt.wantError)
+		})
+	}
+}
+
+func TestSafeInputsContainerToolsConfig(t *testing.T) {
+	config := ParseSafeInputs(map[string]any{
+		"safe-inputs": map[string]any{
+			"lint": map[string]any{
+				"description": "Lint files",
+				"container":   testSafeInputImage,
+				"entrypoint":  "/bin/lint",
+				"mounts":      []any{"src:/src"},
+				"env":         map[string]any{"TOKEN": "${{ secrets.TOKEN }}"},
+			},
+		},
+	})
+	require.NotNil(t, config)
+
+	var toolsJSON SafeInputsConfigJSON
+	require.NoError(t, json.Unmarshal([]byte(generateSafeInputsToolsConfig(config)), &toolsJSON))
+	require.Len(t, toolsJSON.Tools, 1)
+	tool := toolsJSON.Tools[0]
+
+	assert.Empty(t, tool.Handler, "container tools should not have a handler file")
+	assert.Equal(t, &SafeInputsContainerJSON{
+		Image:      testSafeInputImage,
+		Entrypoint: "/bin/lint",
+		Mounts:     []SafeInputContainerMount{{Source: "src", Target: "/src", ReadOnly: true}},
+	}, tool.Container)
+
+	workflowData := &WorkflowData{SafeInputs: config}
+	assert.Contains(t, collectDockerImages(nil, workflowData, ActionModeDev), testSafeInputImage)
+}
+
+func TestCompileWorkflowWithSafeInputContainer(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "safe-inputs-container-test")
+
+	content := `---
+on: workflow_dispatch
+engine: copilot
+safe-inputs:
+  lint:
+    description: Lint the repository
+    container: ` + testSafeInputImage + `
+    args: ["--format", "json"]
+    mounts:
+      - ".:/src"
+---
+
+# Lint
+`
+	workflowPath := filepath.Join(tmpDir, "lint.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+	require.NoError(t, NewCompiler().CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	lock := string(lockContent)
+	assert.Contains(t, lock, `"image": "`+testSafeInputImage+`"`)
+	assert.Contains(t, lock, "download_docker_images.sh")
+	assert.NotContains(t, lock, "lint.cjs")
+}
@@ -15,12 +15,14 @@ var safeInputsGeneratorLog = logger.New("workflow:safe_inputs_generator")
 
 // SafeInputsToolJSON represents a tool configuration for the tools.json file
 type SafeInputsToolJSON struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	InputSchema map[string]any    `json:"inputSchema"`
-	Handler     string            `json:"handler,omitempty"`
-	Env         map[string]string `json:"env,omitempty"`
-	Timeout     int               `json:"timeout,omitempty"`
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description"`
+	InputSchema  map[string]any           `json:"inputSchema"`
+	OutputSchema map[string]any           `json:"outputSchema,omitempty"`
+	Handler      string                   `json:"handler,omitempty"`
+	Container    *SafeInputsContainerJSON `json:"container,omitempty"`
+	Env          map[string]string        `json:"env,omitempty"`
+	Timeout      int                      `json:"timeout,omitempty"`
 }
 
 // SafeInputsConfigJSON represents the tools.json configuration file structure
@@ -70,13 +72,8 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 
 		for _, paramName := range inputNames {
 			param := toolConfig.Inputs[paramName]
-			propDef := map[string]any{
-				"type":        param.Type,
-				"description": param.Description,
-			}
-			if param.Default != nil {
-				propDef["default"] = param.Default
-			}
+			propDef := param.JSONSchema()
+			propDef["description"] = param.Description
 			props[paramName] = propDef
 			if param.Required {
 				required = append(required, paramName)
@@ -89,6 +86,7 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 		}
 
 		// Determine handler path based on script type
+		// Container tools have no handler file; the server runs their image directly
 		var handler string
 		if toolConfig.Script != "" {
 			handler = toolName + ".cjs"
@@ -121,12 +119,14 @@ func generateSafeInputsToolsConfig(safeInputs *SafeInputsConfig) string {
 		}
 
 		config.Tools = append(config.Tools, SafeInputsToolJSON{
-			Name:        toolName,
-			Description: toolConfig.Description,
-			InputSchema: inputSchema,
-			Handler:     handler,
-			Env:         envRefs,
-			Timeout:     toolConfig.Timeout,
+			Name:         toolName,
+			Description:  toolConfig.Description,
+			InputSchema:  inputSchema,
+			OutputSchema: toolConfig.Output,
+			Handler:      handler,
+			Container:    buildSafeInputContainerJSON(toolConfig),
+			Env:          envRefs,
+			Timeout:      toolConfig.Timeout,
 		})
 	}
 
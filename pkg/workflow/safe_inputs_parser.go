@@ -30,20 +30,36 @@ type SafeInputToolConfig struct {
 	Name        string                     // Tool name (key from the config)
 	Description string                     // Required: tool description
 	Inputs      map[string]*SafeInputParam // Optional: input parameters
+	Output      map[string]any             // Optional: JSON Schema of the structured tool result
 	Script      string                     // JavaScript implementation (mutually exclusive with Run, Py, and Go)
 	Run         string                     // Shell script implementation (mutually exclusive with Script, Py, and Go)
 	Py          string                     // Python script implementation (mutually exclusive with Script, Run, and Go)
 	Go          string                     // Go script implementation (mutually exclusive with Script, Run, and Py)
+	Container   string                     // Container image pinned by digest (mutually exclusive with Script, Run, Py, and Go)
+	Entrypoint  string                     // Container entrypoint override
+	Args        []string                   // Arguments passed to the container entrypoint
+	Network     bool                       // Whether the container has network access (default: no network)
+	Mounts      []string                   // Container mounts in source:target[:ro|rw] format
 	Env         map[string]string          // Environment variables (typically for secrets)
 	Timeout     int                        // Timeout in seconds for tool execution (default: 60)
 }
 
-// SafeInputParam holds the configuration for a tool input parameter
+// SafeInputParam holds the configuration for a tool input parameter.
+// Besides the type, parameters may carry JSON Schema constraints that are
+// published in the tool input schema and enforced before the tool runs.
 type SafeInputParam struct {
-	Type        string // JSON schema type (string, number, boolean, array, object)
-	Description string // Description of the parameter
-	Required    bool   // Whether the parameter is required
-	Default     any    // Default value
+	Type        string                     // JSON schema type (string, number, integer, boolean, array, object)
+	Description string                     // Description of the parameter
+	Required    bool                       // Whether the parameter is required
+	Default     any                        // Default value
+	Enum        []any                      // Allowed values
+	Pattern     string                     // Regular expression a string value must match
+	Minimum     *float64                   // Minimum numeric value (inclusive)
+	Maximum     *float64                   // Maximum numeric value (inclusive)
+	MinLength   *int                       // Minimum string length
+	MaxLength   *int                       // Maximum string length
+	Items       *SafeInputParam            // Schema of array items
+	Properties  map[string]*SafeInputParam // Schemas of object properties
 }
 
 // SafeInputsMode constants define the available transport modes
@@ -95,115 +111,121 @@ func parseSafeInputsMap(safeInputsMap map[string]any) (*SafeInputsConfig, bool)
 			continue
 		}
 
-		toolConfig := &SafeInputToolConfig{
-			Name:    toolName,
-			Inputs:  make(map[string]*SafeInputParam),
-			Env:     make(map[string]string),
-			Timeout: 60, // Default timeout: 60 seconds
+		config.Tools[toolName] = parseSafeInputTool(toolName, toolMap)
+	}
+
+	return config, len(config.Tools) > 0
+}
+
+// parseSafeInputTool parses a single safe-input tool definition.
+// This is shared by frontmatter parsing and the merging of imported tools.
+func parseSafeInputTool(toolName string, toolMap map[string]any) *SafeInputToolConfig {
+	toolConfig := &SafeInputToolConfig{
+		Name:    toolName,
+		Inputs:  make(map[string]*SafeInputParam),
+		Env:     make(map[string]string),
+		Timeout: 60, // Default timeout: 60 seconds
+	}
+
+	// Parse description (required)
+	if desc, exists := toolMap["description"]; exists {
+		if descStr, ok := desc.(string); ok {
+			toolConfig.Description = descStr
 		}
+	}
 
-		// Parse description (required)
-		if desc, exists := toolMap["description"]; exists {
-			if descStr, ok := desc.(string); ok {
-				toolConfig.Description = descStr
-			}
+	// Parse inputs (optional)
+	if inputs, exists := toolMap["inputs"]; exists {
+		toolConfig.Inputs = parseSafeInputParams(inputs)
+	}
+
+	// Parse output-schema (optional)
+	if output, exists := toolMap["output-schema"]; exists {
+		if outputMap, ok := output.(map[string]any); ok {
+			toolConfig.Output = outputMap
 		}
+	}
 
-		// Parse inputs (optional)
-		if inputs, exists := toolMap["inputs"]; exists {
-			if inputsMap, ok := inputs.(map[string]any); ok {
-				for paramName, paramValue := range inputsMap {
-					if paramMap, ok := paramValue.(map[string]any); ok {
-						param := &SafeInputParam{
-							Type: "string", // default type
-						}
-
-						if t, exists := paramMap["type"]; exists {
-							if tStr, ok := t.(string); ok {
-								param.Type = tStr
-							}
-						}
-
-						if desc, exists := paramMap["description"]; exists {
-							if descStr, ok := desc.(string); ok {
-								param.Description = descStr
-							}
-						}
-
-						if req, exists := paramMap["required"]; exists {
-							if reqBool, ok := req.(bool); ok {
-								param.Required = reqBool
-							}
-						}
-
-						if def, exists := paramMap["default"]; exists {
-							param.Default = def
-						}
-
-						toolConfig.Inputs[paramName] = param
-					}
-				}
-			}
+	// Parse script (JavaScript implementation)
+	if script, exists := toolMap["script"]; exists {
+		if scriptStr, ok := script.(string); ok {
+			toolConfig.Script = scriptStr
 		}
+	}
 
-		// Parse script (JavaScript implementation)
-		if script, exists := toolMap["script"]; exists {
-			if scriptStr, ok := script.(string); ok {
-				toolConfig.Script = scriptStr
-			}
+	// Parse run (shell script implementation)
+	if run, exists := toolMap["run"]; exists {
+		if runStr, ok := run.(string); ok {
+			toolConfig.Run = runStr
 		}
+	}
 
-		// Parse run (shell script implementation)
-		if run, exists := toolMap["run"]; exists {
-			if runStr, ok := run.(string); ok {
-				toolConfig.Run = runStr
-			}
+	// Parse py (Python script implementation)
+	if py, exists := toolMap["py"]; exists {
+		if pyStr, ok := py.(string); ok {
+			toolConfig.Py = pyStr
 		}
+	}
 
-		// Parse py (Python script implementation)
-		if py, exists := toolMap["py"]; exists {
-			if pyStr, ok := py.(string); ok {
-				toolConfig.Py = pyStr
-			}
+	// Parse go (Go script implementation)
+	if goScript, exists := toolMap["go"]; exists {
+		if goStr, ok := goScript.(string); ok {
+			toolConfig.Go = goStr
 		}
+	}
 
-		// Parse go (Go script implementation)
-		if goScript, exists := toolMap["go"]; exists {
-			if goStr, ok := goScript.(string); ok {
-				toolConfig.Go = goStr
+	// Parse container (container image implementation) and its run options
+	if container, ok := toolMap["container"].(string); ok {
+		toolConfig.Container = container
+	}
+	if entrypoint, ok := toolMap["entrypoint"].(string); ok {
+		toolConfig.Entrypoint = entrypoint
+	}
+	if args, ok := toolMap["args"].([]any); ok {
+		for _, arg := range args {
+			if argStr, ok := arg.(string); ok {
+				toolConfig.Args = append(toolConfig.Args, argStr)
 			}
 		}
-
-		// Parse env (environment variables)
-		if env, exists := toolMap["env"]; exists {
-			if envMap, ok := env.(map[string]any); ok {
-				for envName, envValue := range envMap {
-					if envStr, ok := envValue.(string); ok {
-						toolConfig.Env[envName] = envStr
-					}
-				}
+	}
+	if network, ok := toolMap["network"].(bool); ok {
+		toolConfig.Network = network
+	}
+	if mounts, ok := toolMap["mounts"].([]any); ok {
+		for _, mount := range mounts {
+			if mountStr, ok := mount.(string); ok {
+				toolConfig.Mounts = append(toolConfig.Mounts, mountStr)
 			}
 		}
+	}
 
-		// Parse timeout (optional, default is 60 seconds)
-		if timeout, exists := toolMap["timeout"]; exists {
-			switch t := timeout.(type) {
-			case int:
-				toolConfig.Timeout = t
-			case uint64:
-				toolConfig.Timeout = safeUint64ToIntForTimeout(t) // Safe conversion to prevent overflow (alert #414)
-			case float64:
-				toolConfig.Timeout = int(t)
-			case string:
-				// Try to parse string as integer
-				_, _ = fmt.Sscanf(t, "%d", &toolConfig.Timeout)
+	// Parse env (environment variables)
+	if env, exists := toolMap["env"]; exists {
+		if envMap, ok := env.(map[string]any); ok {
+			for envName, envValue := range envMap {
+				if envStr, ok := envValue.(string); ok {
+					toolConfig.Env[envName] = envStr
+				}
 			}
 		}
+	}
 
-		config.Tools[toolName] = toolConfig
+	// Parse timeout (optional, default is 60 seconds)
+	if timeout, exists := toolMap["timeout"]; exists {
+		switch t := timeout.(type) {
+		case int:
+			toolConfig.Timeout = t
+		case uint64:
+			toolConfig.Timeout = safeUint64ToIntForTimeout(t) // Safe conversion to prevent overflow (alert #414)
+		case float64:
+			toolConfig.Timeout = int(t)
+		case string:
+			// Try to parse string as integer
+			_, _ = fmt.Sscanf(t, "%d", &toolConfig.Timeout)
+		}
 	}
 
-	return config, len(config.Tools) > 0
+	return toolConfig
 }
 
 // ParseSafeInputs parses safe-inputs configuration from frontmatter (standalone function for testing)
@@ -291,107 +313,7 @@ func (c *Compiler) mergeSafeInputs(main *SafeInputsConfig, importedConfigs []str
 				continue
 			}
 
-			toolConfig := &SafeInputToolConfig{
-				Name:    toolName,
-				Inputs:  make(map[string]*SafeInputParam),
-				Env:     make(map[string]string),
-				Timeout: 60, // Default timeout: 60 seconds
-			}
-
-			// Parse description
-			if desc, exists := toolMap["description"]; exists {
-				if descStr, ok := desc.(string); ok {
-					toolConfig.Description = descStr
-				}
-			}
-
-			// Parse inputs
-			if inputs, exists := toolMap["inputs"]; exists {
-				if inputsMap, ok := inputs.(map[string]any); ok {
-					for paramName, paramValue := range inputsMap {
-						if paramMap, ok := paramValue.(map[string]any); ok {
-							param := &SafeInputParam{
-								Type: "string",
-							}
-							if t, exists := paramMap["type"]; exists {
-								if tStr, ok := t.(string); ok {
-									param.Type = tStr
-								}
-							}
-							if desc, exists := paramMap["description"]; exists {
-								if descStr, ok := desc.(string); ok {
-									param.Description = descStr
-								}
-							}
-							if req, exists := paramMap["required"]; exists {
-								if reqBool, ok := req.(bool); ok {
-									param.Required = reqBool
-								}
-							}
-							if def, exists := paramMap["default"]; exists {
-								param.Default = def
-							}
-							toolConfig.Inputs[paramName] = param
-						}
-					}
-				}
-			}
-
-			// Parse script
-			if script, exists := toolMap["script"]; exists {
-				if scriptStr, ok := script.(string); ok {
-					toolConfig.Script = scriptStr
-				}
-			}
-
-			// Parse run
-			if run, exists := toolMap["run"]; exists {
-				if runStr, ok := run.(string); ok {
-					toolConfig.Run = runStr
-				}
-			}
-
-			// Parse py
-			if py, exists := toolMap["py"]; exists {
-				if pyStr, ok := py.(string); ok {
-					toolConfig.Py = pyStr
-				}
-			}
-
-			// Parse go
-			if goScript, exists := toolMap["go"]; exists {
-				if goStr, ok := goScript.(string); ok {
-					toolConfig.Go = goStr
-				}
-			}
-
-			// Parse env
-			if env, exists := toolMap["env"]; exists {
-				if envMap, ok := env.(map[string]any); ok {
-					for envName, envValue := range envMap {
-						if envStr, ok := envValue.(string); ok {
-							toolConfig.Env[envName] = envStr
-						}
-					}
-				}
-			}
-
-			// Parse timeout (optional, default is 60 seconds)
-			if timeout, exists := toolMap["timeout"]; exists {
-				switch t := timeout.(type) {
-				case int:
-					toolConfig.Timeout = t
-				case uint64:
-					toolConfig.Timeout = safeUint64ToIntForTimeout(t) // Safe conversion to prevent overflow (alert #413)
-				case float64:
-					toolConfig.Timeout = int(t)
-				case string:
-					// Try to parse string as integer
-					_, _ = fmt.Sscanf(t, "%d", &toolConfig.Timeout)
-				}
-			}
-
-			main.Tools[toolName] = toolConfig
+			main.Tools[toolName] = parseSafeInputTool(toolName, toolMap)
 			safeInputsLog.Printf("Merged imported safe-input tool: %s", toolName)
 		}
 	}
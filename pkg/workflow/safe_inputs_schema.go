@@ -0,0 +1,266 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseSafeInputParams parses the inputs map of a safe-input tool (or the properties
+// of an object parameter) into parameter definitions
+func parseSafeInputParams(inputs any) map[string]*SafeInputParam {
+	params := make(map[string]*SafeInputParam)
+	inputsMap, ok := inputs.(map[string]any)
+	if !ok {
+		return params
+	}
+	for paramName, paramValue := range inputsMap {
+		if paramMap, ok := paramValue.(map[string]any); ok {
+			params[paramName] = parseSafeInputParam(paramMap)
+		}
+	}
+	return params
+}
+
+// parseSafeInputParam parses a single parameter definition, including its JSON Schema constraints
+func parseSafeInputParam(paramMap map[string]any) *SafeInputParam {
+	param := &SafeInputParam{
+		Type: "string", // default type
+	}
+
+	if t, ok := paramMap["type"].(string); ok {
+		param.Type = t
+	}
+	if desc, ok := paramMap["description"].(string); ok {
+		param.Description = desc
+	}
+	if req, ok := paramMap["required"].(bool); ok {
+		param.Required = req
+	}
+	if def, exists := paramMap["default"]; exists {
+		param.Default = def
+	}
+	if enum, ok := paramMap["enum"].([]any); ok {
+		param.Enum = enum
+	}
+	if pattern, ok := paramMap["pattern"].(string); ok {
+		param.Pattern = pattern
+	}
+	if minimum, ok := safeInputNumber(paramMap["minimum"]); ok {
+		param.Minimum = &minimum
+	}
+	if maximum, ok := safeInputNumber(paramMap["maximum"]); ok {
+		param.Maximum = &maximum
+	}
+	if minLength, ok := parseIntValue(paramMap["minLength"]); ok {
+		param.MinLength = &minLength
+	}
+	if maxLength, ok := parseIntValue(paramMap["maxLength"]); ok {
+		param.MaxLength = &maxLength
+	}
+	if items, ok := paramMap["items"].(map[string]any); ok {
+		param.Items = parseSafeInputParam(items)
+	}
+	if properties, exists := paramMap["properties"]; exists {
+		param.Properties = parseSafeInputParams(properties)
+	}
+
+	return param
+}
+
+// JSONSchema returns the JSON Schema of the parameter as published in the tool input schema
+func (p *SafeInputParam) JSONSchema() map[string]any {
+	schema := map[string]any{
+		"type": p.Type,
+	}
+	if p.Description != "" {
+		schema["description"] = p.Description
+	}
+	if p.Default != nil {
+		schema["default"] = p.Default
+	}
+	if len(p.Enum) > 0 {
+		schema["enum"] = p.Enum
+	}
+	if p.Pattern != "" {
+		schema["pattern"] = p.Pattern
+	}
+	if p.Minimum != nil {
+		schema["minimum"] = *p.Minimum
+	}
+	if p.Maximum != nil {
+		schema["maximum"] = *p.Maximum
+	}
+	if p.MinLength != nil {
+		schema["minLength"] = *p.MinLength
+	}
+	if p.MaxLength != nil {
+		schema["maxLength"] = *p.MaxLength
+	}
+	if p.Items != nil {
+		schema["items"] = p.Items.JSONSchema()
+	}
+	if len(p.Properties) > 0 {
+		properties := make(map[string]any, len(p.Properties))
+		var required []string
+		for name, property := range p.Properties {
+			properties[name] = property.JSONSchema()
+			if property.Required {
+				required = append(required, name)
+			}
+		}
+		schema["properties"] = properties
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+	}
+	return schema
+}
+
+// ValidateSafeInputValue validates a value against the schema of a parameter.
+// The path names the value in the returned messages (e.g. "labels[0]" or "filter.author"),
+// which match the messages of the safe-inputs MCP server.
+func ValidateSafeInputValue(param *SafeInputParam, value any, path string) []string {
+	actualType := safeInputJSONType(value)
+	if !safeInputTypeMatches(param.Type, actualType) {
+		return []string{fmt.Sprintf("%s must be of type %s, got %s", path, param.Type, actualType)}
+	}
+
+	var problems []string
+	if len(param.Enum) > 0 && !safeInputEnumContains(param.Enum, value) {
+		allowed := make([]string, 0, len(param.Enum))
+		for _, option := range param.Enum {
+			allowed = append(allowed, safeInputJSONString(option))
+		}
+		problems = append(problems, fmt.Sprintf("%s must be one of: %s", path, strings.Join(allowed, ", ")))
+	}
+
+	if str, ok := value.(string); ok {
+		if param.Pattern != "" {
+			if re, err := regexp.Compile(param.Pattern); err == nil && !re.MatchString(str) {
+				problems = append(problems, fmt.Sprintf("%s must match pattern %s", path, param.Pattern))
+			}
+		}
+		length := utf8.RuneCountInString(str)
+		if param.MinLength != nil && length < *param.MinLength {
+			problems = append(problems, fmt.Sprintf("%s must be at least %d characters", path, *param.MinLength))
+		}
+		if param.MaxLength != nil && length > *param.MaxLength {
+			problems = append(problems, fmt.Sprintf("%s must be at most %d characters", path, *param.MaxLength))
+		}
+	}
+
+	if number, ok := safeInputNumber(value); ok {
+		if param.Minimum != nil && number < *param.Minimum {
+			problems = append(problems, fmt.Sprintf("%s must be >= %v", path, *param.Minimum))
+		}
+		if param.Maximum != nil && number > *param.Maximum {
+			problems = append(problems, fmt.Sprintf("%s must be <= %v", path, *param.Maximum))
+		}
+	}
+
+	if items, ok := value.([]any); ok && param.Items != nil {
+		for i, item := range items {
+			problems = append(problems, ValidateSafeInputValue(param.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+
+	if object, ok := value.(map[string]any); ok && len(param.Properties) > 0 {
+		names := make([]string, 0, len(param.Properties))
+		for name := range param.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property := param.Properties[name]
+			propertyValue, exists := object[name]
+			if !exists || propertyValue == nil {
+				if property.Required {
+					problems = append(problems, fmt.Sprintf("%s.%s is required", path, name))
+				}
+				continue
+			}
+			problems = append(problems, ValidateSafeInputValue(property, propertyValue, path+"."+name)...)
+		}
+	}
+
+	return problems
+}
+
+// safeInputJSONType returns the JSON Schema type name of a decoded YAML or JSON value
+func safeInputJSONType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// safeInputTypeMatches reports whether a value of the actual type satisfies the declared type
+func safeInputTypeMatches(declared, actual string) bool {
+	switch declared {
+	case "", "any":
+		return true
+	case "number":
+		return actual == "number" || actual == "integer"
+	default:
+		return declared == actual
+	}
+}
+
+// safeInputNumber converts a decoded YAML or JSON number to float64
+func safeInputNumber(value any) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// safeInputEnumContains reports whether value is one of the enum options.
+// Values are compared by their JSON encoding so YAML and JSON numbers compare equal.
+func safeInputEnumContains(enum []any, value any) bool {
+	encoded := safeInputJSONString(value)
+	for _, option := range enum {
+		if safeInputJSONString(option) == encoded {
+			return true
+		}
+	}
+	return false
+}
+
+// safeInputJSONString returns the JSON encoding of a value
+func safeInputJSONString(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
@@ -0,0 +1,179 @@
+// This file provides validation for safe-inputs tool definitions.
+//
+// # Safe Inputs Validation
+//
+// This file validates the JSON Schema of safe-input tool parameters at compile
+// time, so invalid tool contracts are reported before the workflow runs. It ensures that:
+//   - Parameter types are valid JSON Schema types
+//   - Patterns are valid regular expressions
+//   - Range and length bounds are consistent and apply to the parameter type
+//   - Enum values and defaults conform to the parameter schema
+//   - Output schemas describe an object, as required by MCP
+//   - Container tools are pinned by digest and declare valid mounts
+//
+// # Validation Functions
+//
+//   - validateSafeInputs() - Validates all safe-input tools in the configuration
+//   - validateSafeInputParamSchema() - Validates a parameter definition recursively
+//   - validateSafeInputContainer() - Validates the container options of a tool
+
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var safeInputsValidationLog = logger.New("workflow:safe_inputs_validation")
+
+// validSafeInputTypes lists the JSON Schema types supported for safe-input parameters
+var validSafeInputTypes = []string{"string", "number", "integer", "boolean", "array", "object"}
+
+// validateSafeInputs validates the parameter schemas, defaults and output schemas of all safe-input tools
+func validateSafeInputs(safeInputs *SafeInputsConfig) error {
+	if !HasSafeInputs(safeInputs) {
+		return nil
+	}
+
+	safeInputsValidationLog.Printf("Validating %d safe-input tools", len(safeInputs.Tools))
+
+	toolNames := make([]string, 0, len(safeInputs.Tools))
+	for name := range safeInputs.Tools {
+		toolNames = append(toolNames, name)
+	}
+	sort.Strings(toolNames)
+
+	var errs []error
+	for _, toolName := range toolNames {
+		tool := safeInputs.Tools[toolName]
+
+		inputNames := make([]string, 0, len(tool.Inputs))
+		for name := range tool.Inputs {
+			inputNames = append(inputNames, name)
+		}
+		sort.Strings(inputNames)
+
+		for _, inputName := range inputNames {
+			for _, problem := range validateSafeInputParamSchema(tool.Inputs[inputName], inputName) {
+				errs = append(errs, fmt.Errorf("safe-inputs tool '%s': %s", toolName, problem))
+			}
+		}
+
+		for _, problem := range validateSafeInputContainer(tool) {
+			errs = append(errs, fmt.Errorf("safe-inputs tool '%s': %s", toolName, problem))
+		}
+
+		if tool.Output != nil {
+			if outputType, _ := tool.Output["type"].(string); outputType != "object" {
+				errs = append(errs, fmt.Errorf("safe-inputs tool '%s': output-schema must have type 'object'", toolName))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		safeInputsValidationLog.Printf("Safe-inputs validation failed: %d problem(s)", len(errs))
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// validateSafeInputParamSchema validates a parameter definition and its nested item and property schemas
+func validateSafeInputParamSchema(param *SafeInputParam, path string) []string {
+	var problems []string
+
+	if !slices.Contains(validSafeInputTypes, param.Type) {
+		return []string{fmt.Sprintf("input '%s' has invalid type '%s' (must be one of: %s)", path, param.Type, strings.Join(validSafeInputTypes, ", "))}
+	}
+
+	if param.Pattern != "" {
+		if param.Type != "string" {
+			problems = append(problems, fmt.Sprintf("input '%s': pattern only applies to string inputs", path))
+		} else if _, err := regexp.Compile(param.Pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("input '%s' has invalid pattern: %v", path, err))
+		}
+	}
+
+	if (param.MinLength != nil || param.MaxLength != nil) && param.Type != "string" {
+		problems = append(problems, fmt.Sprintf("input '%s': minLength and maxLength only apply to string inputs", path))
+	}
+	if param.MinLength != nil && param.MaxLength != nil && *param.MinLength > *param.MaxLength {
+		problems = append(problems, fmt.Sprintf("input '%s': minLength %d is greater than maxLength %d", path, *param.MinLength, *param.MaxLength))
+	}
+
+	if (param.Minimum != nil || param.Maximum != nil) && param.Type != "number" && param.Type != "integer" {
+		problems = append(problems, fmt.Sprintf("input '%s': minimum and maximum only apply to number and integer inputs", path))
+	}
+	if param.Minimum != nil && param.Maximum != nil && *param.Minimum > *param.Maximum {
+		problems = append(problems, fmt.Sprintf("input '%s': minimum %v is greater than maximum %v", path, *param.Minimum, *param.Maximum))
+	}
+
+	if param.Items != nil && param.Type != "array" {
+		problems = append(problems, fmt.Sprintf("input '%s': items only applies to array inputs", path))
+	}
+	if len(param.Properties) > 0 && param.Type != "object" {
+		problems = append(problems, fmt.Sprintf("input '%s': properties only applies to object inputs", path))
+	}
+
+	// Stop before checking values against a schema that is itself invalid
+	if len(problems) > 0 {
+		return problems
+	}
+
+	if param.Items != nil {
+		problems = append(problems, validateSafeInputParamSchema(param.Items, path+"[]")...)
+	}
+	propertyNames := make([]string, 0, len(param.Properties))
+	for name := range param.Properties {
+		propertyNames = append(propertyNames, name)
+	}
+	sort.Strings(propertyNames)
+	for _, name := range propertyNames {
+		problems = append(problems, validateSafeInputParamSchema(param.Properties[name], path+"."+name)...)
+	}
+
+	// Enum options must themselves satisfy the type, ignoring the enum constraint
+	if len(param.Enum) > 0 {
+		unconstrained := *param
+		unconstrained.Enum = nil
+		for _, option := range param.Enum {
+			for _, problem := range ValidateSafeInputValue(&unconstrained, option, "value") {
+				problems = append(problems, fmt.Sprintf("input '%s' has invalid enum option %s: %s", path, safeInputJSONString(option), problem))
+			}
+		}
+	}
+
+	if param.Default != nil {
+		for _, problem := range ValidateSafeInputValue(param, param.Default, "default") {
+			problems = append(problems, fmt.Sprintf("input '%s' has invalid default: %s", path, problem))
+		}
+	}
+
+	return problems
+}
+
+// validateSafeInputContainer validates the image and run options of container tools
+func validateSafeInputContainer(tool *SafeInputToolConfig) []string {
+	if tool.Container == "" {
+		if tool.Entrypoint != "" || len(tool.Args) > 0 || tool.Network || len(tool.Mounts) > 0 {
+			return []string{"entrypoint, args, network and mounts only apply to container tools"}
+		}
+		return nil
+	}
+
+	var problems []string
+	if !safeInputContainerDigestPattern.MatchString(tool.Container) {
+		problems = append(problems, fmt.Sprintf("container '%s' must be pinned by digest (image@sha256:<digest>)", tool.Container))
+	}
+	for _, spec := range tool.Mounts {
+		if _, err := parseSafeInputContainerMount(spec); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	return problems
+}
@@ -0,0 +1,228 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/stringutil"
+	"github.com/github/gh-aw/pkg/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeInputParamJSONSchema(t *testing.T) {
+	config := ParseSafeInputs(map[string]any{
+		"safe-inputs": map[string]any{
+			"search": map[string]any{
+				"description": "Search issues",
+				"script":      "return inputs;",
+				"inputs": map[string]any{
+					"query": map[string]any{
+						"type":      "string",
+						"required":  true,
+						"pattern":   "^[a-z ]+$",
+						"maxLength": uint64(50),
+					},
+					"state": map[string]any{
+						"type":    "string",
+						"enum":    []any{"open", "closed"},
+						"default": "open",
+					},
+					"limit": map[string]any{
+						"type":    "integer",
+						"minimum": uint64(1),
+						"maximum": uint64(100),
+					},
+					"filters": map[string]any{
+						"type": "array",
+						"items": map[string]any{
+							"type": "object",
+							"properties": map[string]any{
+								"field": map[string]any{"type": "string", "required": true},
+								"value": map[string]any{"type": "string"},
+							},
+						},
+					},
+				},
+				"output-schema": map[string]any{
+					"type":       "object",
+					"properties": map[string]any{"total": map[string]any{"type": "integer"}},
+				},
+			},
+		},
+	})
+	require.NotNil(t, config)
+
+	var toolsJSON SafeInputsConfigJSON
+	require.NoError(t, json.Unmarshal([]byte(generateSafeInputsToolsConfig(config)), &toolsJSON))
+	require.Len(t, toolsJSON.Tools, 1)
+	tool := toolsJSON.Tools[0]
+
+	properties := tool.InputSchema["properties"].(map[string]any)
+	assert.Equal(t, map[string]any{"type": "string", "description": "", "pattern": "^[a-z ]+$", "maxLength": float64(50)}, properties["query"])
+	assert.Equal(t, map[string]any{"type": "string", "description": "", "enum": []any{"open", "closed"}, "default": "open"}, properties["state"])
+	assert.Equal(t, map[string]any{"type": "integer", "description": "", "minimum": float64(1), "maximum": float64(100)}, properties["limit"])
+	assert.Equal(t, map[string]any{
+		"type":        "array",
+		"description": "",
+		"items": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"field": map[string]any{"type": "string"},
+				"value": map[string]any{"type": "string"},
+			},
+			"required": []any{"field"},
+		},
+	}, properties["filters"])
+	assert.Equal(t, []any{"query"}, tool.InputSchema["required"])
+	assert.Equal(t, map[string]any{"type": "object", "properties": map[string]any{"total": map[string]any{"type": "integer"}}}, tool.OutputSchema)
+}
+
+func TestValidateSafeInputValue(t *testing.T) {
+	minimum, maximum := 1.0, 10.0
+	maxLength := 3
+	param := &SafeInputParam{
+		Type: "object",
+		Properties: map[string]*SafeInputParam{
+			"code":  {Type: "string", Pattern: "^[A-Z]+$", MaxLength: &maxLength},
+			"count": {Type: "integer", Minimum: &minimum, Maximum: &maximum, Required: true},
+			"tags":  {Type: "array", Items: &SafeInputParam{Type: "string", Enum: []any{"a", "b"}}},
+			"ratio": {Type: "number"},
+		},
+	}
+
+	assert.Empty(t, ValidateSafeInputValue(param, map[string]any{"code": "AB", "count": uint64(3), "tags": []any{"a"}, "ratio": 2}, "value"))
+	assert.Equal(t, []string{
+		"value.code must match pattern ^[A-Z]+$",
+		"value.code must be at most 3 characters",
+		"value.count must be <= 10",
+		`value.tags[1] must be one of: "a", "b"`,
+	}, ValidateSafeInputValue(param, map[string]any{"code": "abcd", "count": float64(11), "tags": []any{"b", "c"}}, "value"))
+	assert.Equal(t, []string{"value.count is required"}, ValidateSafeInputValue(param, map[string]any{}, "value"))
+	assert.Equal(t, []string{"value must be of type object, got string"}, ValidateSafeInputValue(param, "x", "value"))
+}
+
+func TestValidateSafeInputs(t *testing.T) {
+	tests := []struct {
+		name      string
+		inputs    map[string]any
+		output    map[string]any
+		wantError []string
+	}{
+		{
+			name: "valid constraints and defaults",
+			inputs: map[string]any{
+				"state": map[string]any{"type": "string", "enum": []any{"open", "closed"}, "default": "open"},
+				"limit": map[string]any{"type": "integer", "minimum": uint64(1), "maximum": uint64(50), "default": uint64(10)},
+				"ids":   map[string]any{"type": "array", "items": map[string]any{"type": "integer"}, "default": []any{uint64(1)}},
+			},
+			output: map[string]any{"type": "object"},
+		},
+		{
+			name: "default outside the schema",
+			inputs: map[string]any{
+				"state": map[string]any{"type": "string", "enum": []any{"open", "closed"}, "default": "merged"},
+				"limit": map[string]any{"type": "integer", "maximum": uint64(50), "default": uint64(100)},
+			},
+			wantError: []string{
+				`safe-inputs tool 'search': input 'limit' has invalid default: default must be <= 50`,
+				`safe-inputs tool 'search': input 'state' has invalid default: default must be one of: "open", "closed"`,
+			},
+		},
+		{
+			name: "inconsistent schema",
+			inputs: map[string]any{
+				"query": map[string]any{"type": "string", "pattern": "([a-z"},
+				"count": map[string]any{"type": "string", "minimum": uint64(1)},
+				"range": map[string]any{"type": "number", "minimum": uint64(5), "maximum": uint64(1)},
+				"kind":  map[string]any{"type": "integer", "enum": []any{uint64(1), "two"}},
+				"items": map[string]any{"type": "array", "items": map[string]any{"type": "date"}},
+			},
+			output: map[string]any{"type": "array"},
+			wantError: []string{
+				"input 'count': minimum and maximum only apply to number and integer inputs",
+				"input 'items[]' has invalid type 'date'",
+				`input 'kind' has invalid enum option "two": value must be of type integer, got string`,
+				"input 'query' has invalid pattern",
+				"input 'range': minimum 5 is greater than maximum 1",
+				"output-schema must have type 'object'",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := map[string]any{
+				"description": "Search",
+				"script":      "return 1;",
+				"inputs":      tt.inputs,
+			}
+			if tt.output != nil {
+				tool["output-schema"] = tt.output
+			}
+			config := ParseSafeInputs(map[string]any{"safe-inputs": map[string]any{"search": tool}})
+
+			err := validateSafeInputs(config)
+			if len(tt.wantError) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantError {
+				assert.Contains(t, err.Error(), want)
+			}
+		})
+	}
+}
+
+func TestCompileWorkflowValidatesSafeInputDefaults(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "safe-inputs-schema-test")
+
+	content := `---
+on: workflow_dispatch
+engine: copilot
+safe-inputs:
+  search:
+    description: Search issues
+    inputs:
+      state:
+        type: string
+        enum: [open, closed]
+        default: %s
+      labels:
+        type: array
+        items:
+          type: string
+          minLength: 1
+    output-schema:
+      type: object
+      properties:
+        total:
+          type: integer
+    script: |
+      return { total: 0 };
+---
+
+# Search
+`
+	workflowPath := filepath.Join(tmpDir, "search.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(fmt.Sprintf(content, "open")), 0644))
+	compiler := NewCompiler()
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	lockContent, err := os.ReadFile(stringutil.MarkdownToLockFile(workflowPath))
+	require.NoError(t, err)
+	assert.Contains(t, string(lockContent), `"outputSchema"`)
+	assert.Contains(t, string(lockContent), `"minLength": 1`)
+
+	require.NoError(t, os.WriteFile(workflowPath, []byte(fmt.Sprintf(content, "merged")), 0644))
+	err = NewCompiler().CompileWorkflow(workflowPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `input 'state' has invalid default: default must be one of: "open", "closed"`)
+}
@@ -38,8 +38,9 @@ const (
 // Legacy format: "default"|"sandbox-runtime" or { type, config }
 type SandboxConfig struct {
 	// New fields
-	Agent *AgentSandboxConfig      `yaml:"agent,omitempty"` // Agent sandbox configuration
-	MCP   *MCPGatewayRuntimeConfig `yaml:"mcp,omitempty"`   // MCP gateway configuration
+	Agent      *AgentSandboxConfig      `yaml:"agent,omitempty"`      // Agent sandbox configuration
+	MCP        *MCPGatewayRuntimeConfig `yaml:"mcp,omitempty"`        // MCP gateway configuration
+	Filesystem *SandboxFilesystemConfig `yaml:"filesystem,omitempty"` // Filesystem policy applied by AWF and SRT
 
 	// Legacy fields (for backward compatibility)
 	Type   SandboxType           `yaml:"type,omitempty"`   // Sandbox type: "default" or "sandbox-runtime"
@@ -201,7 +202,8 @@ func generateSRTConfigJSON(workflowData *WorkflowData) (string, error) {
 
 		// Apply filesystem config if provided
 		if userConfig.Filesystem != nil {
-			srtConfig.Filesystem = userConfig.Filesystem
+			filesystem := *userConfig.Filesystem
+			srtConfig.Filesystem = &filesystem
 			// Normalize nil slices
 			if srtConfig.Filesystem.DenyRead == nil {
 				srtConfig.Filesystem.DenyRead = []string{}
@@ -228,6 +230,9 @@ func generateSRTConfigJSON(workflowData *WorkflowData) (string, error) {
 		srtConfig.EnableWeakerNestedSandbox = userConfig.EnableWeakerNestedSandbox
 	}
 
+	// Apply the filesystem policy of the agent sandbox (sandbox.filesystem)
+	applySandboxFilesystemToSRT(srtConfig.Filesystem, workflowData)
+
 	// Marshal to JSON with indentation
 	jsonBytes, err := json.MarshalIndent(srtConfig, "", "  ")
 	if err != nil {
@@ -0,0 +1,186 @@
+// This file provides the filesystem policy of the agent sandbox.
+//
+// # Filesystem Policy
+//
+// The sandbox.filesystem section restricts the paths the agent can read and write:
+//
+//	sandbox:
+//	  filesystem:
+//	    read-only:
+//	      - .github/workflows
+//	    writable:
+//	      - .github/workflows/shared
+//	    denied:
+//	      - ~/.ssh
+//
+// Relative paths are resolved against the repository checkout and ~ against the home
+// directory of the runner. The policy is applied by both agent sandboxes:
+//
+//   - AWF: read-only and writable paths are bind mounted into the agent container with the
+//     ro and rw modes, and an empty read-only directory or file is mounted over denied paths.
+//     Whether a path exists and is a file is only known on the runner, so a step before the
+//     agent writes the mounts to a file, which the agent step loads into a bash array passed
+//     to AWF. The empty read-only directory is also mounted over read-only and denied paths
+//     that do not exist yet, so the agent cannot create them; writable paths that do not
+//     exist are not mounted.
+//   - SRT: read-only paths are added to denyWrite, denied paths to denyRead and denyWrite,
+//     and writable paths to allowWrite
+//
+// Writable paths re-open sub-paths of read-only paths. The policy is recorded in
+// aw_info.json so `gh aw audit` can report the attempts to access the protected paths.
+//
+// Validation of the policy is in sandbox_validation.go.
+
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var sandboxFilesystemLog = logger.New("workflow:sandbox_filesystem")
+
+// sandboxDeniedMountSource is the empty directory mounted over denied directories in the AWF container
+const sandboxDeniedMountSource = "/tmp/gh-aw/sandbox/denied"
+
+// sandboxDeniedFileMountSource is the empty file mounted over denied files in the AWF container
+const sandboxDeniedFileMountSource = "/tmp/gh-aw/sandbox/denied-file"
+
+// sandboxMountsFile holds the AWF mount arguments prepared on the runner, one per line
+const sandboxMountsFile = "/tmp/gh-aw/sandbox/mounts"
+
+// sandboxMountsVar is the bash array the agent step loads the prepared mount arguments into
+const sandboxMountsVar = "GH_AW_SANDBOX_MOUNTS"
+
+// SandboxFilesystemConfig is the filesystem policy of the agent sandbox
+type SandboxFilesystemConfig struct {
+	ReadOnly []string `yaml:"read-only,omitempty"` // Paths the agent can read but not modify
+	Writable []string `yaml:"writable,omitempty"`  // Paths the agent can modify, including inside read-only paths
+	Denied   []string `yaml:"denied,omitempty"`    // Paths the agent can neither read nor modify
+}
+
+// extractSandboxFilesystemConfig extracts the filesystem policy of the agent sandbox
+func extractSandboxFilesystemConfig(filesystemVal any) *SandboxFilesystemConfig {
+	filesystemObj, ok := filesystemVal.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	config := &SandboxFilesystemConfig{
+		ReadOnly: parseStringOrList(filesystemObj["read-only"]),
+		Writable: parseStringOrList(filesystemObj["writable"]),
+		Denied:   parseStringOrList(filesystemObj["denied"]),
+	}
+	sandboxFilesystemLog.Printf("Extracted filesystem policy: read-only=%d, writable=%d, denied=%d",
+		len(config.ReadOnly), len(config.Writable), len(config.Denied))
+	return config
+}
+
+// getSandboxFilesystemConfig returns the filesystem policy of the workflow, or nil if it has none
+func getSandboxFilesystemConfig(workflowData *WorkflowData) *SandboxFilesystemConfig {
+	if workflowData == nil || workflowData.SandboxConfig == nil {
+		return nil
+	}
+	return workflowData.SandboxConfig.Filesystem
+}
+
+// resolveSandboxPath returns the absolute form of a policy path as a shell word, resolving
+// relative paths against the workspace and ~ against the home directory
+func resolveSandboxPath(p string) string {
+	switch {
+	case p == "~":
+		return "${HOME}"
+	case strings.HasPrefix(p, "~/"):
+		return "${HOME}/" + strings.TrimPrefix(p, "~/")
+	case strings.HasPrefix(p, "/"):
+		return p
+	case p == ".":
+		return "${GITHUB_WORKSPACE}"
+	default:
+		return "${GITHUB_WORKSPACE}/" + strings.TrimPrefix(p, "./")
+	}
+}
+
+// getSandboxFilesystemMountArgs returns the AWF arguments adding the mounts of the filesystem
+// policy: the bash array of the mount arguments prepared by generateSandboxFilesystemSetup
+func getSandboxFilesystemMountArgs(workflowData *WorkflowData) []string {
+	if getSandboxFilesystemConfig(workflowData) == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("\"${%s[@]}\"", sandboxMountsVar)}
+}
+
+// getSandboxFilesystemMountsLoad returns the shell line of the agent step loading the prepared
+// mount arguments into the bash array, or an empty string when the workflow has no policy
+func getSandboxFilesystemMountsLoad(workflowData *WorkflowData) string {
+	if getSandboxFilesystemConfig(workflowData) == nil {
+		return ""
+	}
+	return fmt.Sprintf("mapfile -t %s < %s\n", sandboxMountsVar, sandboxMountsFile)
+}
+
+// generateSandboxFilesystemSetup generates the step preparing the AWF mounts of the filesystem
+// policy. Existing read-only and writable paths are mounted with their mode, denied directories
+// get an empty directory and denied files an empty file. Read-only and denied paths that do not
+// exist get the empty read-only directory as a placeholder, which keeps the agent from creating
+// them. Writable paths that do not exist are skipped, as the agent may create them anyway.
+func (c *Compiler) generateSandboxFilesystemSetup(yaml *strings.Builder, workflowData *WorkflowData) {
+	policy := getSandboxFilesystemConfig(workflowData)
+	if policy == nil || !isFirewallEnabled(workflowData) {
+		return
+	}
+
+	sandboxFilesystemLog.Print("Generating sandbox filesystem policy setup step")
+	yaml.WriteString("      - name: Prepare sandbox filesystem policy\n")
+	yaml.WriteString("        run: |\n")
+	fmt.Fprintf(yaml, "          mkdir -p %s\n", sandboxDeniedMountSource)
+	fmt.Fprintf(yaml, "          : > %s\n", sandboxDeniedFileMountSource)
+	fmt.Fprintf(yaml, "          chmod 0444 %s\n", sandboxDeniedFileMountSource)
+	fmt.Fprintf(yaml, "          : > %s\n", sandboxMountsFile)
+	yaml.WriteString("          add_mount() {\n")
+	yaml.WriteString("            source=\"$1\"\n")
+	yaml.WriteString("            if [ ! -e \"$2\" ]; then\n")
+	yaml.WriteString("              if [ \"$3\" = writable ]; then\n")
+	yaml.WriteString("                echo \"Skipping the writable mount of $2: the path does not exist\"\n")
+	yaml.WriteString("                return\n")
+	yaml.WriteString("              fi\n")
+	yaml.WriteString("              echo \"Protecting the missing $3 path $2 with an empty read-only directory\"\n")
+	fmt.Fprintf(yaml, "              source=%s\n", sandboxDeniedMountSource)
+	yaml.WriteString("            elif [ \"$3\" = denied ]; then\n")
+	fmt.Fprintf(yaml, "              source=%s\n", sandboxDeniedMountSource)
+	fmt.Fprintf(yaml, "              [ -d \"$2\" ] || source=%s\n", sandboxDeniedFileMountSource)
+	yaml.WriteString("            fi\n")
+	yaml.WriteString("            mode=ro\n")
+	yaml.WriteString("            [ \"$3\" = writable ] && mode=rw\n")
+	fmt.Fprintf(yaml, "            printf '%%s\\n' --mount \"$source:$2:$mode\" >> %s\n", sandboxMountsFile)
+	yaml.WriteString("          }\n")
+	for _, list := range []struct {
+		kind  string
+		paths []string
+	}{
+		{"read-only", policy.ReadOnly},
+		{"writable", policy.Writable},
+		{"denied", policy.Denied},
+	} {
+		for _, p := range list.paths {
+			resolved := resolveSandboxPath(p)
+			fmt.Fprintf(yaml, "          add_mount \"%s\" \"%s\" %s\n", resolved, resolved, list.kind)
+		}
+	}
+}
+
+// applySandboxFilesystemToSRT adds the filesystem policy to the SRT filesystem configuration
+func applySandboxFilesystemToSRT(srtFilesystem *SRTFilesystemConfig, workflowData *WorkflowData) {
+	policy := getSandboxFilesystemConfig(workflowData)
+	if policy == nil || srtFilesystem == nil {
+		return
+	}
+
+	srtFilesystem.DenyWrite = slices.Concat(srtFilesystem.DenyWrite, policy.ReadOnly, policy.Denied)
+	srtFilesystem.DenyRead = slices.Concat(srtFilesystem.DenyRead, policy.Denied)
+	srtFilesystem.AllowWrite = slices.Concat(srtFilesystem.AllowWrite, policy.Writable)
+	sandboxFilesystemLog.Print("Applied filesystem policy to SRT config")
+}
@@ -0,0 +1,209 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSandboxPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: ".github/workflows", expected: "${GITHUB_WORKSPACE}/.github/workflows"},
+		{path: "./docs", expected: "${GITHUB_WORKSPACE}/docs"},
+		{path: ".", expected: "${GITHUB_WORKSPACE}"},
+		{path: "~/.ssh", expected: "${HOME}/.ssh"},
+		{path: "~", expected: "${HOME}"},
+		{path: "/etc/ssl", expected: "/etc/ssl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolveSandboxPath(tt.path))
+		})
+	}
+}
+
+func TestGetSandboxFilesystemMountArgs(t *testing.T) {
+	workflowData := &WorkflowData{
+		SandboxConfig: &SandboxConfig{
+			Filesystem: &SandboxFilesystemConfig{
+				ReadOnly: []string{".github/workflows"},
+				Denied:   []string{"~/.ssh"},
+			},
+		},
+	}
+
+	assert.Equal(t, []string{`"${GH_AW_SANDBOX_MOUNTS[@]}"`}, getSandboxFilesystemMountArgs(workflowData))
+	assert.Equal(t, "mapfile -t GH_AW_SANDBOX_MOUNTS < /tmp/gh-aw/sandbox/mounts\n", getSandboxFilesystemMountsLoad(workflowData))
+	assert.Empty(t, getSandboxFilesystemMountArgs(&WorkflowData{}))
+	assert.Empty(t, getSandboxFilesystemMountsLoad(&WorkflowData{}))
+}
+
+func TestApplySandboxFilesystemToSRT(t *testing.T) {
+	workflowData := &WorkflowData{
+		SandboxConfig: &SandboxConfig{
+			Agent: &AgentSandboxConfig{Type: SandboxTypeSRT},
+			Filesystem: &SandboxFilesystemConfig{
+				ReadOnly: []string{".github/workflows"},
+				Writable: []string{".github/workflows/shared"},
+				Denied:   []string{"~/.ssh"},
+			},
+		},
+	}
+
+	configJSON, err := generateSRTConfigJSON(workflowData)
+	require.NoError(t, err)
+
+	var config SandboxRuntimeConfig
+	require.NoError(t, json.Unmarshal([]byte(configJSON), &config))
+	require.NotNil(t, config.Filesystem)
+	assert.ElementsMatch(t, []string{".github/workflows", "~/.ssh"}, config.Filesystem.DenyWrite)
+	assert.Equal(t, []string{"~/.ssh"}, config.Filesystem.DenyRead)
+	assert.Contains(t, config.Filesystem.AllowWrite, ".github/workflows/shared")
+	assert.Contains(t, config.Filesystem.AllowWrite, ".", "default writable paths should be kept")
+}
+
+func TestCompileWorkflowWithSandboxFilesystem(t *testing.T) {
+	lockContent, err := compileTestWorkflow(t, `on:
+  issues:
+    types: [labeled]
+permissions:
+  contents: read
+engine: claude
+sandbox:
+  filesystem:
+    read-only: [.github/workflows]
+    writable: [.github/workflows/shared]
+    denied: ["~/.ssh"]
+`)
+	require.NoError(t, err)
+
+	assert.Contains(t, lockContent, "- name: Prepare sandbox filesystem policy")
+	assert.Contains(t, lockContent, "mkdir -p /tmp/gh-aw/sandbox/denied")
+	assert.Contains(t, lockContent, ": > /tmp/gh-aw/sandbox/denied-file")
+	assert.Contains(t, lockContent, `add_mount "${GITHUB_WORKSPACE}/.github/workflows" "${GITHUB_WORKSPACE}/.github/workflows" read-only`)
+	assert.Contains(t, lockContent, `add_mount "${GITHUB_WORKSPACE}/.github/workflows/shared" "${GITHUB_WORKSPACE}/.github/workflows/shared" writable`)
+	assert.Contains(t, lockContent, `add_mount "${HOME}/.ssh" "${HOME}/.ssh" denied`)
+	assert.NotContains(t, lockContent, `mkdir -p "${GITHUB_WORKSPACE}`, "missing paths should not be created")
+	assert.Contains(t, lockContent, "mapfile -t GH_AW_SANDBOX_MOUNTS < /tmp/gh-aw/sandbox/mounts")
+	assert.Contains(t, lockContent, `"${GH_AW_SANDBOX_MOUNTS[@]}"`)
+	assert.Contains(t, lockContent, "sandbox_filesystem:")
+
+	prepare := strings.Index(lockContent, "- name: Prepare sandbox filesystem policy")
+	mount := strings.Index(lockContent, `"${GH_AW_SANDBOX_MOUNTS[@]}"`)
+	assert.Less(t, prepare, mount, "the mounts should be prepared before the agent runs")
+}
+
+// TestSandboxFilesystemSetupScript runs the generated setup script against real paths to check
+// the prepared mounts: denied files get an empty file, denied directories an empty directory,
+// missing read-only and denied paths the empty directory, and missing writable paths are skipped
+func TestSandboxFilesystemSetupScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	workspace := testutil.TempDir(t, "sandbox-workspace")
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, ".github", "workflows"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(workspace, "secrets"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(workspace, ".env"), []byte("TOKEN=x\n"), 0644))
+
+	workflowData := &WorkflowData{
+		NetworkPermissions: &NetworkPermissions{Firewall: &FirewallConfig{Enabled: true}},
+		SandboxConfig: &SandboxConfig{
+			Filesystem: &SandboxFilesystemConfig{
+				ReadOnly: []string{".github/workflows", "docs"},
+				Writable: []string{".github/workflows/shared"},
+				Denied:   []string{".env", "secrets", "missing.key"},
+			},
+		},
+	}
+	var yaml strings.Builder
+	NewCompiler().generateSandboxFilesystemSetup(&yaml, workflowData)
+
+	sandboxDir := testutil.TempDir(t, "sandbox-dir")
+	var script strings.Builder
+	for _, line := range strings.Split(yaml.String(), "\n")[2:] {
+		script.WriteString(strings.TrimPrefix(line, "          ") + "\n")
+	}
+	scriptText := strings.ReplaceAll(script.String(), "/tmp/gh-aw/sandbox", sandboxDir)
+
+	cmd := exec.Command("bash", "-e", "-c", scriptText)
+	cmd.Env = append(os.Environ(), "GITHUB_WORKSPACE="+workspace)
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	mounts, err := os.ReadFile(filepath.Join(sandboxDir, "mounts"))
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join([]string{
+		"--mount", workspace + "/.github/workflows:" + workspace + "/.github/workflows:ro",
+		"--mount", sandboxDir + "/denied:" + workspace + "/docs:ro",
+		"--mount", sandboxDir + "/denied-file:" + workspace + "/.env:ro",
+		"--mount", sandboxDir + "/denied:" + workspace + "/secrets:ro",
+		"--mount", sandboxDir + "/denied:" + workspace + "/missing.key:ro",
+	}, "\n")+"\n", string(mounts))
+	assert.Contains(t, string(output), "Protecting the missing read-only path "+workspace+"/docs")
+	assert.Contains(t, string(output), "Protecting the missing denied path "+workspace+"/missing.key")
+	assert.Contains(t, string(output), "Skipping the writable mount of "+workspace+"/.github/workflows/shared")
+	assert.NoDirExists(t, filepath.Join(workspace, "docs"), "missing paths should not be created")
+
+	deniedFile, err := os.Stat(filepath.Join(sandboxDir, "denied-file"))
+	require.NoError(t, err)
+	assert.Zero(t, deniedFile.Size())
+	assert.True(t, deniedFile.Mode().IsRegular())
+}
+
+func TestSandboxFilesystemValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		filesystem  string
+		errContains string
+	}{
+		{
+			name:        "empty policy",
+			filesystem:  "    read-only: []\n",
+			errContains: "at least one read-only, writable or denied path",
+		},
+		{
+			name:        "parent directory",
+			filesystem:  "    denied: [../secrets]\n",
+			errContains: "sandbox.filesystem.denied[0]",
+		},
+		{
+			name:        "duplicate path",
+			filesystem:  "    read-only: [docs]\n    denied: [./docs]\n",
+			errContains: "already listed in sandbox.filesystem.read-only",
+		},
+		{
+			name:        "read-only workspace",
+			filesystem:  "    read-only: [.]\n",
+			errContains: "workspace, home or root directory",
+		},
+		{
+			name:        "denied gh-aw directory",
+			filesystem:  "    denied: [/tmp/gh-aw/cache-memory]\n",
+			errContains: "/tmp/gh-aw is used by gh-aw",
+		},
+		{
+			name:        "writable inside denied",
+			filesystem:  "    denied: [secrets]\n    writable: [secrets/out]\n",
+			errContains: "inside the denied path secrets",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileTestWorkflow(t, "on: workflow_dispatch\nengine: claude\nsandbox:\n  filesystem:\n"+tt.filesystem)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
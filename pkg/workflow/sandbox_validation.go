@@ -3,6 +3,8 @@
 // This file contains domain-specific validation functions for sandbox configuration:
 //   - validateMountsSyntax() - Validates container mount syntax
 //   - validateSandboxConfig() - Validates complete sandbox configuration
+//   - validateSandboxFilesystem() - Validates the filesystem policy of the agent sandbox
+//   - validateMCPCassetteConfig() - Validates MCP gateway record/replay configuration (mcp_cassette.go)
 //
 // These validation functions are organized in a dedicated file following the validation
 // architecture pattern where domain-specific validation belongs in domain validation files.
@@ -12,6 +14,8 @@ package workflow
 
 import (
 	"fmt"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/github/gh-aw/pkg/constants"
@@ -75,6 +79,131 @@ func validateMountsSyntax(mounts []string) error {
 	return nil
 }
 
+// protectedSandboxPaths are the directories used by gh-aw itself, which the filesystem policy
+// cannot make read-only or deny
+var protectedSandboxPaths = []string{"/tmp/gh-aw", "/opt/gh-aw"}
+
+// validateSandboxFilesystem validates the paths of the filesystem policy and checks that they
+// don't conflict with each other
+func validateSandboxFilesystem(policy *SandboxFilesystemConfig) error {
+	if len(policy.ReadOnly) == 0 && len(policy.Writable) == 0 && len(policy.Denied) == 0 {
+		return NewValidationError(
+			"sandbox.filesystem",
+			"",
+			"filesystem policy must list at least one read-only, writable or denied path",
+			fmt.Sprintf("Example:\nsandbox:\n  filesystem:\n    read-only:\n      - .github/workflows\n    denied:\n      - ~/.ssh\n\nSee: %s", constants.DocsSandboxURL),
+		)
+	}
+
+	seen := make(map[string]string)
+	lists := []struct {
+		field string
+		paths []string
+	}{
+		{"read-only", policy.ReadOnly},
+		{"writable", policy.Writable},
+		{"denied", policy.Denied},
+	}
+	for _, list := range lists {
+		for i, p := range list.paths {
+			field := fmt.Sprintf("sandbox.filesystem.%s[%d]", list.field, i)
+			if err := validateSandboxPath(field, p); err != nil {
+				return err
+			}
+			cleaned := cleanSandboxPath(p)
+			if previous, exists := seen[cleaned]; exists {
+				return NewValidationError(
+					field,
+					p,
+					fmt.Sprintf("path is already listed in sandbox.filesystem.%s", previous),
+					"List each path once: writable paths can re-open sub-paths of read-only paths.",
+				)
+			}
+			seen[cleaned] = list.field
+
+			if list.field != "writable" {
+				if cleaned == "." || cleaned == "/" || cleaned == "~" {
+					return NewValidationError(
+						field,
+						p,
+						fmt.Sprintf("the agent cannot run with the workspace, home or root directory %s", list.field),
+						"Protect the specific directories the agent must not change, such as .github/workflows.",
+					)
+				}
+				for _, protected := range protectedSandboxPaths {
+					if isSandboxSubPath(cleaned, protected) || isSandboxSubPath(protected, cleaned) {
+						return NewValidationError(
+							field,
+							p,
+							fmt.Sprintf("%s is used by gh-aw during the run and cannot be %s", protected, list.field),
+							"Remove the path from the filesystem policy.",
+						)
+					}
+				}
+			}
+		}
+	}
+
+	for i, writable := range policy.Writable {
+		for _, denied := range policy.Denied {
+			if isSandboxSubPath(cleanSandboxPath(writable), cleanSandboxPath(denied)) {
+				return NewValidationError(
+					fmt.Sprintf("sandbox.filesystem.writable[%d]", i),
+					writable,
+					fmt.Sprintf("writable path is inside the denied path %s", denied),
+					"Remove the path from writable or from denied.",
+				)
+			}
+		}
+	}
+
+	sandboxValidationLog.Printf("Validated filesystem policy: read-only=%d, writable=%d, denied=%d",
+		len(policy.ReadOnly), len(policy.Writable), len(policy.Denied))
+	return nil
+}
+
+// validateSandboxPath validates the syntax of a path of the filesystem policy
+func validateSandboxPath(field string, p string) error {
+	if strings.TrimSpace(p) == "" {
+		return NewValidationError(field, p, "path cannot be empty", "Remove the empty entry from the filesystem policy.")
+	}
+	if strings.ContainsAny(p, ":*?\"'`$\\ \t\n") {
+		return NewValidationError(
+			field,
+			p,
+			"path cannot contain spaces, wildcards, quotes, colons or $",
+			"Use a plain directory or file path such as .github/workflows or ~/.ssh.",
+		)
+	}
+	if strings.HasPrefix(p, "~") && p != "~" && !strings.HasPrefix(p, "~/") {
+		return NewValidationError(field, p, "only the home directory of the runner (~/) is supported", "Use a path such as ~/.ssh.")
+	}
+	if slices.Contains(strings.Split(p, "/"), "..") {
+		return NewValidationError(field, p, "path cannot contain '..'", "Use a path relative to the repository root, an absolute path or a path under ~/.")
+	}
+	return nil
+}
+
+// cleanSandboxPath returns the canonical form of a path of the filesystem policy
+func cleanSandboxPath(p string) string {
+	cleaned := path.Clean(p)
+	if !path.IsAbs(cleaned) && !strings.HasPrefix(cleaned, "~") && cleaned != "." {
+		cleaned = strings.TrimPrefix(cleaned, "./")
+	}
+	return cleaned
+}
+
+// isSandboxSubPath reports whether p is base or a path inside base
+func isSandboxSubPath(p string, base string) bool {
+	if p == base {
+		return true
+	}
+	if base == "." {
+		return !path.IsAbs(p) && !strings.HasPrefix(p, "~")
+	}
+	return strings.HasPrefix(p, strings.TrimSuffix(base, "/")+"/")
+}
+
 // validateSandboxConfig validates the sandbox configuration
 // Returns an error if the configuration is invalid
 func validateSandboxConfig(workflowData *WorkflowData) error {
@@ -105,6 +234,21 @@ func validateSandboxConfig(workflowData *WorkflowData) error {
 		}
 	}
 
+	// Validate the filesystem policy (sandbox.filesystem)
+	if sandboxConfig.Filesystem != nil {
+		if sandboxConfig.Agent != nil && sandboxConfig.Agent.Disabled {
+			return NewConfigurationError(
+				"sandbox.filesystem",
+				"sandbox.agent: false",
+				"the filesystem policy is enforced by the agent sandbox, which is disabled",
+				fmt.Sprintf("Remove 'sandbox.agent: false' or the 'sandbox.filesystem' section.\n\nSee: %s", constants.DocsSandboxURL),
+			)
+		}
+		if err := validateSandboxFilesystem(sandboxConfig.Filesystem); err != nil {
+			return err
+		}
+	}
+
 	// Validate that SRT is only used with Copilot engine
 	if isSRTEnabled(workflowData) {
 		// Check if the sandbox-runtime feature flag is enabled
@@ -161,6 +305,13 @@ func validateSandboxConfig(workflowData *WorkflowData) error {
 		sandboxValidationLog.Printf("Validated MCP gateway port: %d", sandboxConfig.MCP.Port)
 	}
 
+	// Validate MCP gateway record/replay configuration
+	if sandboxConfig.MCP != nil {
+		if err := validateMCPCassetteConfig(sandboxConfig.MCP.Cassette); err != nil {
+			return err
+		}
+	}
+
 	// Validate that if agent sandbox is enabled, MCP gateway is always enabled
 	// The MCP gateway is enabled when MCP servers are configured (tools that use MCP)
 	// Only validate this when sandbox is explicitly configured (not nil)
@@ -0,0 +1,421 @@
+// This file provides software bill of materials (SBOM) collection for compiled workflows.
+//
+// # Software Bill of Materials
+//
+// A compiled lock file pulls in third-party software from several places:
+//   - Pinned GitHub Actions (uses: owner/repo@sha)
+//   - Container images for MCP servers, the AWF firewall and the MCP gateway
+//   - The agentic engine CLI and the AWF binary
+//   - npm, pip, uv and Go packages installed by steps or MCP servers
+//   - Language runtimes set up by the compiler
+//   - Remote imports (owner/repo/path@ref)
+//
+// BuildSBOM collects these components from the compiled lock file content (which is
+// authoritative for what actually runs) and from the parsed WorkflowData (for packages,
+// runtimes and imports). Each component records the frontmatter location it originates
+// from, so supply-chain tooling can trace a dependency back to the workflow source.
+//
+// See sbom_format.go for CycloneDX and SPDX serialization.
+
+package workflow
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/console"
+	"github.com/github/gh-aw/pkg/constants"
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var sbomLog = logger.New("workflow:sbom")
+
+// SBOMComponentType identifies the kind of software component in an SBOM
+type SBOMComponentType string
+
+const (
+	SBOMComponentAction    SBOMComponentType = "github-action"
+	SBOMComponentContainer SBOMComponentType = "container"
+	SBOMComponentNpm       SBOMComponentType = "npm"
+	SBOMComponentPyPI      SBOMComponentType = "pypi"
+	SBOMComponentGo        SBOMComponentType = "golang"
+	SBOMComponentRuntime   SBOMComponentType = "runtime"
+	SBOMComponentBinary    SBOMComponentType = "binary"
+	SBOMComponentImport    SBOMComponentType = "import"
+)
+
+// SBOMComponent is a single third-party component used by a compiled workflow
+type SBOMComponent struct {
+	Type    SBOMComponentType `json:"type"`
+	Name    string            `json:"name"`
+	Version string            `json:"version,omitempty"`
+	Digest  string            `json:"digest,omitempty"` // Commit SHA for actions, sha256:... for pinned images
+	PURL    string            `json:"purl,omitempty"`
+	Origin  string            `json:"origin"` // Frontmatter location the component comes from (e.g. tools.github)
+}
+
+// SBOM is the bill of materials of a single compiled workflow
+type SBOM struct {
+	WorkflowID  string
+	LockFile    string
+	ToolVersion string
+	Created     time.Time
+	Components  []SBOMComponent
+}
+
+var (
+	sbomActionPattern       = regexp.MustCompile(`(?m)uses:\s+([A-Za-z0-9_.-]+/[A-Za-z0-9_./-]+)@([0-9a-f]{40})(?:\s+#\s+(\S+))?`)
+	sbomDockerImagesPattern = regexp.MustCompile(`download_docker_images\.sh\s+([^\n"]+)`)
+	sbomCopilotCLIPattern   = regexp.MustCompile(`install_copilot_cli\.sh\s+(\S+)`)
+	sbomAWFBinaryPattern    = regexp.MustCompile(`install_awf_binary\.sh\s+(\S+)`)
+	sbomNpmGlobalPattern    = regexp.MustCompile(`npm install -g (?:--silent )?((?:@[A-Za-z0-9_.-]+/)?[A-Za-z0-9_.-]+)@([A-Za-z0-9_.-]+)`)
+)
+
+// BuildSBOM collects the components of a compiled workflow from its lock file content
+// and parsed workflow data
+func BuildSBOM(workflowData *WorkflowData, lockContent string) *SBOM {
+	sbomLog.Printf("Building SBOM for workflow %s", workflowData.WorkflowID)
+
+	sbom := &SBOM{WorkflowID: workflowData.WorkflowID}
+	seen := make(map[string]bool)
+	add := func(component SBOMComponent) {
+		key := string(component.Type) + ":" + component.Name + "@" + component.Version
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		component.PURL = sbomPackageURL(component)
+		sbom.Components = append(sbom.Components, component)
+	}
+
+	runtimeRequirements := DetectRuntimeRequirements(workflowData)
+
+	// Pinned actions from the compiled lock file
+	for _, match := range sbomActionPattern.FindAllStringSubmatch(lockContent, -1) {
+		add(SBOMComponent{
+			Type:    SBOMComponentAction,
+			Name:    match[1],
+			Version: match[3],
+			Digest:  match[2],
+			Origin:  sbomActionOrigin(match[1], workflowData, runtimeRequirements),
+		})
+	}
+
+	// Container images pulled before the agent runs
+	for _, match := range sbomDockerImagesPattern.FindAllStringSubmatch(lockContent, -1) {
+		for _, image := range strings.Fields(match[1]) {
+			name, version, digest := splitContainerImage(image)
+			add(SBOMComponent{
+				Type:    SBOMComponentContainer,
+				Name:    name,
+				Version: version,
+				Digest:  digest,
+				Origin:  sbomImageOrigin(image, workflowData),
+			})
+		}
+	}
+
+	// Engine CLI and AWF binary installed by the compiler
+	for _, match := range sbomCopilotCLIPattern.FindAllStringSubmatch(lockContent, -1) {
+		add(SBOMComponent{Type: SBOMComponentNpm, Name: "@github/copilot", Version: match[1], Origin: "engine"})
+	}
+	for _, match := range sbomNpmGlobalPattern.FindAllStringSubmatch(lockContent, -1) {
+		origin := "engine"
+		if strings.Contains(workflowData.CustomSteps, match[0]) {
+			origin = "steps"
+		}
+		add(SBOMComponent{Type: SBOMComponentNpm, Name: match[1], Version: match[2], Origin: origin})
+	}
+	for _, match := range sbomAWFBinaryPattern.FindAllStringSubmatch(lockContent, -1) {
+		add(SBOMComponent{Type: SBOMComponentBinary, Name: "github/gh-aw-firewall", Version: match[1], Origin: "sandbox.agent"})
+	}
+
+	// Packages installed by steps or launched by MCP servers
+	for _, pkg := range extractNpxPackages(workflowData) {
+		dep := parseNpmPackage(pkg)
+		add(SBOMComponent{Type: SBOMComponentNpm, Name: dep.Name, Version: dep.Version, Origin: sbomPackageOrigin(pkg, workflowData)})
+	}
+	for _, pkg := range append(extractPipPackages(workflowData), extractUvPackages(workflowData)...) {
+		dep := parsePipPackage(pkg)
+		add(SBOMComponent{Type: SBOMComponentPyPI, Name: dep.Name, Version: strings.TrimPrefix(dep.Version, "=="), Origin: sbomPackageOrigin(pkg, workflowData)})
+	}
+	for _, pkg := range extractGoPackages(workflowData) {
+		dep := parseGoPackage(pkg)
+		add(SBOMComponent{Type: SBOMComponentGo, Name: dep.Path, Version: dep.Version, Origin: sbomPackageOrigin(pkg, workflowData)})
+	}
+
+	// Language runtimes
+	for _, req := range runtimeRequirements {
+		version := req.Version
+		if version == "" {
+			version = req.Runtime.DefaultVersion
+		}
+		origin := "steps"
+		if _, ok := workflowData.Runtimes[req.Runtime.ID]; ok {
+			origin = "runtimes." + req.Runtime.ID
+		}
+		add(SBOMComponent{Type: SBOMComponentRuntime, Name: req.Runtime.ID, Version: version, Origin: origin})
+	}
+
+	// Remote imports
+	for _, importPath := range workflowData.ImportedFiles {
+		if !isRemoteImportSpec(importPath) {
+			continue
+		}
+		spec, ref, _ := strings.Cut(importPath, "@")
+		add(SBOMComponent{Type: SBOMComponentImport, Name: spec, Version: ref, Origin: "imports"})
+	}
+
+	sortSBOMComponents(sbom.Components)
+	sbomLog.Printf("Collected %d SBOM components", len(sbom.Components))
+	return sbom
+}
+
+// SBOMCreationTime returns the creation time recorded in SBOMs. The wall clock would rewrite
+// the SBOM on every compile, so the time is only taken from SOURCE_DATE_EPOCH (see
+// https://reproducible-builds.org/specs/source-date-epoch/) and is zero when it is not set.
+func SBOMCreationTime() time.Time {
+	epoch := strings.TrimSpace(os.Getenv("SOURCE_DATE_EPOCH"))
+	if epoch == "" {
+		return time.Time{}
+	}
+	seconds, err := strconv.ParseInt(epoch, 10, 64)
+	if err != nil {
+		sbomLog.Printf("Ignoring invalid SOURCE_DATE_EPOCH %q: %v", epoch, err)
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0).UTC()
+}
+
+// SBOMFilePath returns the path of the SBOM written next to a lock file
+func SBOMFilePath(lockFile string) string {
+	return strings.TrimSuffix(lockFile, ".lock.yml") + ".sbom.json"
+}
+
+// writeSBOM writes the SBOM of the compiled workflow next to its lock file when enabled
+func (c *Compiler) writeSBOM(workflowData *WorkflowData, lockFile string, lockContent string) error {
+	if c.sbomFormat == "" || c.noEmit {
+		return nil
+	}
+
+	sbom := BuildSBOM(workflowData, lockContent)
+	sbom.LockFile = filepath.ToSlash(console.ToRelativePath(lockFile))
+	sbom.ToolVersion = c.version
+	sbom.Created = SBOMCreationTime()
+
+	content, err := sbom.Marshal(c.sbomFormat)
+	if err != nil {
+		return formatCompilerError(lockFile, "error", err.Error(), err)
+	}
+
+	sbomPath := SBOMFilePath(lockFile)
+	_, statErr := os.Stat(sbomPath)
+	if err := os.WriteFile(sbomPath, content, 0644); err != nil {
+		return formatCompilerError(sbomPath, "error", fmt.Sprintf("failed to write SBOM: %v", err), err)
+	}
+	sbomLog.Printf("Wrote %s SBOM with %d components to %s", c.sbomFormat, len(sbom.Components), sbomPath)
+
+	if os.IsNotExist(statErr) && c.fileTracker != nil {
+		c.fileTracker.TrackCreated(sbomPath)
+	}
+	return nil
+}
+
+// sortSBOMComponents orders components by type, then name, then version
+func sortSBOMComponents(components []SBOMComponent) {
+	sort.SliceStable(components, func(i, j int) bool {
+		if components[i].Type != components[j].Type {
+			return components[i].Type < components[j].Type
+		}
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].Version < components[j].Version
+	})
+}
+
+// isRemoteImportSpec reports whether an import path is a workflowspec (owner/repo/path@ref)
+func isRemoteImportSpec(importPath string) bool {
+	spec, _, hasRef := strings.Cut(importPath, "@")
+	if !hasRef || strings.HasPrefix(spec, ".") || strings.HasPrefix(spec, "/") || strings.HasPrefix(spec, "shared/") {
+		return false
+	}
+	return len(strings.Split(spec, "/")) >= 3
+}
+
+// splitContainerImage splits an image reference into name, tag and digest
+func splitContainerImage(image string) (name, version, digest string) {
+	name = image
+	if before, after, found := strings.Cut(name, "@"); found {
+		name, digest = before, after
+	}
+	// A colon after the last slash separates the tag (a colon before it is a registry port)
+	if idx := strings.LastIndex(name, ":"); idx > strings.LastIndex(name, "/") {
+		name, version = name[:idx], name[idx+1:]
+	}
+	return name, version, digest
+}
+
+// sbomActionOrigin returns the frontmatter location that introduces an action
+func sbomActionOrigin(repo string, workflowData *WorkflowData, runtimeRequirements []RuntimeRequirement) string {
+	for _, req := range runtimeRequirements {
+		if req.Runtime.ActionRepo == repo {
+			return "runtimes." + req.Runtime.ID
+		}
+	}
+	if strings.Contains(workflowData.CustomSteps, repo+"@") {
+		return "steps"
+	}
+	if strings.Contains(workflowData.PostSteps, repo+"@") {
+		return "post-steps"
+	}
+	return "compiler"
+}
+
+// sbomImageOrigin returns the frontmatter location that introduces a container image
+func sbomImageOrigin(image string, workflowData *WorkflowData) string {
+	switch {
+	case strings.HasPrefix(image, "ghcr.io/github/github-mcp-server"):
+		return "tools.github"
+	case strings.HasPrefix(image, "mcr.microsoft.com/playwright/"):
+		return "tools.playwright"
+	case strings.Contains(image, "serena"):
+		return "tools.serena"
+	case image == constants.DefaultNodeAlpineLTSImage:
+		return "safe-outputs"
+	case image == constants.DefaultAlpineImage:
+		return "tools.agentic-workflows"
+	case strings.HasPrefix(image, constants.DefaultFirewallRegistry+"/"):
+		return "sandbox.agent"
+	case strings.HasPrefix(image, constants.DefaultMCPGatewayContainer+":"):
+		return "sandbox.mcp"
+	}
+
+	if workflowData.SandboxConfig != nil && workflowData.SandboxConfig.MCP != nil &&
+		workflowData.SandboxConfig.MCP.Container != "" && strings.HasPrefix(image, workflowData.SandboxConfig.MCP.Container) {
+		return "sandbox.mcp"
+	}
+
+	for _, name := range sortedToolNames(workflowData.Tools) {
+		config, ok := workflowData.Tools[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		if container, ok := config["container"].(string); ok && strings.HasPrefix(image, container) {
+			return "mcp-servers." + name
+		}
+		if args, ok := config["args"].([]any); ok {
+			for _, arg := range args {
+				if arg == image {
+					return "mcp-servers." + name
+				}
+			}
+		}
+	}
+	return "compiler"
+}
+
+// sbomPackageOrigin returns the frontmatter location that installs or launches a package
+func sbomPackageOrigin(pkg string, workflowData *WorkflowData) string {
+	for _, name := range sortedToolNames(workflowData.Tools) {
+		switch config := workflowData.Tools[name].(type) {
+		case string:
+			if strings.Contains(config, pkg) {
+				return "mcp-servers." + name
+			}
+		case map[string]any:
+			if args, ok := config["args"].([]any); ok {
+				for _, arg := range args {
+					if arg == pkg {
+						return "mcp-servers." + name
+					}
+				}
+			}
+		}
+	}
+	if strings.Contains(workflowData.CustomSteps, pkg) {
+		return "steps"
+	}
+	return "engine.steps"
+}
+
+// sortedToolNames returns the tool names in sorted order for deterministic lookups
+func sortedToolNames(tools map[string]any) []string {
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sbomPackageURL builds the package URL (purl) identifying a component
+func sbomPackageURL(component SBOMComponent) string {
+	switch component.Type {
+	case SBOMComponentAction:
+		// owner/repo/sub/path@sha -> pkg:github/owner/repo@sha#sub/path
+		parts := strings.SplitN(component.Name, "/", 3)
+		purl := fmt.Sprintf("pkg:github/%s/%s@%s", parts[0], parts[1], component.Digest)
+		if len(parts) == 3 {
+			purl += "#" + parts[2]
+		}
+		return purl
+	case SBOMComponentContainer:
+		return containerPackageURL(component)
+	case SBOMComponentNpm:
+		return "pkg:npm/" + strings.Replace(component.Name, "@", "%40", 1) + purlVersion(component.Version)
+	case SBOMComponentPyPI:
+		version := component.Version
+		if strings.ContainsAny(version, "<>=!~") {
+			version = "" // Version ranges cannot be expressed in a purl
+		}
+		return "pkg:pypi/" + strings.ToLower(component.Name) + purlVersion(version)
+	case SBOMComponentGo:
+		return "pkg:golang/" + component.Name + purlVersion(component.Version)
+	case SBOMComponentBinary:
+		return "pkg:github/" + component.Name + purlVersion(component.Version)
+	case SBOMComponentImport:
+		parts := strings.SplitN(component.Name, "/", 3)
+		if len(parts) < 3 {
+			return ""
+		}
+		return fmt.Sprintf("pkg:github/%s/%s%s#%s", parts[0], parts[1], purlVersion(component.Version), parts[2])
+	}
+	return ""
+}
+
+// containerPackageURL builds a docker purl, recording non-Docker Hub registries as repository_url
+func containerPackageURL(component SBOMComponent) string {
+	name := component.Name
+	registry := ""
+	if first, rest, found := strings.Cut(name, "/"); found && strings.ContainsAny(first, ".:") {
+		registry, name = first, rest
+	} else if !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+
+	version := component.Version
+	if component.Digest != "" {
+		version = component.Digest
+	}
+	purl := "pkg:docker/" + name + purlVersion(version)
+	if registry != "" {
+		purl += "?repository_url=" + url.QueryEscape(registry)
+	}
+	return purl
+}
+
+// purlVersion formats the optional @version suffix of a purl
+func purlVersion(version string) string {
+	if version == "" || version == "latest" {
+		return ""
+	}
+	return "@" + url.PathEscape(version)
+}
@@ -0,0 +1,270 @@
+// This file provides CycloneDX and SPDX serialization of workflow SBOMs.
+//
+// Both formats are emitted as JSON:
+//   - CycloneDX 1.5 - components with purls, hashes and gh-aw:* properties
+//   - SPDX 2.3 - packages with purl external refs, checksums and DEPENDS_ON relationships
+//
+// The origin of each component in the workflow frontmatter is preserved as the
+// gh-aw:origin property (CycloneDX) or the package comment (SPDX).
+
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SBOMFormat is the serialization format of an SBOM
+type SBOMFormat string
+
+const (
+	SBOMFormatCycloneDX SBOMFormat = "cyclonedx"
+	SBOMFormatSPDX      SBOMFormat = "spdx"
+)
+
+// ParseSBOMFormat validates an SBOM format name
+func ParseSBOMFormat(format string) (SBOMFormat, error) {
+	switch SBOMFormat(strings.ToLower(format)) {
+	case SBOMFormatCycloneDX:
+		return SBOMFormatCycloneDX, nil
+	case SBOMFormatSPDX:
+		return SBOMFormatSPDX, nil
+	}
+	return "", fmt.Errorf("unsupported SBOM format '%s' (must be 'cyclonedx' or 'spdx')", format)
+}
+
+// Marshal serializes the SBOM in the given format
+func (s *SBOM) Marshal(format SBOMFormat) ([]byte, error) {
+	var document any
+	switch format {
+	case SBOMFormatCycloneDX:
+		document = s.cycloneDX()
+	case SBOMFormatSPDX:
+		document = s.spdx()
+	default:
+		return nil, fmt.Errorf("unsupported SBOM format '%s'", format)
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s SBOM: %w", format, err)
+	}
+	return append(data, '\n'), nil
+}
+
+type cycloneDXDocument struct {
+	BOMFormat   string               `json:"bomFormat"`
+	SpecVersion string               `json:"specVersion"`
+	Version     int                  `json:"version"`
+	Metadata    cycloneDXMetadata    `json:"metadata"`
+	Components  []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXMetadata struct {
+	Timestamp string             `json:"timestamp,omitempty"`
+	Tools     cycloneDXTools     `json:"tools"`
+	Component cycloneDXComponent `json:"component"`
+}
+
+type cycloneDXTools struct {
+	Components []cycloneDXComponent `json:"components"`
+}
+
+type cycloneDXComponent struct {
+	Type       string              `json:"type"`
+	BOMRef     string              `json:"bom-ref,omitempty"`
+	Name       string              `json:"name"`
+	Version    string              `json:"version,omitempty"`
+	PURL       string              `json:"purl,omitempty"`
+	Hashes     []cycloneDXHash     `json:"hashes,omitempty"`
+	Properties []cycloneDXProperty `json:"properties,omitempty"`
+}
+
+type cycloneDXHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cycloneDXProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (s *SBOM) cycloneDX() cycloneDXDocument {
+	doc := cycloneDXDocument{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cycloneDXMetadata{
+			Tools: cycloneDXTools{Components: []cycloneDXComponent{{Type: "application", Name: "gh-aw", Version: s.ToolVersion}}},
+			Component: cycloneDXComponent{
+				Type:   "application",
+				BOMRef: "workflow:" + s.WorkflowID,
+				Name:   s.WorkflowID,
+				Properties: []cycloneDXProperty{
+					{Name: "gh-aw:lock-file", Value: s.LockFile},
+				},
+			},
+		},
+		Components: []cycloneDXComponent{},
+	}
+	if !s.Created.IsZero() {
+		doc.Metadata.Timestamp = s.Created.UTC().Format(time.RFC3339)
+	}
+
+	for _, component := range s.Components {
+		cdx := cycloneDXComponent{
+			Type:    cycloneDXComponentType(component.Type),
+			BOMRef:  sbomComponentRef(component),
+			Name:    component.Name,
+			Version: component.Version,
+			PURL:    component.PURL,
+			Properties: []cycloneDXProperty{
+				{Name: "gh-aw:component-type", Value: string(component.Type)},
+				{Name: "gh-aw:origin", Value: component.Origin},
+			},
+		}
+		if alg, value := sbomDigestAlgorithm(component.Digest); alg != "" {
+			cdx.Hashes = []cycloneDXHash{{Alg: alg, Content: value}}
+		}
+		doc.Components = append(doc.Components, cdx)
+	}
+	return doc
+}
+
+// cycloneDXComponentType maps component types to CycloneDX component types
+func cycloneDXComponentType(componentType SBOMComponentType) string {
+	switch componentType {
+	case SBOMComponentContainer:
+		return "container"
+	case SBOMComponentRuntime:
+		return "platform"
+	case SBOMComponentImport:
+		return "file"
+	case SBOMComponentAction, SBOMComponentBinary:
+		return "application"
+	}
+	return "library"
+}
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+	Comment          string            `json:"comment,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func (s *SBOM) spdx() spdxDocument {
+	// SPDX requires a creation time: use the Unix epoch when none is set so the document
+	// stays reproducible
+	created := s.Created
+	if created.IsZero() {
+		created = time.Unix(0, 0)
+	}
+
+	// The namespace must be unique per document: derive it from the components
+	hash := sha256.New()
+	for _, component := range s.Components {
+		fmt.Fprintf(hash, "%s\n", sbomComponentRef(component))
+	}
+	namespace := fmt.Sprintf("https://github.github.com/gh-aw/spdx/%s-%s", s.WorkflowID, hex.EncodeToString(hash.Sum(nil))[:16])
+
+	rootID := "SPDXRef-Workflow"
+	doc := spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              s.WorkflowID,
+		DocumentNamespace: namespace,
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: gh-aw-" + s.ToolVersion},
+		},
+		Packages: []spdxPackage{{
+			Name:             s.WorkflowID,
+			SPDXID:           rootID,
+			DownloadLocation: "NOASSERTION",
+			Comment:          "lock file: " + s.LockFile,
+		}},
+		Relationships: []spdxRelationship{{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: rootID}},
+	}
+
+	for i, component := range s.Components {
+		pkg := spdxPackage{
+			Name:             component.Name,
+			SPDXID:           fmt.Sprintf("SPDXRef-Package-%d", i+1),
+			VersionInfo:      component.Version,
+			DownloadLocation: "NOASSERTION",
+			Comment:          fmt.Sprintf("type: %s; origin: %s", component.Type, component.Origin),
+		}
+		if alg, value := sbomDigestAlgorithm(component.Digest); alg != "" {
+			pkg.Checksums = []spdxChecksum{{Algorithm: strings.ReplaceAll(alg, "-", ""), ChecksumValue: value}}
+		}
+		if component.PURL != "" {
+			pkg.ExternalRefs = []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: component.PURL}}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		doc.Relationships = append(doc.Relationships, spdxRelationship{SPDXElementID: rootID, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: pkg.SPDXID})
+	}
+	return doc
+}
+
+// sbomComponentRef returns a stable reference for a component (its purl when available)
+func sbomComponentRef(component SBOMComponent) string {
+	if component.PURL != "" {
+		return component.PURL
+	}
+	return fmt.Sprintf("%s:%s@%s", component.Type, component.Name, component.Version)
+}
+
+// sbomDigestAlgorithm returns the CycloneDX hash algorithm and value of a digest.
+// Bare 40 character hex digests are git commit SHAs (SHA-1).
+func sbomDigestAlgorithm(digest string) (string, string) {
+	if value, ok := strings.CutPrefix(digest, "sha256:"); ok {
+		return "SHA-256", value
+	}
+	if len(digest) == 40 {
+		return "SHA-1", digest
+	}
+	return "", ""
+}
@@ -0,0 +1,217 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSBOMLockContent = `jobs:
+  agent:
+    steps:
+      - name: Checkout repository
+        uses: actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd # v6.0.2
+      - name: Setup Scripts
+        uses: ./actions/setup
+      - name: Restore cache
+        uses: actions/cache/restore@0057852bfaa89a56745cba8c7296529d2fc39830 # v4.3.0
+      - name: Install GitHub Copilot CLI
+        run: /opt/gh-aw/actions/install_copilot_cli.sh 0.0.410
+      - name: Install awf binary
+        run: bash /opt/gh-aw/actions/install_awf_binary.sh v0.18.0
+      - name: Download container images
+        run: bash /opt/gh-aw/actions/download_docker_images.sh ghcr.io/github/gh-aw-firewall/agent:0.18.0 ghcr.io/github/github-mcp-server:v0.30.3 node:lts-alpine mcp/fetch@sha256:0123456789abcdef
+      - name: Checkout again
+        uses: actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd # v6.0.2
+`
+
+func TestBuildSBOM(t *testing.T) {
+	data := &WorkflowData{
+		WorkflowID:  "triage",
+		CustomSteps: "steps:\n  - run: pip install requests==2.32.0\n",
+		Tools: map[string]any{
+			"github": map[string]any{},
+			"fetch":  map[string]any{"container": "mcp/fetch"},
+		},
+		ImportedFiles: []string{"shared/reporting.md", "githubnext/agentics/shared/tools.md@v1.2.0"},
+	}
+
+	sbom := BuildSBOM(data, testSBOMLockContent)
+	assert.Equal(t, "triage", sbom.WorkflowID)
+
+	byName := make(map[string]SBOMComponent)
+	for _, component := range sbom.Components {
+		byName[component.Name] = component
+	}
+
+	checkout := byName["actions/checkout"]
+	assert.Equal(t, SBOMComponentAction, checkout.Type)
+	assert.Equal(t, "v6.0.2", checkout.Version)
+	assert.Equal(t, "de0fac2e4500dabe0009e67214ff5f5447ce83dd", checkout.Digest)
+	assert.Equal(t, "pkg:github/actions/checkout@de0fac2e4500dabe0009e67214ff5f5447ce83dd", checkout.PURL)
+	assert.Equal(t, "compiler", checkout.Origin)
+	assert.Equal(t, "pkg:github/actions/cache@0057852bfaa89a56745cba8c7296529d2fc39830#restore", byName["actions/cache/restore"].PURL)
+	assert.NotContains(t, byName, "./actions/setup", "local actions are not third-party components")
+
+	assert.Equal(t, "tools.github", byName["ghcr.io/github/github-mcp-server"].Origin)
+	assert.Equal(t, "pkg:docker/github/github-mcp-server@v0.30.3?repository_url=ghcr.io", byName["ghcr.io/github/github-mcp-server"].PURL)
+	assert.Equal(t, "sandbox.agent", byName["ghcr.io/github/gh-aw-firewall/agent"].Origin)
+	assert.Equal(t, "safe-outputs", byName["node"].Origin)
+	assert.Equal(t, "pkg:docker/library/node@lts-alpine", byName["node"].PURL)
+
+	fetch := byName["mcp/fetch"]
+	assert.Equal(t, "sha256:0123456789abcdef", fetch.Digest)
+	assert.Equal(t, "mcp-servers.fetch", fetch.Origin)
+
+	assert.Equal(t, SBOMComponent{Type: SBOMComponentNpm, Name: "@github/copilot", Version: "0.0.410", PURL: "pkg:npm/%40github/copilot@0.0.410", Origin: "engine"}, byName["@github/copilot"])
+	assert.Equal(t, "v0.18.0", byName["github/gh-aw-firewall"].Version)
+
+	requests := byName["requests"]
+	assert.Equal(t, SBOMComponentPyPI, requests.Type)
+	assert.Equal(t, "2.32.0", requests.Version)
+	assert.Equal(t, "steps", requests.Origin)
+
+	assert.Equal(t, SBOMComponentRuntime, byName["python"].Type, "pip usage should add the python runtime")
+
+	imported := byName["githubnext/agentics/shared/tools.md"]
+	assert.Equal(t, SBOMComponentImport, imported.Type)
+	assert.Equal(t, "v1.2.0", imported.Version)
+	assert.Equal(t, "pkg:github/githubnext/agentics@v1.2.0#shared/tools.md", imported.PURL)
+	assert.NotContains(t, byName, "shared/reporting.md", "local imports are not third-party components")
+
+	count := 0
+	for _, component := range sbom.Components {
+		if component.Name == "actions/checkout" {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count, "duplicate components should be collapsed")
+}
+
+func TestSplitContainerImage(t *testing.T) {
+	tests := []struct {
+		image   string
+		name    string
+		version string
+		digest  string
+	}{
+		{image: "node:lts-alpine", name: "node", version: "lts-alpine"},
+		{image: "mcr.microsoft.com/playwright/mcp", name: "mcr.microsoft.com/playwright/mcp"},
+		{image: "localhost:5000/tool:1.0", name: "localhost:5000/tool", version: "1.0"},
+		{image: "mcp/fetch@sha256:abc", name: "mcp/fetch", digest: "sha256:abc"},
+		{image: "ghcr.io/org/tool:v1@sha256:abc", name: "ghcr.io/org/tool", version: "v1", digest: "sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			name, version, digest := splitContainerImage(tt.image)
+			assert.Equal(t, tt.name, name)
+			assert.Equal(t, tt.version, version)
+			assert.Equal(t, tt.digest, digest)
+		})
+	}
+}
+
+func TestSBOMMarshal(t *testing.T) {
+	sbom := BuildSBOM(&WorkflowData{WorkflowID: "triage"}, testSBOMLockContent)
+	sbom.LockFile = ".github/workflows/triage.lock.yml"
+	sbom.ToolVersion = "1.0.0"
+
+	t.Run("cyclonedx", func(t *testing.T) {
+		content, err := sbom.Marshal(SBOMFormatCycloneDX)
+		require.NoError(t, err)
+
+		var doc cycloneDXDocument
+		require.NoError(t, json.Unmarshal(content, &doc))
+		assert.Equal(t, "CycloneDX", doc.BOMFormat)
+		assert.Equal(t, "1.5", doc.SpecVersion)
+		assert.Equal(t, "triage", doc.Metadata.Component.Name)
+		assert.Empty(t, doc.Metadata.Timestamp, "timestamp should be omitted when not set")
+		require.Len(t, doc.Components, len(sbom.Components))
+
+		for _, component := range doc.Components {
+			if component.Name == "actions/checkout" {
+				assert.Equal(t, []cycloneDXHash{{Alg: "SHA-1", Content: "de0fac2e4500dabe0009e67214ff5f5447ce83dd"}}, component.Hashes)
+				assert.Contains(t, component.Properties, cycloneDXProperty{Name: "gh-aw:origin", Value: "compiler"})
+			}
+		}
+	})
+
+	t.Run("spdx", func(t *testing.T) {
+		content, err := sbom.Marshal(SBOMFormatSPDX)
+		require.NoError(t, err)
+
+		var doc spdxDocument
+		require.NoError(t, json.Unmarshal(content, &doc))
+		assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+		assert.Equal(t, "1970-01-01T00:00:00Z", doc.CreationInfo.Created, "creation time should be reproducible when not set")
+		assert.Equal(t, []string{"Tool: gh-aw-1.0.0"}, doc.CreationInfo.Creators)
+		require.Len(t, doc.Packages, len(sbom.Components)+1, "packages should include the workflow itself")
+		assert.Len(t, doc.Relationships, len(sbom.Components)+1)
+		assert.Equal(t, "DESCRIBES", doc.Relationships[0].RelationshipType)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := ParseSBOMFormat("swid")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported SBOM format 'swid'")
+	})
+}
+
+func TestCompileWorkflowWithSBOM(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "sbom-compile")
+
+	workflowContent := `---
+on: workflow_dispatch
+engine: copilot
+permissions:
+  contents: read
+---
+
+# SBOM Test
+`
+	workflowPath := filepath.Join(tmpDir, "sbom-test.md")
+	require.NoError(t, os.WriteFile(workflowPath, []byte(workflowContent), 0644))
+
+	compiler := NewCompiler(WithSBOMFormat(SBOMFormatSPDX))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "sbom-test.sbom.json"))
+	require.NoError(t, err, "SBOM should be written next to the lock file")
+
+	var doc spdxDocument
+	require.NoError(t, json.Unmarshal(content, &doc))
+	assert.Equal(t, "sbom-test", doc.Name)
+	assert.Greater(t, len(doc.Packages), 1)
+
+	// Recompiling does not rewrite the SBOM
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	recompiled, err := os.ReadFile(filepath.Join(tmpDir, "sbom-test.sbom.json"))
+	require.NoError(t, err)
+	assert.Equal(t, string(content), string(recompiled), "SBOM should be reproducible")
+
+	// No SBOM is written when validating only
+	require.NoError(t, os.Remove(filepath.Join(tmpDir, "sbom-test.sbom.json")))
+	compiler = NewCompiler(WithSBOMFormat(SBOMFormatSPDX), WithNoEmit(true))
+	require.NoError(t, compiler.CompileWorkflow(workflowPath))
+	assert.NoFileExists(t, filepath.Join(tmpDir, "sbom-test.sbom.json"))
+}
+
+func TestSBOMCreationTime(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	assert.True(t, SBOMCreationTime().IsZero(), "creation time should be zero without SOURCE_DATE_EPOCH")
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	assert.Equal(t, "2023-11-14T22:13:20Z", SBOMCreationTime().Format(time.RFC3339))
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	assert.True(t, SBOMCreationTime().IsZero(), "invalid SOURCE_DATE_EPOCH should be ignored")
+}
@@ -1,5 +1,14 @@
 package workflow
 
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/require"
+)
+
 // boolPtr returns a pointer to a bool value.
 // This is a shared helper used by both unit and integration tests.
 func boolPtr(b bool) *bool {
@@ -15,3 +24,21 @@ type mockValidationError struct {
 func (m *mockValidationError) Error() string {
 	return m.msg
 }
+
+// compileTestWorkflow compiles a workflow with the given frontmatter in a temporary directory
+// and returns the content of its lock file, or the compilation error.
+// This is a shared helper used by both unit and integration tests.
+func compileTestWorkflow(t *testing.T, frontmatter string) (string, error) {
+	t.Helper()
+	tmpDir := testutil.TempDir(t, "compile-workflow")
+	workflowPath := filepath.Join(tmpDir, "workflow.md")
+	content := "---\n" + frontmatter + "---\n\n# Workflow\n\nDo the task.\n"
+	require.NoError(t, os.WriteFile(workflowPath, []byte(content), 0644))
+
+	if err := NewCompiler().CompileWorkflow(workflowPath); err != nil {
+		return "", err
+	}
+	lockContent, err := os.ReadFile(filepath.Join(tmpDir, "workflow.lock.yml"))
+	require.NoError(t, err)
+	return string(lockContent), nil
+}
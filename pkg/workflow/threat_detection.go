@@ -350,6 +350,7 @@ func (c *Compiler) buildEngineSteps(data *WorkflowData) []string {
 				Model:       "", // Explicitly leave empty for env var mechanism
 				Version:     detectionEngineConfig.Version,
 				MaxTurns:    detectionEngineConfig.MaxTurns,
+				BaseURL:     detectionEngineConfig.BaseURL,
 				Concurrency: detectionEngineConfig.Concurrency,
 				UserAgent:   detectionEngineConfig.UserAgent,
 				Env:         detectionEngineConfig.Env,
@@ -367,10 +368,11 @@ func (c *Compiler) buildEngineSteps(data *WorkflowData) []string {
 		Tools: map[string]any{
 			"bash": []any{"cat", "head", "tail", "wc", "grep", "ls", "jq"},
 		},
-		SafeOutputs:  nil,
-		Network:      "",
-		EngineConfig: detectionEngineConfig,
-		AI:           engineSetting,
+		SafeOutputs:     nil,
+		Network:         "",
+		EngineConfig:    detectionEngineConfig,
+		AI:              engineSetting,
+		ThreatDetection: true,
 	}
 
 	var steps []string
@@ -395,6 +397,13 @@ func (c *Compiler) buildEngineSteps(data *WorkflowData) []string {
 	return steps
 }
 
+// isThreatDetectionData reports whether the engine steps are generated for the threat detection
+// job. The flag is set explicitly, since agent runs without safe outputs (such as phases with
+// safe-outputs: false) must still read the agent model.
+func isThreatDetectionData(workflowData *WorkflowData) bool {
+	return workflowData.ThreatDetection
+}
+
 // buildParsingStep creates the results parsing step
 func (c *Compiler) buildParsingStep() []string {
 	steps := []string{
@@ -0,0 +1,385 @@
+package workflow
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/github/gh-aw/pkg/logger"
+)
+
+var transcriptLog = logger.New("workflow:transcript")
+
+// Transcript message roles
+const (
+	TranscriptRoleUser      = "user"
+	TranscriptRoleAssistant = "assistant"
+	TranscriptRoleReasoning = "reasoning"
+)
+
+// TranscriptUsage is the token usage reported for a turn or a whole transcript.
+// TotalTokens is always set; engines that only report totals leave the breakdown empty.
+type TranscriptUsage struct {
+	InputTokens         int `json:"input_tokens,omitempty"`
+	OutputTokens        int `json:"output_tokens,omitempty"`
+	CacheCreationTokens int `json:"cache_creation_tokens,omitempty"`
+	CacheReadTokens     int `json:"cache_read_tokens,omitempty"`
+	TotalTokens         int `json:"total_tokens,omitempty"`
+}
+
+// add accumulates another usage into this one
+func (u *TranscriptUsage) add(other TranscriptUsage) {
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.CacheCreationTokens += other.CacheCreationTokens
+	u.CacheReadTokens += other.CacheReadTokens
+	u.TotalTokens += other.TotalTokens
+}
+
+// TranscriptMessage is a text message exchanged during a turn
+type TranscriptMessage struct {
+	Role string `json:"role"`
+	Text string `json:"text"`
+}
+
+// TranscriptToolCall is a single tool invocation with its arguments and result
+type TranscriptToolCall struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Args     json.RawMessage `json:"args,omitempty"`
+	Result   string          `json:"result,omitempty"`
+	IsError  bool            `json:"is_error,omitempty"`
+	Duration time.Duration   `json:"duration,omitempty"`
+}
+
+// TranscriptTurn is one model response: the messages leading to it, the text the
+// model produced and the tools it called
+type TranscriptTurn struct {
+	Index     int                  `json:"index"`
+	Timestamp *time.Time           `json:"timestamp,omitempty"`
+	Messages  []TranscriptMessage  `json:"messages,omitempty"`
+	ToolCalls []TranscriptToolCall `json:"tool_calls,omitempty"`
+	Usage     TranscriptUsage      `json:"usage"`
+}
+
+// Transcript is the engine-independent conversation of an agent run
+type Transcript struct {
+	Engine   string           `json:"engine"`
+	Turns    []TranscriptTurn `json:"turns"`
+	Usage    TranscriptUsage  `json:"usage"`
+	Duration time.Duration    `json:"duration,omitempty"`
+}
+
+// TranscriptParser is implemented by engines that can reconstruct the agent conversation
+// from their logs
+type TranscriptParser interface {
+	// ParseTranscript builds a normalized transcript from engine-specific log content
+	ParseTranscript(logContent string, verbose bool) *Transcript
+}
+
+// transcriptBuilder accumulates turns while an engine log is being parsed
+type transcriptBuilder struct {
+	engine    string
+	turns     []*TranscriptTurn
+	pending   []TranscriptMessage          // Messages received before the turn they belong to
+	toolCalls map[string]transcriptCallRef // Tool calls by ID for result correlation
+	usage     TranscriptUsage              // Total usage when reported by the engine
+	duration  time.Duration
+}
+
+// transcriptCallRef locates a tool call within the builder's turns
+type transcriptCallRef struct {
+	turn  *TranscriptTurn
+	index int
+}
+
+func newTranscriptBuilder(engine string) *transcriptBuilder {
+	return &transcriptBuilder{
+		engine:    engine,
+		toolCalls: make(map[string]transcriptCallRef),
+	}
+}
+
+// startTurn opens a new turn, moving pending messages into it
+func (b *transcriptBuilder) startTurn(timestamp *time.Time) *TranscriptTurn {
+	turn := &TranscriptTurn{Index: len(b.turns) + 1, Timestamp: timestamp, Messages: b.pending}
+	b.pending = nil
+	b.turns = append(b.turns, turn)
+	return turn
+}
+
+// turn returns the current turn, opening one if needed
+func (b *transcriptBuilder) turn() *TranscriptTurn {
+	if len(b.turns) == 0 {
+		return b.startTurn(nil)
+	}
+	return b.turns[len(b.turns)-1]
+}
+
+// addMessage adds a message to the current turn, appending to the previous message
+// when it has the same role and no tool call came in between
+func (b *transcriptBuilder) addMessage(role, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	turn := b.turn()
+	if n := len(turn.Messages); n > 0 && turn.Messages[n-1].Role == role && len(turn.ToolCalls) == 0 {
+		turn.Messages[n-1].Text += "\n" + text
+		return
+	}
+	turn.Messages = append(turn.Messages, TranscriptMessage{Role: role, Text: text})
+}
+
+// addPendingMessage records a message that belongs to the next turn
+func (b *transcriptBuilder) addPendingMessage(role, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	b.pending = append(b.pending, TranscriptMessage{Role: role, Text: text})
+}
+
+// addToolCall appends a tool call to the current turn
+func (b *transcriptBuilder) addToolCall(call TranscriptToolCall) {
+	turn := b.turn()
+	turn.ToolCalls = append(turn.ToolCalls, call)
+	if call.ID != "" {
+		b.toolCalls[call.ID] = transcriptCallRef{turn: turn, index: len(turn.ToolCalls) - 1}
+	}
+}
+
+// lastToolCall returns the most recent tool call of the current turn, or nil
+func (b *transcriptBuilder) lastToolCall() *TranscriptToolCall {
+	if len(b.turns) == 0 {
+		return nil
+	}
+	turn := b.turns[len(b.turns)-1]
+	if len(turn.ToolCalls) == 0 {
+		return nil
+	}
+	return &turn.ToolCalls[len(turn.ToolCalls)-1]
+}
+
+// setToolResult records the result of a tool call by ID
+func (b *transcriptBuilder) setToolResult(id, result string, isError bool) {
+	ref, ok := b.toolCalls[id]
+	if !ok {
+		transcriptLog.Printf("No tool call found for result: id=%s", id)
+		return
+	}
+	call := &ref.turn.ToolCalls[ref.index]
+	call.Result = result
+	call.IsError = isError
+}
+
+// build finalizes the transcript; the total usage is summed from the turns unless the
+// engine reported it
+func (b *transcriptBuilder) build() *Transcript {
+	if len(b.pending) > 0 {
+		b.startTurn(nil)
+	}
+	transcript := &Transcript{Engine: b.engine, Turns: make([]TranscriptTurn, 0, len(b.turns)), Usage: b.usage, Duration: b.duration}
+	var summed TranscriptUsage
+	for _, turn := range b.turns {
+		summed.add(turn.Usage)
+		transcript.Turns = append(transcript.Turns, *turn)
+	}
+	if transcript.Usage.TotalTokens == 0 {
+		transcript.Usage = summed
+	}
+	transcriptLog.Printf("Built %s transcript: turns=%d, total_tokens=%d", transcript.Engine, len(transcript.Turns), transcript.Usage.TotalTokens)
+	return transcript
+}
+
+// transcriptArgs converts tool arguments to raw JSON, quoting values that are not valid JSON
+func transcriptArgs(args any) json.RawMessage {
+	switch v := args.(type) {
+	case nil:
+		return nil
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" {
+			return nil
+		}
+		if json.Valid([]byte(trimmed)) {
+			return json.RawMessage(trimmed)
+		}
+		quoted, _ := json.Marshal(v)
+		return quoted
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return data
+	}
+}
+
+// transcriptContentText flattens tool result content (a string or a list of text blocks)
+func transcriptContentText(content any) string {
+	switch v := content.(type) {
+	case string:
+		return v
+	case []any:
+		var parts []string
+		for _, item := range v {
+			if itemMap, ok := item.(map[string]any); ok {
+				if text, ok := itemMap["text"].(string); ok {
+					parts = append(parts, text)
+				}
+			}
+		}
+		return strings.Join(parts, "\n")
+	default:
+		return ""
+	}
+}
+
+// WriteTranscriptJSONL writes the transcript as JSON lines, one turn per line
+func WriteTranscriptJSONL(w io.Writer, transcript *Transcript) error {
+	encoder := json.NewEncoder(w)
+	for _, turn := range transcript.Turns {
+		if err := encoder.Encode(turn); err != nil {
+			return fmt.Errorf("failed to encode turn %d: %w", turn.Index, err)
+		}
+	}
+	return nil
+}
+
+// ReadTranscriptJSONL reads turns written by WriteTranscriptJSONL
+func ReadTranscriptJSONL(r io.Reader, engine string) (*Transcript, error) {
+	transcript := &Transcript{Engine: engine, Turns: []TranscriptTurn{}}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var turn TranscriptTurn
+		if err := json.Unmarshal([]byte(line), &turn); err != nil {
+			return nil, fmt.Errorf("failed to parse transcript line: %w", err)
+		}
+		transcript.Usage.add(turn.Usage)
+		transcript.Turns = append(transcript.Turns, turn)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	return transcript, nil
+}
+
+// RenderTranscriptMarkdown renders the transcript as Markdown with one section per turn.
+// Tool results longer than maxResultLength characters are truncated; 0 disables truncation.
+func RenderTranscriptMarkdown(transcript *Transcript, maxResultLength int) string {
+	var md strings.Builder
+
+	md.WriteString("# Transcript\n\n")
+	fmt.Fprintf(&md, "- **Engine**: %s\n", transcript.Engine)
+	fmt.Fprintf(&md, "- **Turns**: %d\n", len(transcript.Turns))
+	if transcript.Usage.TotalTokens > 0 {
+		fmt.Fprintf(&md, "- **Tokens**: %s\n", formatTranscriptUsage(transcript.Usage))
+	}
+	if transcript.Duration > 0 {
+		fmt.Fprintf(&md, "- **Duration**: %s\n", transcript.Duration.Round(time.Millisecond))
+	}
+
+	for _, turn := range transcript.Turns {
+		fmt.Fprintf(&md, "\n## Turn %d\n\n", turn.Index)
+		var details []string
+		if turn.Timestamp != nil {
+			details = append(details, turn.Timestamp.UTC().Format(time.RFC3339))
+		}
+		if turn.Usage.TotalTokens > 0 {
+			details = append(details, formatTranscriptUsage(turn.Usage))
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(&md, "_%s_\n\n", strings.Join(details, " · "))
+		}
+
+		for _, message := range turn.Messages {
+			switch message.Role {
+			case TranscriptRoleReasoning:
+				fmt.Fprintf(&md, "**Reasoning**\n\n%s\n\n", quoteMarkdown(message.Text))
+			default:
+				fmt.Fprintf(&md, "**%s**\n\n%s\n\n", capitalizeRole(message.Role), message.Text)
+			}
+		}
+
+		for _, call := range turn.ToolCalls {
+			status := ""
+			if call.IsError {
+				status = " (error)"
+			}
+			if call.Duration > 0 {
+				status += fmt.Sprintf(" in %s", call.Duration.Round(time.Millisecond))
+			}
+			fmt.Fprintf(&md, "**Tool** `%s`%s\n\n", call.Name, status)
+			if len(call.Args) > 0 {
+				fmt.Fprintf(&md, "```json\n%s\n```\n\n", indentTranscriptJSON(call.Args))
+			}
+			if call.Result != "" {
+				result := call.Result
+				if maxResultLength > 0 && len(result) > maxResultLength {
+					result = result[:maxResultLength] + fmt.Sprintf("\n... (%d more characters)", len(call.Result)-maxResultLength)
+				}
+				fmt.Fprintf(&md, "<details><summary>Result</summary>\n\n```\n%s\n```\n\n</details>\n\n", result)
+			}
+		}
+	}
+
+	return md.String()
+}
+
+// formatTranscriptUsage formats token usage as "N tokens (in X, out Y)"
+func formatTranscriptUsage(usage TranscriptUsage) string {
+	var parts []string
+	if usage.InputTokens > 0 {
+		parts = append(parts, fmt.Sprintf("in %d", usage.InputTokens))
+	}
+	if usage.OutputTokens > 0 {
+		parts = append(parts, fmt.Sprintf("out %d", usage.OutputTokens))
+	}
+	if usage.CacheReadTokens > 0 {
+		parts = append(parts, fmt.Sprintf("cache read %d", usage.CacheReadTokens))
+	}
+	if usage.CacheCreationTokens > 0 {
+		parts = append(parts, fmt.Sprintf("cache write %d", usage.CacheCreationTokens))
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("%d tokens", usage.TotalTokens)
+	}
+	return fmt.Sprintf("%d tokens (%s)", usage.TotalTokens, strings.Join(parts, ", "))
+}
+
+// quoteMarkdown prefixes every line with a Markdown blockquote marker
+func quoteMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = "> " + line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// capitalizeRole returns the display name of a message role
+func capitalizeRole(role string) string {
+	if role == "" {
+		return ""
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+// indentTranscriptJSON pretty-prints tool arguments, returning them unchanged if they are not valid JSON
+func indentTranscriptJSON(raw json.RawMessage) string {
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return string(raw)
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return string(raw)
+	}
+	return string(data)
+}
@@ -0,0 +1,226 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClaudeParseTranscript(t *testing.T) {
+	logContent := `[DEBUG] Starting Claude
+{"type":"system","subtype":"init","session_id":"abc","tools":["Bash"]}
+{"type":"user","message":{"role":"user","content":"Triage issue #42"}}
+{"type":"assistant","message":{"id":"msg_1","content":[{"type":"thinking","thinking":"I should read the issue."}],"usage":{"input_tokens":100,"output_tokens":20}}}
+{"type":"assistant","message":{"id":"msg_1","content":[{"type":"tool_use","id":"toolu_1","name":"mcp__github__issue_read","input":{"issue_number":42}}],"usage":{"input_tokens":100,"output_tokens":30}}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_1","content":[{"type":"text","text":"Bug: crash on start"}]}]}}
+{"type":"assistant","message":{"id":"msg_2","content":[{"type":"text","text":"This is a bug."},{"type":"tool_use","id":"toolu_2","name":"Bash","input":{"command":"false"}}],"usage":{"input_tokens":150,"output_tokens":10,"cache_read_input_tokens":50}}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"toolu_2","content":"exit 1","is_error":true}]}}
+{"type":"result","subtype":"success","duration_ms":1500,"num_turns":2,"usage":{"input_tokens":250,"output_tokens":40,"cache_read_input_tokens":50}}`
+
+	transcript := NewClaudeEngine().ParseTranscript(logContent, false)
+
+	assert.Equal(t, "claude", transcript.Engine)
+	require.Len(t, transcript.Turns, 2)
+
+	first := transcript.Turns[0]
+	assert.Equal(t, 1, first.Index)
+	assert.Equal(t, []TranscriptMessage{
+		{Role: TranscriptRoleUser, Text: "Triage issue #42"},
+		{Role: TranscriptRoleReasoning, Text: "I should read the issue."},
+	}, first.Messages)
+	require.Len(t, first.ToolCalls, 1)
+	assert.Equal(t, "mcp__github__issue_read", first.ToolCalls[0].Name)
+	assert.JSONEq(t, `{"issue_number":42}`, string(first.ToolCalls[0].Args))
+	assert.Equal(t, "Bug: crash on start", first.ToolCalls[0].Result)
+	assert.Equal(t, TranscriptUsage{InputTokens: 100, OutputTokens: 30, TotalTokens: 130}, first.Usage, "usage of the last entry of the message")
+
+	second := transcript.Turns[1]
+	assert.Equal(t, []TranscriptMessage{{Role: TranscriptRoleAssistant, Text: "This is a bug."}}, second.Messages)
+	require.Len(t, second.ToolCalls, 1)
+	assert.True(t, second.ToolCalls[0].IsError)
+	assert.Equal(t, "exit 1", second.ToolCalls[0].Result)
+
+	assert.Equal(t, 340, transcript.Usage.TotalTokens, "total usage from the result entry")
+	assert.Equal(t, 1500*time.Millisecond, transcript.Duration)
+}
+
+func TestCopilotParseTranscript(t *testing.T) {
+	t.Run("session JSONL", func(t *testing.T) {
+		logContent := `{"type":"system","subtype":"init","session_id":"copilot-test"}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Running a command."},{"type":"tool_use","id":"tool_1","name":"Bash","input":{"command":"echo hi"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"tool_1","content":"hi\n"}]}}
+{"type":"result","usage":{"input_tokens":150,"output_tokens":50},"num_turns":1}`
+
+		transcript := NewCopilotEngine().ParseTranscript(logContent, false)
+
+		assert.Equal(t, "copilot", transcript.Engine)
+		require.Len(t, transcript.Turns, 1)
+		require.Len(t, transcript.Turns[0].ToolCalls, 1)
+		assert.Equal(t, "hi\n", transcript.Turns[0].ToolCalls[0].Result)
+		assert.Equal(t, 200, transcript.Usage.TotalTokens)
+	})
+
+	t.Run("debug log", func(t *testing.T) {
+		logContent := `2025-09-26T11:13:11.798Z [DEBUG] Using model: claude-sonnet-4
+2025-09-26T11:13:17.989Z [DEBUG] data:
+2025-09-26T11:13:17.990Z [DEBUG] {
+2025-09-26T11:13:17.990Z [DEBUG]   "choices": [
+2025-09-26T11:13:17.990Z [DEBUG]     {
+2025-09-26T11:13:17.990Z [DEBUG]       "message": {
+2025-09-26T11:13:17.990Z [DEBUG]         "content": "Listing issues.",
+2025-09-26T11:13:17.990Z [DEBUG]         "tool_calls": [
+2025-09-26T11:13:17.990Z [DEBUG]           {
+2025-09-26T11:13:17.990Z [DEBUG]             "id": "call_1",
+2025-09-26T11:13:17.990Z [DEBUG]             "function": {
+2025-09-26T11:13:17.990Z [DEBUG]               "name": "github-list_issues",
+2025-09-26T11:13:17.990Z [DEBUG]               "arguments": "{\"state\":\"open\"}"
+2025-09-26T11:13:17.990Z [DEBUG]             }
+2025-09-26T11:13:17.990Z [DEBUG]           }
+2025-09-26T11:13:17.990Z [DEBUG]         ]
+2025-09-26T11:13:17.990Z [DEBUG]       }
+2025-09-26T11:13:17.990Z [DEBUG]     }
+2025-09-26T11:13:17.990Z [DEBUG]   ],
+2025-09-26T11:13:17.990Z [DEBUG]   "usage": {
+2025-09-26T11:13:17.990Z [DEBUG]     "prompt_tokens": 100,
+2025-09-26T11:13:17.990Z [DEBUG]     "completion_tokens": 50
+2025-09-26T11:13:17.990Z [DEBUG]   }
+2025-09-26T11:13:17.990Z [DEBUG] }
+2025-09-26T11:13:18.000Z [DEBUG] Executing tool: github-list_issues
+2025-09-26T11:13:20.000Z [DEBUG] data:
+2025-09-26T11:13:20.001Z [DEBUG] {
+2025-09-26T11:13:20.001Z [DEBUG]   "choices": [{"message": {"content": "Done."}}],
+2025-09-26T11:13:20.001Z [DEBUG]   "usage": {"prompt_tokens": 200, "completion_tokens": 10}
+2025-09-26T11:13:20.001Z [DEBUG] }`
+
+		transcript := NewCopilotEngine().ParseTranscript(logContent, false)
+
+		require.Len(t, transcript.Turns, 2)
+		first := transcript.Turns[0]
+		require.NotNil(t, first.Timestamp)
+		assert.Equal(t, time.Date(2025, 9, 26, 11, 13, 17, 989000000, time.UTC), *first.Timestamp)
+		assert.Equal(t, []TranscriptMessage{{Role: TranscriptRoleAssistant, Text: "Listing issues."}}, first.Messages)
+		require.Len(t, first.ToolCalls, 1)
+		assert.Equal(t, "github-list_issues", first.ToolCalls[0].Name)
+		assert.JSONEq(t, `{"state":"open"}`, string(first.ToolCalls[0].Args))
+		assert.Equal(t, 150, first.Usage.TotalTokens)
+		assert.Equal(t, "Done.", transcript.Turns[1].Messages[0].Text)
+		assert.Equal(t, 360, transcript.Usage.TotalTokens, "total usage summed from the turns")
+	})
+}
+
+func TestCodexParseTranscript(t *testing.T) {
+	logContent := `[2025-08-31T12:37:08] OpenAI Codex v0.27.0 (research preview)
+[2025-08-31T12:37:08] User instructions:
+Summarize the open pull requests.
+[2025-08-31T12:37:47] thinking
+I need to list the pull requests.
+[2025-08-31T12:37:49] tool github.list_pull_requests({"state":"open"})
+[2025-08-31T12:37:50] github.list_pull_requests({"state":"open"}) success in 175ms:
+{
+  "content": [
+    {
+      "text": "[{\"number\":1}]",
+      "type": "text"
+    }
+  ],
+  "isError": false
+}
+[2025-08-31T12:37:51] tokens used: 1000
+[2025-08-31T12:37:52] thinking
+Let me check the build.
+[2025-08-31T12:37:53] exec bash -lc 'make test' in /home/runner/work/repo
+[2025-08-31T12:37:55] bash -lc 'make test' exited 2 in 1.5s:
+FAIL
+[2025-08-31T12:37:56] codex
+There is one open pull request and the tests fail.
+[2025-08-31T12:37:57] tokens used: 500`
+
+	transcript := NewCodexEngine().ParseTranscript(logContent, false)
+
+	assert.Equal(t, "codex", transcript.Engine)
+	require.Len(t, transcript.Turns, 2)
+
+	first := transcript.Turns[0]
+	require.NotNil(t, first.Timestamp)
+	assert.Equal(t, time.Date(2025, 8, 31, 12, 37, 47, 0, time.UTC), *first.Timestamp)
+	assert.Equal(t, []TranscriptMessage{
+		{Role: TranscriptRoleUser, Text: "Summarize the open pull requests."},
+		{Role: TranscriptRoleReasoning, Text: "I need to list the pull requests."},
+	}, first.Messages)
+	require.Len(t, first.ToolCalls, 1)
+	assert.Equal(t, "github.list_pull_requests", first.ToolCalls[0].Name)
+	assert.JSONEq(t, `{"state":"open"}`, string(first.ToolCalls[0].Args))
+	assert.Equal(t, `[{"number":1}]`, first.ToolCalls[0].Result)
+	assert.Equal(t, 175*time.Millisecond, first.ToolCalls[0].Duration)
+	assert.False(t, first.ToolCalls[0].IsError)
+	assert.Equal(t, 1000, first.Usage.TotalTokens)
+
+	second := transcript.Turns[1]
+	require.Len(t, second.ToolCalls, 1)
+	assert.Equal(t, "bash", second.ToolCalls[0].Name)
+	assert.JSONEq(t, `{"command":"bash -lc 'make test'"}`, string(second.ToolCalls[0].Args))
+	assert.Equal(t, "FAIL", second.ToolCalls[0].Result)
+	assert.Equal(t, 1500*time.Millisecond, second.ToolCalls[0].Duration)
+	assert.True(t, second.ToolCalls[0].IsError)
+	assert.Equal(t, TranscriptMessage{Role: TranscriptRoleAssistant, Text: "There is one open pull request and the tests fail."}, second.Messages[len(second.Messages)-1])
+
+	assert.Equal(t, 1500, transcript.Usage.TotalTokens)
+}
+
+func TestTranscriptJSONLRoundTrip(t *testing.T) {
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	transcript := &Transcript{
+		Engine: "claude",
+		Turns: []TranscriptTurn{
+			{
+				Index:     1,
+				Timestamp: &timestamp,
+				Messages:  []TranscriptMessage{{Role: TranscriptRoleAssistant, Text: "Hello"}},
+				ToolCalls: []TranscriptToolCall{{ID: "t1", Name: "Bash", Args: json.RawMessage(`{"command":"ls"}`), Result: "a\nb", Duration: time.Second}},
+				Usage:     TranscriptUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15},
+			},
+			{Index: 2, Messages: []TranscriptMessage{{Role: TranscriptRoleAssistant, Text: "Done"}}, Usage: TranscriptUsage{TotalTokens: 5}},
+		},
+		Usage: TranscriptUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 20},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTranscriptJSONL(&buf, transcript))
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")), "one line per turn")
+
+	read, err := ReadTranscriptJSONL(&buf, "claude")
+	require.NoError(t, err)
+	assert.Equal(t, transcript, read)
+}
+
+func TestRenderTranscriptMarkdown(t *testing.T) {
+	transcript := &Transcript{
+		Engine: "codex",
+		Turns: []TranscriptTurn{{
+			Index: 1,
+			Messages: []TranscriptMessage{
+				{Role: TranscriptRoleReasoning, Text: "Think\nharder"},
+				{Role: TranscriptRoleAssistant, Text: "Answer"},
+			},
+			ToolCalls: []TranscriptToolCall{{Name: "bash", Args: json.RawMessage(`{"command":"ls"}`), Result: "0123456789", IsError: true}},
+			Usage:     TranscriptUsage{TotalTokens: 42},
+		}},
+		Usage: TranscriptUsage{TotalTokens: 42},
+	}
+
+	markdown := RenderTranscriptMarkdown(transcript, 4)
+
+	assert.Contains(t, markdown, "- **Engine**: codex")
+	assert.Contains(t, markdown, "## Turn 1\n\n_42 tokens_")
+	assert.Contains(t, markdown, "> Think\n> harder")
+	assert.Contains(t, markdown, "**Assistant**\n\nAnswer")
+	assert.Contains(t, markdown, "**Tool** `bash` (error)")
+	assert.Contains(t, markdown, "\"command\": \"ls\"")
+	assert.Contains(t, markdown, "0123\n... (6 more characters)")
+}
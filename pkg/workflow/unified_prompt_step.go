@@ -13,6 +13,8 @@ var unifiedPromptLog = logger.New("workflow:unified_prompt_step")
 
 // PromptSection represents a section of prompt text to be appended
 type PromptSection struct {
+	// Name is a short human-readable label used by the prompt report (e.g. "Safe outputs")
+	Name string
 	// Content is the actual prompt text or a reference to a file
 	Content string
 	// IsFile indicates if Content is a filename (true) or inline text (false)
@@ -225,6 +227,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	if !isFeatureEnabled(constants.DisableXPIAPromptFeatureFlag, data) {
 		unifiedPromptLog.Print("Adding XPIA section")
 		sections = append(sections, PromptSection{
+			Name:    "XPIA protection",
 			Content: xpiaPromptFile,
 			IsFile:  true,
 		})
@@ -235,6 +238,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	// 1. Temporary folder instructions (always included)
 	unifiedPromptLog.Print("Adding temp folder section")
 	sections = append(sections, PromptSection{
+		Name:    "Temporary folder",
 		Content: tempFolderPromptFile,
 		IsFile:  true,
 	})
@@ -242,6 +246,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	// 2. Markdown generation instructions (always included)
 	unifiedPromptLog.Print("Adding markdown section")
 	sections = append(sections, PromptSection{
+		Name:    "Markdown formatting",
 		Content: markdownPromptFile,
 		IsFile:  true,
 	})
@@ -250,6 +255,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 	if hasPlaywrightTool(data.ParsedTools) {
 		unifiedPromptLog.Print("Adding playwright section")
 		sections = append(sections, PromptSection{
+			Name:    "Playwright",
 			Content: playwrightPromptFile,
 			IsFile:  true,
 		})
@@ -260,6 +266,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		unifiedPromptLog.Print("Adding trial mode section")
 		trialContent := fmt.Sprintf("## Note\nThis workflow is running in directory $GITHUB_WORKSPACE, but that directory actually contains the contents of the repository '%s'.", c.trialLogicalRepoSlug)
 		sections = append(sections, PromptSection{
+			Name:    "Trial mode",
 			Content: trialContent,
 			IsFile:  false,
 		})
@@ -280,6 +287,7 @@ func (c *Compiler) collectPromptSections(data *WorkflowData) []PromptSection {
 		var repoMemContent strings.Builder
 		generateRepoMemoryPromptSection(&repoMemContent, data.RepoMemoryConfig)
 		sections = append(sections, PromptSection{
+			Name:    "Repo memory",
 			Content: repoMemContent.String(),
 			IsFile:  false,
 		})
@@ -317,6 +325,7 @@ Discover available tools from the safeoutputs MCP server.
 </instructions>
 </safe-outputs>`
 		sections = append(sections, PromptSection{
+			Name:    "Safe outputs",
 			Content: safeOutputsContent,
 			IsFile:  false,
 		})
@@ -339,6 +348,7 @@ Discover available tools from the safeoutputs MCP server.
 			}
 
 			sections = append(sections, PromptSection{
+				Name:    "GitHub context",
 				Content: modifiedPromptText,
 				IsFile:  false,
 				EnvVars: envVars,
@@ -366,6 +376,7 @@ Discover available tools from the safeoutputs MCP server.
 		}
 
 		sections = append(sections, PromptSection{
+			Name:           "PR context",
 			Content:        prContextPromptFile,
 			IsFile:         true,
 			ShellCondition: shellCondition,
@@ -0,0 +1,314 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-aw/pkg/logger"
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/goccy/go-yaml"
+)
+
+var workflowTemplateLog = logger.New("workflow:workflow_template")
+
+// TemplateFrontmatterField marks a workflow as a template. Templates are not compiled directly;
+// they are instantiated into concrete workflows with 'gh aw instantiate'.
+const TemplateFrontmatterField = "template"
+
+// TemplateSourceFrontmatterField records the template (and input values) a workflow was instantiated from
+const TemplateSourceFrontmatterField = "template-source"
+
+// WorkflowTemplate is a parameterized workflow. Its frontmatter and markdown reference
+// inputs with ${{ github.aw.inputs.<name> }}, the same syntax used by imports with inputs.
+//
+// Example:
+//
+//	---
+//	template:
+//	  description: Issue triage for a team
+//	  inputs:
+//	    team:
+//	      type: string
+//	      required: true
+//	on:
+//	  issues:
+//	    types: [opened]
+//	---
+//	Triage issues for the ${{ github.aw.inputs.team }} team.
+type WorkflowTemplate struct {
+	Description string
+	Inputs      map[string]*InputDefinition
+	content     string
+}
+
+// TemplateSource is the template-source frontmatter of an instantiated workflow
+type TemplateSource struct {
+	Path   string         `yaml:"path" json:"path"`
+	Inputs map[string]any `yaml:"inputs,omitempty" json:"inputs,omitempty"`
+}
+
+// TemplateWorkflowError is returned when parsing a workflow template for compilation.
+// Like shared workflows, templates are skipped by compile.
+type TemplateWorkflowError struct {
+	Path string
+}
+
+// Error returns an info message explaining how to use the template
+func (e *TemplateWorkflowError) Error() string {
+	return fmt.Sprintf("%s is a workflow template and is not compiled directly. Use 'gh aw instantiate' to generate workflows from it.", e.Path)
+}
+
+// IsWorkflowTemplate reports whether the frontmatter declares a workflow template
+func IsWorkflowTemplate(frontmatter map[string]any) bool {
+	_, ok := frontmatter[TemplateFrontmatterField]
+	return ok
+}
+
+// ParseWorkflowTemplate parses a workflow template from markdown content
+func ParseWorkflowTemplate(content string) (*WorkflowTemplate, error) {
+	result, err := parser.ExtractFrontmatterFromContent(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template frontmatter: %w", err)
+	}
+
+	raw, ok := result.Frontmatter[TemplateFrontmatterField]
+	if !ok {
+		return nil, fmt.Errorf("not a workflow template: missing '%s' field", TemplateFrontmatterField)
+	}
+
+	tmpl := &WorkflowTemplate{Inputs: map[string]*InputDefinition{}, content: content}
+	if raw != nil {
+		config, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("'%s' must be an object", TemplateFrontmatterField)
+		}
+		if description, ok := config["description"].(string); ok {
+			tmpl.Description = description
+		}
+		if inputs, exists := config["inputs"]; exists {
+			inputsMap, ok := inputs.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("'%s.inputs' must be an object", TemplateFrontmatterField)
+			}
+			for name, value := range inputsMap {
+				if _, ok := value.(map[string]any); !ok {
+					return nil, fmt.Errorf("template input '%s' must be an object", name)
+				}
+			}
+			tmpl.Inputs = ParseInputDefinitions(inputsMap)
+		}
+	}
+
+	for name, input := range tmpl.Inputs {
+		switch input.Type {
+		case "", "string", "number", "boolean":
+		case "choice":
+			if len(input.Options) == 0 {
+				return nil, fmt.Errorf("template input '%s' of type choice must define options", name)
+			}
+		default:
+			return nil, fmt.Errorf("template input '%s' has unsupported type '%s' (must be string, number, boolean or choice)", name, input.Type)
+		}
+		if input.Default != nil {
+			if _, err := coerceTemplateInput(name, input, input.Default); err != nil {
+				return nil, fmt.Errorf("invalid default: %w", err)
+			}
+		}
+	}
+
+	// Every referenced input must be declared so instantiation never leaves expressions behind
+	for _, match := range awInputsExprRegex.FindAllStringSubmatch(content, -1) {
+		if _, ok := tmpl.Inputs[match[1]]; !ok {
+			return nil, fmt.Errorf("template references undeclared input '%s'", match[1])
+		}
+	}
+
+	workflowTemplateLog.Printf("Parsed workflow template with %d inputs", len(tmpl.Inputs))
+	return tmpl, nil
+}
+
+// ResolveInputs validates the provided values against the template inputs and applies defaults.
+// String values (from --set) are converted to the declared input type.
+func (t *WorkflowTemplate) ResolveInputs(values map[string]any) (map[string]any, error) {
+	for name := range values {
+		if _, ok := t.Inputs[name]; !ok {
+			return nil, fmt.Errorf("unknown template input '%s' (available: %s)", name, strings.Join(t.InputNames(), ", "))
+		}
+	}
+
+	resolved := make(map[string]any, len(t.Inputs))
+	for _, name := range t.InputNames() {
+		input := t.Inputs[name]
+		value, provided := values[name]
+		if !provided {
+			if input.Default != nil {
+				value = input.Default
+			} else if input.Required || input.Type == "choice" {
+				return nil, fmt.Errorf("missing required template input '%s'", name)
+			} else {
+				resolved[name] = zeroTemplateInput(input)
+				continue
+			}
+		}
+		coerced, err := coerceTemplateInput(name, input, value)
+		if err != nil {
+			return nil, err
+		}
+		resolved[name] = coerced
+	}
+	return resolved, nil
+}
+
+// InputNames returns the sorted names of the template inputs
+func (t *WorkflowTemplate) InputNames() []string {
+	names := make([]string, 0, len(t.Inputs))
+	for name := range t.Inputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Render instantiates the template with resolved input values. The template field is replaced by a
+// template-source field recording the template path and the inputs, so the workflow can be re-rendered.
+func (t *WorkflowTemplate) Render(inputs map[string]any, templatePath string) (string, error) {
+	workflowTemplateLog.Printf("Rendering template %s with %d inputs", templatePath, len(inputs))
+
+	result, err := parser.ExtractFrontmatterFromContent(t.content)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template frontmatter: %w", err)
+	}
+
+	frontmatterLines := removeTopLevelFrontmatterField(result.FrontmatterLines, TemplateFrontmatterField)
+	frontmatterLines = removeTopLevelFrontmatterField(frontmatterLines, TemplateSourceFrontmatterField)
+
+	sourceYAML, err := yaml.Marshal(map[string]TemplateSource{
+		TemplateSourceFrontmatterField: {Path: templatePath, Inputs: inputs},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal template source: %w", err)
+	}
+
+	// Input values are substituted in the frontmatter and the markdown
+	frontmatter := SubstituteImportInputs(strings.Join(frontmatterLines, "\n"), inputs)
+	markdown := SubstituteImportInputs(result.Markdown, inputs)
+
+	var builder strings.Builder
+	builder.WriteString("---\n")
+	builder.WriteString(string(sourceYAML))
+	if strings.TrimSpace(frontmatter) != "" {
+		builder.WriteString(strings.TrimLeft(frontmatter, "\n"))
+		builder.WriteString("\n")
+	}
+	builder.WriteString("---\n")
+	if markdown != "" {
+		builder.WriteString("\n")
+		builder.WriteString(strings.TrimLeft(markdown, "\n"))
+		if !strings.HasSuffix(markdown, "\n") {
+			builder.WriteString("\n")
+		}
+	}
+
+	rendered := builder.String()
+
+	// The rendered workflow must still be valid YAML (e.g. values that need quoting)
+	if _, err := parser.ExtractFrontmatterFromContent(rendered); err != nil {
+		return "", fmt.Errorf("rendered workflow has invalid frontmatter: %w", err)
+	}
+	return rendered, nil
+}
+
+// ExtractTemplateSource returns the template-source of an instantiated workflow, or nil when absent
+func ExtractTemplateSource(frontmatter map[string]any) (*TemplateSource, error) {
+	raw, ok := frontmatter[TemplateSourceFrontmatterField]
+	if !ok {
+		return nil, nil
+	}
+	config, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("'%s' must be an object", TemplateSourceFrontmatterField)
+	}
+	path, _ := config["path"].(string)
+	if path == "" {
+		return nil, fmt.Errorf("'%s.path' is required", TemplateSourceFrontmatterField)
+	}
+	source := &TemplateSource{Path: path}
+	if inputs, ok := config["inputs"].(map[string]any); ok {
+		source.Inputs = inputs
+	}
+	return source, nil
+}
+
+// coerceTemplateInput converts a value to the declared input type
+func coerceTemplateInput(name string, input *InputDefinition, value any) (any, error) {
+	str, isString := value.(string)
+	switch input.Type {
+	case "boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+		if isString {
+			if b, err := strconv.ParseBool(str); err == nil {
+				return b, nil
+			}
+		}
+		return nil, fmt.Errorf("template input '%s' must be a boolean, got '%v'", name, value)
+	case "number":
+		switch v := value.(type) {
+		case int, int64, uint64, float64:
+			return v, nil
+		}
+		if isString {
+			if i, err := strconv.ParseInt(str, 10, 64); err == nil {
+				return i, nil
+			}
+			if f, err := strconv.ParseFloat(str, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, fmt.Errorf("template input '%s' must be a number, got '%v'", name, value)
+	case "choice":
+		choice := fmt.Sprintf("%v", value)
+		if !slices.Contains(input.Options, choice) {
+			return nil, fmt.Errorf("template input '%s' must be one of %s, got '%s'", name, strings.Join(input.Options, ", "), choice)
+		}
+		return choice, nil
+	default:
+		if isString {
+			return str, nil
+		}
+		return fmt.Sprintf("%v", value), nil
+	}
+}
+
+// zeroTemplateInput returns the value of an optional input that has no default
+func zeroTemplateInput(input *InputDefinition) any {
+	switch input.Type {
+	case "boolean":
+		return false
+	case "number":
+		return 0
+	default:
+		return ""
+	}
+}
+
+// removeTopLevelFrontmatterField removes a top-level field and its nested lines from raw frontmatter lines
+func removeTopLevelFrontmatterField(lines []string, field string) []string {
+	var result []string
+	skipping := false
+	for _, line := range lines {
+		isTopLevel := line != "" && line[0] != ' ' && line[0] != '\t' && !strings.HasPrefix(line, "#")
+		if isTopLevel {
+			skipping = line == field+":" || strings.HasPrefix(line, field+": ") || strings.HasPrefix(line, field+":\t")
+		}
+		if skipping {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
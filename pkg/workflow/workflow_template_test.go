@@ -0,0 +1,141 @@
+//go:build !integration
+
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-aw/pkg/parser"
+	"github.com/github/gh-aw/pkg/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testWorkflowTemplate = `---
+template:
+  description: Issue triage for a team
+  inputs:
+    team:
+      type: string
+      required: true
+    label:
+      type: choice
+      options: [bug, triage]
+      default: triage
+    max-issues:
+      type: number
+      default: 5
+    dry-run:
+      type: boolean
+on:
+  issues:
+    types: [opened]
+engine: copilot
+safe-outputs:
+  add-labels:
+    allowed:
+      - ${{ github.aw.inputs.label }}
+    max: ${{ github.aw.inputs.max-issues }}
+---
+
+# Triage for ${{ github.aw.inputs.team }}
+
+Dry run: ${{ github.aw.inputs.dry-run }}
+`
+
+func TestParseWorkflowTemplate(t *testing.T) {
+	tmpl, err := ParseWorkflowTemplate(testWorkflowTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, "Issue triage for a team", tmpl.Description)
+	assert.Equal(t, []string{"dry-run", "label", "max-issues", "team"}, tmpl.InputNames())
+
+	tests := []struct {
+		name    string
+		content string
+		errMsg  string
+	}{
+		{"not a template", "---\non: push\n---\n# Hi\n", "missing 'template' field"},
+		{"undeclared input", "---\ntemplate:\n  inputs: {}\non: push\n---\n${{ github.aw.inputs.team }}\n", "undeclared input 'team'"},
+		{"unsupported type", "---\ntemplate:\n  inputs:\n    team:\n      type: list\non: push\n---\n# Hi\n", "unsupported type 'list'"},
+		{"choice without options", "---\ntemplate:\n  inputs:\n    team:\n      type: choice\non: push\n---\n# Hi\n", "must define options"},
+		{"invalid default", "---\ntemplate:\n  inputs:\n    n:\n      type: number\n      default: many\non: push\n---\n# Hi\n", "must be a number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWorkflowTemplate(tt.content)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestWorkflowTemplateResolveInputs(t *testing.T) {
+	tmpl, err := ParseWorkflowTemplate(testWorkflowTemplate)
+	require.NoError(t, err)
+
+	inputs, err := tmpl.ResolveInputs(map[string]any{"team": "platform", "max-issues": "3", "dry-run": "true"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"team": "platform", "label": "triage", "max-issues": int64(3), "dry-run": true}, inputs)
+
+	inputs, err = tmpl.ResolveInputs(map[string]any{"team": "docs"})
+	require.NoError(t, err)
+	assert.Equal(t, false, inputs["dry-run"], "optional boolean without default should be false")
+
+	_, err = tmpl.ResolveInputs(map[string]any{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "missing required template input 'team'")
+
+	_, err = tmpl.ResolveInputs(map[string]any{"team": "docs", "label": "feature"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be one of bug, triage")
+
+	_, err = tmpl.ResolveInputs(map[string]any{"team": "docs", "owner": "me"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown template input 'owner'")
+}
+
+func TestWorkflowTemplateRender(t *testing.T) {
+	tmpl, err := ParseWorkflowTemplate(testWorkflowTemplate)
+	require.NoError(t, err)
+	inputs, err := tmpl.ResolveInputs(map[string]any{"team": "platform", "label": "bug"})
+	require.NoError(t, err)
+
+	rendered, err := tmpl.Render(inputs, ".github/templates/triage.md")
+	require.NoError(t, err)
+	assert.NotContains(t, rendered, "github.aw.inputs")
+	assert.Contains(t, rendered, "# Triage for platform")
+	assert.Contains(t, rendered, "- bug")
+	assert.Contains(t, rendered, "max: 5")
+
+	result, err := parser.ExtractFrontmatterFromContent(rendered)
+	require.NoError(t, err)
+	assert.False(t, IsWorkflowTemplate(result.Frontmatter), "template field should be removed")
+
+	source, err := ExtractTemplateSource(result.Frontmatter)
+	require.NoError(t, err)
+	require.NotNil(t, source)
+	assert.Equal(t, ".github/templates/triage.md", source.Path)
+	assert.Equal(t, "platform", source.Inputs["team"])
+	assert.Equal(t, "bug", source.Inputs["label"])
+
+	// Re-rendering from the recorded inputs is stable
+	again, err := tmpl.ResolveInputs(source.Inputs)
+	require.NoError(t, err)
+	reRendered, err := tmpl.Render(again, source.Path)
+	require.NoError(t, err)
+	assert.Equal(t, rendered, reRendered)
+}
+
+func TestCompileSkipsWorkflowTemplate(t *testing.T) {
+	tmpDir := testutil.TempDir(t, "workflow-template")
+	templatePath := filepath.Join(tmpDir, "triage.md")
+	require.NoError(t, os.WriteFile(templatePath, []byte(testWorkflowTemplate), 0644))
+
+	_, err := NewCompiler().ParseWorkflowFile(templatePath)
+	require.Error(t, err)
+	var templateErr *TemplateWorkflowError
+	require.ErrorAs(t, err, &templateErr)
+	assert.Equal(t, templatePath, templateErr.Path)
+}